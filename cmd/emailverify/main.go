@@ -0,0 +1,102 @@
+// Command emailverify validates email addresses from the command line, with the same
+// checks as the HTTP service.
+//
+// Usage:
+//
+//	emailverify [flags] [address ...]
+//
+// Addresses given as arguments are validated and written in order. Without arguments,
+// every line of standard input is read and validated as one batch. With -pipe, standard
+// input is read as an unbounded stream instead: each address is validated and its result
+// written as soon as its line arrives, until end of input or an interrupt.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"emailvalidator/internal/cli"
+	"emailvalidator/internal/service"
+)
+
+// exitInterrupted is the exit status after an interrupt, as shells report for SIGINT
+const exitInterrupted = 130
+
+func main() {
+	pipe := flag.Bool("pipe", false, "Read addresses from standard input line by line and write each result as soon as it is validated, until end of input")
+	formatFlag := flag.String("format", cli.FormatJSON, "Output format: json (one object per line) or text (address, status, score and reason code separated by tabs)")
+	validationTimeout := flag.Duration("validation-timeout", 30*time.Second, "Deadline for validating one address; 0 waits for every check")
+	flag.Parse()
+	log.SetPrefix("emailverify: ")
+	log.SetFlags(0)
+
+	format, err := cli.ParseFormat(*formatFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if *pipe && flag.NArg() > 0 {
+		log.Fatalf("Invalid configuration: -pipe reads addresses from standard input, not arguments")
+	}
+
+	emailService, err := service.NewEmailService()
+	if err != nil {
+		log.Fatalf("Failed to initialize email service: %v", err)
+	}
+	emailService.SetValidationTimeout(*validationTimeout)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *pipe {
+		err = cli.Pipe(ctx, os.Stdin, os.Stdout, emailService, format)
+	} else {
+		err = validateBatch(ctx, emailService, flag.Args(), format)
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		stop()
+		os.Exit(exitInterrupted)
+	case err != nil:
+		log.Fatal(err)
+	}
+}
+
+// validateBatch validates emails, or every line of standard input when there are none,
+// as one batch and writes the results in order. When ctx is cancelled, the batch is
+// abandoned and ctx's error returned instead of partial results.
+func validateBatch(ctx context.Context, emailService *service.EmailService, emails []string, format string) error {
+	if len(emails) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), cli.MaxLineLength)
+		for scanner.Scan() {
+			if email := strings.TrimSpace(scanner.Text()); email != "" && !strings.HasPrefix(email, "#") {
+				emails = append(emails, email)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+	}
+
+	response := emailService.ValidateEmailsContext(ctx, emails, service.ValidationOptions{})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	for _, result := range response.Results {
+		if err := cli.WriteResult(w, result, format); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
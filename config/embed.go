@@ -0,0 +1,10 @@
+// Package config embeds the bundled data files, so a binary built from this module runs
+// without the config directory next to it.
+package config
+
+import "embed"
+
+// Files holds the bundled disposable domain and TLD lists
+//
+//go:embed disposable_domains.txt tlds.txt
+var Files embed.FS
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/utils"
+)
+
+// accessLogKey is the request context key of the *accessLogEntry being built
+type accessLogKey struct{}
+
+// accessLogEntry collects the attributes handlers add to the access log line of a request
+type accessLogEntry struct {
+	attrs []slog.Attr
+}
+
+// AccessLogMiddleware writes one structured log line per request to logger, with the
+// method, path, status, latency and client IP, plus the validation outcome recorded by
+// the handler. Query strings are never logged, and addresses are masked, so the log holds
+// no full email addresses. The client IP is the peer address; X-Forwarded-For is ignored,
+// see AccessLogMiddlewareWithProxies.
+func AccessLogMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return AccessLogMiddlewareWithProxies(logger, nil, next)
+}
+
+// AccessLogMiddlewareWithProxies is AccessLogMiddleware behind reverse proxies. When the
+// peer is within trustedProxies, the client IP is taken from X-Forwarded-For: the nearest
+// hop not itself a trusted proxy. Any other peer could forge the header, so its own
+// address is logged.
+func AccessLogMiddlewareWithProxies(logger *slog.Logger, trustedProxies []netip.Prefix, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &accessLogEntry{}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		// Deferred so a panicking handler still leaves a record of the request
+		defer func() {
+			status := rec.status
+			recovered := recover()
+			if recovered != nil {
+				status = http.StatusInternalServerError
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("client_ip", clientIP(r, trustedProxies)),
+			}
+			attrs = append(attrs, entry.attrs...)
+			logger.LogAttrs(r.Context(), slog.LevelInfo, "request", attrs...)
+
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), accessLogKey{}, entry)))
+	})
+}
+
+// logOutcome adds the outcome of a single-email validation to the request's access log line
+func logOutcome(r *http.Request, result model.EmailValidationResponse) {
+	addAccessLogAttrs(r,
+		slog.String("email", utils.MaskEmail(result.Email)),
+		slog.String("outcome", string(result.Status)),
+		slog.String("reason_code", string(result.ReasonCode)),
+	)
+}
+
+// logBatchOutcome adds the outcome of a batch validation to the request's access log line
+func logBatchOutcome(r *http.Request, response model.BatchValidationResponse) {
+	addAccessLogAttrs(r,
+		slog.Int("batch_size", len(response.Results)),
+		slog.Int("batch_errors", response.Errors),
+		slog.Int("batch_not_processed", response.NotProcessed),
+	)
+}
+
+// addAccessLogAttrs adds attrs to the request's access log line. It does nothing when
+// access logging is disabled.
+func addAccessLogAttrs(r *http.Request, attrs ...slog.Attr) {
+	if entry, ok := r.Context().Value(accessLogKey{}).(*accessLogEntry); ok {
+		entry.attrs = append(entry.attrs, attrs...)
+	}
+}
+
+// ParseTrustedProxies parses CIDR ranges, or single addresses, of trusted reverse proxies.
+// Blank entries are skipped.
+func ParseTrustedProxies(entries []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// clientIP returns the address of the client. X-Forwarded-For is only consulted when the
+// peer is a trusted proxy, and is walked from the nearest hop back, past the trusted
+// proxies, so a client cannot choose the address logged by prepending its own hops.
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		host = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	return host
+}
+
+// isTrustedProxy reports whether ip is within one of trustedProxies
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusRecorder wraps http.ResponseWriter to capture the status code
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// BlocklistRefreshHandler serves the admin endpoint that forces the disposable blocklist to
+// be re-fetched, e.g. after a spam wave. Every request must carry the configured bearer token.
+type BlocklistRefreshHandler struct {
+	disposableBlocklist *validator.DisposableBlocklist
+	token               string
+}
+
+// NewBlocklistRefreshHandler creates a new BlocklistRefreshHandler that accepts requests
+// authorized with token
+func NewBlocklistRefreshHandler(dbl *validator.DisposableBlocklist, token string) *BlocklistRefreshHandler {
+	return &BlocklistRefreshHandler{
+		disposableBlocklist: dbl,
+		token:               token,
+	}
+}
+
+// ServeHTTP refreshes the blocklist and responds with its new size and load time. Concurrent
+// requests share a single refresh.
+func (h *BlocklistRefreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !hasBearerToken(r, h.token) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+		sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	stats, err := h.disposableBlocklist.Refresh(r.Context())
+	if err != nil {
+		log.Printf("Error refreshing disposable blocklist: %v", err)
+		sendError(w, http.StatusBadGateway, "Failed to refresh blocklist: "+err.Error())
+		return
+	}
+	log.Printf("Disposable blocklist refreshed on request: %d domains", stats.Domains)
+
+	w.Header().Set("Content-Type", "application/json")
+	response := model.BlocklistRefreshResponse{Domains: stats.Domains, LoadedAt: stats.LoadedAt}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// BlocklistExportHandler serves the admin endpoint that streams what the service treats as
+// disposable, so operators can audit it or seed other systems with it. Every request must
+// carry the configured bearer token.
+type BlocklistExportHandler struct {
+	emailValidator      *validator.EmailValidator
+	disposableBlocklist *validator.DisposableBlocklist
+	token               string
+}
+
+// NewBlocklistExportHandler creates a new BlocklistExportHandler exporting the local
+// disposable list of emailValidator and dbl, that accepts requests authorized with token
+func NewBlocklistExportHandler(emailValidator *validator.EmailValidator, dbl *validator.DisposableBlocklist, token string) *BlocklistExportHandler {
+	return &BlocklistExportHandler{
+		emailValidator:      emailValidator,
+		disposableBlocklist: dbl,
+		token:               token,
+	}
+}
+
+// ServeHTTP streams every entry of the local disposable list and the remote blocklist,
+// merged and sorted, without those the allowlist overrides. The "format" query parameter
+// selects "text", the default, with one entry per line, or "json", an array of entries.
+// With "sources=true", each entry also lists where it comes from, "local" or the URLs of
+// remote sources, and the local patterns follow with the source "pattern": after a tab in
+// text, and as objects with "entry" and "sources" members in JSON. Either format without
+// sources can be read back as a list source.
+func (h *BlocklistExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !hasBearerToken(r, h.token) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+		sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	if r.Method != http.MethodGet {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = "text"
+	case "text", "json":
+	default:
+		sendError(w, http.StatusBadRequest, fmt.Sprintf("invalid format %q: must be %q or %q", format, "text", "json"))
+		return
+	}
+	withSources := r.URL.Query().Get("sources") == "true"
+
+	// The list stays ready once it has loaded, so Export below sees it ready too
+	if !h.disposableBlocklist.IsReady() {
+		sendError(w, http.StatusServiceUnavailable, "Blocklist has not loaded yet")
+		return
+	}
+	stats := h.disposableBlocklist.Stats()
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.Header().Set("Last-Modified", stats.LoadedAt.UTC().Format(http.TimeFormat))
+
+	// The response is written as entries come, so it cannot be replaced by an error once
+	// started; a failed write means the client went away, and a done context that the
+	// client left or the request timed out
+	ctx := r.Context()
+	buf := bufio.NewWriter(w)
+	var err error
+	if format == "json" {
+		err = h.writeJSON(ctx, buf, withSources)
+	} else {
+		_, err = h.emailValidator.ExportDisposable(h.disposableBlocklist, withSources, func(entry validator.BlocklistEntry) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			line := entry.Entry
+			if withSources {
+				line += "\t" + strings.Join(entry.Sources, " ")
+			}
+			_, err := buf.WriteString(line + "\n")
+			return err
+		})
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		log.Printf("Error exporting disposable blocklist: %v", err)
+	}
+}
+
+// writeJSON writes the export as a JSON array, one entry at a time, until ctx is done
+func (h *BlocklistExportHandler) writeJSON(ctx context.Context, w *bufio.Writer, withSources bool) error {
+	if _, err := w.WriteString("["); err != nil {
+		return err
+	}
+	first := true
+	_, err := h.emailValidator.ExportDisposable(h.disposableBlocklist, withSources, func(entry validator.BlocklistEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var item any = entry.Entry
+		if withSources {
+			item = model.BlocklistExportEntry{Entry: entry.Entry, Sources: entry.Sources}
+		}
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if !first {
+			w.WriteString(",")
+		}
+		first = false
+		_, err = w.Write(encoded)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.WriteString("]\n")
+	return err
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// hasBearerToken reports whether the request carries want as its bearer token.
+// An empty want rejects every request.
+func hasBearerToken(r *http.Request, want string) bool {
+	if want == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+)
+
+// csvSummaryMarker starts the summary row of a CSV batch response. List parsers skip rows
+// starting with "#", so the CSV can be fed back in as a source list.
+const csvSummaryMarker = "#summary"
+
+// csvHeader is the header row of a CSV batch response
+var csvHeader = []string{"index", "email", "status", "score", "reason_code", "reason", "canonical", "typo_suggestion", "error"}
+
+// wantsCSV reports whether a batch request asked for CSV, with the "format" query
+// parameter or, without one, an Accept header of text/csv
+func wantsCSV(r *http.Request) (bool, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "csv":
+		return true, nil
+	case "json":
+		return false, nil
+	case "":
+	default:
+		return false, fmt.Errorf("invalid format %q: must be %q or %q", format, "json", "csv")
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted)); err == nil && mediaType == "text/csv" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// writeBatchCSV writes response as CSV, one row per result in request order. Results that
+// could not be validated carry the failure in the "error" column. With summary, a final
+// row starting with csvSummaryMarker holds the total and the number of results per status.
+func writeBatchCSV(w http.ResponseWriter, response model.BatchValidationResponse, scale service.ScoreScale, summary bool) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	counts := make(map[model.ValidationStatus]int)
+	for i, result := range response.Results {
+		counts[result.Status]++
+		index := i
+		if result.Index != nil {
+			index = *result.Index
+		}
+		row := []string{
+			strconv.Itoa(index),
+			result.Email,
+			string(result.Status),
+			fmt.Sprint(scale.Format(result.Score)),
+			string(result.ReasonCode),
+			result.Reason,
+			result.Canonical,
+			result.TypoSuggestion,
+			result.Error,
+		}
+		for j := range row {
+			row[j] = escapeCSVFormula(row[j])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	if summary {
+		if err := writer.Write(csvSummaryRow(len(response.Results), response.Errors, counts)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSummaryRow returns the summary row: the marker, the total, each status with its count
+// in alphabetical order, and the number of errors
+func csvSummaryRow(total, errors int, counts map[model.ValidationStatus]int) []string {
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+
+	row := []string{csvSummaryMarker, "total=" + strconv.Itoa(total)}
+	for _, status := range statuses {
+		row = append(row, status+"="+strconv.Itoa(counts[model.ValidationStatus(status)]))
+	}
+	return append(row, "errors="+strconv.Itoa(errors))
+}
+
+// escapeCSVFormula prefixes a value that a spreadsheet would run as a formula, such as
+// "=HYPERLINK(...)@example.com", with a single quote so it is shown as text instead
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
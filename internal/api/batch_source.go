@@ -0,0 +1,208 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"emailvalidator/pkg/validator"
+)
+
+// DefaultMaxSourceBytes is the largest email list fetched from a source_url unless configured otherwise
+const DefaultMaxSourceBytes = 10 << 20
+
+// errSourceTooLarge is returned by fetchEmailList for a list larger than the configured limit
+var errSourceTooLarge = errors.New("email list exceeds the maximum size")
+
+// errSourceNotAllowed is returned by fetchEmailList for a source_url, or a redirect, to a
+// host lists may not be fetched from
+var errSourceNotAllowed = errors.New("source_url host is not allowed")
+
+// maxSourceRedirects is the number of redirects followed when fetching a list, as for http.Client
+const maxSourceRedirects = 10
+
+// sourceHostPolicy decides which hosts batch lists may be fetched from
+type sourceHostPolicy struct {
+	anyHost bool
+	hosts   *validator.DomainMatcher
+}
+
+// newSourceHostPolicy allows hosts, which may be "*.example.com" wildcards; "*" allows any host
+func newSourceHostPolicy(hosts []string) *sourceHostPolicy {
+	policy := &sourceHostPolicy{}
+	var entries []string
+	for _, host := range hosts {
+		if host = strings.TrimSpace(host); host == "*" {
+			policy.anyHost = true
+		} else if host != "" {
+			entries = append(entries, host)
+		}
+	}
+	if !policy.anyHost && len(entries) == 0 {
+		return nil
+	}
+	policy.hosts = validator.NewDomainMatcher(entries)
+	return policy
+}
+
+// allows reports whether lists may be fetched from u. A nil policy allows nothing.
+func (p *sourceHostPolicy) allows(u *url.URL) bool {
+	if p == nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return p.anyHost || p.hosts.Contains(u.Hostname())
+}
+
+// newSourceHTTPClient returns a client for fetching source_url lists that cannot reach
+// loopback, private or link-local addresses
+func newSourceHTTPClient() *http.Client {
+	// Without a proxy or CA bundle the config is always valid
+	client, _ := validator.NewHTTPClient(validator.HTTPClientConfig{PublicAddressesOnly: true})
+	return client
+}
+
+// invalidSourceError is returned by fetchEmailList for a source_url the client must fix
+type invalidSourceError struct {
+	message string
+}
+
+func (e *invalidSourceError) Error() string {
+	return e.message
+}
+
+// fetchEmailList downloads the email list at rawURL with client, if policy allows its host
+// and the host of every redirect. The list is either one address per line or CSV, chosen
+// by content type, or by the URL's extension when the server sends a generic type such
+// as S3's application/octet-stream.
+func fetchEmailList(ctx context.Context, client *http.Client, policy *sourceHostPolicy, rawURL string, maxBytes int64) ([]string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, &invalidSourceError{message: fmt.Sprintf("invalid source_url %q: must be an http or https URL", rawURL)}
+	}
+	if !policy.allows(parsed) {
+		return nil, errSourceNotAllowed
+	}
+
+	guarded := *client
+	guarded.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxSourceRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxSourceRedirects)
+		}
+		if !policy.allows(req.URL) {
+			return errSourceNotAllowed
+		}
+		return nil
+	}
+	client = &guarded
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain, text/csv")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch email list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch email list: unexpected status %s", resp.Status)
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, errSourceTooLarge
+	}
+
+	isCSV, err := sourceIsCSV(resp.Header.Get("Content-Type"), parsed.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the limit so an oversized list is detected rather than truncated
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email list: %w", err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, errSourceTooLarge
+	}
+
+	if isCSV {
+		return parseCSVEmails(string(body))
+	}
+	return parseEmailLines(string(body))
+}
+
+// sourceIsCSV reports whether a list with the given content type and URL path is CSV
+func sourceIsCSV(contentType, urlPath string) (bool, error) {
+	mediaType := ""
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return false, fmt.Errorf("email list has an invalid content type %q", contentType)
+		}
+		mediaType = parsed
+	}
+
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return true, nil
+	case "text/plain":
+		return false, nil
+	case "", "application/octet-stream", "binary/octet-stream":
+		return strings.EqualFold(path.Ext(urlPath), ".csv"), nil
+	default:
+		return false, fmt.Errorf("email list has unsupported content type %q: must be text/plain or text/csv", mediaType)
+	}
+}
+
+// parseEmailLines returns the addresses of a list with one address per line, skipping
+// blank lines and lines starting with "#"
+func parseEmailLines(body string) ([]string, error) {
+	var emails []string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		emails = append(emails, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read email list: %w", err)
+	}
+	return emails, nil
+}
+
+// parseCSVEmails returns the first field containing "@" of every CSV row, so the address
+// column may be anywhere and a header row is skipped
+func parseCSVEmails(body string) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(body))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var emails []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return emails, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV email list: %w", err)
+		}
+		for _, field := range record {
+			if field = strings.TrimSpace(field); strings.Contains(field, "@") {
+				emails = append(emails, field)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/monitoring"
+)
+
+// joinResultHeader names the result columns appended to every row of a CSV join
+var joinResultHeader = []string{"status", "score", "reason_code", "reason", "canonical", "typo_suggestion", "error"}
+
+// HandleValidateCSV validates the address column of a CSV list with a header row and
+// answers with the same CSV, every input column passed through untouched and in order,
+// with the result columns appended to each row
+func (h *Handler) HandleValidateCSV(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	monitoring.BatchRequestStarted()
+	defer monitoring.BatchRequestFinished()
+
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSourceBytes)
+	records, err := readCSVRecords(r.Body)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		sendError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("email list exceeds the maximum of %d bytes", h.maxSourceBytes))
+		return
+	case err != nil:
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	case len(records) == 0:
+		sendError(w, http.StatusBadRequest, "The CSV has no header row")
+		return
+	}
+
+	header, rows := records[0], records[1:]
+	column, err := emailColumn(header, r.URL.Query().Get("email_column"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(rows) == 0 {
+		sendError(w, http.StatusBadRequest, "The CSV has no rows below the header")
+		return
+	}
+	if err := h.emailService.CheckBatchSize(len(rows)); err != nil {
+		sendError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	emails := make([]string, len(rows))
+	for i, row := range rows {
+		emails[i] = strings.TrimSpace(row[column])
+	}
+	result := h.emailService.ValidateEmailsContext(r.Context(), emails, opts)
+
+	monitoring.RecordBatch(len(emails), time.Since(start))
+	logBatchOutcome(r, result)
+
+	if err := writeJoinedCSV(w, header, rows, result, h.emailService.ScoreScaleFor(opts)); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// readCSVRecords reads every record of a CSV. Every row must have as many fields as the
+// header, so the appended result columns line up.
+func readCSVRecords(body io.Reader) ([][]string, error) {
+	reader := csv.NewReader(body)
+	records, err := reader.ReadAll()
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	return records, nil
+}
+
+// emailColumn returns the index of the address column in header: the first column named
+// name or, without a name, the first column named "email", or else the first whose name
+// contains "email". Names are compared ignoring case and surrounding spaces.
+func emailColumn(header []string, name string) (int, error) {
+	columns := make([]string, len(header))
+	for i, column := range header {
+		columns[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+
+	if want := strings.ToLower(strings.TrimSpace(name)); want != "" {
+		for i, column := range columns {
+			if column == want {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("the CSV has no column named %q", name)
+	}
+	for i, column := range columns {
+		if column == "email" {
+			return i, nil
+		}
+	}
+	for i, column := range columns {
+		if strings.Contains(column, "email") {
+			return i, nil
+		}
+	}
+	return 0, errors.New(`the CSV has no email column; name it with the "email_column" parameter`)
+}
+
+// writeJoinedCSV writes header and rows as they were received, each row followed by the
+// result columns of its address. Result column names that clash with an input column get
+// a numeric suffix, so every column name in the output is distinct from the input's.
+func writeJoinedCSV(w http.ResponseWriter, header []string, rows [][]string, response model.BatchValidationResponse, scale service.ScoreScale) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append(append([]string{}, header...), uniqueColumnNames(header, joinResultHeader)...)); err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		result := response.Results[i]
+		columns := []string{
+			string(result.Status),
+			fmt.Sprint(scale.Format(result.Score)),
+			string(result.ReasonCode),
+			result.Reason,
+			result.Canonical,
+			result.TypoSuggestion,
+			result.Error,
+		}
+		// Input columns are the client's own data and pass through as is; only values
+		// derived from the address are escaped
+		for j := range columns {
+			columns[j] = escapeCSVFormula(columns[j])
+		}
+		if err := writer.Write(append(append([]string{}, row...), columns...)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// uniqueColumnNames returns names, with "_2", "_3" and so on appended to any name already
+// among existing, ignoring case
+func uniqueColumnNames(existing, names []string) []string {
+	taken := make(map[string]bool, len(existing)+len(names))
+	for _, name := range existing {
+		taken[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	unique := make([]string, len(names))
+	for i, name := range names {
+		candidate := name
+		for n := 2; taken[strings.ToLower(candidate)]; n++ {
+			candidate = name + "_" + strconv.Itoa(n)
+		}
+		taken[strings.ToLower(candidate)] = true
+		unique[i] = candidate
+	}
+	return unique
+}
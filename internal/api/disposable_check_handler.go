@@ -8,20 +8,20 @@ import (
 	"strings"
 	"time"
 
-	"email-validator/internal/model"
-	"email-validator/internal/service"
-	"email-validator/pkg/monitoring"
-	"email-validator/pkg/validator"
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/monitoring"
+	"emailvalidator/pkg/validator"
 )
 
 // DisposableCheckHandler handles requests to check an email for disposability after initial validation.
 type DisposableCheckHandler struct {
-	emailService        service.EmailService
+	emailService        *service.EmailService
 	disposableBlocklist *validator.DisposableBlocklist
 }
 
 // NewDisposableCheckHandler creates a new DisposableCheckHandler.
-func NewDisposableCheckHandler(es service.EmailService, dbl *validator.DisposableBlocklist) *DisposableCheckHandler {
+func NewDisposableCheckHandler(es *service.EmailService, dbl *validator.DisposableBlocklist) *DisposableCheckHandler {
 	return &DisposableCheckHandler{
 		emailService:        es,
 		disposableBlocklist: dbl,
@@ -35,7 +35,7 @@ func (h *DisposableCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	status := http.StatusOK             // Default status
 
 	defer func() {
-		monitoring.RecordRequestMetrics(endpoint, status, time.Since(start))
+		monitoring.RecordRequest(endpoint, http.StatusText(status), time.Since(start))
 	}()
 
 	var email string
@@ -61,27 +61,52 @@ func (h *DisposableCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// First, perform the standard email validation using the existing service
-	validationResult, err := h.emailService.ValidateEmail(r.Context(), email)
+	opts, err := parseValidationOptions(r)
 	if err != nil {
-		log.Printf("Error validating email %s: %v", email, err)
-		status = http.StatusInternalServerError
-		http.Error(w, "Internal server error during initial validation", status)
+		status = http.StatusBadRequest
+		http.Error(w, err.Error(), status)
+		return
+	}
+	fields, err := parseFields(r)
+	if err != nil {
+		status = http.StatusBadRequest
+		http.Error(w, err.Error(), status)
 		return
 	}
+	schema, err := parseSchemaVersion(r, fields)
+	if err != nil {
+		status = http.StatusBadRequest
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// First, perform the standard email validation using the existing service
+	validationResult := h.emailService.ValidateEmailWithOptions(email, opts)
 
 	// If the initial validation is VALID, perform the disposable check
 	if validationResult.Status == model.ValidationStatusValid {
 		domain := extractDomain(email)
-		if domain != "" && h.disposableBlocklist.IsDisposable(domain) {
-			validationResult.Validations.IsDisposable = true
-			validationResult.Status = model.ValidationStatusDisposable
-			// You might want to adjust the score here as well, depending on your scoring logic.
+		disposable, ready := h.disposableBlocklist.Lookup(domain)
+		if !ready {
+			// Still loading in the background: answer now, reporting the check as unknown
+			log.Printf("Warning: Disposable blocklist not loaded, skipping check for domain %s", domain)
+			h.emailService.MarkDisposableUnknown(&validationResult, opts)
+		} else if domain != "" && disposable {
+			h.emailService.MarkDisposable(&validationResult, opts)
 		}
 	}
+	logOutcome(r, validationResult)
 
+	body, err := schema.project(validationResult, fields, h.emailService.ScoreScaleFor(opts))
+	if err != nil {
+		status = http.StatusInternalServerError
+		http.Error(w, "Internal server error encoding response", status)
+		return
+	}
+
+	schema.setHeaders(w)
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(validationResult); err != nil {
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		log.Printf("Error encoding response for email %s: %v", email, err)
 		// Note: If an error occurs here, the deferred metric recording might not capture the correct status.
 		// For robust error handling, consider a custom http.ResponseWriter wrapper.
@@ -89,6 +114,75 @@ func (h *DisposableCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// ServeBatch handles bulk disposable checks: a POST of domains or addresses, each checked
+// by its domain, answered with one result per item in request order. Only the disposable
+// lists and heuristics are consulted, with no DNS lookups.
+func (h *DisposableCheckHandler) ServeBatch(w http.ResponseWriter, r *http.Request) {
+	monitoring.BatchRequestStarted()
+	defer monitoring.BatchRequestFinished()
+
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req model.DisposableBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len(req.Domains) == 0 {
+		sendError(w, http.StatusBadRequest, "At least one domain is required")
+		return
+	}
+	if err := h.emailService.CheckBatchSize(len(req.Domains)); err != nil {
+		sendError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	// A nil blocklist must reach the service as a nil interface, not a typed nil
+	var lookup service.DisposableLookup
+	if h.disposableBlocklist != nil {
+		lookup = h.disposableBlocklist
+	}
+	result := h.emailService.CheckDisposableBatch(req.Domains, lookup)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// ServeSource explains why a domain is or is not flagged disposable: GET with a domain
+// parameter, a domain or an address, answered with the matching list entries and the
+// sources listing them, the allowlist entry and the heuristic evidence
+func (h *DisposableCheckHandler) ServeSource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	domain := r.URL.Query().Get("domain")
+	if domain == "" {
+		sendError(w, http.StatusBadRequest, "Domain parameter is required")
+		return
+	}
+
+	var lookup service.DisposableLookup
+	if h.disposableBlocklist != nil {
+		lookup = h.disposableBlocklist
+	}
+	result, err := h.emailService.DisposableSources(domain, lookup)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
 // extractDomain extracts the domain from an email address.
 func extractDomain(email string) string {
 	parts := strings.Split(email, "@")
@@ -96,4 +190,4 @@ func extractDomain(email string) string {
 		return "" // Invalid email format
 	}
 	return parts[1]
-}
\ No newline at end of file
+}
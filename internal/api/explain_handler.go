@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+)
+
+// ExplainHandler serves the verbose validation endpoint used to debug a specific address.
+// It probes expensively and exposes internals, so every request must carry the configured
+// bearer token.
+type ExplainHandler struct {
+	emailService *service.EmailService
+	token        string
+}
+
+// NewExplainHandler creates a new ExplainHandler that accepts requests authorized with token
+func NewExplainHandler(es *service.EmailService, token string) *ExplainHandler {
+	return &ExplainHandler{
+		emailService: es,
+		token:        token,
+	}
+}
+
+// ServeHTTP handles the HTTP requests for verbose email validation
+func (h *ExplainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="explain"`)
+		sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req model.EmailValidationRequest
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Email = r.URL.Query().Get("email")
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	default:
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if req.Email == "" {
+		sendError(w, http.StatusBadRequest, "Email parameter is required")
+		return
+	}
+
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := h.emailService.ExplainEmail(req.Email, opts)
+	logOutcome(r, result.EmailValidationResponse)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// authorized reports whether the request carries the configured bearer token
+func (h *ExplainHandler) authorized(r *http.Request) bool {
+	return hasBearerToken(r, h.token)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+)
+
+var (
+	// responseFields and validationFields are the JSON names that can be requested with "fields"
+	responseFields   = jsonFieldNames(reflect.TypeOf(model.EmailValidationResponse{}))
+	validationFields = jsonFieldNames(reflect.TypeOf(model.ValidationResults{}))
+)
+
+// fieldSelection is the set of result fields requested with the "fields" query parameter.
+// Names are top-level result fields or fields of "validations", which stay nested.
+// A nil selection means the full result.
+type fieldSelection map[string]bool
+
+// parseFields reads the comma-separated "fields" query parameter
+func parseFields(r *http.Request) (fieldSelection, error) {
+	value := r.URL.Query().Get("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	selection := make(fieldSelection)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !responseFields[name] && !validationFields[name] {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		selection[name] = true
+	}
+	if len(selection) == 0 {
+		return nil, nil
+	}
+	return selection, nil
+}
+
+// project returns result reduced to the selected fields, with its score on scale
+func (f fieldSelection) project(result model.EmailValidationResponse, scale service.ScoreScale) (interface{}, error) {
+	if f == nil && scale == service.ScoreScalePercent {
+		return result, nil
+	}
+
+	full, err := toMap(result)
+	if err != nil {
+		return nil, err
+	}
+	full["score"] = scale.Format(result.Score)
+	return f.projectMap(full), nil
+}
+
+// projectBatch returns response with every result reduced to the selected fields, with
+// its score on scale
+func (f fieldSelection) projectBatch(response model.BatchValidationResponse, scale service.ScoreScale) (interface{}, error) {
+	if f == nil && scale == service.ScoreScalePercent {
+		return response, nil
+	}
+
+	full, err := toMap(response)
+	if err != nil {
+		return nil, err
+	}
+	results, _ := full["results"].([]interface{})
+	for i, result := range results {
+		if item, ok := result.(map[string]interface{}); ok {
+			item["score"] = scale.Format(response.Results[i].Score)
+			if correction, ok := item["correction"].(map[string]interface{}); ok && response.Results[i].Correction.Score != nil {
+				correction["score"] = scale.Format(*response.Results[i].Correction.Score)
+			}
+			results[i] = f.projectMap(item)
+		}
+	}
+	return full, nil
+}
+
+// projectMap returns the selected fields of full, or full itself when nothing was selected
+func (f fieldSelection) projectMap(full map[string]interface{}) map[string]interface{} {
+	if f == nil {
+		return full
+	}
+	projected := make(map[string]interface{}, len(f))
+	for key, value := range full {
+		if f[key] {
+			projected[key] = value
+		}
+	}
+
+	if validations, ok := full["validations"].(map[string]interface{}); ok && !f["validations"] {
+		selected := make(map[string]interface{})
+		for key, value := range validations {
+			if f[key] {
+				selected[key] = value
+			}
+		}
+		if len(selected) > 0 {
+			projected["validations"] = selected
+		}
+	}
+	return projected
+}
+
+// toMap converts v to its generic JSON representation
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// jsonFieldNames returns the JSON names of the struct type's exported fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
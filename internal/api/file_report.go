@@ -0,0 +1,200 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/monitoring"
+)
+
+// multipartOverhead is the room allowed for multipart headers and boundaries on top of
+// the largest accepted list
+const multipartOverhead = 1 << 20
+
+// reportHeader is the header row of the results table in a file report
+var reportHeader = []string{"Email", "Status", "Score", "Reason code", "Reason", "Suggestion", "Canonical", "Error"}
+
+// reportRow is one row of a file report. Bold rows are headings, rendered in bold in XLSX.
+type reportRow struct {
+	cells []string
+	bold  bool
+}
+
+// reportFormat is the file format of a report
+type reportFormat struct {
+	extension   string
+	contentType string
+	write       func(io.Writer, []reportRow) error
+}
+
+// reportFormats are the formats a file report can be requested in with "format"
+var reportFormats = map[string]reportFormat{
+	"csv":  {extension: "csv", contentType: "text/csv; charset=utf-8", write: writeReportCSV},
+	"xlsx": {extension: "xlsx", contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", write: writeXLSX},
+}
+
+// HandleValidateFile validates a list uploaded as multipart form data in the "file" field
+// and answers with a report to download: summary statistics followed by one row per
+// address, as CSV or, with format=xlsx, as a spreadsheet
+func (h *Handler) HandleValidateFile(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	monitoring.BatchRequestStarted()
+	defer monitoring.BatchRequestFinished()
+
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = "csv"
+	}
+	format, ok := reportFormats[name]
+	if !ok {
+		sendError(w, http.StatusBadRequest, fmt.Sprintf("invalid format %q: must be %q or %q", name, "csv", "xlsx"))
+		return
+	}
+
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	emails, err := h.readUploadedList(w, r)
+	var invalid *invalidSourceError
+	switch {
+	case errors.As(err, &invalid):
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, errSourceTooLarge):
+		sendError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("email list exceeds the maximum of %d bytes", h.maxSourceBytes))
+		return
+	case err != nil:
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(emails) == 0 {
+		sendError(w, http.StatusBadRequest, "The uploaded list contains no email addresses")
+		return
+	}
+	if err := h.emailService.CheckBatchSize(len(emails)); err != nil {
+		sendError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	result := h.emailService.ValidateEmailsContext(r.Context(), emails, opts)
+
+	monitoring.RecordBatch(len(emails), time.Since(start))
+	logBatchOutcome(r, result)
+
+	generated := time.Now().UTC()
+	filename := "email-validation-report-" + generated.Format("20060102-150405") + "." + format.extension
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	if err := format.write(w, buildReport(result, h.emailService.ScoreScaleFor(opts), generated)); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// readUploadedList reads the addresses of the list uploaded in the "file" form field. Like
+// a source_url list, it is one address per line or CSV, chosen by the part's content type
+// or, for a generic type, by the file name's extension.
+func (h *Handler) readUploadedList(w http.ResponseWriter, r *http.Request) ([]string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSourceBytes+multipartOverhead)
+	file, header, err := r.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return nil, errSourceTooLarge
+	case err != nil:
+		return nil, &invalidSourceError{message: `a list must be uploaded as multipart/form-data in the "file" field`}
+	}
+	defer file.Close()
+	if header.Size > h.maxSourceBytes {
+		return nil, errSourceTooLarge
+	}
+
+	isCSV, err := sourceIsCSV(header.Header.Get("Content-Type"), header.Filename)
+	if err != nil {
+		return nil, &invalidSourceError{message: err.Error()}
+	}
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded list: %w", err)
+	}
+
+	if isCSV {
+		return parseCSVEmails(string(body))
+	}
+	return parseEmailLines(string(body))
+}
+
+// buildReport lays out a report of response: a title, when it was generated, the total,
+// the number of results per status in alphabetical order and the number of errors, then a
+// blank row and the results table in request order
+func buildReport(response model.BatchValidationResponse, scale service.ScoreScale, generated time.Time) []reportRow {
+	counts := make(map[model.ValidationStatus]int)
+	for _, result := range response.Results {
+		counts[result.Status]++
+	}
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+
+	rows := []reportRow{
+		{cells: []string{"Email validation report"}, bold: true},
+		{cells: []string{"Generated", generated.Format(time.RFC3339)}},
+		{cells: []string{"Total", strconv.Itoa(len(response.Results))}},
+	}
+	for _, status := range statuses {
+		rows = append(rows, reportRow{cells: []string{status, strconv.Itoa(counts[model.ValidationStatus(status)])}})
+	}
+	rows = append(rows,
+		reportRow{cells: []string{"Errors", strconv.Itoa(response.Errors)}},
+		reportRow{},
+		reportRow{cells: reportHeader, bold: true},
+	)
+
+	for _, result := range response.Results {
+		rows = append(rows, reportRow{cells: []string{
+			result.Email,
+			string(result.Status),
+			fmt.Sprint(scale.Format(result.Score)),
+			string(result.ReasonCode),
+			result.Reason,
+			result.TypoSuggestion,
+			result.Canonical,
+			result.Error,
+		}})
+	}
+	return rows
+}
+
+// writeReportCSV writes rows as CSV, escaping values a spreadsheet would run as formulas
+func writeReportCSV(w io.Writer, rows []reportRow) error {
+	writer := csv.NewWriter(w)
+	for _, row := range rows {
+		cells := make([]string, len(row.cells))
+		for i, cell := range row.cells {
+			cells[i] = escapeCSVFormula(cell)
+		}
+		if err := writer.Write(cells); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
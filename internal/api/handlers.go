@@ -4,60 +4,67 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"emailvalidator/internal/model"
 	"emailvalidator/internal/service"
-
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
-)
-
-var (
-	// concurrentBatchRequests tracks the number of batch requests being processed concurrently
-	concurrentBatchRequests = promauto.NewGauge(
-		prometheus.GaugeOpts{
-			Name: "email_validator_concurrent_batch_requests",
-			Help: "Number of batch requests being processed concurrently",
-		},
-	)
-	// batchSize tracks the distribution of batch sizes
-	batchSize = promauto.NewHistogram(
-		prometheus.HistogramOpts{
-			Name:    "email_validator_batch_size",
-			Help:    "Distribution of batch validation request sizes",
-			Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000},
-		},
-	)
-
-	// batchProcessingTime tracks the time taken to process entire batches
-	batchProcessingTime = promauto.NewHistogram(
-		prometheus.HistogramOpts{
-			Name:    "email_validator_batch_processing_seconds",
-			Help:    "Time taken to process entire batch requests",
-			Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 20, 30, 60},
-		},
-	)
+	"emailvalidator/pkg/monitoring"
+	"emailvalidator/pkg/validator"
 )
 
 // Handler handles all HTTP requests
 type Handler struct {
-	emailService *service.EmailService
+	emailService   *service.EmailService
+	httpClient     *http.Client
+	sourceHosts    *sourceHostPolicy
+	maxSourceBytes int64
 }
 
 // NewHandler creates a new instance of Handler
 func NewHandler(emailService *service.EmailService) *Handler {
 	return &Handler{
-		emailService: emailService,
+		emailService:   emailService,
+		httpClient:     newSourceHTTPClient(),
+		maxSourceBytes: DefaultMaxSourceBytes,
 	}
 }
 
+// SetHTTPClient sets the client used to fetch batch lists from a source_url. The default
+// client refuses connections to non-public addresses; a replacement should too, see
+// validator.HTTPClientConfig.PublicAddressesOnly.
+func (h *Handler) SetHTTPClient(client *http.Client) {
+	h.httpClient = client
+}
+
+// SetSourceURLHosts sets the hosts batch lists may be fetched from with a source_url.
+// Entries may be "*.example.com" wildcards, and "*" allows any host. Without hosts,
+// which is the default, source_url is rejected.
+func (h *Handler) SetSourceURLHosts(hosts []string) {
+	h.sourceHosts = newSourceHostPolicy(hosts)
+}
+
+// SetMaxSourceBytes sets the largest email list fetched from a source_url or uploaded
+func (h *Handler) SetMaxSourceBytes(n int64) {
+	h.maxSourceBytes = n
+}
+
 // RegisterRoutes registers all API routes
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/validate", h.HandleValidate)
 	mux.HandleFunc("/validate/batch", h.HandleBatchValidate)
+	mux.HandleFunc("/validate/quick", h.HandleValidateQuick)
+	mux.HandleFunc("/validate/file", h.HandleValidateFile)
+	mux.HandleFunc("/validate/csv", h.HandleValidateCSV)
 	mux.HandleFunc("/typo-suggestions", h.HandleTypoSuggestions)
+	mux.HandleFunc("/typo-suggestions/accept", h.HandleAcceptTypoSuggestion)
+	mux.HandleFunc("/same-mailbox", h.HandleSameMailbox)
+	mux.HandleFunc("/canonicalize/batch", h.HandleCanonicalizeBatch)
+	mux.HandleFunc("/validate-domain", h.HandleValidateDomain)
 	mux.HandleFunc("/status", h.HandleStatus)
 }
 
@@ -71,6 +78,67 @@ func sendError(w http.ResponseWriter, status int, message string) {
 	}
 }
 
+// parseValidationOptions reads per-request overrides from the query string
+func parseValidationOptions(r *http.Request) (service.ValidationOptions, error) {
+	var opts service.ValidationOptions
+	if value := r.URL.Query().Get("disposable_policy"); value != "" {
+		policy, err := service.ParseDisposablePolicy(value)
+		if err != nil {
+			return opts, err
+		}
+		opts.DisposablePolicy = policy
+	}
+	if value := r.URL.Query().Get("unknown_policy"); value != "" {
+		policy, err := service.ParseUnknownPolicy(value)
+		if err != nil {
+			return opts, err
+		}
+		opts.UnknownPolicy = policy
+	}
+	if value := r.URL.Query().Get("syntax_mode"); value != "" {
+		mode, err := validator.ParseSyntaxMode(value)
+		if err != nil {
+			return opts, err
+		}
+		opts.SyntaxMode = mode
+	}
+	if value := r.URL.Query().Get("min_confidence"); value != "" {
+		confidence, err := strconv.ParseFloat(value, 64)
+		if err != nil || confidence < 0 || confidence > 1 {
+			return opts, fmt.Errorf("invalid min_confidence %q: must be a number from 0 to 1", value)
+		}
+		opts.MinSuggestionConfidence = &confidence
+	}
+	if value := r.URL.Query().Get("region"); value != "" {
+		opts.TypoRegions = []string{value}
+	} else if value := r.Header.Get("Accept-Language"); value != "" {
+		opts.TypoRegions = validator.TypoRegionsFromAcceptLanguage(value)
+	}
+	if value := r.URL.Query().Get("scale"); value != "" {
+		scale, err := service.ParseScoreScale(value)
+		if err != nil {
+			return opts, err
+		}
+		opts.ScoreScale = scale
+	}
+	return opts, nil
+}
+
+// parseAutocorrect reads the batch-only autocorrect options from the query string into opts
+func parseAutocorrect(r *http.Request, opts *service.ValidationOptions) error {
+	query := r.URL.Query()
+	opts.Autocorrect = query.Get("autocorrect") == "true"
+	if value := query.Get("autocorrect_confidence"); value != "" {
+		confidence, err := strconv.ParseFloat(value, 64)
+		if err != nil || confidence <= 0 || confidence > 1 {
+			return fmt.Errorf("invalid autocorrect_confidence %q: must be a number above 0 and at most 1", value)
+		}
+		opts.AutocorrectConfidence = confidence
+	}
+	opts.SkipRevalidation = query.Get("revalidate") == "false"
+	return nil
+}
+
 // HandleValidate handles email validation requests
 func (h *Handler) HandleValidate(w http.ResponseWriter, r *http.Request) {
 	var req model.EmailValidationRequest
@@ -93,7 +161,67 @@ func (h *Handler) HandleValidate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := h.emailService.ValidateEmail(req.Email)
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields, err := parseFields(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	schema, err := parseSchemaVersion(r, fields)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.Diagnostics = r.URL.Query().Get("diagnostics") == "true"
+
+	result := h.emailService.ValidateEmailWithOptions(req.Email, opts)
+	logOutcome(r, result)
+
+	body, err := schema.project(result, fields, h.emailService.ScoreScaleFor(opts))
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+		return
+	}
+
+	schema.setHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// HandleValidateQuick handles local-only validation requests, which run no network checks
+func (h *Handler) HandleValidateQuick(w http.ResponseWriter, r *http.Request) {
+	var req model.EmailValidationRequest
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Email = r.URL.Query().Get("email")
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	default:
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if req.Email == "" {
+		sendError(w, http.StatusBadRequest, "Email parameter is required")
+		return
+	}
+
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := h.emailService.ValidateEmailLocalOnly(req.Email, opts)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(result); err != nil {
@@ -105,8 +233,8 @@ func (h *Handler) HandleValidate(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) HandleBatchValidate(w http.ResponseWriter, r *http.Request) {
 	var req model.BatchValidationRequest
 	start := time.Now()
-	concurrentBatchRequests.Inc()
-	defer concurrentBatchRequests.Dec()
+	monitoring.BatchRequestStarted()
+	defer monitoring.BatchRequestFinished()
 
 	switch r.Method {
 	case http.MethodGet:
@@ -126,10 +254,193 @@ func (h *Handler) HandleBatchValidate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := h.emailService.ValidateEmails(req.Emails)
+	if req.SourceURL != "" {
+		if len(req.Emails) > 0 {
+			sendError(w, http.StatusBadRequest, "Provide either emails or source_url, not both")
+			return
+		}
+		emails, err := fetchEmailList(r.Context(), h.httpClient, h.sourceHosts, req.SourceURL, h.maxSourceBytes)
+		var invalid *invalidSourceError
+		switch {
+		case errors.As(err, &invalid):
+			sendError(w, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, errSourceNotAllowed):
+			sendError(w, http.StatusForbidden, "Fetching email lists from this source_url is not allowed")
+			return
+		case errors.Is(err, errSourceTooLarge):
+			sendError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("email list exceeds the maximum of %d bytes", h.maxSourceBytes))
+			return
+		case err != nil:
+			// The upstream error may describe internal services, so it is only logged
+			log.Printf("Error fetching email list from source_url: %v", err)
+			sendError(w, http.StatusBadGateway, "Failed to fetch the email list from source_url")
+			return
+		}
+		req.Emails = emails
+	}
+
+	if err := h.emailService.CheckBatchSize(len(req.Emails)); err != nil {
+		sendError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := parseAutocorrect(r, &opts); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields, err := parseFields(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	asCSV, err := wantsCSV(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if asCSV && fields != nil {
+		sendError(w, http.StatusBadRequest, "fields cannot be combined with CSV output")
+		return
+	}
+	schema, err := parseSchemaVersion(r, fields)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := h.emailService.ValidateEmailsContext(r.Context(), req.Emails, opts)
+
+	monitoring.RecordBatch(len(req.Emails), time.Since(start))
+	logBatchOutcome(r, result)
+
+	if asCSV {
+		if err := writeBatchCSV(w, result, h.emailService.ScoreScaleFor(opts), r.URL.Query().Get("summary") == "true"); err != nil {
+			sendError(w, http.StatusInternalServerError, "Failed to encode response")
+		}
+		return
+	}
+
+	body, err := schema.projectBatch(result, fields, h.emailService.ScoreScaleFor(opts))
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+		return
+	}
+
+	schema.setHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// HandleValidateDomain handles requests to validate a domain without an address
+func (h *Handler) HandleValidateDomain(w http.ResponseWriter, r *http.Request) {
+	var req model.DomainValidationRequest
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Domain = r.URL.Query().Get("domain")
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	default:
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if req.Domain == "" {
+		sendError(w, http.StatusBadRequest, "Domain is required")
+		return
+	}
+
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.emailService.ValidateDomain(r.Context(), req.Domain, opts)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// HandleSameMailbox handles requests to compare two addresses for the same mailbox
+func (h *Handler) HandleSameMailbox(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req model.SameMailboxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.First == "" || req.Second == "" {
+		sendError(w, http.StatusBadRequest, "Both first and second emails are required")
+		return
+	}
+
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.emailService.SameMailbox(req.First, req.Second, opts)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encode response")
+	}
+}
+
+// HandleCanonicalizeBatch handles requests to map a list of addresses to their canonical forms
+func (h *Handler) HandleCanonicalizeBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req model.CanonicalizeBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len(req.Emails) == 0 {
+		sendError(w, http.StatusBadRequest, "At least one email is required")
+		return
+	}
+	if err := h.emailService.CheckBatchSize(len(req.Emails)); err != nil {
+		sendError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	batchSize.Observe(float64(len(req.Emails)))
-	batchProcessingTime.Observe(time.Since(start).Seconds())
+	result := h.emailService.CanonicalizeEmails(req.Emails, opts)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(result); err != nil {
@@ -141,6 +452,12 @@ func (h *Handler) HandleBatchValidate(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) HandleTypoSuggestions(w http.ResponseWriter, r *http.Request) {
 	var req model.TypoSuggestionRequest
 
+	opts, err := parseValidationOptions(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		email := r.URL.Query().Get("email")
@@ -159,7 +476,8 @@ func (h *Handler) HandleTypoSuggestions(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	result := h.emailService.GetTypoSuggestions(req.Email)
+	result := h.emailService.GetTypoSuggestionsWithOptions(req.Email, opts)
+	monitoring.RecordTypoSuggestion(result.TypoSuggestion != "")
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(result); err != nil {
@@ -167,6 +485,32 @@ func (h *Handler) HandleTypoSuggestions(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// HandleAcceptTypoSuggestion records that a user took a typo suggestion, feeding the
+// learned suggestion dictionary
+func (h *Handler) HandleAcceptTypoSuggestion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req model.TypoSuggestionAcceptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Email == "" || req.Suggestion == "" {
+		sendError(w, http.StatusBadRequest, "Email and suggestion are required")
+		return
+	}
+
+	if err := h.emailService.AcceptTypoSuggestion(req.Email, req.Suggestion); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	monitoring.RecordTypoSuggestionAccepted()
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // HandleStatus handles API status requests
 func (h *Handler) HandleStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
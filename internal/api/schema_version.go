@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+)
+
+// schemaVersion is the version of the result schema a response is written in
+type schemaVersion int
+
+// parseSchemaVersion reads the schema version a client asked for from the
+// "schema_version" query parameter or, without one, a schema_version parameter on an
+// application/json media type in the Accept header. It defaults to the current version.
+// Field selection is only available in the current version.
+func parseSchemaVersion(r *http.Request, fields fieldSelection) (schemaVersion, error) {
+	value := r.URL.Query().Get("schema_version")
+	if value == "" {
+		for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+			if err == nil && mediaType == "application/json" && params["schema_version"] != "" {
+				value = params["schema_version"]
+				break
+			}
+		}
+	}
+	if value == "" {
+		return model.SchemaVersion, nil
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version < model.SchemaVersion1 || version > model.SchemaVersion {
+		return 0, fmt.Errorf("invalid schema_version %q: must be from %d to %d", value, model.SchemaVersion1, model.SchemaVersion)
+	}
+	if fields != nil && version != model.SchemaVersion {
+		return 0, fmt.Errorf("fields requires schema_version %d", model.SchemaVersion)
+	}
+	return schemaVersion(version), nil
+}
+
+// setHeaders marks a response written in a version kept only for its deprecation window
+func (v schemaVersion) setHeaders(w http.ResponseWriter) {
+	if v != model.SchemaVersion {
+		w.Header().Set("Deprecation", "true")
+	}
+}
+
+// project returns result in version v, reduced to the selected fields, with its score on scale
+func (v schemaVersion) project(result model.EmailValidationResponse, fields fieldSelection, scale service.ScoreScale) (interface{}, error) {
+	if v == model.SchemaVersion1 {
+		return withScale(resultV1(result), scale, result.Score)
+	}
+	result.SchemaVersion = int(v)
+	return fields.project(result, scale)
+}
+
+// projectBatch returns response in version v, with every result reduced to the selected
+// fields, with its score on scale
+func (v schemaVersion) projectBatch(response model.BatchValidationResponse, fields fieldSelection, scale service.ScoreScale) (interface{}, error) {
+	if v == model.SchemaVersion1 {
+		batch := model.BatchValidationResponseV1{Results: make([]model.EmailValidationResponseV1, len(response.Results))}
+		for i, result := range response.Results {
+			batch.Results[i] = resultV1(result)
+		}
+		if scale == service.ScoreScalePercent {
+			return batch, nil
+		}
+		full, err := toMap(batch)
+		if err != nil {
+			return nil, err
+		}
+		results, _ := full["results"].([]interface{})
+		for i, result := range results {
+			if item, ok := result.(map[string]interface{}); ok {
+				item["score"] = scale.Format(response.Results[i].Score)
+			}
+		}
+		return full, nil
+	}
+	response.SchemaVersion = int(v)
+	return fields.projectBatch(response, scale)
+}
+
+// withScale returns v with its score on scale
+func withScale(v interface{}, scale service.ScoreScale, score int) (interface{}, error) {
+	if scale == service.ScoreScalePercent {
+		return v, nil
+	}
+	full, err := toMap(v)
+	if err != nil {
+		return nil, err
+	}
+	full["score"] = scale.Format(score)
+	return full, nil
+}
+
+// resultV1 converts result to the SchemaVersion1 shape. UNKNOWN_TLD, which version 1
+// predates, is reported as INVALID_DOMAIN; ERROR and NOT_PROCESSED, which have no
+// version 1 equivalent, are kept.
+func resultV1(result model.EmailValidationResponse) model.EmailValidationResponseV1 {
+	status := result.Status
+	if status == model.ValidationStatusUnknownTLD {
+		status = model.ValidationStatusInvalidDomain
+	}
+	return model.EmailValidationResponseV1{
+		Email: result.Email,
+		Validations: model.ValidationResultsV1{
+			Syntax:        result.Validations.Syntax,
+			DomainExists:  result.Validations.DomainExists,
+			MXRecords:     result.Validations.MXRecords,
+			MailboxExists: result.Validations.MailboxExists,
+			IsDisposable:  result.Validations.IsDisposable,
+			IsRoleBased:   result.Validations.IsRoleBased,
+		},
+		Score:          result.Score,
+		Status:         status,
+		AliasOf:        result.AliasOf,
+		TypoSuggestion: result.TypoSuggestion,
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// timeoutBody is the response to a request cut off by its endpoint's timeout
+const timeoutBody = `{"error":"Request timed out"}`
+
+// streamingPaths stream their response as it is produced. http.TimeoutHandler would buffer
+// the whole body, so these are bounded by a context deadline instead.
+var streamingPaths = map[string]bool{
+	"/api/admin/blocklist/export": true,
+}
+
+// ParseEndpointTimeouts parses comma-separated "path=duration" pairs, e.g.
+// "/api/typo-suggestions=2s,/api/validate/batch=5m"
+func ParseEndpointTimeouts(spec string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		path, value, found := strings.Cut(entry, "=")
+		if !found || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid endpoint timeout %q: want \"/path=duration\"", entry)
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid endpoint timeout %q: %q is not a duration", entry, value)
+		}
+		timeouts[path] = timeout
+	}
+	return timeouts, nil
+}
+
+// TimeoutMiddleware bounds each request by the timeout configured for its exact path in
+// timeouts, or by def for other paths. A request still running at its deadline gets
+// 503 Service Unavailable and its context is cancelled, so a slow check stops holding the
+// connection. A timeout of zero leaves requests to that path unbounded. Streaming endpoints
+// only have their context cancelled at the deadline, since their response is already under way.
+func TimeoutMiddleware(timeouts map[string]time.Duration, def time.Duration, next http.Handler) http.Handler {
+	handlers := make(map[string]http.Handler, len(timeouts)+len(streamingPaths))
+	for path := range streamingPaths {
+		handlers[path] = withDeadline(next, def)
+	}
+	for path, timeout := range timeouts {
+		if streamingPaths[path] {
+			handlers[path] = withDeadline(next, timeout)
+			continue
+		}
+		handlers[path] = withTimeout(next, timeout)
+	}
+	fallback := withTimeout(next, def)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler, ok := handlers[r.URL.Path]; ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		fallback.ServeHTTP(w, r)
+	})
+}
+
+// withTimeout wraps next in an http.TimeoutHandler, or returns it unchanged for a zero timeout
+func withTimeout(next http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+	return http.TimeoutHandler(next, timeout, timeoutBody)
+}
+
+// withDeadline cancels the request context after timeout without buffering the response,
+// or returns next unchanged for a zero timeout
+func withDeadline(next http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,140 @@
+// Package cli implements the modes of the emailverify command line tool.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"emailvalidator/internal/model"
+)
+
+// MaxLineLength is the longest input line accepted, in bytes
+const MaxLineLength = 64 * 1024
+
+// Output formats
+const (
+	// FormatJSON writes each result as a JSON object on its own line
+	FormatJSON = "json"
+	// FormatText writes each result as the address, status, score and reason code
+	// separated by tabs
+	FormatText = "text"
+)
+
+// Validator validates a single address
+type Validator interface {
+	ValidateEmail(email string) model.EmailValidationResponse
+}
+
+// ParseFormat checks that format is FormatJSON or FormatText
+func ParseFormat(format string) (string, error) {
+	switch format {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatText:
+		return FormatText, nil
+	default:
+		return "", fmt.Errorf("invalid format %q: must be %q or %q", format, FormatJSON, FormatText)
+	}
+}
+
+// Pipe validates addresses read from in one per line, as they arrive, and writes each
+// result to out in format before reading on, flushing it at once so a downstream reader
+// sees it without waiting for more input. Blank lines and lines starting with "#" are
+// skipped. A line longer than MaxLineLength is cut to that length, far beyond any valid
+// address, so its result is invalid and the lines after it are still read. Pipe returns nil
+// at the end of in, and ctx.Err() once ctx is done: the address being validated then is
+// finished and written, and no further input is read.
+func Pipe(ctx context.Context, in io.Reader, out io.Writer, v Validator, format string) error {
+	lines := make(chan string)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReaderSize(in, MaxLineLength)
+		for {
+			line, err := readLine(reader)
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				scanErr <- err
+				return
+			}
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	w := bufio.NewWriter(out)
+	for {
+		var line string
+		var ok bool
+		select {
+		case line, ok = <-lines:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if !ok {
+			select {
+			case err := <-scanErr:
+				if err != nil {
+					return fmt.Errorf("failed to read input: %w", err)
+				}
+				return nil
+			default:
+				// The reader stopped because ctx is done
+				return ctx.Err()
+			}
+		}
+
+		email := strings.TrimSpace(line)
+		if email == "" || strings.HasPrefix(email, "#") {
+			continue
+		}
+		if err := WriteResult(w, v.ValidateEmail(email), format); err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write result: %w", err)
+		}
+	}
+}
+
+// readLine reads the next line from r without its line ending. Only the first MaxLineLength
+// bytes of a longer line are returned; the rest of it is discarded.
+func readLine(r *bufio.Reader) (string, error) {
+	line, isPrefix, err := r.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	text := string(line)
+	for isPrefix {
+		if _, isPrefix, err = r.ReadLine(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+// WriteResult writes result to w in format
+func WriteResult(w io.Writer, result model.EmailValidationResponse, format string) error {
+	if format == FormatText {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", result.Email, result.Status, result.Score, result.ReasonCode); err != nil {
+			return fmt.Errorf("failed to write result: %w", err)
+		}
+		return nil
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@
 // It defines the request/response models for the API endpoints and internal data representations.
 package model
 
+import "time"
+
 // ValidationStatus represents the status of an email validation
 type ValidationStatus string
 
@@ -13,12 +15,139 @@ const (
 	ValidationStatusMissingEmail  ValidationStatus = "MISSING_EMAIL"
 	ValidationStatusInvalidFormat ValidationStatus = "INVALID_FORMAT"
 	ValidationStatusInvalidDomain ValidationStatus = "INVALID_DOMAIN"
+	ValidationStatusUnknownTLD    ValidationStatus = "UNKNOWN_TLD"
 	ValidationStatusNoMXRecords   ValidationStatus = "NO_MX_RECORDS"
 	ValidationStatusDisposable    ValidationStatus = "DISPOSABLE"
+	ValidationStatusError         ValidationStatus = "ERROR"
+	ValidationStatusNotProcessed  ValidationStatus = "NOT_PROCESSED" // A batch item left unvalidated when the batch deadline passed
+)
+
+// ReasonCode is a stable, machine-readable cause of a result that is not VALID
+type ReasonCode string
+
+// Possible reason codes. Every result that is not VALID carries exactly one, its primary cause.
+const (
+	ReasonMissingEmail      ReasonCode = "MISSING_EMAIL"
+	ReasonSyntaxInvalid     ReasonCode = "SYNTAX_INVALID"
+	ReasonUnknownTLD        ReasonCode = "UNKNOWN_TLD"
+	ReasonTimeout           ReasonCode = "TIMEOUT"     // The validation deadline passed before the deciding check finished, or the batch deadline before the item was validated
+	ReasonDNSTimeout        ReasonCode = "DNS_TIMEOUT" // A DNS lookup for the domain failed or timed out
+	ReasonDomainNotFound    ReasonCode = "DOMAIN_NOT_FOUND"
+	ReasonNullMX            ReasonCode = "NULL_MX"            // The domain publishes a null MX (RFC 7505) and accepts no mail
+	ReasonRoleUndeliverable ReasonCode = "ROLE_UNDELIVERABLE" // A role account whose domain or mailbox does not accept mail
+	ReasonParkedDomain      ReasonCode = "PARKED_DOMAIN"      // The domain is delegated to a parking service
+	ReasonNoMX              ReasonCode = "NO_MX"
+	ReasonDisposable        ReasonCode = "DISPOSABLE"
+	ReasonDisposableUnknown ReasonCode = "DISPOSABLE_UNKNOWN" // The disposable blocklist has not loaded yet, so whether the domain is disposable is unknown
+	ReasonBlockedDomain     ReasonCode = "BLOCKED_DOMAIN"     // The domain is rejected by an organization rule
+	ReasonMailboxNotFound   ReasonCode = "MAILBOX_NOT_FOUND"
+	ReasonGreylisted        ReasonCode = "GREYLISTED"
+	ReasonMailboxUnverified ReasonCode = "MAILBOX_UNVERIFIED" // The mailbox probe failed without an answer (e.g. connection refused)
+	ReasonRoleUnverified    ReasonCode = "ROLE_UNVERIFIED"    // A role account on a catch-all server, whose mailbox cannot be confirmed
+	ReasonCatchAll          ReasonCode = "CATCH_ALL"
+	ReasonPossibleTypo      ReasonCode = "POSSIBLE_TYPO"
+	ReasonRoleAccount       ReasonCode = "ROLE_ACCOUNT"
+	ReasonLowScore          ReasonCode = "LOW_SCORE" // The score is too low for VALID without any of the causes above
+	ReasonInternalError     ReasonCode = "INTERNAL_ERROR"
 )
 
 // ValidationResults represents the results of various validation checks
 type ValidationResults struct {
+	Syntax         bool `json:"syntax"`
+	UnknownTLD     bool `json:"unknown_tld"` // The domain's top-level domain does not exist, so no DNS lookups were made
+	DomainExists   bool `json:"domain_exists"`
+	MXRecords      bool `json:"mx_records"`
+	MailboxExists  bool `json:"mailbox_exists"`
+	IsDisposable   bool `json:"is_disposable"`
+	IsRoleBased    bool `json:"is_role_based"`
+	IsFreeProvider bool `json:"is_free_provider"` // The domain is a free mailbox provider anyone can sign up for, such as gmail.com
+	IsCatchAll     bool `json:"is_catch_all"`     // The mail server accepts every recipient, so mailbox_exists is unconfirmed
+	IsGreylisted   bool `json:"is_greylisted"`    // The mail server deferred the mailbox check; a later retry may succeed
+	NullMX         bool `json:"null_mx"`          // The domain publishes a null MX (RFC 7505) and explicitly accepts no mail
+	IPLiteralMX    bool `json:"ip_literal_mx"`    // An MX record points at an IP address instead of a hostname
+	IsParked       bool `json:"is_parked"`        // The domain is delegated to the nameservers of a parking service
+}
+
+// AddressingCapabilities describes the addressing features supported by the email's provider
+type AddressingCapabilities struct {
+	DotInsensitive      bool `json:"dot_insensitive"`      // Dots in the local part are ignored
+	PlusAddressing      bool `json:"plus_addressing"`      // Anything after "+" in the local part is ignored
+	SubdomainAddressing bool `json:"subdomain_addressing"` // anything@user.domain is delivered to user@domain
+	CaseInsensitive     bool `json:"case_insensitive"`     // The local part is matched ignoring case
+}
+
+// RoleStatus combines role detection with deliverability for role accounts
+type RoleStatus string
+
+// Possible role statuses. Addresses that are not role accounts have none.
+const (
+	RoleStatusDeliverable   RoleStatus = "DELIVERABLE"   // A role account whose domain and mailbox accept mail
+	RoleStatusUnverified    RoleStatus = "UNVERIFIED"    // A role account whose mailbox could not be confirmed (catch-all, greylisted, inconclusive)
+	RoleStatusUndeliverable RoleStatus = "UNDELIVERABLE" // A role account whose domain does not exist, accepts no mail or rejected the mailbox
+)
+
+// EmailValidationRequest represents a request to validate a single email
+type EmailValidationRequest struct {
+	Email string `json:"email"`
+}
+
+// Versions of the validation result schema. Responses carry the version they follow in
+// schema_version, and clients may ask for an older one while it is kept for a deprecation
+// window.
+const (
+	// SchemaVersion1 is the original result shape, from before schema_version existed
+	SchemaVersion1 = 1
+	// SchemaVersion2 adds every field introduced since, and schema_version itself
+	SchemaVersion2 = 2
+	// SchemaVersion is the current version, served unless a client asks for another
+	SchemaVersion = SchemaVersion2
+)
+
+// EmailValidationResponse represents the response for email validation
+type EmailValidationResponse struct {
+	SchemaVersion       int                     `json:"schema_version,omitempty"` // Version of the result schema; only set on a top-level response, not on batch results
+	Index               *int                    `json:"index,omitempty"`          // Position of the email in the batch request; only set for batch results
+	Email               string                  `json:"email"`
+	DomainOriginal      string                  `json:"domain_original,omitempty"`   // Domain as typed, for display; set whenever the address has a domain
+	DomainNormalized    string                  `json:"domain_normalized,omitempty"` // Domain lowercased, in punycode and without a trailing dot, for use as a key
+	Validations         ValidationResults       `json:"validations"`
+	Score               int                     `json:"score"`
+	Status              ValidationStatus        `json:"status"`
+	ReasonCode          ReasonCode              `json:"reason_code,omitempty"`          // Primary cause when the status is not VALID
+	Reason              string                  `json:"reason,omitempty"`               // Human-readable explanation when the address is rejected
+	RoleStatus          RoleStatus              `json:"role_status,omitempty"`          // Deliverability of a role account; only set when is_role_based
+	MXDomain            string                  `json:"mx_domain,omitempty"`            // Parent domain whose MX records were used; only set when the domain has none and MX walk-up is enabled
+	AliasOf             string                  `json:"aliasOf,omitempty"`              // Optional field to indicate if email is an alias
+	Canonical           string                  `json:"canonical,omitempty"`            // Normalized address to use as a stable key for the mailbox; set whenever the syntax is valid
+	HasSubaddress       bool                    `json:"has_subaddress"`                 // The address carries a subaddress tag, such as "+news" at Gmail
+	SubaddressTag       string                  `json:"subaddress_tag,omitempty"`       // The tag, without its separator; only set when has_subaddress is true
+	TypoSuggestion      string                  `json:"typoSuggestion,omitempty"`       // Optional field for typo suggestion
+	Inconclusive        []string                `json:"inconclusive,omitempty"`         // Checks that could not reach a verdict (e.g. DNS timeout)
+	TimedOut            []string                `json:"timed_out,omitempty"`            // Checks cut off by the validation deadline; also listed in inconclusive
+	Addressing          *AddressingCapabilities `json:"addressing,omitempty"`           // Addressing features of the provider; only set for known providers
+	DomainAgeDays       *int                    `json:"domain_age_days,omitempty"`      // Days since the domain was registered; only set when domain age checks are enabled
+	IsNewDomain         bool                    `json:"is_new_domain,omitempty"`        // The domain was registered more recently than the configured threshold
+	DisposableHeuristic *DisposableHeuristic    `json:"disposable_heuristic,omitempty"` // Heuristic evidence that a domain missing from the disposable lists is disposable; only set when heuristics are enabled and a rule matched
+	Fingerprint         string                  `json:"fingerprint,omitempty"`          // Stable digest of the status, score, reason code and validation flags, for detecting changed results
+	ValidatedAt         time.Time               `json:"validated_at"`                   // When the checks behind the result ran; a cached result keeps the time it was first validated
+	RevalidateAfter     int                     `json:"revalidate_after"`               // Seconds after validated_at when the address should be validated again, from the outcome; 0 means at once
+	Diagnostics         *ValidationDiagnostics  `json:"diagnostics,omitempty"`          // Where the domain-level data came from; only set when requested with diagnostics=true
+	Correction          *Correction             `json:"correction,omitempty"`           // High-confidence typo correction and the result for the corrected address; only set for autocorrected batch results
+	Error               string                  `json:"error,omitempty"`                // Internal failure that prevented this item from being validated; only set for batch results
+}
+
+// EmailValidationResponseV1 is a result in the SchemaVersion1 shape
+type EmailValidationResponseV1 struct {
+	Email          string              `json:"email"`
+	Validations    ValidationResultsV1 `json:"validations"`
+	Score          int                 `json:"score"`
+	Status         ValidationStatus    `json:"status"`
+	AliasOf        string              `json:"aliasOf,omitempty"`
+	TypoSuggestion string              `json:"typoSuggestion,omitempty"`
+}
+
+// ValidationResultsV1 holds the validation checks of a SchemaVersion1 result
+type ValidationResultsV1 struct {
 	Syntax        bool `json:"syntax"`
 	DomainExists  bool `json:"domain_exists"`
 	MXRecords     bool `json:"mx_records"`
@@ -27,29 +156,117 @@ type ValidationResults struct {
 	IsRoleBased   bool `json:"is_role_based"`
 }
 
-// EmailValidationRequest represents a request to validate a single email
-type EmailValidationRequest struct {
-	Email string `json:"email"`
+// BatchValidationResponseV1 is a batch response in the SchemaVersion1 shape
+type BatchValidationResponseV1 struct {
+	Results []EmailValidationResponseV1 `json:"results"`
 }
 
-// EmailValidationResponse represents the response for email validation
-type EmailValidationResponse struct {
-	Email          string            `json:"email"`
-	Validations    ValidationResults `json:"validations"`
-	Score          int               `json:"score"`
-	Status         ValidationStatus  `json:"status"`
-	AliasOf        string            `json:"aliasOf,omitempty"`        // Optional field to indicate if email is an alias
-	TypoSuggestion string            `json:"typoSuggestion,omitempty"` // Optional field for typo suggestion
+// ValidationDiagnostics describes the data behind a result, for debugging stale results
+type ValidationDiagnostics struct {
+	FromCache       bool `json:"from_cache"`                  // The domain's existence check was answered from the domain cache
+	CacheAgeSeconds *int `json:"cache_age_seconds,omitempty"` // Seconds since the cached check was made; only set when from_cache is true
+}
+
+// Correction is a typo correction substituted for an address by an autocorrecting batch,
+// with the result of validating the corrected address
+type Correction struct {
+	Email      string           `json:"email"`                 // The corrected address
+	Confidence float64          `json:"confidence"`            // How likely the correction is the intended address, from 0 to 1
+	Status     ValidationStatus `json:"status,omitempty"`      // Status of the corrected address; only set when it was re-validated
+	Score      *int             `json:"score,omitempty"`       // Score of the corrected address; only set when it was re-validated
+	ReasonCode ReasonCode       `json:"reason_code,omitempty"` // Primary cause when the corrected address is not VALID
+}
+
+// DisposableHeuristic is the evidence from the disposable heuristics for a domain that is
+// not on the disposable lists
+type DisposableHeuristic struct {
+	Score float64  `json:"score"` // How likely the domain is to be disposable, from 0 to 1
+	Rules []string `json:"rules"` // Names of the heuristic rules the domain matched
 }
 
 // BatchValidationRequest represents a request to validate multiple emails
 type BatchValidationRequest struct {
 	Emails []string `json:"emails"`
+	// SourceURL is an http(s) URL of a list to validate instead of Emails: one address per
+	// line, or CSV
+	SourceURL string `json:"source_url,omitempty"`
 }
 
-// BatchValidationResponse represents the response for batch email validation
+// BatchValidationResponse represents the response for batch email validation.
+// Results are always in the same order as the request's emails.
 type BatchValidationResponse struct {
-	Results []EmailValidationResponse `json:"results"`
+	SchemaVersion int                       `json:"schema_version,omitempty"` // Version of the result schema
+	Results       []EmailValidationResponse `json:"results"`
+	Errors        int                       `json:"errors"`                  // Number of results that failed with an internal error
+	NotProcessed  int                       `json:"not_processed,omitempty"` // Number of results left unvalidated, with status NOT_PROCESSED, when the batch deadline passed
+	Summary       *BatchSummary             `json:"summary,omitempty"`       // List-quality analysis across the whole batch
+}
+
+// BatchSummary holds list-quality signals that only show across a whole batch, such as
+// the patterns typical of scraped lists
+type BatchSummary struct {
+	RoleCollisions      []RoleCollision `json:"role_collisions"`       // Domains with more than one role account in the batch, by domain
+	DisposableCount     int             `json:"disposable_count"`      // Addresses at disposable domains
+	DisposableShare     float64         `json:"disposable_share"`      // Share of well-formed addresses at disposable domains, from 0 to 1
+	HighDisposableShare bool            `json:"high_disposable_share"` // The disposable share is high enough to suggest a low-quality list
+}
+
+// RoleCollision is a domain with several distinct role accounts in one batch, such as both
+// info@ and sales@ at the same company, which often indicates a scraped list
+type RoleCollision struct {
+	Domain   string   `json:"domain"`   // Normalized domain
+	Accounts []string `json:"accounts"` // Distinct lowercased role local parts, sorted
+}
+
+// ExplainResponse is the full validation result together with every intermediate signal
+// that led to it, as returned by the explain endpoint
+type ExplainResponse struct {
+	EmailValidationResponse
+	Trace ValidationTrace `json:"trace"`
+}
+
+// ValidationTrace records the intermediate signals of a single validation
+type ValidationTrace struct {
+	TimingsMs map[string]float64 `json:"timings_ms"`      // Duration of each check in milliseconds
+	DNS       *DNSTrace          `json:"dns,omitempty"`   // Raw DNS records; only set when the domain was looked up
+	SMTP      *SMTPTrace         `json:"smtp,omitempty"`  // Mailbox probe details; only set when a probe was made
+	Score     *ScoreTrace        `json:"score,omitempty"` // How the score was computed; only set when the address was scored
+}
+
+// DNSTrace holds the raw DNS records behind a domain's validation
+type DNSTrace struct {
+	CacheHit        bool       `json:"cache_hit"`                   // The domain's existence check was answered from the cache
+	CacheAgeSeconds *int       `json:"cache_age_seconds,omitempty"` // Seconds since the cached check was made; only set when cache_hit is true
+	MXRecords       []MXRecord `json:"mx_records"`
+	MXError         string     `json:"mx_error,omitempty"`
+	SPF             string     `json:"spf,omitempty"`
+	DMARC           string     `json:"dmarc,omitempty"`
+}
+
+// MXRecord is a mail server published for a domain
+type MXRecord struct {
+	Host string `json:"host"`
+	Pref uint16 `json:"pref"`
+}
+
+// SMTPTrace holds the outcome and full conversation of a mailbox probe
+type SMTPTrace struct {
+	Status      string   `json:"status"`
+	Host        string   `json:"host,omitempty"`
+	Unreachable []string `json:"unreachable,omitempty"`
+	Sender      string   `json:"sender"`
+	CatchAll    bool     `json:"catch_all"`
+	Code        int      `json:"code,omitempty"`
+	Message     string   `json:"message,omitempty"`
+	Transcript  []string `json:"transcript,omitempty"`
+}
+
+// ScoreTrace shows how the final score was computed
+type ScoreTrace struct {
+	Inputs map[string]bool `json:"inputs"` // Checks passed to the weighted score
+	Base   int             `json:"base"`   // Weighted score before adjustments
+	Steps  []string        `json:"steps"`  // Adjustments applied to the base score, in order
+	Final  int             `json:"final"`
 }
 
 // TypoSuggestionRequest represents a request for email typo suggestions
@@ -57,10 +274,18 @@ type TypoSuggestionRequest struct {
 	Email string `json:"email"`
 }
 
+// TypoSuggestionAcceptRequest reports that a user took the typo suggestion offered for an address
+type TypoSuggestionAcceptRequest struct {
+	Email      string `json:"email"`      // The address as typed
+	Suggestion string `json:"suggestion"` // The suggested address the user accepted
+}
+
 // TypoSuggestionResponse represents the response for email typo suggestions
 type TypoSuggestionResponse struct {
 	Email          string `json:"email"`
 	TypoSuggestion string `json:"typoSuggestion,omitempty"`
+	// Confidence is how close the suggestion is to the typed domain, from 0 to 1
+	Confidence float64 `json:"confidence,omitempty"`
 }
 
 // APIStatus represents the current status of the API
@@ -71,6 +296,156 @@ type APIStatus struct {
 	AvgResponseTimeMs float64 `json:"average_response_time_ms"`
 }
 
+// DomainValidationRequest represents a request to validate a domain without an address
+type DomainValidationRequest struct {
+	Domain string `json:"domain"`
+}
+
+// DomainValidations represents the results of the domain-level checks
+type DomainValidations struct {
+	UnknownTLD   bool `json:"unknown_tld"` // The domain's top-level domain does not exist, so no DNS lookups were made
+	DomainExists bool `json:"domain_exists"`
+	MXRecords    bool `json:"mx_records"`
+	IsDisposable bool `json:"is_disposable"`
+	NullMX       bool `json:"null_mx"`       // The domain publishes a null MX (RFC 7505) and explicitly accepts no mail
+	IPLiteralMX  bool `json:"ip_literal_mx"` // An MX record points at an IP address instead of a hostname
+	IsParked     bool `json:"is_parked"`     // The domain is delegated to the nameservers of a parking service
+}
+
+// DomainValidationResponse represents the response for validating a domain on its own
+type DomainValidationResponse struct {
+	Domain              string               `json:"domain"`
+	DomainOriginal      string               `json:"domain_original"`   // Domain as typed, for display
+	DomainNormalized    string               `json:"domain_normalized"` // Domain lowercased, in punycode and without a trailing dot, for use as a key
+	Validations         DomainValidations    `json:"validations"`
+	Status              ValidationStatus     `json:"status"`
+	ReasonCode          ReasonCode           `json:"reason_code,omitempty"`          // Primary cause when the status is not VALID
+	Reason              string               `json:"reason,omitempty"`               // Human-readable explanation when the domain accepts no mail
+	MXDomain            string               `json:"mx_domain,omitempty"`            // Parent domain whose MX records were used; only set when the domain has none and MX walk-up is enabled
+	Inconclusive        []string             `json:"inconclusive,omitempty"`         // Checks that could not reach a verdict (e.g. DNS timeout)
+	TimedOut            []string             `json:"timed_out,omitempty"`            // Checks cut off by the validation deadline; also listed in inconclusive
+	DomainAgeDays       *int                 `json:"domain_age_days,omitempty"`      // Days since the domain was registered; only set when domain age checks are enabled
+	IsNewDomain         bool                 `json:"is_new_domain,omitempty"`        // The domain was registered more recently than the configured threshold
+	DisposableHeuristic *DisposableHeuristic `json:"disposable_heuristic,omitempty"` // Heuristic evidence that a domain missing from the disposable lists is disposable
+}
+
+// DisposableBatchRequest represents a request to check many domains for disposability
+type DisposableBatchRequest struct {
+	Domains []string `json:"domains"` // Domains or addresses; an address is checked by its domain
+}
+
+// DisposableCheckResult is the disposability of one item of a bulk disposable check
+type DisposableCheckResult struct {
+	Index               int                  `json:"index"`                          // Position of the item in the request
+	Input               string               `json:"input"`                          // The item as sent
+	Domain              string               `json:"domain,omitempty"`               // Normalized domain that was checked; empty if none could be taken from the item
+	IsDisposable        bool                 `json:"is_disposable"`                  // The domain is on a disposable list or, with heuristics enabled, scores as disposable
+	DisposableHeuristic *DisposableHeuristic `json:"disposable_heuristic,omitempty"` // Heuristic evidence that a domain missing from the disposable lists is disposable
+	Inconclusive        []string             `json:"inconclusive,omitempty"`         // Lists is_disposable while the remote blocklist is still loading
+	Error               string               `json:"error,omitempty"`                // Why the item could not be checked, e.g. it is not a domain or an address
+}
+
+// DisposableBatchResponse represents the response for a bulk disposable check
+type DisposableBatchResponse struct {
+	Results []DisposableCheckResult `json:"results"` // One result per item, in request order
+	Errors  int                     `json:"errors"`  // Number of items that could not be checked
+}
+
+// DisposableSourceMatch is a disposable list entry that matches a domain
+type DisposableSourceMatch struct {
+	Source string `json:"source"` // "local" for the local list, or the URL of the remote source listing the entry
+	Entry  string `json:"entry"`  // The matching entry: "*.example.com" is a wildcard and ".example.com" also covers subdomains; a regular expression for a pattern
+	Match  string `json:"match"`  // "exact" when the entry names the domain itself, "wildcard" when it covers a parent domain, "pattern" when a regular expression matched
+}
+
+// DisposableSourceResponse explains why a domain is or is not considered disposable
+type DisposableSourceResponse struct {
+	Domain              string                  `json:"domain"`                         // Normalized domain that was checked
+	IsDisposable        bool                    `json:"is_disposable"`                  // The verdict of the disposable check, as /api/disposable-check/batch reports it
+	Sources             []DisposableSourceMatch `json:"sources"`                        // Every list entry matching the domain, most specific first; empty if none
+	AllowlistEntry      string                  `json:"allowlist_entry,omitempty"`      // The allowlist entry matching the domain, which may override the list entries
+	DisposableHeuristic *DisposableHeuristic    `json:"disposable_heuristic,omitempty"` // Heuristic evidence, for a domain missing from the lists
+	MXProvider          string                  `json:"mx_provider,omitempty"`          // The disposable provider whose mail servers receive the domain's mail; only set when the MX check is enabled
+	Inconclusive        []string                `json:"inconclusive,omitempty"`         // Lists is_disposable while the remote blocklist is still loading
+}
+
+// QuickValidations represents the results of the checks that run without network I/O
+type QuickValidations struct {
+	Syntax         bool `json:"syntax"`
+	UnknownTLD     bool `json:"unknown_tld"` // The domain's top-level domain does not exist
+	IsDisposable   bool `json:"is_disposable"`
+	IsRoleBased    bool `json:"is_role_based"`
+	IsFreeProvider bool `json:"is_free_provider"`
+}
+
+// QuickValidationResponse represents the response for a local-only validation, which never
+// reaches DNS, SMTP or a cache. Deliverability is unchecked, so the status is never VALID.
+type QuickValidationResponse struct {
+	Email               string                  `json:"email"`
+	DomainOriginal      string                  `json:"domain_original,omitempty"`   // Domain as typed, for display; set whenever the address has a domain
+	DomainNormalized    string                  `json:"domain_normalized,omitempty"` // Domain lowercased, in punycode and without a trailing dot, for use as a key
+	Validations         QuickValidations        `json:"validations"`
+	Status              ValidationStatus        `json:"status"`
+	ReasonCode          ReasonCode              `json:"reason_code,omitempty"`          // Primary cause when a local check failed or flagged the address
+	Reason              string                  `json:"reason,omitempty"`               // Human-readable explanation when the address is rejected
+	AliasOf             string                  `json:"aliasOf,omitempty"`              // Optional field to indicate if email is an alias
+	Canonical           string                  `json:"canonical,omitempty"`            // Normalized address to use as a stable key for the mailbox; set whenever the syntax is valid
+	HasSubaddress       bool                    `json:"has_subaddress"`                 // The address carries a subaddress tag, such as "+news" at Gmail
+	SubaddressTag       string                  `json:"subaddress_tag,omitempty"`       // The tag, without its separator; only set when has_subaddress is true
+	TypoSuggestion      string                  `json:"typoSuggestion,omitempty"`       // Optional field for typo suggestion
+	Addressing          *AddressingCapabilities `json:"addressing,omitempty"`           // Addressing features of the provider; only set for known providers
+	DisposableHeuristic *DisposableHeuristic    `json:"disposable_heuristic,omitempty"` // Heuristic evidence that a domain missing from the disposable lists is disposable
+}
+
+// SameMailboxRequest represents a request to compare two addresses
+type SameMailboxRequest struct {
+	First  string `json:"first"`
+	Second string `json:"second"`
+}
+
+// SameMailboxResponse reports whether two addresses reach the same mailbox
+type SameMailboxResponse struct {
+	First           string `json:"first"`
+	Second          string `json:"second"`
+	FirstCanonical  string `json:"first_canonical"`
+	SecondCanonical string `json:"second_canonical"`
+	SameMailbox     bool   `json:"same_mailbox"`
+}
+
+// CanonicalizeBatchRequest represents a request to canonicalize a list of addresses
+type CanonicalizeBatchRequest struct {
+	Emails []string `json:"emails"`
+}
+
+// CanonicalizeResult is the canonical form of one address of a list
+type CanonicalizeResult struct {
+	Email     string `json:"email"`
+	Canonical string `json:"canonical"`        // Canonical form; the address unchanged when it is invalid
+	Invalid   bool   `json:"invalid"`          // The address failed syntax validation and was not canonicalized
+	Reason    string `json:"reason,omitempty"` // Human-readable explanation when the address is invalid
+}
+
+// CanonicalizeBatchResponse maps each address of a list to its canonical form. Results are
+// in the same order as the request's emails.
+type CanonicalizeBatchResponse struct {
+	Results  []CanonicalizeResult `json:"results"`
+	Distinct int                  `json:"distinct"` // Number of distinct mailboxes among the valid addresses
+	Invalid  int                  `json:"invalid"`  // Number of invalid addresses
+}
+
+// BlocklistRefreshResponse describes the disposable blocklist after a forced refresh
+type BlocklistRefreshResponse struct {
+	Domains  int       `json:"domains"`
+	LoadedAt time.Time `json:"loaded_at"`
+}
+
+// BlocklistExportEntry is an entry of an exported disposable blocklist and the URLs of the
+// sources listing it
+type BlocklistExportEntry struct {
+	Entry   string   `json:"entry"`
+	Sources []string `json:"sources"`
+}
+
 // CreditInfo represents the credit information for an API key
 type CreditInfo struct {
 	RemainingCredits int `json:"remaining_credits"`
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"strings"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// addressingCapabilities returns the addressing features of domain for the response, or nil
+// if the rule validator cannot tell or the provider supports none
+func addressingCapabilities(ruleValidator EmailRuleValidator, domain string) *model.AddressingCapabilities {
+	provider, ok := ruleValidator.(AddressingCapabilityProvider)
+	if !ok {
+		return nil
+	}
+
+	caps := provider.AddressingCapabilities(domain)
+	if caps == (validator.Capabilities{}) {
+		return nil
+	}
+	return &model.AddressingCapabilities{
+		DotInsensitive:      caps.DotInsensitive,
+		PlusAddressing:      caps.PlusAddressing,
+		SubdomainAddressing: caps.SubdomainAddressing,
+		CaseInsensitive:     caps.CaseInsensitive,
+	}
+}
+
+// canonicalize returns the normalized form of email, or email with its domain normalized
+// for lookups if the rule validator cannot canonicalize
+func canonicalize(ruleValidator EmailRuleValidator, email string) string {
+	if c, ok := ruleValidator.(Canonicalizer); ok {
+		return c.Canonicalize(email)
+	}
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return email
+	}
+	return email[:at+1] + validator.NormalizeDomain(email[at+1:])
+}
+
+// detectSubaddress returns the subaddress tag of email and whether it has one, when the
+// rule validator can tell
+func detectSubaddress(ruleValidator EmailRuleValidator, email string) (string, bool) {
+	if d, ok := ruleValidator.(SubaddressDetector); ok {
+		return d.DetectSubaddress(email)
+	}
+	return "", false
+}
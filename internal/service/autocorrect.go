@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// DefaultAutocorrectConfidence is the lowest typo suggestion confidence an autocorrecting
+// batch substitutes. It is stricter than the confidence at which suggestions are shown,
+// since nobody confirms the substitution.
+const DefaultAutocorrectConfidence = 0.85
+
+// autocorrectConfidence returns the requested autocorrect threshold, or the default
+func (o ValidationOptions) autocorrectConfidence() float64 {
+	if o.AutocorrectConfidence > 0 {
+		return o.AutocorrectConfidence
+	}
+	return DefaultAutocorrectConfidence
+}
+
+// autocorrect attaches a Correction to every result whose typo suggestion is confident
+// enough and, unless opts skips it, validates the corrected addresses as one more batch.
+// The original result is left as it was, so both statuses are reported. Corrections not
+// revalidated before ctx is done are reported without a status.
+func (s *EmailService) autocorrect(ctx context.Context, response *model.BatchValidationResponse, opts ValidationOptions) {
+	threshold := opts.autocorrectConfidence()
+	var corrected []string
+	var positions []int
+	for i := range response.Results {
+		result := &response.Results[i]
+		if result.TypoSuggestion == "" || result.Error != "" {
+			continue
+		}
+		confidence := validator.SuggestionConfidence(domainOf(result.Email), domainOf(result.TypoSuggestion))
+		if confidence < threshold {
+			continue
+		}
+		result.Correction = &model.Correction{Email: result.TypoSuggestion, Confidence: confidence}
+		corrected = append(corrected, result.TypoSuggestion)
+		positions = append(positions, i)
+	}
+	if len(corrected) == 0 || opts.SkipRevalidation {
+		return
+	}
+
+	revalidated := s.batchValidationSvc.ValidateEmailsContext(ctx, corrected, opts)
+	s.postProcessBatch(&revalidated)
+	for j, i := range positions {
+		result := revalidated.Results[j]
+		if result.Error != "" || result.Status == model.ValidationStatusNotProcessed {
+			continue
+		}
+		correction := response.Results[i].Correction
+		correction.Status = result.Status
+		correction.Score = &result.Score
+		correction.ReasonCode = result.ReasonCode
+	}
+}
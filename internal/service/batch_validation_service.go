@@ -2,20 +2,55 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"runtime"
-	"strings"
+	"slices"
 	"sync"
+	"time"
 
 	"emailvalidator/internal/model"
 	"emailvalidator/internal/utils"
+	"emailvalidator/pkg/validator"
 )
 
+// DefaultMaxBatchSize is the largest number of emails accepted in one batch unless configured otherwise
+const DefaultMaxBatchSize = 1000
+
+// BatchTooLargeError is returned by CheckBatchSize for a batch with more emails than allowed
+type BatchTooLargeError struct {
+	Size int
+	Max  int
+}
+
+func (e *BatchTooLargeError) Error() string {
+	return fmt.Sprintf("batch of %d emails exceeds the maximum of %d", e.Size, e.Max)
+}
+
 // BatchValidationService handles batch email validation operations
 type BatchValidationService struct {
 	emailRuleValidator   EmailRuleValidator
 	domainValidationSvc  DomainValidationService
 	metricsCollector     MetricsCollector
+	mailboxVerifier      MailboxVerifier
+	domainAgeChecker     DomainAgeChecker
+	domainLearner        DomainLearner
+	unknownPolicy        UnknownPolicy
+	greylistPolicy       GreylistPolicy
+	disposablePolicy     DisposablePolicy
+	statusPrecedence     StatusPrecedence
+	syntaxMode           validator.SyntaxMode
+	confidencePenalties  ConfidencePenalties
 	maxConcurrentWorkers int
+	domainConcurrency    int
+	maxBatchSize         int
+	timeout              time.Duration
+}
+
+// defaultWorkerCount returns the number of workers a batch is validated with unless
+// configured otherwise. Workers mostly wait on DNS and SMTP, so there are several per CPU.
+func defaultWorkerCount() int {
+	return runtime.NumCPU() * 4
 }
 
 // NewBatchValidationService creates a new instance of BatchValidationService
@@ -28,24 +63,59 @@ func NewBatchValidationService(
 		emailRuleValidator:   ruleValidator,
 		domainValidationSvc:  domainValidationSvc,
 		metricsCollector:     metricsCollector,
-		maxConcurrentWorkers: runtime.NumCPU() * 4,
+		unknownPolicy:        UnknownPolicyStrict,
+		greylistPolicy:       GreylistPolicyUncertain,
+		disposablePolicy:     DisposablePolicyReject,
+		syntaxMode:           validator.SyntaxModeLenient,
+		confidencePenalties:  DefaultConfidencePenalties(),
+		maxConcurrentWorkers: defaultWorkerCount(),
+		maxBatchSize:         DefaultMaxBatchSize,
 	}
 }
 
+// CheckBatchSize returns a *BatchTooLargeError if a batch of size emails exceeds the
+// configured maximum. Callers should check before reading or validating the batch.
+func (s *BatchValidationService) CheckBatchSize(size int) error {
+	if s.maxBatchSize > 0 && size > s.maxBatchSize {
+		return &BatchTooLargeError{Size: size, Max: s.maxBatchSize}
+	}
+	return nil
+}
+
 // ValidateEmails performs validation on multiple email addresses concurrently
 func (s *BatchValidationService) ValidateEmails(emails []string) model.BatchValidationResponse {
+	return s.ValidateEmailsWithOptions(emails, ValidationOptions{})
+}
+
+// ValidateEmailsWithOptions performs validation on multiple email addresses concurrently,
+// applying per-request overrides from opts
+func (s *BatchValidationService) ValidateEmailsWithOptions(emails []string, opts ValidationOptions) model.BatchValidationResponse {
+	return s.ValidateEmailsContext(context.Background(), emails, opts)
+}
+
+// ValidateEmailsContext performs validation on multiple email addresses concurrently,
+// applying per-request overrides from opts. When ctx is done or the batch timeout passes,
+// it returns at once: the results completed so far are kept, and every other email is
+// reported with status NOT_PROCESSED and counted in NotProcessed. Checks still running are
+// abandoned.
+func (s *BatchValidationService) ValidateEmailsContext(ctx context.Context, emails []string, opts ValidationOptions) model.BatchValidationResponse {
 	if len(emails) == 0 {
 		return model.BatchValidationResponse{Results: []model.EmailValidationResponse{}}
 	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
 
 	// Group emails by domain
 	emailsByDomain := s.groupEmailsByDomain(emails)
 
 	// Process domain validations
-	domainResults := s.processDomainValidations(emailsByDomain)
+	domainResults := s.processDomainValidations(ctx, emailsByDomain, opts.unknownPolicy(s.unknownPolicy))
 
 	// Process individual emails
-	response := s.processEmails(emails, emailsByDomain, domainResults)
+	response := s.processEmails(ctx, emails, emailsByDomain, domainResults, opts)
 
 	return response
 }
@@ -57,49 +127,53 @@ func (s *BatchValidationService) groupEmailsByDomain(emails []string) map[string
 			continue
 		}
 
-		parts := strings.Split(email, "@")
-		if len(parts) != 2 {
+		domain, ok := splitDomain(email)
+		if !ok {
 			continue
 		}
-
-		domain := parts[1]
 		emailsByDomain[domain] = append(emailsByDomain[domain], email)
 	}
 	return emailsByDomain
 }
 
-func (s *BatchValidationService) processDomainValidations(emailsByDomain map[string][]string) map[string]struct {
-	DomainExists bool
-	MXRecords    bool
-	IsDisposable bool
-} {
-	ctx := context.Background()
-	domainResults := make(map[string]struct {
-		DomainExists bool
-		MXRecords    bool
-		IsDisposable bool
-	})
+// processDomainValidations runs the domain-level checks for every batch domain, resolving
+// inconclusive checks under unknownPolicy. Once ctx is done, no further checks are started,
+// and domains whose checks have not finished are left out of the results.
+func (s *BatchValidationService) processDomainValidations(ctx context.Context, emailsByDomain map[string][]string, unknownPolicy UnknownPolicy) map[string]DomainCheckResult {
+	domainResults := make(map[string]DomainCheckResult)
 
 	var wg sync.WaitGroup
 	resultChan := make(chan struct {
-		domain       string
-		domainExists bool
-		hasMX        bool
-		isDisposable bool
+		domain string
+		result DomainCheckResult
 	}, len(emailsByDomain))
 
-	// Process domains concurrently
+	// Process domains concurrently, at most domainConcurrency at a time when it is set
+	var slots chan struct{}
+	if s.domainConcurrency > 0 {
+		slots = make(chan struct{}, s.domainConcurrency)
+	}
+dispatch:
 	for domain := range emailsByDomain {
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				break dispatch
+			}
+		} else if ctx.Err() != nil {
+			break dispatch
+		}
 		wg.Add(1)
 		go func(d string) {
 			defer wg.Done()
-			exists, hasMX, isDisposable := s.domainValidationSvc.ValidateDomainConcurrently(ctx, d)
+			if slots != nil {
+				defer func() { <-slots }()
+			}
 			resultChan <- struct {
-				domain       string
-				domainExists bool
-				hasMX        bool
-				isDisposable bool
-			}{d, exists, hasMX, isDisposable}
+				domain string
+				result DomainCheckResult
+			}{d, s.checkDomain(ctx, d, unknownPolicy)}
 		}(domain)
 	}
 
@@ -109,31 +183,51 @@ func (s *BatchValidationService) processDomainValidations(emailsByDomain map[str
 		close(resultChan)
 	}()
 
-	// Collect domain validation results
-	for result := range resultChan {
-		domainResults[result.domain] = struct {
-			DomainExists bool
-			MXRecords    bool
-			IsDisposable bool
-		}{result.domainExists, result.hasMX, result.isDisposable}
+	// Collect domain validation results. The channel is buffered for every domain, so
+	// checks still running at the deadline finish without blocking.
+	for {
+		select {
+		case result, ok := <-resultChan:
+			if !ok {
+				return domainResults
+			}
+			domainResults[result.domain] = result.result
+		case <-ctx.Done():
+			return domainResults
+		}
 	}
+}
+
+// checkDomain runs the domain-level checks for one batch domain. A panic is recovered
+// and recorded on the result so it only fails the emails on that domain.
+func (s *BatchValidationService) checkDomain(ctx context.Context, domain string, unknownPolicy UnknownPolicy) (result DomainCheckResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Domain validation for %s panicked: %v", domain, r)
+			result = DomainCheckResult{err: fmt.Errorf("domain validation failed: %v", r)}
+		}
+	}()
+
+	result = validateDomain(ctx, s.domainValidationSvc, domain)
+	unknownPolicy.apply(&result)
+	checkDomainAge(s.domainAgeChecker, &result, domain)
+	return result
+}
 
-	return domainResults
+// emailJob is a single batch item together with its position in the input
+type emailJob struct {
+	index int
+	email string
 }
 
 func (s *BatchValidationService) processEmails(
+	ctx context.Context,
 	emails []string,
 	emailsByDomain map[string][]string,
-	domainResults map[string]struct {
-		DomainExists bool
-		MXRecords    bool
-		IsDisposable bool
-	},
+	domainResults map[string]DomainCheckResult,
+	opts ValidationOptions,
 ) model.BatchValidationResponse {
-	var response model.BatchValidationResponse
-	resultsMap := make(map[string]model.EmailValidationResponse)
-
-	jobs := make(chan string, len(emails))
+	jobs := make(chan emailJob, len(emails))
 	results := make(chan model.EmailValidationResponse, len(emails))
 
 	// Start workers
@@ -142,12 +236,12 @@ func (s *BatchValidationService) processEmails(
 	wg.Add(workerCount)
 
 	for i := 0; i < workerCount; i++ {
-		go s.emailValidationWorker(&wg, jobs, results, emailsByDomain, domainResults)
+		go s.emailValidationWorker(ctx, &wg, jobs, results, emailsByDomain, domainResults, opts)
 	}
 
 	// Send jobs
-	for _, email := range emails {
-		jobs <- email
+	for i, email := range emails {
+		jobs <- emailJob{index: i, email: email}
 	}
 	close(jobs)
 
@@ -157,46 +251,133 @@ func (s *BatchValidationService) processEmails(
 		close(results)
 	}()
 
-	// Collect results
-	for result := range results {
-		resultsMap[result.Email] = result
+	// Collect results into their input position, regardless of completion order, until
+	// every email is validated or ctx is done
+	response := model.BatchValidationResponse{
+		Results: make([]model.EmailValidationResponse, len(emails)),
+	}
+	done := make([]bool, len(emails))
+collect:
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				break collect
+			}
+			result.Fingerprint = Fingerprint(result)
+			response.Results[*result.Index] = result
+			done[*result.Index] = true
+			if result.Error != "" {
+				response.Errors++
+			}
+		case <-ctx.Done():
+			break collect
+		}
 	}
 
-	// Preserve original order
-	for _, email := range emails {
-		response.Results = append(response.Results, resultsMap[email])
+	for i, email := range emails {
+		if !done[i] {
+			response.Results[i] = notProcessedResponse(i, email)
+			response.NotProcessed++
+		}
 	}
+	return response
+}
 
+// notProcessedResponse builds the batch result for an email left unvalidated when the
+// batch deadline passed
+func notProcessedResponse(index int, email string) model.EmailValidationResponse {
+	response := model.EmailValidationResponse{
+		Email:       email,
+		Validations: model.ValidationResults{},
+		Status:      model.ValidationStatusNotProcessed,
+		ReasonCode:  model.ReasonTimeout,
+		Index:       &index,
+	}
+	setDomainForms(&response)
 	return response
 }
 
 func (s *BatchValidationService) emailValidationWorker(
+	ctx context.Context,
 	wg *sync.WaitGroup,
-	jobs <-chan string,
+	jobs <-chan emailJob,
 	results chan<- model.EmailValidationResponse,
 	emailsByDomain map[string][]string,
-	domainResults map[string]struct {
-		DomainExists bool
-		MXRecords    bool
-		IsDisposable bool
-	},
+	domainResults map[string]DomainCheckResult,
+	opts ValidationOptions,
 ) {
 	defer wg.Done()
 
-	for email := range jobs {
-		response := s.validateSingleEmail(email, domainResults)
+	pool, tracked := s.metricsCollector.(WorkerPoolMetrics)
+	if tracked {
+		pool.WorkerStarted()
+		defer pool.WorkerStopped()
+	}
+
+	for job := range jobs {
+		// Past the deadline, the remaining jobs are drained without being validated
+		if ctx.Err() != nil {
+			continue
+		}
+		response := s.validateJob(job, domainResults, opts, pool)
+		response.ValidatedAt = time.Now().UTC()
+		index := job.index
+		response.Index = &index
 		results <- response
 	}
 }
 
+// validateJob validates one batch item, reporting the worker as busy while it runs
+func (s *BatchValidationService) validateJob(
+	job emailJob,
+	domainResults map[string]DomainCheckResult,
+	opts ValidationOptions,
+	pool WorkerPoolMetrics,
+) model.EmailValidationResponse {
+	if pool != nil {
+		pool.WorkerBusy()
+		defer pool.WorkerIdle()
+	}
+	return s.safeValidateSingleEmail(job.email, domainResults, opts)
+}
+
+// safeValidateSingleEmail validates one batch item, converting a panic into an errored
+// result so a single failing email doesn't take down the whole batch
+func (s *BatchValidationService) safeValidateSingleEmail(
+	email string,
+	domainResults map[string]DomainCheckResult,
+	opts ValidationOptions,
+) (response model.EmailValidationResponse) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Validation of %s panicked: %v", email, r)
+			response = erroredResponse(email, fmt.Errorf("validation failed: %v", r))
+		}
+	}()
+
+	return s.validateSingleEmail(email, domainResults, opts)
+}
+
+// erroredResponse builds the batch result for an email that failed with an internal error
+func erroredResponse(email string, err error) model.EmailValidationResponse {
+	response := model.EmailValidationResponse{
+		Email:       email,
+		Validations: model.ValidationResults{},
+		Status:      model.ValidationStatusError,
+		ReasonCode:  model.ReasonInternalError,
+		Error:       err.Error(),
+	}
+	setDomainForms(&response)
+	return response
+}
+
 func (s *BatchValidationService) validateSingleEmail(
 	email string,
-	domainResults map[string]struct {
-		DomainExists bool
-		MXRecords    bool
-		IsDisposable bool
-	},
+	domainResults map[string]DomainCheckResult,
+	opts ValidationOptions,
 ) model.EmailValidationResponse {
+	disposablePolicy := opts.disposablePolicy(s.disposablePolicy)
 	response := model.EmailValidationResponse{
 		Email:       email,
 		Validations: model.ValidationResults{},
@@ -204,76 +385,213 @@ func (s *BatchValidationService) validateSingleEmail(
 
 	if email == "" {
 		response.Status = model.ValidationStatusMissingEmail
+		response.ReasonCode = model.ReasonMissingEmail
 		return response
 	}
+	setDomainForms(&response)
 
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
+	domain, ok := splitDomain(email)
+	if !ok {
 		response.Status = model.ValidationStatusInvalidFormat
+		response.ReasonCode = model.ReasonSyntaxInvalid
 		return response
 	}
 
-	domain := parts[1]
-	response.Validations.Syntax = s.emailRuleValidator.ValidateSyntax(email)
+	response.Validations.Syntax, response.Reason = checkSyntax(s.emailRuleValidator, email, opts.syntaxMode(s.syntaxMode))
 	if !response.Validations.Syntax {
 		response.Status = model.ValidationStatusInvalidFormat
+		response.ReasonCode = model.ReasonSyntaxInvalid
 		return response
 	}
 
 	// Get domain validation results
 	domainValidation := domainResults[domain]
+	if domainValidation.err != nil {
+		return erroredResponse(email, domainValidation.err)
+	}
+	response.Validations.UnknownTLD = domainValidation.UnknownTLD
 	response.Validations.DomainExists = domainValidation.DomainExists
 	response.Validations.MXRecords = domainValidation.MXRecords
+	setMXFlags(&response, domainValidation)
 	response.Validations.IsDisposable = domainValidation.IsDisposable
+	response.DisposableHeuristic = domainValidation.DisposableHeuristic
 	response.Validations.IsRoleBased = s.emailRuleValidator.IsRoleBased(email)
-	response.Validations.MailboxExists = response.Validations.MXRecords
+	response.Validations.IsFreeProvider = isFreeProvider(s.emailRuleValidator, email)
+	response.Inconclusive = domainValidation.Inconclusive
+	setDomainAge(&response, domainValidation.Age)
+	verifyMailbox(s.mailboxVerifier, &response, opts.unknownPolicy(s.unknownPolicy), s.greylistPolicy, nil)
+	response.RoleStatus = determineRoleStatus(&response)
+
+	// Suggestions and aliases are looked up without the trailing dot of a fully-qualified domain
+	address := validator.TrimTrailingDot(email)
 
 	// Always check for typo suggestions
-	suggestions := s.emailRuleValidator.GetTypoSuggestions(email)
+	suggestions := typoSuggestions(s.emailRuleValidator, address, opts)
 	if len(suggestions) > 0 {
 		response.TypoSuggestion = suggestions[0]
 	}
 
 	// Detect if email is an alias
-	if canonicalEmail := s.emailRuleValidator.DetectAlias(email); canonicalEmail != "" && canonicalEmail != email {
+	if canonicalEmail := s.emailRuleValidator.DetectAlias(address); canonicalEmail != "" && canonicalEmail != address {
 		response.AliasOf = canonicalEmail
 	}
+	response.Addressing = addressingCapabilities(s.emailRuleValidator, domain)
+	response.Canonical = canonicalize(s.emailRuleValidator, address)
+	response.SubaddressTag, response.HasSubaddress = detectSubaddress(s.emailRuleValidator, address)
 
 	// Calculate score
+	response.Score = calculateScore(s.emailRuleValidator, &response, disposablePolicy, s.confidencePenalties, nil)
+
+	// Record validation score
+	s.metricsCollector.RecordValidationScore("overall", float64(response.Score))
+
+	// Set status
+	response.Status = determineValidationStatus(&response, disposablePolicy, s.statusPrecedence)
+	response.ReasonCode = determineReasonCode(&response)
+	learnDomain(s.domainLearner, &response, domain)
+
+	return response
+}
+
+// SetMaxBatchSize sets the largest number of emails accepted in one batch; 0 removes the limit
+func (s *BatchValidationService) SetMaxBatchSize(size int) {
+	s.maxBatchSize = size
+}
+
+// SetWorkerCount sets how many addresses of a batch are validated at the same time. Zero
+// or less restores the default of four workers per CPU.
+func (s *BatchValidationService) SetWorkerCount(workers int) {
+	if workers <= 0 {
+		workers = defaultWorkerCount()
+	}
+	s.maxConcurrentWorkers = workers
+}
+
+// SetTimeout bounds how long validating a batch may take. Emails not validated by then
+// are reported as NOT_PROCESSED alongside the completed results. Zero, the default,
+// waits for every email.
+func (s *BatchValidationService) SetTimeout(timeout time.Duration) {
+	s.timeout = max(timeout, 0)
+}
+
+// SetDomainConcurrency sets how many distinct domains of a batch are checked at the same
+// time. Zero, the default, checks every domain at once.
+func (s *BatchValidationService) SetDomainConcurrency(domains int) {
+	s.domainConcurrency = max(domains, 0)
+}
+
+// SetSyntaxMode sets the syntax mode used when a request does not ask for one
+func (s *BatchValidationService) SetSyntaxMode(mode validator.SyntaxMode) {
+	s.syntaxMode = mode
+}
+
+// SetUnknownPolicy sets how inconclusive checks affect the final status and score
+func (s *BatchValidationService) SetUnknownPolicy(policy UnknownPolicy) {
+	s.unknownPolicy = policy
+}
+
+// SetGreylistPolicy sets the outcome of a mailbox check the server greylisted
+func (s *BatchValidationService) SetGreylistPolicy(policy GreylistPolicy) {
+	s.greylistPolicy = policy
+}
+
+// SetDisposablePolicy sets how disposable domains affect the final status and score
+func (s *BatchValidationService) SetDisposablePolicy(policy DisposablePolicy) {
+	s.disposablePolicy = policy
+}
+
+// SetStatusPrecedence sets which signal decides the status when a disposable domain, a
+// rejected mailbox and a catch-all server disagree
+func (s *BatchValidationService) SetStatusPrecedence(precedence StatusPrecedence) {
+	s.statusPrecedence = precedence
+}
+
+// SetMailboxVerifier sets the verifier that probes mailboxes once the domain is known to accept mail
+func (s *BatchValidationService) SetMailboxVerifier(verifier MailboxVerifier) {
+	s.mailboxVerifier = verifier
+}
+
+// SetDomainAgeChecker enables domain age checks; nil disables them
+func (s *BatchValidationService) SetDomainAgeChecker(checker DomainAgeChecker) {
+	s.domainAgeChecker = checker
+}
+
+// SetDomainLearner sets the learner told about every domain that validates as VALID
+func (s *BatchValidationService) SetDomainLearner(learner DomainLearner) {
+	s.domainLearner = learner
+}
+
+// SetConfidencePenalties sets how catch-all and greylisted mailbox checks limit the score
+func (s *BatchValidationService) SetConfidencePenalties(penalties ConfidencePenalties) {
+	s.confidencePenalties = penalties
+}
+
+// calculateScore scores the response's validations, applying the typo penalty and the
+// confidence ceilings for catch-all and greylisted mailboxes. When trace is set, the
+// computation is recorded in it.
+func calculateScore(
+	ruleValidator EmailRuleValidator,
+	response *model.EmailValidationResponse,
+	disposablePolicy DisposablePolicy,
+	penalties ConfidencePenalties,
+	trace *model.ValidationTrace,
+) int {
 	validationMap := map[string]bool{
 		"syntax":         response.Validations.Syntax,
 		"domain_exists":  response.Validations.DomainExists,
 		"mx_records":     response.Validations.MXRecords,
 		"mailbox_exists": response.Validations.MailboxExists,
-		"is_disposable":  response.Validations.IsDisposable,
+		"is_disposable":  response.Validations.IsDisposable && disposablePolicy.penalizesScore(),
 		"is_role_based":  response.Validations.IsRoleBased,
 	}
-	response.Score = s.emailRuleValidator.CalculateScore(validationMap)
+	score := ruleValidator.CalculateScore(validationMap)
+	if trace != nil {
+		trace.Score = &model.ScoreTrace{Inputs: validationMap, Base: score, Steps: []string{}}
+	}
 
 	// Reduce score if there's a typo suggestion
 	if response.TypoSuggestion != "" {
-		response.Score = max(0, response.Score-20) // Ensure score doesn't go below 0
+		score = max(0, score-20) // Ensure score doesn't go below 0
+		traceScoreStep(trace, score, "typo suggestion: -20")
 	}
 
-	// Record validation score
-	s.metricsCollector.RecordValidationScore("overall", float64(response.Score))
-
-	// Set status
-	response.Status = s.determineValidationStatus(&response)
+	// A parked domain rarely accepts mail, even when it publishes MX records
+	if response.Validations.IsParked {
+		score = max(0, score-30)
+		traceScoreStep(trace, score, "parked domain: -30")
+	}
 
-	return response
+	score = penalties.apply(score, response, trace)
+	score = applyRolePenalty(score, response.RoleStatus, trace)
+	if trace != nil {
+		trace.Score.Final = score
+	}
+	return score
 }
 
-func (s *BatchValidationService) determineValidationStatus(response *model.EmailValidationResponse) model.ValidationStatus {
+// determineValidationStatus derives the final status from the validation results, with
+// precedence deciding between the signals about the mailbox. It may override the score for
+// cases where the status dictates it.
+func determineValidationStatus(response *model.EmailValidationResponse, disposablePolicy DisposablePolicy, precedence StatusPrecedence) model.ValidationStatus {
 	switch {
+	case response.Validations.UnknownTLD:
+		return model.ValidationStatusUnknownTLD
 	case !response.Validations.DomainExists:
 		return model.ValidationStatusInvalidDomain
 	case !response.Validations.MXRecords:
 		response.Score = 40 // Override score for no MX records case
+		if response.Validations.IsParked {
+			// A parked domain without mail servers is all but certainly undeliverable
+			response.Score = 10
+		}
 		return model.ValidationStatusNoMXRecords
-	case response.Validations.IsDisposable:
-		return model.ValidationStatusDisposable
-	case response.Score >= 90:
+	}
+
+	if status, decided := precedence.resolve(response, disposablePolicy); decided {
+		return status
+	}
+	switch {
+	case response.Score >= 90 && len(response.Inconclusive) == 0:
 		return model.ValidationStatusValid
 	case response.Score >= 70:
 		return model.ValidationStatusProbablyValid
@@ -281,3 +599,53 @@ func (s *BatchValidationService) determineValidationStatus(response *model.Email
 		return model.ValidationStatusInvalid
 	}
 }
+
+// determineReasonCode returns the primary cause of a result that is not VALID, checking
+// causes from the most to the least decisive. It must run after the status is determined.
+func determineReasonCode(response *model.EmailValidationResponse) model.ReasonCode {
+	validations := response.Validations
+	switch {
+	case response.Status == model.ValidationStatusValid:
+		return ""
+	case validations.UnknownTLD:
+		return model.ReasonUnknownTLD
+	case slices.Contains(response.TimedOut, CheckDomainExists):
+		return model.ReasonTimeout
+	case slices.Contains(response.Inconclusive, CheckDomainExists) || slices.Contains(response.Inconclusive, CheckMXRecords):
+		return model.ReasonDNSTimeout
+	case validations.NullMX:
+		return model.ReasonNullMX
+	case response.RoleStatus == model.RoleStatusUndeliverable:
+		return model.ReasonRoleUndeliverable
+	case !validations.DomainExists:
+		return model.ReasonDomainNotFound
+	case validations.IsParked:
+		return model.ReasonParkedDomain
+	case !validations.MXRecords:
+		return model.ReasonNoMX
+	case response.Status == model.ValidationStatusDisposable:
+		return model.ReasonDisposable
+	case slices.Contains(response.Inconclusive, CheckIsDisposable) && !validations.IsDisposable:
+		return model.ReasonDisposableUnknown
+	case validations.IsGreylisted:
+		return model.ReasonGreylisted
+	case mailboxRejected(response):
+		return model.ReasonMailboxNotFound
+	case slices.Contains(response.TimedOut, CheckMailboxExists):
+		return model.ReasonTimeout
+	case slices.Contains(response.Inconclusive, CheckMailboxExists):
+		return model.ReasonMailboxUnverified
+	case response.RoleStatus == model.RoleStatusUnverified:
+		return model.ReasonRoleUnverified
+	case validations.IsCatchAll:
+		return model.ReasonCatchAll
+	case validations.IsDisposable:
+		return model.ReasonDisposable
+	case response.TypoSuggestion != "":
+		return model.ReasonPossibleTypo
+	case validations.IsRoleBased:
+		return model.ReasonRoleAccount
+	default:
+		return model.ReasonLowScore
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// CanonicalizeEmails returns the canonical form of every address, in order, for
+// deduplicating accounts offline. Only the syntax is checked; an address that fails it is
+// returned unchanged and flagged invalid. Distinct counts the valid addresses' canonical
+// forms ignoring case, as SameMailbox compares them.
+func (s *EmailService) CanonicalizeEmails(emails []string, opts ValidationOptions) model.CanonicalizeBatchResponse {
+	atomic.AddInt64(&s.requests, 1)
+	mode := opts.syntaxMode(s.syntaxMode)
+	response := model.CanonicalizeBatchResponse{Results: make([]model.CanonicalizeResult, len(emails))}
+	distinct := make(map[string]struct{})
+	for i, email := range emails {
+		result := model.CanonicalizeResult{Email: email, Canonical: email}
+		if valid, reason := checkSyntax(s.emailRuleValidator, email, mode); !valid {
+			result.Invalid, result.Reason = true, reason
+			response.Invalid++
+		} else {
+			result.Canonical = canonicalize(s.emailRuleValidator, validator.TrimTrailingDot(email))
+			distinct[strings.ToLower(result.Canonical)] = struct{}{}
+		}
+		response.Results[i] = result
+	}
+	response.Distinct = len(distinct)
+	return response
+}
+
+// BatchCanonicalize maps every address to its canonical form under the service's syntax
+// mode. Invalid addresses map to themselves; use CanonicalizeEmails to tell them apart.
+func (s *EmailService) BatchCanonicalize(emails []string) map[string]string {
+	canonical := make(map[string]string, len(emails))
+	for _, result := range s.CanonicalizeEmails(emails, ValidationOptions{}).Results {
+		canonical[result.Email] = result.Canonical
+	}
+	return canonical
+}
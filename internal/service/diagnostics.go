@@ -0,0 +1,29 @@
+package service
+
+import (
+	"time"
+
+	"emailvalidator/internal/model"
+)
+
+// domainDiagnostics reports whether the existence of domain will be answered from the
+// domain cache and how old the cached answer is. It returns nil if the validator cannot
+// tell, so the response carries no diagnostics rather than wrong ones.
+func domainDiagnostics(domainValidator DomainValidator, domain string) *model.ValidationDiagnostics {
+	inspector, ok := domainValidator.(DomainCacheInspector)
+	if !ok {
+		return nil
+	}
+	age, cached := inspector.DomainCacheAge(domain)
+	diagnostics := &model.ValidationDiagnostics{FromCache: cached}
+	if cached {
+		diagnostics.CacheAgeSeconds = wholeSeconds(age)
+	}
+	return diagnostics
+}
+
+// wholeSeconds returns d in whole seconds, rounded down
+func wholeSeconds(d time.Duration) *int {
+	seconds := int(d / time.Second)
+	return &seconds
+}
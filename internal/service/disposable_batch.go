@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/utils"
+	"emailvalidator/pkg/validator"
+)
+
+// CheckDisposableBatch checks every item, a domain or an address, for disposability against
+// the validator's disposable list and heuristics and, if lookup is not nil, against lookup.
+// Each distinct domain is checked once. Results are in the order of items.
+func (s *EmailService) CheckDisposableBatch(items []string, lookup DisposableLookup) model.DisposableBatchResponse {
+	atomic.AddInt64(&s.requests, 1)
+	return s.batchValidationSvc.CheckDisposable(items, lookup)
+}
+
+// CheckDisposable checks every item, a domain or an address, for disposability. Distinct
+// domains are checked concurrently by the batch worker pool; items whose domain cannot be
+// taken or is not a valid domain name are reported as errors.
+func (s *BatchValidationService) CheckDisposable(items []string, lookup DisposableLookup) model.DisposableBatchResponse {
+	response := model.DisposableBatchResponse{Results: make([]model.DisposableCheckResult, len(items))}
+	itemsByDomain := make(map[string][]int)
+	for i, item := range items {
+		response.Results[i] = model.DisposableCheckResult{Index: i, Input: item}
+		domain, err := s.disposableItemDomain(item)
+		if err != nil {
+			response.Results[i].Error = err.Error()
+			continue
+		}
+		response.Results[i].Domain = domain
+		itemsByDomain[domain] = append(itemsByDomain[domain], i)
+	}
+
+	for domain, result := range s.checkDisposableDomains(itemsByDomain, lookup) {
+		for _, i := range itemsByDomain[domain] {
+			item := &response.Results[i]
+			item.IsDisposable = result.IsDisposable
+			item.DisposableHeuristic = result.DisposableHeuristic
+			item.Inconclusive = result.Inconclusive
+			if result.err != nil {
+				item.Error = result.err.Error()
+			}
+		}
+	}
+	for _, result := range response.Results {
+		if result.Error != "" {
+			response.Errors++
+		}
+	}
+	return response
+}
+
+// disposableItemDomain returns the normalized domain of a bulk disposable check item: the
+// item itself, or the domain of an address
+func (s *BatchValidationService) disposableItemDomain(item string) (string, error) {
+	domain := strings.TrimSpace(item)
+	if domain == "" {
+		return "", errors.New("item is empty")
+	}
+	if strings.Contains(domain, "@") {
+		var ok bool
+		if domain, ok = splitDomain(domain); !ok {
+			return "", fmt.Errorf("%q is not a valid email address", item)
+		}
+	}
+
+	validateName := validator.ValidateDomainName
+	if names, ok := s.emailRuleValidator.(DomainNameValidator); ok {
+		validateName = names.ValidateDomainName
+	}
+	if err := validateName(validator.TrimTrailingDot(domain)); err != nil {
+		return "", err
+	}
+	return validator.NormalizeDomain(domain), nil
+}
+
+// checkDisposableDomains runs the disposable check on every domain of itemsByDomain with
+// the batch worker pool
+func (s *BatchValidationService) checkDisposableDomains(itemsByDomain map[string][]int, lookup DisposableLookup) map[string]DomainCheckResult {
+	ctx := context.Background()
+	results := make(map[string]DomainCheckResult, len(itemsByDomain))
+	if len(itemsByDomain) == 0 {
+		return results
+	}
+
+	domains := make(chan string, len(itemsByDomain))
+	for domain := range itemsByDomain {
+		domains <- domain
+	}
+	close(domains)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	workerCount := utils.MinInt(len(itemsByDomain), s.maxConcurrentWorkers)
+	wg.Add(workerCount)
+	for i := 0; i < workerCount; i++ {
+		go func() {
+			defer wg.Done()
+			pool, tracked := s.metricsCollector.(WorkerPoolMetrics)
+			if tracked {
+				pool.WorkerStarted()
+				defer pool.WorkerStopped()
+			}
+			for domain := range domains {
+				if tracked {
+					pool.WorkerBusy()
+				}
+				result := s.safeCheckDisposable(ctx, domain, lookup)
+				if tracked {
+					pool.WorkerIdle()
+				}
+				mu.Lock()
+				results[domain] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// safeCheckDisposable checks one domain for disposability, converting a panic into an
+// errored result so one failing domain doesn't take down the whole batch. While lookup is
+// still loading, a domain the other checks do not flag is reported as inconclusive.
+func (s *BatchValidationService) safeCheckDisposable(ctx context.Context, domain string, lookup DisposableLookup) (result DomainCheckResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Disposable check for %s panicked: %v", domain, r)
+			result = DomainCheckResult{err: fmt.Errorf("disposable check failed: %v", r)}
+		}
+	}()
+
+	if checker, ok := s.domainValidationSvc.(DisposableCheckService); ok {
+		result = checker.CheckDisposable(ctx, domain)
+	} else {
+		result = DomainCheckResult{IsDisposable: validateDomain(ctx, s.domainValidationSvc, domain).IsDisposable}
+	}
+	if lookup == nil || result.IsDisposable {
+		return result
+	}
+	disposable, ready := lookup.Lookup(domain)
+	if !ready {
+		result.Inconclusive = []string{CheckIsDisposable}
+		return result
+	}
+	result.IsDisposable = disposable
+	return result
+}
@@ -0,0 +1,43 @@
+package service
+
+import "fmt"
+
+// DisposablePolicy controls how a disposable domain affects the final status and score
+type DisposablePolicy string
+
+const (
+	// DisposablePolicyReject reports disposable addresses with the DISPOSABLE status regardless of
+	// score, so clients treat them as rejected. The disposable score weight is withheld. This is the default.
+	DisposablePolicyReject DisposablePolicy = "reject"
+	// DisposablePolicyFlag only sets validations.is_disposable. Neither the score nor the status
+	// is affected, so a disposable address can still be VALID.
+	DisposablePolicyFlag DisposablePolicy = "flag"
+	// DisposablePolicyScore withholds the disposable score weight but derives the status from the
+	// score as usual, so a disposable address is never forced to DISPOSABLE.
+	DisposablePolicyScore DisposablePolicy = "score"
+)
+
+// ParseDisposablePolicy converts a configuration string into a DisposablePolicy
+func ParseDisposablePolicy(value string) (DisposablePolicy, error) {
+	switch DisposablePolicy(value) {
+	case "", DisposablePolicyReject:
+		return DisposablePolicyReject, nil
+	case DisposablePolicyFlag:
+		return DisposablePolicyFlag, nil
+	case DisposablePolicyScore:
+		return DisposablePolicyScore, nil
+	default:
+		return "", fmt.Errorf("disposable policy %q: must be %q, %q or %q",
+			value, DisposablePolicyReject, DisposablePolicyFlag, DisposablePolicyScore)
+	}
+}
+
+// penalizesScore reports whether a disposable domain loses the disposable score weight
+func (p DisposablePolicy) penalizesScore() bool {
+	return p != DisposablePolicyFlag
+}
+
+// rejects reports whether a disposable domain forces the DISPOSABLE status
+func (p DisposablePolicy) rejects() bool {
+	return p == DisposablePolicyReject
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// DisposableSources explains the disposable verdict for item, a domain or an address: the
+// verdict itself, as CheckDisposableBatch reaches it, along with every list entry that
+// matches the domain and where it comes from, the allowlist entry that may override them,
+// the heuristic evidence and the disposable provider behind the domain's mail servers. It
+// fails when no valid domain can be taken from item.
+func (s *EmailService) DisposableSources(item string, lookup DisposableLookup) (model.DisposableSourceResponse, error) {
+	result := s.CheckDisposableBatch([]string{item}, lookup).Results[0]
+	if result.Error != "" {
+		return model.DisposableSourceResponse{}, errors.New(result.Error)
+	}
+
+	domain := result.Domain
+	response := model.DisposableSourceResponse{
+		Domain:              domain,
+		IsDisposable:        result.IsDisposable,
+		Sources:             []model.DisposableSourceMatch{},
+		DisposableHeuristic: result.DisposableHeuristic,
+		Inconclusive:        result.Inconclusive,
+	}
+	if reporter, ok := s.domainValidator.(DisposableSourceReporter); ok {
+		response.Sources = appendSourceMatches(response.Sources, reporter.DisposableSources(domain))
+		response.AllowlistEntry = reporter.DisposableAllowlistEntry(domain)
+	}
+	if sourceLookup, ok := lookup.(DisposableSourceLookup); ok {
+		if sources, ready := sourceLookup.Sources(domain); ready {
+			response.Sources = appendSourceMatches(response.Sources, sources)
+		}
+	}
+	if mxChecker, ok := s.domainValidator.(DisposableMXChecker); ok {
+		response.MXProvider, _ = mxChecker.DisposableMXProvider(domain)
+	}
+	return response, nil
+}
+
+// appendSourceMatches appends sources to matches in their response form
+func appendSourceMatches(matches []model.DisposableSourceMatch, sources []validator.DisposableSource) []model.DisposableSourceMatch {
+	for _, source := range sources {
+		matches = append(matches, model.DisposableSourceMatch{Source: source.Source, Entry: source.Entry, Match: source.Match.String()})
+	}
+	return matches
+}
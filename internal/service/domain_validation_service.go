@@ -3,11 +3,54 @@ package service
 import (
 	"context"
 	"sync"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// Names of the domain-level checks, as reported in DomainCheckResult.Inconclusive
+const (
+	CheckDomainExists = "domain_exists"
+	CheckMXRecords    = "mx_records"
+	CheckIsDisposable = "is_disposable"
 )
 
+// nullMXReason explains the status of a domain that publishes a null MX
+const nullMXReason = "The domain publishes a null MX record (RFC 7505) and accepts no mail"
+
+// DomainCheckResult holds the outcome of the domain-level checks for a single domain
+type DomainCheckResult struct {
+	// UnknownTLD means the domain's TLD does not exist; no DNS lookups were made
+	UnknownTLD   bool
+	DomainExists bool
+	MXRecords    bool
+	IsDisposable bool
+	// DisposableHeuristic is the heuristic evidence for a domain missing from the disposable
+	// lists; nil unless heuristics are enabled and a rule matched
+	DisposableHeuristic *model.DisposableHeuristic
+	// NullMX means the domain publishes a null MX (RFC 7505) and explicitly accepts no mail
+	NullMX bool
+	// IPLiteralMX means at least one MX record points at an IP address instead of a hostname
+	IPLiteralMX bool
+	// IsParked means the domain is delegated to the nameservers of a parking service
+	IsParked bool
+	// MXDomain is the parent domain that provided the MX records, when the domain has none
+	// of its own and MX walk-up is enabled
+	MXDomain string
+	// Inconclusive lists the checks that could not reach a verdict (e.g. DNS timeout)
+	Inconclusive []string
+	// Age is the domain's registration age; nil unless domain age checks are enabled and succeeded
+	Age *validator.DomainAge
+	// err is set when the domain checks failed internally; every email on the domain is reported as errored
+	err error
+}
+
 // ConcurrentDomainValidationService handles concurrent domain validation operations
 type ConcurrentDomainValidationService struct {
-	domainValidator DomainValidator
+	domainValidator    DomainValidator
+	disposableCache    DisposableResultCache
+	heuristicThreshold float64
+	sequential         bool
 }
 
 // NewConcurrentDomainValidationService creates a new instance of ConcurrentDomainValidationService
@@ -17,129 +60,235 @@ func NewConcurrentDomainValidationService(validator DomainValidator) *Concurrent
 	}
 }
 
+// SetDisposableCache sets the cache consulted before checking whether a domain is
+// disposable. Pass nil to check every time.
+func (s *ConcurrentDomainValidationService) SetDisposableCache(disposableCache DisposableResultCache) {
+	s.disposableCache = disposableCache
+}
+
+// SetDisposableHeuristicThreshold enables the disposable heuristics for domains missing
+// from the disposable lists: a domain whose heuristic score reaches threshold is treated
+// as disposable. Zero, the default, disables the heuristics.
+func (s *ConcurrentDomainValidationService) SetDisposableHeuristicThreshold(threshold float64) {
+	s.heuristicThreshold = threshold
+}
+
+// SetParallelChecks sets whether the existence, MX, disposable and parked checks of a
+// domain run concurrently, the default, or one after another. Running them concurrently
+// makes a domain's checks take as long as the slowest lookup instead of the sum of all of
+// them; sequential checks put less concurrent load on the DNS resolver.
+func (s *ConcurrentDomainValidationService) SetParallelChecks(parallel bool) {
+	s.sequential = !parallel
+}
+
 // ValidateDomainConcurrently runs domain validation checks concurrently
 func (s *ConcurrentDomainValidationService) ValidateDomainConcurrently(ctx context.Context, domain string) (exists, hasMX, isDisposable bool) {
+	result := s.ValidateDomainWithStatus(ctx, domain)
+	return result.DomainExists, result.MXRecords, result.IsDisposable
+}
+
+// ValidateDomainWithStatus runs domain validation checks concurrently and reports which checks were inconclusive.
+// If the validator does not implement DomainStatusValidator, every check is treated as conclusive.
+func (s *ConcurrentDomainValidationService) ValidateDomainWithStatus(ctx context.Context, domain string) DomainCheckResult {
 	// Check if context is already done before starting
 	select {
 	case <-ctx.Done():
-		return false, false, false
+		return DomainCheckResult{}
 	default:
 		// Continue with validation
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(3)
-
-	// Channel for collecting validation results
-	results := make(chan struct {
-		validationType string
-		isValid        bool
-	}, 3)
-
-	// Run domain existence check
-	go func() {
-		defer wg.Done()
-		select {
-		case <-ctx.Done():
-			results <- struct {
-				validationType string
-				isValid        bool
-			}{"domain_exists", false}
-		default:
-			isValid := s.domainValidator.ValidateDomain(domain)
-			// Check context again after validation
-			select {
-			case <-ctx.Done():
-				results <- struct {
-					validationType string
-					isValid        bool
-				}{"domain_exists", false}
-			default:
-				results <- struct {
-					validationType string
-					isValid        bool
-				}{"domain_exists", isValid}
+	// A domain under a TLD that does not exist cannot resolve, so skip the lookups
+	if tldChecker, ok := s.domainValidator.(TLDChecker); ok && !tldChecker.HasKnownTLD(domain) {
+		return DomainCheckResult{UnknownTLD: true}
+	}
+
+	statusValidator, hasStatus := s.domainValidator.(DomainStatusValidator)
+	mxChecker, hasMXChecker := s.domainValidator.(MXChecker)
+	parkedChecker, hasParkedChecker := s.domainValidator.(ParkedDomainChecker)
+
+	var (
+		result                                DomainCheckResult
+		existsInconclusive, hasMXInconclusive bool
+	)
+	// Each check writes only its own fields of result
+	checks := []func(){
+		// Domain existence
+		func() {
+			if hasStatus {
+				result.DomainExists, existsInconclusive = statusValidator.ValidateDomainStatus(domain)
+				return
 			}
-		}
-	}()
-
-	// Run MX records check
-	go func() {
-		defer wg.Done()
-		select {
-		case <-ctx.Done():
-			results <- struct {
-				validationType string
-				isValid        bool
-			}{"mx_records", false}
-		default:
-			isValid := s.domainValidator.ValidateMXRecords(domain)
-			// Check context again after validation
-			select {
-			case <-ctx.Done():
-				results <- struct {
-					validationType string
-					isValid        bool
-				}{"mx_records", false}
-			default:
-				results <- struct {
-					validationType string
-					isValid        bool
-				}{"mx_records", isValid}
+			result.DomainExists = s.domainValidator.ValidateDomain(domain)
+		},
+		// MX records
+		func() {
+			if hasMXChecker {
+				check := mxChecker.CheckMXRecords(domain)
+				result.MXRecords, hasMXInconclusive = check.HasMX, check.Inconclusive
+				result.NullMX, result.IPLiteralMX = check.NullMX, check.IPLiteralMX
+				result.MXDomain = check.MXDomain
+				return
 			}
-		}
-	}()
-
-	// Run disposable domain check
-	go func() {
-		defer wg.Done()
-		select {
-		case <-ctx.Done():
-			results <- struct {
-				validationType string
-				isValid        bool
-			}{"is_disposable", false}
-		default:
-			isValid := s.domainValidator.IsDisposable(domain)
-			// Check context again after validation
-			select {
-			case <-ctx.Done():
-				results <- struct {
-					validationType string
-					isValid        bool
-				}{"is_disposable", false}
-			default:
-				results <- struct {
-					validationType string
-					isValid        bool
-				}{"is_disposable", isValid}
+			if hasStatus {
+				result.MXRecords, hasMXInconclusive = statusValidator.ValidateMXRecordsStatus(domain)
+				return
 			}
-		}
-	}()
-
-	// Close results channel after all goroutines complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect validation results
-	for result := range results {
-		switch result.validationType {
-		case "domain_exists":
-			exists = result.isValid
-		case "mx_records":
-			hasMX = result.isValid
-		case "is_disposable":
-			isDisposable = result.isValid
-		}
+			result.MXRecords = s.domainValidator.ValidateMXRecords(domain)
+		},
+		// Disposable domain
+		func() {
+			result.IsDisposable = s.isDisposable(ctx, domain)
+			if !result.IsDisposable {
+				s.checkDisposableHeuristic(&result, domain)
+			}
+		},
+		// Parked domain
+		func() {
+			if hasParkedChecker {
+				result.IsParked = parkedChecker.IsParked(domain)
+			}
+		},
 	}
+	s.runChecks(checks)
 
-	// Final check if context was canceled
+	// Results computed after the context was canceled are discarded
 	select {
 	case <-ctx.Done():
-		return false, false, false
+		return DomainCheckResult{}
 	default:
-		return exists, hasMX, isDisposable
 	}
+
+	if existsInconclusive {
+		result.Inconclusive = append(result.Inconclusive, CheckDomainExists)
+	}
+	if hasMXInconclusive {
+		result.Inconclusive = append(result.Inconclusive, CheckMXRecords)
+	}
+	return result
+}
+
+// runChecks runs checks concurrently and waits for all of them, or runs them in order when
+// parallel checks are disabled
+func (s *ConcurrentDomainValidationService) runChecks(checks []func()) {
+	if s.sequential {
+		for _, check := range checks {
+			check()
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(checks))
+	for _, check := range checks {
+		go func(check func()) {
+			defer wg.Done()
+			check()
+		}(check)
+	}
+	wg.Wait()
+}
+
+// CheckDisposable runs only the disposable check on domain: the disposable lists, through
+// the disposable cache if one is set, then the heuristics when they are enabled
+func (s *ConcurrentDomainValidationService) CheckDisposable(ctx context.Context, domain string) DomainCheckResult {
+	var result DomainCheckResult
+	result.IsDisposable = s.isDisposable(ctx, domain)
+	if !result.IsDisposable {
+		s.checkDisposableHeuristic(&result, domain)
+	}
+	return result
+}
+
+// isDisposable checks whether domain is disposable, using the disposable cache if one is set
+func (s *ConcurrentDomainValidationService) isDisposable(ctx context.Context, domain string) bool {
+	if s.disposableCache == nil {
+		disposable, _ := s.checkDisposableLists(domain)
+		return disposable
+	}
+	if disposable, ok := s.disposableCache.Get(ctx, domain); ok {
+		return disposable
+	}
+	disposable, inconclusive := s.checkDisposableLists(domain)
+	if !inconclusive {
+		s.disposableCache.Set(ctx, domain, disposable)
+	}
+	return disposable
+}
+
+// checkDisposableLists checks domain against the disposable lists and, if the validator
+// supports it and the lists do not know the domain, whether its mail servers belong to a
+// disposable provider. inconclusive means the MX lookup failed without an answer; the
+// domain is then treated as not disposable, but the verdict should not be cached.
+func (s *ConcurrentDomainValidationService) checkDisposableLists(domain string) (disposable, inconclusive bool) {
+	if s.domainValidator.IsDisposable(domain) {
+		return true, false
+	}
+	mxChecker, ok := s.domainValidator.(DisposableMXChecker)
+	if !ok {
+		return false, false
+	}
+	provider, inconclusive := mxChecker.DisposableMXProvider(domain)
+	return provider != "", inconclusive
+}
+
+// checkDisposableHeuristic is the second tier of the disposable check, for domains the
+// lists do not know: it records the heuristic evidence and treats the domain as disposable
+// if the score reaches the threshold
+func (s *ConcurrentDomainValidationService) checkDisposableHeuristic(result *DomainCheckResult, domain string) {
+	scorer, ok := s.domainValidator.(DisposableHeuristicScorer)
+	if !ok || s.heuristicThreshold <= 0 {
+		return
+	}
+	score, rules := scorer.DisposableHeuristic(domain)
+	if score == 0 {
+		return
+	}
+	result.DisposableHeuristic = &model.DisposableHeuristic{Score: score, Rules: rules}
+	result.IsDisposable = score >= s.heuristicThreshold
+}
+
+// checkDomainAge records the registration age of an existing domain in result. Lookup
+// failures leave the age unset; the age is informational and never fails validation.
+func checkDomainAge(checker DomainAgeChecker, result *DomainCheckResult, domain string) {
+	if checker == nil || !result.DomainExists {
+		return
+	}
+	if age, err := checker.Check(domain); err == nil {
+		result.Age = &age
+	}
+}
+
+// validateDomain runs the domain-level checks, using status reporting when the service supports it
+func validateDomain(ctx context.Context, svc DomainValidationService, domain string) DomainCheckResult {
+	if statusSvc, ok := svc.(DomainStatusValidationService); ok {
+		return statusSvc.ValidateDomainWithStatus(ctx, domain)
+	}
+	exists, hasMX, isDisposable := svc.ValidateDomainConcurrently(ctx, domain)
+	return DomainCheckResult{
+		DomainExists: exists,
+		MXRecords:    hasMX,
+		IsDisposable: isDisposable,
+	}
+}
+
+// setMXFlags copies the MX record classification and the parked flag into the response,
+// explaining a null MX
+func setMXFlags(response *model.EmailValidationResponse, result DomainCheckResult) {
+	response.Validations.NullMX = result.NullMX
+	response.Validations.IPLiteralMX = result.IPLiteralMX
+	response.Validations.IsParked = result.IsParked
+	response.MXDomain = result.MXDomain
+	if result.NullMX {
+		response.Reason = nullMXReason
+	}
+}
+
+// setDomainAge copies a domain age result into the response
+func setDomainAge(response *model.EmailValidationResponse, age *validator.DomainAge) {
+	if age == nil {
+		return
+	}
+	days := age.Days
+	response.DomainAgeDays = &days
+	response.IsNewDomain = age.IsNew
 }
@@ -4,12 +4,15 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"runtime"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"emailvalidator/internal/model"
+	"emailvalidator/pkg/events"
 	"emailvalidator/pkg/validator"
 )
 
@@ -20,17 +23,93 @@ type EmailService struct {
 	domainValidationSvc DomainValidationService
 	batchValidationSvc  *BatchValidationService
 	metricsCollector    MetricsCollector
+	eventSink           EventSink
+	mailboxVerifier     MailboxVerifier
+	domainAgeChecker    DomainAgeChecker
+	domainLearner       DomainLearner
+	unknownPolicy       UnknownPolicy
+	greylistPolicy      GreylistPolicy
+	disposablePolicy    DisposablePolicy
+	statusPrecedence    StatusPrecedence
+	heuristicThreshold  float64
+	syntaxMode          validator.SyntaxMode
+	scoreScale          ScoreScale
+	confidencePenalties ConfidencePenalties
+	revalidationPolicy  RevalidationPolicy
+	resultCache         *ResultCache
+	postProcessors      []ResultPostProcessor
+	validationTimeout   time.Duration
 	startTime           time.Time
 	requests            int64
 }
 
+// ValidationOptions holds per-request overrides of service-level settings.
+// Zero values fall back to the service configuration.
+type ValidationOptions struct {
+	DisposablePolicy DisposablePolicy
+	// UnknownPolicy, when set, overrides how inconclusive checks affect the status and score
+	UnknownPolicy UnknownPolicy
+	// MinSuggestionConfidence, when set, overrides the rule validator's minimum confidence
+	// for typo suggestions
+	MinSuggestionConfidence *float64
+	// TypoRegions are the regions whose typo dictionaries are consulted before the global
+	// one, most preferred first; the first with a dictionary is used
+	TypoRegions []string
+	// SyntaxMode, when set, overrides the service's syntax mode
+	SyntaxMode validator.SyntaxMode
+	// ScoreScale, when set, overrides the scale responses present the score on. It does not
+	// affect validation itself; see EmailService.ScoreScaleFor.
+	ScoreScale ScoreScale
+	// Autocorrect makes batch validation substitute each typo suggestion whose confidence is
+	// at least AutocorrectConfidence and re-validate the corrected address
+	Autocorrect bool
+	// AutocorrectConfidence, when set, overrides DefaultAutocorrectConfidence
+	AutocorrectConfidence float64
+	// SkipRevalidation makes Autocorrect report corrections without validating the corrected addresses
+	SkipRevalidation bool
+	// Trace, when set, receives every intermediate signal of a single-email validation.
+	// Tracing makes extra DNS lookups and records the SMTP conversation, so it is meant for debugging.
+	Trace *model.ValidationTrace
+	// Diagnostics adds to a single-email result whether its domain data came from the domain
+	// cache and how old it is
+	Diagnostics bool
+}
+
+// disposablePolicy returns the requested disposable policy, or def if none was requested
+func (o ValidationOptions) disposablePolicy(def DisposablePolicy) DisposablePolicy {
+	if o.DisposablePolicy != "" {
+		return o.DisposablePolicy
+	}
+	return def
+}
+
+// unknownPolicy returns the requested unknown policy, or def if none was requested
+func (o ValidationOptions) unknownPolicy(def UnknownPolicy) UnknownPolicy {
+	if o.UnknownPolicy != "" {
+		return o.UnknownPolicy
+	}
+	return def
+}
+
+// syntaxMode returns the requested syntax mode, or def if none was requested
+func (o ValidationOptions) syntaxMode(def validator.SyntaxMode) validator.SyntaxMode {
+	if o.SyntaxMode != "" {
+		return o.SyntaxMode
+	}
+	return def
+}
+
 // NewEmailService creates a new instance of EmailService
 func NewEmailService() (*EmailService, error) {
 	emailValidator, err := validator.NewEmailValidator()
 	if err != nil {
 		return nil, err
 	}
+	return NewEmailServiceWithValidator(emailValidator), nil
+}
 
+// NewEmailServiceWithValidator creates a new instance of EmailService backed by a preconfigured validator
+func NewEmailServiceWithValidator(emailValidator *validator.EmailValidator) *EmailService {
 	metricsAdapter := NewMetricsAdapter()
 	domainValidationSvc := NewConcurrentDomainValidationService(emailValidator)
 	batchValidationSvc := NewBatchValidationService(emailValidator, domainValidationSvc, metricsAdapter)
@@ -41,8 +120,14 @@ func NewEmailService() (*EmailService, error) {
 		domainValidationSvc: domainValidationSvc,
 		batchValidationSvc:  batchValidationSvc,
 		metricsCollector:    metricsAdapter,
+		unknownPolicy:       UnknownPolicyStrict,
+		greylistPolicy:      GreylistPolicyUncertain,
+		disposablePolicy:    DisposablePolicyReject,
+		syntaxMode:          validator.SyntaxModeLenient,
+		confidencePenalties: DefaultConfidencePenalties(),
+		revalidationPolicy:  DefaultRevalidationPolicy(),
 		startTime:           time.Now(),
-	}, nil
+	}
 }
 
 // NewEmailServiceWithDeps creates a new instance of EmailService with custom dependencies
@@ -70,13 +155,72 @@ func NewEmailServiceWithDeps(validator interface{}) *EmailService {
 		domainValidationSvc: domainValidationSvc,
 		batchValidationSvc:  batchValidationSvc,
 		metricsCollector:    metricsAdapter,
+		unknownPolicy:       UnknownPolicyStrict,
+		greylistPolicy:      GreylistPolicyUncertain,
+		disposablePolicy:    DisposablePolicyReject,
+		confidencePenalties: DefaultConfidencePenalties(),
+		revalidationPolicy:  DefaultRevalidationPolicy(),
 		startTime:           time.Now(),
 	}
 }
 
 // ValidateEmail performs all validation checks on a single email
 func (s *EmailService) ValidateEmail(email string) model.EmailValidationResponse {
+	return s.ValidateEmailWithOptions(email, ValidationOptions{})
+}
+
+// ValidateEmailWithOptions performs all validation checks on a single email,
+// applying per-request overrides from opts
+func (s *EmailService) ValidateEmailWithOptions(email string, opts ValidationOptions) model.EmailValidationResponse {
 	atomic.AddInt64(&s.requests, 1)
+	// Traced validations must run every check, and diagnostics describe the domain data of
+	// this validation, so both bypass the cache
+	if s.resultCache == nil || opts.Trace != nil || opts.Diagnostics {
+		response := s.validateEmail(email, opts)
+		response.ValidatedAt = time.Now().UTC()
+		s.postProcess(&response, opts.Trace)
+		s.stampRevalidation(&response)
+		response.Fingerprint = Fingerprint(response)
+		s.emitEvent(response)
+		return response
+	}
+
+	checks := s.checkSet(opts)
+	response, ok := s.resultCache.Get(email, checks)
+	if !ok {
+		response = s.validateEmail(email, opts)
+		response.ValidatedAt = time.Now().UTC()
+		s.resultCache.Set(email, checks, response)
+	}
+	// Cached results are stored before post-processing, so changing the processors never
+	// serves a result processed under the old ones
+	s.postProcess(&response, nil)
+	s.stampRevalidation(&response)
+	response.Fingerprint = Fingerprint(response)
+	s.emitEvent(response)
+	return response
+}
+
+// checkSet returns the checks and scoring configuration a validation with opts runs under
+func (s *EmailService) checkSet(opts ValidationOptions) CheckSet {
+	return CheckSet{
+		Mailbox:                 s.mailboxVerifier != nil,
+		DomainAge:               s.domainAgeChecker != nil,
+		UnknownPolicy:           opts.unknownPolicy(s.unknownPolicy),
+		GreylistPolicy:          s.greylistPolicy,
+		DisposablePolicy:        opts.disposablePolicy(s.disposablePolicy),
+		HeuristicThreshold:      s.heuristicThreshold,
+		MinSuggestionConfidence: opts.MinSuggestionConfidence,
+		TypoRegions:             strings.Join(opts.TypoRegions, ","),
+		SyntaxMode:              opts.syntaxMode(s.syntaxMode),
+		Penalties:               s.confidencePenalties,
+		StatusPrecedence:        s.statusPrecedence.String(),
+		ScoringVersion:          ScoringVersion,
+	}
+}
+
+func (s *EmailService) validateEmail(email string, opts ValidationOptions) model.EmailValidationResponse {
+	disposablePolicy := opts.disposablePolicy(s.disposablePolicy)
 
 	response := model.EmailValidationResponse{
 		Email:       email,
@@ -85,103 +229,345 @@ func (s *EmailService) ValidateEmail(email string) model.EmailValidationResponse
 
 	if email == "" {
 		response.Status = model.ValidationStatusMissingEmail
+		response.ReasonCode = model.ReasonMissingEmail
 		return response
 	}
+	setDomainForms(&response)
 
 	// Validate syntax first
-	response.Validations.Syntax = s.emailRuleValidator.ValidateSyntax(email)
+	start := time.Now()
+	response.Validations.Syntax, response.Reason = checkSyntax(s.emailRuleValidator, email, opts.syntaxMode(s.syntaxMode))
+	recordTiming(opts.Trace, "syntax", start)
 	if !response.Validations.Syntax {
 		response.Status = model.ValidationStatusInvalidFormat
+		response.ReasonCode = model.ReasonSyntaxInvalid
 		return response
 	}
 
 	// Extract domain and validate
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
+	domain, ok := splitDomain(email)
+	if !ok {
 		response.Status = model.ValidationStatusInvalidFormat
+		response.ReasonCode = model.ReasonSyntaxInvalid
 		return response
 	}
-	domain := parts[1]
+
+	// Inspect first so the trace and diagnostics show the cache state this validation saw
+	inspectDomain(s.domainValidator, opts.Trace, domain)
+	if opts.Diagnostics {
+		response.Diagnostics = domainDiagnostics(s.domainValidator, domain)
+	}
+
+	// Traced validations run every check to completion, since the trace is written as they go
+	ctx := context.Background()
+	if s.validationTimeout > 0 && opts.Trace == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.validationTimeout)
+		defer cancel()
+	}
+	var timedOut []string
 
 	// Perform domain validations concurrently
-	exists, hasMX, isDisposable := s.domainValidationSvc.ValidateDomainConcurrently(context.Background(), domain)
+	start = time.Now()
+	domainResult, ok := runStage(ctx, func() DomainCheckResult {
+		return validateDomain(ctx, s.domainValidationSvc, domain)
+	})
+	if !ok {
+		timedOut = []string{CheckDomainExists, CheckMXRecords, CheckIsDisposable}
+		domainResult = DomainCheckResult{Inconclusive: timedOut}
+	}
+	unknownPolicy := opts.unknownPolicy(s.unknownPolicy)
+	unknownPolicy.apply(&domainResult)
+	recordTiming(opts.Trace, "domain", start)
+	// The domain age is informational, so it is simply omitted if it does not finish in time
+	start = time.Now()
+	withAge := domainResult
+	if aged, ok := runStage(ctx, func() DomainCheckResult {
+		checkDomainAge(s.domainAgeChecker, &withAge, domain)
+		return withAge
+	}); ok {
+		domainResult = aged
+	}
+	recordTiming(opts.Trace, "domain_age", start)
 
 	// Set validation results
-	response.Validations.DomainExists = exists
-	response.Validations.MXRecords = hasMX
-	response.Validations.IsDisposable = isDisposable
+	response.Validations.UnknownTLD = domainResult.UnknownTLD
+	response.Validations.DomainExists = domainResult.DomainExists
+	response.Validations.MXRecords = domainResult.MXRecords
+	setMXFlags(&response, domainResult)
+	response.Validations.IsDisposable = domainResult.IsDisposable
+	response.DisposableHeuristic = domainResult.DisposableHeuristic
 	response.Validations.IsRoleBased = s.emailRuleValidator.IsRoleBased(email)
-	response.Validations.MailboxExists = hasMX
+	response.Validations.IsFreeProvider = isFreeProvider(s.emailRuleValidator, email)
+	response.Inconclusive = domainResult.Inconclusive
+	setDomainAge(&response, domainResult.Age)
+	markTimedOut(&response, timedOut...)
+	start = time.Now()
+	s.verifyMailboxWithin(ctx, &response, unknownPolicy, opts.Trace)
+	recordTiming(opts.Trace, "mailbox", start)
+	response.RoleStatus = determineRoleStatus(&response)
+
+	// Suggestions and aliases are looked up without the trailing dot of a fully-qualified domain
+	address := validator.TrimTrailingDot(email)
 
 	// Always check for typo suggestions
-	suggestions := s.emailRuleValidator.GetTypoSuggestions(email)
+	start = time.Now()
+	suggestions := typoSuggestions(s.emailRuleValidator, address, opts)
 	if len(suggestions) > 0 {
 		response.TypoSuggestion = suggestions[0]
 	}
+	recordTiming(opts.Trace, "typo_suggestions", start)
 
 	// Detect if email is an alias
-	if canonicalEmail := s.emailRuleValidator.DetectAlias(email); canonicalEmail != "" && canonicalEmail != email {
+	if canonicalEmail := s.emailRuleValidator.DetectAlias(address); canonicalEmail != "" && canonicalEmail != address {
 		response.AliasOf = canonicalEmail
 	}
+	response.Addressing = addressingCapabilities(s.emailRuleValidator, domain)
+	response.Canonical = canonicalize(s.emailRuleValidator, address)
+	response.SubaddressTag, response.HasSubaddress = detectSubaddress(s.emailRuleValidator, address)
 
 	// Calculate score
-	validationMap := map[string]bool{
-		"syntax":         response.Validations.Syntax,
-		"domain_exists":  response.Validations.DomainExists,
-		"mx_records":     response.Validations.MXRecords,
-		"mailbox_exists": response.Validations.MailboxExists,
-		"is_disposable":  response.Validations.IsDisposable,
-		"is_role_based":  response.Validations.IsRoleBased,
-	}
-	response.Score = s.emailRuleValidator.CalculateScore(validationMap)
-
-	// Reduce score if there's a typo suggestion
-	if response.TypoSuggestion != "" {
-		response.Score = max(0, response.Score-20) // Ensure score doesn't go below 0
-	}
+	response.Score = calculateScore(s.emailRuleValidator, &response, disposablePolicy, s.confidencePenalties, opts.Trace)
 
 	// Record validation score
 	s.metricsCollector.RecordValidationScore("overall", float64(response.Score))
 
 	// Set status based on validations
-	switch {
-	case !response.Validations.DomainExists:
-		response.Status = model.ValidationStatusInvalidDomain
-	case !response.Validations.MXRecords:
-		response.Status = model.ValidationStatusNoMXRecords
-		response.Score = 40 // Override score for no MX records case
-	case response.Validations.IsDisposable:
-		response.Status = model.ValidationStatusDisposable
-	case response.Score >= 90:
-		response.Status = model.ValidationStatusValid
-	case response.Score >= 70:
-		response.Status = model.ValidationStatusProbablyValid
-	default:
-		response.Status = model.ValidationStatusInvalid
-	}
+	response.Status = determineValidationStatus(&response, disposablePolicy, s.statusPrecedence)
+	response.ReasonCode = determineReasonCode(&response)
+	traceStatusOverride(opts.Trace, &response)
+	learnDomain(s.domainLearner, &response, domain)
 
 	return response
 }
 
+// verifyMailboxWithin runs the mailbox check, recording it as timed out if the probe does
+// not finish before ctx is done
+func (s *EmailService) verifyMailboxWithin(ctx context.Context, response *model.EmailValidationResponse, unknownPolicy UnknownPolicy, trace *model.ValidationTrace) {
+	// Without a probe to make there is nothing to wait for
+	if s.mailboxVerifier == nil || !response.Validations.MXRecords {
+		verifyMailbox(s.mailboxVerifier, response, unknownPolicy, s.greylistPolicy, trace)
+		return
+	}
+
+	// The probe works on a copy, since it may still be running after the deadline
+	result := *response
+	verified, ok := runStage(ctx, func() model.EmailValidationResponse {
+		verifyMailbox(s.mailboxVerifier, &result, unknownPolicy, s.greylistPolicy, trace)
+		return result
+	})
+	if ok {
+		*response = verified
+		return
+	}
+	response.Validations.MailboxExists = unknownPolicy == UnknownPolicyLenient
+	markTimedOut(response, CheckMailboxExists)
+}
+
 // ValidateEmails performs validation on multiple email addresses concurrently
 func (s *EmailService) ValidateEmails(emails []string) model.BatchValidationResponse {
+	return s.ValidateEmailsWithOptions(emails, ValidationOptions{})
+}
+
+// ValidateEmailsWithOptions performs validation on multiple email addresses concurrently,
+// applying per-request overrides from opts
+func (s *EmailService) ValidateEmailsWithOptions(emails []string, opts ValidationOptions) model.BatchValidationResponse {
+	return s.ValidateEmailsContext(context.Background(), emails, opts)
+}
+
+// ValidateEmailsContext performs validation on multiple email addresses concurrently,
+// applying per-request overrides from opts. When ctx is done or the batch timeout passes,
+// the results completed so far are returned, and the other emails are reported as
+// NOT_PROCESSED.
+func (s *EmailService) ValidateEmailsContext(ctx context.Context, emails []string, opts ValidationOptions) model.BatchValidationResponse {
 	atomic.AddInt64(&s.requests, 1)
-	return s.batchValidationSvc.ValidateEmails(emails)
+	response := s.batchValidationSvc.ValidateEmailsContext(ctx, emails, opts)
+	s.postProcessBatch(&response)
+	for i := range response.Results {
+		s.stampRevalidation(&response.Results[i])
+	}
+	if opts.Autocorrect {
+		s.autocorrect(ctx, &response, opts)
+	}
+	response.Summary = summarizeBatch(response.Results)
+	for _, result := range response.Results {
+		if result.Error != "" || result.Status == model.ValidationStatusNotProcessed {
+			continue
+		}
+		s.emitEvent(result)
+	}
+	return response
+}
+
+// emitEvent publishes a finalized result to the event sink, if one is configured
+func (s *EmailService) emitEvent(response model.EmailValidationResponse) {
+	if s.eventSink == nil {
+		return
+	}
+	s.eventSink.Emit(events.NewEvent(response.Email, string(response.Status), response.Score))
+}
+
+// learnDomain tells learner about domain if the response is VALID and not disposable
+func learnDomain(learner DomainLearner, response *model.EmailValidationResponse, domain string) {
+	if learner != nil && response.Status == model.ValidationStatusValid && !response.Validations.IsDisposable {
+		learner.Learn(domain)
+	}
+}
+
+// MarkDisposable flags a previously validated response as disposable and recomputes
+// its score and status under the effective disposable policy
+func (s *EmailService) MarkDisposable(response *model.EmailValidationResponse, opts ValidationOptions) {
+	response.Validations.IsDisposable = true
+	s.rescore(response, opts)
+}
+
+// MarkDisposableUnknown records that whether a previously validated response is disposable
+// could not be determined, e.g. because the blocklist has not loaded yet. The response is
+// not flagged disposable, but like any inconclusive result it is capped at PROBABLY_VALID.
+// Under the strict unknown policy the score also carries the disposable penalty.
+func (s *EmailService) MarkDisposableUnknown(response *model.EmailValidationResponse, opts ValidationOptions) {
+	if slices.Contains(response.Inconclusive, CheckIsDisposable) {
+		return
+	}
+	inconclusive := response.Inconclusive[:len(response.Inconclusive):len(response.Inconclusive)]
+	response.Inconclusive = append(inconclusive, CheckIsDisposable)
+	s.rescore(response, opts)
+}
+
+// rescore recomputes the score, status and everything derived from them after the
+// validations of a previously validated response changed, running the post-processors
+// again as validation does
+func (s *EmailService) rescore(response *model.EmailValidationResponse, opts ValidationOptions) {
+	disposablePolicy := opts.disposablePolicy(s.disposablePolicy)
+	scored := response
+	if opts.unknownPolicy(s.unknownPolicy) != UnknownPolicyLenient && slices.Contains(response.Inconclusive, CheckIsDisposable) {
+		// Strict counts the unknown disposable check as failed in the score only
+		failed := *response
+		failed.Validations.IsDisposable = true
+		scored = &failed
+	}
+	response.Score = calculateScore(s.emailRuleValidator, scored, disposablePolicy, s.confidencePenalties, opts.Trace)
+	response.Status = determineValidationStatus(response, disposablePolicy, s.statusPrecedence)
+	response.ReasonCode = determineReasonCode(response)
+	s.postProcess(response, opts.Trace)
+	s.stampRevalidation(response)
+	response.Fingerprint = Fingerprint(*response)
 }
 
 // GetTypoSuggestions returns suggestions for possible email typos
 func (s *EmailService) GetTypoSuggestions(email string) model.TypoSuggestionResponse {
+	return s.GetTypoSuggestionsWithOptions(email, ValidationOptions{})
+}
+
+// GetTypoSuggestionsWithOptions returns suggestions for possible email typos, applying
+// per-request overrides from opts
+func (s *EmailService) GetTypoSuggestionsWithOptions(email string, opts ValidationOptions) model.TypoSuggestionResponse {
 	atomic.AddInt64(&s.requests, 1)
-	suggestions := s.emailRuleValidator.GetTypoSuggestions(email)
+	suggestions := typoSuggestions(s.emailRuleValidator, email, opts)
 	response := model.TypoSuggestionResponse{
 		Email: email,
 	}
 	if len(suggestions) > 0 {
 		response.TypoSuggestion = suggestions[0]
+		response.Confidence = validator.SuggestionConfidence(domainOf(email), domainOf(suggestions[0]))
 	}
 	return response
 }
 
+// AcceptTypoSuggestion records that a user took suggestion for email by teaching the domain
+// learner the suggested domain, so it is suggested more readily. The suggestion must be one
+// the service makes for email at some confidence, so clients cannot teach it arbitrary domains.
+func (s *EmailService) AcceptTypoSuggestion(email, suggestion string) error {
+	anyConfidence := 0.0
+	for _, candidate := range typoSuggestions(s.emailRuleValidator, email, ValidationOptions{MinSuggestionConfidence: &anyConfidence}) {
+		if strings.EqualFold(candidate, suggestion) {
+			if s.domainLearner != nil {
+				s.domainLearner.Learn(strings.ToLower(domainOf(candidate)))
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a typo suggestion for %q", suggestion, email)
+}
+
+// isFreeProvider reports whether email is at a free mailbox provider, when the rule validator
+// can tell
+func isFreeProvider(ruleValidator EmailRuleValidator, email string) bool {
+	detector, ok := ruleValidator.(FreeProviderDetector)
+	return ok && detector.IsFreeProvider(email)
+}
+
+// typoSuggestions returns the rule validator's typo suggestions for email, applying the
+// requested regions and minimum confidence when the validator supports them
+func typoSuggestions(ruleValidator EmailRuleValidator, email string, opts ValidationOptions) []string {
+	if suggester, ok := ruleValidator.(RegionalTypoSuggester); ok && len(opts.TypoRegions) > 0 {
+		region := suggester.TypoRegion(opts.TypoRegions...)
+		if opts.MinSuggestionConfidence != nil {
+			return suggester.GetTypoSuggestionsForRegionWithConfidence(email, region, *opts.MinSuggestionConfidence)
+		}
+		return suggester.GetTypoSuggestionsForRegion(email, region)
+	}
+	if opts.MinSuggestionConfidence != nil {
+		if suggester, ok := ruleValidator.(ConfidentTypoSuggester); ok {
+			return suggester.GetTypoSuggestionsWithConfidence(email, *opts.MinSuggestionConfidence)
+		}
+	}
+	return ruleValidator.GetTypoSuggestions(email)
+}
+
+// checkSyntax validates the syntax of email under mode. It returns the reason an invalid
+// address failed, when the rule validator can explain it.
+func checkSyntax(ruleValidator EmailRuleValidator, email string, mode validator.SyntaxMode) (bool, string) {
+	if modeValidator, ok := ruleValidator.(SyntaxModeValidator); ok {
+		if modeValidator.ValidateSyntaxMode(email, mode) {
+			return true, ""
+		}
+		return false, modeValidator.ExplainSyntaxMode(email, mode)
+	}
+	if ruleValidator.ValidateSyntax(email) {
+		return true, ""
+	}
+	if explainer, ok := ruleValidator.(SyntaxExplainer); ok {
+		return false, explainer.ExplainSyntax(email)
+	}
+	return false, ""
+}
+
+// splitDomain returns the domain of email, ignoring RFC 5322 comments and the trailing dot
+// of a fully-qualified domain. It reports false if email has no "@", or an "@" outside a
+// quoted local part.
+func splitDomain(email string) (string, bool) {
+	address := validator.StripCFWS(email)
+	at := strings.LastIndex(address, "@")
+	if at <= 0 {
+		return "", false
+	}
+	if localPart := address[:at]; strings.Contains(localPart, "@") && !strings.HasPrefix(localPart, `"`) {
+		return "", false
+	}
+	return validator.TrimTrailingDot(address[at+1:]), true
+}
+
+// domainForms returns the domain of email as typed, keeping any trailing dot, and
+// normalized. ok is false if email has no domain.
+func domainForms(email string) (original, normalized string, ok bool) {
+	if _, ok := splitDomain(email); !ok {
+		return "", "", false
+	}
+	original = domainOf(validator.StripCFWS(email))
+	return original, validator.NormalizeDomain(original), true
+}
+
+// setDomainForms sets the original and normalized domain of response from its email
+func setDomainForms(response *model.EmailValidationResponse) {
+	response.DomainOriginal, response.DomainNormalized, _ = domainForms(response.Email)
+}
+
+// domainOf returns the part of email after the last "@"
+func domainOf(email string) string {
+	return email[strings.LastIndex(email, "@")+1:]
+}
+
 // GetAPIStatus returns the current status of the API
 func (s *EmailService) GetAPIStatus() model.APIStatus {
 	uptime := time.Since(s.startTime)
@@ -199,6 +585,182 @@ func (s *EmailService) GetAPIStatus() model.APIStatus {
 	}
 }
 
+// CheckBatchSize returns a *BatchTooLargeError if a batch of size emails exceeds the configured maximum
+func (s *EmailService) CheckBatchSize(size int) error {
+	if s.batchValidationSvc == nil {
+		return nil
+	}
+	return s.batchValidationSvc.CheckBatchSize(size)
+}
+
+// SetMaxBatchSize sets the largest number of emails accepted in one batch; 0 removes the limit
+func (s *EmailService) SetMaxBatchSize(size int) {
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetMaxBatchSize(size)
+	}
+}
+
+// SetBatchWorkers sets how many addresses of a batch are validated at the same time. Zero
+// or less restores the default of four workers per CPU.
+func (s *EmailService) SetBatchWorkers(workers int) {
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetWorkerCount(workers)
+	}
+}
+
+// SetBatchDomainConcurrency sets how many distinct domains of a batch are checked at the
+// same time. Zero, the default, checks every domain at once.
+func (s *EmailService) SetBatchDomainConcurrency(domains int) {
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetDomainConcurrency(domains)
+	}
+}
+
+// SetBatchTimeout bounds how long validating a batch may take. Emails not validated by
+// then are reported as NOT_PROCESSED alongside the completed results. Zero, the default,
+// waits for every email.
+func (s *EmailService) SetBatchTimeout(timeout time.Duration) {
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetTimeout(timeout)
+	}
+}
+
+// SetUnknownPolicy sets how inconclusive checks affect the final status and score
+func (s *EmailService) SetUnknownPolicy(policy UnknownPolicy) {
+	s.unknownPolicy = policy
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetUnknownPolicy(policy)
+	}
+}
+
+// SetGreylistPolicy sets the outcome of a mailbox check the server greylisted
+func (s *EmailService) SetGreylistPolicy(policy GreylistPolicy) {
+	s.greylistPolicy = policy
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetGreylistPolicy(policy)
+	}
+}
+
+// SetDisposablePolicy sets how disposable domains affect the final status and score.
+// Requests can override it through ValidationOptions.
+func (s *EmailService) SetDisposablePolicy(policy DisposablePolicy) {
+	s.disposablePolicy = policy
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetDisposablePolicy(policy)
+	}
+}
+
+// SetStatusPrecedence sets which signal decides the status when a disposable domain, a
+// rejected mailbox and a catch-all server disagree
+func (s *EmailService) SetStatusPrecedence(precedence StatusPrecedence) {
+	s.statusPrecedence = precedence
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetStatusPrecedence(precedence)
+	}
+}
+
+// SetSyntaxMode sets the syntax mode used when a request does not ask for one
+func (s *EmailService) SetSyntaxMode(mode validator.SyntaxMode) {
+	s.syntaxMode = mode
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetSyntaxMode(mode)
+	}
+}
+
+// SetMailboxVerifier sets the verifier that probes mailboxes once the domain is known to
+// accept mail. Without one, mailbox_exists mirrors mx_records.
+func (s *EmailService) SetMailboxVerifier(verifier MailboxVerifier) {
+	s.mailboxVerifier = verifier
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetMailboxVerifier(verifier)
+	}
+}
+
+// SetDomainAgeChecker enables domain age checks. Results then report how many days ago the
+// domain was registered and whether it counts as new. Pass nil to disable them.
+func (s *EmailService) SetDomainAgeChecker(checker DomainAgeChecker) {
+	s.domainAgeChecker = checker
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetDomainAgeChecker(checker)
+	}
+}
+
+// SetDomainLearner sets the learner told about every domain that validates as VALID,
+// e.g. to build a dictionary for typo suggestions. Pass nil to disable learning.
+func (s *EmailService) SetDomainLearner(learner DomainLearner) {
+	s.domainLearner = learner
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetDomainLearner(learner)
+	}
+}
+
+// SetConfidencePenalties sets how catch-all and greylisted mailbox checks limit the score
+func (s *EmailService) SetConfidencePenalties(penalties ConfidencePenalties) {
+	s.confidencePenalties = penalties
+	if s.batchValidationSvc != nil {
+		s.batchValidationSvc.SetConfidencePenalties(penalties)
+	}
+}
+
+// SetRevalidationPolicy sets the intervals after which results suggest validating an
+// address again
+func (s *EmailService) SetRevalidationPolicy(policy RevalidationPolicy) {
+	s.revalidationPolicy = policy
+}
+
+// SetResultCache enables caching of single-email validation results. Results are keyed
+// on the check set, so changing the configuration never serves results computed under
+// the old one. Pass nil to disable caching.
+func (s *EmailService) SetResultCache(resultCache *ResultCache) {
+	s.resultCache = resultCache
+}
+
+// SetDisposableCache sets the cache of disposable determinations used by the domain checks.
+// It has no effect when a custom domain validation service is set. Pass nil to disable caching.
+func (s *EmailService) SetDisposableCache(disposableCache DisposableResultCache) {
+	if svc, ok := s.domainValidationSvc.(*ConcurrentDomainValidationService); ok {
+		svc.SetDisposableCache(disposableCache)
+	}
+}
+
+// SetDisposableHeuristicThreshold enables the disposable heuristics for domains missing
+// from the disposable lists: a domain whose heuristic score reaches threshold is treated
+// as disposable. Zero, the default, disables the heuristics.
+func (s *EmailService) SetDisposableHeuristicThreshold(threshold float64) {
+	s.heuristicThreshold = threshold
+	if svc, ok := s.domainValidationSvc.(*ConcurrentDomainValidationService); ok {
+		svc.SetDisposableHeuristicThreshold(threshold)
+	}
+}
+
+// SetParallelDomainChecks sets whether a domain's existence, MX, disposable and parked
+// checks run concurrently, the default, or one after another
+func (s *EmailService) SetParallelDomainChecks(parallel bool) {
+	if svc, ok := s.domainValidationSvc.(*ConcurrentDomainValidationService); ok {
+		svc.SetParallelChecks(parallel)
+	}
+}
+
+// SetResultPostProcessors sets the processors that adjust every single-email, batch and
+// explained result before it is returned, replacing any set before. They run in order, each
+// seeing the changes of the ones before it, after the result is computed or read from the
+// result cache and before its fingerprint is taken and its event published.
+func (s *EmailService) SetResultPostProcessors(processors ...ResultPostProcessor) {
+	s.postProcessors = processors
+}
+
+// SetValidationTimeout bounds how long a single-email validation may take. Checks that
+// have not finished by then are reported as timed out and inconclusive, and the result is
+// built from the checks that did finish. Zero, the default, waits for every check.
+func (s *EmailService) SetValidationTimeout(timeout time.Duration) {
+	s.validationTimeout = timeout
+}
+
+// SetEventSink sets the sink that receives an event for every validation result
+func (s *EmailService) SetEventSink(sink EventSink) {
+	s.eventSink = sink
+}
+
 // SetDomainValidationService sets the domain validation service (for testing)
 func (s *EmailService) SetDomainValidationService(svc DomainValidationService) {
 	s.domainValidationSvc = svc
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// ExplainEmail performs all validation checks on a single email and returns the result
+// together with every intermediate signal: raw DNS records, the SMTP conversation, timings
+// and the score computation. It makes extra lookups and is meant for debugging, so no
+// validation event is emitted for it.
+func (s *EmailService) ExplainEmail(email string, opts ValidationOptions) model.ExplainResponse {
+	atomic.AddInt64(&s.requests, 1)
+
+	trace := &model.ValidationTrace{TimingsMs: make(map[string]float64)}
+	opts.Trace = trace
+
+	start := time.Now()
+	response := s.validateEmail(email, opts)
+	s.postProcess(&response, trace)
+	recordTiming(trace, "total", start)
+
+	return model.ExplainResponse{EmailValidationResponse: response, Trace: *trace}
+}
+
+// recordTiming records how long check took since start. It does nothing without a trace.
+func recordTiming(trace *model.ValidationTrace, check string, start time.Time) {
+	if trace == nil {
+		return
+	}
+	trace.TimingsMs[check] = float64(time.Since(start).Microseconds()) / 1000
+}
+
+// inspectDomain records the domain's raw DNS records in trace, if the validator can look them up
+func inspectDomain(domainValidator DomainValidator, trace *model.ValidationTrace, domain string) {
+	if trace == nil {
+		return
+	}
+	inspector, ok := domainValidator.(DomainInspector)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	inspection := inspector.InspectDomain(domain)
+	recordTiming(trace, "dns_records", start)
+
+	trace.DNS = &model.DNSTrace{
+		CacheHit:  inspection.CacheHit,
+		MXRecords: make([]model.MXRecord, 0, len(inspection.MXRecords)),
+		MXError:   inspection.MXError,
+		SPF:       inspection.SPF,
+		DMARC:     inspection.DMARC,
+	}
+	if inspection.CacheHit {
+		trace.DNS.CacheAgeSeconds = wholeSeconds(inspection.CacheAge)
+	}
+	for _, mx := range inspection.MXRecords {
+		trace.DNS.MXRecords = append(trace.DNS.MXRecords, model.MXRecord{Host: mx.Host, Pref: mx.Pref})
+	}
+}
+
+// probeMailbox probes email with verifier. When tracing, the SMTP transcript is requested
+// from verifiers that support it and the outcome is recorded in trace, with the sender redacted.
+func probeMailbox(verifier MailboxVerifier, email string, trace *model.ValidationTrace) validator.SMTPResult {
+	if trace == nil {
+		return verifier.Verify(email)
+	}
+
+	var result validator.SMTPResult
+	if optionsVerifier, ok := verifier.(SMTPOptionsVerifier); ok {
+		result = optionsVerifier.VerifyWithOptions(email, validator.SMTPOptions{Transcript: true})
+	} else {
+		result = verifier.Verify(email)
+	}
+
+	trace.SMTP = &model.SMTPTrace{
+		Status:      string(result.Status),
+		Host:        result.Host,
+		Unreachable: result.Unreachable,
+		Sender:      validator.RedactSender(result.Sender),
+		CatchAll:    result.CatchAll,
+		Code:        result.Code,
+		Message:     result.Message,
+		Transcript:  result.Transcript,
+	}
+	return result
+}
+
+// traceScoreStep records a score adjustment and the score it produced
+func traceScoreStep(trace *model.ValidationTrace, score int, step string) {
+	if trace == nil || trace.Score == nil {
+		return
+	}
+	trace.Score.Steps = append(trace.Score.Steps, fmt.Sprintf("%s = %d", step, score))
+}
+
+// traceStatusOverride records a score the final status replaced, e.g. for domains without MX records
+func traceStatusOverride(trace *model.ValidationTrace, response *model.EmailValidationResponse) {
+	if trace == nil || trace.Score == nil || trace.Score.Final == response.Score {
+		return
+	}
+	traceScoreStep(trace, response.Score, fmt.Sprintf("status %s overrides score", response.Status))
+	trace.Score.Final = response.Score
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emailvalidator/internal/model"
+)
+
+// fingerprintFields returns the significant fields of a result by name: its status, score
+// and reason code, and the flags in validations. Everything else, such as the batch index,
+// the reason text or typo suggestions, is left out, so it does not change the fingerprint.
+func fingerprintFields(response model.EmailValidationResponse) map[string]string {
+	v := response.Validations
+	return map[string]string{
+		"status":           string(response.Status),
+		"score":            strconv.Itoa(response.Score),
+		"reason_code":      string(response.ReasonCode),
+		"syntax":           strconv.FormatBool(v.Syntax),
+		"unknown_tld":      strconv.FormatBool(v.UnknownTLD),
+		"domain_exists":    strconv.FormatBool(v.DomainExists),
+		"mx_records":       strconv.FormatBool(v.MXRecords),
+		"mailbox_exists":   strconv.FormatBool(v.MailboxExists),
+		"is_disposable":    strconv.FormatBool(v.IsDisposable),
+		"is_role_based":    strconv.FormatBool(v.IsRoleBased),
+		"is_free_provider": strconv.FormatBool(v.IsFreeProvider),
+		"is_catch_all":     strconv.FormatBool(v.IsCatchAll),
+		"is_greylisted":    strconv.FormatBool(v.IsGreylisted),
+		"null_mx":          strconv.FormatBool(v.NullMX),
+		"ip_literal_mx":    strconv.FormatBool(v.IPLiteralMX),
+		"is_parked":        strconv.FormatBool(v.IsParked),
+	}
+}
+
+// Fingerprint returns a stable digest of the significant fields of response. Two results
+// with the same fingerprint agree on status, score, reason code and every validation flag,
+// so a client re-validating a list can compare fingerprints to find the results that changed.
+func Fingerprint(response model.EmailValidationResponse) string {
+	return hashFields(fingerprintFields(response))
+}
+
+// hashFields hashes fields serialized as "name=value" pairs sorted by name, so the digest
+// does not depend on the order the fields were collected in
+func hashFields(fields map[string]string) string {
+	pairs := make([]string, 0, len(fields))
+	for name, value := range fields {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+
+	sum := sha256.Sum256([]byte(strings.Join(pairs, ";")))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,41 @@
+package service
+
+import "fmt"
+
+// GreylistPolicy controls the final outcome of a mailbox check the server greylisted, that
+// is, deferred with a temporary 450 or 451 reply. Greylisting usually clears on a retry,
+// so whether to wait for one, accept the address or reject it is the caller's tradeoff.
+type GreylistPolicy string
+
+const (
+	// GreylistPolicyUncertain records the mailbox check as inconclusive, resolved by the
+	// UnknownPolicy, and caps the score at the greylist ceiling, so the address is at best
+	// PROBABLY_VALID. This is the default.
+	GreylistPolicyUncertain GreylistPolicy = "uncertain"
+	// GreylistPolicyValid treats the mailbox as existing, as if the server had accepted it.
+	// Addresses are accepted optimistically and can be VALID; some will later bounce.
+	GreylistPolicyValid GreylistPolicy = "valid"
+	// GreylistPolicyInvalid treats the mailbox as rejected, so the address is INVALID.
+	// Nothing bad gets through, but real addresses on greylisting servers are turned away.
+	GreylistPolicyInvalid GreylistPolicy = "invalid"
+)
+
+// ParseGreylistPolicy converts a configuration string into a GreylistPolicy
+func ParseGreylistPolicy(value string) (GreylistPolicy, error) {
+	switch GreylistPolicy(value) {
+	case "", GreylistPolicyUncertain:
+		return GreylistPolicyUncertain, nil
+	case GreylistPolicyValid:
+		return GreylistPolicyValid, nil
+	case GreylistPolicyInvalid:
+		return GreylistPolicyInvalid, nil
+	default:
+		return "", fmt.Errorf("greylist policy %q: must be %q, %q or %q",
+			value, GreylistPolicyUncertain, GreylistPolicyValid, GreylistPolicyInvalid)
+	}
+}
+
+// conclusive reports whether a greylisted mailbox check counts as a definite answer
+func (p GreylistPolicy) conclusive() bool {
+	return p == GreylistPolicyValid || p == GreylistPolicyInvalid
+}
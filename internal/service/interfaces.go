@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"time"
+
 	"emailvalidator/internal/model"
+	"emailvalidator/pkg/events"
+	"emailvalidator/pkg/validator"
 )
 
 // EmailValidator defines the contract for email validation operations
@@ -19,6 +23,49 @@ type DomainValidator interface {
 	IsDisposable(domain string) bool
 }
 
+// DomainStatusValidator is optionally implemented by domain validators that can tell an
+// inconclusive lookup (e.g. DNS timeout) apart from a negative answer
+type DomainStatusValidator interface {
+	ValidateDomainStatus(domain string) (exists, inconclusive bool)
+	ValidateMXRecordsStatus(domain string) (hasMX, inconclusive bool)
+}
+
+// MXChecker is optionally implemented by domain validators that classify MX records,
+// telling a null MX (RFC 7505) and IP-literal targets apart from a missing MX
+type MXChecker interface {
+	CheckMXRecords(domain string) validator.MXCheck
+}
+
+// TLDChecker is optionally implemented by domain validators that know which top-level
+// domains exist, so unknown TLDs can be rejected without DNS lookups
+type TLDChecker interface {
+	HasKnownTLD(domain string) bool
+}
+
+// DomainNameValidator is optionally implemented by domain validators with their own rules
+// for which domain names are valid, such as accepting dotless intranet domains
+type DomainNameValidator interface {
+	ValidateDomainName(domain string) error
+}
+
+// ParkedDomainChecker is optionally implemented by domain validators that can tell whether a
+// domain is parked, e.g. from its nameservers
+type ParkedDomainChecker interface {
+	IsParked(domain string) bool
+}
+
+// DomainInspector is optionally implemented by domain validators that can return the raw
+// DNS records behind a domain's validation, for debugging
+type DomainInspector interface {
+	InspectDomain(domain string) validator.DomainInspection
+}
+
+// DomainCacheInspector is optionally implemented by domain validators that cache domain
+// lookups and can tell whether a domain is cached and how long ago it was looked up
+type DomainCacheInspector interface {
+	DomainCacheAge(domain string) (age time.Duration, cached bool)
+}
+
 // EmailRuleValidator defines the contract for email-specific rule validations
 type EmailRuleValidator interface {
 	ValidateSyntax(email string) bool
@@ -28,17 +75,163 @@ type EmailRuleValidator interface {
 	DetectAlias(email string) string
 }
 
+// FreeProviderDetector is optionally implemented by rule validators that can tell whether
+// an address is at a free mailbox provider
+type FreeProviderDetector interface {
+	IsFreeProvider(email string) bool
+}
+
+// SyntaxExplainer is optionally implemented by rule validators that can explain why an
+// address failed syntax validation
+type SyntaxExplainer interface {
+	ExplainSyntax(email string) string
+}
+
+// SyntaxModeValidator is optionally implemented by rule validators that can check syntax
+// under a specific validator.SyntaxMode
+type SyntaxModeValidator interface {
+	ValidateSyntaxMode(email string, mode validator.SyntaxMode) bool
+	ExplainSyntaxMode(email string, mode validator.SyntaxMode) string
+}
+
+// ConfidentTypoSuggester is optionally implemented by rule validators whose typo suggestions
+// can be limited to a minimum confidence
+type ConfidentTypoSuggester interface {
+	GetTypoSuggestionsWithConfidence(email string, minConfidence float64) []string
+}
+
+// RegionalTypoSuggester is optionally implemented by rule validators with typo dictionaries
+// for specific regions
+type RegionalTypoSuggester interface {
+	TypoRegion(candidates ...string) string
+	GetTypoSuggestionsForRegion(email, region string) []string
+	GetTypoSuggestionsForRegionWithConfidence(email, region string, minConfidence float64) []string
+}
+
+// AddressingCapabilityProvider is optionally implemented by rule validators that know which
+// addressing features (dots, plus, subdomain) a domain supports
+type AddressingCapabilityProvider interface {
+	AddressingCapabilities(domain string) validator.Capabilities
+}
+
+// Canonicalizer is optionally implemented by rule validators that can normalize an address
+// to a stable key for its mailbox
+type Canonicalizer interface {
+	Canonicalize(email string) string
+}
+
+// SubaddressDetector is optionally implemented by rule validators that can extract the
+// subaddress tag of an address
+type SubaddressDetector interface {
+	DetectSubaddress(email string) (string, bool)
+}
+
+// DomainAgeChecker looks up how long ago a domain was registered
+type DomainAgeChecker interface {
+	Check(domain string) (validator.DomainAge, error)
+}
+
+// MailboxVerifier checks whether a mailbox exists, e.g. by an SMTP probe
+type MailboxVerifier interface {
+	Verify(email string) validator.SMTPResult
+}
+
+// SMTPOptionsVerifier is optionally implemented by mailbox verifiers that accept per-call
+// options, such as recording the SMTP transcript
+type SMTPOptionsVerifier interface {
+	VerifyWithOptions(email string, opts validator.SMTPOptions) validator.SMTPResult
+}
+
+// DomainLearner records domains that validated successfully, e.g. to learn typo corrections
+type DomainLearner interface {
+	Learn(domain string)
+}
+
 // MetricsCollector defines the contract for collecting service metrics
 type MetricsCollector interface {
 	RecordValidationScore(name string, score float64)
 	UpdateMemoryUsage(heapInUse, stackInUse float64)
 }
 
+// WorkerPoolMetrics is optionally implemented by metrics collectors that track batch
+// worker-pool utilization
+type WorkerPoolMetrics interface {
+	WorkerStarted()
+	WorkerStopped()
+	WorkerBusy()
+	WorkerIdle()
+}
+
+// EventSink receives an event for every finalized validation result.
+// Implementations must not block; the call is made on the request path.
+type EventSink interface {
+	Emit(event events.Event)
+}
+
+// ResultPostProcessor adjusts a computed result before it is returned, to apply
+// organization-specific rules such as rejecting a domain or raising the score of a
+// partner's. It may change the status, score, reason code and flags. Processors are
+// called concurrently and must not keep or share the response or its slices.
+type ResultPostProcessor interface {
+	Process(response *model.EmailValidationResponse)
+}
+
+// DisposableHeuristicScorer is optionally implemented by domain validators that can score
+// how likely a domain is to be disposable from its name alone
+type DisposableHeuristicScorer interface {
+	DisposableHeuristic(domain string) (float64, []string)
+}
+
+// DisposableMXChecker is optionally implemented by domain validators that can tell whether
+// a domain's mail servers belong to a disposable provider, catching vanity domains that
+// route their mail through one
+type DisposableMXChecker interface {
+	DisposableMXProvider(domain string) (provider string, inconclusive bool)
+}
+
+// DisposableSourceReporter is optionally implemented by domain validators that can tell
+// which entries of their disposable list and allowlist match a domain
+type DisposableSourceReporter interface {
+	DisposableSources(domain string) []validator.DisposableSource
+	DisposableAllowlistEntry(domain string) string
+}
+
+// DisposableResultCache caches whether domains are disposable
+type DisposableResultCache interface {
+	Get(ctx context.Context, domain string) (disposable, ok bool)
+	Set(ctx context.Context, domain string, disposable bool)
+}
+
+// DisposableLookup is a disposable domain list consulted besides the validator's own, such as
+// the remote blocklist. ready is false while the list is still loading.
+type DisposableLookup interface {
+	Lookup(domain string) (disposable, ready bool)
+}
+
+// DisposableSourceLookup is a DisposableLookup that can tell which of its sources list a
+// domain. ready is false while the list is still loading.
+type DisposableSourceLookup interface {
+	DisposableLookup
+	Sources(domain string) (sources []validator.DisposableSource, ready bool)
+}
+
 // DomainValidationService defines the contract for concurrent domain validation operations
 type DomainValidationService interface {
 	ValidateDomainConcurrently(ctx context.Context, domain string) (exists, hasMX, isDisposable bool)
 }
 
+// DisposableCheckService is optionally implemented by domain validation services that can
+// run the disposable check on its own, without the DNS lookups of the other domain checks
+type DisposableCheckService interface {
+	CheckDisposable(ctx context.Context, domain string) DomainCheckResult
+}
+
+// DomainStatusValidationService is optionally implemented by domain validation services
+// that report which checks were inconclusive
+type DomainStatusValidationService interface {
+	ValidateDomainWithStatus(ctx context.Context, domain string) DomainCheckResult
+}
+
 // AliasDetector defines the contract for detecting email aliases
 type AliasDetector interface {
 	// DetectAlias checks if the email is an alias and returns the canonical email if it is
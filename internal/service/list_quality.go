@@ -0,0 +1,63 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"emailvalidator/internal/model"
+)
+
+// HighDisposableShare is the share of disposable addresses from which a batch is flagged
+// as a likely low-quality list
+const HighDisposableShare = 0.2
+
+// minDisposableForFlag is the fewest disposable addresses a batch is flagged for, so one
+// throwaway address in a handful does not mark the list
+const minDisposableForFlag = 3
+
+// summarizeBatch analyzes results as a whole: domains with more than one role account and
+// the concentration of disposable addresses. Results that failed with an internal error or
+// have invalid syntax are left out.
+func summarizeBatch(results []model.EmailValidationResponse) *model.BatchSummary {
+	summary := &model.BatchSummary{RoleCollisions: []model.RoleCollision{}}
+	roles := make(map[string]map[string]bool)
+	considered := 0
+	for _, result := range results {
+		if result.Error != "" || !result.Validations.Syntax {
+			continue
+		}
+		considered++
+		if result.Validations.IsDisposable {
+			summary.DisposableCount++
+		}
+		if !result.Validations.IsRoleBased || result.DomainNormalized == "" {
+			continue
+		}
+		local := strings.ToLower(result.Email[:strings.LastIndex(result.Email, "@")])
+		if roles[result.DomainNormalized] == nil {
+			roles[result.DomainNormalized] = make(map[string]bool)
+		}
+		roles[result.DomainNormalized][local] = true
+	}
+
+	for domain, accounts := range roles {
+		if len(accounts) < 2 {
+			continue
+		}
+		collision := model.RoleCollision{Domain: domain, Accounts: make([]string, 0, len(accounts))}
+		for account := range accounts {
+			collision.Accounts = append(collision.Accounts, account)
+		}
+		sort.Strings(collision.Accounts)
+		summary.RoleCollisions = append(summary.RoleCollisions, collision)
+	}
+	sort.Slice(summary.RoleCollisions, func(i, j int) bool {
+		return summary.RoleCollisions[i].Domain < summary.RoleCollisions[j].Domain
+	})
+
+	if considered > 0 {
+		summary.DisposableShare = float64(summary.DisposableCount) / float64(considered)
+	}
+	summary.HighDisposableShare = summary.DisposableCount >= minDisposableForFlag && summary.DisposableShare >= HighDisposableShare
+	return summary
+}
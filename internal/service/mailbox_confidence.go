@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// CheckMailboxExists names the mailbox check, as reported in the response's Inconclusive list
+const CheckMailboxExists = "mailbox_exists"
+
+// ConfidencePenalties limits the score of addresses whose mailbox check passed without
+// proving much. Ceilings are percentages of the full score: a ceiling of 80 scales every
+// score by 0.8, so an otherwise perfect address scores 80 and other deductions still count.
+type ConfidencePenalties struct {
+	// CatchAllCeiling is the highest score for an address on a catch-all server, which accepts
+	// every recipient and so cannot confirm that the mailbox exists
+	CatchAllCeiling int
+	// GreylistCeiling is the highest score for an address whose mailbox check was greylisted
+	GreylistCeiling int
+}
+
+// DefaultConfidencePenalties returns the penalties used unless configured otherwise. Both
+// ceilings keep such addresses below the VALID threshold.
+func DefaultConfidencePenalties() ConfidencePenalties {
+	return ConfidencePenalties{
+		CatchAllCeiling: 80,
+		GreylistCeiling: 90,
+	}
+}
+
+// Validate checks that both ceilings are percentages
+func (p ConfidencePenalties) Validate() error {
+	if p.CatchAllCeiling < 0 || p.CatchAllCeiling > 100 {
+		return fmt.Errorf("catch-all ceiling %d: must be between 0 and 100", p.CatchAllCeiling)
+	}
+	if p.GreylistCeiling < 0 || p.GreylistCeiling > 100 {
+		return fmt.Errorf("greylist ceiling %d: must be between 0 and 100", p.GreylistCeiling)
+	}
+	return nil
+}
+
+// apply scales score down to the ceiling of every uncertain signal in response. A
+// greylisted mailbox check the GreylistPolicy resolved is no longer uncertain.
+func (p ConfidencePenalties) apply(score int, response *model.EmailValidationResponse, trace *model.ValidationTrace) int {
+	validations := response.Validations
+	if validations.IsCatchAll {
+		score = score * p.CatchAllCeiling / 100
+		traceScoreStep(trace, score, fmt.Sprintf("catch-all ceiling: x%d%%", p.CatchAllCeiling))
+	}
+	if validations.IsGreylisted && slices.Contains(response.Inconclusive, CheckMailboxExists) {
+		score = score * p.GreylistCeiling / 100
+		traceScoreStep(trace, score, fmt.Sprintf("greylist ceiling: x%d%%", p.GreylistCeiling))
+	}
+	return score
+}
+
+// verifyMailbox probes the mailbox with verifier and records the outcome in response.
+// Without a verifier, or without MX records to probe, the mailbox is assumed to exist
+// whenever the domain accepts mail. Outcomes other than a definite answer are recorded as
+// inconclusive and resolved by policy, except a greylisted check that greylistPolicy
+// resolves to a definite answer. When trace is set, the probe's full SMTP
+// conversation is recorded in it.
+func verifyMailbox(verifier MailboxVerifier, response *model.EmailValidationResponse, policy UnknownPolicy, greylistPolicy GreylistPolicy, trace *model.ValidationTrace) {
+	response.Validations.MailboxExists = response.Validations.MXRecords
+	if verifier == nil || !response.Validations.MXRecords {
+		return
+	}
+
+	result := probeMailbox(verifier, response.Email, trace)
+	switch {
+	case result.Status == validator.SMTPStatusDeliverable:
+		response.Validations.MailboxExists = true
+		response.Validations.IsCatchAll = result.CatchAll
+	case result.Status == validator.SMTPStatusUndeliverable:
+		response.Validations.MailboxExists = false
+	case result.Status == validator.SMTPStatusGreylisted && greylistPolicy.conclusive():
+		response.Validations.IsGreylisted = true
+		response.Validations.MailboxExists = greylistPolicy == GreylistPolicyValid
+	default:
+		response.Validations.IsGreylisted = result.Status == validator.SMTPStatusGreylisted
+		response.Validations.MailboxExists = policy == UnknownPolicyLenient
+		// Copy before appending: batch results for the same domain share the domain's slice
+		inconclusive := response.Inconclusive[:len(response.Inconclusive):len(response.Inconclusive)]
+		response.Inconclusive = append(inconclusive, CheckMailboxExists)
+	}
+}
+
+// mailboxRejected reports whether the mailbox check conclusively found no such mailbox
+func mailboxRejected(response *model.EmailValidationResponse) bool {
+	return response.Validations.MXRecords && !response.Validations.MailboxExists &&
+		!slices.Contains(response.Inconclusive, CheckMailboxExists)
+}
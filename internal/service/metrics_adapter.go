@@ -15,6 +15,26 @@ func (m *MetricsAdapter) RecordValidationScore(name string, score float64) {
 	monitoring.RecordValidationScore(name, score)
 }
 
+// WorkerStarted marks a batch worker as running
+func (m *MetricsAdapter) WorkerStarted() {
+	monitoring.BatchWorkerStarted()
+}
+
+// WorkerStopped marks a batch worker as no longer running
+func (m *MetricsAdapter) WorkerStopped() {
+	monitoring.BatchWorkerStopped()
+}
+
+// WorkerBusy marks a batch worker as validating an email
+func (m *MetricsAdapter) WorkerBusy() {
+	monitoring.BatchWorkerBusy()
+}
+
+// WorkerIdle marks a batch worker as waiting for work
+func (m *MetricsAdapter) WorkerIdle() {
+	monitoring.BatchWorkerIdle()
+}
+
 // UpdateMemoryUsage updates memory usage metrics
 func (m *MetricsAdapter) UpdateMemoryUsage(heapInUse, stackInUse float64) {
 	monitoring.UpdateMemoryUsage(heapInUse, stackInUse)
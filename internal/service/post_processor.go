@@ -0,0 +1,144 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// blockedDomainReason explains the status of an address at a blocked domain
+const blockedDomainReason = "The domain is blocked by an organization rule"
+
+// ResultPostProcessorFunc adapts an ordinary function to ResultPostProcessor
+type ResultPostProcessorFunc func(response *model.EmailValidationResponse)
+
+// Process calls f(response)
+func (f ResultPostProcessorFunc) Process(response *model.EmailValidationResponse) {
+	f(response)
+}
+
+// DomainRules is a ResultPostProcessor applying rules by the address's domain: addresses
+// at a blocked domain are rejected, and addresses at a boosted domain have their score
+// raised. A rule for a domain also covers its subdomains.
+type DomainRules struct {
+	blocked []string
+	boosts  map[string]int
+}
+
+// NewDomainRules creates rules rejecting addresses at the blocked domains and adding
+// boosts[domain] to the score of addresses at a boosted domain that pass validation
+func NewDomainRules(blocked []string, boosts map[string]int) *DomainRules {
+	rules := &DomainRules{boosts: make(map[string]int, len(boosts))}
+	for _, domain := range blocked {
+		if domain = validator.NormalizeDomain(strings.TrimSpace(domain)); domain != "" {
+			rules.blocked = append(rules.blocked, domain)
+		}
+	}
+	for domain, boost := range boosts {
+		rules.boosts[validator.NormalizeDomain(domain)] = boost
+	}
+	return rules
+}
+
+// Process implements ResultPostProcessor. Results that failed syntax validation are left
+// alone. A boost only applies to VALID and PROBABLY_VALID results, whose score is capped at
+// 100; a PROBABLY_VALID result boosted to 90 or more with every check conclusive becomes VALID.
+func (r *DomainRules) Process(response *model.EmailValidationResponse) {
+	domain := response.DomainNormalized
+	if domain == "" || !response.Validations.Syntax {
+		return
+	}
+
+	for _, blocked := range r.blocked {
+		if domainOrSubdomain(domain, blocked) {
+			response.Status = model.ValidationStatusInvalid
+			response.ReasonCode = model.ReasonBlockedDomain
+			response.Reason = blockedDomainReason
+			response.Score = 0
+			return
+		}
+	}
+
+	boost, ok := r.boost(domain)
+	if !ok || (response.Status != model.ValidationStatusValid && response.Status != model.ValidationStatusProbablyValid) {
+		return
+	}
+	response.Score = min(100, max(0, response.Score+boost))
+	if response.Status == model.ValidationStatusProbablyValid && response.Score >= 90 && len(response.Inconclusive) == 0 {
+		response.Status = model.ValidationStatusValid
+		response.ReasonCode = ""
+	}
+}
+
+// boost returns the boost of the most specific boosted domain covering domain
+func (r *DomainRules) boost(domain string) (int, bool) {
+	for {
+		if boost, ok := r.boosts[domain]; ok {
+			return boost, true
+		}
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			return 0, false
+		}
+		domain = parent
+	}
+}
+
+// domainOrSubdomain reports whether domain is parent or one of its subdomains
+func domainOrSubdomain(domain, parent string) bool {
+	return domain == parent || strings.HasSuffix(domain, "."+parent)
+}
+
+// ParseDomainScoreBoosts parses score boosts written as comma-separated domain=points
+// pairs, e.g. "partner.com=10,reseller.net=5". Points may be negative to lower a score.
+func ParseDomainScoreBoosts(s string) (map[string]int, error) {
+	boosts := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		domain, value, ok := strings.Cut(pair, "=")
+		points, err := strconv.Atoi(strings.TrimSpace(value))
+		domain = strings.TrimSpace(domain)
+		if !ok || err != nil || domain == "" || points < -100 || points > 100 {
+			return nil, fmt.Errorf("invalid domain score boost %q: must be domain=points from -100 to 100", pair)
+		}
+		boosts[validator.NormalizeDomain(domain)] = points
+	}
+	return boosts, nil
+}
+
+// postProcess runs the registered post-processors on response in registration order,
+// recording a changed score in trace
+func (s *EmailService) postProcess(response *model.EmailValidationResponse, trace *model.ValidationTrace) {
+	if len(s.postProcessors) == 0 || response.Error != "" || response.Status == model.ValidationStatusNotProcessed {
+		return
+	}
+	score := response.Score
+	for _, processor := range s.postProcessors {
+		processor.Process(response)
+	}
+	if response.Score != score && trace != nil && trace.Score != nil {
+		traceScoreStep(trace, response.Score, "result post-processors")
+		trace.Score.Final = response.Score
+	}
+}
+
+// postProcessBatch runs the post-processors on every result of a batch that did not fail
+// and was processed
+func (s *EmailService) postProcessBatch(response *model.BatchValidationResponse) {
+	if len(s.postProcessors) == 0 {
+		return
+	}
+	for i := range response.Results {
+		if response.Results[i].Status == model.ValidationStatusNotProcessed {
+			continue
+		}
+		s.postProcess(&response.Results[i], nil)
+		response.Results[i].Fingerprint = Fingerprint(response.Results[i])
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/cache"
+	"emailvalidator/pkg/validator"
+)
+
+// ScoringVersion identifies the scoring and status rules. Bump it whenever calculateScore
+// or determineValidationStatus change, so results cached under the old rules are not reused.
+const ScoringVersion = 3
+
+// DefaultResultCacheTTL is how long a cached validation result is reused
+const DefaultResultCacheTTL = time.Hour
+
+// CheckSet describes which checks produced a result and how it was scored. A cached
+// result is only reused for a request with an equal check set, so a result computed
+// without, say, the mailbox probe never answers a request that expects one.
+type CheckSet struct {
+	Mailbox                 bool
+	DomainAge               bool
+	UnknownPolicy           UnknownPolicy
+	GreylistPolicy          GreylistPolicy
+	DisposablePolicy        DisposablePolicy
+	HeuristicThreshold      float64
+	MinSuggestionConfidence *float64
+	TypoRegions             string
+	SyntaxMode              validator.SyntaxMode
+	Penalties               ConfidencePenalties
+	StatusPrecedence        string
+	ScoringVersion          int
+}
+
+// fingerprint returns a short stable digest of the check set
+func (c CheckSet) fingerprint() string {
+	confidence := "default"
+	if c.MinSuggestionConfidence != nil {
+		confidence = strconv.FormatFloat(*c.MinSuggestionConfidence, 'g', -1, 64)
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("mailbox=%t;domain_age=%t;unknown=%s;greylisted=%s;disposable=%s;heuristic=%g;confidence=%s;typo_regions=%s;syntax=%s;catch_all=%d;greylist=%d;precedence=%s;scoring=%d",
+		c.Mailbox, c.DomainAge, c.UnknownPolicy, c.GreylistPolicy, c.DisposablePolicy, c.HeuristicThreshold, confidence, c.TypoRegions, c.SyntaxMode,
+		c.Penalties.CatchAllCeiling, c.Penalties.GreylistCeiling, c.StatusPrecedence, c.ScoringVersion)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ResultCacheKey returns the cache key of the result for email under checks
+func ResultCacheKey(email string, checks CheckSet) string {
+	return "result:" + checks.fingerprint() + ":" + email
+}
+
+// ResultCache stores complete validation results, keyed on the address and the check set
+// that produced them
+type ResultCache struct {
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewResultCache creates a ResultCache that keeps results in c for ttl
+func NewResultCache(c cache.Cache, ttl time.Duration) *ResultCache {
+	return &ResultCache{cache: c, ttl: ttl}
+}
+
+// Get returns the cached result for email under checks, if there is one
+func (c *ResultCache) Get(email string, checks CheckSet) (model.EmailValidationResponse, bool) {
+	var response model.EmailValidationResponse
+	if err := c.cache.Get(context.Background(), ResultCacheKey(email, checks), &response); err != nil {
+		if !errors.Is(err, cache.ErrMiss) && !errors.Is(err, cache.ErrCircuitOpen) {
+			log.Printf("Warning: Could not read cached result: %v", err)
+		}
+		return response, false
+	}
+	return response, true
+}
+
+// Set caches response as the result for email under checks. Inconclusive results, DNS
+// timeouts and internal errors are not cached, since a retry may well do better.
+func (c *ResultCache) Set(email string, checks CheckSet, response model.EmailValidationResponse) {
+	if len(response.Inconclusive) > 0 || response.ReasonCode == model.ReasonInternalError ||
+		response.ReasonCode == model.ReasonDNSTimeout {
+		return
+	}
+	if err := c.cache.Set(context.Background(), ResultCacheKey(email, checks), response, c.ttl); err != nil && !errors.Is(err, cache.ErrCircuitOpen) {
+		log.Printf("Warning: Could not cache result: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"emailvalidator/internal/model"
+)
+
+// Outcomes a RevalidationPolicy can set an interval for besides the validation statuses
+const (
+	// RevalidateCatchAll applies to addresses at catch-all mail servers, whose mailbox was
+	// accepted without being confirmed
+	RevalidateCatchAll = "CATCH_ALL"
+	// RevalidateInconclusive applies to results with checks that could not reach a verdict
+	RevalidateInconclusive = "INCONCLUSIVE"
+)
+
+const day = 24 * time.Hour
+
+// RevalidationPolicy suggests how long a result can be trusted before the address should be
+// validated again, by outcome: a validation status, RevalidateCatchAll or
+// RevalidateInconclusive. A result gets the shortest interval among its status and the
+// other outcomes that apply to it, so any uncertainty shortens it.
+type RevalidationPolicy map[string]time.Duration
+
+// DefaultRevalidationPolicy returns the intervals used unless configured otherwise.
+// Confirmed outcomes last longest: a well-formed address that was deliverable tends to stay
+// so for months, and a malformed one never changes. Outcomes that depend on a mail server
+// or on DNS that may still be set up are rechecked sooner, and failed validations at once.
+func DefaultRevalidationPolicy() RevalidationPolicy {
+	return RevalidationPolicy{
+		string(model.ValidationStatusValid):         90 * day,
+		string(model.ValidationStatusProbablyValid): 30 * day,
+		string(model.ValidationStatusInvalid):       90 * day,
+		string(model.ValidationStatusMissingEmail):  0,
+		string(model.ValidationStatusInvalidFormat): 365 * day,
+		string(model.ValidationStatusInvalidDomain): 30 * day,
+		string(model.ValidationStatusUnknownTLD):    90 * day,
+		string(model.ValidationStatusNoMXRecords):   30 * day,
+		string(model.ValidationStatusDisposable):    180 * day,
+		string(model.ValidationStatusError):         0,
+		RevalidateCatchAll:                          14 * day,
+		RevalidateInconclusive:                      day,
+	}
+}
+
+// revalidationOutcomes are the outcomes a RevalidationPolicy accepts
+var revalidationOutcomes = []string{
+	string(model.ValidationStatusValid),
+	string(model.ValidationStatusProbablyValid),
+	string(model.ValidationStatusInvalid),
+	string(model.ValidationStatusMissingEmail),
+	string(model.ValidationStatusInvalidFormat),
+	string(model.ValidationStatusInvalidDomain),
+	string(model.ValidationStatusUnknownTLD),
+	string(model.ValidationStatusNoMXRecords),
+	string(model.ValidationStatusDisposable),
+	string(model.ValidationStatusError),
+	RevalidateCatchAll,
+	RevalidateInconclusive,
+}
+
+// ParseRevalidationPolicy parses intervals written as comma-separated outcome=duration
+// pairs, e.g. "VALID=2160h,CATCH_ALL=72h", over DefaultRevalidationPolicy. Outcomes are
+// case-insensitive.
+func ParseRevalidationPolicy(s string) (RevalidationPolicy, error) {
+	policy := DefaultRevalidationPolicy()
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		outcome, value, ok := strings.Cut(pair, "=")
+		outcome = strings.ToUpper(strings.TrimSpace(outcome))
+		interval, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil || interval < 0 || !slices.Contains(revalidationOutcomes, outcome) {
+			return nil, fmt.Errorf("invalid revalidation interval %q: must be outcome=duration, with outcome one of %s",
+				pair, strings.Join(revalidationOutcomes, ", "))
+		}
+		policy[outcome] = interval
+	}
+	return policy, nil
+}
+
+// For returns the interval after which the address of response should be validated
+// again, or zero when no outcome of it has one
+func (p RevalidationPolicy) For(response model.EmailValidationResponse) time.Duration {
+	outcomes := []string{string(response.Status)}
+	if response.Validations.IsCatchAll {
+		outcomes = append(outcomes, RevalidateCatchAll)
+	}
+	if len(response.Inconclusive) > 0 {
+		outcomes = append(outcomes, RevalidateInconclusive)
+	}
+
+	interval, found := time.Duration(0), false
+	for _, outcome := range outcomes {
+		if d, ok := p[outcome]; ok && (!found || d < interval) {
+			interval, found = d, true
+		}
+	}
+	return interval
+}
+
+// stampRevalidation sets when response should be validated again, from its final outcome
+func (s *EmailService) stampRevalidation(response *model.EmailValidationResponse) {
+	response.RevalidateAfter = int(s.revalidationPolicy.For(*response) / time.Second)
+}
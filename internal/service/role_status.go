@@ -0,0 +1,40 @@
+package service
+
+import (
+	"slices"
+
+	"emailvalidator/internal/model"
+)
+
+// unconfirmedRolePenalty is deducted from the score of a role account that is not
+// confirmed deliverable. Such addresses are often unmonitored aliases.
+const unconfirmedRolePenalty = 10
+
+// determineRoleStatus combines role detection with the domain and mailbox checks. It
+// returns "" for addresses that are not role accounts, and must run after the mailbox check.
+func determineRoleStatus(response *model.EmailValidationResponse) model.RoleStatus {
+	validations := response.Validations
+	switch {
+	case !validations.IsRoleBased:
+		return ""
+	case slices.Contains(response.Inconclusive, CheckDomainExists) || slices.Contains(response.Inconclusive, CheckMXRecords):
+		return model.RoleStatusUnverified
+	case !validations.DomainExists || !validations.MXRecords || mailboxRejected(response):
+		return model.RoleStatusUndeliverable
+	case validations.IsCatchAll || slices.Contains(response.Inconclusive, CheckMailboxExists):
+		return model.RoleStatusUnverified
+	default:
+		return model.RoleStatusDeliverable
+	}
+}
+
+// applyRolePenalty deducts unconfirmedRolePenalty from the score of a role account that
+// is not confirmed deliverable
+func applyRolePenalty(score int, roleStatus model.RoleStatus, trace *model.ValidationTrace) int {
+	if roleStatus == "" || roleStatus == model.RoleStatusDeliverable {
+		return score
+	}
+	score = max(0, score-unconfirmedRolePenalty)
+	traceScoreStep(trace, score, "role account not confirmed deliverable: -10")
+	return score
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// SameMailbox reports whether two addresses reach the same mailbox by comparing their
+// canonical forms, e.g. J.Smith+news@gmail.com and jsmith@googlemail.com. Provider rules
+// such as Gmail's ignored dots apply as configured, and the comparison ignores case. It
+// returns an error if either address fails syntax validation.
+func (s *EmailService) SameMailbox(first, second string, opts ValidationOptions) (model.SameMailboxResponse, error) {
+	atomic.AddInt64(&s.requests, 1)
+	mode := opts.syntaxMode(s.syntaxMode)
+	for _, email := range []string{first, second} {
+		if valid, reason := checkSyntax(s.emailRuleValidator, email, mode); !valid {
+			if reason == "" {
+				return model.SameMailboxResponse{}, fmt.Errorf("invalid email %q", email)
+			}
+			return model.SameMailboxResponse{}, fmt.Errorf("invalid email %q: %s", email, reason)
+		}
+	}
+
+	response := model.SameMailboxResponse{
+		First:           first,
+		Second:          second,
+		FirstCanonical:  canonicalize(s.emailRuleValidator, validator.TrimTrailingDot(first)),
+		SecondCanonical: canonicalize(s.emailRuleValidator, validator.TrimTrailingDot(second)),
+	}
+	response.SameMailbox = strings.EqualFold(response.FirstCanonical, response.SecondCanonical)
+	return response, nil
+}
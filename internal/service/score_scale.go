@@ -0,0 +1,76 @@
+package service
+
+import "fmt"
+
+// ScoreScale selects how the 0-100 score is presented in responses. The score is always
+// computed on the 0-100 scale; the scale only changes the output.
+type ScoreScale string
+
+const (
+	// ScoreScalePercent reports the score as an integer from 0 to 100. This is the default.
+	ScoreScalePercent ScoreScale = "percent"
+	// ScoreScaleProbability reports the score as a number from 0.0 to 1.0
+	ScoreScaleProbability ScoreScale = "probability"
+	// ScoreScaleGrade reports the score as a letter grade: A (90 and up), B (80 and up),
+	// C (70 and up), D (60 and up) or F
+	ScoreScaleGrade ScoreScale = "grade"
+)
+
+// ParseScoreScale converts a configuration string into a ScoreScale
+func ParseScoreScale(value string) (ScoreScale, error) {
+	switch ScoreScale(value) {
+	case "", ScoreScalePercent:
+		return ScoreScalePercent, nil
+	case ScoreScaleProbability:
+		return ScoreScaleProbability, nil
+	case ScoreScaleGrade:
+		return ScoreScaleGrade, nil
+	default:
+		return "", fmt.Errorf("score scale %q: must be %q, %q or %q",
+			value, ScoreScalePercent, ScoreScaleProbability, ScoreScaleGrade)
+	}
+}
+
+// Format converts a 0-100 score to the scale: an int, a float64 or a string
+func (s ScoreScale) Format(score int) interface{} {
+	switch s {
+	case ScoreScaleProbability:
+		return float64(score) / 100
+	case ScoreScaleGrade:
+		return scoreGrade(score)
+	default:
+		return score
+	}
+}
+
+// scoreGrade returns the letter grade of a 0-100 score
+func scoreGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// ScoreScaleFor returns the scale responses to a request with opts should use
+func (s *EmailService) ScoreScaleFor(opts ValidationOptions) ScoreScale {
+	if opts.ScoreScale != "" {
+		return opts.ScoreScale
+	}
+	if s.scoreScale != "" {
+		return s.scoreScale
+	}
+	return ScoreScalePercent
+}
+
+// SetScoreScale sets the scale used when a request does not ask for one
+func (s *EmailService) SetScoreScale(scale ScoreScale) {
+	s.scoreScale = scale
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"emailvalidator/internal/model"
+)
+
+// StatusSignal is a signal that can decide the status of an address whose domain accepts
+// mail, when several of them disagree
+type StatusSignal string
+
+const (
+	// SignalDisposable decides DISPOSABLE for a disposable domain, when the disposable
+	// policy rejects disposable addresses
+	SignalDisposable StatusSignal = "disposable"
+	// SignalInvalidMailbox decides INVALID when the mail server conclusively rejected the mailbox
+	SignalInvalidMailbox StatusSignal = "invalid_mailbox"
+	// SignalCatchAll leaves the status of a mailbox accepted by a catch-all server to its
+	// score, which the catch-all ceiling keeps below VALID by default
+	SignalCatchAll StatusSignal = "catch_all"
+)
+
+// statusSignals are every StatusSignal, in the default precedence
+var statusSignals = []StatusSignal{SignalDisposable, SignalInvalidMailbox, SignalCatchAll}
+
+// StatusPrecedence orders the signals that decide the status of an address whose domain
+// accepts mail. The first signal that applies decides; when none does, the score does.
+// Signals about the domain itself, an unknown TLD, a missing domain or missing MX records,
+// always come first, since no mailbox can exist without them.
+//
+// A mailbox rejected by the server is never catch-all, so in practice the order decides
+// whether a disposable address that is also a rejected mailbox is DISPOSABLE or INVALID,
+// and whether a disposable address at a catch-all server is DISPOSABLE or scored.
+type StatusPrecedence []StatusSignal
+
+// DefaultStatusPrecedence returns the precedence used unless configured otherwise:
+// disposable, then invalid_mailbox, then catch_all
+func DefaultStatusPrecedence() StatusPrecedence {
+	return StatusPrecedence{SignalDisposable, SignalInvalidMailbox, SignalCatchAll}
+}
+
+// ParseStatusPrecedence parses a precedence written as the signals separated by commas or
+// ">", highest first, e.g. "invalid_mailbox>disposable>catch_all". Every signal must appear
+// exactly once. An empty value selects DefaultStatusPrecedence.
+func ParseStatusPrecedence(value string) (StatusPrecedence, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultStatusPrecedence(), nil
+	}
+	names := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '>' })
+	precedence := make(StatusPrecedence, 0, len(names))
+	seen := make(map[StatusSignal]bool, len(names))
+	for _, name := range names {
+		signal := StatusSignal(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_"))
+		if !slices.Contains(statusSignals, signal) {
+			return nil, fmt.Errorf("invalid status precedence %q: unknown signal %q, must be one of %s", value, strings.TrimSpace(name), joinSignals(statusSignals))
+		}
+		if seen[signal] {
+			return nil, fmt.Errorf("invalid status precedence %q: %s is listed twice", value, signal)
+		}
+		seen[signal] = true
+		precedence = append(precedence, signal)
+	}
+	if len(precedence) != len(statusSignals) {
+		return nil, fmt.Errorf("invalid status precedence %q: must list every signal of %s", value, joinSignals(statusSignals))
+	}
+	return precedence, nil
+}
+
+// String returns the signals separated by ">", highest first
+func (p StatusPrecedence) String() string {
+	if len(p) == 0 {
+		p = DefaultStatusPrecedence()
+	}
+	return joinSignals(p)
+}
+
+// joinSignals returns signals separated by ">"
+func joinSignals(signals []StatusSignal) string {
+	names := make([]string, len(signals))
+	for i, signal := range signals {
+		names[i] = string(signal)
+	}
+	return strings.Join(names, ">")
+}
+
+// applies reports whether signal holds for response under disposablePolicy
+func (s StatusSignal) applies(response *model.EmailValidationResponse, disposablePolicy DisposablePolicy) bool {
+	switch s {
+	case SignalDisposable:
+		return response.Validations.IsDisposable && disposablePolicy.rejects()
+	case SignalInvalidMailbox:
+		return mailboxRejected(response)
+	case SignalCatchAll:
+		return response.Validations.IsCatchAll
+	default:
+		return false
+	}
+}
+
+// resolve returns the status decided by the first signal in p that holds for response.
+// decided is false when none holds, or when the deciding signal leaves the status to the
+// score. An empty precedence is the default one.
+func (p StatusPrecedence) resolve(response *model.EmailValidationResponse, disposablePolicy DisposablePolicy) (status model.ValidationStatus, decided bool) {
+	if len(p) == 0 {
+		p = DefaultStatusPrecedence()
+	}
+	for _, signal := range p {
+		if !signal.applies(response, disposablePolicy) {
+			continue
+		}
+		switch signal {
+		case SignalDisposable:
+			return model.ValidationStatusDisposable, true
+		case SignalInvalidMailbox:
+			return model.ValidationStatusInvalid, true
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,42 @@
+package service
+
+import "fmt"
+
+// UnknownPolicy controls how inconclusive checks (e.g. DNS timeouts) roll up into the final status and score
+type UnknownPolicy string
+
+const (
+	// UnknownPolicyStrict treats inconclusive checks as failed. An address whose domain
+	// lookup timed out is reported exactly as if the domain did not exist. This is the default.
+	UnknownPolicyStrict UnknownPolicy = "strict"
+	// UnknownPolicyLenient treats inconclusive checks as passed, so they do not reduce the score.
+	// Because nothing was actually confirmed, the status is capped at PROBABLY_VALID.
+	UnknownPolicyLenient UnknownPolicy = "lenient"
+)
+
+// ParseUnknownPolicy converts a configuration string into an UnknownPolicy
+func ParseUnknownPolicy(value string) (UnknownPolicy, error) {
+	switch UnknownPolicy(value) {
+	case "", UnknownPolicyStrict:
+		return UnknownPolicyStrict, nil
+	case UnknownPolicyLenient:
+		return UnknownPolicyLenient, nil
+	default:
+		return "", fmt.Errorf("unknown policy %q: must be %q or %q", value, UnknownPolicyStrict, UnknownPolicyLenient)
+	}
+}
+
+// apply resolves the inconclusive checks in result according to the policy
+func (p UnknownPolicy) apply(result *DomainCheckResult) {
+	if p != UnknownPolicyLenient {
+		return
+	}
+	for _, check := range result.Inconclusive {
+		switch check {
+		case CheckDomainExists:
+			result.DomainExists = true
+		case CheckMXRecords:
+			result.MXRecords = true
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// ValidateDomain runs the domain-level checks on a domain without an address, for callers
+// that only have a domain: whether it exists, has MX records and is disposable, and its
+// registration age when domain age checks are enabled. The checks share the DNS and
+// disposable caches with address validation. It returns an error if domain is not a valid
+// domain name.
+func (s *EmailService) ValidateDomain(ctx context.Context, domain string, opts ValidationOptions) (model.DomainValidationResponse, error) {
+	atomic.AddInt64(&s.requests, 1)
+	validateName := validator.ValidateDomainName
+	if names, ok := s.domainValidator.(DomainNameValidator); ok {
+		validateName = names.ValidateDomainName
+	}
+	if err := validateName(domain); err != nil {
+		return model.DomainValidationResponse{}, err
+	}
+	original := domain
+	domain = validator.TrimTrailingDot(strings.ToLower(domain))
+
+	if s.validationTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.validationTimeout)
+		defer cancel()
+	}
+
+	var timedOut []string
+	result, ok := runStage(ctx, func() DomainCheckResult {
+		return validateDomain(ctx, s.domainValidationSvc, domain)
+	})
+	if !ok {
+		timedOut = []string{CheckDomainExists, CheckMXRecords, CheckIsDisposable}
+		result = DomainCheckResult{Inconclusive: timedOut}
+	}
+	opts.unknownPolicy(s.unknownPolicy).apply(&result)
+	withAge := result
+	if aged, ok := runStage(ctx, func() DomainCheckResult {
+		checkDomainAge(s.domainAgeChecker, &withAge, domain)
+		return withAge
+	}); ok {
+		result = aged
+	}
+
+	response := model.DomainValidationResponse{
+		Domain:           domain,
+		DomainOriginal:   original,
+		DomainNormalized: validator.NormalizeDomain(original),
+		Validations: model.DomainValidations{
+			UnknownTLD:   result.UnknownTLD,
+			DomainExists: result.DomainExists,
+			MXRecords:    result.MXRecords,
+			IsDisposable: result.IsDisposable,
+			NullMX:       result.NullMX,
+			IPLiteralMX:  result.IPLiteralMX,
+			IsParked:     result.IsParked,
+		},
+		MXDomain:            result.MXDomain,
+		Inconclusive:        result.Inconclusive,
+		TimedOut:            timedOut,
+		DisposableHeuristic: result.DisposableHeuristic,
+	}
+	if result.NullMX {
+		response.Reason = nullMXReason
+	}
+	if result.Age != nil {
+		days := result.Age.Days
+		response.DomainAgeDays = &days
+		response.IsNewDomain = result.Age.IsNew
+	}
+	response.Status = domainStatus(&response, opts.disposablePolicy(s.disposablePolicy))
+	response.ReasonCode = domainReasonCode(&response)
+	return response, nil
+}
+
+// domainStatus returns the status of a domain validated on its own. A domain that passes
+// but is parked, disposable under a non-rejecting policy, or whose checks were
+// inconclusive, is PROBABLY_VALID.
+func domainStatus(response *model.DomainValidationResponse, disposablePolicy DisposablePolicy) model.ValidationStatus {
+	validations := response.Validations
+	switch {
+	case validations.UnknownTLD:
+		return model.ValidationStatusUnknownTLD
+	case !validations.DomainExists:
+		return model.ValidationStatusInvalidDomain
+	case !validations.MXRecords:
+		return model.ValidationStatusNoMXRecords
+	case validations.IsDisposable && disposablePolicy.rejects():
+		return model.ValidationStatusDisposable
+	case validations.IsDisposable || validations.IsParked || len(response.Inconclusive) > 0:
+		return model.ValidationStatusProbablyValid
+	default:
+		return model.ValidationStatusValid
+	}
+}
+
+// domainReasonCode returns the primary cause of a domain result that is not VALID, in the
+// same order of precedence as for addresses
+func domainReasonCode(response *model.DomainValidationResponse) model.ReasonCode {
+	validations := response.Validations
+	switch {
+	case response.Status == model.ValidationStatusValid:
+		return ""
+	case validations.UnknownTLD:
+		return model.ReasonUnknownTLD
+	case slices.Contains(response.TimedOut, CheckDomainExists):
+		return model.ReasonTimeout
+	case slices.Contains(response.Inconclusive, CheckDomainExists) || slices.Contains(response.Inconclusive, CheckMXRecords):
+		return model.ReasonDNSTimeout
+	case validations.NullMX:
+		return model.ReasonNullMX
+	case !validations.DomainExists:
+		return model.ReasonDomainNotFound
+	case validations.IsParked:
+		return model.ReasonParkedDomain
+	case !validations.MXRecords:
+		return model.ReasonNoMX
+	default:
+		return model.ReasonDisposable
+	}
+}
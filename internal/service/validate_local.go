@@ -0,0 +1,88 @@
+package service
+
+import (
+	"sync/atomic"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/validator"
+)
+
+// ValidateEmailLocalOnly runs only the checks that need no network I/O: syntax, the TLD
+// list, the disposable lists and heuristics, role and free-provider detection, typo
+// suggestions and alias detection. It never makes a DNS lookup, an SMTP probe or a cache
+// call, so it answers in microseconds and suits latency-critical paths such as inline form
+// validation. Nothing is learned from the result and no event is emitted.
+//
+// Deliverability is unchecked, so an address that passes every local check is
+// PROBABLY_VALID, never VALID. Under the reject disposable policy a disposable address is
+// DISPOSABLE, as in full validation.
+func (s *EmailService) ValidateEmailLocalOnly(email string, opts ValidationOptions) model.QuickValidationResponse {
+	atomic.AddInt64(&s.requests, 1)
+	response := model.QuickValidationResponse{Email: email}
+	if email == "" {
+		response.Status = model.ValidationStatusMissingEmail
+		response.ReasonCode = model.ReasonMissingEmail
+		return response
+	}
+	response.DomainOriginal, response.DomainNormalized, _ = domainForms(email)
+
+	response.Validations.Syntax, response.Reason = checkSyntax(s.emailRuleValidator, email, opts.syntaxMode(s.syntaxMode))
+	domain, ok := splitDomain(email)
+	if !response.Validations.Syntax || !ok {
+		response.Status = model.ValidationStatusInvalidFormat
+		response.ReasonCode = model.ReasonSyntaxInvalid
+		return response
+	}
+
+	if tldChecker, ok := s.domainValidator.(TLDChecker); ok && !tldChecker.HasKnownTLD(domain) {
+		response.Validations.UnknownTLD = true
+	} else {
+		response.Validations.IsDisposable, response.DisposableHeuristic = s.isDisposableLocal(domain)
+	}
+	response.Validations.IsRoleBased = s.emailRuleValidator.IsRoleBased(email)
+	response.Validations.IsFreeProvider = isFreeProvider(s.emailRuleValidator, email)
+
+	address := validator.TrimTrailingDot(email)
+	if suggestions := typoSuggestions(s.emailRuleValidator, address, opts); len(suggestions) > 0 {
+		response.TypoSuggestion = suggestions[0]
+	}
+	if canonicalEmail := s.emailRuleValidator.DetectAlias(address); canonicalEmail != "" && canonicalEmail != address {
+		response.AliasOf = canonicalEmail
+	}
+	response.Addressing = addressingCapabilities(s.emailRuleValidator, domain)
+	response.Canonical = canonicalize(s.emailRuleValidator, address)
+	response.SubaddressTag, response.HasSubaddress = detectSubaddress(s.emailRuleValidator, address)
+
+	switch {
+	case response.Validations.UnknownTLD:
+		response.Status = model.ValidationStatusUnknownTLD
+		response.ReasonCode = model.ReasonUnknownTLD
+	case response.Validations.IsDisposable && opts.disposablePolicy(s.disposablePolicy).rejects():
+		response.Status = model.ValidationStatusDisposable
+		response.ReasonCode = model.ReasonDisposable
+	default:
+		response.Status = model.ValidationStatusProbablyValid
+		if response.TypoSuggestion != "" {
+			response.ReasonCode = model.ReasonPossibleTypo
+		}
+	}
+	return response
+}
+
+// isDisposableLocal checks domain against the in-memory disposable lists and, for domains
+// they do not know, the heuristics. Unlike the domain validation service, it bypasses the
+// disposable cache, which may live in Redis.
+func (s *EmailService) isDisposableLocal(domain string) (bool, *model.DisposableHeuristic) {
+	if s.domainValidator.IsDisposable(domain) {
+		return true, nil
+	}
+	scorer, ok := s.domainValidator.(DisposableHeuristicScorer)
+	if !ok || s.heuristicThreshold <= 0 {
+		return false, nil
+	}
+	score, rules := scorer.DisposableHeuristic(domain)
+	if score == 0 {
+		return false, nil
+	}
+	return score >= s.heuristicThreshold, &model.DisposableHeuristic{Score: score, Rules: rules}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"emailvalidator/internal/model"
+)
+
+// DefaultValidationTimeout bounds a single-email validation unless configured otherwise
+const DefaultValidationTimeout = 30 * time.Second
+
+// stageOutcome is the result of a validation stage run by runStage
+type stageOutcome[T any] struct {
+	value    T
+	panicked any
+}
+
+// runStage runs stage and returns its result, or reports false if ctx is done first. The
+// stage keeps running in the background after the deadline, but its result is discarded.
+// Panics are re-raised on the caller's goroutine, so they are recovered as before.
+func runStage[T any](ctx context.Context, stage func() T) (T, bool) {
+	var zero T
+	if ctx.Err() != nil {
+		return zero, false
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return stage(), true
+	}
+
+	done := make(chan stageOutcome[T], 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- stageOutcome[T]{panicked: r}
+			}
+		}()
+		done <- stageOutcome[T]{value: stage()}
+	}()
+
+	select {
+	case outcome := <-done:
+		if outcome.panicked != nil {
+			panic(outcome.panicked)
+		}
+		return outcome.value, true
+	case <-ctx.Done():
+		return zero, false
+	}
+}
+
+// markTimedOut records checks that did not finish before the validation deadline. They
+// are also inconclusive, so the unknown policy and reason codes treat them like any
+// other check without a verdict.
+func markTimedOut(response *model.EmailValidationResponse, checks ...string) {
+	response.TimedOut = append(response.TimedOut, checks...)
+	// Copy before appending: the slice may be shared with a domain result
+	inconclusive := response.Inconclusive[:len(response.Inconclusive):len(response.Inconclusive)]
+	for _, check := range checks {
+		if !slices.Contains(inconclusive, check) {
+			inconclusive = append(inconclusive, check)
+		}
+	}
+	response.Inconclusive = inconclusive
+}
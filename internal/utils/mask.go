@@ -0,0 +1,19 @@
+package utils
+
+import "strings"
+
+// MaskEmail hides the local part of email except its first character, keeping the domain
+// for diagnostics: "jane.doe@example.com" becomes "j***@example.com". Input without an
+// "@" is masked entirely.
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return "***"
+	}
+	if at == 0 {
+		return "***" + email[at:]
+	}
+	// Keep the whole first rune, so multi-byte characters are not split
+	first := []rune(email[:at])[0]
+	return string(first) + "***" + email[at:]
+}
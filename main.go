@@ -2,103 +2,679 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
-	"email-validator/internal/api"
-	"email-validator/internal/service"
-	"email-validator/pkg/cache"
-	"email-validator/pkg/monitoring"
-	"email-validator/pkg/validator"
-
-	"github.com/go-redis/redis/v8"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"emailvalidator/internal/api"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/cache"
+	"emailvalidator/pkg/events"
+	"emailvalidator/pkg/monitoring"
+	"emailvalidator/pkg/validator"
 )
 
 func main() {
 	// 1. Configuration parsing
 	port := flag.String("port", os.Getenv("PORT"), "Port to listen on")
 	redisURL := flag.String("redis-url", os.Getenv("REDIS_URL"), "Redis connection URL")
+	redisTLSCAFile := flag.String("redis-tls-ca-file", os.Getenv("REDIS_TLS_CA_FILE"), "PEM file of certificate authorities trusted for Redis TLS connections in addition to the system roots")
+	redisTLSCertFile := flag.String("redis-tls-cert-file", os.Getenv("REDIS_TLS_CERT_FILE"), "PEM client certificate presented to Redis; requires -redis-tls-key-file")
+	redisTLSKeyFile := flag.String("redis-tls-key-file", os.Getenv("REDIS_TLS_KEY_FILE"), "PEM key of the Redis client certificate")
+	redisTLSInsecure := flag.Bool("redis-tls-insecure-skip-verify", envBoolOrDefault("REDIS_TLS_INSECURE_SKIP_VERIFY", false), "Accept any Redis server certificate; for testing only")
+	cacheBackend := flag.String("cache-backend", os.Getenv("CACHE_BACKEND"), "Cache backend for results and disposable determinations: redis, memcached or memory; defaults to redis when a Redis URL is set")
+	memcachedAddr := flag.String("memcached-addr", envOrDefault("MEMCACHED_ADDR", "127.0.0.1:11211"), "Memcached server (host:port) for the memcached cache backend")
+	redisBreakerThreshold := flag.Int("redis-breaker-threshold", envIntOrDefault("REDIS_BREAKER_THRESHOLD", cache.DefaultBreakerThreshold), "Consecutive Redis errors after which the cache is bypassed")
+	redisBreakerCooldown := flag.Duration("redis-breaker-cooldown", envDurationOrDefault("REDIS_BREAKER_COOLDOWN", cache.DefaultBreakerCooldown), "How long the cache is bypassed after repeated Redis errors before Redis is tried again")
 	prometheusEnabled := flag.Bool("prometheus-enabled", os.Getenv("PROMETHEUS_ENABLED") == "true", "Enable Prometheus metrics")
+	accessLog := flag.Bool("access-log", os.Getenv("ACCESS_LOG") == "true", "Write a structured JSON access log line to stdout for every API request")
+	trustedProxies := flag.String("trusted-proxies", os.Getenv("TRUSTED_PROXIES"), "Comma-separated CIDR ranges of reverse proxies whose X-Forwarded-For header is trusted for the access log client IP, e.g. 10.0.0.0/8")
+	metricsBackend := flag.String("metrics-backend", envOrDefault("METRICS_BACKEND", monitoring.BackendPrometheus), "Metrics backend: prometheus, statsd or otlp")
+	statsdAddr := flag.String("statsd-addr", envOrDefault("STATSD_ADDR", "127.0.0.1:8125"), "StatsD/DogStatsD address (host:port) for the statsd metrics backend")
+	otlpEndpoint := flag.String("otlp-endpoint", envOrDefault("OTLP_ENDPOINT", "http://127.0.0.1:4318/v1/metrics"), "OTLP/HTTP metrics endpoint for the otlp metrics backend")
+	disposableSources := flag.String("disposable-sources", os.Getenv("DISPOSABLE_SOURCES"), "Comma-separated disposable list URLs, each optionally prefixed with a parser (plaintext=, json=, csv=) and followed by ;diff= and the URL serving its diffs")
+	disposableConcurrency := flag.Int("disposable-concurrency", envIntOrDefault("DISPOSABLE_CONCURRENCY", validator.DefaultBlocklistConcurrency), "Number of disposable list sources fetched at the same time")
+	disposableLoadTimeout := flag.Duration("disposable-load-timeout", envDurationOrDefault("DISPOSABLE_LOAD_TIMEOUT", validator.DefaultBlocklistLoadTimeout), "Time allowed for fetching every disposable list source at startup; slower sources are skipped. 0 sets no deadline")
+	disposableFile := flag.String("disposable-file", os.Getenv("DISPOSABLE_FILE"), "File of disposable domains, one per line (defaults to config/disposable_domains.txt, or the copy embedded in the binary)")
+	disposableMatchFlag := flag.String("disposable-match-strategy", os.Getenv("DISPOSABLE_MATCH_STRATEGY"), "Whether disposable list entries cover subdomains: exact, suffix, or annotated (entries with a leading dot)")
+	disposablePatternFile := flag.String("disposable-pattern-file", os.Getenv("DISPOSABLE_PATTERN_FILE"), "File of regular expressions flagging disposable domains the disposable lists miss, one per line")
+	allowlistFile := flag.String("disposable-allowlist-file", os.Getenv("DISPOSABLE_ALLOWLIST_FILE"), "File of domains never treated as disposable (supports *.example.com)")
+	heuristicThreshold := flag.Float64("disposable-heuristic-threshold", envFloatOrDefault("DISPOSABLE_HEURISTIC_THRESHOLD", 0), "Heuristic score (0-1) at which a domain missing from the disposable lists is treated as disposable; 0 disables the heuristics")
+	parallelDomainChecks := flag.Bool("parallel-domain-checks", envBoolOrDefault("PARALLEL_DOMAIN_CHECKS", true), "Run a domain's existence, MX, disposable and parked checks concurrently instead of one after another")
+	parkedDomainCheck := flag.Bool("parked-domain-check", envBoolOrDefault("PARKED_DOMAIN_CHECK", true), "Look up domains' nameservers and flag domains delegated to a parking service")
+	allowDotless := flag.Bool("allow-dotless-domains", envBoolOrDefault("ALLOW_DOTLESS_DOMAINS", false), "Accept single-label domains such as user@intranet and resolve them through the configured DNS")
+	mxWalkUp := flag.Int("mx-walk-up", envIntOrDefault("MX_WALK_UP_LEVELS", 0), "Parent domain levels searched for MX records when a subdomain has none of its own; 0 disables the walk-up")
+	disposableMXCheck := flag.Bool("disposable-mx-check", envBoolOrDefault("DISPOSABLE_MX_CHECK", false), "Look up the MX records of domains missing from the disposable lists and flag those whose mail servers belong to a disposable provider")
+	disposableMXFile := flag.String("disposable-mx-file", os.Getenv("DISPOSABLE_MX_FILE"), "File of disposable providers' mail server domains, one per line (defaults to the built-in list)")
+	disposableMXCacheTTL := flag.Duration("disposable-mx-cache-ttl", envDurationOrDefault("DISPOSABLE_MX_CACHE_TTL", validator.DefaultDisposableMXCacheTTL), "How long a domain's mail server domains are cached by the disposable MX check")
+	parkingNSFile := flag.String("parking-ns-file", os.Getenv("PARKING_NS_FILE"), "File of parking service nameservers, one per line, wildcards like *.sedoparking.com allowed (defaults to the built-in list)")
+	heuristicRulesFile := flag.String("disposable-heuristic-rules-file", os.Getenv("DISPOSABLE_HEURISTIC_RULES_FILE"), "File of disposable heuristic rules, one \"name weight pattern\" per line (defaults to the built-in rules)")
+	roleFile := flag.String("role-file", os.Getenv("ROLE_FILE"), "File of role-based local parts, one per line (defaults to the built-in list); the fallback when role-url is set")
+	roleURL := flag.String("role-url", os.Getenv("ROLE_URL"), "Comma-separated URLs of role-based local part lists, each optionally prefixed with a parser (plaintext=, json=, csv=)")
+	freeProviderFile := flag.String("free-provider-file", os.Getenv("FREE_PROVIDER_FILE"), "File of free mailbox provider domains, one per line (defaults to the built-in list); the fallback when free-provider-url is set")
+	freeProviderURL := flag.String("free-provider-url", os.Getenv("FREE_PROVIDER_URL"), "Comma-separated URLs of free mailbox provider lists, each optionally prefixed with a parser (plaintext=, json=, csv=)")
+	listRefreshInterval := flag.Duration("list-refresh-interval", envDurationOrDefault("LIST_REFRESH_INTERVAL", validator.DefaultListRefreshInterval), "How often role and free provider lists loaded from URLs are re-fetched; 0 disables refreshes")
+	typoDictionaryDir := flag.String("typo-dictionary-dir", os.Getenv("TYPO_DICTIONARY_DIR"), "Directory of regional typo dictionaries (<region>.csv, global.csv) that extend the built-in ones")
+	addressingFile := flag.String("addressing-file", os.Getenv("ADDRESSING_FILE"), "CSV of provider addressing capabilities that extends or overrides the built-in table")
+	tldFile := flag.String("tld-file", os.Getenv("TLD_FILE"), "File of existing TLDs in the IANA format (defaults to config/tlds.txt, or the copy embedded in the binary)")
+	tldUpdateInterval := flag.Duration("tld-update-interval", envDurationOrDefault("TLD_UPDATE_INTERVAL", 24*time.Hour), "How often to refresh the TLD list from IANA; 0 disables updates")
+	unknownPolicyFlag := flag.String("unknown-policy", os.Getenv("UNKNOWN_POLICY"), "How inconclusive checks are treated: strict or lenient")
+	blockedDomains := flag.String("blocked-domains", os.Getenv("BLOCKED_DOMAINS"), "Comma-separated domains whose addresses are always rejected, subdomains included")
+	domainScoreBoosts := flag.String("domain-score-boosts", os.Getenv("DOMAIN_SCORE_BOOSTS"), "Score adjustments for deliverable addresses at given domains as domain=points pairs, e.g. partner.com=10")
+	greylistPolicyFlag := flag.String("greylist-policy", os.Getenv("GREYLIST_POLICY"), "Outcome of a greylisted mailbox check: uncertain, valid or invalid")
+	disposablePolicyFlag := flag.String("disposable-policy", os.Getenv("DISPOSABLE_POLICY"), "How disposable domains are treated: reject, flag or score")
+	statusPrecedenceFlag := flag.String("status-precedence", os.Getenv("STATUS_PRECEDENCE"), "Which signal decides the status when they disagree, highest first: disposable, invalid_mailbox and catch_all separated by >, e.g. invalid_mailbox>disposable>catch_all")
+	syntaxModeFlag := flag.String("syntax-mode", os.Getenv("SYNTAX_MODE"), "Address syntax accepted: lenient, rfc5322 or rfc5321")
+	revalidationPolicyFlag := flag.String("revalidation-policy", os.Getenv("REVALIDATION_POLICY"), "Suggested revalidation intervals overriding the defaults, as outcome=duration pairs, e.g. VALID=2160h,CATCH_ALL=72h")
+	scoreScaleFlag := flag.String("score-scale", os.Getenv("SCORE_SCALE"), "How scores are reported: percent (0-100), probability (0.0-1.0) or grade (A-F)")
+	smtpProbe := flag.Bool("smtp-probe", os.Getenv("SMTP_PROBE") == "true", "Probe mailboxes over SMTP; implied by -smtp-helo")
+	smtpHelo := flag.String("smtp-helo", os.Getenv("SMTP_HELO"), "FQDN resolving to the sending IP, used as the HELO name for SMTP mailbox probes (defaults to the sending IP's reverse DNS name or the host name)")
+	smtpSourceIP := flag.String("smtp-source-ip", os.Getenv("SMTP_SOURCE_IP"), "Local address SMTP mailbox probes connect from, on hosts with several addresses (defaults to the operating system's choice)")
+	smtpProxy := flag.String("smtp-proxy", os.Getenv("SMTP_PROXY"), "SOCKS5 proxy SMTP mailbox probes connect through, as socks5://[user:password@]host:port; set SMTP_HELO to a name resolving to the proxy's address")
+	smtpSender := flag.String("smtp-sender", envOrDefault("SMTP_SENDER", validator.NullSender), "MAIL FROM sender for SMTP mailbox probes: an address, a domain or <>")
+	smtpConnectTimeout := flag.Duration("smtp-connect-timeout", envDurationOrDefault("SMTP_CONNECT_TIMEOUT", validator.DefaultSMTPConnectTimeout), "Time allowed for connecting to each mail server during SMTP probes")
+	smtpCommandTimeout := flag.Duration("smtp-command-timeout", envDurationOrDefault("SMTP_COMMAND_TIMEOUT", validator.DefaultSMTPCommandTimeout), "Time allowed for each mail server reply once connected during SMTP probes")
+	smtpBreakerThreshold := flag.Int("smtp-breaker-threshold", envIntOrDefault("SMTP_BREAKER_THRESHOLD", validator.DefaultSMTPBreakerThreshold), "Consecutive failed SMTP probes to a domain after which its probes are skipped; 0 disables the breaker")
+	smtpProbeRate := flag.Int("smtp-probe-rate", envIntOrDefault("SMTP_PROBE_RATE", 0), "SMTP probes allowed per minute across the whole process; 0 is unlimited")
+	smtpProviderRates := flag.String("smtp-provider-rates", os.Getenv("SMTP_PROVIDER_RATES"), "Per-provider SMTP probes per minute as domain=limit pairs, e.g. google.com=60,outlook.com=120")
+	smtpProbeRateWait := flag.Duration("smtp-probe-rate-wait", envDurationOrDefault("SMTP_PROBE_RATE_WAIT", validator.DefaultSMTPProbeRateWait), "How long a probe may wait for the rate budget before the mailbox is reported inconclusive")
+	smtpBreakerCooldown := flag.Duration("smtp-breaker-cooldown", envDurationOrDefault("SMTP_BREAKER_COOLDOWN", validator.DefaultSMTPBreakerCooldown), "How long SMTP probes to a failing domain are skipped before it is tried again")
+	catchAllCeiling := flag.Int("catch-all-ceiling", envIntOrDefault("CATCH_ALL_CEILING", service.DefaultConfidencePenalties().CatchAllCeiling), "Highest score (percent) for addresses on catch-all mail servers")
+	greylistCeiling := flag.Int("greylist-ceiling", envIntOrDefault("GREYLIST_CEILING", service.DefaultConfidencePenalties().GreylistCeiling), "Highest score (percent) for addresses whose mailbox check was greylisted")
+	domainAgeEnabled := flag.Bool("domain-age", os.Getenv("DOMAIN_AGE_ENABLED") == "true", "Look up domain registration dates over RDAP and flag new domains")
+	newDomainDays := flag.Int("new-domain-days", envIntOrDefault("NEW_DOMAIN_DAYS", 30), "Domains registered fewer than this many days ago are flagged as new")
+	eventsStream := flag.String("events-stream", os.Getenv("EVENTS_STREAM"), "Redis stream that receives a validation event per result (requires -redis-url)")
+	eventsBuffer := flag.Int("events-buffer", envIntOrDefault("EVENTS_BUFFER", 1000), "Maximum number of pending validation events; further events are dropped")
+	typoLearning := flag.Bool("typo-learning", os.Getenv("TYPO_LEARNING") == "true", "Learn typo corrections from domains that validate successfully (requires -redis-url)")
+	typoLearningHalfLife := flag.Duration("typo-learning-half-life", envDurationOrDefault("TYPO_LEARNING_HALF_LIFE", validator.DefaultTypoLearningHalfLife), "Time after which a learned domain observation counts half as much")
+	minSuggestionConfidence := flag.Float64("min-suggestion-confidence", envFloatOrDefault("MIN_SUGGESTION_CONFIDENCE", validator.DefaultMinSuggestionConfidence), "Lowest confidence (0-1) at which a typo suggestion is returned")
+	canonicalLocalCaseFlag := flag.String("canonical-local-case", os.Getenv("CANONICAL_LOCAL_CASE"), "Where canonical addresses have their local part lowercased: preserve, provider (case-insensitive providers only) or lower")
+	canonicalStripDots := flag.Bool("canonical-strip-dots", envBoolOrDefault("CANONICAL_STRIP_DOTS", true), "Remove dots from the local part of canonical addresses at dot-insensitive providers")
+	canonicalStripSubaddress := flag.Bool("canonical-strip-subaddress", envBoolOrDefault("CANONICAL_STRIP_SUBADDRESS", true), "Remove +tags and subdomain addressing from canonical addresses at providers that support them")
+	canonicalUnifyDomains := flag.Bool("canonical-unify-domains", envBoolOrDefault("CANONICAL_UNIFY_DOMAINS", true), "Replace provider domain aliases (googlemail.com) with the main domain in canonical addresses")
+	dnsTimeout := flag.Duration("dns-timeout", envDurationOrDefault("DNS_TIMEOUT", validator.DefaultDNSTimeout), "Time allowed for each DNS lookup")
+	dnsConcurrency := flag.Int("dns-concurrency", envIntOrDefault("DNS_CONCURRENCY", 0), "Most DNS lookups in flight at once; further lookups wait for a free slot. 0 leaves lookups unlimited")
+	dnsTCPFallback := flag.Bool("dns-tcp-fallback", envBoolOrDefault("DNS_TCP_FALLBACK", true), "Retry a DNS query over TCP when its UDP answer is truncated; when disabled, a truncated answer is reported as inconclusive")
+	dnsMaxMXRecords := flag.Int("dns-max-mx-records", envIntOrDefault("DNS_MAX_MX_RECORDS", validator.DefaultMaxMXRecords), "Most MX records considered per domain, keeping the most preferred. 0 considers them all")
+	httpTimeout := flag.Duration("http-timeout", envDurationOrDefault("HTTP_TIMEOUT", validator.DefaultHTTPTimeout), "Time allowed for each outbound HTTP request (blocklists, TLD list, RDAP)")
+	httpCABundle := flag.String("http-ca-bundle", os.Getenv("HTTP_CA_BUNDLE"), "PEM file of certificate authorities trusted for outbound HTTPS in addition to the system roots")
+	httpUserAgent := flag.String("http-user-agent", envOrDefault("HTTP_USER_AGENT", validator.DefaultUserAgent()), "User-Agent sent with outbound HTTP requests")
+	httpProxy := flag.String("http-proxy", os.Getenv("HTTP_PROXY_URL"), "Proxy for outbound HTTP requests (http://, https:// or socks5://); defaults to the HTTP_PROXY/HTTPS_PROXY environment variables")
+	resultCacheTTL := flag.Duration("result-cache-ttl", envDurationOrDefault("RESULT_CACHE_TTL", 0), "How long single-email validation results are cached; 0 disables result caching (requires a cache backend)")
+	disposableCacheTTL := flag.Duration("disposable-cache-ttl", envDurationOrDefault("DISPOSABLE_CACHE_TTL", cache.DefaultDisposableTTL), "How long disposable determinations are cached, independently of DNS results; 0 disables the cache (requires a cache backend)")
+	validationTimeout := flag.Duration("validation-timeout", envDurationOrDefault("VALIDATION_TIMEOUT", service.DefaultValidationTimeout), "Longest a single-email validation may take; checks still running are reported as timed out. 0 waits for every check")
+	batchTimeout := flag.Duration("batch-timeout", envDurationOrDefault("BATCH_TIMEOUT", 0), "Deadline for validating a batch, after which the completed results are returned and the rest reported as NOT_PROCESSED; 0 waits for every address")
+	batchWorkers := flag.Int("batch-workers", envIntOrDefault("BATCH_WORKERS", 0), "Addresses of a batch validated at the same time; 0 uses four per CPU")
+	batchDomainConcurrency := flag.Int("batch-domain-concurrency", envIntOrDefault("BATCH_DOMAIN_CONCURRENCY", 0), "Distinct domains of a batch checked at the same time; 0 checks them all at once")
+	maxBatchSize := flag.Int("max-batch-size", envIntOrDefault("MAX_BATCH_SIZE", service.DefaultMaxBatchSize), "Largest number of emails accepted in one batch request; 0 removes the limit")
+	batchSourceHosts := flag.String("batch-source-hosts", os.Getenv("BATCH_SOURCE_HOSTS"), "Comma-separated hosts batch lists may be fetched from with a source_url, e.g. lists.example.com,*.s3.amazonaws.com; * allows any public host. Empty disables source_url")
+	batchSourceMaxBytes := flag.Int("batch-source-max-bytes", envIntOrDefault("BATCH_SOURCE_MAX_BYTES", api.DefaultMaxSourceBytes), "Largest email list fetched from a batch request's source_url or uploaded for a file report, in bytes")
+	explainToken := flag.String("explain-token", os.Getenv("EXPLAIN_TOKEN"), "Bearer token for the /api/validate/explain debug endpoint; the endpoint is disabled when empty")
+	requestTimeout := flag.Duration("request-timeout", envDurationOrDefault("REQUEST_TIMEOUT", 0), "Longest an API request may take before it gets 503, for endpoints without their own timeout; 0 leaves them unbounded")
+	endpointTimeoutsFlag := flag.String("endpoint-timeouts", os.Getenv("ENDPOINT_TIMEOUTS"), "Comma-separated per-endpoint request timeouts overriding request-timeout, e.g. /api/typo-suggestions=2s,/api/validate/batch=5m")
+	adminToken := flag.String("admin-token", os.Getenv("ADMIN_TOKEN"), "Bearer token for the /api/admin endpoints; they are disabled when empty")
 	flag.Parse()
 
 	if *port == "" {
 		*port = "8080"
 	}
 
-	// 2. Initialize Redis client (if Redis URL is provided)
-	var redisClient *redis.Client
-	if *redisURL != "" {
-		opt, err := redis.ParseURL(*redisURL)
+	unknownPolicy, err := service.ParseUnknownPolicy(*unknownPolicyFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	greylistPolicy, err := service.ParseGreylistPolicy(*greylistPolicyFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	scoreBoosts, err := service.ParseDomainScoreBoosts(*domainScoreBoosts)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	disposablePolicy, err := service.ParseDisposablePolicy(*disposablePolicyFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	statusPrecedence, err := service.ParseStatusPrecedence(*statusPrecedenceFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	// CANONICAL_LOWERCASE_LOCAL=false predates CANONICAL_LOCAL_CASE and still keeps the case
+	if *canonicalLocalCaseFlag == "" && !envBoolOrDefault("CANONICAL_LOWERCASE_LOCAL", true) {
+		*canonicalLocalCaseFlag = string(validator.LocalPartCasePreserve)
+	}
+	canonicalLocalCase, err := validator.ParseLocalPartCase(*canonicalLocalCaseFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	syntaxMode, err := validator.ParseSyntaxMode(*syntaxModeFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	scoreScale, err := service.ParseScoreScale(*scoreScaleFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	revalidationPolicy, err := service.ParseRevalidationPolicy(*revalidationPolicyFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	disposableMatch, err := validator.ParseMatchStrategy(*disposableMatchFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	blocklistSources, err := validator.ParseBlocklistSources(*disposableSources)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	endpointTimeouts, err := api.ParseEndpointTimeouts(*endpointTimeoutsFlag)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	roleSources, err := validator.ParseBlocklistSources(*roleURL)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	freeProviderSources, err := validator.ParseBlocklistSources(*freeProviderURL)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	// One client for every outbound HTTP fetch, so they share a connection pool and network policy
+	httpClient, err := validator.NewHTTPClient(validator.HTTPClientConfig{
+		ProxyURL:  *httpProxy,
+		Timeout:   *httpTimeout,
+		CABundle:  *httpCABundle,
+		UserAgent: *httpUserAgent,
+	})
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	confidencePenalties := service.ConfidencePenalties{
+		CatchAllCeiling: *catchAllCeiling,
+		GreylistCeiling: *greylistCeiling,
+	}
+	if err := confidencePenalties.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// 2. Initialize the metrics backend
+	if err := monitoring.ValidateBackendName(*metricsBackend); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	switch *metricsBackend {
+	case monitoring.BackendStatsD:
+		statsd, err := monitoring.NewStatsDBackend(*statsdAddr, "")
 		if err != nil {
-			log.Fatalf("Failed to parse Redis URL: %v", err)
+			log.Fatalf("Failed to initialize StatsD metrics backend: %v", err)
+		}
+		defer func() {
+			if err := statsd.Close(); err != nil {
+				log.Printf("Error closing StatsD metrics backend: %v", err)
+			}
+		}()
+		monitoring.SetBackend(statsd)
+		log.Printf("Sending metrics to StatsD at %s", *statsdAddr)
+	case monitoring.BackendOTLP:
+		otlp := monitoring.NewOTLPBackendWithClient(httpClient, *otlpEndpoint, "email-validator", 15*time.Second)
+		otlp.Start()
+		defer func() {
+			if err := otlp.Close(); err != nil {
+				log.Printf("Error flushing OTLP metrics: %v", err)
+			}
+		}()
+		monitoring.SetBackend(otlp)
+		log.Printf("Pushing metrics to OTLP endpoint %s", *otlpEndpoint)
+	}
+
+	// Redis TLS settings apply to every Redis connection: cache, typo learning and events.
+	// Setting any of them connects over TLS even for a redis:// URL.
+	redisTLSConfig, err := cache.RedisTLSOptions{
+		CAFile:             *redisTLSCAFile,
+		CertFile:           *redisTLSCertFile,
+		KeyFile:            *redisTLSKeyFile,
+		InsecureSkipVerify: *redisTLSInsecure,
+	}.TLSConfig()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	redisTLS := cache.WithRedisTLS(redisTLSConfig)
+
+	// 3. Initialize the cache backend (Redis by default when a Redis URL is provided)
+	var resultCache *service.ResultCache
+	var disposableCache *cache.DisposableCache
+	if *cacheBackend == "" && *redisURL != "" {
+		*cacheBackend = cache.BackendRedis
+	}
+	if *cacheBackend != "" {
+		address := *redisURL
+		if *cacheBackend == cache.BackendMemcached {
+			address = *memcachedAddr
 		}
-		redisClient = redis.NewClient(opt)
-		_, err = redisClient.Ping(context.Background()).Result()
+		client, err := cache.New(*cacheBackend, address, redisTLS)
 		if err != nil {
-			log.Fatalf("Failed to connect to Redis: %v", err)
+			log.Fatalf("Failed to initialize the %s cache: %v", *cacheBackend, err)
+		}
+		// A cache outage after startup degrades to cache misses rather than failing requests
+		sharedCache := cache.NewCircuitBreakerCache(client, *redisBreakerThreshold, *redisBreakerCooldown)
+		defer func() {
+			if err := sharedCache.Close(); err != nil {
+				log.Printf("Error closing the %s cache: %v", *cacheBackend, err)
+			}
+		}()
+		log.Printf("Caching in %s.", *cacheBackend)
+		if *resultCacheTTL > 0 {
+			resultCache = service.NewResultCache(sharedCache, *resultCacheTTL)
+		}
+		if *disposableCacheTTL > 0 {
+			disposableCache = cache.NewDisposableCache(sharedCache, *disposableCacheTTL)
 		}
-		log.Println("Connected to Redis.")
 	}
 
-	// 3. Initialize Caching
-	domainCache := cache.NewRedisDomainCache(redisClient, 10*time.Minute) // Assuming 10 min cache duration
+	// Cached disposable determinations are keyed by the contents of the lists they depend
+	// on, so they stay valid across restarts and instances exactly while the lists do
+	disposableListFiles := []string{*disposableFile, *disposablePatternFile, *disposableMXFile, *allowlistFile}
+	if *disposableFile == "" {
+		if path, err := validator.DefaultDisposableFile(); err == nil {
+			disposableListFiles = append(disposableListFiles, path)
+		}
+	}
+	setDisposableCacheVersion := func() error {
+		if disposableCache == nil {
+			return nil
+		}
+		hash, err := validator.HashFiles(disposableListFiles...)
+		if err != nil {
+			return fmt.Errorf("failed to hash disposable lists: %w", err)
+		}
+		disposableCache.SetVersion(string(disposableMatch) + "-" + hash)
+		return nil
+	}
+	if err := setDisposableCacheVersion(); err != nil {
+		log.Fatalf("Failed to initialize the disposable cache: %v", err)
+	}
 
 	// 4. Initialize Validators
-	dnsResolver := validator.NewDNSResolver()
-	domainValidator := validator.NewDomainValidator(dnsResolver, domainCache)
-	disposableValidator := validator.NewDisposableValidator()
-	roleValidator := validator.NewRoleValidator()
-	aliasDetector := validator.NewAliasDetector()
-	syntaxValidator := validator.NewSyntaxValidator()
+	emailValidator, err := validator.NewEmailValidator()
+	if err != nil {
+		log.Fatalf("Failed to initialize email validator: %v", err)
+	}
+	resolver := validator.NewDefaultResolver(*dnsTimeout)
+	resolver.SetConcurrencyLimit(*dnsConcurrency)
+	resolver.SetTCPFallback(*dnsTCPFallback)
+	resolver.SetMaxMXRecords(*dnsMaxMXRecords)
+	emailValidator.SetResolver(resolver)
+
+	emailValidator.SetCanonicalizationRules(validator.CanonicalizationRules{
+		LocalPartCase:   canonicalLocalCase,
+		StripDots:       *canonicalStripDots,
+		StripSubaddress: *canonicalStripSubaddress,
+		UnifyDomains:    *canonicalUnifyDomains,
+	})
+	emailValidator.SetMinSuggestionConfidence(*minSuggestionConfidence)
+
+	// Lists loaded from URLs are refreshed periodically once the watchers start
+	remoteLists := map[string]validator.Reloader{}
+	if *roleFile != "" || len(roleSources) > 0 {
+		roleValidator, err := validator.NewRoleValidatorWithReader(listReader(httpClient, roleSources, *roleFile))
+		if err != nil {
+			log.Fatalf("Failed to load role list: %v", err)
+		}
+		emailValidator.SetRoleValidator(roleValidator)
+		if len(roleSources) > 0 {
+			remoteLists["role list"] = roleValidator
+		}
+	}
+	if *freeProviderFile != "" || len(freeProviderSources) > 0 {
+		freeProviderValidator, err := validator.NewFreeProviderValidatorWithReader(listReader(httpClient, freeProviderSources, *freeProviderFile))
+		if err != nil {
+			log.Fatalf("Failed to load free provider list: %v", err)
+		}
+		emailValidator.SetFreeProviderValidator(freeProviderValidator)
+		if len(freeProviderSources) > 0 {
+			remoteLists["free provider list"] = freeProviderValidator
+		}
+	}
 
-	// NEW: Initialize the disposable blocklist and load it
-	disposableBlocklist := validator.NewDisposableBlocklist()
-	if err := disposableBlocklist.Load(); err != nil {
-		log.Fatalf("Failed to load disposable blocklist: %v", err)
+	if *disposableFile != "" {
+		disposableValidator, err := validator.NewDisposableValidatorWithReader(validator.NewFileDomainReader(*disposableFile))
+		if err != nil {
+			log.Fatalf("Failed to load disposable domain list: %v", err)
+		}
+		emailValidator.SetDisposableValidator(disposableValidator)
+	}
+	if disposableMatch != validator.MatchStrategyExact {
+		if err := emailValidator.SetDisposableMatchStrategy(disposableMatch); err != nil {
+			log.Fatalf("Failed to load disposable domain list: %v", err)
+		}
+	}
+	if *disposablePatternFile != "" {
+		patterns, err := validator.NewDisposablePatternsFromFile(*disposablePatternFile)
+		if err != nil {
+			log.Fatalf("Failed to load disposable patterns: %v", err)
+		}
+		emailValidator.SetDisposablePatterns(patterns)
+	}
+
+	if *heuristicRulesFile != "" {
+		detector, err := validator.NewDisposableHeuristicDetectorFromFile(*heuristicRulesFile)
+		if err != nil {
+			log.Fatalf("Failed to load disposable heuristic rules: %v", err)
+		}
+		emailValidator.SetDisposableHeuristicDetector(detector)
+	}
+
+	switch {
+	case !*parkedDomainCheck:
+		emailValidator.SetParkedDomainDetector(nil)
+	case *parkingNSFile != "":
+		detector, err := validator.NewParkedDomainDetectorFromFile(*parkingNSFile)
+		if err != nil {
+			log.Fatalf("Failed to load parking nameserver list: %v", err)
+		}
+		emailValidator.SetParkedDomainDetector(detector)
+	}
+	if *disposableMXCheck {
+		detector := validator.NewDisposableMXDetector()
+		if *disposableMXFile != "" {
+			var err error
+			if detector, err = validator.NewDisposableMXDetectorFromFile(*disposableMXFile); err != nil {
+				log.Fatalf("Failed to load disposable MX provider list: %v", err)
+			}
+		}
+		detector.SetCacheTTL(*disposableMXCacheTTL)
+		emailValidator.SetDisposableMXDetector(detector)
+	}
+	emailValidator.SetMXWalkUp(*mxWalkUp)
+	emailValidator.SetAllowDotlessDomains(*allowDotless)
+
+	if *addressingFile != "" {
+		table, err := validator.LoadAddressingCapabilitiesFromFile(*addressingFile)
+		if err != nil {
+			log.Fatalf("Failed to load addressing capabilities: %v", err)
+		}
+		emailValidator.SetAddressingCapabilities(table)
+		log.Printf("Loaded addressing capabilities for %d domains.", len(table))
+	}
+
+	if *typoDictionaryDir != "" {
+		dictionaries, err := validator.LoadTypoDictionaries(*typoDictionaryDir)
+		if err != nil {
+			log.Fatalf("Failed to load typo dictionaries: %v", err)
+		}
+		emailValidator.SetTypoDictionaries(dictionaries)
+		log.Printf("Loaded typo dictionaries for %d regions.", len(dictionaries))
+	}
+
+	if *tldFile != "" {
+		tlds, err := validator.NewTLDListFromFile(*tldFile)
+		if err != nil {
+			log.Fatalf("Failed to load TLD list: %v", err)
+		}
+		emailValidator.SetTLDList(tlds)
+	}
+
+	// Reload the local role, disposable and parking nameserver lists and the heuristic rules
+	// whenever their files change. Cached disposable determinations made against the old
+	// lists are no longer read, since the lists' version changes with them. Reload keeps
+	// going past a failing list, so the version is updated whether or not it fails.
+	reloadValidator := func() error {
+		err := emailValidator.Reload()
+		return errors.Join(err, setDisposableCacheVersion())
+	}
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	watchedFiles := []string{*roleFile, *freeProviderFile, *heuristicRulesFile, *disposableFile, *disposablePatternFile, *parkingNSFile, *disposableMXFile}
+	if *disposableFile == "" {
+		if path, err := validator.DefaultDisposableFile(); err == nil {
+			watchedFiles = append(watchedFiles, path)
+		}
+	}
+	for _, path := range watchedFiles {
+		if path == "" {
+			continue
+		}
+		if err := validator.WatchFile(watchCtx, path, reloadValidator); err != nil {
+			log.Printf("Warning: Could not watch %s for changes: %v", path, err)
+		}
+	}
+	if *listRefreshInterval > 0 {
+		for name, list := range remoteLists {
+			validator.StartAutoReload(watchCtx, list, name, *listRefreshInterval)
+		}
+	}
+	if tlds := emailValidator.TLDList(); tlds != nil && *tldUpdateInterval > 0 {
+		tlds.StartAutoUpdate(watchCtx, httpClient, validator.IANATLDListURL, *tldUpdateInterval)
+	}
+
+	// Load the disposable blocklist in the background, so startup and requests never wait on
+	// the fetch; until it has loaded, disposable checks report the blocklist as not ready
+	if len(blocklistSources) == 0 {
+		blocklistSources = validator.DefaultBlocklistSources()
+	}
+	disposableBlocklist := validator.NewDisposableBlocklistWithClient(httpClient, blocklistSources...)
+	disposableBlocklist.SetConcurrency(*disposableConcurrency)
+	disposableBlocklist.SetLoadTimeout(*disposableLoadTimeout)
+	disposableBlocklist.SetMatchStrategy(disposableMatch)
+	disposableBlocklist.LoadInBackground(watchCtx, validator.DefaultBlocklistRetryInterval)
+
+	if *allowlistFile != "" {
+		allowlist, err := validator.NewFileDomainReader(*allowlistFile).ReadDomains()
+		if err != nil {
+			log.Fatalf("Failed to load disposable allowlist: %v", err)
+		}
+		emailValidator.SetDisposableAllowlist(allowlist)
+		disposableBlocklist.SetAllowlist(allowlist)
+		log.Printf("Loaded %d disposable allowlist entries.", len(allowlist))
 	}
 
 	// 5. Initialize Services
-	emailService := service.NewEmailService(
-		syntaxValidator,
-		domainValidator,
-		disposableValidator,
-		roleValidator,
-		aliasDetector,
-	)
-	batchValidationService := service.NewBatchValidationService(emailService)
+	emailService := service.NewEmailServiceWithValidator(emailValidator)
+	emailService.SetUnknownPolicy(unknownPolicy)
+	emailService.SetGreylistPolicy(greylistPolicy)
+	emailService.SetDisposablePolicy(disposablePolicy)
+	emailService.SetStatusPrecedence(statusPrecedence)
+	emailService.SetDisposableHeuristicThreshold(*heuristicThreshold)
+	emailService.SetParallelDomainChecks(*parallelDomainChecks)
+	// Organization rules adjust results after validation
+	if *blockedDomains != "" || len(scoreBoosts) > 0 {
+		emailService.SetResultPostProcessors(service.NewDomainRules(strings.Split(*blockedDomains, ","), scoreBoosts))
+	}
+	emailService.SetSyntaxMode(syntaxMode)
+	emailService.SetScoreScale(scoreScale)
+	emailService.SetRevalidationPolicy(revalidationPolicy)
+	emailService.SetConfidencePenalties(confidencePenalties)
+	emailService.SetMaxBatchSize(*maxBatchSize)
+	emailService.SetBatchWorkers(*batchWorkers)
+	emailService.SetBatchDomainConcurrency(*batchDomainConcurrency)
+	emailService.SetBatchTimeout(*batchTimeout)
+	emailService.SetValidationTimeout(*validationTimeout)
+	if resultCache != nil {
+		emailService.SetResultCache(resultCache)
+	}
+	if disposableCache != nil {
+		emailService.SetDisposableCache(disposableCache)
+	}
 
-	// 6. Setup HTTP server
-	mux := http.NewServeMux()
+	if *domainAgeEnabled {
+		emailService.SetDomainAgeChecker(validator.NewDomainAgeCheckerWithClient(httpClient, *newDomainDays))
+		log.Printf("Domain age checks enabled (new domains: under %d days)", *newDomainDays)
+	}
+
+	if *smtpProbe || *smtpHelo != "" {
+		var smtpOpts []validator.SMTPValidatorOption
+		var sendingIP net.IP
+		if *smtpSourceIP != "" {
+			sendingIP = net.ParseIP(*smtpSourceIP)
+			if sendingIP == nil {
+				log.Fatalf("Invalid configuration: SMTP source IP %q is not an IP address", *smtpSourceIP)
+			}
+			if err := validator.CheckSourceIP(sendingIP); err != nil {
+				log.Fatalf("Invalid configuration: %v", err)
+			}
+			smtpOpts = append(smtpOpts, validator.WithSourceIP(sendingIP))
+		} else if sendingIP, err = validator.SendingIP(); err != nil {
+			log.Printf("Warning: Could not determine the sending IP: %v", err)
+		}
+		if *smtpProxy != "" {
+			proxy, err := validator.ParseSOCKS5Proxy(*smtpProxy)
+			if err != nil {
+				log.Fatalf("Invalid configuration: %v", err)
+			}
+			smtpOpts = append(smtpOpts, validator.WithSOCKS5Proxy(proxy))
+			// Mail servers see the proxy's address, which this host cannot know
+			sendingIP = nil
+			if *smtpHelo == "" {
+				log.Printf("Warning: SMTP probes go through %s but SMTP_HELO is not set; the default HELO name is derived from this host, not the proxy", proxy)
+			}
+			log.Printf("SMTP probes connect through SOCKS5 proxy %s", proxy)
+		}
+		helo := *smtpHelo
+		if helo == "" {
+			helo = validator.DefaultHELOName(sendingIP)
+		}
+
+		// Mail servers that distrust the HELO name reject every probe, so check it up front
+		if err := validator.PreflightHELO(helo, resolver, sendingIP); err != nil {
+			log.Printf("Warning: Mail servers may reject SMTP probes: %v", err)
+		}
+
+		smtpValidator := validator.NewSMTPValidator(resolver, helo, *smtpSender, smtpOpts...)
+		smtpValidator.SetConnectTimeout(*smtpConnectTimeout)
+		smtpValidator.SetCommandTimeout(*smtpCommandTimeout)
+		smtpValidator.SetCircuitBreaker(*smtpBreakerThreshold, *smtpBreakerCooldown)
+		smtpValidator.SetMXWalkUp(*mxWalkUp)
+		providerRates, err := validator.ParseSMTPProviderRates(*smtpProviderRates)
+		if err != nil {
+			log.Fatalf("Invalid configuration: %v", err)
+		}
+		if *smtpProbeRate > 0 || len(providerRates) > 0 {
+			smtpValidator.SetGovernor(validator.NewSMTPGovernor(*smtpProbeRate, providerRates, *smtpProbeRateWait))
+		}
+		emailService.SetMailboxVerifier(smtpValidator)
+		log.Printf("SMTP mailbox probing enabled (HELO %s)", helo)
+	}
+
+	// Learn typo corrections from the domains our users actually deliver mail to
+	if *typoLearning {
+		if *redisURL == "" {
+			log.Fatal("Invalid configuration: typo learning requires a Redis URL")
+		}
+		store, err := cache.NewRedisDomainFrequencyStore(*redisURL, "emailvalidator:typo:domains", 10000, redisTLS)
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis for typo learning: %v", err)
+		}
+		learner := validator.NewTypoLearner(store, *typoLearningHalfLife)
+		defer func() {
+			if err := learner.Sync(context.Background()); err != nil {
+				log.Printf("Error saving learned typo domains: %v", err)
+			}
+			if err := store.Close(); err != nil {
+				log.Printf("Error closing Redis connection for typo learning: %v", err)
+			}
+		}()
+		if err := learner.Sync(watchCtx); err != nil {
+			log.Printf("Warning: Could not load learned typo domains: %v", err)
+		}
+		learner.Start(watchCtx, validator.DefaultTypoSyncInterval)
+		emailValidator.SetTypoLearner(learner)
+		emailService.SetDomainLearner(learner)
+		log.Printf("Typo learning enabled (half-life %s)", *typoLearningHalfLife)
+	}
 
-	// Register existing handlers
-	mux.Handle("/api/validate", monitoring.MetricsMiddleware(http.HandlerFunc(api.HandleValidateEmail(emailService))))
-	mux.Handle("/api/validate/batch", monitoring.MetricsMiddleware(http.HandlerFunc(api.HandleBatchValidateEmails(batchValidationService))))
-	mux.Handle("/api/typo-suggestions", monitoring.MetricsMiddleware(http.HandlerFunc(api.HandleTypoSuggestions(emailService))))
-	mux.Handle("/api/status", monitoring.MetricsMiddleware(http.HandlerFunc(api.HandleStatus())))
+	// Publish validation events without ever blocking requests on the stream
+	if *eventsStream != "" {
+		if *redisURL == "" {
+			log.Fatal("Invalid configuration: events stream requires a Redis URL")
+		}
+		publisher, err := events.NewRedisStreamPublisher(*redisURL, *eventsStream, 100000, redisTLS)
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis for validation events: %v", err)
+		}
+		sink := events.NewAsyncSink(publisher, *eventsBuffer)
+		defer func() {
+			if err := sink.Close(); err != nil {
+				log.Printf("Error flushing validation events: %v", err)
+			}
+			if dropped := sink.Dropped(); dropped > 0 {
+				log.Printf("Dropped %d validation events because the buffer was full.", dropped)
+			}
+			if err := publisher.Close(); err != nil {
+				log.Printf("Error closing Redis connection for validation events: %v", err)
+			}
+		}()
+		emailService.SetEventSink(sink)
+		log.Printf("Publishing validation events to Redis stream %s", *eventsStream)
+	}
 
-	// NEW: Create and register the new disposable check handler
+	// 6. Setup HTTP server
+	handler := api.NewHandler(emailService)
+	// source_url lists are fetched from addresses clients choose, so they get their own
+	// client that cannot reach loopback, private or link-local addresses
+	sourceClient, err := validator.NewHTTPClient(validator.HTTPClientConfig{
+		Timeout:             *httpTimeout,
+		CABundle:            *httpCABundle,
+		UserAgent:           *httpUserAgent,
+		PublicAddressesOnly: true,
+	})
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	handler.SetHTTPClient(sourceClient)
+	handler.SetSourceURLHosts(strings.Split(*batchSourceHosts, ","))
+	handler.SetMaxSourceBytes(int64(*batchSourceMaxBytes))
+
+	apiMux := http.NewServeMux()
+	handler.RegisterRoutes(apiMux)
 	disposableCheckHandler := api.NewDisposableCheckHandler(emailService, disposableBlocklist)
-	mux.Handle("/api/check-disposable", monitoring.MetricsMiddleware(disposableCheckHandler))
+	apiMux.Handle("/check-disposable", disposableCheckHandler)
+	apiMux.HandleFunc("/disposable-check/batch", disposableCheckHandler.ServeBatch)
+	apiMux.HandleFunc("/disposable-source", disposableCheckHandler.ServeSource)
+	if *explainToken != "" {
+		apiMux.Handle("/validate/explain", api.NewExplainHandler(emailService, *explainToken))
+		log.Println("Explain endpoint enabled on /api/validate/explain")
+	}
+	if *adminToken != "" {
+		apiMux.Handle("/admin/refresh-blocklist", api.NewBlocklistRefreshHandler(disposableBlocklist, *adminToken))
+		apiMux.Handle("/admin/blocklist/export", api.NewBlocklistExportHandler(emailValidator, disposableBlocklist, *adminToken))
+		log.Println("Admin endpoints enabled on /api/admin")
+	}
+
+	mux := http.NewServeMux()
+	apiHandler := http.StripPrefix("/api", monitoring.MetricsMiddleware(apiMux))
+	apiHandler = api.TimeoutMiddleware(endpointTimeouts, *requestTimeout, apiHandler)
+	if *accessLog {
+		proxies, err := api.ParseTrustedProxies(strings.Split(*trustedProxies, ","))
+		if err != nil {
+			log.Fatalf("Invalid configuration: %v", err)
+		}
+		apiHandler = api.AccessLogMiddlewareWithProxies(slog.New(slog.NewJSONHandler(os.Stdout, nil)), proxies, apiHandler)
+		log.Println("Access log enabled")
+	}
+	mux.Handle("/api/", apiHandler)
 
 	// Serve static files
 	mux.Handle("/", http.FileServer(http.Dir("./static")))
 
 	// Prometheus metrics endpoint
 	if *prometheusEnabled {
-		mux.Handle("/metrics", promhttp.Handler())
+		mux.Handle("/metrics", monitoring.PrometheusHandler())
 		log.Println("Prometheus metrics enabled on /metrics")
 	}
 
 	server := &http.Server{
-		Addr:    fmt.Sprintf(":%s", *port),
-		Handler: mux,
+		Addr:              fmt.Sprintf(":%s", *port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
 	}
 
 	// 7. Start server in a goroutine
@@ -121,4 +697,53 @@ func main() {
 		log.Fatalf("Server shutdown failed: %v", err)
 	}
 	log.Println("Server gracefully stopped.")
-}
\ No newline at end of file
+}
+
+// listReader returns the reader for a role or free provider list: the remote sources with
+// the file at path as their fallback, or the file alone when there are no sources
+func listReader(client *http.Client, sources []validator.BlocklistSource, path string) validator.DomainReader {
+	if len(sources) == 0 {
+		return validator.NewFileDomainReader(path)
+	}
+	return validator.NewRemoteListReader(client, path, sources...)
+}
+
+// envOrDefault returns the value of the environment variable, or def if it is unset or empty
+func envOrDefault(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// envDurationOrDefault returns the duration value of the environment variable, or def if it is unset or invalid
+func envDurationOrDefault(key string, def time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return def
+}
+
+// envIntOrDefault returns the integer value of the environment variable, or def if it is unset or invalid
+func envIntOrDefault(key string, def int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return def
+}
+
+// envBoolOrDefault returns the boolean value of the environment variable, or def if it is unset or invalid
+func envBoolOrDefault(key string, def bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return def
+}
+
+// envFloatOrDefault returns the floating-point value of the environment variable, or def if it is unset or invalid
+func envFloatOrDefault(key string, def float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return def
+}
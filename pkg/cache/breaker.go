@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"emailvalidator/pkg/monitoring"
+)
+
+// Defaults for NewCircuitBreakerCache
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned instead of calling a cache whose circuit breaker is open
+var ErrCircuitOpen = errors.New("cache unavailable: circuit breaker open")
+
+// CircuitBreakerCache wraps a cache backend so an outage mid-run degrades to cache misses
+// instead of failing or slowing down every request. After threshold consecutive errors the
+// circuit opens and every call fails fast with ErrCircuitOpen. Once cooldown has passed, a
+// single call is let through to test the connection: if it succeeds the circuit closes,
+// otherwise it stays open for another cooldown. Misses (ErrMiss) do not count as errors.
+// Calls cancelled by their caller are neutral: they neither count as errors nor close the
+// circuit, and a cancelled probe lets the next call probe again.
+type CircuitBreakerCache struct {
+	cache     Cache
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreakerCache wraps c with a circuit breaker that opens after threshold
+// consecutive errors and tries c again after cooldown
+func NewCircuitBreakerCache(c Cache, threshold int, cooldown time.Duration) *CircuitBreakerCache {
+	return &CircuitBreakerCache{cache: c, threshold: max(threshold, 1), cooldown: cooldown}
+}
+
+// Get implements Cache
+func (c *CircuitBreakerCache) Get(ctx context.Context, key string, dest interface{}) error {
+	return c.call("get", func() error { return c.cache.Get(ctx, key, dest) })
+}
+
+// Set implements Cache
+func (c *CircuitBreakerCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return c.call("set", func() error { return c.cache.Set(ctx, key, value, expiration) })
+}
+
+// Delete implements Cache
+func (c *CircuitBreakerCache) Delete(ctx context.Context, key string) error {
+	return c.call("delete", func() error { return c.cache.Delete(ctx, key) })
+}
+
+// Close closes the wrapped cache
+func (c *CircuitBreakerCache) Close() error {
+	return c.cache.Close()
+}
+
+// Open reports whether the circuit is open, i.e. calls are failing fast
+func (c *CircuitBreakerCache) Open() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.open
+}
+
+// call runs op against the wrapped cache unless the circuit is open, and records the outcome
+func (c *CircuitBreakerCache) call(operation string, op func() error) error {
+	if !c.allow() {
+		return ErrCircuitOpen
+	}
+	err := op()
+	c.record(operation, err)
+	return err
+}
+
+// allow reports whether a call may go through: always while the circuit is closed, and
+// once per cooldown while it is open
+func (c *CircuitBreakerCache) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.open {
+		return true
+	}
+	if c.probing || time.Since(c.openedAt) < c.cooldown {
+		return false
+	}
+	c.probing = true
+	return true
+}
+
+// record updates the breaker with the result of a call
+func (c *CircuitBreakerCache) record(operation string, err error) {
+	cancelled := errors.Is(err, context.Canceled)
+	failed := err != nil && !errors.Is(err, ErrMiss) && !cancelled
+	if failed {
+		monitoring.RecordRedisError(operation)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wasProbing := c.probing
+	c.probing = false
+
+	// A cancelled call says nothing about the backend
+	if cancelled {
+		return
+	}
+
+	if !failed {
+		if c.open {
+			log.Printf("The cache backend is reachable again; resuming caching.")
+			monitoring.SetRedisCircuitOpen(false)
+		}
+		c.open = false
+		c.failures = 0
+		return
+	}
+
+	c.failures++
+	switch {
+	case wasProbing:
+		c.openedAt = time.Now()
+	case !c.open && c.failures >= c.threshold:
+		log.Printf("Warning: %d consecutive cache errors (last: %v); bypassing the cache for %s", c.failures, err, c.cooldown)
+		c.open = true
+		c.openedAt = time.Now()
+		monitoring.SetRedisCircuitOpen(true)
+	}
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Cache defines the interface for caching operations. Values are stored as JSON, so any
+// backend can hold any value. Get returns ErrMiss for a key that is not cached or has
+// expired, whatever the backend.
+type Cache interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Close() error
+}
+
+// ErrMiss is returned by Get for a key that is not in the cache
+var ErrMiss = errors.New("cache: miss")
+
+// Cache backends that can be selected by name
+const (
+	BackendRedis     = "redis"
+	BackendMemcached = "memcached"
+	BackendMemory    = "memory"
+)
+
+// New connects to the named backend at address: a Redis URL for BackendRedis, a host:port
+// for BackendMemcached, and nothing for BackendMemory, which keeps entries in this process.
+// redisOpts only apply to BackendRedis.
+func New(backend, address string, redisOpts ...RedisOption) (Cache, error) {
+	switch backend {
+	case BackendRedis:
+		c, err := NewRedisCache(address, redisOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case BackendMemcached:
+		c, err := NewMemcachedCache(address)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case BackendMemory:
+		return NewMemoryCache(), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q: must be %q, %q or %q", backend, BackendRedis, BackendMemcached, BackendMemory)
+	}
+}
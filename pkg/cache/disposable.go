@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"emailvalidator/pkg/monitoring"
+)
+
+// DefaultDisposableTTL is how long a domain's disposable determination is cached. Disposable
+// lists change rarely, so this is much longer than DNS-based domain results are kept.
+const DefaultDisposableTTL = 24 * time.Hour
+
+// disposableCacheType labels the disposable cache in cache hit and miss metrics
+const disposableCacheType = "disposable"
+
+// DisposableCache caches whether domains are disposable, with a TTL of its own. Entries are
+// namespaced by the version of the disposable lists they were determined against, set with
+// SetVersion, so determinations made against another list are never read and simply expire.
+type DisposableCache struct {
+	cache   Cache
+	ttl     time.Duration
+	version atomic.Pointer[string]
+}
+
+// NewDisposableCache creates a DisposableCache that keeps determinations in c for ttl
+func NewDisposableCache(c Cache, ttl time.Duration) *DisposableCache {
+	return &DisposableCache{cache: c, ttl: ttl}
+}
+
+// Get returns the cached determination for domain, if there is one
+func (c *DisposableCache) Get(ctx context.Context, domain string) (disposable, ok bool) {
+	if err := c.cache.Get(ctx, c.key(domain), &disposable); err != nil {
+		if !errors.Is(err, ErrMiss) && !errors.Is(err, ErrCircuitOpen) {
+			log.Printf("Warning: Could not read cached disposable result: %v", err)
+		}
+		monitoring.RecordCacheMiss(disposableCacheType)
+		return false, false
+	}
+	monitoring.RecordCacheHit(disposableCacheType)
+	return disposable, true
+}
+
+// Set caches whether domain is disposable
+func (c *DisposableCache) Set(ctx context.Context, domain string, disposable bool) {
+	if err := c.cache.Set(ctx, c.key(domain), disposable, c.ttl); err != nil && !errors.Is(err, ErrCircuitOpen) {
+		log.Printf("Warning: Could not cache disposable result: %v", err)
+	}
+}
+
+// SetVersion sets the version of the disposable lists that determinations are cached
+// for, e.g. a hash of the list files. Call it whenever the lists load. The version must
+// be derived from the lists themselves rather than counted by the process: the cache
+// outlives restarts and is shared by instances, which must agree on which determinations
+// are current.
+func (c *DisposableCache) SetVersion(version string) {
+	c.version.Store(&version)
+}
+
+// key returns the cache key of domain in the current version. "Example.com." and
+// "example.com" share a key.
+func (c *DisposableCache) key(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	version := ""
+	if v := c.version.Load(); v != nil {
+		version = *v
+	}
+	return "disposable:" + version + ":" + domain
+}
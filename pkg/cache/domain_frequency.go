@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisDomainFrequencyStore keeps domain observation weights in a Redis sorted set, so
+// every instance learns from and suggests the same domains. Only the maxEntries heaviest
+// domains are kept.
+type RedisDomainFrequencyStore struct {
+	client     *redis.Client
+	key        string
+	maxEntries int64
+}
+
+// NewRedisDomainFrequencyStore connects to Redis and returns a store using the sorted set at key
+func NewRedisDomainFrequencyStore(redisURL, key string, maxEntries int64, opts ...RedisOption) (*RedisDomainFrequencyStore, error) {
+	opt, err := ParseRedisURL(redisURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(opt)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &RedisDomainFrequencyStore{
+		client:     client,
+		key:        key,
+		maxEntries: maxEntries,
+	}, nil
+}
+
+// IncrementDomains adds the weights to their domains and trims the set to the heaviest entries
+func (s *RedisDomainFrequencyStore) IncrementDomains(ctx context.Context, increments map[string]float64) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for domain, weight := range increments {
+			pipe.ZIncrBy(ctx, s.key, weight, domain)
+		}
+		pipe.ZRemRangeByRank(ctx, s.key, 0, -s.maxEntries-1)
+		return nil
+	})
+	return err
+}
+
+// TopDomains returns the n heaviest domains and their weights
+func (s *RedisDomainFrequencyStore) TopDomains(ctx context.Context, n int) (map[string]float64, error) {
+	entries, err := s.client.ZRevRangeWithScores(ctx, s.key, 0, int64(n)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	top := make(map[string]float64, len(entries))
+	for _, entry := range entries {
+		if domain, ok := entry.Member.(string); ok {
+			top[domain] = entry.Score
+		}
+	}
+	return top, nil
+}
+
+// Close closes the Redis connection
+func (s *RedisDomainFrequencyStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,255 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMemcachedTimeout is the time allowed for a Memcached command whose context has no
+// earlier deadline
+const DefaultMemcachedTimeout = time.Second
+
+// maxIdleMemcachedConns is how many idle connections a MemcachedCache keeps for reuse
+const maxIdleMemcachedConns = 8
+
+// Memcached protocol limits
+const (
+	maxMemcachedKeyLength = 250
+	// Expirations longer than this are read by the server as a Unix time instead of seconds
+	maxMemcachedRelativeExpiry = 30 * 24 * time.Hour
+)
+
+// MemcachedCache implements the Cache interface with a Memcached server, speaking the text
+// protocol directly. Connections are reused; one that fails mid-command is discarded.
+type MemcachedCache struct {
+	addr    string
+	timeout time.Duration
+	idle    chan *memcachedConn
+}
+
+// memcachedConn is a connection to the server with buffered reads and writes
+type memcachedConn struct {
+	net.Conn
+	rw *bufio.ReadWriter
+}
+
+// memcachedServerError is an ERROR, CLIENT_ERROR or SERVER_ERROR reply. The connection is
+// still in sync after one, so it can be reused.
+type memcachedServerError struct {
+	reply string
+}
+
+func (e *memcachedServerError) Error() string {
+	return "memcached: " + e.reply
+}
+
+// NewMemcachedCache connects to the Memcached server at addr (host:port) and checks that it answers
+func NewMemcachedCache(addr string) (*MemcachedCache, error) {
+	c := &MemcachedCache{
+		addr:    addr,
+		timeout: DefaultMemcachedTimeout,
+		idle:    make(chan *memcachedConn, maxIdleMemcachedConns),
+	}
+	err := c.do(context.Background(), func(conn *memcachedConn) error {
+		reply, err := conn.command("version\r\n")
+		if err == nil && !strings.HasPrefix(reply, "VERSION ") {
+			err = fmt.Errorf("memcached: unexpected reply %q to version", reply)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Memcached at %s: %w", addr, err)
+	}
+	return c, nil
+}
+
+// SetTimeout sets the time allowed for a command whose context has no earlier deadline
+func (c *MemcachedCache) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// Get implements Cache
+func (c *MemcachedCache) Get(ctx context.Context, key string, dest interface{}) error {
+	var data []byte
+	err := c.do(ctx, func(conn *memcachedConn) error {
+		reply, err := conn.command("get " + memcachedKey(key) + "\r\n")
+		if err != nil || reply == "END" {
+			return err
+		}
+
+		// VALUE <key> <flags> <bytes>
+		fields := strings.Fields(reply)
+		if len(fields) != 4 || fields[0] != "VALUE" {
+			return fmt.Errorf("memcached: unexpected reply %q to get", reply)
+		}
+		size, err := strconv.Atoi(fields[3])
+		if err != nil || size < 0 {
+			return fmt.Errorf("memcached: invalid value length in %q", reply)
+		}
+		data = make([]byte, size+2)
+		if _, err := io.ReadFull(conn.rw, data); err != nil {
+			return err
+		}
+		data = data[:size]
+		if end, err := conn.readLine(); err != nil || end != "END" {
+			return errors.Join(fmt.Errorf("memcached: unexpected end of get reply %q", end), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return ErrMiss
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// Set implements Cache. A zero expiration keeps the entry until the server evicts it.
+func (c *MemcachedCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, func(conn *memcachedConn) error {
+		reply, err := conn.command(fmt.Sprintf("set %s 0 %d %d\r\n%s\r\n", memcachedKey(key), memcachedExpiry(expiration), len(data), data))
+		if err == nil && reply != "STORED" {
+			err = fmt.Errorf("memcached: unexpected reply %q to set", reply)
+		}
+		return err
+	})
+}
+
+// Delete implements Cache
+func (c *MemcachedCache) Delete(ctx context.Context, key string) error {
+	return c.do(ctx, func(conn *memcachedConn) error {
+		reply, err := conn.command("delete " + memcachedKey(key) + "\r\n")
+		if err == nil && reply != "DELETED" && reply != "NOT_FOUND" {
+			err = fmt.Errorf("memcached: unexpected reply %q to delete", reply)
+		}
+		return err
+	})
+}
+
+// Close closes the idle connections
+func (c *MemcachedCache) Close() error {
+	for {
+		select {
+		case conn := <-c.idle:
+			conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs fn on a connection, bounded by ctx's deadline or the command timeout. The
+// connection is returned for reuse unless fn failed with a network or protocol error,
+// which may have left unread data behind.
+func (c *MemcachedCache) do(ctx context.Context, fn func(*memcachedConn) error) error {
+	conn, err := c.conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	err = fn(conn)
+	var serverErr *memcachedServerError
+	if err != nil && !errors.As(err, &serverErr) {
+		conn.Close()
+		return err
+	}
+	select {
+	case c.idle <- conn:
+	default:
+		conn.Close()
+	}
+	return err
+}
+
+// conn returns an idle connection, or dials a new one
+func (c *MemcachedCache) conn(ctx context.Context) (*memcachedConn, error) {
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+	}
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	return &memcachedConn{Conn: conn, rw: bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))}, nil
+}
+
+// command sends request and returns the first line of the reply
+func (conn *memcachedConn) command(request string) (string, error) {
+	if _, err := conn.rw.WriteString(request); err != nil {
+		return "", err
+	}
+	if err := conn.rw.Flush(); err != nil {
+		return "", err
+	}
+	reply, err := conn.readLine()
+	if err != nil {
+		return "", err
+	}
+	if reply == "ERROR" || strings.HasPrefix(reply, "CLIENT_ERROR") || strings.HasPrefix(reply, "SERVER_ERROR") {
+		return "", &memcachedServerError{reply: reply}
+	}
+	return reply, nil
+}
+
+// readLine reads one reply line without its CRLF
+func (conn *memcachedConn) readLine() (string, error) {
+	line, err := conn.rw.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+// memcachedKey returns key if Memcached accepts it as is, or a digest of it for a key
+// that is too long or contains spaces or control characters, as an address may
+func memcachedKey(key string) string {
+	valid := len(key) <= maxMemcachedKeyLength
+	for i := 0; valid && i < len(key); i++ {
+		valid = key[i] > ' ' && key[i] != 0x7f
+	}
+	if valid {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// memcachedExpiry returns the exptime field for expiration: whole seconds, rounded up, or
+// a Unix time for expirations longer than the server reads as relative
+func memcachedExpiry(expiration time.Duration) int64 {
+	switch {
+	case expiration <= 0:
+		return 0
+	case expiration > maxMemcachedRelativeExpiry:
+		return time.Now().Add(expiration).Unix()
+	default:
+		return int64((expiration + time.Second - 1) / time.Second)
+	}
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is the least time between sweeps of expired entries from a MemoryCache
+const memorySweepInterval = time.Minute
+
+// memoryEntry is a cached value and when it expires; a zero expiry never expires
+type memoryEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// MemoryCache implements the Cache interface in process memory. Entries are not shared
+// between instances and are lost on restart, so it suits single-instance deployments and
+// tests. Expired entries are swept out as new ones are set.
+type MemoryCache struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryEntry), lastSweep: time.Now()}
+}
+
+// MockCache is the in-memory cache under its former name.
+//
+// Deprecated: use MemoryCache.
+type MockCache = MemoryCache
+
+// NewMockCache creates an empty in-memory cache.
+//
+// Deprecated: use NewMemoryCache.
+func NewMockCache() *MockCache {
+	return NewMemoryCache()
+}
+
+// Get implements Cache
+func (m *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	m.mu.Lock()
+	entry, exists := m.entries[key]
+	if exists && entry.expired(time.Now()) {
+		delete(m.entries, key)
+		exists = false
+	}
+	m.mu.Unlock()
+
+	if !exists {
+		return ErrMiss
+	}
+	return json.Unmarshal(entry.data, dest)
+}
+
+// Set implements Cache. A zero expiration keeps the entry until it is deleted.
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entry := memoryEntry{data: data}
+	if expiration > 0 {
+		entry.expires = now.Add(expiration)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = entry
+	if now.Sub(m.lastSweep) >= memorySweepInterval {
+		m.sweep(now)
+	}
+	return nil
+}
+
+// Delete implements Cache
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+// Close discards every entry
+func (m *MemoryCache) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]memoryEntry)
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not swept out yet
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// sweep deletes the entries expired at now. The caller must hold m.mu.
+func (m *MemoryCache) sweep(now time.Time) {
+	for key, entry := range m.entries {
+		if entry.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+	m.lastSweep = now
+}
+
+// expired reports whether the entry has expired at now
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
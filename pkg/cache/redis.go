@@ -3,98 +3,22 @@ package cache
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"sync"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// Cache defines the interface for caching operations
-type Cache interface {
-	Get(ctx context.Context, key string, dest interface{}) error
-	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
-	Delete(ctx context.Context, key string) error
-	Close() error
-}
-
 // RedisCache implements the Cache interface using Redis
 type RedisCache struct {
 	client *redis.Client
 }
 
-// MockCache implements the Cache interface for testing
-type MockCache struct {
-	mu   sync.RWMutex
-	data map[string][]byte
-	ttls map[string]time.Time
-}
-
-// NewMockCache creates a new mock cache for testing
-func NewMockCache() *MockCache {
-	return &MockCache{
-		data: make(map[string][]byte),
-		ttls: make(map[string]time.Time),
-	}
-}
-
-func (m *MockCache) Get(ctx context.Context, key string, dest interface{}) error {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	data, exists := m.data[key]
-	if !exists {
-		return redis.Nil
-	}
-
-	// Check if key has expired
-	if expiry, ok := m.ttls[key]; ok && time.Now().After(expiry) {
-		delete(m.data, key)
-		delete(m.ttls, key)
-		return redis.Nil
-	}
-
-	return json.Unmarshal(data, dest)
-}
-
-func (m *MockCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	data, err := json.Marshal(value)
-	if err != nil {
-		return err
-	}
-
-	m.data[key] = data
-	if expiration > 0 {
-		m.ttls[key] = time.Now().Add(expiration)
-	}
-	return nil
-}
-
-func (m *MockCache) Delete(ctx context.Context, key string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	delete(m.data, key)
-	delete(m.ttls, key)
-	return nil
-}
-
-func (m *MockCache) Close() error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	m.data = make(map[string][]byte)
-	m.ttls = make(map[string]time.Time)
-	return nil
-}
-
-func NewRedisCache(redisURL string) (*RedisCache, error) {
-	opt, err := redis.ParseURL(redisURL)
+// NewRedisCache connects to the Redis server at redisURL
+func NewRedisCache(redisURL string, opts ...RedisOption) (*RedisCache, error) {
+	opt, err := ParseRedisURL(redisURL, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Redis URL: %v", err)
+		return nil, err
 	}
 
 	client := redis.NewClient(opt)
@@ -111,6 +35,7 @@ func NewRedisCache(redisURL string) (*RedisCache, error) {
 	}, nil
 }
 
+// Set implements Cache
 func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	data, err := json.Marshal(value)
 	if err != nil {
@@ -119,18 +44,24 @@ func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, exp
 	return c.client.Set(ctx, key, data, expiration).Err()
 }
 
+// Get implements Cache
 func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
 	data, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return ErrMiss
+	}
 	if err != nil {
 		return err
 	}
 	return json.Unmarshal(data, dest)
 }
 
+// Delete implements Cache
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, key).Err()
 }
 
+// Close closes the connection to Redis
 func (c *RedisCache) Close() error {
 	return c.client.Close()
 }
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisTLSOptions are the TLS settings for Redis connections that a rediss:// URL cannot
+// express, such as a private certificate authority or a client certificate
+type RedisTLSOptions struct {
+	// CAFile is a PEM file of certificate authorities trusted in addition to the system roots
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and its key, for servers that
+	// require clients to authenticate. Both or neither must be set.
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify accepts any server certificate. Only for testing.
+	InsecureSkipVerify bool
+}
+
+// IsZero reports whether o sets nothing
+func (o RedisTLSOptions) IsZero() bool {
+	return o == RedisTLSOptions{}
+}
+
+// TLSConfig loads the files named by o into a tls.Config, or returns nil when o sets nothing
+func (o RedisTLSOptions) TLSConfig() (*tls.Config, error) {
+	if o.IsZero() {
+		return nil, nil
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, fmt.Errorf("invalid Redis TLS configuration: a client certificate and its key must be set together")
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(filepath.Clean(o.CAFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Redis CA file: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates in Redis CA file %s", o.CAFile)
+		}
+		config.RootCAs = roots
+	}
+	if o.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(filepath.Clean(o.CertFile), filepath.Clean(o.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Redis client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// RedisOption configures a Redis connection beyond its URL
+type RedisOption func(*redis.Options)
+
+// WithRedisTLS connects over TLS with config, even for a redis:// URL. The server name
+// checked against the server's certificate is the URL's host unless config sets one.
+// A nil config leaves the connection as its URL configures it.
+func WithRedisTLS(config *tls.Config) RedisOption {
+	return func(opt *redis.Options) {
+		if config == nil {
+			return
+		}
+		tlsConfig := config.Clone()
+		if tlsConfig.ServerName == "" {
+			if opt.TLSConfig != nil && opt.TLSConfig.ServerName != "" {
+				tlsConfig.ServerName = opt.TLSConfig.ServerName
+			} else if host, _, err := net.SplitHostPort(opt.Addr); err == nil {
+				tlsConfig.ServerName = host
+			}
+		}
+		opt.TLSConfig = tlsConfig
+	}
+}
+
+// ParseRedisURL parses redisURL into client options and applies opts
+func ParseRedisURL(redisURL string, opts ...RedisOption) (*redis.Options, error) {
+	opt, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %v", err)
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+	return opt, nil
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"emailvalidator/pkg/webhook"
+)
+
+// maxCallbackBodyBytes limits how much of a callback request VerifyCallback reads
+const maxCallbackBodyBytes = 64 << 20
+
+// VerifyCallback checks that a callback request received from the server is signed with
+// secret, following the scheme described in package webhook, and was signed within
+// webhook.DefaultTolerance. It returns the verified body, and also restores r.Body so the
+// handler can decode it as usual.
+func VerifyCallback(r *http.Request, secret []byte) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read callback body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), body, time.Now(), webhook.DefaultTolerance); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
@@ -0,0 +1,243 @@
+// Package client is a Go client for the email validator HTTP API
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"emailvalidator/internal/model"
+)
+
+// DefaultTimeout is the time allowed for a request when the client was not given an http.Client
+const DefaultTimeout = 30 * time.Second
+
+// maxErrorBodyBytes limits how much of an error response is read into an APIError
+const maxErrorBodyBytes = 4 << 10
+
+// Response types of the API
+type (
+	// ValidationResult is the result of validating one address
+	ValidationResult = model.EmailValidationResponse
+	// BatchResult is the result of validating a list of addresses, in request order
+	BatchResult = model.BatchValidationResponse
+	// Suggestion is the did-you-mean correction for an address
+	Suggestion = model.TypoSuggestionResponse
+	// Status is the overall outcome of a validation
+	Status = model.ValidationStatus
+	// ReasonCode is the primary cause of a status other than StatusValid
+	ReasonCode = model.ReasonCode
+)
+
+// Validation statuses
+const (
+	StatusValid         = model.ValidationStatusValid
+	StatusProbablyValid = model.ValidationStatusProbablyValid
+	StatusInvalid       = model.ValidationStatusInvalid
+	StatusMissingEmail  = model.ValidationStatusMissingEmail
+	StatusInvalidFormat = model.ValidationStatusInvalidFormat
+	StatusInvalidDomain = model.ValidationStatusInvalidDomain
+	StatusUnknownTLD    = model.ValidationStatusUnknownTLD
+	StatusNoMXRecords   = model.ValidationStatusNoMXRecords
+	StatusDisposable    = model.ValidationStatusDisposable
+	StatusError         = model.ValidationStatusError
+	StatusNotProcessed  = model.ValidationStatusNotProcessed
+)
+
+// Options are per-request overrides of the server's configuration. The zero value uses
+// the server's defaults.
+type Options struct {
+	// DisposablePolicy is "reject", "flag" or "score"
+	DisposablePolicy string
+	// SyntaxMode is "lenient", "rfc5322" or "rfc5321"
+	SyntaxMode string
+	// MinConfidence, when set, is the minimum confidence from 0 to 1 of typo suggestions
+	MinConfidence *float64
+	// Fields limits the fields of each result to the given dotted paths, e.g. "validations.syntax"
+	Fields []string
+}
+
+// query encodes the options as query parameters. The score is always requested on the
+// percent scale, which the result types hold, whatever the server's default scale.
+func (o *Options) query() url.Values {
+	query := url.Values{"scale": {"percent"}}
+	if o == nil {
+		return query
+	}
+	if o.DisposablePolicy != "" {
+		query.Set("disposable_policy", o.DisposablePolicy)
+	}
+	if o.SyntaxMode != "" {
+		query.Set("syntax_mode", o.SyntaxMode)
+	}
+	if o.MinConfidence != nil {
+		query.Set("min_confidence", strconv.FormatFloat(*o.MinConfidence, 'g', -1, 64))
+	}
+	if len(o.Fields) > 0 {
+		query.Set("fields", strings.Join(o.Fields, ","))
+	}
+	return query
+}
+
+// APIError is returned for a response with a status other than 200 OK
+type APIError struct {
+	StatusCode int
+	// Message is the server's explanation, if it gave one
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("email validator API: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("email validator API: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Client calls the email validator HTTP API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retry      RetryPolicy
+}
+
+// New creates a Client for the server at baseURL, e.g. "http://localhost:8080". Requests
+// go to the API under baseURL's "/api" path.
+func New(baseURL string) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+	return &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+	}, nil
+}
+
+// SetHTTPClient sets the client used for requests, e.g. to configure a proxy or transport
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetRetryPolicy sets how transient failures are retried. Retries are disabled by default.
+// It must not be called while requests are in flight.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// Validate validates one address. opts may be nil.
+func (c *Client) Validate(ctx context.Context, email string, opts *Options) (*ValidationResult, error) {
+	var result ValidationResult
+	if err := c.post(ctx, "validate", opts, model.EmailValidationRequest{Email: email}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ValidateBatch validates a list of addresses. The results are in the same order as
+// emails. opts may be nil.
+func (c *Client) ValidateBatch(ctx context.Context, emails []string, opts *Options) (*BatchResult, error) {
+	var result BatchResult
+	if err := c.post(ctx, "validate/batch", opts, model.BatchValidationRequest{Emails: emails}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CheckDisposable validates one address and additionally checks its domain against the
+// server's remote disposable blocklist. opts may be nil.
+func (c *Client) CheckDisposable(ctx context.Context, email string, opts *Options) (*ValidationResult, error) {
+	var result ValidationResult
+	if err := c.post(ctx, "check-disposable", opts, model.EmailValidationRequest{Email: email}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Suggest returns the did-you-mean correction for an address. The result has no
+// TypoSuggestion if the domain looks right. opts may be nil; only MinConfidence applies.
+func (c *Client) Suggest(ctx context.Context, email string, opts *Options) (*Suggestion, error) {
+	var result Suggestion
+	if err := c.post(ctx, "typo-suggestions", opts, model.TypoSuggestionRequest{Email: email}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// post sends body as JSON to the API endpoint at path and decodes the response into
+// result, retrying transient failures under the retry policy. Every endpoint the client
+// calls only reads and computes, so repeating a POST is as safe as repeating a GET.
+func (c *Client) post(ctx context.Context, path string, opts *Options, body, result any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	endpoint := c.baseURL.JoinPath("api", path)
+	endpoint.RawQuery = opts.query().Encode()
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := c.do(ctx, endpoint, payload, result)
+		if err == nil || attempt >= c.retry.MaxAttempts || !isTransient(ctx, err) {
+			return err
+		}
+		if err := sleep(ctx, c.retry.delay(attempt, retryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+// do makes one attempt at a request. For an error response it also returns how long the
+// server asked the client to wait with Retry-After, or noRetryAfter if it did not.
+func (c *Client) do(ctx context.Context, endpoint *url.URL, payload []byte, result any) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(payload))
+	if err != nil {
+		return noRetryAfter, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return noRetryAfter, fmt.Errorf("request to %s failed: %w", endpoint.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), newAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return noRetryAfter, fmt.Errorf("failed to decode response from %s: %w", endpoint.Path, err)
+	}
+	return noRetryAfter, nil
+}
+
+// newAPIError reads the explanation from an error response. The API answers with
+// {"error": "..."} on most endpoints and with plain text on some.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if err != nil {
+		return apiErr
+	}
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		apiErr.Message = payload.Error
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	return apiErr
+}
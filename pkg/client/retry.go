@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Defaults for RetryPolicy
+const (
+	// DefaultInitialBackoff is the longest wait before the first retry
+	DefaultInitialBackoff = 200 * time.Millisecond
+	// DefaultMaxBackoff caps the exponential growth of the wait between retries
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// noRetryAfter stands for a response without a Retry-After header. An explicit zero asks
+// for an immediate retry, so it cannot double as "none".
+const noRetryAfter time.Duration = -1
+
+// RetryPolicy configures retries of transient failures: network errors, 429 Too Many
+// Requests and 5xx responses other than 501 Not Implemented. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts made for a request, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the longest wait before the first retry; zero uses DefaultInitialBackoff.
+	// The longest wait doubles with every retry, and the actual wait is chosen at random
+	// below it (full jitter), so clients that failed together do not retry together.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the longest wait between retries; zero uses DefaultMaxBackoff. It
+	// does not cap a wait the server asked for with Retry-After.
+	MaxBackoff time.Duration
+}
+
+// delay returns how long to wait after the given failed attempt. A Retry-After from the
+// server takes precedence over the backoff.
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter != noRetryAfter {
+		return retryAfter
+	}
+	initial, ceiling := p.InitialBackoff, p.MaxBackoff
+	if initial <= 0 {
+		initial = DefaultInitialBackoff
+	}
+	if ceiling <= 0 {
+		ceiling = DefaultMaxBackoff
+	}
+	backoff := initial
+	for i := 1; i < attempt && backoff < ceiling; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, ceiling)
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// isTransient reports whether a request that failed with err may succeed if repeated
+func isTransient(ctx context.Context, err error) bool {
+	// The caller gave up, so there is no point in trying again
+	if ctx.Err() != nil {
+		return false
+	}
+	// Network errors come from http.Client as *url.Error. A response that fails to decode
+	// is not retried, since the server would most likely answer the same way again.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests ||
+		apiErr.StatusCode >= 500 && apiErr.StatusCode != http.StatusNotImplemented
+}
+
+// parseRetryAfter returns the wait a Retry-After header asks for, given in seconds or as
+// an HTTP date, or noRetryAfter if there is no valid one
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return noRetryAfter
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return noRetryAfter
+}
+
+// sleep waits for d, returning early with the context's error if it is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Publisher delivers a single event to a message queue
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// AsyncSink buffers events and publishes them from a background goroutine.
+// Emit never blocks: when the buffer is full, or the sink is closed, the event is
+// dropped and counted.
+type AsyncSink struct {
+	publisher Publisher
+	timeout   time.Duration
+	events    chan Event
+	dropped   atomic.Uint64
+	failed    atomic.Uint64
+	done      chan struct{}
+
+	// mu guards closed against sends racing the close of events
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncSink creates an AsyncSink with room for bufferSize pending events and starts
+// its publishing goroutine. Call Close to drain the buffer and stop it.
+func NewAsyncSink(publisher Publisher, bufferSize int) *AsyncSink {
+	s := &AsyncSink{
+		publisher: publisher,
+		timeout:   5 * time.Second,
+		events:    make(chan Event, bufferSize),
+		done:      make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Emit queues an event for publishing, dropping it if the buffer is full or the sink
+// has been closed
+func (s *AsyncSink) Emit(event Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.dropped.Add(1)
+		return
+	}
+	select {
+	case s.events <- event:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events discarded because the buffer was full or the
+// sink was closed
+func (s *AsyncSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Failed returns the number of events the publisher could not deliver
+func (s *AsyncSink) Failed() uint64 {
+	return s.failed.Load()
+}
+
+// Close stops accepting events and waits for the buffered ones to be published.
+// Events emitted after Close are dropped.
+func (s *AsyncSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *AsyncSink) run() {
+	defer close(s.done)
+
+	// Only log transitions between failing and healthy to avoid flooding the log when the queue is down
+	failing := false
+	for event := range s.events {
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err := s.publisher.Publish(ctx, event)
+		cancel()
+
+		switch {
+		case err != nil:
+			s.failed.Add(1)
+			if !failing {
+				log.Printf("Error publishing validation event: %v", err)
+				failing = true
+			}
+		case failing:
+			log.Println("Publishing validation events recovered.")
+			failing = false
+		}
+	}
+}
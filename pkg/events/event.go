@@ -0,0 +1,43 @@
+// Package events publishes structured validation events for downstream analytics.
+// Events never carry the raw email address, only a SHA-256 hash of its normalized form.
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Event describes a finalized validation result
+type Event struct {
+	EmailHash string    `json:"email_hash"`
+	Domain    string    `json:"domain"`
+	Status    string    `json:"status"`
+	Score     int       `json:"score"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewEvent creates an event for a validation result, hashing the email address
+func NewEvent(email, status string, score int) Event {
+	normalized := strings.ToLower(strings.TrimSpace(email))
+
+	var domain string
+	if at := strings.LastIndex(normalized, "@"); at != -1 {
+		domain = normalized[at+1:]
+	}
+
+	return Event{
+		EmailHash: HashEmail(normalized),
+		Domain:    domain,
+		Status:    status,
+		Score:     score,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// HashEmail returns the hex-encoded SHA-256 hash of the lowercased, trimmed email address
+func HashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
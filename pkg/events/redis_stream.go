@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"emailvalidator/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStreamPublisher appends events to a Redis stream with XADD.
+// The stream is trimmed approximately to maxLen entries so it cannot grow without bound.
+type RedisStreamPublisher struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamPublisher connects to Redis and returns a publisher writing to stream
+func NewRedisStreamPublisher(redisURL, stream string, maxLen int64, opts ...cache.RedisOption) (*RedisStreamPublisher, error) {
+	opt, err := cache.ParseRedisURL(redisURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(opt)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &RedisStreamPublisher{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}, nil
+}
+
+// Publish implements Publisher
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event Event) error {
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"email_hash": event.EmailHash,
+			"domain":     event.Domain,
+			"status":     event.Status,
+			"score":      strconv.Itoa(event.Score),
+			"timestamp":  event.Timestamp.Format(time.RFC3339Nano),
+		},
+	}).Err()
+}
+
+// Close closes the Redis connection
+func (p *RedisStreamPublisher) Close() error {
+	return p.client.Close()
+}
@@ -0,0 +1,85 @@
+package monitoring
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Metric names emitted through the Backend
+const (
+	MetricRequestsTotal           = "email_validator_requests_total"
+	MetricRequestDuration         = "email_validator_request_duration_seconds"
+	MetricValidationScores        = "email_validator_scores"
+	MetricCacheOperations         = "email_validator_cache_operations_total"
+	MetricDNSLookupDuration       = "email_validator_dns_lookup_duration_seconds"
+	MetricActiveGoroutines        = "email_validator_active_goroutines"
+	MetricMemoryUsage             = "email_validator_memory_usage_bytes"
+	MetricCacheHits               = "cache_hits_total"
+	MetricCacheMisses             = "cache_misses_total"
+	MetricConcurrentBatchRequests = "email_validator_concurrent_batch_requests"
+	MetricBatchSize               = "email_validator_batch_size"
+	MetricBatchProcessingTime     = "email_validator_batch_processing_seconds"
+	MetricInFlightRequests        = "email_validator_in_flight_requests"
+	MetricBatchWorkers            = "email_validator_batch_workers"
+	MetricBatchWorkersActive      = "email_validator_batch_workers_active"
+	MetricBatchWorkerUtilization  = "email_validator_batch_worker_utilization"
+	MetricRedisErrors             = "email_validator_redis_errors_total"
+	MetricRedisCircuitOpen        = "email_validator_redis_circuit_open"
+	MetricSMTPCircuitOpen         = "email_validator_smtp_circuit_open_total"
+	MetricSMTPRateLimited         = "email_validator_smtp_rate_limited_total"
+	MetricDNSQueueDepth           = "email_validator_dns_queue_depth"
+	MetricTypoSuggestions         = "email_validator_typo_suggestions_total"
+	MetricTypoSuggestionsAccepted = "email_validator_typo_suggestions_accepted_total"
+)
+
+// Labels holds the label (tag) values attached to a metric sample
+type Labels map[string]string
+
+// Backend emits metrics to a monitoring system
+type Backend interface {
+	// IncCounter increments a monotonically increasing counter by one
+	IncCounter(name string, labels Labels)
+	// ObserveHistogram records a single observation in a distribution
+	ObserveHistogram(name string, value float64, labels Labels)
+	// SetGauge sets a gauge to the given value
+	SetGauge(name string, value float64, labels Labels)
+	// AddGauge adds delta (which may be negative) to a gauge
+	AddGauge(name string, delta float64, labels Labels)
+}
+
+// Supported backend names for configuration
+const (
+	BackendPrometheus = "prometheus"
+	BackendStatsD     = "statsd"
+	BackendOTLP       = "otlp"
+)
+
+type backendHolder struct {
+	backend Backend
+}
+
+var activeBackend atomic.Value
+
+func init() {
+	activeBackend.Store(backendHolder{backend: PrometheusBackend{}})
+}
+
+// SetBackend replaces the backend all metrics are emitted through. The default is Prometheus.
+func SetBackend(backend Backend) {
+	activeBackend.Store(backendHolder{backend: backend})
+}
+
+// CurrentBackend returns the backend metrics are currently emitted through
+func CurrentBackend() Backend {
+	return activeBackend.Load().(backendHolder).backend
+}
+
+// ValidateBackendName checks that name refers to a supported backend
+func ValidateBackendName(name string) error {
+	switch name {
+	case BackendPrometheus, BackendStatsD, BackendOTLP:
+		return nil
+	default:
+		return fmt.Errorf("unsupported metrics backend %q: must be %q, %q or %q", name, BackendPrometheus, BackendStatsD, BackendOTLP)
+	}
+}
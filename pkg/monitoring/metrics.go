@@ -1,8 +1,9 @@
 // Package monitoring provides metrics collection and monitoring functionality for the email validator service.
-// It includes Prometheus metrics for tracking request rates, latencies, and various operational metrics.
+// Metrics are emitted through a pluggable Backend; Prometheus is the default, with StatsD and OTLP available.
 package monitoring
 
 import (
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,7 +14,7 @@ var (
 	// RequestsTotal tracks the total number of requests
 	RequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "email_validator_requests_total",
+			Name: MetricRequestsTotal,
 			Help: "Total number of email validation requests",
 		},
 		[]string{"endpoint", "status"},
@@ -22,7 +23,7 @@ var (
 	// RequestDuration tracks request duration
 	RequestDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "email_validator_request_duration_seconds",
+			Name:    MetricRequestDuration,
 			Help:    "Request duration in seconds",
 			Buckets: prometheus.DefBuckets,
 		},
@@ -32,7 +33,7 @@ var (
 	// ValidationScores tracks the distribution of validation scores
 	ValidationScores = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "email_validator_scores",
+			Name:    MetricValidationScores,
 			Help:    "Distribution of email validation scores",
 			Buckets: []float64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
 		},
@@ -42,7 +43,7 @@ var (
 	// CacheOperations tracks cache hits and misses
 	CacheOperations = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "email_validator_cache_operations_total",
+			Name: MetricCacheOperations,
 			Help: "Total number of cache operations",
 		},
 		[]string{"operation", "result"},
@@ -51,7 +52,7 @@ var (
 	// DNSLookupDuration tracks DNS lookup times
 	DNSLookupDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "email_validator_dns_lookup_duration_seconds",
+			Name:    MetricDNSLookupDuration,
 			Help:    "DNS lookup duration in seconds",
 			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
 		},
@@ -61,7 +62,7 @@ var (
 	// ActiveGoroutines tracks the number of active goroutines
 	ActiveGoroutines = promauto.NewGauge(
 		prometheus.GaugeOpts{
-			Name: "email_validator_active_goroutines",
+			Name: MetricActiveGoroutines,
 			Help: "Current number of active goroutines",
 		},
 	)
@@ -69,15 +70,80 @@ var (
 	// MemoryUsage tracks the memory usage
 	MemoryUsage = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "email_validator_memory_usage_bytes",
+			Name: MetricMemoryUsage,
 			Help: "Current memory usage in bytes",
 		},
 		[]string{"type"},
 	)
 
+	// ConcurrentBatchRequests tracks the number of batch requests being processed concurrently
+	ConcurrentBatchRequests = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricConcurrentBatchRequests,
+			Help: "Number of batch requests being processed concurrently",
+		},
+		nil,
+	)
+
+	// InFlightRequests tracks the number of HTTP requests being served
+	InFlightRequests = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricInFlightRequests,
+			Help: "Number of HTTP requests currently being served",
+		},
+		nil,
+	)
+
+	// BatchWorkers tracks the number of running batch validation workers
+	BatchWorkers = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricBatchWorkers,
+			Help: "Number of running batch validation workers",
+		},
+		nil,
+	)
+
+	// BatchWorkersActive tracks the number of batch workers validating an email
+	BatchWorkersActive = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricBatchWorkersActive,
+			Help: "Number of batch validation workers currently validating an email",
+		},
+		nil,
+	)
+
+	// BatchWorkerUtilization tracks the share of running batch workers that are busy
+	BatchWorkerUtilization = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricBatchWorkerUtilization,
+			Help: "Active batch workers divided by running batch workers (0 when none are running)",
+		},
+		nil,
+	)
+
+	// BatchSize tracks the distribution of batch sizes
+	BatchSize = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    MetricBatchSize,
+			Help:    "Distribution of batch validation request sizes",
+			Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000},
+		},
+		nil,
+	)
+
+	// BatchProcessingTime tracks the time taken to process entire batches
+	BatchProcessingTime = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    MetricBatchProcessingTime,
+			Help:    "Time taken to process entire batch requests",
+			Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 20, 30, 60},
+		},
+		nil,
+	)
+
 	cacheHits = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "cache_hits_total",
+			Name: MetricCacheHits,
 			Help: "The total number of cache hits",
 		},
 		[]string{"cache_type"},
@@ -85,51 +151,237 @@ var (
 
 	cacheMisses = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "cache_misses_total",
+			Name: MetricCacheMisses,
 			Help: "The total number of cache misses",
 		},
 		[]string{"cache_type"},
 	)
+
+	// RedisErrors counts failed Redis cache operations
+	RedisErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricRedisErrors,
+			Help: "The total number of failed Redis cache operations",
+		},
+		[]string{"operation"},
+	)
+
+	// RedisCircuitOpen is 1 while the Redis cache is bypassed after repeated errors
+	RedisCircuitOpen = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricRedisCircuitOpen,
+			Help: "1 while the Redis cache circuit breaker is open and the cache is bypassed, 0 otherwise",
+		},
+		nil,
+	)
+
+	// SMTPCircuitOpen counts mailbox probes skipped because the domain's circuit breaker was open
+	SMTPCircuitOpen = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricSMTPCircuitOpen,
+			Help: "The total number of SMTP mailbox probes skipped because the domain's circuit breaker was open",
+		},
+		nil,
+	)
+
+	// SMTPRateLimited counts mailbox probes skipped because the probe rate budget was exhausted
+	SMTPRateLimited = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricSMTPRateLimited,
+			Help: "The total number of SMTP mailbox probes skipped because the probe rate limit was reached, by limit",
+		},
+		[]string{"limit"},
+	)
+
+	// TypoSuggestions counts typo suggestion requests by whether a suggestion was returned
+	TypoSuggestions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricTypoSuggestions,
+			Help: "The total number of typo suggestion requests, by whether a suggestion was returned",
+		},
+		[]string{"result"},
+	)
+
+	// TypoSuggestionsAccepted counts typo suggestions clients reported as accepted
+	TypoSuggestionsAccepted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricTypoSuggestionsAccepted,
+			Help: "The total number of typo suggestions clients reported as accepted",
+		},
+		nil,
+	)
+
+	// DNSQueueDepth is the number of DNS lookups waiting for a slot under the concurrency limit
+	DNSQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricDNSQueueDepth,
+			Help: "The number of DNS lookups waiting for a free slot under the DNS concurrency limit",
+		},
+		nil,
+	)
 )
 
 // RecordRequest records metrics for an API request
 func RecordRequest(endpoint, status string, duration time.Duration) {
-	RequestsTotal.WithLabelValues(endpoint, status).Inc()
-	RequestDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+	backend := CurrentBackend()
+	backend.IncCounter(MetricRequestsTotal, Labels{"endpoint": endpoint, "status": status})
+	backend.ObserveHistogram(MetricRequestDuration, duration.Seconds(), Labels{"endpoint": endpoint})
 }
 
 // RecordValidationScore records a validation score
 func RecordValidationScore(validationType string, score float64) {
-	ValidationScores.WithLabelValues(validationType).Observe(score)
+	CurrentBackend().ObserveHistogram(MetricValidationScores, score, Labels{"validation_type": validationType})
 }
 
 // RecordCacheOperation records a cache hit or miss
 func RecordCacheOperation(operation, result string) {
-	CacheOperations.WithLabelValues(operation, result).Inc()
+	CurrentBackend().IncCounter(MetricCacheOperations, Labels{"operation": operation, "result": result})
 }
 
 // RecordDNSLookup records DNS lookup duration
 func RecordDNSLookup(lookupType string, duration time.Duration) {
-	DNSLookupDuration.WithLabelValues(lookupType).Observe(duration.Seconds())
+	CurrentBackend().ObserveHistogram(MetricDNSLookupDuration, duration.Seconds(), Labels{"lookup_type": lookupType})
 }
 
 // UpdateGoroutineCount updates the active goroutine count
 func UpdateGoroutineCount(count float64) {
-	ActiveGoroutines.Set(count)
+	CurrentBackend().SetGauge(MetricActiveGoroutines, count, nil)
 }
 
 // UpdateMemoryUsage updates memory usage metrics
 func UpdateMemoryUsage(heapInUse, stackInUse float64) {
-	MemoryUsage.WithLabelValues("heap").Set(heapInUse)
-	MemoryUsage.WithLabelValues("stack").Set(stackInUse)
+	backend := CurrentBackend()
+	backend.SetGauge(MetricMemoryUsage, heapInUse, Labels{"type": "heap"})
+	backend.SetGauge(MetricMemoryUsage, stackInUse, Labels{"type": "stack"})
 }
 
 // RecordCacheHit records a cache hit for the specified cache type
 func RecordCacheHit(cacheType string) {
-	cacheHits.WithLabelValues(cacheType).Inc()
+	CurrentBackend().IncCounter(MetricCacheHits, Labels{"cache_type": cacheType})
 }
 
 // RecordCacheMiss records a cache miss for the specified cache type
 func RecordCacheMiss(cacheType string) {
-	cacheMisses.WithLabelValues(cacheType).Inc()
+	CurrentBackend().IncCounter(MetricCacheMisses, Labels{"cache_type": cacheType})
+}
+
+// RecordRedisError records a failed Redis cache operation
+func RecordRedisError(operation string) {
+	CurrentBackend().IncCounter(MetricRedisErrors, Labels{"operation": operation})
+}
+
+// SetRedisCircuitOpen records whether the Redis cache circuit breaker is open
+func SetRedisCircuitOpen(open bool) {
+	value := 0.0
+	if open {
+		value = 1
+	}
+	CurrentBackend().SetGauge(MetricRedisCircuitOpen, value, nil)
+}
+
+// RecordSMTPCircuitOpen records a mailbox probe skipped by the SMTP circuit breaker
+func RecordSMTPCircuitOpen() {
+	CurrentBackend().IncCounter(MetricSMTPCircuitOpen, nil)
+}
+
+// RecordSMTPRateLimited records a mailbox probe skipped by the SMTP rate governor, labelled
+// with the limit that was exhausted: "global" or a provider's domain
+func RecordSMTPRateLimited(limit string) {
+	CurrentBackend().IncCounter(MetricSMTPRateLimited, Labels{"limit": limit})
+}
+
+// RecordTypoSuggestion records a typo suggestion request and whether it returned a suggestion
+func RecordTypoSuggestion(suggested bool) {
+	result := "none"
+	if suggested {
+		result = "suggested"
+	}
+	CurrentBackend().IncCounter(MetricTypoSuggestions, Labels{"result": result})
+}
+
+// RecordTypoSuggestionAccepted records a typo suggestion a client reported as accepted
+func RecordTypoSuggestionAccepted() {
+	CurrentBackend().IncCounter(MetricTypoSuggestionsAccepted, nil)
+}
+
+// DNSLookupQueued marks a DNS lookup as waiting for a slot under the concurrency limit
+func DNSLookupQueued() {
+	CurrentBackend().AddGauge(MetricDNSQueueDepth, 1, nil)
+}
+
+// DNSLookupDequeued marks a DNS lookup as no longer waiting for a slot
+func DNSLookupDequeued() {
+	CurrentBackend().AddGauge(MetricDNSQueueDepth, -1, nil)
+}
+
+// BatchRequestStarted marks a batch request as in progress
+func BatchRequestStarted() {
+	CurrentBackend().AddGauge(MetricConcurrentBatchRequests, 1, nil)
+}
+
+// BatchRequestFinished marks a batch request as no longer in progress
+func BatchRequestFinished() {
+	CurrentBackend().AddGauge(MetricConcurrentBatchRequests, -1, nil)
+}
+
+// RequestStarted marks an HTTP request as in flight
+func RequestStarted() {
+	CurrentBackend().AddGauge(MetricInFlightRequests, 1, nil)
+}
+
+// RequestFinished marks an HTTP request as no longer in flight
+func RequestFinished() {
+	CurrentBackend().AddGauge(MetricInFlightRequests, -1, nil)
+}
+
+// batchWorkerPool counts batch workers across all batches in progress
+var batchWorkerPool struct {
+	mu     sync.Mutex
+	total  int
+	active int
+}
+
+// BatchWorkerStarted marks a batch worker as running
+func BatchWorkerStarted() {
+	updateBatchWorkers(1, 0)
+}
+
+// BatchWorkerStopped marks a batch worker as no longer running
+func BatchWorkerStopped() {
+	updateBatchWorkers(-1, 0)
+}
+
+// BatchWorkerBusy marks a running batch worker as validating an email
+func BatchWorkerBusy() {
+	updateBatchWorkers(0, 1)
+}
+
+// BatchWorkerIdle marks a running batch worker as waiting for work
+func BatchWorkerIdle() {
+	updateBatchWorkers(0, -1)
+}
+
+// updateBatchWorkers adjusts the worker counts and reports them. The gauges are set while
+// holding the lock, so concurrent updates cannot leave a stale value behind.
+func updateBatchWorkers(totalDelta, activeDelta int) {
+	batchWorkerPool.mu.Lock()
+	defer batchWorkerPool.mu.Unlock()
+	batchWorkerPool.total += totalDelta
+	batchWorkerPool.active += activeDelta
+
+	utilization := 0.0
+	if batchWorkerPool.total > 0 {
+		utilization = float64(batchWorkerPool.active) / float64(batchWorkerPool.total)
+	}
+	backend := CurrentBackend()
+	backend.SetGauge(MetricBatchWorkers, float64(batchWorkerPool.total), nil)
+	backend.SetGauge(MetricBatchWorkersActive, float64(batchWorkerPool.active), nil)
+	backend.SetGauge(MetricBatchWorkerUtilization, utilization, nil)
+}
+
+// RecordBatch records the size and total processing time of a batch request
+func RecordBatch(size int, duration time.Duration) {
+	backend := CurrentBackend()
+	backend.ObserveHistogram(MetricBatchSize, float64(size), nil)
+	backend.ObserveHistogram(MetricBatchProcessingTime, duration.Seconds(), nil)
 }
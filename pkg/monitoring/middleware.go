@@ -12,6 +12,9 @@ import (
 func MetricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		RequestStarted()
+		// Deferred so a panicking handler does not leave the request counted as in flight
+		defer RequestFinished()
 
 		// Create a custom response writer to capture the status code
 		rw := &responseWriter{
@@ -26,13 +29,12 @@ func MetricsMiddleware(next http.Handler) http.Handler {
 		duration := time.Since(start)
 		RecordRequest(r.URL.Path, http.StatusText(rw.statusCode), duration)
 
-		// Update system metrics periodically (every 100th request)
-		if RequestsTotal.WithLabelValues(r.URL.Path, "total").Inc(); true {
-			var m runtime.MemStats
-			runtime.ReadMemStats(&m)
-			UpdateMemoryUsage(float64(m.HeapInuse), float64(m.StackInuse))
-			UpdateGoroutineCount(float64(runtime.NumGoroutine()))
-		}
+		// Update system metrics
+		CurrentBackend().IncCounter(MetricRequestsTotal, Labels{"endpoint": r.URL.Path, "status": "total"})
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		UpdateMemoryUsage(float64(m.HeapInuse), float64(m.StackInuse))
+		UpdateGoroutineCount(float64(runtime.NumGoroutine()))
 	})
 }
 
@@ -0,0 +1,319 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OTLP aggregation temporality for cumulative metrics
+const otlpCumulative = 2
+
+// OTLPBackend aggregates metrics in memory and periodically pushes them to an
+// OpenTelemetry collector using OTLP/HTTP with JSON encoding.
+type OTLPBackend struct {
+	endpoint    string
+	serviceName string
+	interval    time.Duration
+	client      *http.Client
+	startTime   time.Time
+
+	mu         sync.Mutex
+	counters   map[string]*otlpSeries
+	gauges     map[string]*otlpSeries
+	histograms map[string]*otlpSeries
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type otlpSeries struct {
+	name   string
+	labels Labels
+	value  float64
+	count  uint64
+}
+
+// NewOTLPBackend creates an OTLP backend that pushes to endpoint (e.g. http://collector:4318/v1/metrics)
+// every interval. Call Start to begin pushing and Close to flush and stop.
+func NewOTLPBackend(endpoint, serviceName string, interval time.Duration) *OTLPBackend {
+	return NewOTLPBackendWithClient(&http.Client{Timeout: 10 * time.Second}, endpoint, serviceName, interval)
+}
+
+// NewOTLPBackendWithClient creates an OTLP backend that pushes to the collector with client
+func NewOTLPBackendWithClient(client *http.Client, endpoint, serviceName string, interval time.Duration) *OTLPBackend {
+	return &OTLPBackend{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		interval:    interval,
+		client:      client,
+		startTime:   time.Now(),
+		counters:    make(map[string]*otlpSeries),
+		gauges:      make(map[string]*otlpSeries),
+		histograms:  make(map[string]*otlpSeries),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start begins pushing metrics in the background
+func (b *OTLPBackend) Start() {
+	go func() {
+		defer close(b.done)
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := b.Flush(context.Background()); err != nil {
+					log.Printf("Error pushing OTLP metrics: %v", err)
+				}
+			case <-b.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background pusher and sends a final flush
+func (b *OTLPBackend) Close() error {
+	close(b.stop)
+	<-b.done
+	return b.Flush(context.Background())
+}
+
+// IncCounter increments a cumulative counter
+func (b *OTLPBackend) IncCounter(name string, labels Labels) {
+	b.mu.Lock()
+	b.series(b.counters, name, labels).value++
+	b.mu.Unlock()
+}
+
+// ObserveHistogram records an observation; histograms are exported as count and sum
+func (b *OTLPBackend) ObserveHistogram(name string, value float64, labels Labels) {
+	b.mu.Lock()
+	series := b.series(b.histograms, name, labels)
+	series.value += value
+	series.count++
+	b.mu.Unlock()
+}
+
+// SetGauge sets a gauge to the given value
+func (b *OTLPBackend) SetGauge(name string, value float64, labels Labels) {
+	b.mu.Lock()
+	b.series(b.gauges, name, labels).value = value
+	b.mu.Unlock()
+}
+
+// AddGauge adds delta to a gauge
+func (b *OTLPBackend) AddGauge(name string, delta float64, labels Labels) {
+	b.mu.Lock()
+	b.series(b.gauges, name, labels).value += delta
+	b.mu.Unlock()
+}
+
+// Flush pushes the current state of all metrics to the collector
+func (b *OTLPBackend) Flush(ctx context.Context) error {
+	body, err := json.Marshal(b.snapshot(time.Now()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp collector returned status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// series returns the series for name and labels, creating it if needed. Callers must hold b.mu.
+func (b *OTLPBackend) series(set map[string]*otlpSeries, name string, labels Labels) *otlpSeries {
+	key := seriesKey(name, labels)
+	s, ok := set[key]
+	if !ok {
+		copied := make(Labels, len(labels))
+		for k, v := range labels {
+			copied[k] = v
+		}
+		s = &otlpSeries{name: name, labels: copied}
+		set[key] = s
+	}
+	return s
+}
+
+func seriesKey(name string, labels Labels) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, key := range keys {
+		sb.WriteByte('|')
+		sb.WriteString(key)
+		sb.WriteByte('=')
+		sb.WriteString(labels[key])
+	}
+	return sb.String()
+}
+
+// The types below mirror the subset of the OTLP JSON metrics schema that this backend emits
+
+type otlpKeyValue struct {
+	Key   string            `json:"key"`
+	Value map[string]string `json:"value"`
+}
+
+type otlpDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsDouble          *float64       `json:"asDouble,omitempty"`
+	Count             string         `json:"count,omitempty"`
+	Sum               *float64       `json:"sum,omitempty"`
+	BucketCounts      []string       `json:"bucketCounts,omitempty"`
+}
+
+type otlpSum struct {
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpHistogram struct {
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpMetric struct {
+	Name      string         `json:"name"`
+	Sum       *otlpSum       `json:"sum,omitempty"`
+	Gauge     *otlpGauge     `json:"gauge,omitempty"`
+	Histogram *otlpHistogram `json:"histogram,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   map[string]string `json:"scope"`
+	Metrics []otlpMetric      `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     map[string][]otlpKeyValue `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics        `json:"scopeMetrics"`
+}
+
+type otlpExportRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+func (b *OTLPBackend) snapshot(now time.Time) otlpExportRequest {
+	start := strconv.FormatInt(b.startTime.UnixNano(), 10)
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	metrics := make(map[string]*otlpMetric)
+	metric := func(name string) *otlpMetric {
+		m, ok := metrics[name]
+		if !ok {
+			m = &otlpMetric{Name: name}
+			metrics[name] = m
+		}
+		return m
+	}
+
+	for _, s := range b.counters {
+		m := metric(s.name)
+		if m.Sum == nil {
+			m.Sum = &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+		}
+		value := s.value
+		m.Sum.DataPoints = append(m.Sum.DataPoints, otlpDataPoint{
+			Attributes: otlpAttributes(s.labels), StartTimeUnixNano: start, TimeUnixNano: ts, AsDouble: &value,
+		})
+	}
+	for _, s := range b.gauges {
+		m := metric(s.name)
+		if m.Gauge == nil {
+			m.Gauge = &otlpGauge{}
+		}
+		value := s.value
+		m.Gauge.DataPoints = append(m.Gauge.DataPoints, otlpDataPoint{
+			Attributes: otlpAttributes(s.labels), StartTimeUnixNano: start, TimeUnixNano: ts, AsDouble: &value,
+		})
+	}
+	for _, s := range b.histograms {
+		m := metric(s.name)
+		if m.Histogram == nil {
+			m.Histogram = &otlpHistogram{AggregationTemporality: otlpCumulative}
+		}
+		sum := s.value
+		count := strconv.FormatUint(s.count, 10)
+		m.Histogram.DataPoints = append(m.Histogram.DataPoints, otlpDataPoint{
+			Attributes: otlpAttributes(s.labels), StartTimeUnixNano: start, TimeUnixNano: ts,
+			Count: count, Sum: &sum, BucketCounts: []string{count},
+		})
+	}
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	exported := make([]otlpMetric, 0, len(names))
+	for _, name := range names {
+		exported = append(exported, *metrics[name])
+	}
+
+	return otlpExportRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource: map[string][]otlpKeyValue{
+				"attributes": {{Key: "service.name", Value: map[string]string{"stringValue": b.serviceName}}},
+			},
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope:   map[string]string{"name": "emailvalidator/pkg/monitoring"},
+				Metrics: exported,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(labels Labels) []otlpKeyValue {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attributes := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		attributes = append(attributes, otlpKeyValue{Key: key, Value: map[string]string{"stringValue": labels[key]}})
+	}
+	return attributes
+}
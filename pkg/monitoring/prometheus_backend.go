@@ -0,0 +1,91 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusBackend emits metrics to the Prometheus collectors registered by this package.
+// Metrics are exposed for scraping through PrometheusHandler.
+type PrometheusBackend struct{}
+
+var (
+	prometheusCounters = map[string]*prometheus.CounterVec{
+		MetricRequestsTotal:           RequestsTotal,
+		MetricCacheOperations:         CacheOperations,
+		MetricCacheHits:               cacheHits,
+		MetricCacheMisses:             cacheMisses,
+		MetricRedisErrors:             RedisErrors,
+		MetricSMTPCircuitOpen:         SMTPCircuitOpen,
+		MetricSMTPRateLimited:         SMTPRateLimited,
+		MetricTypoSuggestions:         TypoSuggestions,
+		MetricTypoSuggestionsAccepted: TypoSuggestionsAccepted,
+	}
+
+	prometheusHistograms = map[string]*prometheus.HistogramVec{
+		MetricRequestDuration:     RequestDuration,
+		MetricValidationScores:    ValidationScores,
+		MetricDNSLookupDuration:   DNSLookupDuration,
+		MetricBatchSize:           BatchSize,
+		MetricBatchProcessingTime: BatchProcessingTime,
+	}
+
+	// prometheusUnlabelledGauges holds gauges registered without labels; labels passed for them are ignored
+	prometheusUnlabelledGauges = map[string]prometheus.Gauge{
+		MetricActiveGoroutines: ActiveGoroutines,
+	}
+
+	prometheusGauges = map[string]*prometheus.GaugeVec{
+		MetricMemoryUsage:             MemoryUsage,
+		MetricConcurrentBatchRequests: ConcurrentBatchRequests,
+		MetricInFlightRequests:        InFlightRequests,
+		MetricBatchWorkers:            BatchWorkers,
+		MetricBatchWorkersActive:      BatchWorkersActive,
+		MetricBatchWorkerUtilization:  BatchWorkerUtilization,
+		MetricRedisCircuitOpen:        RedisCircuitOpen,
+		MetricDNSQueueDepth:           DNSQueueDepth,
+	}
+)
+
+// IncCounter increments a registered counter; unknown metrics or label sets are ignored
+func (PrometheusBackend) IncCounter(name string, labels Labels) {
+	if vec, ok := prometheusCounters[name]; ok {
+		if counter, err := vec.GetMetricWith(prometheus.Labels(labels)); err == nil {
+			counter.Inc()
+		}
+	}
+}
+
+// ObserveHistogram records an observation in a registered histogram
+func (PrometheusBackend) ObserveHistogram(name string, value float64, labels Labels) {
+	if vec, ok := prometheusHistograms[name]; ok {
+		if observer, err := vec.GetMetricWith(prometheus.Labels(labels)); err == nil {
+			observer.Observe(value)
+		}
+	}
+}
+
+// SetGauge sets a registered gauge
+func (PrometheusBackend) SetGauge(name string, value float64, labels Labels) {
+	if gauge, ok := prometheusUnlabelledGauges[name]; ok {
+		gauge.Set(value)
+		return
+	}
+	if vec, ok := prometheusGauges[name]; ok {
+		if gauge, err := vec.GetMetricWith(prometheus.Labels(labels)); err == nil {
+			gauge.Set(value)
+		}
+	}
+}
+
+// AddGauge adds delta to a registered gauge
+func (PrometheusBackend) AddGauge(name string, delta float64, labels Labels) {
+	if gauge, ok := prometheusUnlabelledGauges[name]; ok {
+		gauge.Add(delta)
+		return
+	}
+	if vec, ok := prometheusGauges[name]; ok {
+		if gauge, err := vec.GetMetricWith(prometheus.Labels(labels)); err == nil {
+			gauge.Add(delta)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package monitoring
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StatsDBackend emits metrics over UDP in the DogStatsD line format, with labels sent as tags.
+// Sends are fire-and-forget: a lost packet never blocks or fails the caller.
+type StatsDBackend struct {
+	conn   net.Conn
+	prefix string
+}
+
+// NewStatsDBackend creates a StatsD backend sending to addr (host:port).
+// If prefix is not empty it is prepended to every metric name, separated by a dot.
+func NewStatsDBackend(addr, prefix string) (*StatsDBackend, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	return &StatsDBackend{
+		conn:   conn,
+		prefix: prefix,
+	}, nil
+}
+
+// IncCounter sends a counter increment
+func (b *StatsDBackend) IncCounter(name string, labels Labels) {
+	b.send(name, "1", "c", labels)
+}
+
+// ObserveHistogram sends a histogram sample
+func (b *StatsDBackend) ObserveHistogram(name string, value float64, labels Labels) {
+	b.send(name, formatStatsDValue(value), "h", labels)
+}
+
+// SetGauge sends an absolute gauge value
+func (b *StatsDBackend) SetGauge(name string, value float64, labels Labels) {
+	// A leading sign would be read as a delta, so negative absolute values are reset to zero first
+	if value < 0 {
+		b.send(name, "0", "g", labels)
+	}
+	b.send(name, formatStatsDValue(value), "g", labels)
+}
+
+// AddGauge sends a relative gauge change
+func (b *StatsDBackend) AddGauge(name string, delta float64, labels Labels) {
+	value := formatStatsDValue(delta)
+	if delta >= 0 {
+		value = "+" + value
+	}
+	b.send(name, value, "g", labels)
+}
+
+// Close closes the underlying UDP socket
+func (b *StatsDBackend) Close() error {
+	return b.conn.Close()
+}
+
+func (b *StatsDBackend) send(name, value, metricType string, labels Labels) {
+	var line strings.Builder
+	line.WriteString(b.prefix)
+	line.WriteString(name)
+	line.WriteByte(':')
+	line.WriteString(value)
+	line.WriteByte('|')
+	line.WriteString(metricType)
+
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		line.WriteString("|#")
+		for i, key := range keys {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			line.WriteString(key)
+			line.WriteByte(':')
+			line.WriteString(labels[key])
+		}
+	}
+
+	_, _ = b.conn.Write([]byte(line.String()))
+}
+
+func formatStatsDValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Capabilities describes the addressing features a mail provider supports, which
+// determine how addresses at its domains can be canonicalized
+type Capabilities struct {
+	// DotInsensitive means dots in the local part are ignored (j.smith == jsmith)
+	DotInsensitive bool `json:"dot_insensitive"`
+	// PlusAddressing means anything after a "+" in the local part is ignored (jsmith+news == jsmith)
+	PlusAddressing bool `json:"plus_addressing"`
+	// SubdomainAddressing means mail to anything@user.domain is delivered to user@domain
+	SubdomainAddressing bool `json:"subdomain_addressing"`
+	// CaseInsensitive means the local part is matched ignoring case (JSmith == jsmith).
+	// Local parts are case-sensitive by standard, so only providers known to ignore case
+	// have their local parts lowercased by canonicalization.
+	CaseInsensitive bool `json:"case_insensitive"`
+}
+
+// defaultAddressingCapabilities lists the addressing features of major providers
+var defaultAddressingCapabilities = map[string]Capabilities{
+	"gmail.com":      {DotInsensitive: true, PlusAddressing: true, CaseInsensitive: true},
+	"googlemail.com": {DotInsensitive: true, PlusAddressing: true, CaseInsensitive: true},
+	"outlook.com":    {PlusAddressing: true, CaseInsensitive: true},
+	"hotmail.com":    {PlusAddressing: true, CaseInsensitive: true},
+	"live.com":       {PlusAddressing: true, CaseInsensitive: true},
+	"msn.com":        {PlusAddressing: true, CaseInsensitive: true},
+	"icloud.com":     {PlusAddressing: true, CaseInsensitive: true},
+	"me.com":         {PlusAddressing: true, CaseInsensitive: true},
+	"mac.com":        {PlusAddressing: true, CaseInsensitive: true},
+	"protonmail.com": {PlusAddressing: true, CaseInsensitive: true},
+	"proton.me":      {PlusAddressing: true, CaseInsensitive: true},
+	"pm.me":          {PlusAddressing: true, CaseInsensitive: true},
+	"fastmail.com":   {PlusAddressing: true, SubdomainAddressing: true, CaseInsensitive: true},
+	"fastmail.fm":    {PlusAddressing: true, SubdomainAddressing: true, CaseInsensitive: true},
+	"zoho.com":       {PlusAddressing: true, CaseInsensitive: true},
+	"yandex.com":     {PlusAddressing: true, CaseInsensitive: true},
+	"yandex.ru":      {PlusAddressing: true, CaseInsensitive: true},
+	"yahoo.com":      {CaseInsensitive: true},
+	"aol.com":        {CaseInsensitive: true},
+}
+
+// addressingColumns is the header expected in an addressing capabilities file. The last
+// column is optional, for files written before it existed; without it no entry is case-insensitive.
+var addressingColumns = []string{"domain", "dot_insensitive", "plus_addressing", "subdomain_addressing", "case_insensitive"}
+
+// ParseAddressingCapabilities reads an addressing capabilities table in CSV form. The first
+// row must be the header "domain,dot_insensitive,plus_addressing,subdomain_addressing,case_insensitive",
+// with or without the last column; flags accept any value understood by strconv.ParseBool.
+// Rows starting with "#" are skipped.
+func ParseAddressingCapabilities(r io.Reader) (map[string]Capabilities, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read addressing capabilities header: %w", err)
+	}
+	if len(header) != len(addressingColumns) && len(header) != len(addressingColumns)-1 {
+		return nil, fmt.Errorf("addressing capabilities header must be %q", strings.Join(addressingColumns, ","))
+	}
+	for i, column := range header {
+		if strings.ToLower(strings.TrimSpace(column)) != addressingColumns[i] {
+			return nil, fmt.Errorf("addressing capabilities header must be %q", strings.Join(addressingColumns, ","))
+		}
+	}
+
+	table := make(map[string]Capabilities)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read addressing capabilities: %w", err)
+		}
+
+		var flags [4]bool
+		for i := range record[1:] {
+			if flags[i], err = strconv.ParseBool(strings.TrimSpace(record[i+1])); err != nil {
+				return nil, fmt.Errorf("invalid %s value %q for %s", addressingColumns[i+1], record[i+1], record[0])
+			}
+		}
+		table[strings.ToLower(strings.TrimSpace(record[0]))] = Capabilities{
+			DotInsensitive:      flags[0],
+			PlusAddressing:      flags[1],
+			SubdomainAddressing: flags[2],
+			CaseInsensitive:     flags[3],
+		}
+	}
+	return table, nil
+}
+
+// LoadAddressingCapabilitiesFromFile reads an addressing capabilities table from a CSV file
+func LoadAddressingCapabilitiesFromFile(path string) (map[string]Capabilities, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseAddressingCapabilities(file)
+}
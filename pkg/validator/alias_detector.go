@@ -7,7 +7,8 @@ import (
 
 // AliasDetector provides methods for detecting email aliases
 type AliasDetector struct {
-	providers map[string]AliasProvider
+	providers    map[string]AliasProvider
+	capabilities map[string]Capabilities
 }
 
 // AliasProvider defines behavior for detecting aliases for a specific provider
@@ -21,7 +22,11 @@ type AliasProvider interface {
 // NewAliasDetector creates a new instance of AliasDetector
 func NewAliasDetector() *AliasDetector {
 	detector := &AliasDetector{
-		providers: make(map[string]AliasProvider),
+		providers:    make(map[string]AliasProvider),
+		capabilities: make(map[string]Capabilities, len(defaultAddressingCapabilities)),
+	}
+	for domain, caps := range defaultAddressingCapabilities {
+		detector.capabilities[domain] = caps
 	}
 
 	// Register providers
@@ -58,6 +63,28 @@ func (d *AliasDetector) DetectAlias(email string) string {
 	return ""
 }
 
+// AddressingCapabilities returns the addressing features supported at domain. A subdomain
+// of a provider with subdomain addressing (e.g. user.fastmail.com) inherits the provider's
+// capabilities. Unknown domains report no capabilities.
+func (d *AliasDetector) AddressingCapabilities(domain string) Capabilities {
+	domain = strings.ToLower(domain)
+	if caps, ok := d.capabilities[domain]; ok {
+		return caps
+	}
+	if idx := strings.Index(domain, "."); idx != -1 {
+		if caps, ok := d.capabilities[domain[idx+1:]]; ok && caps.SubdomainAddressing {
+			return caps
+		}
+	}
+	return Capabilities{}
+}
+
+// SetAddressingCapabilities adds or replaces the addressing capabilities of domain.
+// It is not safe to call concurrently with lookups.
+func (d *AliasDetector) SetAddressingCapabilities(domain string, caps Capabilities) {
+	d.capabilities[strings.ToLower(domain)] = caps
+}
+
 // --------------------------------------------------------
 // Gmail Alias Provider Implementation
 // --------------------------------------------------------
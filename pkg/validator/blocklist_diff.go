@@ -0,0 +1,128 @@
+package validator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listDiffOp is one line of a list diff: an entry added to or removed from the list
+type listDiffOp struct {
+	entry  string
+	remove bool
+}
+
+// fetchListDiff downloads the changes to a source since its entries were fetched at since.
+// The diff URL is requested with a "since" query parameter holding since as Unix seconds.
+// The server answers 200 with a diff, one "+entry" or "-entry" per line with blank lines
+// and "#" comments skipped, or 204 when nothing changed. Any other answer means the diff
+// is not available and the caller should fetch the whole list.
+func fetchListDiff(ctx context.Context, client *http.Client, diffURL string, since time.Time) ([]listDiffOp, error) {
+	u, err := url.Parse(diffURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch diff from %s: %w", diffURL, err)
+	}
+	query := u.Query()
+	query.Set("since", strconv.FormatInt(since.Unix(), 10))
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch diff from %s: %w", diffURL, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch diff from %s: %w", diffURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("failed to fetch diff from %s, status code: %d", diffURL, resp.StatusCode)
+	}
+
+	ops, err := parseListDiff(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse diff from %s: %w", diffURL, err)
+	}
+	return ops, nil
+}
+
+// parseListDiff reads a diff of "+entry" and "-entry" lines. A diff with any other line is
+// rejected as a whole, since applying part of it would leave the list in an unknown state.
+func parseListDiff(r io.Reader) ([]listDiffOp, error) {
+	var ops []listDiffOp
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		entry := strings.TrimSpace(text[1:])
+		if (text[0] != '+' && text[0] != '-') || entry == "" {
+			return nil, fmt.Errorf("line %d: %q is not a +entry or -entry change", line, text)
+		}
+		ops = append(ops, listDiffOp{entry: entry, remove: text[0] == '-'})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// applyListDiff applies a diff of source i, in order, to entrySources, which maps each
+// canonical entry to the sources listing it. When matcher is not nil, it is kept in step:
+// an entry no source lists any more is removed from it and a newly listed one is added.
+func applyListDiff(entrySources map[string][]int, i int, ops []listDiffOp, strategy MatchStrategy, matcher *DomainMatcher) {
+	for _, op := range ops {
+		entry := canonicalEntry(op.entry, strategy)
+		if entry == "" {
+			continue
+		}
+		sources, listed := entrySources[entry]
+		if op.remove {
+			sources = removeSource(sources, i)
+		} else {
+			sources = addSource(sources, i)
+		}
+		if len(sources) == 0 {
+			delete(entrySources, entry)
+			if listed && matcher != nil {
+				matcher.remove(entry)
+			}
+			continue
+		}
+		entrySources[entry] = sources
+		if !listed && matcher != nil {
+			matcher.add(entry)
+		}
+	}
+}
+
+// addSource adds source i to the sorted list of sources, if it is not there yet
+func addSource(sources []int, i int) []int {
+	pos, found := slices.BinarySearch(sources, i)
+	if found {
+		return sources
+	}
+	return slices.Insert(sources, pos, i)
+}
+
+// removeSource removes source i from the sorted list of sources, if it is there
+func removeSource(sources []int, i int) []int {
+	pos, found := slices.BinarySearch(sources, i)
+	if !found {
+		return sources
+	}
+	return slices.Delete(sources, pos, pos+1)
+}
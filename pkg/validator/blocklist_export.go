@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"slices"
+)
+
+// blocklistExportChunk is how many entries Export looks up under one hold of the read lock
+const blocklistExportChunk = 1000
+
+// BlocklistEntry is an entry of a disposable list and the sources listing it
+type BlocklistEntry struct {
+	// Entry is in canonical form, as in DisposableSource, or a regular expression for a
+	// pattern
+	Entry string
+	// Sources are DisposableSourceLocal for the local list, followed by the URLs of the
+	// remote sources listing the entry in source order, or DisposableSourcePattern for a
+	// local pattern
+	Sources []string
+}
+
+// Export calls fn with every entry of the loaded list, merged across sources, in sorted
+// order, and stops at the first error fn returns. Sources are filled in only when
+// withSources is set. Only the entry names are copied up front: each is looked up again
+// just before it is passed on, so a refresh running meanwhile is not held up, and an
+// entry it removes is skipped. ready is false, and fn is never called, until the list has
+// loaded. Entries the allowlist overrides for every domain they cover are left out, since
+// they never make a domain disposable.
+func (db *DisposableBlocklist) Export(withSources bool, fn func(BlocklistEntry) error) (ready bool, err error) {
+	if !db.IsReady() {
+		return false, nil
+	}
+
+	db.mu.RLock()
+	entries := make([]string, 0, len(db.entrySources))
+	for entry := range db.entrySources {
+		entries = append(entries, entry)
+	}
+	db.mu.RUnlock()
+	slices.Sort(entries)
+
+	chunk := make([]BlocklistEntry, 0, blocklistExportChunk)
+	for start := 0; start < len(entries); start += blocklistExportChunk {
+		chunk = chunk[:0]
+		db.mu.RLock()
+		for _, entry := range entries[start:min(start+blocklistExportChunk, len(entries))] {
+			sources, listed := db.entrySources[entry]
+			if !listed || allowlistOverrides(entry, db.allowlist) {
+				continue
+			}
+			exported := BlocklistEntry{Entry: entry}
+			if withSources {
+				for _, i := range sources {
+					exported.Sources = append(exported.Sources, db.sources[i].URL)
+				}
+			}
+			chunk = append(chunk, exported)
+		}
+		db.mu.RUnlock()
+
+		for _, entry := range chunk {
+			if err := fn(entry); err != nil {
+				return true, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// exportEntries returns the entries of the local list in canonical form, sorted, without
+// those the allowlist overrides for every domain they cover
+func (v *DisposableValidator) exportEntries() []string {
+	entries := v.disposableDomains.Load().canonicalEntries()
+	entries = slices.DeleteFunc(entries, func(entry string) bool {
+		return allowlistOverrides(entry, v.allowlist)
+	})
+	slices.Sort(entries)
+	return entries
+}
+
+// ExportDisposable calls fn with everything the validator and db treat as disposable, and
+// stops at the first error fn returns: the entries of the local list and of db, merged and
+// sorted, then, when withSources is set, the local patterns in file order. Entries the
+// allowlist overrides for every domain they cover are left out, the local allowlist
+// applying to local entries and db's to db's. Without withSources the patterns are left
+// out too, so the output is a plain domain list. db is streamed as by
+// DisposableBlocklist.Export; ready is false, and fn is never called, until it has loaded.
+func (v *EmailValidator) ExportDisposable(db *DisposableBlocklist, withSources bool, fn func(BlocklistEntry) error) (ready bool, err error) {
+	local := v.disposableValidator
+	if !db.IsReady() {
+		return false, nil
+	}
+	entries := local.exportEntries()
+
+	localEntry := func(entry string) BlocklistEntry {
+		exported := BlocklistEntry{Entry: entry}
+		if withSources {
+			exported.Sources = []string{DisposableSourceLocal}
+		}
+		return exported
+	}
+	ready, err = db.Export(withSources, func(remote BlocklistEntry) error {
+		for len(entries) > 0 && entries[0] <= remote.Entry {
+			entry := entries[0]
+			entries = entries[1:]
+			if entry == remote.Entry {
+				if withSources {
+					remote.Sources = append([]string{DisposableSourceLocal}, remote.Sources...)
+				}
+				break
+			}
+			if err := fn(localEntry(entry)); err != nil {
+				return err
+			}
+		}
+		return fn(remote)
+	})
+	if err != nil || !ready {
+		return ready, err
+	}
+	for _, entry := range entries {
+		if err := fn(localEntry(entry)); err != nil {
+			return true, err
+		}
+	}
+
+	if withSources && local.patterns != nil {
+		for _, pattern := range local.patterns.Patterns() {
+			if err := fn(BlocklistEntry{Entry: pattern, Sources: []string{DisposableSourcePattern}}); err != nil {
+				return true, err
+			}
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// canonicalDomains maps provider domains to the domain their mailboxes are known by
+var canonicalDomains = map[string]string{
+	"googlemail.com": "gmail.com",
+}
+
+// LocalPartCase controls how canonicalization treats the case of the local part. Local
+// parts are case-sensitive by standard (RFC 5321), though virtually every provider ignores
+// case; the address as typed is always kept in the response for display.
+type LocalPartCase string
+
+const (
+	// LocalPartCasePreserve keeps the local part as typed
+	LocalPartCasePreserve LocalPartCase = "preserve"
+	// LocalPartCaseProvider lowercases the local part at providers whose addressing
+	// capabilities mark them case-insensitive, and keeps it as typed elsewhere. This is the default.
+	LocalPartCaseProvider LocalPartCase = "provider"
+	// LocalPartCaseLower lowercases the local part at every domain
+	LocalPartCaseLower LocalPartCase = "lower"
+)
+
+// ParseLocalPartCase converts a configuration string into a LocalPartCase
+func ParseLocalPartCase(value string) (LocalPartCase, error) {
+	switch LocalPartCase(strings.ToLower(value)) {
+	case "", LocalPartCaseProvider:
+		return LocalPartCaseProvider, nil
+	case LocalPartCasePreserve:
+		return LocalPartCasePreserve, nil
+	case LocalPartCaseLower:
+		return LocalPartCaseLower, nil
+	default:
+		return "", fmt.Errorf("local part case %q: must be %q, %q or %q",
+			value, LocalPartCasePreserve, LocalPartCaseProvider, LocalPartCaseLower)
+	}
+}
+
+// lowercases reports whether the local part is lowercased at a provider with caps
+func (c LocalPartCase) lowercases(caps Capabilities) bool {
+	return c == LocalPartCaseLower || (c == LocalPartCaseProvider && caps.CaseInsensitive)
+}
+
+// CanonicalizationRules selects the normalizations Canonicalize applies. The domain is
+// always lowercased and internationalized labels converted to punycode; the other rules only apply where the provider's addressing
+// capabilities say they are safe.
+type CanonicalizationRules struct {
+	// LocalPartCase selects where the local part is lowercased. The zero value preserves it.
+	LocalPartCase LocalPartCase
+	// StripDots removes dots from the local part at dot-insensitive providers (Gmail)
+	StripDots bool
+	// StripSubaddress removes "+tag" from the local part at providers with plus addressing,
+	// and rewrites anything@user.domain to user@domain at providers with subdomain addressing
+	StripSubaddress bool
+	// UnifyDomains replaces provider domain aliases with the main domain (googlemail.com → gmail.com)
+	UnifyDomains bool
+}
+
+// DefaultCanonicalizationRules returns rules with every normalization enabled
+func DefaultCanonicalizationRules() CanonicalizationRules {
+	return CanonicalizationRules{
+		LocalPartCase:   LocalPartCaseProvider,
+		StripDots:       true,
+		StripSubaddress: true,
+		UnifyDomains:    true,
+	}
+}
+
+// Canonicalize returns the normalized form of email under rules, suitable as a stable key
+// for the mailbox. The domain always takes its NormalizeDomain form, so an internationalized
+// domain gives the same key whether written in Unicode or punycode, and the trailing dot of
+// a fully-qualified domain is removed. Addresses without exactly one "@" are returned unchanged.
+func (d *AliasDetector) Canonicalize(email string, rules CanonicalizationRules) string {
+	at := strings.LastIndex(email, "@")
+	if at == -1 || strings.Count(email, "@") != 1 {
+		return email
+	}
+	localPart, domain := email[:at], normalizeDomain(email[at+1:])
+
+	caps := d.AddressingCapabilities(domain)
+	if rules.StripSubaddress && caps.SubdomainAddressing {
+		if _, ok := d.capabilities[domain]; !ok {
+			// anything@user.fastmail.com is user@fastmail.com
+			dot := strings.Index(domain, ".")
+			localPart, domain = domain[:dot], domain[dot+1:]
+		}
+	}
+	if rules.StripSubaddress && caps.PlusAddressing {
+		if plus := strings.Index(localPart, "+"); plus > 0 {
+			localPart = localPart[:plus]
+		}
+	}
+	if rules.StripDots && caps.DotInsensitive {
+		localPart = strings.ReplaceAll(localPart, ".", "")
+	}
+	if rules.LocalPartCase.lowercases(caps) {
+		localPart = strings.ToLower(localPart)
+	}
+	if rules.UnifyDomains {
+		if main, ok := canonicalDomains[domain]; ok {
+			domain = main
+		}
+	}
+	return localPart + "@" + domain
+}
+
+// hyphenSubaddressDomains are providers whose extra addresses take the form base-keyword
+// rather than base+tag (Yahoo's disposable addresses)
+var hyphenSubaddressDomains = map[string]bool{
+	"yahoo.com": true,
+}
+
+// DetectSubaddress returns the subaddress tag of email, such as "news" in
+// jane+news@gmail.com, and whether email has one. Recognized forms are "+tag" at providers
+// with plus addressing, "-keyword" at Yahoo and tag@user.domain at providers with subdomain
+// addressing. Addresses at other providers never have a subaddress, since a "+" or "-" may
+// be part of the mailbox name there.
+func (d *AliasDetector) DetectSubaddress(email string) (string, bool) {
+	at := strings.LastIndex(email, "@")
+	if at == -1 || strings.Count(email, "@") != 1 {
+		return "", false
+	}
+	localPart, domain := email[:at], strings.ToLower(TrimTrailingDot(email[at+1:]))
+
+	caps := d.AddressingCapabilities(domain)
+	if _, ok := d.capabilities[domain]; caps.SubdomainAddressing && !ok {
+		// The whole local part of anything@user.fastmail.com is the tag
+		return localPart, true
+	}
+	separator := ""
+	switch {
+	case caps.PlusAddressing:
+		separator = "+"
+	case hyphenSubaddressDomains[domain]:
+		separator = "-"
+	default:
+		return "", false
+	}
+	if idx := strings.Index(localPart, separator); idx > 0 {
+		return localPart[idx+1:], true
+	}
+	return "", false
+}
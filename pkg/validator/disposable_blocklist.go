@@ -1,85 +1,470 @@
 package validator
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const disposableBlocklistURL = "https://raw.githubusercontent.com/disposable-email-domains/disposable-email-domains/refs/heads/main/disposable_email_blocklist.conf"
 
+// Defaults for loading a DisposableBlocklist
+const (
+	// DefaultBlocklistConcurrency is the number of sources fetched at the same time
+	DefaultBlocklistConcurrency = 4
+	// DefaultBlocklistLoadTimeout is the time allowed for fetching every source
+	DefaultBlocklistLoadTimeout = time.Minute
+	// DefaultBlocklistRetryInterval is how long LoadInBackground waits before retrying a failed load
+	DefaultBlocklistRetryInterval = time.Minute
+)
+
+// BlocklistSource describes a remote list of disposable domains and how to parse it
+type BlocklistSource struct {
+	URL    string
+	Parser ListParser // Defaults to PlaintextParser when nil
+	// DiffURL, if set, serves the changes to the list since a given time, so a refresh
+	// downloads only additions and removals; see fetchListDiff for the format. The whole
+	// list is fetched from URL on the first load and whenever the diff is unavailable.
+	// Only DisposableBlocklist uses it; RemoteListReader always fetches the whole list.
+	DiffURL string
+}
+
 // DisposableBlocklist manages the loading and checking of disposable email domains.
+// The list must be loaded explicitly (typically in the background at startup); lookups
+// never trigger network I/O and report the list as not ready until it has loaded.
 type DisposableBlocklist struct {
-	domains map[string]struct{}
-	once    sync.Once
-	mu      sync.RWMutex // Protects access to the domains map
+	sources     []BlocklistSource
+	client      *http.Client
+	concurrency int
+	loadTimeout time.Duration
+	strategy    MatchStrategy
+	domains     *DomainMatcher
+	allowlist   *DomainMatcher
+	loadedAt    time.Time
+	loadMu      sync.Mutex // Serializes loads, so concurrent callers share one fetch
+	// entrySources maps each entry of domains, in canonical form, to the indices of the
+	// sources listing it in ascending order. It is protected by mu, like domains, and only
+	// changed while loadMu is held too.
+	entrySources map[string][]int
+	// fetchedAt is when each source's entries were last brought up to date, indexed like
+	// sources, or zero if its last fetch failed, and entriesStrategy the strategy the entries
+	// were read with. Both are protected by loadMu.
+	fetchedAt       []time.Time
+	entriesStrategy MatchStrategy
+	ready           atomic.Bool
+	mu              sync.RWMutex // Protects access to the domains and allowlist matchers and loadedAt
+	refreshMu       sync.Mutex   // Protects refreshing
+	refreshing      *blocklistRefresh
+}
+
+// BlocklistStats describes the currently loaded blocklist
+type BlocklistStats struct {
+	// Domains is the number of entries merged from every source
+	Domains int
+	// LoadedAt is when the list was last loaded; zero if it has not loaded yet
+	LoadedAt time.Time
+}
+
+// blocklistRefresh is a refresh in flight, whose outcome is shared by every caller that
+// asks for a refresh before it finishes
+type blocklistRefresh struct {
+	done  chan struct{}
+	stats BlocklistStats
+	err   error
 }
 
 // NewDisposableBlocklist creates and returns a new DisposableBlocklist instance.
 func NewDisposableBlocklist() *DisposableBlocklist {
+	return NewDisposableBlocklistWithSources(DefaultBlocklistSources()...)
+}
+
+// DefaultBlocklistSources returns the sources fetched when none are configured
+func DefaultBlocklistSources() []BlocklistSource {
+	return []BlocklistSource{{URL: disposableBlocklistURL}}
+}
+
+// NewDisposableBlocklistWithURL creates a new DisposableBlocklist that fetches a plaintext list from a custom URL
+func NewDisposableBlocklistWithURL(url string) *DisposableBlocklist {
+	return NewDisposableBlocklistWithSources(BlocklistSource{URL: url})
+}
+
+// NewDisposableBlocklistWithSources creates a new DisposableBlocklist that merges several lists,
+// each parsed with its own parser, using the default HTTP client
+func NewDisposableBlocklistWithSources(sources ...BlocklistSource) *DisposableBlocklist {
+	return NewDisposableBlocklistWithClient(DefaultHTTPClient(), sources...)
+}
+
+// NewDisposableBlocklistWithClient creates a new DisposableBlocklist that fetches sources with client
+func NewDisposableBlocklistWithClient(client *http.Client, sources ...BlocklistSource) *DisposableBlocklist {
 	return &DisposableBlocklist{
-		domains: make(map[string]struct{}),
+		sources:     sources,
+		client:      client,
+		concurrency: DefaultBlocklistConcurrency,
+		loadTimeout: DefaultBlocklistLoadTimeout,
+		domains:     NewDomainMatcher(nil),
 	}
 }
 
-// Load fetches the disposable email domain blocklist from the URL and populates the internal map.
-// It uses sync.Once to ensure the list is loaded only once.
+// SetConcurrency sets how many sources Load fetches at the same time
+func (db *DisposableBlocklist) SetConcurrency(n int) {
+	db.concurrency = max(n, 1)
+}
+
+// SetLoadTimeout sets the time Load allows for fetching every source. Sources still
+// being fetched at the deadline are abandoned and the others are loaded. A timeout of 0
+// or less sets no deadline, leaving each fetch to the HTTP client's own timeout.
+func (db *DisposableBlocklist) SetLoadTimeout(timeout time.Duration) {
+	db.loadTimeout = timeout
+}
+
+// SetMatchStrategy sets whether entries of the fetched lists cover subdomains. It applies
+// from the next load, so call it before Load. The allowlist is not affected.
+func (db *DisposableBlocklist) SetMatchStrategy(strategy MatchStrategy) {
+	db.strategy = strategy
+}
+
+// SetHTTPClient sets the client used to fetch the sources, e.g. one that goes through a proxy
+func (db *DisposableBlocklist) SetHTTPClient(client *http.Client) {
+	db.client = client
+}
+
+// Load fetches every source concurrently, merges the parsed domains and populates the
+// internal matcher, blocking until it is done. Once the list has loaded, further calls do
+// nothing; a call made while another is loading waits for it rather than fetching again.
+// Sources that fail or miss the load deadline are logged and skipped; Load only fails,
+// loading nothing, when no source succeeds, and may then be called again.
 func (db *DisposableBlocklist) Load() error {
-	var err error
-	db.once.Do(func() {
-		log.Println("Loading disposable email domain blocklist...")
-		client := &http.Client{Timeout: 10 * time.Second}
-		resp, httpErr := client.Get(disposableBlocklistURL)
-		if httpErr != nil {
-			err = fmt.Errorf("failed to fetch disposable domains: %w", httpErr)
-			log.Printf("Error fetching disposable domains: %v", err)
-			return
+	return db.load(context.Background())
+}
+
+// LoadInBackground loads the list without blocking the caller, retrying failed loads
+// every retryInterval until one succeeds or ctx is done. Lookups report the list as not
+// ready in the meantime. The returned channel is closed when loading stops.
+func (db *DisposableBlocklist) LoadInBackground(ctx context.Context, retryInterval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			err := db.load(ctx)
+			if err == nil || ctx.Err() != nil {
+				return
+			}
+			log.Printf("Warning: Failed to load disposable blocklist, retrying in %v: %v", retryInterval, err)
+			select {
+			case <-time.After(retryInterval):
+			case <-ctx.Done():
+				return
+			}
 		}
-		defer resp.Body.Close()
+	}()
+	return done
+}
+
+// load is Load, abandoning the fetch when ctx is done
+func (db *DisposableBlocklist) load(ctx context.Context) error {
+	db.loadMu.Lock()
+	defer db.loadMu.Unlock()
+	if db.ready.Load() {
+		return nil
+	}
+	return db.fetch(ctx)
+}
+
+// Refresh re-fetches every source and swaps in the merged list, whether or not the list
+// has loaded before, and returns the stats of the list now in use. A call made while a
+// refresh is in flight waits for it and shares its outcome rather than fetching again. If
+// no source loads, the current list is kept. Cancelling ctx only abandons the wait; the
+// refresh itself runs to completion for any other callers.
+func (db *DisposableBlocklist) Refresh(ctx context.Context) (BlocklistStats, error) {
+	db.refreshMu.Lock()
+	call := db.refreshing
+	if call == nil {
+		call = &blocklistRefresh{done: make(chan struct{})}
+		db.refreshing = call
+		go db.runRefresh(call)
+	}
+	db.refreshMu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.stats, call.err
+	case <-ctx.Done():
+		return BlocklistStats{}, ctx.Err()
+	}
+}
+
+// runRefresh performs call and publishes its outcome
+func (db *DisposableBlocklist) runRefresh(call *blocklistRefresh) {
+	db.loadMu.Lock()
+	call.err = db.fetch(context.Background())
+	db.loadMu.Unlock()
+	call.stats = db.Stats()
+
+	db.refreshMu.Lock()
+	db.refreshing = nil
+	db.refreshMu.Unlock()
+	close(call.done)
+}
+
+// fetch downloads every source, or the changes to it where the source serves diffs, and
+// swaps in the merged list. The caller must hold loadMu.
+func (db *DisposableBlocklist) fetch(ctx context.Context) error {
+	log.Println("Loading disposable email domain blocklist...")
+
+	// Entries read with another match strategy cannot take a diff
+	fetchedAt := db.fetchedAt
+	if db.entriesStrategy != db.strategy {
+		fetchedAt = nil
+	}
+
+	if db.loadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, db.loadTimeout)
+		defer cancel()
+	}
+	results := db.fetchSources(ctx, fetchedAt)
+
+	var errs []error
+	loaded, diffs := 0, 0
+	for _, result := range results {
+		if result.err != nil {
+			errs = append(errs, result.err)
+			continue
+		}
+		loaded++
+		if result.diff {
+			diffs++
+		}
+	}
+	if loaded == 0 && len(db.sources) > 0 {
+		return errors.Join(errs...)
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			err = fmt.Errorf("failed to fetch disposable domains, status code: %d", resp.StatusCode)
-			log.Printf("Error fetching disposable domains: %v", err)
-			return
+	if diffs > 0 && diffs == len(db.sources) {
+		db.applyDiffs(results)
+	} else {
+		db.rebuild(results)
+	}
+	db.ready.Store(true)
+	return nil
+}
+
+// rebuild swaps in a new matcher merged from the sources that loaded. A source that
+// failed is left out, and its next fetch is a full one. A source that returned a diff has
+// it applied to the entries it had in the loaded list. The caller must hold loadMu.
+func (db *DisposableBlocklist) rebuild(results []sourceResult) {
+	entrySources := make(map[string][]int)
+	fetchedAt := make([]time.Time, len(db.sources))
+	loaded := 0
+	for i, result := range results {
+		if result.err != nil {
+			continue
+		}
+		loaded++
+		fetchedAt[i] = result.startedAt
+		if result.diff {
+			// entrySources is only changed under loadMu, so it can be read without mu
+			for entry, sources := range db.entrySources {
+				if slices.Contains(sources, i) {
+					entrySources[entry] = addSource(entrySources[entry], i)
+				}
+			}
+			applyListDiff(entrySources, i, result.ops, db.strategy, nil)
+			continue
+		}
+		for _, domain := range result.domains {
+			if entry := canonicalEntry(domain, db.strategy); entry != "" {
+				entrySources[entry] = addSource(entrySources[entry], i)
+			}
 		}
+	}
+
+	newDomains := NewDomainMatcher(nil)
+	for entry := range entrySources {
+		newDomains.add(entry)
+	}
+	db.mu.Lock()
+	db.domains = newDomains
+	db.entrySources = entrySources
+	db.loadedAt = time.Now()
+	db.mu.Unlock()
+	db.fetchedAt, db.entriesStrategy = fetchedAt, db.strategy
+	log.Printf("Successfully loaded %d disposable email domains from %d of %d sources.", newDomains.Len(), loaded, len(db.sources))
+}
 
-		newDomains := make(map[string]struct{})
-		scanner := bufio.NewScanner(resp.Body)
-		for scanner.Scan() {
-			domain := strings.TrimSpace(scanner.Text())
-			if domain != "" && !strings.HasPrefix(domain, "#") { // Ignore empty lines and comments
-				newDomains[domain] = struct{}{}
+// applyDiffs applies the diff every source returned to the loaded list in place, without
+// rebuilding it. An entry removed from one source stays listed while another source still
+// lists it. The caller must hold loadMu, and every source must have returned a diff.
+func (db *DisposableBlocklist) applyDiffs(results []sourceResult) {
+	changes := 0
+	db.mu.Lock()
+	for i, result := range results {
+		applyListDiff(db.entrySources, i, result.ops, db.strategy, db.domains)
+		db.fetchedAt[i] = result.startedAt
+		changes += len(result.ops)
+	}
+	db.loadedAt = time.Now()
+	count := db.domains.Len()
+	db.mu.Unlock()
+	log.Printf("Applied %d changes to the disposable email domain blocklist, now %d domains.", changes, count)
+}
+
+// sourceResult is the outcome of fetching one blocklist source: its whole list, or the
+// diff against its saved entries when diff is true
+type sourceResult struct {
+	domains   []string
+	diff      bool
+	ops       []listDiffOp
+	startedAt time.Time
+	err       error
+}
+
+// fetchSources fetches every source with at most db.concurrency requests in flight and
+// returns the results in source order. A source with a DiffURL whose entries are up to
+// date as of fetchedAt is asked for a diff first, and fetched whole if that fails.
+func (db *DisposableBlocklist) fetchSources(ctx context.Context, fetchedAt []time.Time) []sourceResult {
+	results := make([]sourceResult, len(db.sources))
+	slots := make(chan struct{}, max(db.concurrency, 1))
+	var wg sync.WaitGroup
+	for i, source := range db.sources {
+		wg.Add(1)
+		go func(i int, source BlocklistSource) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				results[i].err = fmt.Errorf("failed to fetch disposable domains from %s: %w", source.URL, ctx.Err())
+				log.Printf("Error fetching disposable domains: %v", results[i].err)
+				return
+			}
+
+			start := time.Now()
+			results[i].startedAt = start
+			if source.DiffURL != "" && i < len(fetchedAt) && !fetchedAt[i].IsZero() {
+				ops, err := fetchListDiff(ctx, db.client, source.DiffURL, fetchedAt[i])
+				if err == nil {
+					results[i].diff, results[i].ops = true, ops
+					log.Printf("Fetched %d disposable domain changes from %s in %v", len(ops), source.DiffURL, time.Since(start).Round(time.Millisecond))
+					return
+				}
+				log.Printf("Diff unavailable, fetching the whole list: %v", err)
+			}
+			results[i].domains, results[i].err = fetchListSource(ctx, db.client, source)
+			if results[i].err != nil {
+				log.Printf("Error fetching disposable domains: %v (after %v)", results[i].err, time.Since(start).Round(time.Millisecond))
+				return
 			}
+			log.Printf("Fetched %d disposable domains from %s in %v", len(results[i].domains), source.URL, time.Since(start).Round(time.Millisecond))
+		}(i, source)
+	}
+	wg.Wait()
+	return results
+}
+
+// fetchListSource downloads a single source and parses it with the source's parser
+func fetchListSource(ctx context.Context, client *http.Client, source BlocklistSource) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch list from %s: %w", source.URL, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch list from %s: %w", source.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch list from %s, status code: %d", source.URL, resp.StatusCode)
+	}
+
+	parser := source.Parser
+	if parser == nil {
+		parser = PlaintextParser{}
+	}
+
+	domains, err := parser.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse list from %s: %w", source.URL, err)
+	}
+	return domains, nil
+}
+
+// ParseBlocklistSources parses a comma-separated list of sources. Each entry is a URL,
+// optionally prefixed with a parser name and "=", e.g. "json=https://example.com/list.json",
+// and optionally followed by ";diff=" and the URL serving the list's diffs.
+func ParseBlocklistSources(spec string) ([]BlocklistSource, error) {
+	var sources []BlocklistSource
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
 		}
 
-		if scanErr := scanner.Err(); scanErr != nil {
-			err = fmt.Errorf("failed to read disposable domains: %w", scanErr)
-			log.Printf("Error reading disposable domains: %v", err)
-			return
+		entry, diffURL, hasDiff := strings.Cut(entry, ";diff=")
+		if hasDiff && strings.TrimSpace(diffURL) == "" {
+			return nil, fmt.Errorf("invalid blocklist source %q: diff URL is empty", entry)
 		}
 
-		db.mu.Lock()
-		db.domains = newDomains
-		db.mu.Unlock()
-		log.Printf("Successfully loaded %d disposable email domains.", len(newDomains))
-	})
-	return err
+		parserName, url := "", entry
+		if name, rest, found := strings.Cut(entry, "="); found && !strings.Contains(name, "/") {
+			parserName, url = name, rest
+		}
+
+		parser, err := ParserByName(parserName)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, BlocklistSource{URL: url, Parser: parser, DiffURL: strings.TrimSpace(diffURL)})
+	}
+	return sources, nil
 }
 
-// IsDisposable checks if the given domain is present in the disposable email domain blocklist.
-func (db *DisposableBlocklist) IsDisposable(domain string) bool {
-	// Ensure the list is loaded before checking
-	if err := db.Load(); err != nil {
-		log.Printf("Warning: Disposable blocklist not loaded, cannot check domain %s: %v", domain, err)
-		return false // Cannot confirm, so assume not disposable
+// SetAllowlist sets domains that are never considered disposable, even if they match the blocklist.
+// Entries may be exact domains or wildcards like "*.example.com".
+func (db *DisposableBlocklist) SetAllowlist(domains []string) {
+	allowlist := NewDomainMatcher(domains)
+	db.mu.Lock()
+	db.allowlist = allowlist
+	db.mu.Unlock()
+}
+
+// Stats returns the size of the loaded list and when it was loaded
+func (db *DisposableBlocklist) Stats() BlocklistStats {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return BlocklistStats{Domains: db.domains.Len(), LoadedAt: db.loadedAt}
+}
+
+// IsReady reports whether the blocklist has been loaded successfully.
+func (db *DisposableBlocklist) IsReady() bool {
+	return db.ready.Load()
+}
+
+// Lookup checks the domain against the blocklist without triggering a load.
+// The ready flag is false when the list has not been loaded yet, in which case
+// the disposable result is always false and should be treated as unknown.
+func (db *DisposableBlocklist) Lookup(domain string) (disposable, ready bool) {
+	if !db.IsReady() {
+		return false, false
 	}
 
 	db.mu.RLock()
-	_, found := db.domains[strings.ToLower(domain)]
+	found := isBlocked(domain, db.domains, db.allowlist)
 	db.mu.RUnlock()
-	return found
-}
\ No newline at end of file
+	return found, true
+}
+
+// IsDisposable checks if the given domain is present in the disposable email domain blocklist.
+// It returns false if the list has not been loaded yet.
+func (db *DisposableBlocklist) IsDisposable(domain string) bool {
+	disposable, _ := db.Lookup(domain)
+	return disposable
+}
@@ -39,11 +39,8 @@ func LoadDisposableDomainsFromFile(path string) ([]string, error) {
 	return domains, nil
 }
 
-// NewDisposableValidatorFromFile creates a new instance of DisposableValidator using domains from a file
+// NewDisposableValidatorFromFile creates a new instance of DisposableValidator using domains from a file.
+// Reload re-reads the file.
 func NewDisposableValidatorFromFile(path string) (*DisposableValidator, error) {
-	domains, err := LoadDisposableDomainsFromFile(path)
-	if err != nil {
-		return nil, err
-	}
-	return NewDisposableValidatorWithReader(NewStaticDomainReader(domains))
+	return NewDisposableValidatorWithReader(NewFileDomainReader(filepath.Clean(path)))
 }
@@ -0,0 +1,166 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// RandomLabelRule is the name of the built-in rule that matches random-looking labels,
+// such as the generated subdomains some disposable services hand out. It takes no pattern.
+const RandomLabelRule = "random_label"
+
+// defaultHeuristicRules are the rules used when no rules file is configured, one
+// "name weight pattern" entry per line
+var defaultHeuristicRules = []string{
+	`disposable_keyword 0.9 (tempmail|temp-mail|tmpmail|trashmail|trash-mail|throwawaymail|guerrillamail|mailinator|yopmail|fakeinbox|spamgourmet|spambox|dispostable|discardmail|disposable|burnermail|maildrop|mailcatch|mohmal)`,
+	`minute_mail 0.8 \d+-?min(ute)?s?-?(e-?mail|mail|inbox|box)`,
+	`throwaway_word 0.4 (temp|tmp|trash|burner|throwaway|fake|junk|spam|dump)`,
+	`mailbox_word 0.2 (mail|inbox|box)`,
+	RandomLabelRule + ` 0.5`,
+}
+
+// HeuristicRule is one signal that a domain may be disposable
+type HeuristicRule struct {
+	Name string
+	// Weight is how strongly a match suggests a disposable domain, from 0 to 1
+	Weight float64
+	// Pattern is matched against the lowercased domain; nil for RandomLabelRule
+	Pattern *regexp.Regexp
+}
+
+// ParseHeuristicRules parses rules written one per line as "name weight pattern", where
+// pattern is a regular expression matched anywhere in the lowercased domain. The
+// RandomLabelRule entry is written without a pattern.
+func ParseHeuristicRules(lines []string) ([]HeuristicRule, error) {
+	rules := make([]HeuristicRule, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid heuristic rule %q: want \"name weight pattern\"", line)
+		}
+		weight, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || weight < 0 || weight > 1 {
+			return nil, fmt.Errorf("invalid heuristic rule %q: weight must be a number from 0 to 1", line)
+		}
+		rule := HeuristicRule{Name: fields[0], Weight: weight}
+
+		switch {
+		case rule.Name == RandomLabelRule && len(fields) == 2:
+		case rule.Name == RandomLabelRule:
+			return nil, fmt.Errorf("invalid heuristic rule %q: %s takes no pattern", line, RandomLabelRule)
+		case len(fields) != 3:
+			return nil, fmt.Errorf("invalid heuristic rule %q: want \"name weight pattern\"", line)
+		default:
+			if rule.Pattern, err = regexp.Compile(fields[2]); err != nil {
+				return nil, fmt.Errorf("invalid heuristic rule %q: %w", line, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// DisposableHeuristicDetector scores how likely a domain is to be disposable from its
+// name alone, to catch disposable services newer than the lists
+type DisposableHeuristicDetector struct {
+	reader DomainReader
+	rules  atomic.Pointer[[]HeuristicRule]
+}
+
+// NewDisposableHeuristicDetector creates a DisposableHeuristicDetector with the default rules
+func NewDisposableHeuristicDetector() *DisposableHeuristicDetector {
+	d := &DisposableHeuristicDetector{reader: NewStaticDomainReader(defaultHeuristicRules)}
+	// The default rules are known to parse
+	rules, _ := ParseHeuristicRules(defaultHeuristicRules)
+	d.rules.Store(&rules)
+	return d
+}
+
+// NewDisposableHeuristicDetectorFromFile creates a DisposableHeuristicDetector with the rules
+// in a file, one per line. Empty lines and lines starting with "#" are skipped.
+func NewDisposableHeuristicDetectorFromFile(path string) (*DisposableHeuristicDetector, error) {
+	d := &DisposableHeuristicDetector{reader: NewFileDomainReader(path)}
+	if err := d.Reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reload re-reads the rules and atomically swaps them in. If the new content is empty or
+// contains an invalid rule, the current rules are kept.
+func (d *DisposableHeuristicDetector) Reload() error {
+	lines, err := d.reader.ReadDomains()
+	if err != nil {
+		return fmt.Errorf("failed to read heuristic rules: %w", err)
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("heuristic rule list is empty")
+	}
+	rules, err := ParseHeuristicRules(lines)
+	if err != nil {
+		return err
+	}
+	d.rules.Store(&rules)
+	return nil
+}
+
+// Score returns how likely domain is to be disposable, from 0 to 1, and the names of the
+// rules it matched. Matches combine so that each one closes part of the remaining gap to
+// 1: two rules of weight 0.5 score 0.75.
+func (d *DisposableHeuristicDetector) Score(domain string) (float64, []string) {
+	domain = normalizeDomain(domain)
+	remaining := 1.0
+	var matched []string
+	for _, rule := range *d.rules.Load() {
+		if rule.Pattern != nil && rule.Pattern.MatchString(domain) ||
+			rule.Pattern == nil && hasRandomLabel(domain) {
+			remaining *= 1 - rule.Weight
+			matched = append(matched, rule.Name)
+		}
+	}
+	return math.Round((1-remaining)*100) / 100, matched
+}
+
+// hasRandomLabel reports whether a label of domain other than its public suffix looks
+// machine-generated
+func hasRandomLabel(domain string) bool {
+	suffix, _ := publicsuffix.PublicSuffix(domain)
+	for _, label := range strings.Split(strings.TrimSuffix(domain, suffix), ".") {
+		if looksRandom(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksRandom reports whether label is long and alphanumeric, and either switches between
+// letters and digits several times ("x7k2m9qa") or has almost no vowels ("qzkwrtxp")
+func looksRandom(label string) bool {
+	if len(label) < 8 {
+		return false
+	}
+	letters, vowels, switches := 0, 0, 0
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		isDigit := '0' <= c && c <= '9'
+		if !isDigit && !('a' <= c && c <= 'z') {
+			return false
+		}
+		if i > 0 && isDigit != ('0' <= label[i-1] && label[i-1] <= '9') {
+			switches++
+		}
+		if !isDigit {
+			letters++
+			if strings.IndexByte("aeiouy", c) != -1 {
+				vowels++
+			}
+		}
+	}
+	return switches >= 3 || letters >= 6 && float64(vowels)/float64(letters) < 0.2
+}
@@ -0,0 +1,180 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+
+	"emailvalidator/pkg/monitoring"
+)
+
+// DefaultDisposableMXCacheTTL is how long a domain's mail server domains are cached by a
+// DisposableMXDetector
+const DefaultDisposableMXCacheTTL = time.Hour
+
+// defaultDisposableMXProviders are the domains of the mail servers of disposable email
+// services, used when no disposable MX provider list is configured
+var defaultDisposableMXProviders = []string{
+	"10minutemail.com",
+	"dropmail.me",
+	"emailondeck.com",
+	"guerrillamail.com",
+	"mail.tm",
+	"maildrop.cc",
+	"mailinator.com",
+	"mailnesia.com",
+	"temp-mail.org",
+	"yopmail.com",
+}
+
+// mxDomainsEntry is a domain's cached mail server domains
+type mxDomainsEntry struct {
+	domains   []string
+	timestamp time.Time
+}
+
+// DisposableMXDetector recognizes domains that receive their mail through a disposable
+// email service. Some services let users bring their own domain, so such a domain is on
+// no disposable list, but its MX records point at the service's mail servers.
+type DisposableMXDetector struct {
+	reader    DomainReader
+	providers atomic.Pointer[DomainMatcher]
+
+	// The cache holds the registrable domains of each domain's mail servers rather than a
+	// verdict, so a reloaded provider list applies at once
+	cacheTTL   time.Duration
+	cacheMutex sync.RWMutex
+	cache      map[string]mxDomainsEntry
+}
+
+// NewDisposableMXDetector creates a DisposableMXDetector with the built-in list of
+// disposable providers' mail server domains
+func NewDisposableMXDetector() *DisposableMXDetector {
+	d := newDisposableMXDetector(NewStaticDomainReader(defaultDisposableMXProviders))
+	d.providers.Store(NewDomainMatcher(defaultDisposableMXProviders))
+	return d
+}
+
+// NewDisposableMXDetectorFromFile creates a DisposableMXDetector using provider mail server
+// domains from a file with one entry per line. Entries are registrable domains like
+// "mailinator.com", matched against the registrable domain of each MX host.
+func NewDisposableMXDetectorFromFile(path string) (*DisposableMXDetector, error) {
+	d := newDisposableMXDetector(NewFileDomainReader(path))
+	if err := d.Reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func newDisposableMXDetector(reader DomainReader) *DisposableMXDetector {
+	return &DisposableMXDetector{
+		reader:   reader,
+		cacheTTL: DefaultDisposableMXCacheTTL,
+		cache:    make(map[string]mxDomainsEntry),
+	}
+}
+
+// SetCacheTTL sets how long a domain's mail server domains are cached. Zero disables the cache.
+func (d *DisposableMXDetector) SetCacheTTL(ttl time.Duration) {
+	d.cacheMutex.Lock()
+	d.cacheTTL = ttl
+	d.cacheMutex.Unlock()
+}
+
+// Reload re-reads the provider list and atomically swaps it in. If the new content is
+// empty or contains an invalid entry, the current list is kept.
+func (d *DisposableMXDetector) Reload() error {
+	providers, err := d.reader.ReadDomains()
+	if err != nil {
+		return fmt.Errorf("failed to read disposable MX provider list: %w", err)
+	}
+	if len(providers) == 0 {
+		return fmt.Errorf("disposable MX provider list is empty")
+	}
+	for _, provider := range providers {
+		if !isValidListDomain(provider) {
+			return fmt.Errorf("invalid disposable MX provider list entry %q", provider)
+		}
+	}
+
+	d.providers.Store(NewDomainMatcher(providers))
+	return nil
+}
+
+// Provider returns the disposable provider domain that one of mxDomains belongs to, or
+// an empty string
+func (d *DisposableMXDetector) Provider(mxDomains []string) string {
+	providers := d.providers.Load()
+	for _, mxDomain := range mxDomains {
+		if providers.Contains(mxDomain) {
+			return mxDomain
+		}
+	}
+	return ""
+}
+
+// mxDomains returns the registrable domains of domain's mail servers, from the cache or
+// by looking them up. ok is false when the lookup failed inconclusively; such results are
+// not cached.
+func (d *DisposableMXDetector) mxDomains(v *DomainValidator, domain string) (domains []string, ok bool) {
+	d.cacheMutex.RLock()
+	entry, found := d.cache[domain]
+	ttl := d.cacheTTL
+	d.cacheMutex.RUnlock()
+	if found && time.Since(entry.timestamp) <= ttl {
+		monitoring.RecordCacheOperation("disposable_mx", "hit")
+		return entry.domains, true
+	}
+	monitoring.RecordCacheOperation("disposable_mx", "miss")
+
+	hosts, err := v.MXHosts(domain)
+	if IsInconclusiveDNSError(err) {
+		return nil, false
+	}
+	for _, host := range hosts {
+		if apex, err := publicsuffix.EffectiveTLDPlusOne(normalizeDomain(host)); err == nil && !slices.Contains(domains, apex) {
+			domains = append(domains, apex)
+		}
+	}
+
+	if ttl > 0 {
+		d.cacheMutex.Lock()
+		d.cache[domain] = mxDomainsEntry{domains: domains, timestamp: time.Now()}
+		for cached, entry := range d.cache {
+			if time.Since(entry.timestamp) > d.cacheTTL {
+				delete(d.cache, cached)
+			}
+		}
+		d.cacheMutex.Unlock()
+	}
+	return domains, true
+}
+
+// MXHosts looks up the hostnames of the domain's mail servers. It returns nil without a
+// lookup if the domain's TLD does not exist. A null MX and IP literals are left out.
+func (v *DomainValidator) MXHosts(domain string) ([]string, error) {
+	domain = TrimTrailingDot(domain)
+	if !v.HasKnownTLD(domain) {
+		return nil, nil
+	}
+
+	start := time.Now()
+	records, err := v.resolver.LookupMX(domain)
+	monitoring.RecordDNSLookup("mx", time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, 0, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Host, ".")
+		if host != "" && !IsIPLiteralMX(record.Host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sync/atomic"
+)
+
+const (
+	// MaxDisposablePatternLength is the longest disposable pattern accepted, in bytes
+	MaxDisposablePatternLength = 256
+	// maxDisposablePatternInsts bounds the size of a compiled disposable pattern. Counted
+	// repetitions multiply it, so "(a{100}){100}" is rejected though it is short.
+	maxDisposablePatternInsts = 2000
+)
+
+// DisposablePatterns matches domains against regular expressions, for disposable services
+// whose domains follow a pattern, such as "^\d+min(ute)?mail\." for 10minutemail.com,
+// 20minutemail.it and their kin. Patterns are matched anywhere in the lowercased, punycode
+// form of the domain, so anchor them to match a whole label.
+//
+// Go regular expressions run in time linear in the domain, so no pattern can backtrack
+// catastrophically; patterns are still bounded in length and compiled size, and a pattern
+// that matches every domain is rejected.
+type DisposablePatterns struct {
+	reader   DomainReader
+	patterns atomic.Pointer[[]*regexp.Regexp]
+}
+
+// NewDisposablePatterns creates DisposablePatterns from patterns
+func NewDisposablePatterns(patterns []string) (*DisposablePatterns, error) {
+	return NewDisposablePatternsWithReader(NewStaticDomainReader(patterns))
+}
+
+// NewDisposablePatternsFromFile creates DisposablePatterns with the patterns in a file, one
+// per line. Empty lines and lines starting with "#" are skipped.
+func NewDisposablePatternsFromFile(path string) (*DisposablePatterns, error) {
+	return NewDisposablePatternsWithReader(NewFileDomainReader(path))
+}
+
+// NewDisposablePatternsWithReader creates DisposablePatterns using a DomainReader.
+// Call Reload to pick up changes to the underlying source.
+func NewDisposablePatternsWithReader(reader DomainReader) (*DisposablePatterns, error) {
+	p := &DisposablePatterns{reader: reader}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseDisposablePatterns compiles disposable patterns, rejecting any that is longer than
+// MaxDisposablePatternLength, compiles too large or matches the empty string
+func ParseDisposablePatterns(lines []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(lines))
+	for _, line := range lines {
+		if len(line) > MaxDisposablePatternLength {
+			return nil, fmt.Errorf("invalid disposable pattern %q: longer than %d bytes", line, MaxDisposablePatternLength)
+		}
+		parsed, err := syntax.Parse(line, syntax.Perl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid disposable pattern %q: %w", line, err)
+		}
+		prog, err := syntax.Compile(parsed.Simplify())
+		if err != nil {
+			return nil, fmt.Errorf("invalid disposable pattern %q: %w", line, err)
+		}
+		if len(prog.Inst) > maxDisposablePatternInsts {
+			return nil, fmt.Errorf("invalid disposable pattern %q: too complex", line)
+		}
+		pattern, err := regexp.Compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid disposable pattern %q: %w", line, err)
+		}
+		if pattern.MatchString("") {
+			return nil, fmt.Errorf("invalid disposable pattern %q: matches every domain", line)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// Reload re-reads the patterns and atomically swaps them in. If the new content is empty
+// or contains an invalid pattern, the current patterns are kept.
+func (p *DisposablePatterns) Reload() error {
+	lines, err := p.reader.ReadDomains()
+	if err != nil {
+		return fmt.Errorf("failed to read disposable patterns: %w", err)
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("disposable pattern list is empty")
+	}
+	patterns, err := ParseDisposablePatterns(lines)
+	if err != nil {
+		return err
+	}
+	p.patterns.Store(&patterns)
+	return nil
+}
+
+// Patterns returns the patterns, in file order
+func (p *DisposablePatterns) Patterns() []string {
+	compiled := *p.patterns.Load()
+	patterns := make([]string, len(compiled))
+	for i, pattern := range compiled {
+		patterns[i] = pattern.String()
+	}
+	return patterns
+}
+
+// Match returns the first pattern that matches domain, or an empty string
+func (p *DisposablePatterns) Match(domain string) string {
+	domain = normalizeDomain(domain)
+	for _, pattern := range *p.patterns.Load() {
+		if pattern.MatchString(domain) {
+			return pattern.String()
+		}
+	}
+	return ""
+}
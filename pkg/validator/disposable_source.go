@@ -0,0 +1,76 @@
+package validator
+
+// DisposableSourceLocal names the local disposable list in a DisposableSource
+const DisposableSourceLocal = "local"
+
+// DisposableSourcePattern names the local disposable patterns in a BlocklistEntry
+const DisposableSourcePattern = "pattern"
+
+// DisposableSource is a disposable list entry that matches a domain, and the list it is on
+type DisposableSource struct {
+	// Source is DisposableSourceLocal for the local list, or the URL of a remote source
+	Source string
+	// Entry is the matching entry in canonical form: "tempmail.com" for an exact entry,
+	// "*.tempmail.com" for a wildcard and ".tempmail.com" for an entry covering subdomains,
+	// or the regular expression of a matching pattern
+	Entry string
+	// Match is how the domain matched the entry
+	Match MatchKind
+}
+
+// Sources returns the entries of the loaded list that match domain, each with the URL of
+// every source listing it, most specific entry first. The allowlist is not consulted, so
+// an allowlisted domain still reports the entries it overrides. ready is false until the
+// list has loaded.
+func (db *DisposableBlocklist) Sources(domain string) (sources []DisposableSource, ready bool) {
+	if !db.IsReady() {
+		return nil, false
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	for _, entry := range db.domains.Entries(domain) {
+		for _, i := range db.entrySources[entry] {
+			sources = append(sources, DisposableSource{Source: db.sources[i].URL, Entry: entry, Match: entryMatchKind(entry, domain)})
+		}
+	}
+	return sources, true
+}
+
+// Sources returns the entries of the local disposable list that match domain, most
+// specific first, followed by the first matching pattern. The allowlist is not consulted.
+func (v *DisposableValidator) Sources(domain string) []DisposableSource {
+	var sources []DisposableSource
+	for _, entry := range v.disposableDomains.Load().Entries(domain) {
+		sources = append(sources, DisposableSource{Source: DisposableSourceLocal, Entry: entry, Match: entryMatchKind(entry, domain)})
+	}
+	if v.patterns != nil {
+		if pattern := v.patterns.Match(domain); pattern != "" {
+			sources = append(sources, DisposableSource{Source: DisposableSourceLocal, Entry: pattern, Match: MatchPattern})
+		}
+	}
+	return sources
+}
+
+// AllowlistEntry returns the most specific allowlist entry matching domain, in canonical
+// form, or an empty string
+func (v *DisposableValidator) AllowlistEntry(domain string) string {
+	if v.allowlist == nil {
+		return ""
+	}
+	if entries := v.allowlist.Entries(domain); len(entries) > 0 {
+		return entries[0]
+	}
+	return ""
+}
+
+// DisposableSources returns the entries of the local disposable list that match domain
+func (v *EmailValidator) DisposableSources(domain string) []DisposableSource {
+	return v.disposableValidator.Sources(domain)
+}
+
+// DisposableAllowlistEntry returns the most specific disposable allowlist entry matching
+// domain, or an empty string
+func (v *EmailValidator) DisposableAllowlistEntry(domain string) string {
+	return v.disposableValidator.AllowlistEntry(domain)
+}
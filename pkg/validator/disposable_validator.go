@@ -1,21 +1,41 @@
 package validator
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 )
 
 // DisposableValidator handles disposable email validation
 type DisposableValidator struct {
-	disposableDomains map[string]struct{}
+	reader            DomainReader
+	disposableDomains atomic.Pointer[DomainMatcher]
+	allowlist         *DomainMatcher
+	patterns          *DisposablePatterns
+	strategy          MatchStrategy
 }
 
-// NewDisposableValidator creates a new instance of DisposableValidator using the config file
+// NewDisposableValidator creates a new instance of DisposableValidator using the bundled
+// list: config/disposable_domains.txt if it exists, or else the copy embedded in the binary
 func NewDisposableValidator() (*DisposableValidator, error) {
+	return NewDisposableValidatorWithReader(DefaultListReader(EmbeddedDisposableList))
+}
+
+// DefaultDisposableFile returns the path of config/disposable_domains.txt, searching upwards
+// from the working directory for the config directory
+func DefaultDisposableFile() (string, error) {
+	return configFile("disposable_domains.txt")
+}
+
+// configFile returns the path of the named file in the config directory, searching upwards
+// from the working directory for it
+func configFile(name string) (string, error) {
 	// Get the project root directory
 	projectRoot, err := os.Getwd()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	// Keep going up until we find the config directory or hit the root
@@ -25,37 +45,110 @@ func NewDisposableValidator() (*DisposableValidator, error) {
 		}
 		parent := filepath.Dir(projectRoot)
 		if parent == projectRoot {
-			return nil, err
+			return "", err
 		}
 		projectRoot = parent
 	}
 
-	reader := NewFileDomainReader(filepath.Join(projectRoot, "config", "disposable_domains.txt"))
-	return NewDisposableValidatorWithReader(reader)
+	return filepath.Join(projectRoot, "config", name), nil
 }
 
-// NewDisposableValidatorWithDomains creates a new instance of DisposableValidator with a custom list of domains
+// NewDisposableValidatorWithDomains creates a new instance of DisposableValidator with a custom list of domains.
+// Entries may be exact domains or wildcards like "*.example.com".
 func NewDisposableValidatorWithDomains(domains []string) *DisposableValidator {
-	disposableDomains := make(map[string]struct{}, len(domains))
-	for _, domain := range domains {
-		disposableDomains[domain] = struct{}{}
-	}
-	return &DisposableValidator{
-		disposableDomains: disposableDomains,
-	}
+	v := &DisposableValidator{reader: NewStaticDomainReader(domains)}
+	v.disposableDomains.Store(NewDomainMatcher(domains))
+	return v
 }
 
-// NewDisposableValidatorWithReader creates a new instance of DisposableValidator using a DomainReader
+// NewDisposableValidatorWithReader creates a new instance of DisposableValidator using a DomainReader.
+// Call Reload to pick up changes to the underlying source.
 func NewDisposableValidatorWithReader(reader DomainReader) (*DisposableValidator, error) {
-	domains, err := reader.ReadDomains()
-	if err != nil {
+	v := &DisposableValidator{reader: reader}
+	if err := v.Reload(); err != nil {
 		return nil, err
 	}
-	return NewDisposableValidatorWithDomains(domains), nil
+	return v, nil
+}
+
+// Reload re-reads the disposable domain list and atomically swaps it in, then the patterns
+// if any are set. If the new content is empty or contains an entry that is not a domain
+// (e.g. a partially written file), the current list is kept.
+func (v *DisposableValidator) Reload() error {
+	domains, err := v.reader.ReadDomains()
+	if err != nil {
+		return fmt.Errorf("failed to read disposable domains: %w", err)
+	}
+	if len(domains) == 0 {
+		return fmt.Errorf("disposable domain list is empty")
+	}
+	for _, domain := range domains {
+		if !isValidListDomain(domain) {
+			return fmt.Errorf("invalid disposable domain list entry %q", domain)
+		}
+	}
+
+	v.disposableDomains.Store(NewDomainMatcherWithStrategy(domains, v.strategy))
+	if v.patterns != nil {
+		return v.patterns.Reload()
+	}
+	return nil
+}
+
+// SetMatchStrategy sets whether entries of the disposable list cover subdomains, and
+// reloads the list to apply it. The allowlist is not affected.
+func (v *DisposableValidator) SetMatchStrategy(strategy MatchStrategy) error {
+	v.strategy = strategy
+	return v.Reload()
+}
+
+// SetAllowlist sets domains that are never considered disposable, even if they match the blocklist.
+// Entries may be exact domains or wildcards like "*.example.com".
+func (v *DisposableValidator) SetAllowlist(domains []string) {
+	v.allowlist = NewDomainMatcher(domains)
+}
+
+// SetPatterns sets regular expressions flagging domains the list does not name; nil, the
+// default, clears them. Like the allowlist, they belong to the validator.
+func (v *DisposableValidator) SetPatterns(patterns *DisposablePatterns) {
+	v.patterns = patterns
 }
 
 // Validate checks if the email domain is from a disposable email provider
 func (v *DisposableValidator) Validate(domain string) bool {
-	_, exists := v.disposableDomains[domain]
-	return exists
+	blocklist := v.disposableDomains.Load()
+	if blocklist.Contains(domain) {
+		return isBlocked(domain, blocklist, v.allowlist)
+	}
+	// The patterns are only tried once the list misses, and any allowlist entry beats them
+	return v.patterns != nil && v.patterns.Match(domain) != "" && !v.IsAllowlisted(domain)
+}
+
+// IsAllowlisted reports whether the domain matches the allowlist
+func (v *DisposableValidator) IsAllowlisted(domain string) bool {
+	return v.allowlist != nil && v.allowlist.Contains(domain)
+}
+
+// isValidListDomain reports whether entry is a domain, a "*." wildcard domain or a domain
+// annotated with a leading dot
+func isValidListDomain(entry string) bool {
+	if strings.HasPrefix(entry, wildcardPrefix) {
+		entry = entry[len(wildcardPrefix):]
+	} else {
+		entry = strings.TrimPrefix(entry, suffixPrefix)
+	}
+	if entry == "" || len(entry) > maxDomainLength {
+		return false
+	}
+	for _, label := range strings.Split(entry, ".") {
+		if label == "" || len(label) > maxLabelLength {
+			return false
+		}
+		for _, c := range label {
+			if !(c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c > 127) {
+				return false
+			}
+		}
+	}
+	return true
 }
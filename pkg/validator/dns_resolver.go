@@ -1,67 +1,262 @@
 package validator
 
 import (
+	"context"
+	"errors"
 	"net"
+	"sync/atomic"
 	"time"
+
+	"emailvalidator/pkg/monitoring"
 )
 
+// DefaultDNSTimeout is how long DefaultResolver lookups may take unless configured otherwise
+const DefaultDNSTimeout = 2 * time.Second
+
+// DefaultMaxMXRecords is how many MX records the server considers per domain unless configured otherwise
+const DefaultMaxMXRecords = 10
+
+// ErrDNSTimeout is returned when a DNS lookup does not complete within the resolver timeout
+var ErrDNSTimeout = errors.New("dns lookup timed out")
+
+// ErrDNSTruncated is reported when a DNS answer over UDP is truncated and the resolver is
+// not allowed to retry the query over TCP
+var ErrDNSTruncated = errors.New("dns response truncated")
+
 // DNSResolver interface for making DNS lookups configurable and mockable
 type DNSResolver interface {
 	LookupHost(domain string) ([]string, error)
 	LookupMX(domain string) ([]*net.MX, error)
 }
 
+// TXTResolver is optionally implemented by resolvers that can look up TXT records
+type TXTResolver interface {
+	LookupTXT(domain string) ([]string, error)
+}
+
+// NSResolver is optionally implemented by resolvers that can look up NS records
+type NSResolver interface {
+	LookupNS(domain string) ([]*net.NS, error)
+}
+
 // DefaultResolver implements DNSResolver using net package
 type DefaultResolver struct {
-	timeout time.Duration
+	timeout     time.Duration
+	resolver    *net.Resolver
+	slots       atomic.Pointer[chan struct{}]
+	maxMX       int
+	tcpFallback bool
+	// udp and tcp query over that transport only; both are nil until SetTCPFallback is
+	// called, leaving truncated answers to the underlying resolver
+	udp, tcp *net.Resolver
+}
+
+// NewDefaultResolver creates a DefaultResolver whose lookups time out after timeout
+func NewDefaultResolver(timeout time.Duration) *DefaultResolver {
+	return &DefaultResolver{timeout: timeout}
+}
+
+// SetNetResolver sets the resolver lookups go through, e.g. one that queries a specific
+// DNS server. nil, the default, uses the system resolver.
+func (r *DefaultResolver) SetNetResolver(resolver *net.Resolver) {
+	r.resolver = resolver
+	if r.udp != nil {
+		r.udp, r.tcp = transportResolver(r.netResolver(), "udp"), transportResolver(r.netResolver(), "tcp")
+	}
+}
+
+// SetTCPFallback makes truncated UDP answers explicit. Once called, lookups go through the
+// Go resolver over UDP; when an answer comes back truncated, as one listing many MX records
+// may, the query is sent again over TCP (RFC 7766) if enabled, and otherwise fails with a
+// DNSError for ErrDNSTruncated, which IsInconclusiveDNSError reports as inconclusive.
+// Before it is called, truncated answers are left to the underlying resolver.
+func (r *DefaultResolver) SetTCPFallback(enabled bool) {
+	r.tcpFallback = enabled
+	r.udp, r.tcp = transportResolver(r.netResolver(), "udp"), transportResolver(r.netResolver(), "tcp")
+}
+
+// SetMaxMXRecords bounds how many MX records LookupMX returns, keeping the most preferred.
+// Zero, the default, returns them all.
+func (r *DefaultResolver) SetMaxMXRecords(limit int) {
+	r.maxMX = max(limit, 0)
+}
+
+// SetConcurrencyLimit caps the number of lookups in flight at once; further lookups wait
+// for a free slot, and give up with ErrDNSTimeout if none frees up within the timeout.
+// Zero, the default, leaves lookups unlimited.
+func (r *DefaultResolver) SetConcurrencyLimit(limit int) {
+	if limit <= 0 {
+		r.slots.Store(nil)
+		return
+	}
+	slots := make(chan struct{}, limit)
+	r.slots.Store(&slots)
+}
+
+// netResolver returns the resolver lookups go through
+func (r *DefaultResolver) netResolver() *net.Resolver {
+	if r.resolver == nil {
+		return net.DefaultResolver
+	}
+	return r.resolver
+}
+
+// transportResolver returns a Go resolver that sends queries the way base does, but over
+// network only. Over "udp", a truncated answer fails with ErrDNSTruncated where the Go
+// resolver would otherwise retry it over TCP on its own.
+func transportResolver(base *net.Resolver, network string) *net.Resolver {
+	dial := base.Dial
+	if dial == nil {
+		var dialer net.Dialer
+		dial = dialer.DialContext
+	}
+	return &net.Resolver{
+		PreferGo:     true,
+		StrictErrors: base.StrictErrors,
+		Dial: func(ctx context.Context, requested, address string) (net.Conn, error) {
+			if network == "udp" && requested == "tcp" {
+				return nil, ErrDNSTruncated
+			}
+			return dial(ctx, network, address)
+		},
+	}
+}
+
+// query runs fn against the resolver lookups go through. With SetTCPFallback enabled, a
+// query whose UDP answer is truncated is run again over TCP.
+func query[T any](r *DefaultResolver, fn func(resolver *net.Resolver) (T, error)) (T, error) {
+	if r.udp == nil {
+		return fn(r.netResolver())
+	}
+	value, err := fn(r.udp)
+	if r.tcpFallback && IsTruncatedDNSError(err) {
+		return fn(r.tcp)
+	}
+	return value, err
 }
 
 // LookupHost performs a DNS lookup for the given domain and returns a list of IP addresses.
 // It uses the system's default DNS resolver with the configured timeout.
 func (r *DefaultResolver) LookupHost(domain string) ([]string, error) {
-	resultChan := make(chan []string, 1)
-	errChan := make(chan error, 1)
+	return lookup(r, func(ctx context.Context) ([]string, error) {
+		return query(r, func(resolver *net.Resolver) ([]string, error) {
+			return resolver.LookupHost(ctx, domain)
+		})
+	})
+}
+
+// LookupMX performs a DNS lookup for MX records of the given domain.
+// It returns a list of mail servers responsible for handling email for the domain, in
+// order of preference and at most SetMaxMXRecords of them.
+func (r *DefaultResolver) LookupMX(domain string) ([]*net.MX, error) {
+	records, err := lookup(r, func(ctx context.Context) ([]*net.MX, error) {
+		return query(r, func(resolver *net.Resolver) ([]*net.MX, error) {
+			return resolver.LookupMX(ctx, domain)
+		})
+	})
+	// net.Resolver returns records sorted by preference
+	if r.maxMX > 0 && len(records) > r.maxMX {
+		records = records[:r.maxMX]
+	}
+	return records, err
+}
+
+// LookupTXT performs a DNS lookup for TXT records of the given domain
+func (r *DefaultResolver) LookupTXT(domain string) ([]string, error) {
+	return lookup(r, func(ctx context.Context) ([]string, error) {
+		return query(r, func(resolver *net.Resolver) ([]string, error) {
+			return resolver.LookupTXT(ctx, domain)
+		})
+	})
+}
+
+// LookupNS performs a DNS lookup for the nameservers of the given domain
+func (r *DefaultResolver) LookupNS(domain string) ([]*net.NS, error) {
+	return lookup(r, func(ctx context.Context) ([]*net.NS, error) {
+		return query(r, func(resolver *net.Resolver) ([]*net.NS, error) {
+			return resolver.LookupNS(ctx, domain)
+		})
+	})
+}
+
+// lookup runs fn in the background and returns its answer, or ErrDNSTimeout if the
+// resolver timeout passes first. With a concurrency limit, waiting for a free slot counts
+// against the timeout, and the slot is held until fn returns, even after a timeout, so the
+// limit bounds the queries actually in flight.
+func lookup[T any](r *DefaultResolver, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	slots := r.slots.Load()
+	if slots != nil {
+		monitoring.DNSLookupQueued()
+		select {
+		case *slots <- struct{}{}:
+			monitoring.DNSLookupDequeued()
+		case <-ctx.Done():
+			monitoring.DNSLookupDequeued()
+			return zero, ErrDNSTimeout
+		}
+	}
 
+	type answer struct {
+		value T
+		err   error
+	}
+	answers := make(chan answer, 1)
 	go func() {
-		addrs, err := net.LookupHost(domain)
-		if err != nil {
-			errChan <- err
-			return
+		value, err := fn(ctx)
+		if slots != nil {
+			<-*slots
 		}
-		resultChan <- addrs
+		answers <- answer{value, err}
 	}()
 
 	select {
-	case addrs := <-resultChan:
-		return addrs, nil
-	case err := <-errChan:
-		return nil, err
-	case <-time.After(r.timeout):
-		return nil, net.ErrClosed
+	case a := <-answers:
+		// fn gets ctx, so the resolver's own timeout error can arrive before ctx.Done
+		if a.err != nil && (ctx.Err() != nil || isDNSTimeout(a.err)) {
+			return zero, ErrDNSTimeout
+		}
+		return a.value, a.err
+	case <-ctx.Done():
+		return zero, ErrDNSTimeout
 	}
 }
 
-// LookupMX performs a DNS lookup for MX records of the given domain.
-// It returns a list of mail servers responsible for handling email for the domain.
-func (r *DefaultResolver) LookupMX(domain string) ([]*net.MX, error) {
-	resultChan := make(chan []*net.MX, 1)
-	errChan := make(chan error, 1)
+// isDNSTimeout reports whether err is a *net.DNSError for a query that timed out
+func isDNSTimeout(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsTimeout
+}
 
-	go func() {
-		mxs, err := net.LookupMX(domain)
-		if err != nil {
-			errChan <- err
-			return
-		}
-		resultChan <- mxs
-	}()
+// IsInconclusiveDNSError reports whether a lookup error means the answer is unknown
+// (timeout or temporary resolver failure) rather than a definitive negative answer
+func IsInconclusiveDNSError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrDNSTimeout) {
+		return true
+	}
+	if IsTruncatedDNSError(err) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	return false
+}
 
-	select {
-	case mxs := <-resultChan:
-		return mxs, nil
-	case err := <-errChan:
-		return nil, err
-	case <-time.After(r.timeout):
-		return nil, net.ErrClosed
+// IsTruncatedDNSError reports whether a lookup failed because its UDP answer was truncated
+// and could not be retried over TCP
+func IsTruncatedDNSError(err error) bool {
+	if errors.Is(err, ErrDNSTruncated) {
+		return true
 	}
+	// net.DNSError keeps only the text of the error behind it
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.Err == ErrDNSTruncated.Error()
 }
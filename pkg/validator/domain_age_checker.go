@@ -0,0 +1,214 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// ianaRDAPBootstrapURL is the IANA registry of RDAP servers per top-level domain (RFC 9224)
+const ianaRDAPBootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+var (
+	// ErrNoRDAPServer is returned when no RDAP server is registered for the domain's TLD
+	ErrNoRDAPServer = errors.New("no RDAP server for top-level domain")
+	// ErrNoRegistrationDate is returned when the RDAP record has no registration event
+	ErrNoRegistrationDate = errors.New("RDAP record has no registration date")
+)
+
+// DomainAge describes when a domain was registered
+type DomainAge struct {
+	Registered time.Time
+	Days       int  // Whole days since registration
+	IsNew      bool // Registered fewer than the checker's threshold days ago
+}
+
+// domainAgeEntry is a cached registration date lookup
+type domainAgeEntry struct {
+	registered time.Time
+	err        error
+	fetched    time.Time
+}
+
+// DomainAgeChecker looks up domain registration dates over RDAP, using the IANA bootstrap
+// registry to find each TLD's RDAP server. RDAP servers are slow and rate-limited, so
+// lookups (including "not found" answers) are cached per registrable domain.
+type DomainAgeChecker struct {
+	client        *http.Client
+	bootstrapURL  string
+	newDomainDays int
+	cacheDuration time.Duration
+
+	bootstrapMu sync.Mutex
+	servers     map[string][]string // TLD -> RDAP base URLs
+
+	cacheMu sync.RWMutex
+	cache   map[string]domainAgeEntry
+}
+
+// NewDomainAgeChecker creates a new DomainAgeChecker that flags domains registered fewer
+// than newDomainDays days ago, using the default HTTP client
+func NewDomainAgeChecker(newDomainDays int) *DomainAgeChecker {
+	return NewDomainAgeCheckerWithClient(DefaultHTTPClient(), newDomainDays)
+}
+
+// NewDomainAgeCheckerWithClient creates a new DomainAgeChecker that makes its bootstrap and
+// RDAP requests with client
+func NewDomainAgeCheckerWithClient(client *http.Client, newDomainDays int) *DomainAgeChecker {
+	return &DomainAgeChecker{
+		client:        client,
+		bootstrapURL:  ianaRDAPBootstrapURL,
+		newDomainDays: newDomainDays,
+		cacheDuration: 24 * time.Hour,
+		cache:         make(map[string]domainAgeEntry),
+	}
+}
+
+// SetBootstrapURL sets the URL of the RDAP bootstrap registry
+func (c *DomainAgeChecker) SetBootstrapURL(url string) {
+	c.bootstrapMu.Lock()
+	c.bootstrapURL = url
+	c.servers = nil
+	c.bootstrapMu.Unlock()
+}
+
+// SetHTTPClient sets the client used for bootstrap and RDAP requests
+func (c *DomainAgeChecker) SetHTTPClient(client *http.Client) {
+	c.client = client
+}
+
+// SetCacheDuration sets how long registration dates are cached
+func (c *DomainAgeChecker) SetCacheDuration(duration time.Duration) {
+	c.cacheDuration = duration
+}
+
+// Check returns the registration age of domain. Subdomains are looked up by their
+// registrable domain (mail.example.co.uk -> example.co.uk).
+func (c *DomainAgeChecker) Check(domain string) (DomainAge, error) {
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(strings.TrimSuffix(strings.ToLower(domain), "."))
+	if err != nil {
+		return DomainAge{}, err
+	}
+
+	registered, err := c.registrationDate(registrable)
+	if err != nil {
+		return DomainAge{}, err
+	}
+
+	days := int(time.Since(registered).Hours() / 24)
+	return DomainAge{
+		Registered: registered,
+		Days:       days,
+		IsNew:      days < c.newDomainDays,
+	}, nil
+}
+
+// registrationDate returns the cached registration date of domain, fetching it if needed.
+// Successful lookups and definitive negative answers are cached; transient errors are not.
+func (c *DomainAgeChecker) registrationDate(domain string) (time.Time, error) {
+	c.cacheMu.RLock()
+	entry, ok := c.cache[domain]
+	c.cacheMu.RUnlock()
+	if ok && time.Since(entry.fetched) < c.cacheDuration {
+		return entry.registered, entry.err
+	}
+
+	registered, err := c.fetchRegistrationDate(domain)
+	if err == nil || errors.Is(err, ErrNoRDAPServer) || errors.Is(err, ErrNoRegistrationDate) {
+		c.cacheMu.Lock()
+		c.cache[domain] = domainAgeEntry{registered: registered, err: err, fetched: time.Now()}
+		c.cacheMu.Unlock()
+	}
+	return registered, err
+}
+
+// rdapDomain is the subset of an RDAP domain response (RFC 9083) used here
+type rdapDomain struct {
+	Events []struct {
+		Action string    `json:"eventAction"`
+		Date   time.Time `json:"eventDate"`
+	} `json:"events"`
+}
+
+func (c *DomainAgeChecker) fetchRegistrationDate(domain string) (time.Time, error) {
+	servers, err := c.rdapServers(domain[strings.LastIndex(domain, ".")+1:])
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var lastErr error
+	for _, server := range servers {
+		url := strings.TrimSuffix(server, "/") + "/domain/" + domain
+		var record rdapDomain
+		if lastErr = c.getJSON(url, &record); lastErr != nil {
+			continue
+		}
+		for _, event := range record.Events {
+			if event.Action == "registration" {
+				return event.Date, nil
+			}
+		}
+		return time.Time{}, ErrNoRegistrationDate
+	}
+	return time.Time{}, lastErr
+}
+
+// rdapServers returns the RDAP base URLs for tld, loading the bootstrap registry on first use
+func (c *DomainAgeChecker) rdapServers(tld string) ([]string, error) {
+	c.bootstrapMu.Lock()
+	defer c.bootstrapMu.Unlock()
+
+	if c.servers == nil {
+		var registry struct {
+			Services [][][]string `json:"services"`
+		}
+		if err := c.getJSON(c.bootstrapURL, &registry); err != nil {
+			return nil, fmt.Errorf("failed to load RDAP bootstrap registry: %w", err)
+		}
+
+		servers := make(map[string][]string)
+		for _, service := range registry.Services {
+			if len(service) != 2 {
+				continue
+			}
+			for _, t := range service[0] {
+				servers[strings.ToLower(t)] = service[1]
+			}
+		}
+		c.servers = servers
+	}
+
+	servers, ok := c.servers[tld]
+	if !ok || len(servers) == 0 {
+		return nil, fmt.Errorf("%w %q", ErrNoRDAPServer, tld)
+	}
+	return servers, nil
+}
+
+func (c *DomainAgeChecker) getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s, status code: %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return nil
+}
@@ -28,21 +28,29 @@ func NewDomainCacheManager(duration time.Duration) *DomainCacheManager {
 
 // Get retrieves a cached domain validation result
 func (m *DomainCacheManager) Get(domain string) (bool, bool) {
+	exists, _, ok := m.GetWithAge(domain)
+	return exists, ok
+}
+
+// GetWithAge retrieves a cached domain validation result along with how long ago it was
+// looked up
+func (m *DomainCacheManager) GetWithAge(domain string) (exists bool, age time.Duration, ok bool) {
 	m.cacheMutex.RLock()
 	cache, ok := m.cache[domain]
 	if !ok {
 		m.cacheMutex.RUnlock()
-		return false, false
+		return false, 0, false
 	}
 
 	// Check expiration without allocating time.Time
-	if time.Since(cache.timestamp) > m.cacheDuration {
+	age = time.Since(cache.timestamp)
+	if age > m.cacheDuration {
 		m.cacheMutex.RUnlock()
-		return false, false
+		return false, 0, false
 	}
 
 	m.cacheMutex.RUnlock()
-	return cache.exists, true
+	return cache.exists, age, true
 }
 
 // Set stores a domain validation result in the cache
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// MXRecord is a mail server published for a domain
+type MXRecord struct {
+	Host string
+	Pref uint16
+}
+
+// DomainInspection holds the raw DNS records behind a domain's validation
+type DomainInspection struct {
+	CacheHit  bool          // The domain's existence check was answered from the cache
+	CacheAge  time.Duration // How long ago the cached existence check was made; zero unless CacheHit
+	MXRecords []MXRecord    // Published mail servers, ordered by preference
+	MXError   string        // Error from the MX lookup, if any
+	SPF       string        // The domain's SPF record; empty if none was found
+	DMARC     string        // The domain's DMARC record; empty if none was found
+}
+
+// Inspect looks up the raw DNS records for domain without touching the cache.
+// SPF and DMARC are only looked up when the resolver supports TXT lookups.
+func (v *DomainValidator) Inspect(domain string) DomainInspection {
+	var inspection DomainInspection
+	domain = TrimTrailingDot(domain)
+	_, inspection.CacheAge, inspection.CacheHit = v.cacheManager.GetWithAge(domain)
+
+	mxRecords, err := v.resolver.LookupMX(domain)
+	if err != nil {
+		inspection.MXError = err.Error()
+	}
+	for _, mx := range mxRecords {
+		inspection.MXRecords = append(inspection.MXRecords, MXRecord{Host: mx.Host, Pref: mx.Pref})
+	}
+	sort.SliceStable(inspection.MXRecords, func(i, j int) bool {
+		return inspection.MXRecords[i].Pref < inspection.MXRecords[j].Pref
+	})
+
+	if txt, ok := v.resolver.(TXTResolver); ok {
+		inspection.SPF = findTXTRecord(txt, domain, "v=spf1")
+		inspection.DMARC = findTXTRecord(txt, "_dmarc."+domain, "v=DMARC1")
+	}
+	return inspection
+}
+
+// findTXTRecord returns the first TXT record at name that starts with prefix
+func findTXTRecord(resolver TXTResolver, name, prefix string) string {
+	records, err := resolver.LookupTXT(name)
+	if err != nil {
+		return ""
+	}
+	for _, record := range records {
+		if len(record) >= len(prefix) && strings.EqualFold(record[:len(prefix)], prefix) {
+			return record
+		}
+	}
+	return ""
+}
+
+// CacheAge reports whether the existence of domain would be answered from the cache and,
+// if so, how long ago it was looked up. It makes no lookups.
+func (v *DomainValidator) CacheAge(domain string) (age time.Duration, cached bool) {
+	_, age, cached = v.cacheManager.GetWithAge(TrimTrailingDot(domain))
+	return age, cached
+}
@@ -0,0 +1,292 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// MatchKind describes how a domain matched a DomainMatcher
+type MatchKind int
+
+const (
+	// MatchNone means the domain did not match any entry
+	MatchNone MatchKind = iota
+	// MatchPattern means the domain matched a regular expression of DisposablePatterns,
+	// the weakest kind of match; a DomainMatcher never reports it
+	MatchPattern
+	// MatchWildcard means the domain matched a "*.example.com" entry, or is a subdomain of
+	// an entry that covers its subdomains under the matcher's MatchStrategy
+	MatchWildcard
+	// MatchExact means the domain matched an exact entry
+	MatchExact
+)
+
+// MatchStrategy selects whether a plain list entry, such as "example.com", also covers
+// the subdomains of the domain it names. Wildcard entries behave the same under every strategy.
+type MatchStrategy string
+
+// Supported match strategies
+const (
+	// MatchStrategyExact matches a plain entry against the domain itself only. This is the default.
+	MatchStrategyExact MatchStrategy = "exact"
+	// MatchStrategySuffix matches a plain entry against the domain and every subdomain of it
+	MatchStrategySuffix MatchStrategy = "suffix"
+	// MatchStrategyAnnotated decides per entry: a plain entry matches the domain itself only,
+	// and an entry written with a leading dot, such as ".example.com", matches the domain
+	// and every subdomain of it
+	MatchStrategyAnnotated MatchStrategy = "annotated"
+)
+
+// ParseMatchStrategy converts a configuration string into a MatchStrategy
+func ParseMatchStrategy(value string) (MatchStrategy, error) {
+	switch MatchStrategy(strings.ToLower(value)) {
+	case "", MatchStrategyExact:
+		return MatchStrategyExact, nil
+	case MatchStrategySuffix:
+		return MatchStrategySuffix, nil
+	case MatchStrategyAnnotated:
+		return MatchStrategyAnnotated, nil
+	default:
+		return "", fmt.Errorf("match strategy %q: must be %q, %q or %q",
+			value, MatchStrategyExact, MatchStrategySuffix, MatchStrategyAnnotated)
+	}
+}
+
+const (
+	wildcardPrefix = "*."
+	// suffixPrefix marks an entry that covers its subdomains under MatchStrategyAnnotated
+	suffixPrefix = "."
+)
+
+// DomainMatcher matches domains against exact entries and wildcard entries.
+// A wildcard entry like "*.example.com" covers every subdomain of example.com
+// at any depth, but not example.com itself. Depending on the MatchStrategy, plain
+// entries may also cover their subdomains. Entries and domains are compared in
+// punycode form, so an internationalized domain matches in Unicode or "xn--" form.
+type DomainMatcher struct {
+	exact     map[string]struct{}
+	wildcards map[string]struct{}
+	// suffixes are entries that match the domain itself and every subdomain of it
+	suffixes map[string]struct{}
+}
+
+// NewDomainMatcher creates a DomainMatcher from a list of exact and wildcard entries
+func NewDomainMatcher(entries []string) *DomainMatcher {
+	return NewDomainMatcherWithStrategy(entries, MatchStrategyExact)
+}
+
+// NewDomainMatcherWithStrategy creates a DomainMatcher whose plain entries follow strategy.
+// Outside MatchStrategyAnnotated, a leading dot on an entry is ignored.
+func NewDomainMatcherWithStrategy(entries []string, strategy MatchStrategy) *DomainMatcher {
+	m := &DomainMatcher{
+		exact:     make(map[string]struct{}, len(entries)),
+		wildcards: make(map[string]struct{}),
+		suffixes:  make(map[string]struct{}),
+	}
+	for _, entry := range entries {
+		if entry = canonicalEntry(entry, strategy); entry != "" {
+			m.add(entry)
+		}
+	}
+	return m
+}
+
+// canonicalEntry returns entry in the form it takes under strategy, normalized and written
+// as MatchStrategyAnnotated reads it: "*.example.com" for a wildcard, ".example.com" for
+// an entry covering subdomains and "example.com" for an exact one. Entries that read the
+// same under strategy share a canonical form. A blank entry is returned empty.
+func canonicalEntry(entry string, strategy MatchStrategy) string {
+	entry = strings.ToLower(strings.TrimSpace(entry))
+	if entry == "" {
+		return ""
+	}
+	if strings.HasPrefix(entry, wildcardPrefix) {
+		return wildcardPrefix + normalizeDomain(strings.TrimPrefix(entry, wildcardPrefix))
+	}
+	annotated := strings.HasPrefix(entry, suffixPrefix)
+	entry = normalizeDomain(strings.TrimPrefix(entry, suffixPrefix))
+	if strategy == MatchStrategySuffix || strategy == MatchStrategyAnnotated && annotated {
+		return suffixPrefix + entry
+	}
+	return entry
+}
+
+// entrySet returns the set a canonical entry belongs to and its key in it
+func (m *DomainMatcher) entrySet(entry string) (map[string]struct{}, string) {
+	switch {
+	case strings.HasPrefix(entry, wildcardPrefix):
+		return m.wildcards, strings.TrimPrefix(entry, wildcardPrefix)
+	case strings.HasPrefix(entry, suffixPrefix):
+		return m.suffixes, strings.TrimPrefix(entry, suffixPrefix)
+	default:
+		return m.exact, entry
+	}
+}
+
+// add adds a canonical entry. The matcher is not safe for concurrent use while entries
+// are added or removed; callers sharing it must hold a lock.
+func (m *DomainMatcher) add(entry string) {
+	set, key := m.entrySet(entry)
+	set[key] = struct{}{}
+}
+
+// remove removes a canonical entry, if present
+func (m *DomainMatcher) remove(entry string) {
+	set, key := m.entrySet(entry)
+	delete(set, key)
+}
+
+// Match reports how the domain matches the entries. Exact entries are checked first,
+// then the domain's parent suffixes are walked up, one label at a time, against the
+// entries that cover subdomains, so the cost depends on the domain's depth, not the
+// size of the list.
+func (m *DomainMatcher) Match(domain string) MatchKind {
+	domain = normalizeDomain(domain)
+	if _, ok := m.exact[domain]; ok {
+		return MatchExact
+	}
+	if _, ok := m.suffixes[domain]; ok {
+		return MatchExact
+	}
+	if len(m.wildcards) == 0 && len(m.suffixes) == 0 {
+		return MatchNone
+	}
+	for i := strings.IndexByte(domain, '.'); i != -1; i = strings.IndexByte(domain, '.') {
+		domain = domain[i+1:]
+		if _, ok := m.wildcards[domain]; ok {
+			return MatchWildcard
+		}
+		if _, ok := m.suffixes[domain]; ok {
+			return MatchWildcard
+		}
+	}
+	return MatchNone
+}
+
+// Entries returns every entry the domain matches, in the canonical form of canonicalEntry,
+// from the most specific to the least: an entry for the domain itself first, then entries
+// covering its parents from the nearest up
+func (m *DomainMatcher) Entries(domain string) []string {
+	domain = normalizeDomain(domain)
+	var entries []string
+	if _, ok := m.exact[domain]; ok {
+		entries = append(entries, domain)
+	}
+	if _, ok := m.suffixes[domain]; ok {
+		entries = append(entries, suffixPrefix+domain)
+	}
+	for i := strings.IndexByte(domain, '.'); i != -1; i = strings.IndexByte(domain, '.') {
+		domain = domain[i+1:]
+		if _, ok := m.wildcards[domain]; ok {
+			entries = append(entries, wildcardPrefix+domain)
+		}
+		if _, ok := m.suffixes[domain]; ok {
+			entries = append(entries, suffixPrefix+domain)
+		}
+	}
+	return entries
+}
+
+// entryMatchKind reports how domain matches entry, one of the canonical entries returned
+// by Entries for it
+func entryMatchKind(entry, domain string) MatchKind {
+	if strings.TrimPrefix(entry, suffixPrefix) == normalizeDomain(domain) {
+		return MatchExact
+	}
+	return MatchWildcard
+}
+
+// String returns "exact", "wildcard", "pattern" or "none"
+func (k MatchKind) String() string {
+	switch k {
+	case MatchPattern:
+		return "pattern"
+	case MatchExact:
+		return "exact"
+	case MatchWildcard:
+		return "wildcard"
+	default:
+		return "none"
+	}
+}
+
+// canonicalEntries returns every entry in the canonical form of canonicalEntry, unsorted
+func (m *DomainMatcher) canonicalEntries() []string {
+	entries := make([]string, 0, m.Len())
+	for entry := range m.exact {
+		entries = append(entries, entry)
+	}
+	for entry := range m.wildcards {
+		entries = append(entries, wildcardPrefix+entry)
+	}
+	for entry := range m.suffixes {
+		entries = append(entries, suffixPrefix+entry)
+	}
+	return entries
+}
+
+// allowlistOverrides reports whether allowlist overrides a canonical blocklist entry for
+// every domain it covers, as isBlocked decides, so the entry never makes a domain disposable
+func allowlistOverrides(entry string, allowlist *DomainMatcher) bool {
+	if allowlist == nil {
+		return false
+	}
+	// A "*" label matches no exact entry, so only entries covering every subdomain match it
+	coversSubdomains := func(domain string) bool {
+		return allowlist.Match(wildcardPrefix+domain) >= MatchWildcard
+	}
+	switch {
+	case strings.HasPrefix(entry, wildcardPrefix):
+		return coversSubdomains(strings.TrimPrefix(entry, wildcardPrefix))
+	case strings.HasPrefix(entry, suffixPrefix):
+		domain := strings.TrimPrefix(entry, suffixPrefix)
+		return allowlist.Match(domain) == MatchExact && coversSubdomains(domain)
+	default:
+		return allowlist.Match(entry) == MatchExact
+	}
+}
+
+// Contains reports whether the domain matches any entry
+func (m *DomainMatcher) Contains(domain string) bool {
+	return m.Match(domain) != MatchNone
+}
+
+// Len returns the number of entries in the matcher
+func (m *DomainMatcher) Len() int {
+	return len(m.exact) + len(m.wildcards) + len(m.suffixes)
+}
+
+// NormalizeDomain returns domain in the form used for DNS lookups and list matching:
+// lowercased, without a trailing dot, and with internationalized labels in punycode
+func NormalizeDomain(domain string) string {
+	return normalizeDomain(domain)
+}
+
+// normalizeDomain lowercases domain, removes its trailing dot and converts internationalized
+// labels to punycode. A domain that cannot be converted is returned lowercased.
+func normalizeDomain(domain string) string {
+	domain = TrimTrailingDot(strings.ToLower(domain))
+	for i := 0; i < len(domain); i++ {
+		if domain[i] >= utf8.RuneSelf {
+			if ascii, err := idna.ToASCII(domain); err == nil {
+				return ascii
+			}
+			return domain
+		}
+	}
+	return domain
+}
+
+// isBlocked resolves a domain against a blocklist and an allowlist. An exact entry
+// always beats a wildcard entry; when both lists match with the same kind, the
+// allowlist wins.
+func isBlocked(domain string, blocklist, allowlist *DomainMatcher) bool {
+	blocked := blocklist.Match(domain)
+	if blocked == MatchNone || allowlist == nil {
+		return blocked != MatchNone
+	}
+	return blocked > allowlist.Match(domain)
+}
@@ -1,15 +1,24 @@
 package validator
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"emailvalidator/pkg/monitoring"
+
+	"golang.org/x/net/publicsuffix"
 )
 
 // DomainValidator handles domain existence validation
 type DomainValidator struct {
 	resolver     DNSResolver
 	cacheManager *DomainCacheManager
+	tlds         *TLDList
+	mxWalkUp     int
+	allowDotless bool
 }
 
 // NewDomainValidator creates a new instance of DomainValidator
@@ -20,12 +29,104 @@ func NewDomainValidator(resolver DNSResolver, cacheManager *DomainCacheManager)
 	}
 }
 
+// SetTLDList sets the list of existing TLDs. Domains under any other TLD fail validation
+// without a DNS lookup. A nil list disables the check.
+func (v *DomainValidator) SetTLDList(tlds *TLDList) {
+	v.tlds = tlds
+}
+
+// SetMXWalkUp makes the MX check try up to levels parent domains when a domain has no MX
+// records, so user@sub.example.com is accepted when only example.com publishes mail
+// servers. Zero, the default, checks the exact domain only.
+func (v *DomainValidator) SetMXWalkUp(levels int) {
+	v.mxWalkUp = levels
+}
+
+// SetAllowDotlessDomains makes single-label domains such as "user@intranet" acceptable, for
+// deployments whose DNS resolves internal hostnames. They skip the TLD check and are looked
+// up like any other domain, so the resolver's search domains apply. Off by default, when
+// they fail the TLD check.
+func (v *DomainValidator) SetAllowDotlessDomains(allow bool) {
+	v.allowDotless = allow
+}
+
+// HasKnownTLD reports whether the domain's TLD exists. It is always true when no TLD list is
+// set, and for a dotless domain when dotless domains are allowed.
+func (v *DomainValidator) HasKnownTLD(domain string) bool {
+	if v.allowDotless && isDotless(domain) {
+		return true
+	}
+	return v.tlds == nil || v.tlds.HasKnownTLD(domain)
+}
+
+// ValidateDomainName checks domain like the package-level ValidateDomainName, but also
+// accepts a single-label domain when dotless domains are allowed
+func (v *DomainValidator) ValidateDomainName(domain string) error {
+	return validateDomainName(domain, v.allowDotless)
+}
+
+// ValidateDomainName checks that domain can follow the "@" of an address: a fully
+// qualified hostname of at most 255 characters whose labels are letters, digits and inner
+// hyphens. Internationalized domains are checked in their punycode form, and a trailing
+// dot is allowed.
+func ValidateDomainName(domain string) error {
+	return validateDomainName(domain, false)
+}
+
+// validateDomainName implements ValidateDomainName, accepting a single label with allowDotless
+func validateDomainName(domain string, allowDotless bool) error {
+	name := normalizeDomain(domain)
+	switch {
+	case name == "":
+		return errors.New("domain is empty")
+	case len(name) > maxDomainLength:
+		return fmt.Errorf("domain %q is longer than %d characters", domain, maxDomainLength)
+	case !allowDotless && !strings.Contains(name, "."):
+		return fmt.Errorf("domain %q is not a fully qualified domain name", domain)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !isHostnameLabel(label) {
+			return fmt.Errorf("domain %q has an invalid label %q", domain, label)
+		}
+	}
+	return nil
+}
+
+// isDotless reports whether domain is a single label, ignoring a trailing dot
+func isDotless(domain string) bool {
+	domain = TrimTrailingDot(domain)
+	return domain != "" && !strings.Contains(domain, ".")
+}
+
+// TrimTrailingDot removes the trailing dot of a fully-qualified domain, so "example.com."
+// becomes "example.com". It works on a bare domain or a whole address alike. Anything
+// else, including a domain ending with several dots, is returned unchanged.
+func TrimTrailingDot(s string) string {
+	if strings.HasSuffix(s, ".") && !strings.HasSuffix(s, "..") {
+		return s[:len(s)-1]
+	}
+	return s
+}
+
 // Validate checks if the domain exists
 func (v *DomainValidator) Validate(domain string) bool {
+	exists, _ := v.ValidateWithStatus(domain)
+	return exists
+}
+
+// ValidateWithStatus checks if the domain exists and reports whether the lookup was inconclusive.
+// Inconclusive results (e.g. DNS timeouts) are reported as not existing and are never cached.
+func (v *DomainValidator) ValidateWithStatus(domain string) (exists, inconclusive bool) {
+	// "example.com." and "example.com" share a cache entry
+	domain = TrimTrailingDot(domain)
+	if !v.HasKnownTLD(domain) {
+		return false, false
+	}
+
 	// Check cache first
 	if exists, found := v.cacheManager.Get(domain); found {
 		monitoring.RecordCacheOperation("domain_lookup", "hit")
-		return exists
+		return exists, false
 	}
 	monitoring.RecordCacheOperation("domain_lookup", "miss")
 
@@ -33,7 +134,10 @@ func (v *DomainValidator) Validate(domain string) bool {
 	start := time.Now()
 	_, err := v.resolver.LookupHost(domain)
 	monitoring.RecordDNSLookup("host", time.Since(start))
-	exists := err == nil
+	if IsInconclusiveDNSError(err) {
+		return false, true
+	}
+	exists = err == nil
 
 	// Update cache
 	v.cacheManager.Set(domain, exists)
@@ -41,31 +145,157 @@ func (v *DomainValidator) Validate(domain string) bool {
 	// Periodically clean up expired cache entries
 	go v.cacheManager.ClearExpired()
 
-	return exists
+	return exists, false
 }
 
 // ValidateMX checks if the domain has valid MX records
 func (v *DomainValidator) ValidateMX(domain string) bool {
-	start := time.Now()
-	mxRecords, err := v.resolver.LookupMX(domain)
-	monitoring.RecordDNSLookup("mx", time.Since(start))
+	hasMX, _ := v.ValidateMXWithStatus(domain)
+	return hasMX
+}
+
+// MXCheck is the outcome of a domain's MX lookup
+type MXCheck struct {
+	// HasMX means the domain publishes at least one usable mail server hostname
+	HasMX bool
+	// Inconclusive means the lookup failed without an answer (e.g. DNS timeout)
+	Inconclusive bool
+	// NullMX means the domain publishes a null MX (RFC 7505): it explicitly accepts no mail
+	NullMX bool
+	// IPLiteralMX means at least one MX record points at an IP address instead of a
+	// hostname, which RFC 5321 does not allow and many mail servers refuse to deliver to
+	IPLiteralMX bool
+	// MXDomain is the parent domain whose MX records were used, when the domain itself
+	// publishes none and walking up is enabled; empty when the domain's own records were used
+	MXDomain string
+}
+
+// ValidateMXWithStatus checks if the domain has valid MX records and reports whether the lookup was inconclusive
+func (v *DomainValidator) ValidateMXWithStatus(domain string) (hasMX, inconclusive bool) {
+	check := v.CheckMX(domain)
+	return check.HasMX, check.Inconclusive
+}
+
+// CheckMX looks up the domain's MX records and classifies them
+func (v *DomainValidator) CheckMX(domain string) MXCheck {
+	domain = TrimTrailingDot(domain)
+	if !v.HasKnownTLD(domain) {
+		return MXCheck{}
+	}
+
+	mxRecords, mxDomain, err := LookupMXWalkUp(v.resolver, domain, v.mxWalkUp)
+
+	// A timeout or temporary failure tells us nothing about the domain
+	if IsInconclusiveDNSError(err) {
+		return MXCheck{Inconclusive: true}
+	}
 
 	// If there's an error in lookup, the domain doesn't have valid MX records
 	if err != nil {
-		return false
+		return MXCheck{}
+	}
+
+	if IsNullMX(mxRecords) {
+		return MXCheck{NullMX: true}
+	}
+
+	// IP literals are flagged but do not count as mail servers
+	check := MXCheck{MXDomain: mxDomain}
+	for _, mx := range mxRecords {
+		if IsIPLiteralMX(mx.Host) {
+			check.IPLiteralMX = true
+		} else {
+			check.HasMX = true
+		}
+	}
+	return check
+}
+
+// LookupMXWalkUp looks up the MX records of domain and, while none are found, of up to
+// levels parent domains, stopping below the public suffix. mxDomain is the parent whose
+// records were returned, or empty for the domain's own. The walk only continues from a
+// name that exists without mail servers of its own: a name that does not exist at all,
+// such as a made-up subdomain, is not routed by its parent. A domain that publishes a null
+// MX has opted out of mail, so the walk stops there; a parent's null MX says nothing about
+// the subdomain, whose own answer is returned then. A lookup that fails inconclusively
+// stops the walk with that error, since a parent's answer cannot stand in for an unknown one.
+func LookupMXWalkUp(resolver DNSResolver, domain string, levels int) (records []*net.MX, mxDomain string, err error) {
+	records, err = lookupMX(resolver, domain)
+	if levels <= 0 || IsInconclusiveDNSError(err) || (err == nil && len(records) > 0) {
+		return records, "", err
+	}
+
+	ownRecords, ownErr := records, err
+	name := domain
+	for level := 0; level < levels; level++ {
+		exists, existsErr := nameExists(resolver, name, err)
+		if existsErr != nil {
+			return nil, "", existsErr
+		}
+		parent, ok := parentDomain(name)
+		if !exists || !ok {
+			break
+		}
+
+		records, err = lookupMX(resolver, parent)
+		if IsInconclusiveDNSError(err) {
+			return nil, "", err
+		}
+		if err == nil && len(records) > 0 {
+			if IsNullMX(records) {
+				break
+			}
+			return records, parent, nil
+		}
+		name = parent
 	}
+	return ownRecords, "", ownErr
+}
+
+// lookupMX looks up the MX records of name, recording the lookup's duration
+func lookupMX(resolver DNSResolver, name string) ([]*net.MX, error) {
+	start := time.Now()
+	records, err := resolver.LookupMX(name)
+	monitoring.RecordDNSLookup("mx", time.Since(start))
+	return records, err
+}
 
-	// No MX records means the domain doesn't accept email
-	if len(mxRecords) == 0 {
-		return false
+// nameExists reports whether name exists in DNS, given the error of its MX lookup that
+// found no records. An empty answer without an error means the name exists (NODATA). Since
+// resolvers commonly report NODATA and a missing name (NXDOMAIN) alike, the name's
+// addresses are looked up otherwise. err is set when that lookup is inconclusive.
+func nameExists(resolver DNSResolver, name string, mxErr error) (bool, error) {
+	if mxErr == nil {
+		return true, nil
+	}
+	start := time.Now()
+	addrs, err := resolver.LookupHost(name)
+	monitoring.RecordDNSLookup("host", time.Since(start))
+	if IsInconclusiveDNSError(err) {
+		return false, err
 	}
+	return err == nil && len(addrs) > 0, nil
+}
 
-	// Check for null MX record (RFC 7505)
-	// A single MX record with "." as the host indicates the domain doesn't accept email
-	if len(mxRecords) == 1 && mxRecords[0].Host == "." {
-		return false
+// parentDomain returns the domain one level above name, or false if that would be a
+// public suffix such as "com" or "co.uk", under which every domain is independent
+func parentDomain(name string) (string, bool) {
+	_, parent, found := strings.Cut(name, ".")
+	if !found || !strings.Contains(parent, ".") {
+		return "", false
 	}
+	suffix, _ := publicsuffix.PublicSuffix(parent)
+	return parent, suffix != parent
+}
+
+// IsNullMX reports whether records are a null MX (RFC 7505): a single record whose host
+// is the root ".", declaring that the domain accepts no mail
+func IsNullMX(records []*net.MX) bool {
+	return len(records) == 1 && (records[0].Host == "." || records[0].Host == "")
+}
 
-	// Otherwise, the domain has valid MX records
-	return true
+// IsIPLiteralMX reports whether an MX host is an IP address rather than a hostname
+func IsIPLiteralMX(host string) bool {
+	host = strings.Trim(strings.TrimSuffix(host, "."), "[]")
+	return net.ParseIP(host) != nil
 }
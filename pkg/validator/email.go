@@ -1,35 +1,64 @@
 package validator
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
 
+// DefaultMinSuggestionConfidence is the lowest SuggestionConfidence of a typo suggestion
+// returned unless configured otherwise. One edit in a domain of five or more characters
+// passes; two edits in a typical nine-character domain such as gmail.com do not, since
+// such a guess is as likely to be a different real domain as a typo.
+const DefaultMinSuggestionConfidence = 0.8
+
 // EmailValidator provides methods for validating email addresses
 type EmailValidator struct {
-	syntaxValidator     *SyntaxValidator
-	domainValidator     *DomainValidator
-	roleValidator       *RoleValidator
-	disposableValidator *DisposableValidator
-	aliasDetector       *AliasDetector
+	syntaxValidator         *SyntaxValidator
+	domainValidator         *DomainValidator
+	roleValidator           *RoleValidator
+	freeProviderValidator   *FreeProviderValidator
+	disposableValidator     *DisposableValidator
+	heuristicDetector       *DisposableHeuristicDetector
+	parkedDetector          *ParkedDomainDetector
+	disposableMXDetector    *DisposableMXDetector
+	aliasDetector           *AliasDetector
+	canonicalRules          CanonicalizationRules
+	typoLearner             *TypoLearner
+	typoDictionaries        map[string]map[string]string
+	minSuggestionConfidence float64
 }
 
 // NewEmailValidator creates a new instance of EmailValidator
 func NewEmailValidator() (*EmailValidator, error) {
 	cacheManager := NewDomainCacheManager(time.Hour)
-	resolver := &DefaultResolver{timeout: 2 * time.Second}
+	resolver := NewDefaultResolver(DefaultDNSTimeout)
 
 	disposableValidator, err := NewDisposableValidator()
 	if err != nil {
 		return nil, err
 	}
 
+	tlds, err := defaultTLDList()
+	if err != nil {
+		return nil, err
+	}
+	domainValidator := NewDomainValidator(resolver, cacheManager)
+	domainValidator.SetTLDList(tlds)
+
 	return &EmailValidator{
-		syntaxValidator:     NewSyntaxValidator(),
-		domainValidator:     NewDomainValidator(resolver, cacheManager),
-		roleValidator:       NewRoleValidator(),
-		disposableValidator: disposableValidator,
-		aliasDetector:       NewAliasDetector(),
+		syntaxValidator:         NewSyntaxValidator(),
+		domainValidator:         domainValidator,
+		roleValidator:           NewRoleValidator(),
+		freeProviderValidator:   NewFreeProviderValidator(),
+		disposableValidator:     disposableValidator,
+		heuristicDetector:       NewDisposableHeuristicDetector(),
+		parkedDetector:          NewParkedDomainDetector(),
+		aliasDetector:           NewAliasDetector(),
+		canonicalRules:          DefaultCanonicalizationRules(),
+		typoDictionaries:        defaultTypoDictionaries(),
+		minSuggestionConfidence: DefaultMinSuggestionConfidence,
 	}, nil
 }
 
@@ -42,18 +71,81 @@ func NewEmailValidatorWithResolver(resolver DNSResolver) (*EmailValidator, error
 		return nil, err
 	}
 
+	tlds, err := defaultTLDList()
+	if err != nil {
+		return nil, err
+	}
+	domainValidator := NewDomainValidator(resolver, cacheManager)
+	domainValidator.SetTLDList(tlds)
+
 	return &EmailValidator{
-		syntaxValidator:     NewSyntaxValidator(),
-		domainValidator:     NewDomainValidator(resolver, cacheManager),
-		roleValidator:       NewRoleValidator(),
-		disposableValidator: disposableValidator,
-		aliasDetector:       NewAliasDetector(),
+		syntaxValidator:         NewSyntaxValidator(),
+		domainValidator:         domainValidator,
+		roleValidator:           NewRoleValidator(),
+		freeProviderValidator:   NewFreeProviderValidator(),
+		disposableValidator:     disposableValidator,
+		heuristicDetector:       NewDisposableHeuristicDetector(),
+		parkedDetector:          NewParkedDomainDetector(),
+		aliasDetector:           NewAliasDetector(),
+		canonicalRules:          DefaultCanonicalizationRules(),
+		typoDictionaries:        defaultTypoDictionaries(),
+		minSuggestionConfidence: DefaultMinSuggestionConfidence,
 	}, nil
 }
 
+// defaultTLDList loads the bundled list of existing TLDs: config/tlds.txt if it exists, or
+// else the copy embedded in the binary
+func defaultTLDList() (*TLDList, error) {
+	tlds, err := DefaultListReader(EmbeddedTLDList).ReadDomains()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLD list: %w", err)
+	}
+	if len(tlds) == 0 {
+		return nil, fmt.Errorf("TLD list is empty")
+	}
+	return NewTLDList(tlds), nil
+}
+
 // SetResolver allows changing the DNS resolver
 func (v *EmailValidator) SetResolver(resolver DNSResolver) {
-	v.domainValidator = NewDomainValidator(resolver, v.domainValidator.cacheManager)
+	previous := v.domainValidator
+	v.domainValidator = NewDomainValidator(resolver, previous.cacheManager)
+	v.domainValidator.SetTLDList(previous.tlds)
+	v.domainValidator.SetMXWalkUp(previous.mxWalkUp)
+	v.domainValidator.SetAllowDotlessDomains(previous.allowDotless)
+}
+
+// SetMXWalkUp makes the MX check try up to levels parent domains when a domain has no MX
+// records; zero checks the exact domain only
+func (v *EmailValidator) SetMXWalkUp(levels int) {
+	v.domainValidator.SetMXWalkUp(levels)
+}
+
+// SetAllowDotlessDomains accepts single-label domains such as "user@intranet", which are
+// resolved through the configured DNS instead of failing the TLD check
+func (v *EmailValidator) SetAllowDotlessDomains(allow bool) {
+	v.domainValidator.SetAllowDotlessDomains(allow)
+}
+
+// ValidateDomainName checks that domain is a valid domain name, accepting single-label
+// domains when they are allowed
+func (v *EmailValidator) ValidateDomainName(domain string) error {
+	return v.domainValidator.ValidateDomainName(domain)
+}
+
+// SetTLDList replaces the list of existing TLDs; nil disables the TLD check
+func (v *EmailValidator) SetTLDList(tlds *TLDList) {
+	v.domainValidator.SetTLDList(tlds)
+}
+
+// TLDList returns the list of existing TLDs, or nil if the TLD check is disabled
+func (v *EmailValidator) TLDList() *TLDList {
+	return v.domainValidator.tlds
+}
+
+// HasKnownTLD reports whether the domain's TLD exists
+func (v *EmailValidator) HasKnownTLD(domain string) bool {
+	return v.domainValidator.HasKnownTLD(domain)
 }
 
 // SetCacheDuration sets how long domain lookup results are cached
@@ -61,28 +153,102 @@ func (v *EmailValidator) SetCacheDuration(duration time.Duration) {
 	v.domainValidator.cacheManager.SetDuration(duration)
 }
 
-// ValidateSyntax checks if the email address format is valid
-func (v *EmailValidator) ValidateSyntax(email string) bool {
-	// Check maximum length (RFC 5321)
-	if len(email) > 254 {
-		return false
-	}
+// SetDisposableAllowlist sets domains that are never considered disposable.
+// Entries may be exact domains or wildcards like "*.example.com".
+func (v *EmailValidator) SetDisposableAllowlist(domains []string) {
+	v.disposableValidator.SetAllowlist(domains)
+}
 
-	// Split email into local part and domain
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
-		return false
-	}
+// SetDisposableHeuristicDetector replaces the heuristic detector, e.g. with one whose rules
+// are loaded from a file
+func (v *EmailValidator) SetDisposableHeuristicDetector(detector *DisposableHeuristicDetector) {
+	v.heuristicDetector = detector
+}
 
-	// Check local part and domain lengths
-	localPart, domain := parts[0], parts[1]
-	if len(localPart) > 64 || len(domain) > 255 {
-		return false
+// SetParkedDomainDetector replaces the parked domain detector, e.g. with one whose parking
+// nameservers are loaded from a file. nil disables parked domain detection.
+func (v *EmailValidator) SetParkedDomainDetector(detector *ParkedDomainDetector) {
+	v.parkedDetector = detector
+}
+
+// SetDisposableMXDetector sets the detector of domains whose mail servers belong to a
+// disposable provider. nil, the default, disables the check, which costs an MX lookup.
+func (v *EmailValidator) SetDisposableMXDetector(detector *DisposableMXDetector) {
+	v.disposableMXDetector = detector
+}
+
+// SetDisposableValidator replaces the disposable domain validator, e.g. with one loaded from
+// a file. Set the allowlist afterwards, since it belongs to the validator.
+func (v *EmailValidator) SetDisposableValidator(disposableValidator *DisposableValidator) {
+	v.disposableValidator = disposableValidator
+}
+
+// SetDisposablePatterns sets regular expressions flagging disposable domains the list
+// does not name; nil disables them. Call it after SetDisposableValidator, which replaces
+// the list.
+func (v *EmailValidator) SetDisposablePatterns(patterns *DisposablePatterns) {
+	v.disposableValidator.SetPatterns(patterns)
+}
+
+// SetDisposableMatchStrategy sets whether entries of the disposable list cover subdomains.
+// Call it after SetDisposableValidator, which replaces the list.
+func (v *EmailValidator) SetDisposableMatchStrategy(strategy MatchStrategy) error {
+	return v.disposableValidator.SetMatchStrategy(strategy)
+}
+
+// SetRoleValidator replaces the role validator, e.g. with one loaded from a file
+func (v *EmailValidator) SetRoleValidator(roleValidator *RoleValidator) {
+	v.roleValidator = roleValidator
+}
+
+// SetFreeProviderValidator replaces the free provider validator, e.g. with one loaded from a file
+func (v *EmailValidator) SetFreeProviderValidator(freeProviderValidator *FreeProviderValidator) {
+	v.freeProviderValidator = freeProviderValidator
+}
+
+// Reload re-reads the role, free provider and disposable domain lists, the disposable
+// heuristic rules, the parking nameservers and the disposable MX providers from their
+// sources. Each list is swapped atomically and left unchanged if its new content fails
+// validation. A list that fails does not stop the others from reloading; the errors of
+// all failed lists are joined.
+func (v *EmailValidator) Reload() error {
+	errs := []error{
+		v.roleValidator.Reload(),
+		v.freeProviderValidator.Reload(),
+		v.disposableValidator.Reload(),
+		v.heuristicDetector.Reload(),
+	}
+	if v.parkedDetector != nil {
+		errs = append(errs, v.parkedDetector.Reload())
 	}
+	if v.disposableMXDetector != nil {
+		errs = append(errs, v.disposableMXDetector.Reload())
+	}
+	return errors.Join(errs...)
+}
 
+// ValidateSyntax checks if the email address format is valid
+func (v *EmailValidator) ValidateSyntax(email string) bool {
 	return v.syntaxValidator.Validate(email)
 }
 
+// ValidateSyntaxMode checks if the email address format is valid under mode
+func (v *EmailValidator) ValidateSyntaxMode(email string, mode SyntaxMode) bool {
+	return v.syntaxValidator.CheckMode(email, mode) == ViolationNone
+}
+
+// ExplainSyntaxMode returns a human-readable reason the email address failed syntax
+// validation under mode, or "" if it is valid
+func (v *EmailValidator) ExplainSyntaxMode(email string, mode SyntaxMode) string {
+	return v.syntaxValidator.CheckMode(email, mode).Description()
+}
+
+// ExplainSyntax returns a human-readable reason the email address failed syntax validation,
+// or an empty string if the address is valid
+func (v *EmailValidator) ExplainSyntax(email string) string {
+	return v.syntaxValidator.Check(email).Description()
+}
+
 // ValidateDomain checks if the domain exists
 func (v *EmailValidator) ValidateDomain(domain string) bool {
 	return v.domainValidator.Validate(domain)
@@ -93,16 +259,91 @@ func (v *EmailValidator) ValidateMXRecords(domain string) bool {
 	return v.domainValidator.ValidateMX(domain)
 }
 
+// ValidateDomainStatus checks if the domain exists and reports whether the lookup was inconclusive
+func (v *EmailValidator) ValidateDomainStatus(domain string) (exists, inconclusive bool) {
+	return v.domainValidator.ValidateWithStatus(domain)
+}
+
+// ValidateMXRecordsStatus checks if the domain has valid MX records and reports whether the lookup was inconclusive
+func (v *EmailValidator) ValidateMXRecordsStatus(domain string) (hasMX, inconclusive bool) {
+	return v.domainValidator.ValidateMXWithStatus(domain)
+}
+
+// CheckMXRecords looks up the domain's MX records and reports null MX and IP-literal targets
+func (v *EmailValidator) CheckMXRecords(domain string) MXCheck {
+	return v.domainValidator.CheckMX(domain)
+}
+
+// InspectDomain returns the raw DNS records behind the domain's validation, for debugging
+func (v *EmailValidator) InspectDomain(domain string) DomainInspection {
+	return v.domainValidator.Inspect(domain)
+}
+
+// DomainCacheAge reports whether the domain's existence would be answered from the cache
+// and, if so, how long ago it was looked up
+func (v *EmailValidator) DomainCacheAge(domain string) (time.Duration, bool) {
+	return v.domainValidator.CacheAge(domain)
+}
+
 // IsDisposable checks if the email domain is from a disposable email provider
 func (v *EmailValidator) IsDisposable(domain string) bool {
 	return v.disposableValidator.Validate(domain)
 }
 
+// DisposableHeuristic scores how likely domain is to be disposable from its name alone,
+// from 0 to 1, and returns the names of the rules it matched. Allowlisted domains score 0.
+// It complements IsDisposable for domains newer than the lists.
+func (v *EmailValidator) DisposableHeuristic(domain string) (float64, []string) {
+	if v.disposableValidator.IsAllowlisted(domain) {
+		return 0, nil
+	}
+	return v.heuristicDetector.Score(domain)
+}
+
+// DisposableMXProvider returns the disposable provider whose mail servers receive the
+// domain's mail, e.g. "mailinator.com" for a vanity domain routed through Mailinator, or
+// an empty string. It is empty when the check is disabled, the domain is allowlisted or
+// the MX lookup fails; inconclusive reports whether the lookup failed without an answer.
+func (v *EmailValidator) DisposableMXProvider(domain string) (provider string, inconclusive bool) {
+	if v.disposableMXDetector == nil || v.disposableValidator.IsAllowlisted(domain) {
+		return "", false
+	}
+	mxDomains, ok := v.disposableMXDetector.mxDomains(v.domainValidator, normalizeDomain(domain))
+	if !ok {
+		return "", true
+	}
+	return v.disposableMXDetector.Provider(mxDomains), false
+}
+
+// IsParked reports whether domain is delegated to the nameservers of a parking service.
+// It is false when parked domain detection is disabled, the resolver cannot look up NS
+// records, or the lookup fails.
+func (v *EmailValidator) IsParked(domain string) bool {
+	if v.parkedDetector == nil {
+		return false
+	}
+	nameservers, err := v.domainValidator.Nameservers(domain)
+	if err != nil {
+		return false
+	}
+	for _, nameserver := range nameservers {
+		if v.parkedDetector.IsParkingNameserver(nameserver) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsRoleBased checks if the email address is role-based
 func (v *EmailValidator) IsRoleBased(email string) bool {
 	return v.roleValidator.Validate(email)
 }
 
+// IsFreeProvider checks if the email address is at a free mailbox provider
+func (v *EmailValidator) IsFreeProvider(email string) bool {
+	return v.freeProviderValidator.Validate(email)
+}
+
 // CalculateScore calculates a score based on validation results
 func (v *EmailValidator) CalculateScore(validations map[string]bool) int {
 	score := 0
@@ -138,32 +379,43 @@ func (v *EmailValidator) CalculateScore(validations map[string]bool) int {
 	return score
 }
 
-// GetTypoSuggestions returns possible corrections for common email typos
+// SetTypoLearner enables suggestions from domains that have validated successfully.
+// Learned suggestions are preferred over the built-in corrections. nil disables them.
+func (v *EmailValidator) SetTypoLearner(learner *TypoLearner) {
+	v.typoLearner = learner
+}
+
+// SetMinSuggestionConfidence sets the lowest SuggestionConfidence of a suggestion returned
+// by GetTypoSuggestions; weaker guesses are dropped
+func (v *EmailValidator) SetMinSuggestionConfidence(confidence float64) {
+	v.minSuggestionConfidence = confidence
+}
+
+// GetTypoSuggestions returns possible corrections for common email typos whose confidence
+// meets the configured minimum
 func (v *EmailValidator) GetTypoSuggestions(email string) []string {
-	// Common domain corrections
-	commonDomains := map[string]string{
-		"gmial.com":  "gmail.com",
-		"gmal.com":   "gmail.com",
-		"gamil.com":  "gmail.com",
-		"gmai.com":   "gmail.com",
-		"gmail.co":   "gmail.com",
-		"gmail.cm":   "gmail.com",
-		"gmail.om":   "gmail.com",
-		"gmail.con":  "gmail.com",
-		"yaho.com":   "yahoo.com",
-		"yahooo.com": "yahoo.com",
-		"yahoo.co":   "yahoo.com",
-		"yahoo.cm":   "yahoo.com",
-		"hotmai.com": "hotmail.com",
-		"hotmal.com": "hotmail.com",
-		"hotmail.co": "hotmail.com",
-		"hotmail.cm": "hotmail.com",
-		"otmail.com": "hotmail.com",
-		"outlook.co": "outlook.com",
-		"outlook.cm": "outlook.com",
-		"outlok.com": "outlook.com",
-	}
+	return v.GetTypoSuggestionsWithConfidence(email, v.minSuggestionConfidence)
+}
 
+// GetTypoSuggestionsWithConfidence returns possible corrections for common email typos whose
+// SuggestionConfidence is at least minConfidence
+func (v *EmailValidator) GetTypoSuggestionsWithConfidence(email string, minConfidence float64) []string {
+	return v.GetTypoSuggestionsForRegionWithConfidence(email, "", minConfidence)
+}
+
+// GetTypoSuggestionsForRegion returns possible corrections for common email typos, consulting
+// region's dictionary (see TypoRegion) before the global one, whose confidence meets the
+// configured minimum
+func (v *EmailValidator) GetTypoSuggestionsForRegion(email, region string) []string {
+	return v.GetTypoSuggestionsForRegionWithConfidence(email, region, v.minSuggestionConfidence)
+}
+
+// GetTypoSuggestionsForRegionWithConfidence returns possible corrections for common email
+// typos whose SuggestionConfidence is at least minConfidence. A typo in region's dictionary
+// is corrected as the region's dictionary says, so a mistyped regional provider is not
+// mistaken for a global one; other typos fall back to the global dictionary. An empty or
+// unknown region uses the global dictionary only.
+func (v *EmailValidator) GetTypoSuggestionsForRegionWithConfidence(email, region string, minConfidence float64) []string {
 	parts := strings.Split(email, "@")
 	if len(parts) != 2 {
 		return nil
@@ -172,15 +424,66 @@ func (v *EmailValidator) GetTypoSuggestions(email string) []string {
 	localPart, domain := parts[0], parts[1]
 	var suggestions []string
 
-	// Check for common domain typos
-	if correctedDomain, exists := commonDomains[domain]; exists {
+	// Prefer domains our own users have been seen delivering mail to
+	if v.typoLearner != nil {
+		if learnedDomain := v.typoLearner.Suggest(domain); learnedDomain != "" && SuggestionConfidence(domain, learnedDomain) >= minConfidence {
+			suggestions = append(suggestions, localPart+"@"+learnedDomain)
+		}
+	}
+
+	// Check for common domain typos, in the region's dictionary first
+	correctedDomain, exists := v.typoDictionaries[strings.ToLower(region)][strings.ToLower(domain)]
+	if !exists {
+		correctedDomain, exists = v.typoDictionaries[""][strings.ToLower(domain)]
+	}
+	if exists && SuggestionConfidence(domain, correctedDomain) >= minConfidence &&
+		(len(suggestions) == 0 || suggestions[0] != localPart+"@"+correctedDomain) {
 		suggestions = append(suggestions, localPart+"@"+correctedDomain)
 	}
 
 	return suggestions
 }
 
+// SuggestionConfidence rates from 0 to 1 how likely suggested is the domain meant by someone
+// who typed domain: one minus their edit distance relative to the longer of the two
+func SuggestionConfidence(domain, suggested string) float64 {
+	domain, suggested = strings.ToLower(domain), strings.ToLower(suggested)
+	length := max(len(domain), len(suggested))
+	if length == 0 {
+		return 0
+	}
+	return 1 - float64(editDistance(domain, suggested))/float64(length)
+}
+
 // DetectAlias checks if the email is an alias and returns the canonical email if it is
 func (v *EmailValidator) DetectAlias(email string) string {
 	return v.aliasDetector.DetectAlias(email)
 }
+
+// Canonicalize returns the normalized form of email under the configured canonicalization rules
+func (v *EmailValidator) Canonicalize(email string) string {
+	return v.aliasDetector.Canonicalize(email, v.canonicalRules)
+}
+
+// DetectSubaddress returns the subaddress tag of email, e.g. "news" in jane+news@gmail.com,
+// and whether it has one
+func (v *EmailValidator) DetectSubaddress(email string) (string, bool) {
+	return v.aliasDetector.DetectSubaddress(email)
+}
+
+// SetCanonicalizationRules sets which normalizations Canonicalize applies
+func (v *EmailValidator) SetCanonicalizationRules(rules CanonicalizationRules) {
+	v.canonicalRules = rules
+}
+
+// AddressingCapabilities returns the addressing features supported at domain
+func (v *EmailValidator) AddressingCapabilities(domain string) Capabilities {
+	return v.aliasDetector.AddressingCapabilities(domain)
+}
+
+// SetAddressingCapabilities adds or replaces entries in the addressing capabilities table
+func (v *EmailValidator) SetAddressingCapabilities(table map[string]Capabilities) {
+	for domain, caps := range table {
+		v.aliasDetector.SetAddressingCapabilities(domain, caps)
+	}
+}
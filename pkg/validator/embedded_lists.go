@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"emailvalidator/config"
+)
+
+// Names of the lists embedded in the binary, which are also their names in the config directory
+const (
+	EmbeddedDisposableList = "disposable_domains.txt"
+	EmbeddedTLDList        = "tlds.txt"
+)
+
+// EmbeddedDomainReader implements DomainReader for a list embedded in the binary, in the
+// same one-entry-per-line format as FileDomainReader
+type EmbeddedDomainReader struct {
+	name string
+}
+
+// NewEmbeddedDomainReader creates an EmbeddedDomainReader for the named embedded list
+func NewEmbeddedDomainReader(name string) *EmbeddedDomainReader {
+	return &EmbeddedDomainReader{name: name}
+}
+
+// ReadDomains reads the embedded list, skipping empty lines and comments
+func (r *EmbeddedDomainReader) ReadDomains() ([]string, error) {
+	data, err := config.Files.ReadFile(r.name)
+	if err != nil {
+		return nil, fmt.Errorf("no embedded list %q", r.name)
+	}
+	return PlaintextParser{}.Parse(bytes.NewReader(data))
+}
+
+// DefaultListReader returns the reader for a bundled list: the file of that name in the
+// config directory if there is one, so an edited copy takes effect, and otherwise the copy
+// embedded in the binary
+func DefaultListReader(name string) DomainReader {
+	if path, err := configFile(name); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			return NewFileDomainReader(path)
+		}
+	}
+	return NewEmbeddedDomainReader(name)
+}
@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce is how long a watched file must be quiet before it is reloaded, so that
+// an editor or deploy tool writing the file in several steps triggers a single reload
+const reloadDebounce = 250 * time.Millisecond
+
+// WatchFile calls reload whenever the file at path is written, created or replaced, until
+// ctx is cancelled. The parent directory is watched so that atomic replacements (write to a
+// temporary file, then rename) are picked up. Reload errors are logged; reload implementations
+// are expected to keep their previous contents when they fail.
+func WatchFile(ctx context.Context, path string, reload func() error) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	path = filepath.Clean(path)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		timer := time.NewTimer(reloadDebounce)
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					continue
+				}
+				timer.Reset(reloadDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("Error watching %s: %v", path, err)
+			case <-timer.C:
+				if err := reload(); err != nil {
+					log.Printf("Error reloading %s, keeping previous contents: %v", path, err)
+					continue
+				}
+				log.Printf("Reloaded %s", path)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// HashFiles returns a digest of the contents of the files at paths, in order, for telling
+// whether lists loaded from them changed, e.g. across restarts or between instances. Empty
+// paths are skipped, and a missing file hashes differently from an empty one.
+func HashFiles(paths ...string) (string, error) {
+	h := sha256.New()
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Clean(path))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fmt.Fprintf(h, "%s\x00missing\x00", path)
+			continue
+		case err != nil:
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", path, len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
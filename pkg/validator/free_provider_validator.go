@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultFreeProviders are the free mailbox providers used when no free-provider list is configured
+var defaultFreeProviders = []string{
+	"gmail.com",
+	"googlemail.com",
+	"yahoo.com",
+	"ymail.com",
+	"outlook.com",
+	"hotmail.com",
+	"live.com",
+	"msn.com",
+	"aol.com",
+	"icloud.com",
+	"me.com",
+	"mac.com",
+	"proton.me",
+	"protonmail.com",
+	"gmx.com",
+	"gmx.net",
+	"web.de",
+	"mail.com",
+	"zoho.com",
+	"yandex.com",
+	"yandex.ru",
+	"mail.ru",
+	"qq.com",
+	"163.com",
+}
+
+// FreeProviderValidator detects addresses at free mailbox providers such as Gmail, which
+// anyone can sign up for, as opposed to company or school domains
+type FreeProviderValidator struct {
+	reader  DomainReader
+	domains atomic.Pointer[DomainMatcher]
+}
+
+// NewFreeProviderValidator creates a FreeProviderValidator with the built-in provider list
+func NewFreeProviderValidator() *FreeProviderValidator {
+	v := &FreeProviderValidator{reader: NewStaticDomainReader(defaultFreeProviders)}
+	v.domains.Store(NewDomainMatcher(defaultFreeProviders))
+	return v
+}
+
+// NewFreeProviderValidatorWithReader creates a FreeProviderValidator whose provider domains
+// are read from reader. Call Reload to pick up changes to the underlying source.
+func NewFreeProviderValidatorWithReader(reader DomainReader) (*FreeProviderValidator, error) {
+	v := &FreeProviderValidator{reader: reader}
+	if err := v.Reload(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewFreeProviderValidatorFromFile creates a FreeProviderValidator using provider domains
+// from a file with one entry per line. Entries may be wildcards like "*.example.com".
+func NewFreeProviderValidatorFromFile(path string) (*FreeProviderValidator, error) {
+	return NewFreeProviderValidatorWithReader(NewFileDomainReader(path))
+}
+
+// Reload re-reads the provider list and atomically swaps it in. If the new content is empty
+// or contains an invalid entry (e.g. a partially written file), the current list is kept.
+func (v *FreeProviderValidator) Reload() error {
+	domains, err := v.reader.ReadDomains()
+	if err != nil {
+		return fmt.Errorf("failed to read free provider list: %w", err)
+	}
+	if len(domains) == 0 {
+		return fmt.Errorf("free provider list is empty")
+	}
+	for _, domain := range domains {
+		if !isValidListDomain(domain) {
+			return fmt.Errorf("invalid free provider list entry %q", domain)
+		}
+	}
+
+	v.domains.Store(NewDomainMatcher(domains))
+	return nil
+}
+
+// Validate checks if the email address is at a free mailbox provider
+func (v *FreeProviderValidator) Validate(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return false
+	}
+	return v.domains.Load().Contains(email[at+1:])
+}
@@ -0,0 +1,114 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// sendingIPProbeAddress is a public address used to find the local address of the
+// default route. Dialing UDP sends no packets.
+const sendingIPProbeAddress = "8.8.8.8:53"
+
+// ValidateHELOName checks that name is usable as a HELO/EHLO hostname. Many mail servers
+// reject greetings that are not fully qualified domain names, and then answer every
+// mailbox probe with an error that says nothing about the mailbox.
+func ValidateHELOName(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	switch {
+	case name == "":
+		return errors.New("HELO name is empty")
+	case net.ParseIP(strings.Trim(name, "[]")) != nil:
+		return fmt.Errorf("HELO name %q is an IP address, not a hostname", name)
+	case len(name) > maxDomainLength:
+		return fmt.Errorf("HELO name %q is longer than %d characters", name, maxDomainLength)
+	case !strings.Contains(name, "."):
+		return fmt.Errorf("HELO name %q is not a fully qualified domain name", name)
+	}
+
+	lower := strings.ToLower(name)
+	for _, suffix := range []string{".localhost", ".localdomain", ".local", ".internal"} {
+		if strings.HasSuffix(lower, suffix) {
+			return fmt.Errorf("HELO name %q is not publicly resolvable", name)
+		}
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if !isHostnameLabel(label) {
+			return fmt.Errorf("HELO name %q has an invalid label %q", name, label)
+		}
+	}
+	return nil
+}
+
+// isHostnameLabel reports whether label is a valid hostname label (letters, digits and
+// inner hyphens, at most 63 characters)
+func isHostnameLabel(label string) bool {
+	if label == "" || len(label) > maxLabelLength || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, c := range label {
+		if !(c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// PreflightHELO checks that name is a valid HELO hostname that resolves to sendingIP, as
+// mail servers verify. It returns the first problem found, or nil if the name is fine.
+// The address check is skipped when sendingIP is nil.
+func PreflightHELO(name string, resolver DNSResolver, sendingIP net.IP) error {
+	if err := ValidateHELOName(name); err != nil {
+		return err
+	}
+
+	addrs, err := resolver.LookupHost(strings.TrimSuffix(name, "."))
+	if err != nil {
+		return fmt.Errorf("HELO name %q does not resolve: %w", name, err)
+	}
+	if sendingIP == nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip != nil && ip.Equal(sendingIP) {
+			return nil
+		}
+	}
+	return fmt.Errorf("HELO name %q resolves to %s, not to the sending IP %s",
+		name, strings.Join(addrs, ", "), sendingIP)
+}
+
+// SendingIP returns the local address used for outbound connections on the default route.
+// Behind NAT this is a private address rather than the one mail servers see.
+func SendingIP() (net.IP, error) {
+	conn, err := net.Dial("udp", sendingIPProbeAddress)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// DefaultHELOName picks a HELO name for this host: the reverse DNS name of sendingIP when
+// it is a valid HELO name, otherwise the host name. The result should still be checked
+// with PreflightHELO.
+func DefaultHELOName(sendingIP net.IP) string {
+	if sendingIP != nil {
+		if names, err := net.LookupAddr(sendingIP.String()); err == nil {
+			for _, name := range names {
+				if ValidateHELOName(name) == nil {
+					return strings.TrimSuffix(name, ".")
+				}
+			}
+		}
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
@@ -0,0 +1,202 @@
+package validator
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Defaults for HTTPClientConfig
+const (
+	// DefaultHTTPTimeout is the time allowed for an outbound HTTP request, including reading the body
+	DefaultHTTPTimeout = 30 * time.Second
+	// DefaultMaxIdleConnsPerHost is the number of idle connections kept open to each host
+	DefaultMaxIdleConnsPerHost = 10
+	// projectURL identifies the project in the default User-Agent
+	projectURL = "https://github.com/SaiAkashNeela/email-verifier"
+)
+
+// Version is the release reported in the default User-Agent. Release builds set it with
+// -ldflags "-X emailvalidator/pkg/validator.Version=1.2.3".
+var Version = "dev"
+
+var (
+	defaultHTTPClientOnce sync.Once
+	defaultHTTPClient     *http.Client
+)
+
+// HTTPClientConfig configures the client used for outbound HTTP requests, such as
+// blocklist, TLD list and RDAP fetches. One client is meant to be shared by every
+// fetcher, so they share its connection pool and network policy.
+type HTTPClientConfig struct {
+	// ProxyURL routes every request through this proxy. The scheme may be http, https or
+	// socks5. Empty uses the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL string
+	// Timeout limits each request; zero uses DefaultHTTPTimeout
+	Timeout time.Duration
+	// CABundle is a PEM file of certificate authorities trusted in addition to the system
+	// roots, e.g. for a TLS-intercepting proxy. Empty trusts the system roots only.
+	CABundle string
+	// MaxIdleConnsPerHost limits the idle connections kept open to each host; zero uses
+	// DefaultMaxIdleConnsPerHost
+	MaxIdleConnsPerHost int
+	// UserAgent is sent with every request that does not set its own; empty uses DefaultUserAgent
+	UserAgent string
+	// PublicAddressesOnly refuses connections to loopback, private, link-local, multicast
+	// and unspecified addresses, for fetching URLs supplied by API clients. The address is
+	// checked when dialing, after DNS resolution, so it covers every redirect and every
+	// answer of a rebinding DNS server. No proxy is used, since the proxy's address would
+	// be checked instead of the destination's.
+	PublicAddressesOnly bool
+}
+
+// ErrNonPublicAddress is returned for a connection refused by HTTPClientConfig.PublicAddressesOnly
+var ErrNonPublicAddress = errors.New("connection to a non-public address refused")
+
+// DefaultUserAgent returns the User-Agent identifying this project and its version. Some
+// CDNs throttle or block requests with Go's generic User-Agent.
+func DefaultUserAgent() string {
+	return "email-verifier/" + Version + " (+" + projectURL + ")"
+}
+
+// NewHTTPClient creates an http.Client from config
+func NewHTTPClient(config HTTPClientConfig) (*http.Client, error) {
+	proxy, err := proxyFunc(config.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	if config.PublicAddressesOnly {
+		transport.Proxy = nil
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: refuseNonPublic}
+		transport.DialContext = dialer.DialContext
+	}
+	transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	if transport.MaxIdleConnsPerHost == 0 {
+		transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if config.CABundle != "" {
+		roots, err := loadCABundle(config.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	}
+
+	userAgent := config.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	return &http.Client{
+		Transport: &userAgentTransport{next: transport, userAgent: userAgent},
+		Timeout:   timeout,
+	}, nil
+}
+
+// userAgentTransport sets the User-Agent header on requests that do not have one
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return t.next.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
+
+// CloseIdleConnections closes idle connections of the underlying transport, so
+// http.Client.CloseIdleConnections keeps working
+func (t *userAgentTransport) CloseIdleConnections() {
+	if closer, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// DefaultHTTPClient returns the client shared by fetchers that were not given one. It
+// honors the proxy environment variables and uses the defaults of HTTPClientConfig.
+func DefaultHTTPClient() *http.Client {
+	defaultHTTPClientOnce.Do(func() {
+		// The zero config is always valid
+		defaultHTTPClient, _ = NewHTTPClient(HTTPClientConfig{})
+	})
+	return defaultHTTPClient
+}
+
+// refuseNonPublic is a net.Dialer Control function that fails dials to addresses that are
+// not publicly routable
+func refuseNonPublic(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, ip)
+	}
+	return nil
+}
+
+// proxyFunc returns the transport proxy function for proxyURL, falling back to the
+// environment when it is empty
+func proxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", proxyURL)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxyURL)
+	}
+	return http.ProxyURL(parsed), nil
+}
+
+// loadCABundle returns the system roots extended with the certificates in the PEM file at path
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+	}
+	return roots, nil
+}
@@ -0,0 +1,149 @@
+package validator
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Names of the built-in list parsers
+const (
+	ParserPlaintext = "plaintext"
+	ParserJSON      = "json"
+	ParserCSV       = "csv"
+)
+
+// ListParser extracts domain entries from a downloaded domain list
+type ListParser interface {
+	Parse(r io.Reader) ([]string, error)
+}
+
+// PlaintextParser parses lists with one domain per line. Blank lines and lines
+// starting with any of CommentPrefixes are skipped; "#" is used if none are set.
+type PlaintextParser struct {
+	CommentPrefixes []string
+}
+
+// Parse implements ListParser
+func (p PlaintextParser) Parse(r io.Reader) ([]string, error) {
+	prefixes := p.CommentPrefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{"#"}
+	}
+
+	var domains []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || hasAnyPrefix(line, prefixes) {
+			continue
+		}
+		domains = append(domains, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// JSONParser parses lists encoded as a JSON array. Elements are domain strings, or
+// objects whose Field member holds the domain when Field is set.
+type JSONParser struct {
+	Field string
+}
+
+// Parse implements ListParser
+func (p JSONParser) Parse(r io.Reader) ([]string, error) {
+	if p.Field == "" {
+		var domains []string
+		if err := json.NewDecoder(r).Decode(&domains); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON domain list: %w", err)
+		}
+		return nonEmpty(domains), nil
+	}
+
+	var objects []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&objects); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON domain list: %w", err)
+	}
+
+	domains := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if domain, ok := object[p.Field].(string); ok {
+			domains = append(domains, domain)
+		}
+	}
+	return nonEmpty(domains), nil
+}
+
+// CSVParser parses comma-separated lists, taking the domain from Column (zero-based).
+// The first row is skipped when HasHeader is set, and rows starting with "#" are ignored.
+type CSVParser struct {
+	Column    int
+	HasHeader bool
+}
+
+// Parse implements ListParser
+func (p CSVParser) Parse(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var domains []string
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV domain list: %w", err)
+		}
+		if row == 0 && p.HasHeader {
+			continue
+		}
+		if p.Column < len(record) {
+			domains = append(domains, record[p.Column])
+		}
+	}
+	return nonEmpty(domains), nil
+}
+
+// ParserByName returns the built-in parser with the given name using its default settings
+func ParserByName(name string) (ListParser, error) {
+	switch strings.ToLower(name) {
+	case "", ParserPlaintext:
+		return PlaintextParser{}, nil
+	case ParserJSON:
+		return JSONParser{}, nil
+	case ParserCSV:
+		return CSVParser{}, nil
+	default:
+		return nil, fmt.Errorf("unknown list parser %q: must be %q, %q or %q", name, ParserPlaintext, ParserJSON, ParserCSV)
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// nonEmpty trims entries and drops blank ones
+func nonEmpty(entries []string) []string {
+	result := entries[:0]
+	for _, entry := range entries {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"emailvalidator/pkg/monitoring"
+)
+
+// defaultParkingNameservers are the nameservers of domain parking services, used when no
+// parking nameserver list is configured
+var defaultParkingNameservers = []string{
+	"*.above.com",
+	"*.bodis.com",
+	"*.dan.com",
+	"*.fabulous.com",
+	"*.hugedomainsdns.com",
+	"*.namebrightdns.com",
+	"*.parkingcrew.net",
+	"*.parklogic.com",
+	"*.sedoparking.com",
+	"*.ztomy.com",
+}
+
+// ParkedDomainDetector recognizes parked domains by their nameservers. Parked and
+// recently expired domains are usually delegated to a parking service that shows ads on
+// the domain, and they rarely accept mail.
+type ParkedDomainDetector struct {
+	reader      DomainReader
+	nameservers atomic.Pointer[DomainMatcher]
+}
+
+// NewParkedDomainDetector creates a ParkedDomainDetector with the built-in list of
+// parking nameservers
+func NewParkedDomainDetector() *ParkedDomainDetector {
+	d := &ParkedDomainDetector{reader: NewStaticDomainReader(defaultParkingNameservers)}
+	d.nameservers.Store(NewDomainMatcher(defaultParkingNameservers))
+	return d
+}
+
+// NewParkedDomainDetectorFromFile creates a ParkedDomainDetector using parking nameservers
+// from a file with one entry per line. Entries are nameserver hostnames, or wildcards like
+// "*.sedoparking.com" covering every nameserver of a provider.
+func NewParkedDomainDetectorFromFile(path string) (*ParkedDomainDetector, error) {
+	d := &ParkedDomainDetector{reader: NewFileDomainReader(path)}
+	if err := d.Reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reload re-reads the parking nameserver list and atomically swaps it in. If the new
+// content is empty or contains an invalid entry, the current list is kept.
+func (d *ParkedDomainDetector) Reload() error {
+	nameservers, err := d.reader.ReadDomains()
+	if err != nil {
+		return fmt.Errorf("failed to read parking nameserver list: %w", err)
+	}
+	if len(nameservers) == 0 {
+		return fmt.Errorf("parking nameserver list is empty")
+	}
+	for _, nameserver := range nameservers {
+		if !isValidListDomain(nameserver) {
+			return fmt.Errorf("invalid parking nameserver list entry %q", nameserver)
+		}
+	}
+
+	d.nameservers.Store(NewDomainMatcher(nameservers))
+	return nil
+}
+
+// IsParkingNameserver reports whether host is the nameserver of a parking service
+func (d *ParkedDomainDetector) IsParkingNameserver(host string) bool {
+	return d.nameservers.Load().Contains(strings.TrimSuffix(host, "."))
+}
+
+// Nameservers looks up the domain's NS records. It returns nil without a lookup if the
+// resolver cannot look up NS records or the domain's TLD does not exist.
+func (v *DomainValidator) Nameservers(domain string) ([]string, error) {
+	nsResolver, ok := v.resolver.(NSResolver)
+	domain = TrimTrailingDot(domain)
+	if !ok || !v.HasKnownTLD(domain) {
+		return nil, nil
+	}
+
+	start := time.Now()
+	records, err := nsResolver.LookupNS(domain)
+	monitoring.RecordDNSLookup("ns", time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, 0, len(records))
+	for _, record := range records {
+		hosts = append(hosts, record.Host)
+	}
+	return hosts, nil
+}
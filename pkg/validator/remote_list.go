@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultListRefreshInterval is how often remote classification lists are re-fetched
+const DefaultListRefreshInterval = 24 * time.Hour
+
+// RemoteListReader is a DomainReader that downloads a list from one or more sources, parsed
+// the same way as disposable blocklist sources. It lets the role and free-provider lists be
+// updated without a redeploy. If any source fails on the first read and a fallback is set,
+// the fallback is read instead, so the list still loads when the remote is unreachable at
+// startup. Later reads return the fetch error, so a refresh keeps the list already loaded
+// rather than replacing it with the older fallback.
+type RemoteListReader struct {
+	sources  []BlocklistSource
+	client   *http.Client
+	timeout  time.Duration
+	fallback DomainReader
+	// loaded is set once a read succeeds
+	loaded atomic.Bool
+}
+
+// NewRemoteListReader creates a RemoteListReader that fetches sources with client and falls
+// back to the local file at fallbackPath. An empty fallbackPath disables the fallback, and a
+// nil client uses DefaultHTTPClient.
+func NewRemoteListReader(client *http.Client, fallbackPath string, sources ...BlocklistSource) *RemoteListReader {
+	if client == nil {
+		client = DefaultHTTPClient()
+	}
+	r := &RemoteListReader{
+		sources: sources,
+		client:  client,
+		timeout: DefaultBlocklistLoadTimeout,
+	}
+	if fallbackPath != "" {
+		r.fallback = NewFileDomainReader(fallbackPath)
+	}
+	return r
+}
+
+// SetTimeout sets the time allowed for fetching every source
+func (r *RemoteListReader) SetTimeout(timeout time.Duration) {
+	r.timeout = timeout
+}
+
+// ReadDomains fetches and merges every source. If one of them fails, the first successful
+// read falls back to the fallback; after that, the fetch error is returned.
+func (r *RemoteListReader) ReadDomains() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	var entries []string
+	for _, source := range r.sources {
+		fetched, err := fetchListSource(ctx, r.client, source)
+		if err != nil {
+			if r.loaded.Load() {
+				return nil, err
+			}
+			entries, err := r.readFallback(err)
+			if err == nil {
+				r.loaded.Store(true)
+			}
+			return entries, err
+		}
+		entries = append(entries, fetched...)
+	}
+	r.loaded.Store(true)
+	return entries, nil
+}
+
+// readFallback reads the fallback after fetching failed with fetchErr
+func (r *RemoteListReader) readFallback(fetchErr error) ([]string, error) {
+	if r.fallback == nil {
+		return nil, fetchErr
+	}
+	entries, err := r.fallback.ReadDomains()
+	if err != nil {
+		return nil, errors.Join(fetchErr, err)
+	}
+	log.Printf("Warning: %v; using the local fallback list", fetchErr)
+	return entries, nil
+}
+
+// Reloader is a list that can re-read its source
+type Reloader interface {
+	Reload() error
+}
+
+// StartAutoReload reloads list every interval until ctx is cancelled. Failed reloads are
+// logged under name and the current list is kept.
+func StartAutoReload(ctx context.Context, list Reloader, name string, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := list.Reload(); err != nil {
+					log.Printf("Error refreshing %s, keeping the current list: %v", name, err)
+					continue
+				}
+				log.Printf("Refreshed %s.", name)
+			}
+		}
+	}()
+}
@@ -1,28 +1,72 @@
 package validator
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultRolePrefixes are the role-based local parts used when no role file is configured
+var defaultRolePrefixes = []string{
+	"admin",
+	"support",
+	"info",
+	"sales",
+	"contact",
+	"help",
+	"marketing",
+	"team",
+	"billing",
+	"office",
+}
 
 // RoleValidator handles role-based email validation
 type RoleValidator struct {
-	rolePrefixes []string
+	reader DomainReader
+	roles  atomic.Pointer[map[string]struct{}]
 }
 
 // NewRoleValidator creates a new instance of RoleValidator
 func NewRoleValidator() *RoleValidator {
-	return &RoleValidator{
-		rolePrefixes: []string{
-			"admin",
-			"support",
-			"info",
-			"sales",
-			"contact",
-			"help",
-			"marketing",
-			"team",
-			"billing",
-			"office",
-		},
+	v := &RoleValidator{reader: NewStaticDomainReader(defaultRolePrefixes)}
+	v.roles.Store(newRoleSet(defaultRolePrefixes))
+	return v
+}
+
+// NewRoleValidatorWithReader creates a new instance of RoleValidator whose role local parts
+// are read from reader. Call Reload to pick up changes to the underlying source.
+func NewRoleValidatorWithReader(reader DomainReader) (*RoleValidator, error) {
+	v := &RoleValidator{reader: reader}
+	if err := v.Reload(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewRoleValidatorFromFile creates a new instance of RoleValidator using role local parts
+// from a file with one entry per line
+func NewRoleValidatorFromFile(path string) (*RoleValidator, error) {
+	return NewRoleValidatorWithReader(NewFileDomainReader(path))
+}
+
+// Reload re-reads the role list and atomically swaps it in. If the new content is empty
+// or contains an invalid entry (e.g. a partially written file), the current list is kept.
+func (v *RoleValidator) Reload() error {
+	roles, err := v.reader.ReadDomains()
+	if err != nil {
+		return fmt.Errorf("failed to read role list: %w", err)
 	}
+	if len(roles) == 0 {
+		return fmt.Errorf("role list is empty")
+	}
+	for _, role := range roles {
+		if !isValidRoleEntry(role) {
+			return fmt.Errorf("invalid role list entry %q", role)
+		}
+	}
+
+	v.roles.Store(newRoleSet(roles))
+	return nil
 }
 
 // Validate checks if the email address is role-based
@@ -32,11 +76,19 @@ func (v *RoleValidator) Validate(email string) bool {
 		return false
 	}
 
-	localPart := strings.ToLower(parts[0])
-	for _, prefix := range v.rolePrefixes {
-		if localPart == prefix {
-			return true
-		}
+	_, ok := (*v.roles.Load())[strings.ToLower(parts[0])]
+	return ok
+}
+
+func newRoleSet(roles []string) *map[string]struct{} {
+	set := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		set[strings.ToLower(role)] = struct{}{}
 	}
-	return false
+	return &set
+}
+
+// isValidRoleEntry reports whether entry can be a local part on its own
+func isValidRoleEntry(entry string) bool {
+	return entry != "" && len(entry) <= maxLocalPartLength && !strings.ContainsAny(entry, "@ \t,")
 }
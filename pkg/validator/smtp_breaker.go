@@ -0,0 +1,165 @@
+package validator
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"emailvalidator/pkg/monitoring"
+)
+
+// Defaults for the per-domain SMTP circuit breaker
+const (
+	DefaultSMTPBreakerThreshold = 5
+	DefaultSMTPBreakerCooldown  = 5 * time.Minute
+)
+
+// SMTPStatusCircuitOpen means the probe was skipped because the domain's mail servers failed
+// too many probes in a row. The mailbox check is inconclusive.
+const SMTPStatusCircuitOpen SMTPStatus = "CIRCUIT_OPEN"
+
+// domainBreakerState tracks the consecutive probe failures of one domain
+type domainBreakerState struct {
+	failures    int
+	lastFailure time.Time
+	openedAt    time.Time
+	probing     bool
+}
+
+// domainBreaker is a circuit breaker per domain. After threshold consecutive failed probes
+// a domain's circuit opens, and probes to it are skipped until cooldown has passed. Then a
+// single probe is let through: success closes the circuit, failure keeps it open for
+// another cooldown. A zero threshold disables the breaker.
+//
+// Failures are forgotten once they go stale, so the state kept stays bounded by the domains
+// failing recently: failures short of the threshold after a cooldown without another, and
+// an open circuit after a further cooldown passes with no probe let through.
+type domainBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	domains   map[string]*domainBreakerState
+	lastPrune time.Time
+}
+
+// allow reports whether domain may be probed now
+func (b *domainBreaker) allow(domain string) bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.domains[domain]
+	if !ok || state.failures < b.threshold {
+		return true
+	}
+	if state.probing || time.Since(state.openedAt) < b.cooldown {
+		return false
+	}
+	state.probing = true
+	return true
+}
+
+// record updates domain's circuit with the outcome of a probe
+func (b *domainBreaker) record(domain string, failed bool) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.prune(now)
+
+	state, ok := b.domains[domain]
+	if ok && b.stale(state, now) {
+		ok = false
+	}
+	if !failed {
+		if ok && state.failures >= b.threshold {
+			log.Printf("SMTP circuit for %s closed, probing resumed", domain)
+		}
+		delete(b.domains, domain)
+		return
+	}
+
+	if !ok {
+		if b.domains == nil {
+			b.domains = make(map[string]*domainBreakerState)
+		}
+		state = &domainBreakerState{}
+		b.domains[domain] = state
+	}
+	state.failures++
+	state.lastFailure = now
+	state.probing = false
+	if state.failures >= b.threshold {
+		if state.failures == b.threshold {
+			log.Printf("SMTP circuit for %s opened after %d failed probes; skipping probes for %s", domain, state.failures, b.cooldown)
+		}
+		state.openedAt = now
+	}
+}
+
+// stale reports whether state's failures should be forgotten at now. b.mu must be held.
+func (b *domainBreaker) stale(state *domainBreakerState, now time.Time) bool {
+	if state.probing {
+		return false
+	}
+	if state.failures < b.threshold {
+		return now.Sub(state.lastFailure) >= b.cooldown
+	}
+	return now.Sub(state.openedAt) >= 2*b.cooldown
+}
+
+// prune drops the stale domains, at most once per cooldown. b.mu must be held.
+func (b *domainBreaker) prune(now time.Time) {
+	if now.Sub(b.lastPrune) < b.cooldown {
+		return
+	}
+	b.lastPrune = now
+	for domain, state := range b.domains {
+		if b.stale(state, now) {
+			delete(b.domains, domain)
+		}
+	}
+}
+
+// release lets the next probe of a domain whose circuit is half-open through again, after
+// the probe it allowed was not made
+func (b *domainBreaker) release(domain string) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if state, ok := b.domains[domain]; ok {
+		state.probing = false
+	}
+}
+
+// probeFailed reports whether result shows the domain's mail servers misbehaving: none
+// could be reached, or the conversation broke off without a reply (e.g. a timeout while
+// tarpitting). Any SMTP reply, even a rejection, means the servers are responsive.
+func probeFailed(result SMTPResult) bool {
+	switch result.Status {
+	case SMTPStatusAllMXUnreachable:
+		return true
+	case SMTPStatusUnknown:
+		return result.Code == 0
+	default:
+		return false
+	}
+}
+
+// circuitOpenResult is returned in place of a probe skipped by the breaker
+func circuitOpenResult(from string) SMTPResult {
+	monitoring.RecordSMTPCircuitOpen()
+	return SMTPResult{
+		Status:  SMTPStatusCircuitOpen,
+		Sender:  from,
+		Message: "circuit open: the domain's mail servers failed repeated probes",
+	}
+}
@@ -0,0 +1,174 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"emailvalidator/pkg/monitoring"
+)
+
+// DefaultSMTPProbeRateWait is how long a probe may wait for the rate governor's budget
+// before it is skipped
+const DefaultSMTPProbeRateWait = 2 * time.Second
+
+// SMTPStatusRateLimited means the probe was skipped because the probe rate budget was
+// exhausted. The mailbox check is inconclusive.
+const SMTPStatusRateLimited SMTPStatus = "RATE_LIMITED"
+
+// globalRateLimit names the process-wide limit in metrics
+const globalRateLimit = "global"
+
+// tokenBucket refills at rate tokens per second up to burst. Tokens may go negative: a
+// caller that takes a token before one is available has reserved the next one to arrive.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket for perMinute tokens a minute. It holds ten
+// seconds' worth, so bursts stay short.
+func newTokenBucket(perMinute int, now time.Time) *tokenBucket {
+	burst := max(1, float64(perMinute)/6)
+	return &tokenBucket{rate: float64(perMinute) / 60, burst: burst, tokens: burst, last: now}
+}
+
+// delay refills the bucket up to now and returns how long until a token is available
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// providerLimit is the bucket of a per-provider sub-limit
+type providerLimit struct {
+	domain string
+	bucket *tokenBucket
+}
+
+// SMTPGovernor limits how many SMTP probes are made per minute, across every request
+// and batch sharing it, with optional lower limits for individual providers. A probe
+// that cannot get a token within the maximum wait is skipped rather than queued, so a
+// busy process reports inconclusive mailboxes instead of blowing its deadlines.
+type SMTPGovernor struct {
+	maxWait time.Duration
+
+	mu        sync.Mutex
+	global    *tokenBucket
+	providers []providerLimit
+}
+
+// NewSMTPGovernor creates a governor allowing perMinute probes a minute in total, or any
+// number when perMinute is zero. providers caps the probes per minute to a provider,
+// keyed by domain: a limit applies to recipients at the domain or its subdomains and to
+// domains whose preferred mail server is under it, so "google.com" covers Gmail and
+// Google Workspace alike. A limit on the recipient's domain takes precedence over one on
+// its mail server. A probe waits up to maxWait for its budget.
+func NewSMTPGovernor(perMinute int, providers map[string]int, maxWait time.Duration) *SMTPGovernor {
+	now := time.Now()
+	g := &SMTPGovernor{maxWait: maxWait}
+	if perMinute > 0 {
+		g.global = newTokenBucket(perMinute, now)
+	}
+	for domain, limit := range providers {
+		if limit > 0 {
+			g.providers = append(g.providers, providerLimit{domain: normalizeDomain(domain), bucket: newTokenBucket(limit, now)})
+		}
+	}
+	return g
+}
+
+// Acquire takes a probe token for a recipient at domain whose preferred mail server is
+// mxHost, waiting for it if one becomes available within the maximum wait. It reports
+// false, taking nothing, when the budget is exhausted for longer; the limit that was
+// exhausted is counted in email_validator_smtp_rate_limited_total.
+func (g *SMTPGovernor) Acquire(domain, mxHost string) bool {
+	g.mu.Lock()
+	now := time.Now()
+	var (
+		wait    time.Duration
+		limit   string
+		buckets []*tokenBucket
+	)
+	if g.global != nil {
+		wait, limit = g.global.delay(now), globalRateLimit
+		buckets = append(buckets, g.global)
+	}
+	if provider := g.provider(domain, mxHost); provider != nil {
+		if delay := provider.bucket.delay(now); delay > wait {
+			wait, limit = delay, provider.domain
+		}
+		buckets = append(buckets, provider.bucket)
+	}
+	if wait > g.maxWait {
+		g.mu.Unlock()
+		monitoring.RecordSMTPRateLimited(limit)
+		return false
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	g.mu.Unlock()
+
+	time.Sleep(wait)
+	return true
+}
+
+// provider returns the most specific provider limit covering domain or, failing that,
+// mxHost, or nil
+func (g *SMTPGovernor) provider(domain, mxHost string) *providerLimit {
+	for _, name := range []string{normalizeDomain(domain), normalizeDomain(mxHost)} {
+		var match *providerLimit
+		for i := range g.providers {
+			p := &g.providers[i]
+			if domainUnder(name, p.domain) && (match == nil || len(p.domain) > len(match.domain)) {
+				match = p
+			}
+		}
+		if match != nil {
+			return match
+		}
+	}
+	return nil
+}
+
+// domainUnder reports whether domain is parent or one of its subdomains
+func domainUnder(domain, parent string) bool {
+	return domain == parent || strings.HasSuffix(domain, "."+parent)
+}
+
+// ParseSMTPProviderRates parses per-provider probe limits written as comma-separated
+// domain=probes-per-minute pairs, e.g. "google.com=60,outlook.com=120"
+func ParseSMTPProviderRates(s string) (map[string]int, error) {
+	rates := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		domain, value, ok := strings.Cut(pair, "=")
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		domain = normalizeDomain(strings.TrimSpace(domain))
+		if !ok || err != nil || limit <= 0 || domain == "" {
+			return nil, fmt.Errorf("invalid SMTP provider rate %q: must be domain=probes per minute", pair)
+		}
+		rates[domain] = limit
+	}
+	return rates, nil
+}
+
+// rateLimitedResult is returned in place of a probe skipped by the governor
+func rateLimitedResult(from string) SMTPResult {
+	return SMTPResult{
+		Status:  SMTPStatusRateLimited,
+		Sender:  from,
+		Message: "probe rate limit reached: the probe budget is exhausted",
+	}
+}
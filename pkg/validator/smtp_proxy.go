@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// SOCKS5Proxy is a SOCKS5 proxy that SMTP probes connect to mail servers through
+type SOCKS5Proxy struct {
+	// Address is the host:port of the proxy
+	Address string
+	// Username and Password authenticate with the proxy; an empty Username connects
+	// without authentication
+	Username string
+	Password string
+}
+
+// ParseSOCKS5Proxy parses a proxy given as socks5://[user:password@]host:port
+func ParseSOCKS5Proxy(value string) (SOCKS5Proxy, error) {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return SOCKS5Proxy{}, fmt.Errorf("invalid SOCKS5 proxy %q: %w", value, err)
+	}
+	if parsed.Scheme != "socks5" && parsed.Scheme != "socks5h" {
+		return SOCKS5Proxy{}, fmt.Errorf("invalid SOCKS5 proxy %q: scheme must be socks5", value)
+	}
+	if parsed.Hostname() == "" || parsed.Port() == "" {
+		return SOCKS5Proxy{}, fmt.Errorf("invalid SOCKS5 proxy %q: must include a host and port", value)
+	}
+
+	p := SOCKS5Proxy{Address: parsed.Host}
+	if parsed.User != nil {
+		p.Username = parsed.User.Username()
+		p.Password, _ = parsed.User.Password()
+	}
+	return p, nil
+}
+
+// String returns the proxy address, without credentials
+func (p SOCKS5Proxy) String() string {
+	return "socks5://" + p.Address
+}
+
+// WithSOCKS5Proxy routes connections to mail servers through a SOCKS5 proxy, so probes
+// come from the proxy's address rather than this host's, e.g. one with a PTR record and a
+// reputation mail servers accept. Mail server names are resolved by the proxy. With
+// WithSourceIP, the connection to the proxy is bound to the source IP. The HELO name
+// should match the proxy's address; the default is derived from this host instead.
+func WithSOCKS5Proxy(p SOCKS5Proxy) SMTPValidatorOption {
+	return func(v *SMTPValidator) {
+		v.proxy = &p
+	}
+}
+
+// Proxy returns the SOCKS5 proxy connections go through, or nil if they are made directly
+func (v *SMTPValidator) Proxy() *SOCKS5Proxy {
+	return v.proxy
+}
+
+// socks5DialFunc returns a DialFunc that connects through p, reaching the proxy with
+// forward. The timeout covers both reaching the proxy and its handshake.
+func socks5DialFunc(p SOCKS5Proxy, forward DialFunc) DialFunc {
+	var auth *proxy.Auth
+	if p.Username != "" {
+		auth = &proxy.Auth{User: p.Username, Password: p.Password}
+	}
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dialer, err := proxy.SOCKS5("tcp", p.Address, auth, forwardDialer{dial: forward, timeout: timeout})
+		if err != nil {
+			return nil, fmt.Errorf("SOCKS5 proxy %s: %w", p.Address, err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, network, address)
+		if err != nil {
+			return nil, fmt.Errorf("SOCKS5 proxy %s: %w", p.Address, err)
+		}
+		return conn, nil
+	}
+}
+
+// forwardDialer adapts a DialFunc to the proxy.Dialer the SOCKS5 client reaches the proxy with
+type forwardDialer struct {
+	dial    DialFunc
+	timeout time.Duration
+}
+
+// Dial implements proxy.Dialer
+func (d forwardDialer) Dial(network, address string) (net.Conn, error) {
+	return d.dial(network, address, d.timeout)
+}
@@ -0,0 +1,566 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// NullSender is the null reverse-path. Configuring it as the sender makes the
+// validator send "MAIL FROM:<>", as bounce messages do.
+const NullSender = "<>"
+
+// senderLocalPart is the local part used when the configured sender is a bare domain
+const senderLocalPart = "verify"
+
+// redactedLocalPart replaces the sender's local part in transcripts
+const redactedLocalPart = "***"
+
+// Default SMTP timeouts
+const (
+	// DefaultSMTPConnectTimeout is the time allowed for connecting to a mail server
+	DefaultSMTPConnectTimeout = 10 * time.Second
+	// DefaultSMTPCommandTimeout is the time allowed for each reply once connected,
+	// including the greeting
+	DefaultSMTPCommandTimeout = 10 * time.Second
+)
+
+// SMTPStatus is the outcome of an SMTP mailbox probe
+type SMTPStatus string
+
+// Possible SMTP probe outcomes
+const (
+	SMTPStatusDeliverable    SMTPStatus = "DELIVERABLE"
+	SMTPStatusUndeliverable  SMTPStatus = "UNDELIVERABLE"
+	SMTPStatusSenderRejected SMTPStatus = "SENDER_REJECTED"
+	SMTPStatusUnknown        SMTPStatus = "UNKNOWN"
+	// SMTPStatusGreylisted means the server temporarily deferred the recipient (450/451),
+	// as greylisting servers do for unfamiliar senders. A later retry usually succeeds.
+	SMTPStatusGreylisted SMTPStatus = "GREYLISTED"
+	// SMTPStatusAllMXUnreachable means no mail server for the domain accepted a connection
+	SMTPStatusAllMXUnreachable SMTPStatus = "ALL_MX_UNREACHABLE"
+	// SMTPStatusSMTPUTF8Unsupported means the address has a non-ASCII local part but the
+	// server does not advertise SMTPUTF8, so the mailbox could not be asked about
+	SMTPStatusSMTPUTF8Unsupported SMTPStatus = "SMTPUTF8_UNSUPPORTED"
+)
+
+// SMTPResult describes the outcome of an SMTP mailbox probe
+type SMTPResult struct {
+	Status      SMTPStatus
+	Host        string   // Mail server that answered; empty if none did
+	Unreachable []string // Mail servers tried before Host that could not be reached
+	Sender      string   // Reverse-path sent in MAIL FROM; empty for the null sender
+	CatchAll    bool     // The server also accepted a random recipient, so acceptance proves little
+	Code        int      // Last SMTP reply code, or 0 if the server never replied
+	Message     string   // Last SMTP reply text or connection error
+	Transcript  []string // Every line exchanged with the mail servers, with the sender redacted; only set when requested in SMTPOptions
+}
+
+// DialFunc opens a connection to a mail server
+type DialFunc func(network, address string, timeout time.Duration) (net.Conn, error)
+
+// SMTPOptions holds per-call overrides for an SMTP probe
+type SMTPOptions struct {
+	// Sender overrides the configured MAIL FROM sender. It may be an address,
+	// a bare domain, or NullSender. Empty uses the configured sender.
+	Sender string
+	// Transcript records the full SMTP conversation in the result, for debugging. The
+	// sender's local part is redacted wherever it appears.
+	Transcript bool
+	// ConnectTimeout overrides the configured connect timeout; zero uses the configured one
+	ConnectTimeout time.Duration
+	// CommandTimeout overrides the configured command timeout; zero uses the configured one
+	CommandTimeout time.Duration
+}
+
+// SMTPValidator checks whether a mailbox exists by asking the domain's mail server
+// to accept it as a recipient, without sending a message.
+//
+// Many servers judge the MAIL FROM sender before answering RCPT TO: an empty or
+// unverifiable sender domain (no SPF, no MX, mismatched with the HELO name) is often
+// rejected outright, and the probe then says nothing about the mailbox. Configure a
+// sender on a domain you control with valid DNS to get accurate answers.
+type SMTPValidator struct {
+	resolver   DNSResolver
+	heloDomain string
+	sender     string
+	port       int
+	timeouts   smtpTimeouts
+	sourceIP   net.IP
+	proxy      *SOCKS5Proxy
+	dial       DialFunc
+	mxWalkUp   int
+	breaker    domainBreaker
+	governor   *SMTPGovernor
+}
+
+// SMTPValidatorOption configures an SMTPValidator when it is created
+type SMTPValidatorOption func(*SMTPValidator)
+
+// WithSourceIP binds outbound connections to ip, which must be an address of this host.
+// On multi-homed hosts, use the address whose PTR record and SPF authorization mail
+// servers will check; connections from any other address are often rejected.
+func WithSourceIP(ip net.IP) SMTPValidatorOption {
+	return func(v *SMTPValidator) {
+		v.sourceIP = ip
+		v.dial = sourceDialFunc(ip)
+	}
+}
+
+// NewSMTPValidator creates a new SMTPValidator that greets servers with heloDomain and
+// uses sender for MAIL FROM. sender may be an address, a bare domain (expanded to
+// verify@domain), or NullSender. An empty heloDomain defaults to the reverse DNS name of
+// the source IP or the host name; use PreflightHELO to check that the name will be accepted.
+func NewSMTPValidator(resolver DNSResolver, heloDomain, sender string, opts ...SMTPValidatorOption) *SMTPValidator {
+	v := &SMTPValidator{
+		resolver:   resolver,
+		heloDomain: heloDomain,
+		sender:     sender,
+		port:       25,
+		timeouts:   smtpTimeouts{connect: DefaultSMTPConnectTimeout, command: DefaultSMTPCommandTimeout},
+		dial:       net.DialTimeout,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.proxy != nil {
+		v.dial = socks5DialFunc(*v.proxy, v.dial)
+	}
+	if v.heloDomain == "" {
+		v.heloDomain = DefaultHELOName(v.sourceIP)
+	}
+	return v
+}
+
+// sourceDialFunc returns a DialFunc whose connections originate from ip
+func sourceDialFunc(ip net.IP) DialFunc {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dialer := net.Dialer{
+			Timeout:   timeout,
+			LocalAddr: &net.TCPAddr{IP: ip},
+		}
+		return dialer.Dial(network, address)
+	}
+}
+
+// CheckSourceIP returns an error unless ip is assigned to one of this host's network
+// interfaces, since connections cannot be bound to any other address
+func CheckSourceIP(ip net.IP) error {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return nil
+		}
+	}
+	// The whole loopback range is routed to the loopback interface
+	if ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("source IP %s is not an address of this host", ip)
+}
+
+// HELOName returns the hostname sent in HELO/EHLO
+func (v *SMTPValidator) HELOName() string {
+	return v.heloDomain
+}
+
+// SourceIP returns the local address outbound connections are bound to, or nil if the
+// operating system chooses
+func (v *SMTPValidator) SourceIP() net.IP {
+	return v.sourceIP
+}
+
+// SetSender sets the default MAIL FROM sender
+func (v *SMTPValidator) SetSender(sender string) {
+	v.sender = sender
+}
+
+// SetPort sets the port used to connect to mail servers
+func (v *SMTPValidator) SetPort(port int) {
+	v.port = port
+}
+
+// SetTimeout sets both the connect timeout and the command timeout
+func (v *SMTPValidator) SetTimeout(timeout time.Duration) {
+	v.timeouts = smtpTimeouts{connect: timeout, command: timeout}
+}
+
+// SetConnectTimeout sets the time allowed for connecting to each mail server. A server
+// that does not accept the connection in time is skipped for the next one.
+func (v *SMTPValidator) SetConnectTimeout(timeout time.Duration) {
+	v.timeouts.connect = timeout
+}
+
+// SetCommandTimeout sets the time allowed for each server reply once connected, from the
+// greeting to the answer to the last RCPT TO
+func (v *SMTPValidator) SetCommandTimeout(timeout time.Duration) {
+	v.timeouts.command = timeout
+}
+
+// SetMXWalkUp makes the validator probe the mail servers of up to levels parent domains
+// when a domain has no MX records, as DomainValidator.SetMXWalkUp does for the MX check
+func (v *SMTPValidator) SetMXWalkUp(levels int) {
+	v.mxWalkUp = levels
+}
+
+// SetDialFunc sets the function used to connect to mail servers. It replaces the dialer
+// bound to the source IP and the SOCKS5 proxy, if either was configured.
+func (v *SMTPValidator) SetDialFunc(dial DialFunc) {
+	v.dial = dial
+}
+
+// SetCircuitBreaker makes the validator skip probes to a domain for cooldown after
+// threshold consecutive probes to it failed to get an answer, reporting
+// SMTPStatusCircuitOpen instead. A zero threshold disables the breaker, which is the default.
+func (v *SMTPValidator) SetCircuitBreaker(threshold int, cooldown time.Duration) {
+	v.breaker.mu.Lock()
+	defer v.breaker.mu.Unlock()
+	v.breaker.threshold = threshold
+	v.breaker.cooldown = cooldown
+	v.breaker.domains = nil
+}
+
+// SetGovernor makes probes take their budget from governor, which may be shared by
+// several validators. Probes the budget does not allow report SMTPStatusRateLimited. Pass
+// nil, the default, to probe without a rate limit.
+func (v *SMTPValidator) SetGovernor(governor *SMTPGovernor) {
+	v.governor = governor
+}
+
+// Verify probes the mailbox for email using the configured sender
+func (v *SMTPValidator) Verify(email string) SMTPResult {
+	return v.VerifyWithOptions(email, SMTPOptions{})
+}
+
+// VerifyWithOptions probes the mailbox for email, applying per-call overrides from opts
+func (v *SMTPValidator) VerifyWithOptions(email string, opts SMTPOptions) SMTPResult {
+	sender := v.sender
+	if opts.Sender != "" {
+		sender = opts.Sender
+	}
+	from := reversePath(sender)
+
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return SMTPResult{Status: SMTPStatusUnknown, Sender: from, Message: "invalid email address"}
+	}
+
+	domain := normalizeDomain(email[at+1:])
+	if !v.breaker.allow(domain) {
+		return circuitOpenResult(from)
+	}
+	result := v.verify(email, domain, from, v.timeouts.override(opts), opts.Transcript)
+	if result.Status == SMTPStatusRateLimited {
+		// A skipped probe says nothing about the domain's mail servers
+		v.breaker.release(domain)
+		return result
+	}
+	v.breaker.record(domain, probeFailed(result))
+	return result
+}
+
+// verify probes the mailbox for email at domain's mail servers, sending from as the reverse-path
+func (v *SMTPValidator) verify(email, domain, from string, timeouts smtpTimeouts, withTranscript bool) SMTPResult {
+	var transcript *[]string
+	if withTranscript {
+		transcript = &[]string{}
+	}
+
+	hosts := v.mailHosts(domain)
+	if len(hosts) == 0 {
+		return SMTPResult{Status: SMTPStatusUndeliverable, Sender: from, Message: "domain publishes a null MX and accepts no mail"}
+	}
+	if v.governor != nil && !v.governor.Acquire(domain, hosts[0]) {
+		return rateLimitedResult(from)
+	}
+
+	// Try mail servers in priority order until one accepts a connection
+	var unreachable []string
+	var lastErr string
+	for _, host := range hosts {
+		result, reachable := v.probe(host, email, from, timeouts, transcript)
+		if !reachable {
+			unreachable = append(unreachable, host)
+			lastErr = result.Message
+			continue
+		}
+		result.Host = host
+		result.Unreachable = unreachable
+		result.Sender = from
+		if transcript != nil {
+			result.Transcript = *transcript
+		}
+		return result
+	}
+
+	result := SMTPResult{
+		Status:      SMTPStatusAllMXUnreachable,
+		Unreachable: unreachable,
+		Sender:      from,
+		Message:     lastErr,
+	}
+	if transcript != nil {
+		result.Transcript = *transcript
+	}
+	return result
+}
+
+// mailHosts returns the domain's mail servers ordered by preference, or a parent's when
+// walking up is enabled, falling back to the domain itself when none publish MX records
+// (RFC 5321 implicit MX). A domain with a null MX (RFC 7505) has no mail servers.
+func (v *SMTPValidator) mailHosts(domain string) []string {
+	mxRecords, _, err := LookupMXWalkUp(v.resolver, domain, v.mxWalkUp)
+	if err != nil || len(mxRecords) == 0 {
+		return []string{domain}
+	}
+	if IsNullMX(mxRecords) {
+		return nil
+	}
+
+	sort.SliceStable(mxRecords, func(i, j int) bool {
+		return mxRecords[i].Pref < mxRecords[j].Pref
+	})
+
+	hosts := make([]string, 0, len(mxRecords))
+	for _, mx := range mxRecords {
+		hosts = append(hosts, strings.TrimSuffix(mx.Host, "."))
+	}
+	return hosts
+}
+
+// probe runs the SMTP conversation with host. reachable is false if the host could not
+// be connected to or did not greet us, in which case the next host should be tried.
+// When transcript is non-nil, every line exchanged with host is appended to it.
+func (v *SMTPValidator) probe(host, email, from string, timeouts smtpTimeouts, transcript *[]string) (result SMTPResult, reachable bool) {
+	address := net.JoinHostPort(host, strconv.Itoa(v.port))
+	if transcript != nil {
+		*transcript = append(*transcript, "* connecting to "+address)
+	}
+	conn, err := v.dial("tcp", address, timeouts.connect)
+	if err != nil {
+		if transcript != nil {
+			*transcript = append(*transcript, "* "+err.Error())
+		}
+		return SMTPResult{Status: SMTPStatusUnknown, Message: err.Error()}, false
+	}
+	if transcript != nil {
+		conn = &transcriptConn{Conn: conn, lines: transcript, sender: from}
+	}
+	// The greeting gets the same time as the reply to each command
+	if err := conn.SetDeadline(time.Now().Add(timeouts.command)); err != nil {
+		conn.Close()
+		return SMTPResult{Status: SMTPStatusUnknown, Message: err.Error()}, false
+	}
+	conn = &commandDeadlineConn{Conn: conn, timeout: timeouts.command}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return replyResult(SMTPStatusUnknown, err), false
+	}
+	defer client.Close()
+
+	if err := client.Hello(v.heloDomain); err != nil {
+		return replyResult(SMTPStatusUnknown, err), true
+	}
+
+	// Mail adds the SMTPUTF8 parameter itself when the server advertises the extension
+	smtputf8, _ := client.Extension("SMTPUTF8")
+	recipient, ok := recipientAddress(email, smtputf8)
+	if !ok {
+		_ = client.Quit()
+		return SMTPResult{
+			Status:  SMTPStatusSMTPUTF8Unsupported,
+			Message: "server does not support SMTPUTF8, which the address requires",
+		}, true
+	}
+
+	if err := client.Mail(from); err != nil {
+		return replyResult(SMTPStatusSenderRejected, err), true
+	}
+
+	if err := client.Rcpt(recipient); err != nil {
+		if isGreylistReply(err) {
+			result := replyResult(SMTPStatusUnknown, err)
+			result.Status = SMTPStatusGreylisted
+			return result, true
+		}
+		return replyResult(SMTPStatusUndeliverable, err), true
+	}
+
+	// A server that also accepts a made-up recipient accepts everything (catch-all)
+	catchAll := client.Rcpt(catchAllProbeAddress(recipient)) == nil
+
+	_ = client.Quit()
+	return SMTPResult{Status: SMTPStatusDeliverable, CatchAll: catchAll, Code: 250}, true
+}
+
+// recipientAddress returns the form of email to send in RCPT TO. With SMTPUTF8 the address
+// is sent as is; without it an internationalized domain is sent in punycode, and ok is false
+// if the local part is not ASCII, since such an address cannot be expressed at all.
+func recipientAddress(email string, smtputf8 bool) (recipient string, ok bool) {
+	if smtputf8 || isASCII(email) {
+		return email, true
+	}
+	at := strings.LastIndex(email, "@")
+	localPart := email[:at]
+	if !isASCII(localPart) {
+		return "", false
+	}
+	return localPart + "@" + normalizeDomain(email[at+1:]), true
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// smtpTimeouts are the time limits of an SMTP probe
+type smtpTimeouts struct {
+	connect time.Duration
+	command time.Duration
+}
+
+// override returns t with the timeouts set in opts in place of its own
+func (t smtpTimeouts) override(opts SMTPOptions) smtpTimeouts {
+	if opts.ConnectTimeout > 0 {
+		t.connect = opts.ConnectTimeout
+	}
+	if opts.CommandTimeout > 0 {
+		t.command = opts.CommandTimeout
+	}
+	return t
+}
+
+// commandDeadlineConn moves the connection deadline forward by timeout whenever a command
+// is written, so each reply gets the full command timeout however long the earlier ones took
+type commandDeadlineConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *commandDeadlineConn) Write(p []byte) (int, error) {
+	if err := c.Conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(p)
+}
+
+// transcriptConn records the lines read from and written to a connection,
+// prefixed "S: " for the server and "C: " for the client, with sender redacted
+type transcriptConn struct {
+	net.Conn
+	lines         *[]string
+	sender        string
+	read, written []byte
+}
+
+func (c *transcriptConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.read = c.record("S: ", append(c.read, p[:n]...))
+	return n, err
+}
+
+func (c *transcriptConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.written = c.record("C: ", append(c.written, p[:n]...))
+	return n, err
+}
+
+// record appends every complete line in buf to the transcript and returns the unfinished rest
+func (c *transcriptConn) record(prefix string, buf []byte) []byte {
+	for {
+		end := bytes.IndexByte(buf, '\n')
+		if end == -1 {
+			return buf
+		}
+		*c.lines = append(*c.lines, prefix+redactAddress(strings.TrimRight(string(buf[:end]), "\r"), c.sender))
+		buf = buf[end+1:]
+	}
+}
+
+// isGreylistReply reports whether err is a transient "try again later" reply to RCPT TO
+func isGreylistReply(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && (protoErr.Code == 450 || protoErr.Code == 451)
+}
+
+// catchAllProbeAddress returns a random address at email's domain that is very unlikely to exist
+func catchAllProbeAddress(email string) string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return "catchall-" + hex.EncodeToString(buf) + email[strings.LastIndex(email, "@"):]
+}
+
+// replyResult converts an SMTP error into a result. Permanent (5xx) replies map to
+// status; transient replies and connection errors are reported as unknown.
+func replyResult(status SMTPStatus, err error) SMTPResult {
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) {
+		return SMTPResult{Status: SMTPStatusUnknown, Message: err.Error()}
+	}
+	if protoErr.Code < 500 {
+		status = SMTPStatusUnknown
+	}
+	return SMTPResult{Status: status, Code: protoErr.Code, Message: protoErr.Msg}
+}
+
+// RedactSender returns the reverse-path sender with its local part masked, e.g.
+// "***@example.com", keeping the domain that servers judge senders by. The null sender
+// is returned unchanged.
+func RedactSender(sender string) string {
+	at := strings.LastIndex(sender, "@")
+	if at == -1 {
+		return sender
+	}
+	return redactedLocalPart + sender[at:]
+}
+
+// redactAddress replaces every occurrence of address in line, ignoring case, with its
+// redacted form, so servers echoing the sender back do not reveal it either
+func redactAddress(line, address string) string {
+	if address == "" {
+		return line
+	}
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		if i+len(address) <= len(line) && strings.EqualFold(line[i:i+len(address)], address) {
+			b.WriteString(RedactSender(address))
+			i += len(address)
+			continue
+		}
+		b.WriteByte(line[i])
+		i++
+	}
+	return b.String()
+}
+
+// reversePath converts a configured sender into the MAIL FROM reverse-path
+func reversePath(sender string) string {
+	switch {
+	case sender == "" || sender == NullSender:
+		return ""
+	case !strings.Contains(sender, "@"):
+		return senderLocalPart + "@" + sender
+	default:
+		return sender
+	}
+}
@@ -0,0 +1,283 @@
+package validator
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SyntaxMode selects the rules an address must follow to pass syntax validation
+type SyntaxMode string
+
+// Supported syntax modes
+const (
+	// SyntaxModeLenient accepts what mainstream mailbox providers accept: dot-atom local
+	// parts, including internationalized (UTF-8) ones, at a domain name. Quoted strings,
+	// comments and IP address literals are rejected. This is the default.
+	SyntaxModeLenient SyntaxMode = "lenient"
+	// SyntaxModeRFC5322 accepts the addr-spec of RFC 5322: quoted-string local parts,
+	// domain literals, and comments and folding whitespace around the address's atoms.
+	// Only ASCII is accepted.
+	SyntaxModeRFC5322 SyntaxMode = "rfc5322"
+	// SyntaxModeRFC5321Strict accepts exactly the mailbox an SMTP client may send in
+	// RCPT TO (RFC 5321 section 4.1.2): quoted strings and IPv4 or IPv6 address literals,
+	// but no comments or whitespace, and a hostname of letters, digits and hyphens. Only
+	// ASCII is accepted.
+	SyntaxModeRFC5321Strict SyntaxMode = "rfc5321"
+)
+
+// ParseSyntaxMode converts a configuration string into a SyntaxMode
+func ParseSyntaxMode(value string) (SyntaxMode, error) {
+	switch SyntaxMode(strings.ToLower(value)) {
+	case "", SyntaxModeLenient:
+		return SyntaxModeLenient, nil
+	case SyntaxModeRFC5322:
+		return SyntaxModeRFC5322, nil
+	case SyntaxModeRFC5321Strict:
+		return SyntaxModeRFC5321Strict, nil
+	default:
+		return "", fmt.Errorf("syntax mode %q: must be %q, %q or %q",
+			value, SyntaxModeLenient, SyntaxModeRFC5322, SyntaxModeRFC5321Strict)
+	}
+}
+
+// specials are the atext characters allowed besides letters and digits (RFC 5322 section 3.2.3)
+const specials = "!#$%&'*+-/=?^_`{|}~"
+
+// isAtext reports whether c may appear in an atom
+func isAtext(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte(specials, c) != -1
+}
+
+// StripCFWS returns email with the comments and folding whitespace around its atoms
+// removed, e.g. "jane(work)@example.com" becomes "jane@example.com". Quoted strings and
+// domain literals are kept as they are. Only addresses accepted in SyntaxModeRFC5322 can
+// contain CFWS; anything else is returned unchanged.
+func StripCFWS(email string) string {
+	stripped, ok := stripCFWS(email)
+	if !ok {
+		return email
+	}
+	return stripped
+}
+
+// stripCFWS removes comments and whitespace that sit between the tokens of an address.
+// It reports false for unbalanced comments or quotes and for CFWS inside an atom.
+func stripCFWS(email string) (string, bool) {
+	var b strings.Builder
+	depth := 0    // Nesting depth of the current comment
+	gap := false  // CFWS seen since the last significant character
+	var last byte // Last significant character written
+	inQuotes, inLiteral, escaped := false, false, false
+
+	for i := 0; i < len(email); i++ {
+		c := email[i]
+		switch {
+		case escaped:
+			escaped = false
+			if depth == 0 {
+				b.WriteByte(c)
+			}
+			continue
+		case c == '\\' && (inQuotes || depth > 0):
+			escaped = true
+			if depth == 0 {
+				b.WriteByte(c)
+			}
+			continue
+		case inQuotes:
+			b.WriteByte(c)
+			if c == '"' {
+				inQuotes, last = false, c
+			}
+			continue
+		case inLiteral:
+			b.WriteByte(c)
+			if c == ']' {
+				inLiteral, last = false, c
+			}
+			continue
+		case c == '(':
+			depth++
+			gap = true
+			continue
+		case c == ')':
+			if depth == 0 {
+				return "", false
+			}
+			depth--
+			continue
+		case depth > 0:
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			gap = true
+			continue
+		}
+
+		// CFWS may separate atoms from dots and the "@", but may not split an atom
+		if gap && last != 0 && last != '.' && last != '@' && c != '.' && c != '@' {
+			return "", false
+		}
+		gap = false
+		switch c {
+		case '"':
+			inQuotes = true
+		case '[':
+			inLiteral = true
+		}
+		b.WriteByte(c)
+		last = c
+	}
+
+	if depth > 0 || inQuotes || inLiteral || escaped {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// checkModeFeatures rejects the comments, quoted strings and address literals that mode does
+// not allow. It returns the address with comments and folding whitespace removed.
+func checkModeFeatures(email string, mode SyntaxMode) (string, SyntaxViolation) {
+	hasComment := strings.ContainsAny(email, "()")
+	if mode == SyntaxModeRFC5322 {
+		stripped, ok := stripCFWS(email)
+		if !ok {
+			return email, ViolationMalformed
+		}
+		email = stripped
+	} else if hasComment {
+		return email, ViolationComment
+	}
+
+	if mode == SyntaxModeLenient {
+		if strings.HasPrefix(email[strings.LastIndex(email, "@")+1:], "[") {
+			return email, ViolationAddressLiteral
+		}
+		return email, ViolationNone
+	}
+
+	for i := 0; i < len(email); i++ {
+		if email[i] >= 0x80 {
+			return email, ViolationNonASCII
+		}
+	}
+	return email, ViolationNone
+}
+
+// checkRFCAddress validates an address without CFWS against the grammar of mode, which is
+// SyntaxModeRFC5322 or SyntaxModeRFC5321Strict
+func checkRFCAddress(email string, mode SyntaxMode) SyntaxViolation {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ViolationMalformed
+	}
+	localPart, domain := email[:at], email[at+1:]
+	if localPart == "" || domain == "" {
+		return ViolationMalformed
+	}
+
+	if strings.HasPrefix(localPart, `"`) {
+		if !isQuotedString(localPart, mode) {
+			return ViolationMalformed
+		}
+	} else if !isDotAtom(localPart) {
+		return ViolationMalformed
+	}
+
+	if strings.HasPrefix(domain, "[") {
+		if !isAddressLiteral(domain, mode) {
+			return ViolationMalformed
+		}
+		return ViolationNone
+	}
+	if mode == SyntaxModeRFC5321Strict {
+		if !isHostname(domain) {
+			return ViolationMalformed
+		}
+		return ViolationNone
+	}
+	if !isDotAtom(domain) {
+		return ViolationMalformed
+	}
+	return ViolationNone
+}
+
+// isDotAtom reports whether s is atoms of atext joined by single dots
+func isDotAtom(s string) bool {
+	for _, atom := range strings.Split(s, ".") {
+		if atom == "" {
+			return false
+		}
+		for i := 0; i < len(atom); i++ {
+			if !isAtext(atom[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isQuotedString reports whether s is one quoted string of printable ASCII. RFC 5322 also
+// allows tabs in quoted strings; RFC 5321 does not.
+func isQuotedString(s string, mode SyntaxMode) bool {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return false
+	}
+	for i := 1; i < len(s)-1; i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			i++
+			if i == len(s)-1 || s[i] < ' ' || s[i] > '~' {
+				return false
+			}
+		case c == '"':
+			return false
+		case c == '\t' && mode == SyntaxModeRFC5322:
+		case c < ' ' || c > '~':
+			return false
+		}
+	}
+	return true
+}
+
+// isAddressLiteral reports whether s is a bracketed IPv4 address or "IPv6:" address. RFC 5322
+// also accepts any other bracketed text without brackets or backslashes.
+func isAddressLiteral(s string, mode SyntaxMode) bool {
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return false
+	}
+	content := s[1 : len(s)-1]
+	if ip := net.ParseIP(content); ip != nil && ip.To4() != nil && !strings.Contains(content, ":") {
+		return true
+	}
+	if ipv6, ok := strings.CutPrefix(content, "IPv6:"); ok {
+		ip := net.ParseIP(ipv6)
+		return ip != nil && strings.Contains(ipv6, ":")
+	}
+	if mode != SyntaxModeRFC5322 || content == "" {
+		return false
+	}
+	for i := 0; i < len(content); i++ {
+		if c := content[i]; c < '!' || c > '~' || c == '[' || c == ']' || c == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+// isHostname reports whether s is labels of letters, digits and inner hyphens joined by dots
+func isHostname(s string) bool {
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -6,59 +6,211 @@ import (
 	"strings"
 )
 
+// Length limits from RFC 5321 section 4.5.3.1, measured in octets
+const (
+	maxAddressLength   = 254
+	maxLocalPartLength = 64
+	maxDomainLength    = 255
+	maxLabelLength     = 63
+)
+
+// SyntaxViolation identifies why an email address failed syntax validation
+type SyntaxViolation string
+
+// Possible syntax violations
+const (
+	ViolationNone             SyntaxViolation = ""
+	ViolationEmpty            SyntaxViolation = "EMPTY"
+	ViolationMalformed        SyntaxViolation = "MALFORMED"
+	ViolationQuotedString     SyntaxViolation = "QUOTED_STRING"
+	ViolationAddressTooLong   SyntaxViolation = "ADDRESS_TOO_LONG"
+	ViolationLocalPartTooLong SyntaxViolation = "LOCAL_PART_TOO_LONG"
+	ViolationDomainTooLong    SyntaxViolation = "DOMAIN_TOO_LONG"
+	ViolationLabelTooLong     SyntaxViolation = "DOMAIN_LABEL_TOO_LONG"
+	ViolationLeadingDot       SyntaxViolation = "LOCAL_PART_LEADING_DOT"
+	ViolationTrailingDot      SyntaxViolation = "LOCAL_PART_TRAILING_DOT"
+	ViolationConsecutiveDots  SyntaxViolation = "LOCAL_PART_CONSECUTIVE_DOTS"
+	ViolationComment          SyntaxViolation = "COMMENT"
+	ViolationAddressLiteral   SyntaxViolation = "ADDRESS_LITERAL"
+	ViolationNonASCII         SyntaxViolation = "NON_ASCII"
+)
+
+var violationDescriptions = map[SyntaxViolation]string{
+	ViolationEmpty:            "email address is empty",
+	ViolationMalformed:        "email address is malformed",
+	ViolationQuotedString:     "quoted strings are not supported",
+	ViolationAddressTooLong:   "email address exceeds 254 octets",
+	ViolationLocalPartTooLong: "local part exceeds 64 octets",
+	ViolationDomainTooLong:    "domain exceeds 255 octets",
+	ViolationLabelTooLong:     "domain label exceeds 63 octets",
+	ViolationLeadingDot:       "local part starts with a dot",
+	ViolationTrailingDot:      "local part ends with a dot",
+	ViolationConsecutiveDots:  "local part contains consecutive dots",
+	ViolationComment:          "comments are only accepted in rfc5322 syntax mode",
+	ViolationAddressLiteral:   "IP address literals are not accepted in lenient syntax mode",
+	ViolationNonASCII:         "non-ASCII characters are only accepted in lenient syntax mode",
+}
+
+// Description returns a human-readable explanation of the violation
+func (v SyntaxViolation) Description() string {
+	return violationDescriptions[v]
+}
+
 // SyntaxValidator handles email syntax validation
 type SyntaxValidator struct {
 	// Regex to detect quoted strings
 	quotedStringCheck *regexp.Regexp
+	mode              SyntaxMode
 }
 
-// NewSyntaxValidator creates a new instance of SyntaxValidator
+// NewSyntaxValidator creates a new instance of SyntaxValidator using SyntaxModeLenient
 func NewSyntaxValidator() *SyntaxValidator {
 	return &SyntaxValidator{
 		// Regex to detect quoted strings in local part
 		quotedStringCheck: regexp.MustCompile(`"[^"]*"`),
+		mode:              SyntaxModeLenient,
 	}
 }
 
+// SetMode sets the syntax mode used by Validate and Check
+func (v *SyntaxValidator) SetMode(mode SyntaxMode) {
+	v.mode = mode
+}
+
 // Validate checks if the email address format is valid
 func (v *SyntaxValidator) Validate(email string) bool {
+	return v.Check(email) == ViolationNone
+}
+
+// Check validates the email address format and returns the first violation found,
+// or ViolationNone if the address is valid
+func (v *SyntaxValidator) Check(email string) SyntaxViolation {
+	return v.CheckMode(email, v.mode)
+}
+
+// CheckMode validates the email address format under mode, or the configured mode if mode
+// is empty, and returns the first violation found
+func (v *SyntaxValidator) CheckMode(email string, mode SyntaxMode) SyntaxViolation {
+	if mode == "" {
+		mode = v.mode
+	}
 	if email == "" {
-		return false
+		return ViolationEmpty
+	}
+	if !strings.Contains(email, "@") {
+		return ViolationMalformed
+	}
+
+	email, violation := checkModeFeatures(email, mode)
+	if violation != ViolationNone {
+		return violation
+	}
+	// A fully-qualified domain ("example.com.") is the same domain
+	email = TrimTrailingDot(email)
+
+	// Stripping comments may have removed the only "@", as in "user.(@)example.com"
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ViolationMalformed
+	}
+
+	// Check local part and domain lengths before parsing, so oversized
+	// addresses are reported precisely rather than as malformed. The domain
+	// comes before the total length, which any domain over its limit exceeds too.
+	localPart, domain := email[:at], email[at+1:]
+	if len(domain) > maxDomainLength {
+		return ViolationDomainTooLong
 	}
 
 	// Check maximum length (RFC 5321)
-	if len(email) > 254 {
-		return false
+	if len(email) > maxAddressLength {
+		return ViolationAddressTooLong
+	}
+	if len(localPart) > maxLocalPartLength {
+		return ViolationLocalPartTooLong
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) > maxLabelLength {
+			return ViolationLabelTooLong
+		}
+	}
+
+	// Check dot placement in the unquoted portions of the local part
+	if violation := checkLocalPartDots(localPart); violation != ViolationNone {
+		return violation
+	}
+
+	if mode != SyntaxModeLenient {
+		return checkRFCAddress(email, mode)
 	}
 
 	// Check for quoted strings
 	if v.quotedStringCheck.MatchString(email) {
-		return false
+		return ViolationQuotedString
 	}
 
-	// Parse with net/mail
+	// Parse with net/mail, which also accepts display names and angle brackets around
+	// the address; only a bare address is accepted here
 	addr, err := mail.ParseAddress(email)
-	if err != nil {
-		return false
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return ViolationMalformed
 	}
 
-	// Check for consecutive dots
+	// Check for consecutive dots in the domain
 	if strings.Contains(addr.Address, "..") {
-		return false
+		return ViolationMalformed
 	}
 
 	// Split email into local part and domain
-	parts := strings.Split(addr.Address, "@")
-	if len(parts) != 2 {
-		return false
+	if len(strings.Split(addr.Address, "@")) != 2 {
+		return ViolationMalformed
 	}
 
-	localPart, domain := parts[0], parts[1]
+	return ViolationNone
+}
+
+// checkLocalPartDots rejects leading, trailing and consecutive dots outside quoted
+// strings. Dots inside quotes (e.g. "john..doe") are permitted by RFC 5322.
+func checkLocalPartDots(localPart string) SyntaxViolation {
+	inQuotes := false
+	escaped := false
+	prevDot := false
+
+	for i := 0; i < len(localPart); i++ {
+		c := localPart[i]
+
+		if inQuotes {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inQuotes = false
+			}
+			prevDot = false
+			continue
+		}
 
-	// Check local part and domain lengths
-	if len(localPart) > 64 || len(domain) > 255 {
-		return false
+		switch c {
+		case '"':
+			inQuotes = true
+			prevDot = false
+		case '.':
+			if i == 0 {
+				return ViolationLeadingDot
+			}
+			if prevDot {
+				return ViolationConsecutiveDots
+			}
+			if i == len(localPart)-1 {
+				return ViolationTrailingDot
+			}
+			prevDot = true
+		default:
+			prevDot = false
+		}
 	}
 
-	return true
+	return ViolationNone
 }
@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+// IANATLDListURL is the authoritative list of top-level domains in the root zone
+const IANATLDListURL = "https://data.iana.org/TLD/tlds-alpha-by-domain.txt"
+
+// minTLDCount guards against swapping in a truncated download; the root zone has well over a thousand TLDs
+const minTLDCount = 250
+
+// TLDList is a set of existing top-level domains, used to reject domains such as
+// example.qwerty without a DNS lookup. It can be refreshed at runtime.
+type TLDList struct {
+	tlds atomic.Pointer[map[string]struct{}]
+}
+
+// NewTLDList creates a TLDList from tlds, in any case, without leading dots
+func NewTLDList(tlds []string) *TLDList {
+	l := &TLDList{}
+	l.tlds.Store(newTLDSet(tlds))
+	return l
+}
+
+// NewTLDListFromFile creates a TLDList from a file in the IANA format: one TLD per
+// line, with "#" comment lines
+func NewTLDListFromFile(path string) (*TLDList, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tlds, err := PlaintextParser{}.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLD list: %w", err)
+	}
+	if len(tlds) == 0 {
+		return nil, fmt.Errorf("TLD list %s is empty", path)
+	}
+	return NewTLDList(tlds), nil
+}
+
+// DefaultTLDFile returns the path of the bundled config/tlds.txt
+func DefaultTLDFile() (string, error) {
+	return configFile("tlds.txt")
+}
+
+// Len returns the number of TLDs in the list
+func (l *TLDList) Len() int {
+	return len(*l.tlds.Load())
+}
+
+// Contains reports whether tld exists. Internationalized TLDs may be given in Unicode or punycode.
+func (l *TLDList) Contains(tld string) bool {
+	tld = strings.ToLower(strings.TrimSuffix(tld, "."))
+	if ascii, err := idna.ToASCII(tld); err == nil {
+		tld = ascii
+	}
+	_, ok := (*l.tlds.Load())[tld]
+	return ok
+}
+
+// HasKnownTLD reports whether the last label of domain is an existing TLD
+func (l *TLDList) HasKnownTLD(domain string) bool {
+	domain = strings.TrimSuffix(domain, ".")
+	return l.Contains(domain[strings.LastIndex(domain, ".")+1:])
+}
+
+// Update downloads the list at url and swaps it in. The current list is kept if the
+// download fails or looks truncated.
+func (l *TLDList) Update(client *http.Client, url string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch TLD list from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch TLD list from %s, status code: %d", url, resp.StatusCode)
+	}
+
+	tlds, err := PlaintextParser{}.Parse(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read TLD list from %s: %w", url, err)
+	}
+	if len(tlds) < minTLDCount {
+		return fmt.Errorf("TLD list from %s has only %d entries", url, len(tlds))
+	}
+
+	l.tlds.Store(newTLDSet(tlds))
+	return nil
+}
+
+// StartAutoUpdate refreshes the list from url with client every interval until ctx is
+// cancelled. A nil client uses DefaultHTTPClient. Failed updates are logged and the
+// current list is kept.
+func (l *TLDList) StartAutoUpdate(ctx context.Context, client *http.Client, url string, interval time.Duration) {
+	if client == nil {
+		client = DefaultHTTPClient()
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Update(client, url); err != nil {
+					log.Printf("Error updating TLD list, keeping %d TLDs: %v", l.Len(), err)
+					continue
+				}
+				log.Printf("Updated TLD list: %d TLDs.", l.Len())
+			}
+		}
+	}()
+}
+
+func newTLDSet(tlds []string) *map[string]struct{} {
+	set := make(map[string]struct{}, len(tlds))
+	for _, tld := range tlds {
+		set[strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tld), "."))] = struct{}{}
+	}
+	return &set
+}
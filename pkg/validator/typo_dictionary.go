@@ -0,0 +1,261 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GlobalTypoRegion is the file name, without extension, of a dictionary file that extends
+// the global dictionary rather than a region's
+const GlobalTypoRegion = "global"
+
+// globalTypoCorrections maps common misspellings of the world's largest providers to the
+// intended domain. They are suggested in every region.
+var globalTypoCorrections = map[string]string{
+	"gmial.com":  "gmail.com",
+	"gmal.com":   "gmail.com",
+	"gamil.com":  "gmail.com",
+	"gmai.com":   "gmail.com",
+	"gmail.co":   "gmail.com",
+	"gmail.cm":   "gmail.com",
+	"gmail.om":   "gmail.com",
+	"gmail.con":  "gmail.com",
+	"yaho.com":   "yahoo.com",
+	"yahooo.com": "yahoo.com",
+	"yahoo.co":   "yahoo.com",
+	"yahoo.cm":   "yahoo.com",
+	"hotmai.com": "hotmail.com",
+	"hotmal.com": "hotmail.com",
+	"hotmail.co": "hotmail.com",
+	"hotmail.cm": "hotmail.com",
+	"otmail.com": "hotmail.com",
+	"outlook.co": "outlook.com",
+	"outlook.cm": "outlook.com",
+	"outlok.com": "outlook.com",
+}
+
+// regionalTypoCorrections maps misspellings of providers popular in one region, keyed by
+// ISO 3166 country code. A region's corrections take precedence over the global ones.
+var regionalTypoCorrections = map[string]map[string]string{
+	"ru": {
+		"mail.ry":    "mail.ru",
+		"mai.ru":     "mail.ru",
+		"mial.ru":    "mail.ru",
+		"yandex.r":   "yandex.ru",
+		"yandx.ru":   "yandex.ru",
+		"yadex.ru":   "yandex.ru",
+		"ramber.ru":  "rambler.ru",
+		"rambler.r":  "rambler.ru",
+		"rambler.ry": "rambler.ru",
+	},
+	"cn": {
+		"qq.co":    "qq.com",
+		"qq.cm":    "qq.com",
+		"qq.con":   "qq.com",
+		"163.co":   "163.com",
+		"163.cm":   "163.com",
+		"126.co":   "126.com",
+		"sina.co":  "sina.com",
+		"sian.com": "sina.com",
+	},
+	"kr": {
+		"naver.co":    "naver.com",
+		"naver.cm":    "naver.com",
+		"navr.com":    "naver.com",
+		"nave.com":    "naver.com",
+		"daum.ne":     "daum.net",
+		"duam.net":    "daum.net",
+		"hanmail.ne":  "hanmail.net",
+		"hanmial.net": "hanmail.net",
+	},
+	"de": {
+		"gmx.d":      "gmx.de",
+		"gmx.dee":    "gmx.de",
+		"gmc.de":     "gmx.de",
+		"web.d":      "web.de",
+		"wbe.de":     "web.de",
+		"tonline.de": "t-online.de",
+		"t-onlin.de": "t-online.de",
+	},
+}
+
+// typoLanguageRegions maps languages whose usual region code differs from the language code
+// to that region, so Accept-Language: ko selects the "kr" dictionary
+var typoLanguageRegions = map[string]string{
+	"ko": "kr",
+	"zh": "cn",
+}
+
+// defaultTypoDictionaries returns a copy of the built-in dictionaries, the global one under ""
+func defaultTypoDictionaries() map[string]map[string]string {
+	dictionaries := make(map[string]map[string]string, len(regionalTypoCorrections)+1)
+	dictionaries[""] = copyTypoDictionary(globalTypoCorrections)
+	for region, corrections := range regionalTypoCorrections {
+		dictionaries[region] = copyTypoDictionary(corrections)
+	}
+	return dictionaries
+}
+
+func copyTypoDictionary(corrections map[string]string) map[string]string {
+	dictionary := make(map[string]string, len(corrections))
+	for typo, correction := range corrections {
+		dictionary[typo] = correction
+	}
+	return dictionary
+}
+
+// ParseTypoDictionary reads typo corrections, one "typo,correction" pair of domains per
+// line. Blank lines and lines starting with "#" are skipped; domains are lowercased.
+func ParseTypoDictionary(r io.Reader) (map[string]string, error) {
+	corrections := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		typo, correction, ok := strings.Cut(text, ",")
+		typo, correction = strings.ToLower(strings.TrimSpace(typo)), strings.ToLower(strings.TrimSpace(correction))
+		if !ok || !isTypoDictionaryDomain(typo) || !isTypoDictionaryDomain(correction) {
+			return nil, fmt.Errorf("line %d: expected \"typo,correction\", got %q", line, text)
+		}
+		corrections[typo] = correction
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return corrections, nil
+}
+
+// isTypoDictionaryDomain reports whether entry is a plain domain, without the wildcard and
+// suffix markers list entries may have
+func isTypoDictionaryDomain(entry string) bool {
+	return isValidListDomain(entry) && !strings.HasPrefix(entry, wildcardPrefix) && !strings.HasPrefix(entry, suffixPrefix)
+}
+
+// LoadTypoDictionaries reads every *.csv and *.txt file in dir as a typo dictionary for
+// the region its name gives, such as "de.csv" for Germany. The file "global.csv" extends
+// the global dictionary and is returned under "".
+func LoadTypoDictionaries(dir string) (map[string]map[string]string, error) {
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	dictionaries := make(map[string]map[string]string)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".csv" && ext != ".txt") {
+			continue
+		}
+		region := strings.ToLower(strings.TrimSuffix(entry.Name(), ext))
+		if region == GlobalTypoRegion {
+			region = ""
+		}
+
+		file, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		corrections, err := ParseTypoDictionary(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read typo dictionary %s: %w", entry.Name(), err)
+		}
+		if dictionaries[region] == nil {
+			dictionaries[region] = make(map[string]string, len(corrections))
+		}
+		for typo, correction := range corrections {
+			dictionaries[region][typo] = correction
+		}
+	}
+	return dictionaries, nil
+}
+
+// TypoRegionsFromAcceptLanguage returns the regions an Accept-Language header asks for, most
+// preferred first: for each language range, its region subtag and then its language, so
+// "de-AT;q=0.9, ko" gives "kr", "ko", "at", "de". Ranges with q=0 and "*" are skipped.
+func TypoRegionsFromAcceptLanguage(header string) []string {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" || quality <= 0 {
+			continue
+		}
+		ranges = append(ranges, languageRange{tag: tag, quality: quality})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	var regions []string
+	seen := make(map[string]bool)
+	add := func(region string) {
+		if region != "" && !seen[region] {
+			seen[region] = true
+			regions = append(regions, region)
+		}
+	}
+	for _, r := range ranges {
+		subtags := strings.FieldsFunc(r.tag, func(c rune) bool { return c == '-' || c == '_' })
+		for _, subtag := range subtags[1:] {
+			if len(subtag) == 2 {
+				add(subtag)
+				break
+			}
+		}
+		add(typoLanguageRegions[subtags[0]])
+		add(subtags[0])
+	}
+	return regions
+}
+
+// SetTypoDictionaries adds typo corrections to the dictionaries, keyed by region, with the
+// global dictionary under "". Corrections replace built-in ones for the same typo. It is
+// not safe to call concurrently with suggestions.
+func (v *EmailValidator) SetTypoDictionaries(dictionaries map[string]map[string]string) {
+	for region, corrections := range dictionaries {
+		region = strings.ToLower(region)
+		if v.typoDictionaries[region] == nil {
+			v.typoDictionaries[region] = make(map[string]string, len(corrections))
+		}
+		for typo, correction := range corrections {
+			v.typoDictionaries[region][strings.ToLower(typo)] = strings.ToLower(correction)
+		}
+	}
+}
+
+// TypoRegion returns the first of candidates that has a typo dictionary, or "" to use the
+// global dictionary only. Candidates are matched ignoring case.
+func (v *EmailValidator) TypoRegion(candidates ...string) string {
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if candidate == "" {
+			continue
+		}
+		if _, ok := v.typoDictionaries[candidate]; ok {
+			return candidate
+		}
+		if region, ok := typoLanguageRegions[candidate]; ok && v.typoDictionaries[region] != nil {
+			return region
+		}
+	}
+	return ""
+}
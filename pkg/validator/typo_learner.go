@@ -0,0 +1,263 @@
+package validator
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// decayEpoch is the fixed reference time for learned domain weights, shared by every instance
+var decayEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Defaults for TypoLearner
+const (
+	DefaultTypoLearningHalfLife = 30 * 24 * time.Hour
+	DefaultTypoSyncInterval     = time.Minute
+	defaultLearnedDomains       = 1000
+	defaultMinObservations      = 5
+	defaultMaxTypoDistance      = 1
+	// countTolerance absorbs rounding when decayed weights are converted back to counts
+	countTolerance = 1e-9
+)
+
+// DomainFrequencyStore persists how often each domain has been observed. Weights are
+// opaque scores that only grow; TypoLearner derives decayed counts from them.
+type DomainFrequencyStore interface {
+	IncrementDomains(ctx context.Context, increments map[string]float64) error
+	TopDomains(ctx context.Context, n int) (map[string]float64, error)
+}
+
+// learnedDomain is a domain in the learned dictionary with its decayed observation count
+type learnedDomain struct {
+	domain string
+	count  float64
+}
+
+// TypoLearner builds a frequency-weighted dictionary of domains that validated successfully
+// and suggests the most frequently seen close match for a mistyped domain.
+//
+// Observations decay with a half-life, so domains that stop appearing fade out. Decay needs
+// no coordination between instances: each observation is stored with weight
+// 2^((t-epoch)/halfLife), so a newer observation outweighs an older one, and counts are
+// recovered by dividing by the current weight.
+//
+// Learn only buffers observations in memory; Sync writes them to the store and reloads
+// the dictionary, so neither blocks validation on the store.
+type TypoLearner struct {
+	store           DomainFrequencyStore
+	halfLife        time.Duration
+	size            int
+	minObservations float64
+	maxDistance     int
+	now             func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]float64
+
+	dictionary atomic.Pointer[[]learnedDomain]
+}
+
+// NewTypoLearner creates a TypoLearner backed by store, whose observations lose half their
+// weight every halfLife
+func NewTypoLearner(store DomainFrequencyStore, halfLife time.Duration) *TypoLearner {
+	l := &TypoLearner{
+		store:           store,
+		halfLife:        halfLife,
+		size:            defaultLearnedDomains,
+		minObservations: defaultMinObservations,
+		maxDistance:     defaultMaxTypoDistance,
+		now:             time.Now,
+		pending:         make(map[string]float64),
+	}
+	l.dictionary.Store(&[]learnedDomain{})
+	return l
+}
+
+// SetMinObservations sets how many (decayed) observations a domain needs before it is suggested
+func (l *TypoLearner) SetMinObservations(n float64) {
+	l.minObservations = n
+}
+
+// SetMaxDistance sets the largest edit distance between a domain and a suggested correction
+func (l *TypoLearner) SetMaxDistance(distance int) {
+	l.maxDistance = distance
+}
+
+// SetClock sets the function used to read the current time
+func (l *TypoLearner) SetClock(now func() time.Time) {
+	l.now = now
+}
+
+// Learn records that domain validated successfully. It only buffers the observation;
+// call Sync (or Start) to write it to the store.
+func (l *TypoLearner) Learn(domain string) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if domain == "" {
+		return
+	}
+	l.mu.Lock()
+	l.pending[domain] += l.weight()
+	l.mu.Unlock()
+}
+
+// Suggest returns the most frequently observed learned domain within the maximum edit
+// distance of domain, or "" if there is none or domain is itself a learned domain
+func (l *TypoLearner) Suggest(domain string) string {
+	domain = strings.ToLower(domain)
+	best, bestCount := "", 0.0
+	for _, candidate := range *l.dictionary.Load() {
+		if candidate.domain == domain {
+			return ""
+		}
+		if candidate.count+countTolerance < l.minObservations || candidate.count <= bestCount {
+			continue
+		}
+		if abs(len(candidate.domain)-len(domain)) > l.maxDistance {
+			continue
+		}
+		if editDistance(domain, candidate.domain) <= l.maxDistance {
+			best, bestCount = candidate.domain, candidate.count
+		}
+	}
+	return best
+}
+
+// Sync writes buffered observations to the store and reloads the dictionary from it.
+// Observations that could not be written are kept for the next Sync.
+func (l *TypoLearner) Sync(ctx context.Context) error {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = make(map[string]float64)
+	l.mu.Unlock()
+
+	if len(pending) > 0 {
+		if err := l.store.IncrementDomains(ctx, pending); err != nil {
+			l.mu.Lock()
+			for domain, weight := range pending {
+				l.pending[domain] += weight
+			}
+			l.mu.Unlock()
+			return err
+		}
+	}
+
+	weights, err := l.store.TopDomains(ctx, l.size)
+	if err != nil {
+		return err
+	}
+	current := l.weight()
+	dictionary := make([]learnedDomain, 0, len(weights))
+	for domain, weight := range weights {
+		dictionary = append(dictionary, learnedDomain{domain: domain, count: weight / current})
+	}
+	sort.Slice(dictionary, func(i, j int) bool {
+		return dictionary[i].count > dictionary[j].count
+	})
+	l.dictionary.Store(&dictionary)
+	return nil
+}
+
+// Start syncs with the store every interval until ctx is done
+func (l *TypoLearner) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Sync(ctx); err != nil {
+					log.Printf("Warning: Could not sync learned typo domains: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// weight returns the weight of an observation made now
+func (l *TypoLearner) weight() float64 {
+	return math.Exp2(float64(l.now().Sub(decayEpoch)) / float64(l.halfLife))
+}
+
+// editDistance returns the optimal string alignment distance between a and b: the number
+// of insertions, deletions, substitutions and adjacent transpositions turning a into b
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// MemoryDomainFrequencyStore is an in-process DomainFrequencyStore, for single instances and tests
+type MemoryDomainFrequencyStore struct {
+	mu      sync.Mutex
+	weights map[string]float64
+}
+
+// NewMemoryDomainFrequencyStore creates an empty MemoryDomainFrequencyStore
+func NewMemoryDomainFrequencyStore() *MemoryDomainFrequencyStore {
+	return &MemoryDomainFrequencyStore{weights: make(map[string]float64)}
+}
+
+// IncrementDomains implements DomainFrequencyStore
+func (s *MemoryDomainFrequencyStore) IncrementDomains(ctx context.Context, increments map[string]float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for domain, weight := range increments {
+		s.weights[domain] += weight
+	}
+	return nil
+}
+
+// TopDomains implements DomainFrequencyStore
+func (s *MemoryDomainFrequencyStore) TopDomains(ctx context.Context, n int) (map[string]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	domains := make([]string, 0, len(s.weights))
+	for domain := range s.weights {
+		domains = append(domains, domain)
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		return s.weights[domains[i]] > s.weights[domains[j]]
+	})
+	if len(domains) > n {
+		domains = domains[:n]
+	}
+
+	top := make(map[string]float64, len(domains))
+	for _, domain := range domains {
+		top[domain] = s.weights[domain]
+	}
+	return top, nil
+}
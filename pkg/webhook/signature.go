@@ -0,0 +1,101 @@
+// Package webhook signs callback requests so receivers can check they came from the
+// email validator and were not replayed.
+//
+// A signed request carries the SignatureHeader:
+//
+//	X-Emailvalidator-Signature: t=1700000000,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd
+//
+// t is the Unix time the request was signed at and v1 the hex-encoded HMAC-SHA256, keyed
+// with the shared secret, of the timestamp, a period and the raw request body:
+//
+//	hex(HMAC-SHA256(secret, "1700000000." + body))
+//
+// To verify, recompute the HMAC over the body exactly as received, compare it in constant
+// time, and reject timestamps further than a tolerance from the current time.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the request header carrying the signature
+const SignatureHeader = "X-Emailvalidator-Signature"
+
+// DefaultTolerance is how far a signature's timestamp may be from the current time
+const DefaultTolerance = 5 * time.Minute
+
+// Errors returned by Verify
+var (
+	ErrMissingSignature = errors.New("webhook: missing signature")
+	ErrInvalidSignature = errors.New("webhook: signature does not match")
+	ErrSignatureExpired = errors.New("webhook: signature timestamp outside tolerance")
+)
+
+// Sign returns the SignatureHeader value for payload sent at timestamp
+func Sign(secret []byte, timestamp time.Time, payload []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac(secret, t, payload))
+}
+
+// Verify checks that header is a valid signature of payload under secret, made within
+// tolerance of now. A header may carry several v1 signatures, e.g. while a secret is
+// rotated; any one of them matching is enough.
+func Verify(secret []byte, header string, payload []byte, now time.Time, tolerance time.Duration) error {
+	if header == "" {
+		return ErrMissingSignature
+	}
+
+	var t string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return fmt.Errorf("%w: malformed element %q", ErrInvalidSignature, part)
+		}
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("%w: v1 is not hex", ErrInvalidSignature)
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+	if t == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: want t and v1 elements", ErrInvalidSignature)
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: t is not a Unix time", ErrInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := mac(secret, t, payload)
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// mac returns the HMAC-SHA256 of the timestamp t, a period and payload
+func mac(secret []byte, t string, payload []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(t))
+	h.Write([]byte{'.'})
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -2,11 +2,22 @@
 package integration
 
 import (
+	"archive/zip"
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -22,6 +33,9 @@ var (
 	testServerOnce sync.Once
 )
 
+// testExplainToken authorizes requests to the explain endpoint of the test server
+const testExplainToken = "test-explain-token"
+
 func getTestServer(t *testing.T) *httptest.Server {
 	testServerOnce.Do(func() {
 		// Create validator
@@ -41,8 +55,16 @@ func getTestServer(t *testing.T) *httptest.Server {
 		apiMux := http.NewServeMux()
 		apiMux.HandleFunc("/validate", handler.HandleValidate)
 		apiMux.HandleFunc("/validate/batch", handler.HandleBatchValidate)
+		apiMux.HandleFunc("/validate/quick", handler.HandleValidateQuick)
+		apiMux.HandleFunc("/validate/file", handler.HandleValidateFile)
+		apiMux.HandleFunc("/validate/csv", handler.HandleValidateCSV)
 		apiMux.HandleFunc("/typo-suggestions", handler.HandleTypoSuggestions)
+		apiMux.HandleFunc("/typo-suggestions/accept", handler.HandleAcceptTypoSuggestion)
+		apiMux.HandleFunc("/same-mailbox", handler.HandleSameMailbox)
+		apiMux.HandleFunc("/canonicalize/batch", handler.HandleCanonicalizeBatch)
+		apiMux.HandleFunc("/validate-domain", handler.HandleValidateDomain)
 		apiMux.HandleFunc("/status", handler.HandleStatus)
+		apiMux.Handle("/validate/explain", api.NewExplainHandler(emailService, testExplainToken))
 
 		// Wrap API routes with monitoring
 		monitoredHandler := monitoring.MetricsMiddleware(apiMux)
@@ -337,78 +359,192 @@ func TestHandleTypoSuggestions(t *testing.T) {
 	}
 }
 
-func TestHandleStatus(t *testing.T) {
+func TestHandleTypoSuggestionsMinConfidence(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
 	t.Parallel()
-	start := time.Now()
-	defer func() {
-		t.Logf("Test 'TestHandleStatus' took %v", time.Since(start))
-	}()
-
 	server := getTestServer(t)
-	client := &http.Client{
-		Timeout: 2 * time.Second,
+
+	tests := []struct {
+		name              string
+		minConfidence     string
+		wantStatus        int
+		wantHasSuggestion bool
+	}{
+		{"Default threshold", "", http.StatusOK, true},
+		{"Threshold below confidence", "0.5", http.StatusOK, true},
+		{"Threshold above confidence", "0.95", http.StatusOK, false},
+		{"Threshold out of range", "1.5", http.StatusBadRequest, false},
+		{"Threshold not a number", "high", http.StatusBadRequest, false},
 	}
 
-	resp, err := client.Get(server.URL + "/api/status")
-	if err != nil {
-		t.Fatalf("Failed to make request: %v", err)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			query := url.Values{"email": {"user@gmial.com"}}
+			if tt.minConfidence != "" {
+				query.Set("min_confidence", tt.minConfidence)
+			}
+			resp, err := http.Get(server.URL + "/api/typo-suggestions?" + query.Encode())
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			var result model.TypoSuggestionResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if hasSuggestion := result.TypoSuggestion != ""; hasSuggestion != tt.wantHasSuggestion {
+				t.Errorf("got typoSuggestion = %v, want %v", hasSuggestion, tt.wantHasSuggestion)
+			}
+			if tt.wantHasSuggestion && result.Confidence <= 0 {
+				t.Errorf("got confidence %v, want a positive value", result.Confidence)
+			}
+		})
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			t.Errorf("Failed to close response body: %v", err)
-		}
-	}()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+func TestHandleTypoSuggestionsRegion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
 	}
+	t.Parallel()
+	server := getTestServer(t)
 
-	var result model.APIStatus
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
+	tests := []struct {
+		name           string
+		email          string
+		region         string
+		acceptLanguage string
+		want           string
+	}{
+		{"No region", "ivan@mai.ru", "", "", ""},
+		{"Region parameter", "ivan@mai.ru", "ru", "", "ivan@mail.ru"},
+		{"Accept-Language", "minsu@navr.com", "", "ko-KR,ko;q=0.9", "minsu@naver.com"},
+		{"Region parameter wins over Accept-Language", "minsu@navr.com", "ru", "ko-KR", ""},
+		{"Global typos in any region", "user@gmial.com", "kr", "", "user@gmail.com"},
 	}
 
-	if result.Status != "healthy" {
-		t.Errorf("got status %q, want 'healthy'", result.Status)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			query := url.Values{"email": {tt.email}}
+			if tt.region != "" {
+				query.Set("region", tt.region)
+			}
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/api/typo-suggestions?"+query.Encode(), nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			var result model.TypoSuggestionResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result.TypoSuggestion != tt.want {
+				t.Errorf("got typoSuggestion %q, want %q", result.TypoSuggestion, tt.want)
+			}
+		})
 	}
 }
 
-func TestInvalidJSON(t *testing.T) {
+func TestHandleAcceptTypoSuggestion(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
 	t.Parallel()
 	server := getTestServer(t)
 
-	endpoints := []string{
-		"/validate",
-		"/validate/batch",
-		"/typo-suggestions",
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{"Accepted suggestion", http.MethodPost, `{"email":"user@gmial.com","suggestion":"user@gmail.com"}`, http.StatusNoContent},
+		{"Suggestion not offered", http.MethodPost, `{"email":"user@gmial.com","suggestion":"user@example.com"}`, http.StatusBadRequest},
+		{"Missing suggestion", http.MethodPost, `{"email":"user@gmial.com"}`, http.StatusBadRequest},
+		{"Invalid body", http.MethodPost, `{`, http.StatusBadRequest},
+		{"Wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
 	}
 
-	for _, endpoint := range endpoints {
-		endpoint := endpoint
-		t.Run(endpoint, func(t *testing.T) {
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			start := time.Now()
-			defer func() {
-				t.Logf("Subtest '%s' took %v", endpoint, time.Since(start))
-			}()
+			req, err := http.NewRequest(tt.method, server.URL+"/api/typo-suggestions/accept", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
 
-			client := &http.Client{
-				Timeout: 2 * time.Second,
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
 			}
+		})
+	}
+}
 
-			req, err := http.NewRequest(http.MethodPost, server.URL+"/api"+endpoint, bytes.NewBufferString("invalid json"))
-			if err != nil {
-				t.Fatalf("Failed to create request: %v", err)
+func TestHandleValidateSuggestion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	tests := []struct {
+		name           string
+		query          string
+		wantSuggestion string
+	}{
+		{
+			name:           "Typo",
+			query:          "?email=user@gmial.com",
+			wantSuggestion: "user@gmail.com",
+		},
+		{
+			name:           "No typo",
+			query:          "?email=user@gmail.com",
+			wantSuggestion: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{
+				Timeout: 5 * time.Second,
 			}
-			req.Header.Set("Content-Type", "application/json")
 
-			resp, err := client.Do(req)
+			resp, err := client.Get(server.URL + "/api/validate" + tt.query)
 			if err != nil {
 				t.Fatalf("Failed to make request: %v", err)
 			}
@@ -418,14 +554,96 @@ func TestInvalidJSON(t *testing.T) {
 				}
 			}()
 
-			if resp.StatusCode != http.StatusBadRequest {
-				t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			var result model.EmailValidationResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if result.TypoSuggestion != tt.wantSuggestion {
+				t.Errorf("got typoSuggestion = %q, want %q", result.TypoSuggestion, tt.wantSuggestion)
 			}
 		})
 	}
 }
 
-func TestPublicEndpoints(t *testing.T) {
+func TestHandleValidateFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantKeys   []string
+		wantNested []string
+	}{
+		{
+			name:       "Top-level and nested fields",
+			path:       "/api/validate?email=not-an-email&fields=status,score,is_disposable",
+			wantStatus: http.StatusOK,
+			wantKeys:   []string{"status", "score", "validations"},
+			wantNested: []string{"is_disposable"},
+		},
+		{
+			name:       "Whole validations object",
+			path:       "/api/validate?email=not-an-email&fields=email,validations",
+			wantStatus: http.StatusOK,
+			wantKeys:   []string{"email", "validations"},
+			wantNested: []string{"syntax", "unknown_tld", "domain_exists", "mx_records", "mailbox_exists", "is_disposable", "is_role_based", "is_free_provider", "is_catch_all", "is_greylisted", "null_mx", "ip_literal_mx", "is_parked"},
+		},
+		{
+			name:       "Omitted optional field is not added",
+			path:       "/api/validate?email=not-an-email&fields=status,aliasOf",
+			wantStatus: http.StatusOK,
+			wantKeys:   []string{"status"},
+		},
+		{
+			name:       "Unknown field",
+			path:       "/api/validate?email=not-an-email&fields=status,bogus",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{Timeout: 5 * time.Second}
+			resp, err := client.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			assertKeys(t, result, tt.wantKeys)
+			if len(tt.wantNested) > 0 {
+				validations, _ := result["validations"].(map[string]interface{})
+				assertKeys(t, validations, tt.wantNested)
+			}
+		})
+	}
+}
+
+func TestHandleValidateSchemaVersion(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
@@ -434,77 +652,911 @@ func TestPublicEndpoints(t *testing.T) {
 
 	tests := []struct {
 		name           string
-		endpoint       string
-		method         string
+		path           string
+		accept         string
 		wantStatus     int
-		wantBodyFields []string
+		wantKeys       []string
+		wantNested     []string
+		wantDeprecated bool
 	}{
 		{
-			name:           "Status endpoint",
-			endpoint:       "/api/status",
-			method:         http.MethodGet,
+			name:       "Current version by default",
+			path:       "/api/validate?email=not-an-email",
+			wantStatus: http.StatusOK,
+			wantKeys:   []string{"schema_version", "email", "validations", "score", "status", "reason_code", "reason", "has_subaddress", "fingerprint", "validated_at", "revalidate_after"},
+		},
+		{
+			name:           "Version 1 by query parameter",
+			path:           "/api/validate?email=not-an-email&schema_version=1",
 			wantStatus:     http.StatusOK,
-			wantBodyFields: []string{"status", "uptime", "requests_handled", "average_response_time_ms"},
+			wantKeys:       []string{"email", "validations", "score", "status"},
+			wantNested:     []string{"syntax", "domain_exists", "mx_records", "mailbox_exists", "is_disposable", "is_role_based"},
+			wantDeprecated: true,
 		},
 		{
-			name:       "Metrics endpoint",
-			endpoint:   "/metrics",
-			method:     http.MethodGet,
+			name:           "Version 1 by Accept header",
+			path:           "/api/validate?email=not-an-email",
+			accept:         "application/json; schema_version=1",
+			wantStatus:     http.StatusOK,
+			wantKeys:       []string{"email", "validations", "score", "status"},
+			wantDeprecated: true,
+		},
+		{
+			name:       "Query parameter wins over Accept header",
+			path:       "/api/validate?email=not-an-email&schema_version=2",
+			accept:     "application/json; schema_version=1",
 			wantStatus: http.StatusOK,
+			wantKeys:   []string{"schema_version", "email", "validations", "score", "status", "reason_code", "reason", "has_subaddress", "fingerprint", "validated_at", "revalidate_after"},
 		},
 		{
-			name:       "Status endpoint with POST",
-			endpoint:   "/api/status",
-			method:     http.MethodPost,
-			wantStatus: http.StatusMethodNotAllowed,
+			name:       "Unsupported version",
+			path:       "/api/validate?email=not-an-email&schema_version=3",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Fields with an older version",
+			path:       "/api/validate?email=not-an-email&schema_version=1&fields=status",
+			wantStatus: http.StatusBadRequest,
 		},
-	}
-
-	client := &http.Client{
-		Timeout: 2 * time.Second,
 	}
 
 	for _, tt := range tests {
-		tt := tt // capture range variable
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			start := time.Now()
-			defer func() {
-				t.Logf("Subtest '%s' took %v", tt.name, time.Since(start))
-			}()
 
-			// Create request
-			req, err := http.NewRequest(tt.method, server.URL+tt.endpoint, nil)
+			req, err := http.NewRequest(http.MethodGet, server.URL+tt.path, nil)
 			if err != nil {
 				t.Fatalf("Failed to create request: %v", err)
 			}
-
-			// Send request
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			client := &http.Client{Timeout: 5 * time.Second}
 			resp, err := client.Do(req)
 			if err != nil {
 				t.Fatalf("Failed to make request: %v", err)
 			}
-			defer func() {
-				if err := resp.Body.Close(); err != nil {
-					t.Errorf("Failed to close response body: %v", err)
-				}
-			}()
+			defer resp.Body.Close()
 
-			// Check status code
 			if resp.StatusCode != tt.wantStatus {
-				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if deprecated := resp.Header.Get("Deprecation") != ""; deprecated != tt.wantDeprecated {
+				t.Errorf("got Deprecation header %q, want one: %v", resp.Header.Get("Deprecation"), tt.wantDeprecated)
 			}
 
-			// For successful requests, verify response body
-			if resp.StatusCode == http.StatusOK && len(tt.wantBodyFields) > 0 {
-				var body map[string]interface{}
-				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-					t.Fatalf("Failed to decode response body: %v", err)
-				}
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			assertKeys(t, result, tt.wantKeys)
+			if len(tt.wantNested) > 0 {
+				validations, _ := result["validations"].(map[string]interface{})
+				assertKeys(t, validations, tt.wantNested)
+			}
+		})
+	}
+}
 
-				// Check required fields exist
-				for _, field := range tt.wantBodyFields {
-					if _, exists := body[field]; !exists {
+func TestHandleBatchValidateSchemaVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	for _, tt := range []struct {
+		query    string
+		wantKeys []string
+	}{
+		{query: "", wantKeys: []string{"schema_version", "results", "errors", "summary"}},
+		{query: "&schema_version=1", wantKeys: []string{"results"}},
+	} {
+		resp, err := client.Get(server.URL + "/api/validate/batch?email=not-an-email" + tt.query)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		var result map[string]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		assertKeys(t, result, tt.wantKeys)
+		results, _ := result["results"].([]interface{})
+		if len(results) != 1 {
+			t.Fatalf("got %d results, want 1", len(results))
+		}
+		if item, _ := results[0].(map[string]interface{}); item["schema_version"] != nil {
+			t.Errorf("batch result carries schema_version %v, want it on the batch only", item["schema_version"])
+		}
+	}
+}
+
+func TestHandleBatchValidateFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(server.URL + "/api/validate/batch?email=not-an-email&email=also-not-an-email&fields=email,status")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var result struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(result.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(result.Results))
+	}
+	for _, item := range result.Results {
+		assertKeys(t, item, []string{"email", "status"})
+	}
+	if result.Results[0]["email"] != "not-an-email" {
+		t.Errorf("got first email %v, want not-an-email", result.Results[0]["email"])
+	}
+}
+
+func TestHandleBatchValidateAutocorrect(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	resp, err := client.Get(server.URL + "/api/validate/batch?email=user@gmai.com&autocorrect=true&autocorrect_confidence=2")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("got status %d for an invalid autocorrect_confidence, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	resp, err = client.Get(server.URL + "/api/validate/batch?email=user@gmai.com&email=not-an-email&autocorrect=true&revalidate=false")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var result model.BatchValidationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(result.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(result.Results))
+	}
+	correction := result.Results[0].Correction
+	if correction == nil || correction.Email != "user@gmail.com" {
+		t.Fatalf("got correction %+v, want user@gmail.com", correction)
+	}
+	if correction.Status != "" || correction.Score != nil {
+		t.Errorf("got status %q for a correction that was not re-validated, want none", correction.Status)
+	}
+	if result.Results[1].Correction != nil {
+		t.Errorf("got correction %+v for an address without a suggestion, want none", result.Results[1].Correction)
+	}
+}
+
+func TestHandleValidateScoreScale(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantScore  interface{}
+	}{
+		{name: "Default percent", path: "/api/validate?email=not-an-email", wantStatus: http.StatusOK, wantScore: float64(0)},
+		{name: "Probability", path: "/api/validate?email=not-an-email&scale=probability", wantStatus: http.StatusOK, wantScore: float64(0)},
+		{name: "Grade", path: "/api/validate?email=not-an-email&scale=grade", wantStatus: http.StatusOK, wantScore: "F"},
+		{name: "Grade with fields", path: "/api/validate?email=not-an-email&scale=grade&fields=score", wantStatus: http.StatusOK, wantScore: "F"},
+		{name: "Batch grade", path: "/api/validate/batch?email=not-an-email&scale=grade", wantStatus: http.StatusOK, wantScore: "F"},
+		{name: "Unknown scale", path: "/api/validate?email=not-an-email&scale=stars", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &http.Client{Timeout: 5 * time.Second}
+			resp, err := client.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if results, ok := result["results"].([]interface{}); ok && len(results) > 0 {
+				result, _ = results[0].(map[string]interface{})
+			}
+			if result["score"] != tt.wantScore {
+				t.Errorf("got score %#v, want %#v", result["score"], tt.wantScore)
+			}
+		})
+	}
+}
+
+// assertKeys checks that m has exactly the given keys
+func assertKeys(t *testing.T, m map[string]interface{}, keys []string) {
+	t.Helper()
+	if len(m) != len(keys) {
+		t.Errorf("got keys %v, want %v", mapKeys(m), keys)
+		return
+	}
+	for _, key := range keys {
+		if _, ok := m[key]; !ok {
+			t.Errorf("missing key %q in %v", key, mapKeys(m))
+		}
+	}
+}
+
+func mapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestHandleStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	start := time.Now()
+	defer func() {
+		t.Logf("Test 'TestHandleStatus' took %v", time.Since(start))
+	}()
+
+	server := getTestServer(t)
+	client := &http.Client{
+		Timeout: 2 * time.Second,
+	}
+
+	resp, err := client.Get(server.URL + "/api/status")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			t.Errorf("Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var result model.APIStatus
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if result.Status != "healthy" {
+		t.Errorf("got status %q, want 'healthy'", result.Status)
+	}
+}
+
+func TestHandleExplain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+
+	server := getTestServer(t)
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	tests := []struct {
+		name           string
+		authorization  string
+		email          string
+		expectedStatus int
+	}{
+		{name: "Missing token", email: "invalid-email", expectedStatus: http.StatusUnauthorized},
+		{name: "Wrong token", authorization: "Bearer wrong", email: "invalid-email", expectedStatus: http.StatusUnauthorized},
+		{name: "Token without Bearer scheme", authorization: testExplainToken, email: "invalid-email", expectedStatus: http.StatusUnauthorized},
+		{name: "Missing email", authorization: "Bearer " + testExplainToken, expectedStatus: http.StatusBadRequest},
+		{name: "Authorized", authorization: "Bearer " + testExplainToken, email: "invalid-email", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/api/validate/explain?email="+tt.email, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer func() {
+				if err := resp.Body.Close(); err != nil {
+					t.Errorf("Failed to close response body: %v", err)
+				}
+			}()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if resp.StatusCode == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Error("missing WWW-Authenticate header on 401")
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			var result model.ExplainResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result.Status != model.ValidationStatusInvalidFormat {
+				t.Errorf("got status %q, want %q", result.Status, model.ValidationStatusInvalidFormat)
+			}
+			if _, ok := result.Trace.TimingsMs["syntax"]; !ok {
+				t.Errorf("trace is missing the syntax timing: %v", result.Trace.TimingsMs)
+			}
+		})
+	}
+}
+
+func TestHandleRefreshBlocklist(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	const adminToken = "test-admin-token"
+	var fetches int32
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		fmt.Fprint(w, "tempmail.com\nmailinator.com\n")
+	}))
+	defer list.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(list.URL)
+	server := httptest.NewServer(api.NewBlocklistRefreshHandler(blocklist, adminToken))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		method         string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "Missing token", method: http.MethodPost, expectedStatus: http.StatusUnauthorized},
+		{name: "Wrong token", method: http.MethodPost, authorization: "Bearer wrong", expectedStatus: http.StatusUnauthorized},
+		{name: "Wrong method", method: http.MethodGet, authorization: "Bearer " + adminToken, expectedStatus: http.StatusMethodNotAllowed},
+		{name: "Authorized", method: http.MethodPost, authorization: "Bearer " + adminToken, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			var result model.BlocklistRefreshResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result.Domains != 2 {
+				t.Errorf("got %d domains, want 2", result.Domains)
+			}
+			if time.Since(result.LoadedAt) > time.Minute {
+				t.Errorf("got loaded_at %v, want the time of the refresh", result.LoadedAt)
+			}
+			if !blocklist.IsDisposable("tempmail.com") {
+				t.Error("blocklist does not contain tempmail.com after the refresh")
+			}
+		})
+	}
+
+	if got := atomic.LoadInt32(&fetches); got != 1 {
+		t.Errorf("list was fetched %d times, want 1 for the one authorized request", got)
+	}
+}
+
+func TestHandleBlocklistExport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	const adminToken = "test-admin-token"
+	lists := map[string]string{
+		"/a": "tempmail.com\nMailinator.com\n",
+		"/b": "tempmail.com\n*.throwaway.com\nok.example\n",
+	}
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, lists[r.URL.Path])
+	}))
+	defer list.Close()
+
+	// The allowlist overrides allowed.com on the local list and ok.example on the remote one
+	allowlist := []string{"allowed.com", "ok.example"}
+	emailValidator, err := validator.NewEmailValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailValidator.SetDisposableValidator(validator.NewDisposableValidatorWithDomains([]string{"local-only.com", "tempmail.com", "allowed.com"}))
+	emailValidator.SetDisposableAllowlist(allowlist)
+	patterns, err := validator.NewDisposablePatterns([]string{`^temp-?mail[0-9]+\.`})
+	if err != nil {
+		t.Fatalf("Failed to compile patterns: %v", err)
+	}
+	emailValidator.SetDisposablePatterns(patterns)
+
+	blocklist := validator.NewDisposableBlocklistWithSources(
+		validator.BlocklistSource{URL: list.URL + "/a"},
+		validator.BlocklistSource{URL: list.URL + "/b"},
+	)
+	blocklist.SetAllowlist(allowlist)
+	server := httptest.NewServer(api.NewBlocklistExportHandler(emailValidator, blocklist, adminToken))
+	defer server.Close()
+
+	export := func(t *testing.T, query, authorization string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL+query, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		return resp, string(body)
+	}
+
+	if resp, _ := export(t, "", "Bearer wrong"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got status %d without the token, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+	if resp, _ := export(t, "", "Bearer "+adminToken); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got status %d before the list loaded, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if resp, _ := export(t, "?format=xml", "Bearer "+adminToken); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("got status %d for an unknown format, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "Text", want: "*.throwaway.com\nlocal-only.com\nmailinator.com\ntempmail.com\n"},
+		{name: "Text with sources", query: "?sources=true", want: fmt.Sprintf("*.throwaway.com\t%[1]s/b\nlocal-only.com\tlocal\nmailinator.com\t%[1]s/a\ntempmail.com\tlocal %[1]s/a %[1]s/b\n^temp-?mail[0-9]+\\.\tpattern\n", list.URL)},
+		{name: "JSON", query: "?format=json", want: `["*.throwaway.com","local-only.com","mailinator.com","tempmail.com"]` + "\n"},
+		{name: "JSON with sources", query: "?format=json&sources=true", want: fmt.Sprintf(`[{"entry":"*.throwaway.com","sources":["%[1]s/b"]},{"entry":"local-only.com","sources":["local"]},{"entry":"mailinator.com","sources":["%[1]s/a"]},{"entry":"tempmail.com","sources":["local","%[1]s/a","%[1]s/b"]},{"entry":"^temp-?mail[0-9]+\\.","sources":["pattern"]}]`+"\n", list.URL)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := export(t, tt.query, "Bearer "+adminToken)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if body != tt.want {
+				t.Errorf("got body %q, want %q", body, tt.want)
+			}
+			if resp.Header.Get("Last-Modified") == "" {
+				t.Error("response has no Last-Modified header")
+			}
+		})
+	}
+}
+
+func TestHandleDisposableCheckBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "remote-temp.com\n")
+	}))
+	defer list.Close()
+	blocklist := validator.NewDisposableBlocklistWithURL(list.URL)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Failed to load blocklist: %v", err)
+	}
+
+	emailValidator, err := validator.NewEmailValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	handler := api.NewDisposableCheckHandler(service.NewEmailServiceWithDeps(emailValidator), blocklist)
+	server := httptest.NewServer(http.HandlerFunc(handler.ServeBatch))
+	defer server.Close()
+
+	t.Run("Mixed domains and addresses", func(t *testing.T) {
+		body := `{"domains": ["mailinator.com", "user@remote-temp.com", "gmail.com", "not a domain"]}`
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+
+		var result model.DisposableBatchResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		want := []struct {
+			domain     string
+			disposable bool
+			failed     bool
+		}{
+			{"mailinator.com", true, false},
+			{"remote-temp.com", true, false},
+			{"gmail.com", false, false},
+			{"", false, true},
+		}
+		if len(result.Results) != len(want) {
+			t.Fatalf("got %d results, want %d", len(result.Results), len(want))
+		}
+		for i, w := range want {
+			got := result.Results[i]
+			if got.Index != i || got.Domain != w.domain || got.IsDisposable != w.disposable || (got.Error != "") != w.failed {
+				t.Errorf("result %d = %+v, want domain %q, disposable %v, failed %v", i, got, w.domain, w.disposable, w.failed)
+			}
+		}
+		if result.Errors != 1 {
+			t.Errorf("got %d errors, want 1", result.Errors)
+		}
+	})
+
+	for _, tt := range []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{"Empty list", http.MethodPost, `{"domains": []}`, http.StatusBadRequest},
+		{"Invalid body", http.MethodPost, `{`, http.StatusBadRequest},
+		{"Wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandleDisposableCheckFingerprint(t *testing.T) {
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "example.com\n")
+	}))
+	defer list.Close()
+	blocklist := validator.NewDisposableBlocklistWithURL(list.URL)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Failed to load blocklist: %v", err)
+	}
+
+	emailService := service.NewEmailServiceWithDeps(&panickingValidator{})
+	handler := api.NewDisposableCheckHandler(emailService, blocklist)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/check-disposable?email=user@example.com", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var result model.EmailValidationResponse
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.Status != model.ValidationStatusDisposable {
+		t.Fatalf("got status %s, want %s", result.Status, model.ValidationStatusDisposable)
+	}
+	// The fingerprint must describe the disposable result, not the VALID one it was marked from
+	if want := service.Fingerprint(result); result.Fingerprint != want {
+		t.Errorf("got fingerprint %s, want %s", result.Fingerprint, want)
+	}
+}
+
+func TestHandleDisposableSource(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "remote-temp.com\nmailinator.com\n*.allowed-temp.com\n")
+	}))
+	defer list.Close()
+	blocklist := validator.NewDisposableBlocklistWithURL(list.URL)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Failed to load blocklist: %v", err)
+	}
+
+	emailValidator, err := validator.NewEmailValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailValidator.SetDisposableAllowlist([]string{"support.allowed-temp.com"})
+	blocklist.SetAllowlist([]string{"support.allowed-temp.com"})
+	handler := api.NewDisposableCheckHandler(service.NewEmailServiceWithDeps(emailValidator), blocklist)
+	server := httptest.NewServer(http.HandlerFunc(handler.ServeSource))
+	defer server.Close()
+
+	get := func(t *testing.T, query string) (*http.Response, model.DisposableSourceResponse) {
+		t.Helper()
+		resp, err := http.Get(server.URL + "?" + query)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+		var result model.DisposableSourceResponse
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+		}
+		return resp, result
+	}
+
+	t.Run("Listed locally and remotely", func(t *testing.T) {
+		_, result := get(t, "domain=user@Mailinator.com")
+		if result.Domain != "mailinator.com" || !result.IsDisposable {
+			t.Fatalf("got %+v, want a disposable mailinator.com", result)
+		}
+		want := map[string]bool{validator.DisposableSourceLocal: false, list.URL: false}
+		for _, source := range result.Sources {
+			if _, ok := want[source.Source]; ok && source.Entry == "mailinator.com" && source.Match == "exact" {
+				want[source.Source] = true
+			}
+		}
+		for source, found := range want {
+			if !found {
+				t.Errorf("sources %+v do not include %s", result.Sources, source)
+			}
+		}
+	})
+
+	t.Run("Remote source only", func(t *testing.T) {
+		_, result := get(t, "domain=remote-temp.com")
+		if !result.IsDisposable || len(result.Sources) != 1 || result.Sources[0].Source != list.URL {
+			t.Errorf("got %+v, want remote-temp.com attributed to %s", result, list.URL)
+		}
+	})
+
+	t.Run("Allowlisted", func(t *testing.T) {
+		_, result := get(t, "domain=support.allowed-temp.com")
+		if result.IsDisposable || result.AllowlistEntry != "support.allowed-temp.com" {
+			t.Errorf("got %+v, want an allowlisted domain that is not disposable", result)
+		}
+		if len(result.Sources) != 1 || result.Sources[0].Entry != "*.allowed-temp.com" || result.Sources[0].Match != "wildcard" {
+			t.Errorf("got sources %+v, want the overridden wildcard entry", result.Sources)
+		}
+	})
+
+	t.Run("Not listed", func(t *testing.T) {
+		_, result := get(t, "domain=gmail.com")
+		if result.IsDisposable || result.Sources == nil || len(result.Sources) != 0 {
+			t.Errorf("got %+v, want no sources", result)
+		}
+	})
+
+	for _, tt := range []struct {
+		name  string
+		query string
+	}{
+		{"Missing domain", ""},
+		{"Invalid domain", "domain=not%20a%20domain"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := get(t, tt.query)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestInvalidJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	endpoints := []string{
+		"/validate",
+		"/validate/batch",
+		"/typo-suggestions",
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint := endpoint
+		t.Run(endpoint, func(t *testing.T) {
+			t.Parallel()
+			start := time.Now()
+			defer func() {
+				t.Logf("Subtest '%s' took %v", endpoint, time.Since(start))
+			}()
+
+			client := &http.Client{
+				Timeout: 2 * time.Second,
+			}
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/api"+endpoint, bytes.NewBufferString("invalid json"))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer func() {
+				if err := resp.Body.Close(); err != nil {
+					t.Errorf("Failed to close response body: %v", err)
+				}
+			}()
+
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestPublicEndpoints(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	tests := []struct {
+		name           string
+		endpoint       string
+		method         string
+		wantStatus     int
+		wantBodyFields []string
+	}{
+		{
+			name:           "Status endpoint",
+			endpoint:       "/api/status",
+			method:         http.MethodGet,
+			wantStatus:     http.StatusOK,
+			wantBodyFields: []string{"status", "uptime", "requests_handled", "average_response_time_ms"},
+		},
+		{
+			name:       "Metrics endpoint",
+			endpoint:   "/metrics",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Status endpoint with POST",
+			endpoint:   "/api/status",
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	client := &http.Client{
+		Timeout: 2 * time.Second,
+	}
+
+	for _, tt := range tests {
+		tt := tt // capture range variable
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			start := time.Now()
+			defer func() {
+				t.Logf("Subtest '%s' took %v", tt.name, time.Since(start))
+			}()
+
+			// Create request
+			req, err := http.NewRequest(tt.method, server.URL+tt.endpoint, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			// Send request
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer func() {
+				if err := resp.Body.Close(); err != nil {
+					t.Errorf("Failed to close response body: %v", err)
+				}
+			}()
+
+			// Check status code
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			// For successful requests, verify response body
+			if resp.StatusCode == http.StatusOK && len(tt.wantBodyFields) > 0 {
+				var body map[string]interface{}
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatalf("Failed to decode response body: %v", err)
+				}
+
+				// Check required fields exist
+				for _, field := range tt.wantBodyFields {
+					if _, exists := body[field]; !exists {
 						t.Errorf("response body missing required field: %s", field)
 					}
 				}
@@ -512,3 +1564,1075 @@ func TestPublicEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleBatchValidateMaxBatchSize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	// Malformed addresses fail syntax validation without any DNS lookups
+	batch := func(size int) []string {
+		emails := make([]string, size)
+		for i := range emails {
+			emails[i] = fmt.Sprintf("not-an-email-%d", i)
+		}
+		return emails
+	}
+
+	tests := []struct {
+		name       string
+		emails     []string
+		wantStatus int
+	}{
+		{"Exactly at the limit", batch(service.DefaultMaxBatchSize), http.StatusOK},
+		{"Over the limit", batch(service.DefaultMaxBatchSize + 1), http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := &http.Client{Timeout: 10 * time.Second}
+
+			jsonBody, _ := json.Marshal(model.BatchValidationRequest{Emails: tt.emails})
+			resp, err := client.Post(server.URL+"/api/validate/batch", "application/json", bytes.NewBuffer(jsonBody))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				var result model.BatchValidationResponse
+				if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if len(result.Results) != len(tt.emails) {
+					t.Errorf("got %d results, want %d", len(result.Results), len(tt.emails))
+				}
+				return
+			}
+
+			var errResp map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			want := fmt.Sprintf("batch of %d emails exceeds the maximum of %d", len(tt.emails), service.DefaultMaxBatchSize)
+			if errResp["error"] != want {
+				t.Errorf("got error %q, want %q", errResp["error"], want)
+			}
+		})
+	}
+}
+
+func TestHandleBatchValidateSourceURL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	lists := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/list.txt":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprint(w, "# exported list\nfirst@\n\nsecond@\n")
+		case "/list.csv":
+			w.Header().Set("Content-Type", "text/csv")
+			fmt.Fprint(w, "name,email\nFirst,first@\nSecond,second@\n")
+		case "/export.csv":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			fmt.Fprint(w, "email\nfirst@\n")
+		case "/large.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, strings.Repeat("first@\n", 10))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			fmt.Fprint(w, "first@\n")
+		case "/redirect":
+			// localhost is not among the allowed hosts, unlike 127.0.0.1
+			http.Redirect(w, r, strings.Replace("http://"+r.Host+"/list.txt", "127.0.0.1", "localhost", 1), http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer lists.Close()
+
+	emailValidator, err := validator.NewEmailValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	handler := api.NewHandler(service.NewEmailServiceWithDeps(emailValidator))
+	handler.SetHTTPClient(lists.Client())
+	handler.SetSourceURLHosts([]string{"127.0.0.1"})
+	handler.SetMaxSourceBytes(40)
+
+	tests := []struct {
+		name       string
+		request    model.BatchValidationRequest
+		wantStatus int
+		wantEmails []string
+	}{
+		{"Plain text list", model.BatchValidationRequest{SourceURL: lists.URL + "/list.txt"}, http.StatusOK, []string{"first@", "second@"}},
+		{"CSV list", model.BatchValidationRequest{SourceURL: lists.URL + "/list.csv"}, http.StatusOK, []string{"first@", "second@"}},
+		{"CSV by extension", model.BatchValidationRequest{SourceURL: lists.URL + "/export.csv"}, http.StatusOK, []string{"first@"}},
+		{"List over the size limit", model.BatchValidationRequest{SourceURL: lists.URL + "/large.txt"}, http.StatusRequestEntityTooLarge, nil},
+		{"Unsupported content type", model.BatchValidationRequest{SourceURL: lists.URL + "/image.png"}, http.StatusBadGateway, nil},
+		{"Missing list", model.BatchValidationRequest{SourceURL: lists.URL + "/missing.txt"}, http.StatusBadGateway, nil},
+		{"Unsupported scheme", model.BatchValidationRequest{SourceURL: "file:///etc/passwd"}, http.StatusBadRequest, nil},
+		{"Both emails and source_url", model.BatchValidationRequest{Emails: []string{"first@"}, SourceURL: lists.URL + "/list.txt"}, http.StatusBadRequest, nil},
+		{"Host not allowed", model.BatchValidationRequest{SourceURL: strings.Replace(lists.URL, "127.0.0.1", "localhost", 1) + "/list.txt"}, http.StatusForbidden, nil},
+		{"Redirect to a host not allowed", model.BatchValidationRequest{SourceURL: lists.URL + "/redirect"}, http.StatusForbidden, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonBody, _ := json.Marshal(tt.request)
+			rec := httptest.NewRecorder()
+			handler.HandleBatchValidate(rec, httptest.NewRequest(http.MethodPost, "/validate/batch", bytes.NewBuffer(jsonBody)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result model.BatchValidationResponse
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(result.Results) != len(tt.wantEmails) {
+				t.Fatalf("got %d results, want %d", len(result.Results), len(tt.wantEmails))
+			}
+			for i, want := range tt.wantEmails {
+				if result.Results[i].Email != want {
+					t.Errorf("result %d: got email %q, want %q", i, result.Results[i].Email, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleBatchValidateSourceURLRestrictions(t *testing.T) {
+	lists := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "secret-token@\n")
+	}))
+	defer lists.Close()
+
+	emailValidator, err := validator.NewEmailValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	post := func(handler *api.Handler) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(model.BatchValidationRequest{SourceURL: lists.URL + "/list.txt"})
+		rec := httptest.NewRecorder()
+		handler.HandleBatchValidate(rec, httptest.NewRequest(http.MethodPost, "/validate/batch", bytes.NewBuffer(jsonBody)))
+		return rec
+	}
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		rec := post(api.NewHandler(service.NewEmailServiceWithDeps(emailValidator)))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("Loopback refused at dial time", func(t *testing.T) {
+		handler := api.NewHandler(service.NewEmailServiceWithDeps(emailValidator))
+		handler.SetSourceURLHosts([]string{"*"})
+		rec := post(handler)
+		if rec.Code != http.StatusBadGateway {
+			t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusBadGateway, rec.Body.String())
+		}
+		if body := rec.Body.String(); strings.Contains(body, "secret-token") || strings.Contains(body, "127.0.0.1") {
+			t.Errorf("response leaks upstream details: %s", body)
+		}
+	})
+}
+
+func TestParseEndpointTimeouts(t *testing.T) {
+	timeouts, err := api.ParseEndpointTimeouts(" /api/typo-suggestions=2s, /api/validate/batch=5m ,")
+	if err != nil {
+		t.Fatalf("ParseEndpointTimeouts() error = %v", err)
+	}
+	want := map[string]time.Duration{"/api/typo-suggestions": 2 * time.Second, "/api/validate/batch": 5 * time.Minute}
+	if len(timeouts) != len(want) {
+		t.Errorf("got %v, want %v", timeouts, want)
+	}
+	for path, timeout := range want {
+		if timeouts[path] != timeout {
+			t.Errorf("timeout of %s: got %v, want %v", path, timeouts[path], timeout)
+		}
+	}
+
+	for _, spec := range []string{"/api/validate", "api/validate=1s", "/api/validate=soon", "/api/validate=-1s"} {
+		if _, err := api.ParseEndpointTimeouts(spec); err == nil {
+			t.Errorf("ParseEndpointTimeouts(%q) error = nil, want error", spec)
+		}
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// The slow handler blocks until its request is cancelled, as a stuck SMTP probe would
+	cancelled := make(chan struct{}, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		cancelled <- struct{}{}
+	})
+	mux.HandleFunc("/api/fast", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true}`)
+	})
+	timeouts := map[string]time.Duration{"/api/slow": 50 * time.Millisecond}
+	server := httptest.NewServer(api.TimeoutMiddleware(timeouts, time.Minute, mux))
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL + "/api/slow")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode timeout response: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("slow endpoint: got status %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if body["error"] == "" {
+		t.Errorf("slow endpoint: got body %v, want an error message", body)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("slow endpoint took %v, want it cut off at its timeout", elapsed)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Error("slow handler's context was not cancelled at the timeout")
+	}
+
+	resp, err = http.Get(server.URL + "/api/fast")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("fast endpoint: got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("fast endpoint: got Content-Type %q, want the handler's header kept", got)
+	}
+}
+
+func TestTimeoutMiddlewareStreamsExport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// The export streams its first entries, then stalls until its request is cancelled
+	cancelled := make(chan struct{}, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/admin/blocklist/export", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "mailinator.com")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		cancelled <- struct{}{}
+	})
+	server := httptest.NewServer(api.TimeoutMiddleware(nil, 50*time.Millisecond, mux))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/admin/blocklist/export")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if string(body) != "mailinator.com\n" {
+		t.Errorf("got body %q, want the entries streamed before the deadline", body)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Error("export handler's context was not cancelled at the timeout")
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	emailValidator, err := validator.NewEmailValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	handler := api.NewHandler(service.NewEmailServiceWithDeps(emailValidator))
+	apiMux := http.NewServeMux()
+	handler.RegisterRoutes(apiMux)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	server := httptest.NewServer(api.AccessLogMiddleware(logger, http.StripPrefix("/api", apiMux)))
+	defer server.Close()
+
+	// A malformed address fails syntax validation without any DNS lookups
+	resp, err := http.Get(server.URL + "/api/validate?email=" + url.QueryEscape("jane doe@example.com"))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &line); err != nil {
+		t.Fatalf("Failed to decode access log line %q: %v", logs.String(), err)
+	}
+	want := map[string]interface{}{
+		"method":      "GET",
+		"path":        "/api/validate",
+		"status":      float64(http.StatusOK),
+		"client_ip":   "127.0.0.1",
+		"email":       "j***@example.com",
+		"outcome":     string(model.ValidationStatusInvalidFormat),
+		"reason_code": string(model.ReasonSyntaxInvalid),
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("access log %s: got %v, want %v", key, line[key], value)
+		}
+	}
+	if _, ok := line["latency_ms"]; !ok {
+		t.Error("access log has no latency_ms")
+	}
+	if strings.Contains(logs.String(), "jane") {
+		t.Errorf("access log contains the full address: %s", logs.String())
+	}
+}
+
+func TestAccessLogClientIP(t *testing.T) {
+	proxies, err := api.ParseTrustedProxies([]string{"10.0.0.0/8", " 192.0.2.1", ""})
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+	if _, err := api.ParseTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected an invalid CIDR range to be rejected")
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"Direct client", "203.0.113.7:1234", "", "203.0.113.7"},
+		{"Forged header from an untrusted peer", "203.0.113.7:1234", "198.51.100.1", "203.0.113.7"},
+		{"Trusted proxy", "10.0.0.5:1234", "198.51.100.1", "198.51.100.1"},
+		{"Prepended hop behind a trusted proxy", "10.0.0.5:1234", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"Chain of trusted proxies", "10.0.0.5:1234", "198.51.100.1, 192.0.2.1, 10.1.1.1", "198.51.100.1"},
+		{"Trusted proxy without the header", "10.0.0.5:1234", "", "10.0.0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+			handler := api.AccessLogMiddlewareWithProxies(logger, proxies, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest(http.MethodGet, "/validate", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			var line map[string]interface{}
+			if err := json.Unmarshal(logs.Bytes(), &line); err != nil {
+				t.Fatalf("Failed to decode access log line %q: %v", logs.String(), err)
+			}
+			if line["client_ip"] != tt.want {
+				t.Errorf("got client_ip %v, want %s", line["client_ip"], tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessLogRecordsPanics(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	handler := api.AccessLogMiddleware(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	}))
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/validate", nil))
+	}()
+
+	if !strings.Contains(logs.String(), `"status":500`) {
+		t.Errorf("got access log %q, want a line with status 500", logs.String())
+	}
+}
+
+func TestHandleSameMailbox(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	tests := []struct {
+		name            string
+		method          string
+		body            string
+		wantStatus      int
+		wantSameMailbox bool
+	}{
+		{"Same Gmail mailbox", http.MethodPost, `{"first":"J.Smith+news@gmail.com","second":"jsmith@googlemail.com"}`, http.StatusOK, true},
+		{"Different mailboxes", http.MethodPost, `{"first":"jsmith@gmail.com","second":"jdoe@gmail.com"}`, http.StatusOK, false},
+		{"Invalid address", http.MethodPost, `{"first":"jsmith@gmail.com","second":"not-an-email"}`, http.StatusBadRequest, false},
+		{"Missing address", http.MethodPost, `{"first":"jsmith@gmail.com"}`, http.StatusBadRequest, false},
+		{"Malformed body", http.MethodPost, `{"first":`, http.StatusBadRequest, false},
+		{"Wrong method", http.MethodGet, ``, http.StatusMethodNotAllowed, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest(tt.method, server.URL+"/api/same-mailbox", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result model.SameMailboxResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result.SameMailbox != tt.wantSameMailbox {
+				t.Errorf("got same_mailbox = %v, want %v", result.SameMailbox, tt.wantSameMailbox)
+			}
+		})
+	}
+}
+
+func TestHandleCanonicalizeBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	tests := []struct {
+		name          string
+		method        string
+		body          string
+		wantStatus    int
+		wantCanonical []string
+		wantDistinct  int
+	}{
+		{"Duplicates and an invalid address", http.MethodPost, `{"emails":["J.Smith+news@gmail.com","jsmith@googlemail.com","not-an-email"]}`, http.StatusOK, []string{"jsmith@gmail.com", "jsmith@gmail.com", "not-an-email"}, 1},
+		{"No emails", http.MethodPost, `{"emails":[]}`, http.StatusBadRequest, nil, 0},
+		{"Malformed body", http.MethodPost, `{"emails":`, http.StatusBadRequest, nil, 0},
+		{"Wrong method", http.MethodGet, ``, http.StatusMethodNotAllowed, nil, 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest(tt.method, server.URL+"/api/canonicalize/batch", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result model.CanonicalizeBatchResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(result.Results) != len(tt.wantCanonical) {
+				t.Fatalf("got %d results, want %d", len(result.Results), len(tt.wantCanonical))
+			}
+			for i, item := range result.Results {
+				if item.Canonical != tt.wantCanonical[i] {
+					t.Errorf("got canonical %q for %q, want %q", item.Canonical, item.Email, tt.wantCanonical[i])
+				}
+			}
+			if !result.Results[2].Invalid {
+				t.Errorf("got invalid = false for %q, want true", result.Results[2].Email)
+			}
+			if result.Distinct != tt.wantDistinct {
+				t.Errorf("got distinct = %d, want %d", result.Distinct, tt.wantDistinct)
+			}
+		})
+	}
+}
+
+// readCSV parses a CSV batch response, allowing the summary row's different width
+func readCSV(t *testing.T, resp *http.Response) [][]string {
+	t.Helper()
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Fatalf("got Content-Type %q, want text/csv", got)
+	}
+	reader := csv.NewReader(resp.Body)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV response: %v", err)
+	}
+	return records
+}
+
+func TestHandleBatchValidateCSV(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	// Malformed addresses fail syntax validation without any DNS lookups
+	emails := []string{"not-an-email", "a,b", `say "hi"`, "line\nbreak", "=1+1"}
+	jsonBody, _ := json.Marshal(model.BatchValidationRequest{Emails: emails})
+	resp, err := http.Post(server.URL+"/api/validate/batch?format=csv&summary=true", "application/json", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	records := readCSV(t, resp)
+	if len(records) != len(emails)+2 {
+		t.Fatalf("got %d rows, want header, %d results and a summary", len(records), len(emails))
+	}
+	wantHeader := "index,email,status,score,reason_code,reason,canonical,typo_suggestion,error"
+	if got := strings.Join(records[0], ","); got != wantHeader {
+		t.Errorf("got header %q, want %q", got, wantHeader)
+	}
+
+	wantEmails := []string{"not-an-email", "a,b", `say "hi"`, "line\nbreak", "'=1+1"}
+	for i, row := range records[1 : len(emails)+1] {
+		if len(row) != 9 {
+			t.Fatalf("row %d has %d columns, want 9", i, len(row))
+		}
+		if row[0] != strconv.Itoa(i) || row[1] != wantEmails[i] {
+			t.Errorf("got row %d = %q, %q, want %d, %q", i, row[0], row[1], i, wantEmails[i])
+		}
+		if row[2] != string(model.ValidationStatusInvalidFormat) || row[8] != "" {
+			t.Errorf("got row %d status %q and error %q, want %s and no error", i, row[2], row[8], model.ValidationStatusInvalidFormat)
+		}
+	}
+
+	wantSummary := "#summary,total=5,INVALID_FORMAT=5,errors=0"
+	if got := strings.Join(records[len(records)-1], ","); got != wantSummary {
+		t.Errorf("got summary %q, want %q", got, wantSummary)
+	}
+}
+
+func TestHandleBatchValidateCSVNegotiation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	tests := []struct {
+		name        string
+		query       string
+		accept      string
+		wantStatus  int
+		wantCSV     bool
+		wantSummary bool
+	}{
+		{name: "Accept header", accept: "text/csv", wantStatus: http.StatusOK, wantCSV: true},
+		{name: "Accept header among others", accept: "application/json;q=0.5, text/csv", wantStatus: http.StatusOK, wantCSV: true},
+		{name: "Format overrides Accept", query: "&format=json", accept: "text/csv", wantStatus: http.StatusOK},
+		{name: "Default JSON", wantStatus: http.StatusOK},
+		{name: "No summary by default", query: "&format=csv", wantStatus: http.StatusOK, wantCSV: true},
+		{name: "Summary", query: "&format=csv&summary=true", wantStatus: http.StatusOK, wantCSV: true, wantSummary: true},
+		{name: "Unknown format", query: "&format=xml", wantStatus: http.StatusBadRequest},
+		{name: "Fields with CSV", query: "&format=csv&fields=email", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest(http.MethodGet, server.URL+"/api/validate/batch?email=not-an-email"+tt.query, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if !tt.wantCSV {
+				if got := resp.Header.Get("Content-Type"); got != "application/json" {
+					t.Errorf("got Content-Type %q, want application/json", got)
+				}
+				return
+			}
+
+			records := readCSV(t, resp)
+			hasSummary := records[len(records)-1][0] == "#summary"
+			if hasSummary != tt.wantSummary {
+				t.Errorf("got summary row = %v, want %v", hasSummary, tt.wantSummary)
+			}
+		})
+	}
+}
+
+// panickingValidator accepts every address and domain but panics while checking the
+// addresses in failOn, so their batch results carry an internal error
+type panickingValidator struct {
+	failOn map[string]bool
+}
+
+func (v *panickingValidator) ValidateSyntax(email string) bool { return true }
+
+func (v *panickingValidator) IsRoleBased(email string) bool {
+	if v.failOn[email] {
+		panic("smtp connection reset")
+	}
+	return false
+}
+
+func (v *panickingValidator) CalculateScore(validations map[string]bool) int { return 100 }
+
+func (v *panickingValidator) GetTypoSuggestions(email string) []string { return nil }
+
+func (v *panickingValidator) DetectAlias(email string) string { return "" }
+
+func (v *panickingValidator) ValidateDomain(domain string) bool { return true }
+
+func (v *panickingValidator) ValidateMXRecords(domain string) bool { return true }
+
+func (v *panickingValidator) IsDisposable(domain string) bool { return false }
+
+func TestHandleBatchValidateCSVErrorColumn(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+
+	emailService := service.NewEmailServiceWithDeps(&panickingValidator{failOn: map[string]bool{"boom@example.com": true}})
+	mux := http.NewServeMux()
+	api.NewHandler(emailService).RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/validate/batch?email=a@example.com&email=boom@example.com&format=csv&summary=true")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	records := readCSV(t, resp)
+	if len(records) != 4 {
+		t.Fatalf("got %d rows, want 4", len(records))
+	}
+	if records[1][2] != string(model.ValidationStatusValid) || records[1][8] != "" {
+		t.Errorf("got first row status %q and error %q, want VALID and no error", records[1][2], records[1][8])
+	}
+	if records[2][2] != string(model.ValidationStatusError) || !strings.Contains(records[2][8], "smtp connection reset") {
+		t.Errorf("got second row status %q and error %q, want ERROR with the failure", records[2][2], records[2][8])
+	}
+	wantSummary := "#summary,total=2,ERROR=1,VALID=1,errors=1"
+	if got := strings.Join(records[3], ","); got != wantSummary {
+		t.Errorf("got summary %q, want %q", got, wantSummary)
+	}
+}
+
+func TestHandleValidateDomain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	// A domain under a TLD that does not exist is answered without DNS lookups
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		body       string
+		wantStatus int
+		wantResult model.ValidationStatus
+	}{
+		{"POST", http.MethodPost, "", `{"domain":"example.qwerty"}`, http.StatusOK, model.ValidationStatusUnknownTLD},
+		{"GET", http.MethodGet, "?domain=example.qwerty", "", http.StatusOK, model.ValidationStatusUnknownTLD},
+		{"Invalid domain", http.MethodPost, "", `{"domain":"user@example.com"}`, http.StatusBadRequest, ""},
+		{"Missing domain", http.MethodPost, "", `{}`, http.StatusBadRequest, ""},
+		{"Malformed body", http.MethodPost, "", `{"domain":`, http.StatusBadRequest, ""},
+		{"Unknown policy", http.MethodPost, "?disposable_policy=ignore", `{"domain":"example.qwerty"}`, http.StatusBadRequest, ""},
+		{"Wrong method", http.MethodDelete, "", "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest(tt.method, server.URL+"/api/validate-domain"+tt.query, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result model.DomainValidationResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result.Domain != "example.qwerty" || result.Status != tt.wantResult {
+				t.Errorf("got domain %q with status %s, want example.qwerty with %s", result.Domain, result.Status, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestHandleValidateQuick(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		body       string
+		wantStatus int
+		wantResult model.ValidationStatus
+	}{
+		{"GET", http.MethodGet, "?email=user@example.com", "", http.StatusOK, model.ValidationStatusProbablyValid},
+		{"POST", http.MethodPost, "", `{"email":"user@mailinator.com"}`, http.StatusOK, model.ValidationStatusDisposable},
+		{"Flag policy", http.MethodPost, "?disposable_policy=flag", `{"email":"user@mailinator.com"}`, http.StatusOK, model.ValidationStatusProbablyValid},
+		{"Invalid syntax", http.MethodGet, "?email=not-an-email", "", http.StatusOK, model.ValidationStatusInvalidFormat},
+		{"Unknown TLD", http.MethodGet, "?email=user@example.qwerty", "", http.StatusOK, model.ValidationStatusUnknownTLD},
+		{"Missing email", http.MethodGet, "", "", http.StatusBadRequest, ""},
+		{"Malformed body", http.MethodPost, "", `{"email":`, http.StatusBadRequest, ""},
+		{"Wrong method", http.MethodDelete, "", "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest(tt.method, server.URL+"/api/validate/quick"+tt.query, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result model.QuickValidationResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result.Status != tt.wantResult {
+				t.Errorf("got status %s, want %s", result.Status, tt.wantResult)
+			}
+		})
+	}
+}
+
+// uploadList posts content as the "file" form field of a multipart request to the file report endpoint
+func uploadList(t *testing.T, server *httptest.Server, query, filename, content string) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if filename != "" {
+		part, err := form.CreateFormFile("file", filename)
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write([]byte(content))
+	}
+	form.Close()
+
+	resp, err := http.Post(server.URL+"/api/validate/file"+query, form.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	return resp
+}
+
+func TestHandleValidateFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	t.Run("CSV report", func(t *testing.T) {
+		t.Parallel()
+		resp := uploadList(t, server, "", "list.txt", "test@example.com\nnot-an-email\n")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		disposition := resp.Header.Get("Content-Disposition")
+		if !strings.HasPrefix(disposition, "attachment;") || !strings.Contains(disposition, ".csv") {
+			t.Errorf("got Content-Disposition %q, want a .csv attachment", disposition)
+		}
+
+		records := readCSV(t, resp)
+		if records[0][0] != "Email validation report" {
+			t.Errorf("got first row %v, want the report title", records[0])
+		}
+		summary := make(map[string]string)
+		header := -1
+		for i, record := range records {
+			if record[0] == "Email" {
+				header = i
+				break
+			}
+			if len(record) == 2 {
+				summary[record[0]] = record[1]
+			}
+		}
+		if summary["Total"] != "2" || summary["INVALID_FORMAT"] != "1" {
+			t.Errorf("got summary %v, want a total of 2 and one INVALID_FORMAT", summary)
+		}
+		if header == -1 || len(records) != header+3 {
+			t.Fatalf("got %d rows after the results header, want 2", len(records)-header-1)
+		}
+		if records[header+1][0] != "test@example.com" || records[header+2][1] != "INVALID_FORMAT" {
+			t.Errorf("got result rows %v, want them in upload order", records[header+1:])
+		}
+	})
+
+	t.Run("XLSX report", func(t *testing.T) {
+		t.Parallel()
+		resp := uploadList(t, server, "?format=xlsx", "list.csv", "name,email\nTest,test@example.com\n")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if got := resp.Header.Get("Content-Type"); got != "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" {
+			t.Errorf("got Content-Type %q, want the XLSX type", got)
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("Response is not a zip archive: %v", err)
+		}
+		var sheet string
+		for _, f := range archive.File {
+			if f.Name != "xl/worksheets/sheet1.xml" {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatalf("Failed to open sheet: %v", err)
+			}
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(content)
+		}
+		if err := xml.Unmarshal([]byte(sheet), new(struct{})); err != nil {
+			t.Fatalf("Sheet is not well-formed XML: %v", err)
+		}
+		if !strings.Contains(sheet, "test@example.com") || !strings.Contains(sheet, "Email validation report") {
+			t.Errorf("sheet is missing the report content: %s", sheet)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		query      string
+		filename   string
+		content    string
+		wantStatus int
+	}{
+		{"No file", "", "", "", http.StatusBadRequest},
+		{"Empty list", "", "list.txt", "# nothing here\n", http.StatusBadRequest},
+		{"Unknown format", "?format=pdf", "list.txt", "test@example.com\n", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp := uploadList(t, server, tt.query, tt.filename, tt.content)
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandleValidateCSV(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	t.Parallel()
+	server := getTestServer(t)
+
+	post := func(t *testing.T, query, body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(server.URL+"/api/validate/csv"+query, "text/csv", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		return resp
+	}
+
+	t.Run("Passes columns through", func(t *testing.T) {
+		t.Parallel()
+		body := "name,Email,status,name,signup\n" +
+			"\"Doe, Jane\",test@example.com,active,Jane,=2024-01-01\n" +
+			"John,not-an-email,,John,2024-02-01\n"
+		resp := post(t, "", body)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+
+		records := readCSV(t, resp)
+		wantHeader := []string{"name", "Email", "status", "name", "signup", "status_2", "score", "reason_code", "reason", "canonical", "typo_suggestion", "error"}
+		if strings.Join(records[0], "|") != strings.Join(wantHeader, "|") {
+			t.Fatalf("got header %v, want %v", records[0], wantHeader)
+		}
+		if len(records) != 3 {
+			t.Fatalf("got %d rows, want 3", len(records))
+		}
+		wantInput := [][]string{
+			{"Doe, Jane", "test@example.com", "active", "Jane", "=2024-01-01"},
+			{"John", "not-an-email", "", "John", "2024-02-01"},
+		}
+		for i, want := range wantInput {
+			row := records[i+1]
+			if strings.Join(row[:5], "|") != strings.Join(want, "|") {
+				t.Errorf("got input columns %v, want them untouched: %v", row[:5], want)
+			}
+		}
+		if records[2][5] != string(model.ValidationStatusInvalidFormat) {
+			t.Errorf("got status %q for not-an-email, want %q", records[2][5], model.ValidationStatusInvalidFormat)
+		}
+	})
+
+	t.Run("Named column", func(t *testing.T) {
+		t.Parallel()
+		resp := post(t, "?email_column=work%20address", "id,work address\n1,not-an-email\n")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		records := readCSV(t, resp)
+		if records[1][2] != string(model.ValidationStatusInvalidFormat) {
+			t.Errorf("got row %v, want the work address validated", records[1])
+		}
+	})
+
+	tests := []struct {
+		name       string
+		query      string
+		body       string
+		wantStatus int
+	}{
+		{"No email column", "", "id,name\n1,Jane\n", http.StatusBadRequest},
+		{"Unknown named column", "?email_column=mail", "email\ntest@example.com\n", http.StatusBadRequest},
+		{"Header only", "", "email\n", http.StatusBadRequest},
+		{"Ragged rows", "", "email,name\ntest@example.com\n", http.StatusBadRequest},
+		{"Empty body", "", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp := post(t, tt.query, tt.body)
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
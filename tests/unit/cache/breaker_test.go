@@ -0,0 +1,179 @@
+package cachetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyCache counts calls and fails them all while down is set
+type flakyCache struct {
+	down  atomic.Bool
+	calls atomic.Int64
+	err   error
+}
+
+func (c *flakyCache) do() error {
+	c.calls.Add(1)
+	if c.down.Load() {
+		if c.err != nil {
+			return c.err
+		}
+		return errors.New("dial tcp: connection refused")
+	}
+	return nil
+}
+
+func (c *flakyCache) Get(ctx context.Context, key string, dest interface{}) error { return c.do() }
+
+func (c *flakyCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return c.do()
+}
+
+func (c *flakyCache) Delete(ctx context.Context, key string) error { return c.do() }
+
+func (c *flakyCache) Close() error { return nil }
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyCache{}
+	inner.down.Store(true)
+	breaker := cache.NewCircuitBreakerCache(inner, 3, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		err := breaker.Set(ctx, "key", 1, time.Minute)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, cache.ErrCircuitOpen)
+	}
+	assert.True(t, breaker.Open())
+
+	// Further calls fail fast without reaching Redis
+	var dest int
+	assert.ErrorIs(t, breaker.Get(ctx, "key", &dest), cache.ErrCircuitOpen)
+	assert.ErrorIs(t, breaker.Delete(ctx, "key"), cache.ErrCircuitOpen)
+	assert.Equal(t, int64(3), inner.calls.Load())
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyCache{}
+	breaker := cache.NewCircuitBreakerCache(inner, 3, time.Hour)
+
+	for i := 0; i < 5; i++ {
+		inner.down.Store(true)
+		_ = breaker.Set(ctx, "key", 1, time.Minute)
+		_ = breaker.Set(ctx, "key", 1, time.Minute)
+		inner.down.Store(false)
+		assert.NoError(t, breaker.Set(ctx, "key", 1, time.Minute))
+	}
+	assert.False(t, breaker.Open())
+}
+
+func TestCircuitBreakerIgnoresMissesAndCancellation(t *testing.T) {
+	ctx := context.Background()
+	for _, err := range []error{cache.ErrMiss, context.Canceled} {
+		inner := &flakyCache{err: err}
+		inner.down.Store(true)
+		breaker := cache.NewCircuitBreakerCache(inner, 1, time.Hour)
+
+		var dest int
+		for i := 0; i < 3; i++ {
+			assert.ErrorIs(t, breaker.Get(ctx, "key", &dest), err)
+		}
+		assert.False(t, breaker.Open(), "%v opened the circuit", err)
+	}
+}
+
+func TestCircuitBreakerCancellationIsNeutral(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyCache{}
+	inner.down.Store(true)
+	breaker := cache.NewCircuitBreakerCache(inner, 2, 20*time.Millisecond)
+
+	// A cancelled call between two failures does not reset the count
+	assert.Error(t, breaker.Set(ctx, "key", 1, time.Minute))
+	inner.err = context.Canceled
+	assert.ErrorIs(t, breaker.Set(ctx, "key", 1, time.Minute), context.Canceled)
+	inner.err = nil
+	assert.Error(t, breaker.Set(ctx, "key", 1, time.Minute))
+	assert.True(t, breaker.Open())
+
+	// A cancelled probe leaves the circuit open, and the next call probes again
+	time.Sleep(30 * time.Millisecond)
+	inner.err = context.Canceled
+	assert.ErrorIs(t, breaker.Set(ctx, "key", 1, time.Minute), context.Canceled)
+	assert.True(t, breaker.Open(), "a cancelled probe closed the circuit")
+	inner.err = nil
+	inner.down.Store(false)
+	assert.NoError(t, breaker.Set(ctx, "key", 1, time.Minute))
+	assert.False(t, breaker.Open())
+}
+
+func TestCircuitBreakerRecovers(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyCache{}
+	inner.down.Store(true)
+	breaker := cache.NewCircuitBreakerCache(inner, 1, 20*time.Millisecond)
+
+	assert.Error(t, breaker.Set(ctx, "key", 1, time.Minute))
+	assert.True(t, breaker.Open())
+
+	// A failed trial call keeps the circuit open for another cooldown
+	time.Sleep(30 * time.Millisecond)
+	assert.NotErrorIs(t, breaker.Set(ctx, "key", 1, time.Minute), cache.ErrCircuitOpen)
+	assert.True(t, breaker.Open())
+	assert.ErrorIs(t, breaker.Set(ctx, "key", 1, time.Minute), cache.ErrCircuitOpen)
+
+	// Once Redis is back, the next trial call closes the circuit
+	inner.down.Store(false)
+	time.Sleep(30 * time.Millisecond)
+	assert.NoError(t, breaker.Set(ctx, "key", 1, time.Minute))
+	assert.False(t, breaker.Open())
+	assert.NoError(t, breaker.Set(ctx, "key", 1, time.Minute))
+}
+
+func TestCircuitBreakerSingleTrialCall(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyCache{}
+	inner.down.Store(true)
+	breaker := cache.NewCircuitBreakerCache(inner, 1, 10*time.Millisecond)
+	_ = breaker.Set(ctx, "key", 1, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	// Only one of many concurrent calls after the cooldown tests the connection
+	before := inner.calls.Load()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var dest int
+			_ = breaker.Get(ctx, "key", &dest)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, before+1, inner.calls.Load())
+}
+
+func TestDisposableCacheBypassedWhileCircuitOpen(t *testing.T) {
+	ctx := context.Background()
+	inner := &flakyCache{}
+	inner.down.Store(true)
+	breaker := cache.NewCircuitBreakerCache(inner, 1, time.Hour)
+	disposable := cache.NewDisposableCache(breaker, time.Hour)
+
+	// Every lookup is a miss and every write is dropped, so callers compute the result themselves
+	for i := 0; i < 3; i++ {
+		_, ok := disposable.Get(ctx, "example.com")
+		assert.False(t, ok)
+		disposable.Set(ctx, "example.com", true)
+	}
+	assert.Equal(t, int64(1), inner.calls.Load())
+}
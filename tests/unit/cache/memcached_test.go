@@ -0,0 +1,155 @@
+package cachetest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMemcached speaks enough of the Memcached text protocol for MemcachedCache, keeping
+// values in a map and recording the key and exptime of every set
+type fakeMemcached struct {
+	listener net.Listener
+	mu       sync.Mutex
+	values   map[string][]byte
+	expiries map[string]int64
+}
+
+func newFakeMemcached(t *testing.T) *fakeMemcached {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f := &fakeMemcached{listener: listener, values: map[string][]byte{}, expiries: map[string]int64{}}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeMemcached) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		f.mu.Lock()
+		switch {
+		case len(fields) == 1 && fields[0] == "version":
+			fmt.Fprint(conn, "VERSION 1.6.0\r\n")
+		case len(fields) == 2 && fields[0] == "get":
+			if value, ok := f.values[fields[1]]; ok {
+				fmt.Fprintf(conn, "VALUE %s 0 %d\r\n%s\r\n", fields[1], len(value), value)
+			}
+			fmt.Fprint(conn, "END\r\n")
+		case len(fields) == 5 && fields[0] == "set":
+			size, _ := strconv.Atoi(fields[4])
+			data := make([]byte, size+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				f.mu.Unlock()
+				return
+			}
+			f.values[fields[1]] = data[:size]
+			f.expiries[fields[1]], _ = strconv.ParseInt(fields[3], 10, 64)
+			fmt.Fprint(conn, "STORED\r\n")
+		case len(fields) == 2 && fields[0] == "delete":
+			if _, ok := f.values[fields[1]]; ok {
+				delete(f.values, fields[1])
+				fmt.Fprint(conn, "DELETED\r\n")
+			} else {
+				fmt.Fprint(conn, "NOT_FOUND\r\n")
+			}
+		default:
+			fmt.Fprint(conn, "ERROR\r\n")
+		}
+		f.mu.Unlock()
+	}
+}
+
+func (f *fakeMemcached) expiry(key string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.expiries[key]
+}
+
+func (f *fakeMemcached) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []string
+	for key := range f.values {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestMemcachedCache(t *testing.T) {
+	server := newFakeMemcached(t)
+	c, err := cache.NewMemcachedCache(server.listener.Addr().String())
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	type result struct {
+		Status string `json:"status"`
+		Score  int    `json:"score"`
+	}
+	var dest result
+	assert.ErrorIs(t, c.Get(ctx, "result:user@example.com", &dest), cache.ErrMiss)
+
+	require.NoError(t, c.Set(ctx, "result:user@example.com", result{Status: "VALID", Score: 100}, 90*time.Second))
+	require.NoError(t, c.Get(ctx, "result:user@example.com", &dest))
+	assert.Equal(t, result{Status: "VALID", Score: 100}, dest)
+	assert.Equal(t, int64(90), server.expiry("result:user@example.com"))
+
+	require.NoError(t, c.Delete(ctx, "result:user@example.com"))
+	assert.ErrorIs(t, c.Get(ctx, "result:user@example.com", &dest), cache.ErrMiss)
+	assert.NoError(t, c.Delete(ctx, "result:user@example.com"), "deleting a missing key is not an error")
+}
+
+func TestMemcachedCacheHashesUnsupportedKeys(t *testing.T) {
+	server := newFakeMemcached(t)
+	c, err := cache.NewMemcachedCache(server.listener.Addr().String())
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	for _, key := range []string{`result:"john doe"@example.com`, "result:" + strings.Repeat("a", 300) + "@example.com"} {
+		require.NoError(t, c.Set(ctx, key, key, time.Minute))
+		var dest string
+		require.NoError(t, c.Get(ctx, key, &dest))
+		assert.Equal(t, key, dest)
+	}
+	for _, stored := range server.keys() {
+		assert.True(t, strings.HasPrefix(stored, "sha256:"), "key %q stored as is", stored)
+	}
+}
+
+func TestMemcachedCacheUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	_, err = cache.NewMemcachedCache(addr)
+	assert.Error(t, err)
+}
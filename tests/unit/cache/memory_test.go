@@ -0,0 +1,51 @@
+package cachetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache()
+
+	var dest map[string]int
+	assert.ErrorIs(t, c.Get(ctx, "key", &dest), cache.ErrMiss)
+
+	require.NoError(t, c.Set(ctx, "key", map[string]int{"a": 1}, time.Minute))
+	require.NoError(t, c.Get(ctx, "key", &dest))
+	assert.Equal(t, map[string]int{"a": 1}, dest)
+
+	require.NoError(t, c.Delete(ctx, "key"))
+	assert.ErrorIs(t, c.Get(ctx, "key", &dest), cache.ErrMiss)
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache()
+
+	require.NoError(t, c.Set(ctx, "short", 1, 10*time.Millisecond))
+	require.NoError(t, c.Set(ctx, "forever", 2, 0))
+	time.Sleep(20 * time.Millisecond)
+
+	var dest int
+	assert.ErrorIs(t, c.Get(ctx, "short", &dest), cache.ErrMiss)
+	require.NoError(t, c.Get(ctx, "forever", &dest))
+	assert.Equal(t, 2, dest)
+	assert.Equal(t, 1, c.Len(), "the expired entry is dropped when read")
+}
+
+func TestNewCacheBackend(t *testing.T) {
+	c, err := cache.New(cache.BackendMemory, "")
+	require.NoError(t, err)
+	assert.IsType(t, &cache.MemoryCache{}, c)
+
+	_, err = cache.New("couchbase", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package cachetest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeyPair writes a self-signed certificate and its key to dir and returns their paths
+func writeKeyPair(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestRedisTLSOptions(t *testing.T) {
+	config, err := cache.RedisTLSOptions{}.TLSConfig()
+	require.NoError(t, err)
+	assert.Nil(t, config, "no options, no TLS config")
+
+	certFile, keyFile := writeKeyPair(t, t.TempDir())
+	config, err = cache.RedisTLSOptions{CAFile: certFile, CertFile: certFile, KeyFile: keyFile}.TLSConfig()
+	require.NoError(t, err)
+	assert.NotNil(t, config.RootCAs)
+	assert.Len(t, config.Certificates, 1)
+	assert.False(t, config.InsecureSkipVerify)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+
+	config, err = cache.RedisTLSOptions{InsecureSkipVerify: true}.TLSConfig()
+	require.NoError(t, err)
+	assert.True(t, config.InsecureSkipVerify)
+}
+
+func TestRedisTLSOptionsErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir)
+	notPEM := filepath.Join(dir, "not.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name    string
+		options cache.RedisTLSOptions
+	}{
+		{"Certificate without key", cache.RedisTLSOptions{CertFile: certFile}},
+		{"Key without certificate", cache.RedisTLSOptions{KeyFile: keyFile}},
+		{"Missing CA file", cache.RedisTLSOptions{CAFile: filepath.Join(dir, "missing.pem")}},
+		{"CA file without certificates", cache.RedisTLSOptions{CAFile: notPEM}},
+		{"Mismatched key", cache.RedisTLSOptions{CertFile: certFile, KeyFile: notPEM}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.options.TLSConfig()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseRedisURLWithTLS(t *testing.T) {
+	opt, err := cache.ParseRedisURL("redis://cache.internal:6379")
+	require.NoError(t, err)
+	assert.Nil(t, opt.TLSConfig)
+
+	opt, err = cache.ParseRedisURL("redis://cache.internal:6379", cache.WithRedisTLS(nil))
+	require.NoError(t, err)
+	assert.Nil(t, opt.TLSConfig, "a nil config leaves the URL's settings")
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: true}
+	opt, err = cache.ParseRedisURL("redis://cache.internal:6379", cache.WithRedisTLS(config))
+	require.NoError(t, err)
+	require.NotNil(t, opt.TLSConfig, "TLS options enable TLS for a redis:// URL")
+	assert.Equal(t, "cache.internal", opt.TLSConfig.ServerName)
+	assert.True(t, opt.TLSConfig.InsecureSkipVerify)
+	assert.Empty(t, config.ServerName, "the caller's config is not modified")
+
+	opt, err = cache.ParseRedisURL("rediss://managed.example.com:6380", cache.WithRedisTLS(config))
+	require.NoError(t, err)
+	assert.Equal(t, "managed.example.com", opt.TLSConfig.ServerName)
+
+	_, err = cache.ParseRedisURL("http://cache.internal")
+	assert.Error(t, err)
+}
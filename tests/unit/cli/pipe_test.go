@@ -0,0 +1,118 @@
+package clitest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"emailvalidator/internal/cli"
+	"emailvalidator/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubValidator reports every address containing "@" as VALID and any other as INVALID_FORMAT
+type stubValidator struct{}
+
+func (stubValidator) ValidateEmail(email string) model.EmailValidationResponse {
+	if strings.Contains(email, "@") {
+		return model.EmailValidationResponse{Email: email, Status: model.ValidationStatusValid, Score: 100}
+	}
+	return model.EmailValidationResponse{Email: email, Status: model.ValidationStatusInvalidFormat, ReasonCode: model.ReasonSyntaxInvalid}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := cli.ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, cli.FormatJSON, format)
+
+	format, err = cli.ParseFormat("text")
+	require.NoError(t, err)
+	assert.Equal(t, cli.FormatText, format)
+
+	_, err = cli.ParseFormat("csv")
+	assert.Error(t, err)
+}
+
+func TestPipeUntilEOF(t *testing.T) {
+	in := strings.NewReader("user@example.com\n\n# a comment\n  not-an-email  \n")
+	var out bytes.Buffer
+
+	err := cli.Pipe(context.Background(), in, &out, stubValidator{}, cli.FormatText)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com\tVALID\t100\t\nnot-an-email\tINVALID_FORMAT\t0\tSYNTAX_INVALID\n", out.String())
+}
+
+func TestPipeReportsOverlongLineAndContinues(t *testing.T) {
+	overlong := strings.Repeat("x", cli.MaxLineLength+100)
+	in := strings.NewReader(overlong + "\nuser@example.com\n")
+	var out bytes.Buffer
+
+	err := cli.Pipe(context.Background(), in, &out, stubValidator{}, cli.FormatText)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, strings.Repeat("x", cli.MaxLineLength)+"\tINVALID_FORMAT\t0\tSYNTAX_INVALID", lines[0])
+	assert.Equal(t, "user@example.com\tVALID\t100\t", lines[1])
+}
+
+func TestPipeWritesJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	err := cli.Pipe(context.Background(), strings.NewReader("user@example.com\nother@example.org"), &out, stubValidator{}, cli.FormatJSON)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	var result model.EmailValidationResponse
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &result))
+	assert.Equal(t, "other@example.org", result.Email)
+	assert.Equal(t, model.ValidationStatusValid, result.Status)
+}
+
+func TestPipeFlushesEachResult(t *testing.T) {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- cli.Pipe(context.Background(), inReader, outWriter, stubValidator{}, cli.FormatText)
+		outWriter.Close()
+	}()
+
+	results := bufio.NewScanner(outReader)
+	_, err := io.WriteString(inWriter, "first@example.com\n")
+	require.NoError(t, err)
+	require.True(t, results.Scan(), "the result is written before more input arrives")
+	assert.True(t, strings.HasPrefix(results.Text(), "first@example.com\t"))
+
+	_, err = io.WriteString(inWriter, "second@example.com\n")
+	require.NoError(t, err)
+	require.True(t, results.Scan())
+	assert.True(t, strings.HasPrefix(results.Text(), "second@example.com\t"))
+
+	inWriter.Close()
+	assert.NoError(t, <-done)
+}
+
+func TestPipeStopsWhenCancelled(t *testing.T) {
+	inReader, inWriter := io.Pipe()
+	defer inWriter.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- cli.Pipe(ctx, inReader, io.Discard, stubValidator{}, cli.FormatText)
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Pipe did not return after cancellation")
+	}
+}
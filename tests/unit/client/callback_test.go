@@ -0,0 +1,48 @@
+package clienttest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/client"
+	"emailvalidator/pkg/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyCallback(t *testing.T) {
+	secret := []byte("shared-secret")
+	payload := `{"results":[]}`
+
+	t.Run("Signed", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(payload))
+		r.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, time.Now(), []byte(payload)))
+
+		body, err := client.VerifyCallback(r, secret)
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(body))
+
+		// The body can still be read by the handler
+		restored, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(restored))
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(payload))
+		_, err := client.VerifyCallback(r, secret)
+		assert.ErrorIs(t, err, webhook.ErrMissingSignature)
+	})
+
+	t.Run("Signed with another secret", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(payload))
+		r.Header.Set(webhook.SignatureHeader, webhook.Sign([]byte("other"), time.Now(), []byte(payload)))
+		_, err := client.VerifyCallback(r, secret)
+		assert.ErrorIs(t, err, webhook.ErrInvalidSignature)
+	})
+}
@@ -0,0 +1,202 @@
+package clienttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns a client for a server that answers with handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *client.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c, err := client.New(server.URL)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		wantErr bool
+	}{
+		{"http://localhost:8080", false},
+		{"https://validator.example.com/prefix", false},
+		{"ftp://validator.example.com", true},
+		{"localhost:8080", true},
+		{"http://", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			_, err := client.New(tt.baseURL)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/validate", r.URL.Path)
+		assert.Equal(t, "flag", r.URL.Query().Get("disposable_policy"))
+		assert.Equal(t, "rfc5322", r.URL.Query().Get("syntax_mode"))
+
+		var req model.EmailValidationRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.NoError(t, json.NewEncoder(w).Encode(model.EmailValidationResponse{
+			Email:  req.Email,
+			Score:  100,
+			Status: model.ValidationStatusValid,
+		}))
+	})
+
+	result, err := c.Validate(context.Background(), "user@example.com", &client.Options{
+		DisposablePolicy: "flag",
+		SyntaxMode:       "rfc5322",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", result.Email)
+	assert.Equal(t, client.StatusValid, result.Status)
+	assert.Equal(t, 100, result.Score)
+}
+
+func TestValidateBatch(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/validate/batch", r.URL.Path)
+		assert.Equal(t, "email,status", r.URL.Query().Get("fields"))
+
+		var req model.BatchValidationRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var response model.BatchValidationResponse
+		for _, email := range req.Emails {
+			response.Results = append(response.Results, model.EmailValidationResponse{Email: email, Status: model.ValidationStatusInvalidFormat})
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	})
+
+	result, err := c.ValidateBatch(context.Background(), []string{"a", "b"}, &client.Options{Fields: []string{"email", "status"}})
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "a", result.Results[0].Email)
+	assert.Equal(t, "b", result.Results[1].Email)
+	assert.Equal(t, client.StatusInvalidFormat, result.Results[1].Status)
+}
+
+func TestCheckDisposable(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/check-disposable", r.URL.Path)
+		// Without options only the score scale the result types hold is requested
+		assert.Equal(t, "scale=percent", r.URL.RawQuery)
+		assert.NoError(t, json.NewEncoder(w).Encode(model.EmailValidationResponse{
+			Email:       "user@mailinator.com",
+			Validations: model.ValidationResults{IsDisposable: true},
+			Status:      model.ValidationStatusDisposable,
+		}))
+	})
+
+	result, err := c.CheckDisposable(context.Background(), "user@mailinator.com", nil)
+	require.NoError(t, err)
+	assert.True(t, result.Validations.IsDisposable)
+	assert.Equal(t, client.StatusDisposable, result.Status)
+}
+
+func TestSuggest(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/typo-suggestions", r.URL.Path)
+		assert.Equal(t, "0.8", r.URL.Query().Get("min_confidence"))
+		assert.NoError(t, json.NewEncoder(w).Encode(model.TypoSuggestionResponse{
+			Email:          "user@gmial.com",
+			TypoSuggestion: "user@gmail.com",
+			Confidence:     0.8,
+		}))
+	})
+
+	confidence := 0.8
+	result, err := c.Suggest(context.Background(), "user@gmial.com", &client.Options{MinConfidence: &confidence})
+	require.NoError(t, err)
+	assert.Equal(t, "user@gmail.com", result.TypoSuggestion)
+}
+
+func TestBaseURLPathPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/validator/api/validate", r.URL.Path)
+		assert.NoError(t, json.NewEncoder(w).Encode(model.EmailValidationResponse{}))
+	}))
+	defer server.Close()
+
+	c, err := client.New(server.URL + "/validator/")
+	require.NoError(t, err)
+	_, err = c.Validate(context.Background(), "user@example.com", nil)
+	assert.NoError(t, err)
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		wantMessage string
+	}{
+		{
+			name:        "JSON error",
+			contentType: "application/json",
+			body:        `{"error": "Invalid request body"}`,
+			status:      http.StatusBadRequest,
+			wantMessage: "Invalid request body",
+		},
+		{
+			name:        "Plain text error",
+			contentType: "text/plain",
+			body:        "Email parameter is required\n",
+			status:      http.StatusBadRequest,
+			wantMessage: "Email parameter is required",
+		},
+		{
+			name:   "Empty body",
+			status: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			_, err := c.Validate(context.Background(), "user@example.com", nil)
+			var apiErr *client.APIError
+			require.True(t, errors.As(err, &apiErr), "got %v", err)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.wantMessage, apiErr.Message)
+		})
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.Validate(ctx, "user@example.com", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
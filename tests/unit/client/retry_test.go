@@ -0,0 +1,173 @@
+package clienttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/pkg/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer fails the first failures requests with status, then answers successfully
+type flakyServer struct {
+	failures   int64
+	status     int
+	retryAfter string
+	attempts   atomic.Int64
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.attempts.Add(1) <= s.failures {
+		if s.retryAfter != "" {
+			w.Header().Set("Retry-After", s.retryAfter)
+		}
+		w.WriteHeader(s.status)
+		return
+	}
+	var req model.EmailValidationRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	_ = json.NewEncoder(w).Encode(model.EmailValidationResponse{Email: req.Email, Status: model.ValidationStatusValid})
+}
+
+// newFlakyClient returns a client for flaky with policy
+func newFlakyClient(t *testing.T, flaky *flakyServer, policy client.RetryPolicy) *client.Client {
+	server := httptest.NewServer(flaky)
+	t.Cleanup(server.Close)
+	c, err := client.New(server.URL)
+	require.NoError(t, err)
+	c.SetRetryPolicy(policy)
+	return c
+}
+
+// fastRetries retries up to attempts times with negligible waits
+func fastRetries(attempts int) client.RetryPolicy {
+	return client.RetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"Service unavailable", http.StatusServiceUnavailable},
+		{"Bad gateway", http.StatusBadGateway},
+		{"Internal server error", http.StatusInternalServerError},
+		{"Too many requests", http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyServer{failures: 2, status: tt.status}
+			c := newFlakyClient(t, flaky, fastRetries(3))
+
+			result, err := c.Validate(context.Background(), "user@example.com", nil)
+			require.NoError(t, err)
+			assert.Equal(t, "user@example.com", result.Email)
+			assert.Equal(t, int64(3), flaky.attempts.Load())
+		})
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	flaky := &flakyServer{failures: 1, status: http.StatusServiceUnavailable}
+	c := newFlakyClient(t, flaky, client.RetryPolicy{})
+
+	_, err := c.Validate(context.Background(), "user@example.com", nil)
+	var apiErr *client.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, int64(1), flaky.attempts.Load())
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	flaky := &flakyServer{failures: 10, status: http.StatusServiceUnavailable}
+	c := newFlakyClient(t, flaky, fastRetries(3))
+
+	_, err := c.ValidateBatch(context.Background(), []string{"user@example.com"}, nil)
+	var apiErr *client.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, int64(3), flaky.attempts.Load())
+}
+
+func TestRetrySkipsPermanentFailures(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusNotImplemented} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			flaky := &flakyServer{failures: 1, status: status}
+			c := newFlakyClient(t, flaky, fastRetries(3))
+
+			_, err := c.Validate(context.Background(), "user@example.com", nil)
+			assert.Error(t, err)
+			assert.Equal(t, int64(1), flaky.attempts.Load())
+		})
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	// The backoff alone would wait far longer than the test runs
+	slowBackoff := client.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+
+	tests := []struct {
+		name       string
+		retryAfter string
+	}{
+		{"Seconds", "0"},
+		{"HTTP date in the past", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyServer{failures: 1, status: http.StatusTooManyRequests, retryAfter: tt.retryAfter}
+			c := newFlakyClient(t, flaky, slowBackoff)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err := c.Validate(ctx, "user@example.com", nil)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), flaky.attempts.Load())
+		})
+	}
+}
+
+func TestRetryWaitsForRetryAfter(t *testing.T) {
+	flaky := &flakyServer{failures: 1, status: http.StatusServiceUnavailable, retryAfter: "1"}
+	c := newFlakyClient(t, flaky, fastRetries(2))
+
+	start := time.Now()
+	_, err := c.Validate(context.Background(), "user@example.com", nil)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	flaky := &flakyServer{failures: 10, status: http.StatusServiceUnavailable}
+	c := newFlakyClient(t, flaky, client.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.Validate(ctx, "user@example.com", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	// A closed server refuses connections
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	c, err := client.New(server.URL)
+	require.NoError(t, err)
+	c.SetRetryPolicy(fastRetries(3))
+
+	_, err = c.Validate(context.Background(), "user@example.com", nil)
+	assert.Error(t, err)
+	var apiErr *client.APIError
+	assert.False(t, errors.As(err, &apiErr))
+}
@@ -99,3 +99,36 @@ func TestNullMXRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckMX(t *testing.T) {
+	mockResolver := &MockResolver{
+		MXResults: map[string][]*net.MX{
+			"example.com": {{Host: "mail.example.com.", Pref: 10}},
+			"gmail.dk":    {{Host: ".", Pref: 0}},
+			"literal.com": {{Host: "192.0.2.25", Pref: 10}, {Host: "[2001:db8::25]", Pref: 20}},
+			"mixed.com":   {{Host: "192.0.2.25", Pref: 10}, {Host: "mail.mixed.com", Pref: 20}},
+		},
+		MXErrors: map[string]error{},
+	}
+	domainValidator := validator.NewDomainValidator(mockResolver, validator.NewDomainCacheManager(0))
+
+	testCases := []struct {
+		name   string
+		domain string
+		want   validator.MXCheck
+	}{
+		{"Hostname MX", "example.com", validator.MXCheck{HasMX: true}},
+		{"Null MX", "gmail.dk", validator.MXCheck{NullMX: true}},
+		{"Only IP-literal MX", "literal.com", validator.MXCheck{IPLiteralMX: true}},
+		{"IP-literal and hostname MX", "mixed.com", validator.MXCheck{HasMX: true, IPLiteralMX: true}},
+		{"No MX", "nonexistent.com", validator.MXCheck{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := domainValidator.CheckMX(tc.domain); got != tc.want {
+				t.Errorf("CheckMX for %s returned %+v, expected %+v", tc.domain, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package eventstest contains unit tests for the events package
+package eventstest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/events"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingPublisher records published events and can block until released
+type recordingPublisher struct {
+	mu      sync.Mutex
+	events  []events.Event
+	release chan struct{}
+	err     error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) error {
+	if p.release != nil {
+		<-p.release
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return p.err
+}
+
+func (p *recordingPublisher) Events() []events.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]events.Event(nil), p.events...)
+}
+
+func TestNewEventHashesEmail(t *testing.T) {
+	event := events.NewEvent(" User@Example.COM ", "VALID", 100)
+
+	assert.Equal(t, events.HashEmail("user@example.com"), event.EmailHash)
+	assert.Len(t, event.EmailHash, 64)
+	assert.NotContains(t, event.EmailHash, "user")
+	assert.Equal(t, "example.com", event.Domain)
+	assert.Equal(t, "VALID", event.Status)
+	assert.Equal(t, 100, event.Score)
+	assert.False(t, event.Timestamp.IsZero())
+}
+
+func TestAsyncSinkPublishesInOrderAndDrainsOnClose(t *testing.T) {
+	publisher := &recordingPublisher{}
+	sink := events.NewAsyncSink(publisher, 10)
+
+	sink.Emit(events.NewEvent("a@example.com", "VALID", 100))
+	sink.Emit(events.NewEvent("b@example.com", "INVALID", 0))
+	assert.NoError(t, sink.Close())
+
+	published := publisher.Events()
+	if assert.Len(t, published, 2) {
+		assert.Equal(t, events.HashEmail("a@example.com"), published[0].EmailHash)
+		assert.Equal(t, events.HashEmail("b@example.com"), published[1].EmailHash)
+	}
+	assert.Zero(t, sink.Dropped())
+}
+
+func TestAsyncSinkDropsWhenFull(t *testing.T) {
+	publisher := &recordingPublisher{release: make(chan struct{})}
+	sink := events.NewAsyncSink(publisher, 2)
+
+	// The first event is picked up by the publishing goroutine and blocks there,
+	// the next two fill the buffer, and the rest are dropped
+	sink.Emit(events.NewEvent("0@example.com", "VALID", 100))
+	assert.Eventually(t, func() bool {
+		sink.Emit(events.NewEvent("probe@example.com", "VALID", 100))
+		return sink.Dropped() > 0
+	}, time.Second, time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		sink.Emit(events.NewEvent("x@example.com", "VALID", 100))
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "Emit must not block when the buffer is full")
+
+	close(publisher.release)
+	assert.NoError(t, sink.Close())
+
+	// One in flight plus a full buffer were published; everything else was dropped
+	assert.Len(t, publisher.Events(), 3)
+	assert.GreaterOrEqual(t, sink.Dropped(), uint64(100))
+}
+
+func TestAsyncSinkCountsPublishFailures(t *testing.T) {
+	publisher := &recordingPublisher{err: errors.New("stream unavailable")}
+	sink := events.NewAsyncSink(publisher, 10)
+
+	sink.Emit(events.NewEvent("a@example.com", "VALID", 100))
+	sink.Emit(events.NewEvent("b@example.com", "VALID", 100))
+	assert.NoError(t, sink.Close())
+
+	assert.Equal(t, uint64(2), sink.Failed())
+}
+
+func TestAsyncSinkDropsAfterClose(t *testing.T) {
+	publisher := &recordingPublisher{}
+	sink := events.NewAsyncSink(publisher, 10)
+	assert.NoError(t, sink.Close())
+
+	assert.NotPanics(t, func() {
+		sink.Emit(events.NewEvent("late@example.com", "VALID", 100))
+	})
+	assert.NoError(t, sink.Close(), "Close may be called again")
+	assert.Empty(t, publisher.Events())
+	assert.Equal(t, uint64(1), sink.Dropped())
+}
@@ -0,0 +1,192 @@
+// Package monitoringtest contains unit tests for the monitoring package
+package monitoringtest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/monitoring"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingBackend implements monitoring.Backend and records every call
+type recordingBackend struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (b *recordingBackend) record(call string) {
+	b.mu.Lock()
+	b.calls = append(b.calls, call)
+	b.mu.Unlock()
+}
+
+func (b *recordingBackend) IncCounter(name string, labels monitoring.Labels) {
+	b.record("counter:" + name)
+}
+
+func (b *recordingBackend) ObserveHistogram(name string, value float64, labels monitoring.Labels) {
+	b.record("histogram:" + name)
+}
+
+func (b *recordingBackend) SetGauge(name string, value float64, labels monitoring.Labels) {
+	b.record("gauge:" + name)
+}
+
+func (b *recordingBackend) AddGauge(name string, delta float64, labels monitoring.Labels) {
+	b.record("gauge_add:" + name)
+}
+
+func TestSetBackendRoutesRecordFunctions(t *testing.T) {
+	original := monitoring.CurrentBackend()
+	defer monitoring.SetBackend(original)
+
+	backend := &recordingBackend{}
+	monitoring.SetBackend(backend)
+
+	monitoring.RecordRequest("/validate", "OK", 10*time.Millisecond)
+	monitoring.RecordValidationScore("overall", 90)
+	monitoring.BatchRequestStarted()
+
+	assert.Equal(t, []string{
+		"counter:" + monitoring.MetricRequestsTotal,
+		"histogram:" + monitoring.MetricRequestDuration,
+		"histogram:" + monitoring.MetricValidationScores,
+		"gauge_add:" + monitoring.MetricConcurrentBatchRequests,
+	}, backend.calls)
+}
+
+func TestStatsDBackendLineFormat(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	backend, err := monitoring.NewStatsDBackend(conn.LocalAddr().String(), "emailvalidator")
+	require.NoError(t, err)
+	defer backend.Close()
+
+	read := func() string {
+		buf := make([]byte, 1024)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	backend.IncCounter("requests_total", monitoring.Labels{"status": "OK", "endpoint": "/validate"})
+	assert.Equal(t, "emailvalidator.requests_total:1|c|#endpoint:/validate,status:OK", read())
+
+	backend.ObserveHistogram("duration", 0.25, nil)
+	assert.Equal(t, "emailvalidator.duration:0.25|h", read())
+
+	backend.AddGauge("in_flight", 1, nil)
+	assert.Equal(t, "emailvalidator.in_flight:+1|g", read())
+
+	backend.AddGauge("in_flight", -1, nil)
+	assert.Equal(t, "emailvalidator.in_flight:-1|g", read())
+
+	backend.SetGauge("memory", 1024, monitoring.Labels{"type": "heap"})
+	assert.Equal(t, "emailvalidator.memory:1024|g|#type:heap", read())
+}
+
+func TestOTLPBackendFlush(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	backend := monitoring.NewOTLPBackend(server.URL, "email-validator-test", time.Hour)
+	backend.IncCounter("requests_total", monitoring.Labels{"endpoint": "/validate"})
+	backend.IncCounter("requests_total", monitoring.Labels{"endpoint": "/validate"})
+	backend.ObserveHistogram("duration", 0.5, nil)
+	backend.ObserveHistogram("duration", 1.5, nil)
+	backend.SetGauge("goroutines", 12, nil)
+
+	require.NoError(t, backend.Flush(context.Background()))
+
+	var payload struct {
+		ResourceMetrics []struct {
+			ScopeMetrics []struct {
+				Metrics []struct {
+					Name string `json:"name"`
+					Sum  *struct {
+						DataPoints []struct {
+							AsDouble float64 `json:"asDouble"`
+						} `json:"dataPoints"`
+					} `json:"sum"`
+					Gauge *struct {
+						DataPoints []struct {
+							AsDouble float64 `json:"asDouble"`
+						} `json:"dataPoints"`
+					} `json:"gauge"`
+					Histogram *struct {
+						DataPoints []struct {
+							Count string  `json:"count"`
+							Sum   float64 `json:"sum"`
+						} `json:"dataPoints"`
+					} `json:"histogram"`
+				} `json:"metrics"`
+			} `json:"scopeMetrics"`
+		} `json:"resourceMetrics"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.True(t, strings.Contains(string(body), "email-validator-test"))
+
+	metrics := payload.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 3)
+
+	assert.Equal(t, "duration", metrics[0].Name)
+	assert.Equal(t, "2", metrics[0].Histogram.DataPoints[0].Count)
+	assert.Equal(t, 2.0, metrics[0].Histogram.DataPoints[0].Sum)
+
+	assert.Equal(t, "goroutines", metrics[1].Name)
+	assert.Equal(t, 12.0, metrics[1].Gauge.DataPoints[0].AsDouble)
+
+	assert.Equal(t, "requests_total", metrics[2].Name)
+	assert.Equal(t, 2.0, metrics[2].Sum.DataPoints[0].AsDouble)
+}
+
+type headerTransport struct {
+	userAgent string
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOTLPBackendWithClientUsesInjectedClient(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: headerTransport{userAgent: "shared-client"}}
+	backend := monitoring.NewOTLPBackendWithClient(client, server.URL, "email-validator-test", time.Hour)
+	backend.SetGauge("goroutines", 1, nil)
+
+	require.NoError(t, backend.Flush(context.Background()))
+	assert.Equal(t, "shared-client", userAgent)
+}
+
+func TestPrometheusBackendSetsUnlabelledGoroutineGauge(t *testing.T) {
+	monitoring.PrometheusBackend{}.SetGauge(monitoring.MetricActiveGoroutines, 7, nil)
+	assert.Equal(t, 7.0, testutil.ToFloat64(monitoring.ActiveGoroutines))
+}
@@ -0,0 +1,99 @@
+package monitoringtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"emailvalidator/pkg/monitoring"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gaugeBackend implements monitoring.Backend and keeps the current value of every gauge
+type gaugeBackend struct {
+	mu     sync.Mutex
+	gauges map[string]float64
+}
+
+func newGaugeBackend() *gaugeBackend {
+	return &gaugeBackend{gauges: make(map[string]float64)}
+}
+
+func (b *gaugeBackend) value(name string) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.gauges[name]
+}
+
+func (b *gaugeBackend) IncCounter(name string, labels monitoring.Labels) {}
+
+func (b *gaugeBackend) ObserveHistogram(name string, value float64, labels monitoring.Labels) {}
+
+func (b *gaugeBackend) SetGauge(name string, value float64, labels monitoring.Labels) {
+	b.mu.Lock()
+	b.gauges[name] = value
+	b.mu.Unlock()
+}
+
+func (b *gaugeBackend) AddGauge(name string, delta float64, labels monitoring.Labels) {
+	b.mu.Lock()
+	b.gauges[name] += delta
+	b.mu.Unlock()
+}
+
+func TestInFlightRequestsGauge(t *testing.T) {
+	original := monitoring.CurrentBackend()
+	defer monitoring.SetBackend(original)
+	backend := newGaugeBackend()
+	monitoring.SetBackend(backend)
+
+	var during float64
+	handler := monitoring.MetricsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		during = backend.value(monitoring.MetricInFlightRequests)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/validate", nil))
+
+	assert.Equal(t, 1.0, during)
+	assert.Equal(t, 0.0, backend.value(monitoring.MetricInFlightRequests))
+}
+
+func TestInFlightRequestsGaugeAfterPanic(t *testing.T) {
+	original := monitoring.CurrentBackend()
+	defer monitoring.SetBackend(original)
+	backend := newGaugeBackend()
+	monitoring.SetBackend(backend)
+
+	handler := monitoring.MetricsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	}))
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/validate", nil))
+	})
+
+	assert.Equal(t, 0.0, backend.value(monitoring.MetricInFlightRequests))
+}
+
+func TestBatchWorkerUtilization(t *testing.T) {
+	original := monitoring.CurrentBackend()
+	defer monitoring.SetBackend(original)
+	backend := newGaugeBackend()
+	monitoring.SetBackend(backend)
+
+	for i := 0; i < 4; i++ {
+		monitoring.BatchWorkerStarted()
+	}
+	monitoring.BatchWorkerBusy()
+	assert.Equal(t, 4.0, backend.value(monitoring.MetricBatchWorkers))
+	assert.Equal(t, 1.0, backend.value(monitoring.MetricBatchWorkersActive))
+	assert.Equal(t, 0.25, backend.value(monitoring.MetricBatchWorkerUtilization))
+
+	monitoring.BatchWorkerIdle()
+	for i := 0; i < 4; i++ {
+		monitoring.BatchWorkerStopped()
+	}
+	assert.Equal(t, 0.0, backend.value(monitoring.MetricBatchWorkers))
+	assert.Equal(t, 0.0, backend.value(monitoring.MetricBatchWorkersActive))
+	assert.Equal(t, 0.0, backend.value(monitoring.MetricBatchWorkerUtilization))
+}
@@ -0,0 +1,92 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressingCapabilitiesInResponse(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	tests := []struct {
+		email string
+		want  *model.AddressingCapabilities
+	}{
+		{"j.smith+news@gmail.com", &model.AddressingCapabilities{DotInsensitive: true, PlusAddressing: true, CaseInsensitive: true}},
+		{"user@outlook.com", &model.AddressingCapabilities{PlusAddressing: true, CaseInsensitive: true}},
+		{"user@example.com", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.want, svc.ValidateEmail(tt.email).Addressing)
+
+			batch := svc.ValidateEmails([]string{tt.email})
+			if assert.Len(t, batch.Results, 1) {
+				assert.Equal(t, tt.want, batch.Results[0].Addressing)
+			}
+		})
+	}
+}
+
+func TestCanonicalInResponse(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	assert.Equal(t, "jsmith@gmail.com", svc.ValidateEmail("J.Smith+news@GMail.com").Canonical)
+	assert.Empty(t, svc.ValidateEmail("not-an-email").Canonical)
+
+	batch := svc.ValidateEmails([]string{"J.Smith+news@GMail.com"})
+	if assert.Len(t, batch.Results, 1) {
+		assert.Equal(t, "jsmith@gmail.com", batch.Results[0].Canonical)
+	}
+
+	emailValidator.SetCanonicalizationRules(validator.CanonicalizationRules{LocalPartCase: validator.LocalPartCaseProvider})
+	assert.Equal(t, "j.smith+news@gmail.com", svc.ValidateEmail("J.Smith+news@GMail.com").Canonical)
+}
+
+func TestSubaddressInResponse(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	tests := []struct {
+		email   string
+		wantTag string
+		wantHas bool
+	}{
+		{"jsmith+news@gmail.com", "news", true},
+		{"jsmith-shopping@yahoo.com", "shopping", true},
+		{"anything@jsmith.fastmail.com", "anything", true},
+		{"jsmith@gmail.com", "", false},
+		{"john+tag@example.com", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := svc.ValidateEmail(tt.email)
+			assert.Equal(t, tt.wantHas, result.HasSubaddress)
+			assert.Equal(t, tt.wantTag, result.SubaddressTag)
+
+			batch := svc.ValidateEmails([]string{tt.email})
+			if assert.Len(t, batch.Results, 1) {
+				assert.Equal(t, tt.wantHas, batch.Results[0].HasSubaddress)
+				assert.Equal(t, tt.wantTag, batch.Results[0].SubaddressTag)
+			}
+		})
+	}
+}
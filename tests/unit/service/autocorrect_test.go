@@ -0,0 +1,66 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutocorrect(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	emails := []string{"user@gmai.com", "user@example.com", "not-an-address"}
+	plain := svc.ValidateEmailsWithOptions(emails, service.ValidationOptions{})
+	for _, result := range plain.Results {
+		assert.Nil(t, result.Correction, "autocorrect is opt-in")
+	}
+
+	batch := svc.ValidateEmailsWithOptions(emails, service.ValidationOptions{Autocorrect: true})
+	require.Len(t, batch.Results, 3)
+
+	typo := batch.Results[0]
+	assert.Equal(t, "user@gmai.com", typo.Email)
+	assert.Equal(t, model.ReasonPossibleTypo, typo.ReasonCode)
+	require.NotNil(t, typo.Correction)
+	assert.Equal(t, "user@gmail.com", typo.Correction.Email)
+	assert.InDelta(t, 0.89, typo.Correction.Confidence, 0.01)
+	assert.Equal(t, model.ValidationStatusValid, typo.Correction.Status)
+	require.NotNil(t, typo.Correction.Score)
+	assert.Greater(t, *typo.Correction.Score, typo.Score)
+	assert.Empty(t, typo.Correction.ReasonCode)
+
+	assert.Nil(t, batch.Results[1].Correction)
+	assert.Nil(t, batch.Results[2].Correction)
+}
+
+func TestAutocorrectThreshold(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	batch := svc.ValidateEmailsWithOptions([]string{"user@gmai.com"}, service.ValidationOptions{Autocorrect: true, AutocorrectConfidence: 0.95})
+	require.Len(t, batch.Results, 1)
+	assert.NotEmpty(t, batch.Results[0].TypoSuggestion)
+	assert.Nil(t, batch.Results[0].Correction)
+}
+
+func TestAutocorrectWithoutRevalidation(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	batch := svc.ValidateEmailsWithOptions([]string{"user@gmai.com"}, service.ValidationOptions{Autocorrect: true, SkipRevalidation: true})
+	require.Len(t, batch.Results, 1)
+	correction := batch.Results[0].Correction
+	require.NotNil(t, correction)
+	assert.Equal(t, "user@gmail.com", correction.Email)
+	assert.Empty(t, correction.Status)
+	assert.Nil(t, correction.Score)
+}
@@ -0,0 +1,178 @@
+package servicetest
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inFlightCounter records the most calls that were ever in progress at once
+type inFlightCounter struct {
+	mu      sync.Mutex
+	current int
+	peak    int
+}
+
+func (c *inFlightCounter) enter() {
+	c.mu.Lock()
+	c.current++
+	c.peak = max(c.peak, c.current)
+	c.mu.Unlock()
+}
+
+func (c *inFlightCounter) leave() {
+	c.mu.Lock()
+	c.current--
+	c.mu.Unlock()
+}
+
+func (c *inFlightCounter) Peak() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.peak
+}
+
+// slowResolver answers every lookup after delay, counting the lookups in flight
+type slowResolver struct {
+	delay    time.Duration
+	inFlight inFlightCounter
+}
+
+func (r *slowResolver) LookupHost(domain string) ([]string, error) {
+	r.inFlight.enter()
+	defer r.inFlight.leave()
+	time.Sleep(r.delay)
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *slowResolver) LookupMX(domain string) ([]*net.MX, error) {
+	r.inFlight.enter()
+	defer r.inFlight.leave()
+	time.Sleep(r.delay)
+	return []*net.MX{{Host: "mail." + domain, Pref: 10}}, nil
+}
+
+// slowMailboxVerifier accepts every mailbox after delay, standing in for an SMTP probe,
+// and counts the probes in flight
+type slowMailboxVerifier struct {
+	delay    time.Duration
+	inFlight inFlightCounter
+}
+
+func (v *slowMailboxVerifier) Verify(email string) validator.SMTPResult {
+	v.inFlight.enter()
+	defer v.inFlight.leave()
+	time.Sleep(v.delay)
+	return validator.SMTPResult{Status: validator.SMTPStatusDeliverable}
+}
+
+// benchmarkBatch returns size addresses spread over domains domains, named with prefix so
+// that separate batches do not share domain cache entries
+func benchmarkBatch(prefix string, size, domains int) []string {
+	emails := make([]string, size)
+	for i := range emails {
+		emails[i] = fmt.Sprintf("user%d@%s%d.example.com", i, prefix, i%domains)
+	}
+	return emails
+}
+
+func TestBatchWorkers(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	verifier := &slowMailboxVerifier{delay: 5 * time.Millisecond}
+	svc.SetMailboxVerifier(verifier)
+	svc.SetBatchWorkers(2)
+
+	result := svc.ValidateEmails(benchmarkBatch("workers", 20, 4))
+	require.Len(t, result.Results, 20)
+	for _, r := range result.Results {
+		assert.Equal(t, model.ValidationStatusValid, r.Status, r.Email)
+	}
+	assert.Equal(t, 2, verifier.inFlight.Peak())
+}
+
+func TestBatchDomainConcurrency(t *testing.T) {
+	resolver := &slowResolver{delay: 5 * time.Millisecond}
+	emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	// Each domain check runs its host and MX lookups concurrently
+	svc.SetBatchDomainConcurrency(1)
+	result := svc.ValidateEmails(benchmarkBatch("limited", 10, 10))
+	require.Len(t, result.Results, 10)
+	for _, r := range result.Results {
+		assert.Equal(t, model.ValidationStatusValid, r.Status, r.Email)
+	}
+	assert.LessOrEqual(t, resolver.inFlight.Peak(), 2)
+
+	svc.SetBatchDomainConcurrency(0)
+	svc.ValidateEmails(benchmarkBatch("unlimited", 10, 10))
+	assert.Greater(t, resolver.inFlight.Peak(), 2, "0 checks every domain at once")
+}
+
+func BenchmarkValidateEmailsWorkers(b *testing.B) {
+	emails := benchmarkBatch("workers", 200, 20)
+	for _, workers := range []int{1, 4, 16, 64, 256} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+			require.NoError(b, err)
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			svc.SetMailboxVerifier(&slowMailboxVerifier{delay: time.Millisecond})
+			svc.SetBatchWorkers(workers)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				svc.ValidateEmails(emails)
+			}
+			b.ReportMetric(float64(b.N*len(emails))/b.Elapsed().Seconds(), "emails/s")
+		})
+	}
+}
+
+func BenchmarkValidateEmailsDomainConcurrency(b *testing.B) {
+	for _, concurrency := range []int{1, 8, 32, 0} {
+		b.Run(fmt.Sprintf("domains=%d", concurrency), func(b *testing.B) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(&slowResolver{delay: time.Millisecond})
+			require.NoError(b, err)
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			svc.SetBatchDomainConcurrency(concurrency)
+
+			// Fresh domains in every batch keep the domain cache from answering
+			batches := make([][]string, b.N)
+			for i := range batches {
+				batches[i] = benchmarkBatch(fmt.Sprintf("b%d-", i), 200, 50)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				svc.ValidateEmails(batches[i])
+			}
+			b.ReportMetric(float64(b.N*200)/b.Elapsed().Seconds(), "emails/s")
+		})
+	}
+}
+
+func BenchmarkValidateEmailsCPUBound(b *testing.B) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(b, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	emails := benchmarkBatch("cpu", 1000, 10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		svc.ValidateEmails(emails)
+	}
+	b.ReportMetric(float64(b.N*len(emails))/b.Elapsed().Seconds(), "emails/s")
+}
@@ -0,0 +1,116 @@
+package servicetest
+
+import (
+	"context"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/tests/unit/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// failingRuleValidator accepts every address but panics while checking the ones in failOn
+type failingRuleValidator struct {
+	failOn map[string]bool
+}
+
+func (v *failingRuleValidator) ValidateSyntax(email string) bool { return true }
+
+func (v *failingRuleValidator) IsRoleBased(email string) bool {
+	if v.failOn[email] {
+		panic("smtp connection reset")
+	}
+	return false
+}
+
+func (v *failingRuleValidator) CalculateScore(validations map[string]bool) int { return 100 }
+
+func (v *failingRuleValidator) GetTypoSuggestions(email string) []string { return nil }
+
+func (v *failingRuleValidator) DetectAlias(email string) string { return "" }
+
+// failingDomainService reports every domain as valid but panics on the ones in failOn
+type failingDomainService struct {
+	failOn map[string]bool
+}
+
+func (s *failingDomainService) ValidateDomainConcurrently(ctx context.Context, domain string) (exists, hasMX, isDisposable bool) {
+	if s.failOn[domain] {
+		panic("resolver crashed")
+	}
+	return true, true, false
+}
+
+func TestBatchValidationService_IsolatesItemErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		emails        []string
+		failEmails    map[string]bool
+		failDomains   map[string]bool
+		wantErrored   []int
+		wantSucceeded []int
+	}{
+		{
+			name:          "No failures",
+			emails:        []string{"a@example.com", "b@example.org"},
+			wantSucceeded: []int{0, 1},
+		},
+		{
+			name:          "Single email panics",
+			emails:        []string{"a@example.com", "boom@example.com", "c@example.org"},
+			failEmails:    map[string]bool{"boom@example.com": true},
+			wantErrored:   []int{1},
+			wantSucceeded: []int{0, 2},
+		},
+		{
+			name:          "Domain check panics",
+			emails:        []string{"a@broken.com", "b@example.com", "c@broken.com"},
+			failDomains:   map[string]bool{"broken.com": true},
+			wantErrored:   []int{0, 2},
+			wantSucceeded: []int{1},
+		},
+		{
+			name:        "Every email fails",
+			emails:      []string{"x@example.com", "y@example.com"},
+			failEmails:  map[string]bool{"x@example.com": true, "y@example.com": true},
+			wantErrored: []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := new(mocks.MockMetricsCollector)
+			mc.On("RecordValidationScore", "overall", mock.Anything)
+
+			svc := service.NewBatchValidationService(
+				&failingRuleValidator{failOn: tt.failEmails},
+				&failingDomainService{failOn: tt.failDomains},
+				mc,
+			)
+
+			var response model.BatchValidationResponse
+			assert.NotPanics(t, func() {
+				response = svc.ValidateEmails(tt.emails)
+			})
+
+			assert.Len(t, response.Results, len(tt.emails))
+			assert.Equal(t, len(tt.wantErrored), response.Errors)
+			for _, i := range tt.wantErrored {
+				result := response.Results[i]
+				assert.Equal(t, tt.emails[i], result.Email)
+				assert.Equal(t, i, *result.Index)
+				assert.Equal(t, model.ValidationStatusError, result.Status)
+				assert.NotEmpty(t, result.Error)
+			}
+			for _, i := range tt.wantSucceeded {
+				result := response.Results[i]
+				assert.Equal(t, tt.emails[i], result.Email)
+				assert.Equal(t, model.ValidationStatusValid, result.Status)
+				assert.Empty(t, result.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckBatchSize(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	assert.NoError(t, svc.CheckBatchSize(service.DefaultMaxBatchSize))
+	assert.Error(t, svc.CheckBatchSize(service.DefaultMaxBatchSize+1))
+
+	svc.SetMaxBatchSize(10)
+	assert.NoError(t, svc.CheckBatchSize(10), "a batch exactly at the limit is accepted")
+
+	err = svc.CheckBatchSize(11)
+	var tooLarge *service.BatchTooLargeError
+	if assert.ErrorAs(t, err, &tooLarge) {
+		assert.Equal(t, 11, tooLarge.Size)
+		assert.Equal(t, 10, tooLarge.Max)
+	}
+
+	svc.SetMaxBatchSize(0)
+	assert.NoError(t, svc.CheckBatchSize(1_000_000), "0 removes the limit")
+}
@@ -0,0 +1,90 @@
+package servicetest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// heldMailboxVerifier answers at once, except for mailboxes named "slow", which it holds
+// until release is closed
+type heldMailboxVerifier struct {
+	release chan struct{}
+}
+
+func (v *heldMailboxVerifier) Verify(email string) validator.SMTPResult {
+	if strings.HasPrefix(email, "slow@") {
+		<-v.release
+	}
+	return deliverable
+}
+
+func TestBatchTimeoutReturnsPartialResults(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&lookupCountingResolver{})
+	require.NoError(t, err)
+	verifier := &heldMailboxVerifier{release: make(chan struct{})}
+	defer close(verifier.release)
+
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	svc.SetMailboxVerifier(verifier)
+	svc.SetBatchWorkers(1)
+	svc.SetBatchTimeout(100 * time.Millisecond)
+
+	start := time.Now()
+	response := svc.ValidateEmails([]string{"user@example.com", "slow@example.com", "other@example.org"})
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, model.ValidationStatusValid, response.Results[0].Status, "results completed before the deadline are kept")
+	assert.NotEmpty(t, response.Results[0].Fingerprint)
+	for _, i := range []int{1, 2} {
+		result := response.Results[i]
+		assert.Equal(t, model.ValidationStatusNotProcessed, result.Status)
+		assert.Equal(t, model.ReasonTimeout, result.ReasonCode)
+		require.NotNil(t, result.Index)
+		assert.Equal(t, i, *result.Index)
+		assert.Empty(t, result.Error)
+	}
+	assert.Equal(t, "other@example.org", response.Results[2].Email)
+	assert.Equal(t, 2, response.NotProcessed)
+	assert.Zero(t, response.Errors)
+}
+
+func TestBatchTimeoutDuringDomainChecks(t *testing.T) {
+	resolver := &blockingDNSResolver{release: make(chan struct{})}
+	defer close(resolver.release)
+	emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+	require.NoError(t, err)
+
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	svc.SetBatchTimeout(50 * time.Millisecond)
+
+	response := svc.ValidateEmails([]string{"user@example.com", "user@example.org"})
+	assert.Equal(t, 2, response.NotProcessed)
+	for _, result := range response.Results {
+		assert.Equal(t, model.ValidationStatusNotProcessed, result.Status)
+	}
+}
+
+func TestBatchCancelledContext(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&lookupCountingResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	response := svc.ValidateEmailsContext(ctx, []string{"user@example.com", "not-an-email"}, service.ValidationOptions{})
+	assert.Equal(t, 2, response.NotProcessed)
+
+	response = svc.ValidateEmailsContext(context.Background(), []string{"user@example.com", "not-an-email"}, service.ValidationOptions{})
+	assert.Zero(t, response.NotProcessed, "without a deadline every email is validated")
+	assert.Equal(t, model.ValidationStatusInvalidFormat, response.Results[1].Status)
+}
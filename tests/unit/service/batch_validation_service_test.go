@@ -3,6 +3,7 @@ package servicetest
 
 import (
 	"testing"
+	"time"
 
 	"emailvalidator/internal/model"
 	"emailvalidator/internal/service"
@@ -180,3 +181,45 @@ func TestBatchValidationService_ValidateEmails(t *testing.T) {
 		})
 	}
 }
+
+func TestBatchValidationService_ResultsFollowInputOrder(t *testing.T) {
+	// Earlier emails take longer to validate, so workers complete them out of order
+	emails := []string{
+		"first@example.com",
+		"second@example.com",
+		"third@example.com",
+		"first@example.com",
+		"fourth@example.com",
+	}
+	delays := map[string]time.Duration{
+		"first@example.com":  40 * time.Millisecond,
+		"second@example.com": 30 * time.Millisecond,
+		"third@example.com":  20 * time.Millisecond,
+		"fourth@example.com": 0,
+	}
+
+	mockRuleValidator := new(mocks.MockEmailRuleValidator)
+	mockDomainSvc := new(mocks.MockDomainValidationService)
+	mockMetrics := new(mocks.MockMetricsCollector)
+
+	for email, delay := range delays {
+		mockRuleValidator.On("ValidateSyntax", email).After(delay).Return(true)
+		mockRuleValidator.On("IsRoleBased", email).Return(false)
+		mockRuleValidator.On("DetectAlias", email).Return("")
+		mockRuleValidator.On("GetTypoSuggestions", email).Return([]string{})
+	}
+	mockRuleValidator.On("CalculateScore", mock.Anything).Return(100)
+	mockDomainSvc.On("ValidateDomainConcurrently", mock.Anything, "example.com").Return(true, true, false)
+	mockMetrics.On("RecordValidationScore", "overall", float64(100))
+
+	svc := service.NewBatchValidationService(mockRuleValidator, mockDomainSvc, mockMetrics)
+	result := svc.ValidateEmails(emails)
+
+	assert.Len(t, result.Results, len(emails))
+	for i, email := range emails {
+		assert.Equal(t, email, result.Results[i].Email)
+		if assert.NotNil(t, result.Results[i].Index) {
+			assert.Equal(t, i, *result.Results[i].Index)
+		}
+	}
+}
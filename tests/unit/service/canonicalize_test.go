@@ -0,0 +1,57 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalizeEmails(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	emails := []string{"J.Smith+news@gmail.com", "jsmith@googlemail.com", "John@Example.com.", "not-an-email", "jsmith@gmail.com"}
+	result := svc.CanonicalizeEmails(emails, service.ValidationOptions{})
+
+	require.Len(t, result.Results, len(emails))
+	wantCanonical := []string{"jsmith@gmail.com", "jsmith@gmail.com", "John@example.com", "not-an-email", "jsmith@gmail.com"}
+	for i, item := range result.Results {
+		assert.Equal(t, emails[i], item.Email)
+		assert.Equal(t, wantCanonical[i], item.Canonical)
+	}
+	assert.True(t, result.Results[3].Invalid)
+	assert.False(t, result.Results[0].Invalid)
+	assert.Equal(t, 2, result.Distinct)
+	assert.Equal(t, 1, result.Invalid)
+}
+
+func TestCanonicalizeEmailsIDN(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := svc.CanonicalizeEmails([]string{"user@例え.jp", "user@xn--r8jz45g.jp", "user@XN--R8JZ45G.JP."}, service.ValidationOptions{})
+	require.Len(t, result.Results, 3)
+	for _, item := range result.Results {
+		assert.False(t, item.Invalid, item.Email)
+		assert.Equal(t, "user@xn--r8jz45g.jp", item.Canonical, item.Email)
+	}
+	assert.Equal(t, 1, result.Distinct)
+}
+
+func TestBatchCanonicalize(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	canonical := svc.BatchCanonicalize([]string{"J.Smith+news@gmail.com", "user@@example.com"})
+	assert.Equal(t, map[string]string{
+		"J.Smith+news@gmail.com": "jsmith@gmail.com",
+		"user@@example.com":      "user@@example.com",
+	}, canonical)
+}
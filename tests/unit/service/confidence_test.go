@@ -0,0 +1,150 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubMailboxVerifier answers every mailbox probe with the same result
+type stubMailboxVerifier struct {
+	result validator.SMTPResult
+}
+
+func (v *stubMailboxVerifier) Verify(email string) validator.SMTPResult {
+	return v.result
+}
+
+var (
+	deliverable = validator.SMTPResult{Status: validator.SMTPStatusDeliverable}
+	catchAll    = validator.SMTPResult{Status: validator.SMTPStatusDeliverable, CatchAll: true}
+	greylisted  = validator.SMTPResult{Status: validator.SMTPStatusGreylisted, Code: 450}
+	rejected    = validator.SMTPResult{Status: validator.SMTPStatusUndeliverable, Code: 550}
+)
+
+func TestConfidencePenalties(t *testing.T) {
+	tests := []struct {
+		name             string
+		email            string
+		smtp             validator.SMTPResult
+		policy           service.UnknownPolicy
+		penalties        service.ConfidencePenalties
+		wantStatus       model.ValidationStatus
+		wantScore        int
+		wantMailbox      bool
+		wantInconclusive []string
+	}{
+		{
+			name:        "Deliverable mailbox",
+			email:       "user@example.com",
+			smtp:        deliverable,
+			wantStatus:  model.ValidationStatusValid,
+			wantScore:   100,
+			wantMailbox: true,
+		},
+		{
+			name:        "Catch-all caps the score",
+			email:       "user@example.com",
+			smtp:        catchAll,
+			wantStatus:  model.ValidationStatusProbablyValid,
+			wantScore:   80,
+			wantMailbox: true,
+		},
+		{
+			name:        "Catch-all and role-based",
+			email:       "admin@example.com",
+			smtp:        catchAll,
+			wantStatus:  model.ValidationStatusInvalid,
+			wantScore:   62,
+			wantMailbox: true,
+		},
+		{
+			name:        "Catch-all with a custom ceiling",
+			email:       "user@example.com",
+			smtp:        catchAll,
+			penalties:   service.ConfidencePenalties{CatchAllCeiling: 60, GreylistCeiling: 90},
+			wantStatus:  model.ValidationStatusInvalid,
+			wantScore:   60,
+			wantMailbox: true,
+		},
+		{
+			name:             "Greylisted under strict policy",
+			email:            "user@example.com",
+			smtp:             greylisted,
+			wantStatus:       model.ValidationStatusProbablyValid,
+			wantScore:        72,
+			wantMailbox:      false,
+			wantInconclusive: []string{service.CheckMailboxExists},
+		},
+		{
+			name:             "Greylisted under lenient policy is still uncertain",
+			email:            "user@example.com",
+			smtp:             greylisted,
+			policy:           service.UnknownPolicyLenient,
+			wantStatus:       model.ValidationStatusProbablyValid,
+			wantScore:        90,
+			wantMailbox:      true,
+			wantInconclusive: []string{service.CheckMailboxExists},
+		},
+		{
+			name:             "Greylisted and role-based",
+			email:            "admin@example.com",
+			smtp:             greylisted,
+			wantStatus:       model.ValidationStatusInvalid,
+			wantScore:        53,
+			wantMailbox:      false,
+			wantInconclusive: []string{service.CheckMailboxExists},
+		},
+		{
+			name:        "Undeliverable mailbox",
+			email:       "user@example.com",
+			smtp:        rejected,
+			wantStatus:  model.ValidationStatusInvalid,
+			wantScore:   80,
+			wantMailbox: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			svc.SetMailboxVerifier(&stubMailboxVerifier{result: tt.smtp})
+			if tt.policy != "" {
+				svc.SetUnknownPolicy(tt.policy)
+			}
+			if tt.penalties != (service.ConfidencePenalties{}) {
+				svc.SetConfidencePenalties(tt.penalties)
+			}
+
+			result := svc.ValidateEmail(tt.email)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantScore, result.Score)
+			assert.True(t, result.Validations.Syntax)
+			assert.Equal(t, tt.wantMailbox, result.Validations.MailboxExists)
+			assert.Equal(t, tt.smtp.CatchAll, result.Validations.IsCatchAll)
+			assert.Equal(t, tt.smtp.Status == validator.SMTPStatusGreylisted, result.Validations.IsGreylisted)
+			assert.Equal(t, tt.wantInconclusive, result.Inconclusive)
+
+			batch := svc.ValidateEmails([]string{tt.email})
+			if assert.Len(t, batch.Results, 1) {
+				assert.Equal(t, tt.wantStatus, batch.Results[0].Status)
+				assert.Equal(t, tt.wantScore, batch.Results[0].Score)
+				assert.Equal(t, tt.wantInconclusive, batch.Results[0].Inconclusive)
+			}
+		})
+	}
+}
+
+func TestConfidencePenaltiesValidate(t *testing.T) {
+	assert.NoError(t, service.DefaultConfidencePenalties().Validate())
+	assert.Error(t, service.ConfidencePenalties{CatchAllCeiling: 120, GreylistCeiling: 90}.Validate())
+	assert.Error(t, service.ConfidencePenalties{CatchAllCeiling: 80, GreylistCeiling: -1}.Validate())
+}
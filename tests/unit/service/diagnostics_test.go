@@ -0,0 +1,48 @@
+package servicetest
+
+import (
+	"testing"
+	"time"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationDiagnostics(t *testing.T) {
+	svc := newExplainService(t)
+
+	result := svc.ValidateEmail("user@example.com")
+	assert.Nil(t, result.Diagnostics, "diagnostics are only included on request")
+
+	// The validation above cached the domain
+	result = svc.ValidateEmailWithOptions("user@example.com", service.ValidationOptions{Diagnostics: true})
+	if assert.NotNil(t, result.Diagnostics) {
+		assert.True(t, result.Diagnostics.FromCache)
+		if assert.NotNil(t, result.Diagnostics.CacheAgeSeconds) {
+			assert.Equal(t, 0, *result.Diagnostics.CacheAgeSeconds)
+		}
+	}
+
+	result = svc.ValidateEmailWithOptions("user@example.org", service.ValidationOptions{Diagnostics: true})
+	if assert.NotNil(t, result.Diagnostics) {
+		assert.False(t, result.Diagnostics.FromCache)
+		assert.Nil(t, result.Diagnostics.CacheAgeSeconds)
+	}
+}
+
+func TestValidationDiagnosticsBypassResultCache(t *testing.T) {
+	svc := newExplainService(t)
+	svc.SetResultCache(service.NewResultCache(cache.NewMemoryCache(), time.Hour))
+
+	svc.ValidateEmail("user@example.com")
+	result := svc.ValidateEmailWithOptions("user@example.com", service.ValidationOptions{Diagnostics: true})
+	if assert.NotNil(t, result.Diagnostics) {
+		assert.True(t, result.Diagnostics.FromCache)
+	}
+
+	// Diagnostics are never stored in the result cache
+	result = svc.ValidateEmail("user@example.com")
+	assert.Nil(t, result.Diagnostics)
+}
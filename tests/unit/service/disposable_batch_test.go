@@ -0,0 +1,81 @@
+package servicetest
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDisposableLookup stands in for the remote blocklist
+type fakeDisposableLookup struct {
+	domains map[string]bool
+	ready   bool
+	lookups atomic.Int32
+}
+
+func (l *fakeDisposableLookup) Lookup(domain string) (disposable, ready bool) {
+	l.lookups.Add(1)
+	return l.domains[domain], l.ready
+}
+
+func newDisposableBatchService(t *testing.T) *service.EmailService {
+	t.Helper()
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	return service.NewEmailServiceWithValidator(emailValidator)
+}
+
+func TestCheckDisposableBatch(t *testing.T) {
+	svc := newDisposableBatchService(t)
+	lookup := &fakeDisposableLookup{domains: map[string]bool{"remote-temp.com": true}, ready: true}
+
+	items := []string{
+		"mailinator.com",
+		"user@Mailinator.COM",
+		"example.com",
+		"someone@remote-temp.com",
+		"not a domain",
+		"",
+		"user@@example.com",
+	}
+	response := svc.CheckDisposableBatch(items, lookup)
+	require.Len(t, response.Results, len(items))
+
+	for i, result := range response.Results {
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, items[i], result.Input)
+	}
+	assert.True(t, response.Results[0].IsDisposable)
+	assert.True(t, response.Results[1].IsDisposable, "an address is checked by its domain")
+	assert.Equal(t, "mailinator.com", response.Results[1].Domain)
+	assert.False(t, response.Results[2].IsDisposable)
+	assert.True(t, response.Results[3].IsDisposable, "the lookup is consulted for domains the validator does not flag")
+	for _, result := range response.Results[4:] {
+		assert.NotEmpty(t, result.Error)
+		assert.Empty(t, result.Domain)
+		assert.False(t, result.IsDisposable)
+	}
+	assert.Equal(t, 3, response.Errors)
+
+	// mailinator.com is listed locally, so the lookup only sees the other two domains
+	assert.Equal(t, int32(2), lookup.lookups.Load())
+}
+
+func TestCheckDisposableBatchWhileBlocklistLoads(t *testing.T) {
+	svc := newDisposableBatchService(t)
+	lookup := &fakeDisposableLookup{ready: false}
+
+	response := svc.CheckDisposableBatch([]string{"mailinator.com", "example.com"}, lookup)
+	assert.True(t, response.Results[0].IsDisposable)
+	assert.Empty(t, response.Results[0].Inconclusive, "a domain listed locally is disposable whatever the blocklist says")
+	assert.False(t, response.Results[1].IsDisposable)
+	assert.Equal(t, []string{service.CheckIsDisposable}, response.Results[1].Inconclusive)
+
+	response = svc.CheckDisposableBatch([]string{"example.com"}, nil)
+	assert.Empty(t, response.Results[0].Inconclusive, "without a blocklist only the validator's lists are checked")
+}
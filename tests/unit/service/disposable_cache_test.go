@@ -0,0 +1,84 @@
+package servicetest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/cache"
+	"emailvalidator/pkg/validator"
+	"emailvalidator/tests/unit/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisposableCacheAvoidsRepeatedChecks(t *testing.T) {
+	mv := new(mocks.MockDomainValidator)
+	mv.On("ValidateDomain", "mailinator.com").Return(true)
+	mv.On("ValidateMXRecords", "mailinator.com").Return(true)
+	mv.On("IsDisposable", "mailinator.com").Return(true).Once()
+
+	svc := service.NewConcurrentDomainValidationService(mv)
+	svc.SetDisposableCache(cache.NewDisposableCache(cache.NewMockCache(), cache.DefaultDisposableTTL))
+
+	for i := 0; i < 3; i++ {
+		_, _, isDisposable := svc.ValidateDomainConcurrently(context.Background(), "mailinator.com")
+		assert.True(t, isDisposable)
+	}
+	mv.AssertNumberOfCalls(t, "IsDisposable", 1)
+}
+
+func TestDisposableCacheVersion(t *testing.T) {
+	shared := cache.NewMockCache()
+	disposableCache := cache.NewDisposableCache(shared, cache.DefaultDisposableTTL)
+	disposableCache.SetVersion("v1")
+	ctx := context.Background()
+
+	_, ok := disposableCache.Get(ctx, "example.com")
+	assert.False(t, ok)
+
+	disposableCache.Set(ctx, "example.com", true)
+	disposable, ok := disposableCache.Get(ctx, "Example.com")
+	assert.True(t, ok)
+	assert.True(t, disposable)
+
+	// Determinations made against the previous list are not served after a refresh
+	disposableCache.SetVersion("v2")
+	_, ok = disposableCache.Get(ctx, "example.com")
+	assert.False(t, ok)
+
+	// Another instance, or this one after a restart, loading the same lists reads them back
+	restarted := cache.NewDisposableCache(shared, cache.DefaultDisposableTTL)
+	restarted.SetVersion("v1")
+	disposable, ok = restarted.Get(ctx, "example.com")
+	assert.True(t, ok)
+	assert.True(t, disposable)
+}
+
+func TestHashFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "disposable.txt")
+	require.NoError(t, os.WriteFile(path, []byte("mailinator.com\n"), 0o600))
+
+	first, err := validator.HashFiles(path, "")
+	require.NoError(t, err)
+	again, err := validator.HashFiles(path)
+	require.NoError(t, err)
+	assert.Equal(t, first, again, "the hash depends on the contents only")
+
+	require.NoError(t, os.WriteFile(path, []byte("mailinator.com\nguerrillamail.com\n"), 0o600))
+	edited, err := validator.HashFiles(path)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, edited)
+
+	other := filepath.Join(dir, "other.txt")
+	missing, err := validator.HashFiles(other)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(other, nil, 0o600))
+	empty, err := validator.HashFiles(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, missing, empty)
+}
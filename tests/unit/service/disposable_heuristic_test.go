@@ -0,0 +1,70 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisposableHeuristicTiers(t *testing.T) {
+	tests := []struct {
+		name           string
+		email          string
+		wantDisposable bool
+		wantHeuristic  *model.DisposableHeuristic
+	}{
+		{
+			name:           "Listed domains are decided by the list alone",
+			email:          "user@mailinator.com",
+			wantDisposable: true,
+		},
+		{
+			name:           "Unlisted domains scoring above the threshold are disposable",
+			email:          "user@newtempmail.io",
+			wantDisposable: true,
+			wantHeuristic:  &model.DisposableHeuristic{Score: 0.95, Rules: []string{"disposable_keyword", "throwaway_word", "mailbox_word"}},
+		},
+		{
+			name:          "Weaker evidence is reported without changing the verdict",
+			email:         "user@junkbox.org",
+			wantHeuristic: &model.DisposableHeuristic{Score: 0.52, Rules: []string{"throwaway_word", "mailbox_word"}},
+		},
+		{
+			name:  "Domains matching no rule carry no heuristic",
+			email: "user@example.com",
+		},
+	}
+
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+	emailService.SetDisposableHeuristicThreshold(0.8)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := emailService.ValidateEmail(tt.email)
+			assert.Equal(t, tt.wantDisposable, result.Validations.IsDisposable)
+			assert.Equal(t, tt.wantHeuristic, result.DisposableHeuristic)
+
+			batch := emailService.ValidateEmails([]string{tt.email})
+			assert.Equal(t, tt.wantDisposable, batch.Results[0].Validations.IsDisposable)
+			assert.Equal(t, tt.wantHeuristic, batch.Results[0].DisposableHeuristic)
+		})
+	}
+}
+
+func TestDisposableHeuristicDisabledByDefault(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := emailService.ValidateEmail("user@newtempmail.io")
+	assert.False(t, result.Validations.IsDisposable)
+	assert.Nil(t, result.DisposableHeuristic)
+	assert.Equal(t, model.ValidationStatusValid, result.Status)
+}
@@ -0,0 +1,52 @@
+package servicetest
+
+import (
+	"net"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// disposableMXResolver routes every domain's mail through Mailinator's mail servers
+type disposableMXResolver struct{}
+
+func (r *disposableMXResolver) LookupHost(domain string) ([]string, error) {
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *disposableMXResolver) LookupMX(domain string) ([]*net.MX, error) {
+	return []*net.MX{{Host: "mail.mailinator.com.", Pref: 10}}, nil
+}
+
+func TestDisposableMXProvider(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&disposableMXResolver{})
+	require.NoError(t, err)
+	emailValidator.SetDisposableMXDetector(validator.NewDisposableMXDetector())
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := svc.ValidateEmail("user@vanity-domain.com")
+	assert.True(t, result.Validations.IsDisposable, "a vanity domain on no list is flagged by its mail servers")
+	assert.Equal(t, model.ReasonDisposable, result.ReasonCode)
+
+	batch := svc.ValidateEmails([]string{"user@vanity-domain.com"})
+	require.Len(t, batch.Results, 1)
+	assert.True(t, batch.Results[0].Validations.IsDisposable)
+
+	disposable := svc.CheckDisposableBatch([]string{"vanity-domain.com"}, nil)
+	require.Len(t, disposable.Results, 1)
+	assert.True(t, disposable.Results[0].IsDisposable)
+}
+
+func TestDisposableMXProviderDisabled(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&disposableMXResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := svc.ValidateEmail("user@vanity-domain.com")
+	assert.False(t, result.Validations.IsDisposable)
+}
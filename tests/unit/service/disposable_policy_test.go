@@ -0,0 +1,169 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDisposablePolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    service.DisposablePolicy
+		wantErr bool
+	}{
+		{"", service.DisposablePolicyReject, false},
+		{"reject", service.DisposablePolicyReject, false},
+		{"flag", service.DisposablePolicyFlag, false},
+		{"score", service.DisposablePolicyScore, false},
+		{"ignore", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := service.ParseDisposablePolicy(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDisposablePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     service.DisposablePolicy
+		email      string
+		wantStatus model.ValidationStatus
+		wantScore  int
+	}{
+		{
+			name:       "Reject forces the disposable status",
+			policy:     service.DisposablePolicyReject,
+			email:      "user@mailinator.com",
+			wantStatus: model.ValidationStatusDisposable,
+			wantScore:  90,
+		},
+		{
+			name:       "Flag keeps the address valid with full score",
+			policy:     service.DisposablePolicyFlag,
+			email:      "user@mailinator.com",
+			wantStatus: model.ValidationStatusValid,
+			wantScore:  100,
+		},
+		{
+			name:       "Score only withholds the disposable weight",
+			policy:     service.DisposablePolicyScore,
+			email:      "user@mailinator.com",
+			wantStatus: model.ValidationStatusValid,
+			wantScore:  90,
+		},
+		{
+			name:       "Score only lets the penalty combine with others",
+			policy:     service.DisposablePolicyScore,
+			email:      "admin@mailinator.com",
+			wantStatus: model.ValidationStatusProbablyValid,
+			wantScore:  80,
+		},
+	}
+
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailService := service.NewEmailServiceWithDeps(emailValidator)
+			emailService.SetDisposablePolicy(tt.policy)
+
+			result := emailService.ValidateEmail(tt.email)
+			assert.True(t, result.Validations.IsDisposable)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantScore, result.Score)
+
+			batch := emailService.ValidateEmails([]string{tt.email})
+			assert.Equal(t, tt.wantStatus, batch.Results[0].Status)
+			assert.Equal(t, tt.wantScore, batch.Results[0].Score)
+		})
+	}
+}
+
+func TestDisposablePolicy_PerRequestOverride(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+	emailService.SetDisposablePolicy(service.DisposablePolicyReject)
+
+	opts := service.ValidationOptions{DisposablePolicy: service.DisposablePolicyFlag}
+
+	result := emailService.ValidateEmailWithOptions("user@mailinator.com", opts)
+	assert.Equal(t, model.ValidationStatusValid, result.Status)
+
+	batch := emailService.ValidateEmailsWithOptions([]string{"user@mailinator.com"}, opts)
+	assert.Equal(t, model.ValidationStatusValid, batch.Results[0].Status)
+
+	// The service default still applies to requests without an override
+	result = emailService.ValidateEmail("user@mailinator.com")
+	assert.Equal(t, model.ValidationStatusDisposable, result.Status)
+}
+
+func TestEmailService_MarkDisposable(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := emailService.ValidateEmail("user@example.com")
+	assert.Equal(t, model.ValidationStatusValid, result.Status)
+
+	emailService.MarkDisposable(&result, service.ValidationOptions{})
+	assert.True(t, result.Validations.IsDisposable)
+	assert.Equal(t, model.ValidationStatusDisposable, result.Status)
+	assert.Equal(t, 90, result.Score)
+}
+
+func TestEmailService_MarkDisposableUnknown(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+
+	tests := []struct {
+		name      string
+		policy    service.UnknownPolicy
+		opts      service.ValidationOptions
+		wantScore int
+	}{
+		{"Strict penalizes the score", service.UnknownPolicyStrict, service.ValidationOptions{}, 90},
+		{"Lenient leaves the score", service.UnknownPolicyLenient, service.ValidationOptions{}, 100},
+		{"Per-request policy wins", service.UnknownPolicyStrict, service.ValidationOptions{UnknownPolicy: service.UnknownPolicyLenient}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailService.SetUnknownPolicy(tt.policy)
+			result := emailService.ValidateEmailWithOptions("user@example.com", tt.opts)
+			assert.Equal(t, model.ValidationStatusValid, result.Status)
+
+			emailService.MarkDisposableUnknown(&result, tt.opts)
+			emailService.MarkDisposableUnknown(&result, tt.opts)
+			assert.Equal(t, []string{service.CheckIsDisposable}, result.Inconclusive)
+			assert.False(t, result.Validations.IsDisposable, "an unknown check never flags the address")
+			assert.Equal(t, model.ValidationStatusProbablyValid, result.Status)
+			assert.Equal(t, model.ReasonDisposableUnknown, result.ReasonCode)
+			assert.Equal(t, tt.wantScore, result.Score)
+			assert.Equal(t, service.Fingerprint(result), result.Fingerprint)
+		})
+	}
+}
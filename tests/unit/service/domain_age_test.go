@@ -0,0 +1,75 @@
+package servicetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubDomainAgeChecker reports fixed ages per domain and fails for the rest
+type stubDomainAgeChecker struct {
+	days map[string]int
+}
+
+func (c *stubDomainAgeChecker) Check(domain string) (validator.DomainAge, error) {
+	days, ok := c.days[domain]
+	if !ok {
+		return validator.DomainAge{}, errors.New("rdap lookup failed")
+	}
+	return validator.DomainAge{
+		Registered: time.Now().Add(-time.Duration(days) * 24 * time.Hour),
+		Days:       days,
+		IsNew:      days < 30,
+	}, nil
+}
+
+func TestDomainAgeInResponse(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	// Disabled by default
+	result := svc.ValidateEmail("user@example.com")
+	assert.Nil(t, result.DomainAgeDays)
+	assert.False(t, result.IsNewDomain)
+
+	svc.SetDomainAgeChecker(&stubDomainAgeChecker{days: map[string]int{
+		"example.com": 4000,
+		"fresh.com":   2,
+	}})
+
+	tests := []struct {
+		email    string
+		wantDays *int
+		wantNew  bool
+	}{
+		{"user@example.com", intPtr(4000), false},
+		{"user@fresh.com", intPtr(2), true},
+		{"user@unknown-age.com", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := svc.ValidateEmail(tt.email)
+			assert.Equal(t, tt.wantDays, result.DomainAgeDays)
+			assert.Equal(t, tt.wantNew, result.IsNewDomain)
+
+			batch := svc.ValidateEmails([]string{tt.email})
+			if assert.Len(t, batch.Results, 1) {
+				assert.Equal(t, tt.wantDays, batch.Results[0].DomainAgeDays)
+				assert.Equal(t, tt.wantNew, batch.Results[0].IsNewDomain)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
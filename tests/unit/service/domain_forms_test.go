@@ -0,0 +1,59 @@
+package servicetest
+
+import (
+	"context"
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainForms(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	tests := []struct {
+		name           string
+		email          string
+		wantOriginal   string
+		wantNormalized string
+	}{
+		{name: "Mixed case", email: "User@Example.COM", wantOriginal: "Example.COM", wantNormalized: "example.com"},
+		{name: "Mixed-case IDN", email: "user@BÜCHER.de", wantOriginal: "BÜCHER.de", wantNormalized: "xn--bcher-kva.de"},
+		{name: "Fully-qualified IDN", email: "user@Bücher.De.", wantOriginal: "Bücher.De.", wantNormalized: "xn--bcher-kva.de"},
+		{name: "Invalid syntax keeps the domain", email: "a..b@Example.com", wantOriginal: "Example.com", wantNormalized: "example.com"},
+		{name: "No domain", email: "not-an-address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := svc.ValidateEmail(tt.email)
+			assert.Equal(t, tt.wantOriginal, result.DomainOriginal)
+			assert.Equal(t, tt.wantNormalized, result.DomainNormalized)
+
+			batch := svc.ValidateEmails([]string{tt.email})
+			require.Len(t, batch.Results, 1)
+			assert.Equal(t, tt.wantOriginal, batch.Results[0].DomainOriginal)
+			assert.Equal(t, tt.wantNormalized, batch.Results[0].DomainNormalized)
+
+			quick := svc.ValidateEmailLocalOnly(tt.email, service.ValidationOptions{})
+			assert.Equal(t, tt.wantOriginal, quick.DomainOriginal)
+			assert.Equal(t, tt.wantNormalized, quick.DomainNormalized)
+		})
+	}
+}
+
+func TestDomainFormsValidateDomain(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result, err := svc.ValidateDomain(context.Background(), "BÜCHER.de", service.ValidationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "BÜCHER.de", result.DomainOriginal)
+	assert.Equal(t, "xn--bcher-kva.de", result.DomainNormalized)
+	assert.Equal(t, "bücher.de", result.Domain)
+}
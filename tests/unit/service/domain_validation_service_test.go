@@ -3,13 +3,16 @@ package servicetest
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
 	"emailvalidator/tests/unit/service/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConcurrentDomainValidationService_ValidateDomainConcurrently(t *testing.T) {
@@ -102,3 +105,47 @@ func TestConcurrentDomainValidationService_ValidateDomainConcurrently(t *testing
 		})
 	}
 }
+
+func TestConcurrentDomainValidationService_ParallelChecks(t *testing.T) {
+	for _, parallel := range []bool{true, false} {
+		t.Run(fmt.Sprintf("parallel=%t", parallel), func(t *testing.T) {
+			resolver := &slowResolver{delay: 20 * time.Millisecond}
+			emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+			require.NoError(t, err)
+			svc := service.NewConcurrentDomainValidationService(emailValidator)
+			svc.SetParallelChecks(parallel)
+
+			start := time.Now()
+			result := svc.ValidateDomainWithStatus(context.Background(), "example.com")
+			elapsed := time.Since(start)
+
+			assert.True(t, result.DomainExists)
+			assert.True(t, result.MXRecords)
+			assert.False(t, result.IsDisposable)
+			assert.Empty(t, result.Inconclusive)
+			if parallel {
+				assert.Equal(t, 2, resolver.inFlight.Peak(), "the host and MX lookups overlap")
+			} else {
+				assert.Equal(t, 1, resolver.inFlight.Peak(), "one lookup at a time")
+				assert.GreaterOrEqual(t, elapsed, 2*resolver.delay)
+			}
+		})
+	}
+}
+
+func BenchmarkValidateDomainWithStatus(b *testing.B) {
+	for _, parallel := range []bool{true, false} {
+		b.Run(fmt.Sprintf("parallel=%t", parallel), func(b *testing.B) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(&slowResolver{delay: time.Millisecond})
+			require.NoError(b, err)
+			svc := service.NewConcurrentDomainValidationService(emailValidator)
+			svc.SetParallelChecks(parallel)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// A new domain each time, so the domain cache never answers
+				svc.ValidateDomainWithStatus(context.Background(), fmt.Sprintf("bench%d.example.com", i))
+			}
+		})
+	}
+}
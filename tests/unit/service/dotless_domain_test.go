@@ -0,0 +1,52 @@
+package servicetest
+
+import (
+	"context"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotlessDomains(t *testing.T) {
+	tests := []struct {
+		name          string
+		allow         bool
+		wantStatus    model.ValidationStatus
+		wantDomainErr bool
+	}{
+		{name: "Disabled", allow: false, wantStatus: model.ValidationStatusUnknownTLD, wantDomainErr: true},
+		{name: "Enabled", allow: true, wantStatus: model.ValidationStatusValid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+			require.NoError(t, err)
+			emailValidator.SetAllowDotlessDomains(tt.allow)
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+
+			result := svc.ValidateEmail("user@intranet")
+			assert.True(t, result.Validations.Syntax)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, !tt.allow, result.Validations.UnknownTLD)
+			assert.Equal(t, tt.allow, result.Validations.MXRecords)
+
+			batch := svc.ValidateEmails([]string{"user@intranet"})
+			require.Len(t, batch.Results, 1)
+			assert.Equal(t, tt.wantStatus, batch.Results[0].Status)
+
+			domain, err := svc.ValidateDomain(context.Background(), "intranet", service.ValidationOptions{})
+			if tt.wantDomainErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, domain.Validations.DomainExists)
+			assert.False(t, domain.Validations.UnknownTLD)
+		})
+	}
+}
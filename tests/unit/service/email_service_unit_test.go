@@ -375,6 +375,7 @@ func TestServiceEmailValidationErrorScenarios(t *testing.T) {
 		email       string
 		wantStatus  model.ValidationStatus
 		wantScore   int
+		wantReason  string
 		setupFunc   func()
 		cleanupFunc func()
 	}{
@@ -389,6 +390,14 @@ func TestServiceEmailValidationErrorScenarios(t *testing.T) {
 			email:      fmt.Sprintf("%s@example.com", strings.Repeat("a", 256)),
 			wantStatus: model.ValidationStatusInvalidFormat,
 			wantScore:  0,
+			wantReason: validator.ViolationAddressTooLong.Description(),
+		},
+		{
+			name:       "Local part too long",
+			email:      fmt.Sprintf("%s@example.com", strings.Repeat("a", 65)),
+			wantStatus: model.ValidationStatusInvalidFormat,
+			wantScore:  0,
+			wantReason: validator.ViolationLocalPartTooLong.Description(),
 		},
 		{
 			name:       "Domain with invalid characters",
@@ -412,6 +421,9 @@ func TestServiceEmailValidationErrorScenarios(t *testing.T) {
 			if result.Score != tt.wantScore {
 				t.Errorf("Score = %v, want %v", result.Score, tt.wantScore)
 			}
+			if tt.wantReason != "" && result.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", result.Reason, tt.wantReason)
+			}
 
 			if tt.cleanupFunc != nil {
 				tt.cleanupFunc()
@@ -0,0 +1,49 @@
+package servicetest
+
+import (
+	"sync"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/events"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingEventSink collects emitted events
+type recordingEventSink struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (s *recordingEventSink) Emit(event events.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func TestEmailService_EmitsValidationEvents(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+	sink := &recordingEventSink{}
+	emailService.SetEventSink(sink)
+
+	single := emailService.ValidateEmail("User@Example.com")
+	batch := emailService.ValidateEmails([]string{"user@example.com", "invalid-email"})
+
+	if assert.Len(t, sink.events, 3) {
+		assert.Equal(t, events.HashEmail("user@example.com"), sink.events[0].EmailHash)
+		assert.Equal(t, "example.com", sink.events[0].Domain)
+		assert.Equal(t, string(single.Status), sink.events[0].Status)
+		assert.Equal(t, single.Score, sink.events[0].Score)
+
+		assert.Equal(t, string(batch.Results[0].Status), sink.events[1].Status)
+		assert.Equal(t, string(model.ValidationStatusInvalidFormat), sink.events[2].Status)
+		assert.Empty(t, sink.events[2].Domain)
+	}
+}
@@ -0,0 +1,119 @@
+package servicetest
+
+import (
+	"net"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// txtResolver answers host, MX and TXT lookups from fixed records
+type txtResolver struct {
+	txt  map[string][]string
+	noMX bool
+}
+
+func (r *txtResolver) LookupHost(domain string) ([]string, error) {
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *txtResolver) LookupMX(domain string) ([]*net.MX, error) {
+	if r.noMX {
+		return nil, nil
+	}
+	return []*net.MX{{Host: "mx2." + domain + ".", Pref: 20}, {Host: "mx1." + domain + ".", Pref: 10}}, nil
+}
+
+func (r *txtResolver) LookupTXT(domain string) ([]string, error) {
+	return r.txt[domain], nil
+}
+
+func newExplainService(t *testing.T) *service.EmailService {
+	t.Helper()
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&txtResolver{txt: map[string][]string{
+		"example.com":        {"google-site-verification=abc", "v=spf1 include:_spf.example.com -all"},
+		"_dmarc.example.com": {"v=DMARC1; p=reject"},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	return service.NewEmailServiceWithValidator(emailValidator)
+}
+
+func TestExplainEmail(t *testing.T) {
+	svc := newExplainService(t)
+	svc.SetMailboxVerifier(&stubMailboxVerifier{result: validator.SMTPResult{
+		Status:   validator.SMTPStatusDeliverable,
+		Host:     "mx1.example.com",
+		CatchAll: true,
+		Code:     250,
+	}})
+
+	result := svc.ExplainEmail("user@example.com", service.ValidationOptions{})
+	assert.Equal(t, model.ValidationStatusProbablyValid, result.Status)
+	assert.Equal(t, 80, result.Score)
+
+	if assert.NotNil(t, result.Trace.DNS) {
+		assert.False(t, result.Trace.DNS.CacheHit)
+		assert.Nil(t, result.Trace.DNS.CacheAgeSeconds)
+		assert.Equal(t, []model.MXRecord{{Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}}, result.Trace.DNS.MXRecords)
+		assert.Equal(t, "v=spf1 include:_spf.example.com -all", result.Trace.DNS.SPF)
+		assert.Equal(t, "v=DMARC1; p=reject", result.Trace.DNS.DMARC)
+	}
+	if assert.NotNil(t, result.Trace.SMTP) {
+		assert.Equal(t, "DELIVERABLE", result.Trace.SMTP.Status)
+		assert.Equal(t, "mx1.example.com", result.Trace.SMTP.Host)
+		assert.True(t, result.Trace.SMTP.CatchAll)
+	}
+	if assert.NotNil(t, result.Trace.Score) {
+		assert.Equal(t, 100, result.Trace.Score.Base)
+		assert.Equal(t, []string{"catch-all ceiling: x80% = 80"}, result.Trace.Score.Steps)
+		assert.Equal(t, 80, result.Trace.Score.Final)
+		assert.True(t, result.Trace.Score.Inputs["mx_records"])
+	}
+	for _, check := range []string{"syntax", "dns_records", "domain", "mailbox", "total"} {
+		assert.Contains(t, result.Trace.TimingsMs, check)
+	}
+
+	// The first validation cached the domain
+	again := svc.ExplainEmail("user@example.com", service.ValidationOptions{})
+	if assert.NotNil(t, again.Trace.DNS) {
+		assert.True(t, again.Trace.DNS.CacheHit)
+		if assert.NotNil(t, again.Trace.DNS.CacheAgeSeconds) {
+			assert.Equal(t, 0, *again.Trace.DNS.CacheAgeSeconds)
+		}
+	}
+}
+
+func TestExplainEmailPartialTrace(t *testing.T) {
+	svc := newExplainService(t)
+
+	result := svc.ExplainEmail("not-an-email", service.ValidationOptions{})
+	assert.Equal(t, model.ValidationStatusInvalidFormat, result.Status)
+	assert.Nil(t, result.Trace.DNS)
+	assert.Nil(t, result.Trace.Score)
+	assert.Contains(t, result.Trace.TimingsMs, "syntax")
+
+	result = svc.ExplainEmail("user@example.com", service.ValidationOptions{})
+	assert.Nil(t, result.Trace.SMTP, "no probe is made without a mailbox verifier")
+	assert.Equal(t, 100, result.Trace.Score.Final)
+}
+
+func TestExplainEmailStatusOverride(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&txtResolver{noMX: true})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+
+	result := svc.ExplainEmail("user@example.com", service.ValidationOptions{})
+	assert.Equal(t, model.ValidationStatusNoMXRecords, result.Status)
+	assert.Empty(t, result.Trace.DNS.MXRecords)
+	assert.Equal(t, 60, result.Trace.Score.Base)
+	assert.Equal(t, []string{"status NO_MX_RECORDS overrides score = 40"}, result.Trace.Score.Steps)
+	assert.Equal(t, 40, result.Trace.Score.Final)
+}
@@ -0,0 +1,92 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintIsStable(t *testing.T) {
+	result := model.EmailValidationResponse{
+		Email:       "user@example.com",
+		Status:      model.ValidationStatusValid,
+		Score:       90,
+		Validations: model.ValidationResults{Syntax: true, DomainExists: true, MXRecords: true},
+	}
+
+	// The fields are collected in a map, so repeated calls also cover iteration order
+	want := service.Fingerprint(result)
+	assert.Len(t, want, 16)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, want, service.Fingerprint(result))
+	}
+}
+
+func TestFingerprintIgnoresInsignificantFields(t *testing.T) {
+	result := model.EmailValidationResponse{
+		Email:       "user@example.com",
+		Status:      model.ValidationStatusValid,
+		Score:       90,
+		Validations: model.ValidationResults{Syntax: true, DomainExists: true, MXRecords: true},
+	}
+	index := 3
+	other := result
+	other.Index = &index
+	other.Reason = "some explanation"
+	other.TypoSuggestion = "user@example.org"
+	other.Canonical = "user@example.com"
+
+	assert.Equal(t, service.Fingerprint(result), service.Fingerprint(other))
+}
+
+func TestFingerprintChangesWithSignificantFields(t *testing.T) {
+	base := model.EmailValidationResponse{
+		Status:      model.ValidationStatusValid,
+		Score:       90,
+		Validations: model.ValidationResults{Syntax: true, DomainExists: true, MXRecords: true},
+	}
+
+	tests := []struct {
+		name   string
+		change func(*model.EmailValidationResponse)
+	}{
+		{name: "Status", change: func(r *model.EmailValidationResponse) { r.Status = model.ValidationStatusProbablyValid }},
+		{name: "Score", change: func(r *model.EmailValidationResponse) { r.Score = 80 }},
+		{name: "Reason code", change: func(r *model.EmailValidationResponse) { r.ReasonCode = model.ReasonDomainNotFound }},
+		{name: "Disposable flag", change: func(r *model.EmailValidationResponse) { r.Validations.IsDisposable = true }},
+		{name: "Catch-all flag", change: func(r *model.EmailValidationResponse) { r.Validations.IsCatchAll = true }},
+		{name: "MX flag", change: func(r *model.EmailValidationResponse) { r.Validations.MXRecords = false }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base
+			tt.change(&changed)
+			assert.NotEqual(t, service.Fingerprint(base), service.Fingerprint(changed))
+		})
+	}
+}
+
+func TestFingerprintSetOnResults(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	single := svc.ValidateEmail("user@example.com")
+	assert.Equal(t, service.Fingerprint(single), single.Fingerprint)
+	assert.NotEmpty(t, single.Fingerprint)
+
+	// The same address gets the same fingerprint wherever it is in a batch
+	first := svc.ValidateEmails([]string{"user@example.com", "not-an-email"})
+	second := svc.ValidateEmails([]string{"not-an-email", "user@example.com"})
+	require.Len(t, first.Results, 2)
+	require.Len(t, second.Results, 2)
+	assert.Equal(t, single.Fingerprint, first.Results[0].Fingerprint)
+	assert.Equal(t, single.Fingerprint, second.Results[1].Fingerprint)
+	assert.Equal(t, first.Results[1].Fingerprint, second.Results[0].Fingerprint)
+	assert.NotEqual(t, first.Results[0].Fingerprint, first.Results[1].Fingerprint)
+}
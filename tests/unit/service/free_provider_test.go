@@ -0,0 +1,28 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeProviderDetection(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := emailService.ValidateEmail("jane@gmail.com")
+	assert.True(t, result.Validations.IsFreeProvider)
+
+	result = emailService.ValidateEmail("jane@example.com")
+	assert.False(t, result.Validations.IsFreeProvider)
+
+	batch := emailService.ValidateEmails([]string{"jane@gmail.com", "jane@example.com"})
+	require.Len(t, batch.Results, 2)
+	assert.True(t, batch.Results[0].Validations.IsFreeProvider)
+	assert.False(t, batch.Results[1].Validations.IsFreeProvider)
+}
@@ -0,0 +1,170 @@
+package servicetest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+	"emailvalidator/tests/unit/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGreylistingSMTPServer starts an SMTP server that defers every recipient with a 450
+// reply and returns its port
+func newGreylistingSMTPServer(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				_, _ = conn.Write([]byte("220 greylist.test ESMTP\r\n"))
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(strings.TrimSpace(line))
+					reply := "250 OK"
+					switch {
+					case command == "QUIT":
+						_, _ = conn.Write([]byte("221 bye\r\n"))
+						return
+					case strings.HasPrefix(command, "RCPT TO:"):
+						reply = "450 4.2.0 Greylisted, please try again later"
+					}
+					_, _ = conn.Write([]byte(reply + "\r\n"))
+				}
+			}()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestParseGreylistPolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    service.GreylistPolicy
+		wantErr bool
+	}{
+		{"", service.GreylistPolicyUncertain, false},
+		{"uncertain", service.GreylistPolicyUncertain, false},
+		{"valid", service.GreylistPolicyValid, false},
+		{"invalid", service.GreylistPolicyInvalid, false},
+		{"retry", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := service.ParseGreylistPolicy(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGreylistPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		email            string
+		policy           service.GreylistPolicy
+		wantStatus       model.ValidationStatus
+		wantScore        int
+		wantMailbox      bool
+		wantReason       model.ReasonCode
+		wantInconclusive []string
+	}{
+		{
+			name:             "Uncertain by default",
+			email:            "user@example.com",
+			wantStatus:       model.ValidationStatusProbablyValid,
+			wantScore:        72,
+			wantReason:       model.ReasonGreylisted,
+			wantInconclusive: []string{service.CheckMailboxExists},
+		},
+		{
+			name:             "Uncertain",
+			email:            "user@example.com",
+			policy:           service.GreylistPolicyUncertain,
+			wantStatus:       model.ValidationStatusProbablyValid,
+			wantScore:        72,
+			wantReason:       model.ReasonGreylisted,
+			wantInconclusive: []string{service.CheckMailboxExists},
+		},
+		{
+			name:        "Valid accepts the mailbox",
+			email:       "user@example.com",
+			policy:      service.GreylistPolicyValid,
+			wantStatus:  model.ValidationStatusValid,
+			wantScore:   100,
+			wantMailbox: true,
+		},
+		{
+			name:        "Valid confirms a role account",
+			email:       "admin@example.com",
+			policy:      service.GreylistPolicyValid,
+			wantStatus:  model.ValidationStatusValid,
+			wantScore:   90,
+			wantMailbox: true,
+		},
+		{
+			name:       "Invalid rejects the mailbox",
+			email:      "user@example.com",
+			policy:     service.GreylistPolicyInvalid,
+			wantStatus: model.ValidationStatusInvalid,
+			wantScore:  80,
+			wantReason: model.ReasonGreylisted,
+		},
+	}
+
+	port := newGreylistingSMTPServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+			require.NoError(t, err)
+			smtp := validator.NewSMTPValidator(&testutil.LoopbackMXResolver{}, "verifier.test", "bounce@verifier.test")
+			smtp.SetPort(port)
+			smtp.SetTimeout(2 * time.Second)
+
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			svc.SetMailboxVerifier(smtp)
+			if tt.policy != "" {
+				svc.SetGreylistPolicy(tt.policy)
+			}
+
+			result := svc.ValidateEmail(tt.email)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantScore, result.Score)
+			assert.Equal(t, tt.wantReason, result.ReasonCode)
+			assert.True(t, result.Validations.IsGreylisted)
+			assert.Equal(t, tt.wantMailbox, result.Validations.MailboxExists)
+			assert.Equal(t, tt.wantInconclusive, result.Inconclusive)
+
+			batch := svc.ValidateEmails([]string{tt.email})
+			if assert.Len(t, batch.Results, 1) {
+				assert.Equal(t, tt.wantStatus, batch.Results[0].Status)
+				assert.Equal(t, tt.wantScore, batch.Results[0].Score)
+				assert.Equal(t, tt.wantReason, batch.Results[0].ReasonCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newListQualityService(t *testing.T) *service.EmailService {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	return service.NewEmailServiceWithDeps(emailValidator)
+}
+
+func TestBatchSummaryRoleCollisions(t *testing.T) {
+	svc := newListQualityService(t)
+
+	batch := svc.ValidateEmails([]string{
+		"sales@acme.com",
+		"info@acme.com",
+		"INFO@Acme.com", // same account as info@acme.com
+		"john@acme.com", // not a role account
+		"admin@other.com",
+		"not-an-email",
+	})
+
+	require.NotNil(t, batch.Summary)
+	assert.Equal(t, []model.RoleCollision{
+		{Domain: "acme.com", Accounts: []string{"info", "sales"}},
+	}, batch.Summary.RoleCollisions)
+}
+
+func TestBatchSummaryNoCollisions(t *testing.T) {
+	svc := newListQualityService(t)
+
+	batch := svc.ValidateEmails([]string{"info@acme.com", "info@other.com", "john@acme.com"})
+
+	require.NotNil(t, batch.Summary)
+	assert.Empty(t, batch.Summary.RoleCollisions)
+	assert.NotNil(t, batch.Summary.RoleCollisions, "an empty list, not null, in JSON")
+	assert.False(t, batch.Summary.HighDisposableShare)
+}
+
+func TestBatchSummaryDisposableShare(t *testing.T) {
+	tests := []struct {
+		name      string
+		emails    []string
+		wantCount int
+		wantShare float64
+		wantHigh  bool
+	}{
+		{
+			name:      "Concentrated",
+			emails:    []string{"a@mailinator.com", "b@mailinator.com", "c@mailinator.com", "d@example.com", "e@example.com", "not-an-email"},
+			wantCount: 3,
+			wantShare: 0.6,
+			wantHigh:  true,
+		},
+		{
+			name:      "Too few to flag",
+			emails:    []string{"a@mailinator.com", "d@example.com"},
+			wantCount: 1,
+			wantShare: 0.5,
+			wantHigh:  false,
+		},
+		{
+			name:      "At the threshold",
+			emails:    []string{"a@mailinator.com", "b@mailinator.com", "c@mailinator.com", "1@example.com", "2@example.com", "3@example.com", "4@example.com", "5@example.com", "6@example.com", "7@example.com", "8@example.com", "9@example.com", "10@example.com", "11@example.com", "12@example.com"},
+			wantCount: 3,
+			wantShare: 0.2,
+			wantHigh:  true,
+		},
+		{
+			name:      "Diluted",
+			emails:    []string{"a@mailinator.com", "b@mailinator.com", "c@mailinator.com", "1@example.com", "2@example.com", "3@example.com", "4@example.com", "5@example.com", "6@example.com", "7@example.com", "8@example.com", "9@example.com", "10@example.com", "11@example.com", "12@example.com", "13@example.com"},
+			wantCount: 3,
+			wantShare: 0.1875,
+			wantHigh:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newListQualityService(t)
+
+			batch := svc.ValidateEmails(tt.emails)
+
+			require.NotNil(t, batch.Summary)
+			assert.Equal(t, tt.wantCount, batch.Summary.DisposableCount)
+			assert.InDelta(t, tt.wantShare, batch.Summary.DisposableShare, 1e-9)
+			assert.Equal(t, tt.wantHigh, batch.Summary.HighDisposableShare)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package servicetest
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parentMXResolver publishes mail servers for example.com only. sales.example.com exists
+// without mail servers of its own; other subdomains do not exist.
+type parentMXResolver struct{}
+
+func (r *parentMXResolver) LookupHost(domain string) ([]string, error) {
+	switch domain {
+	case "example.com":
+		return []string{"192.0.2.1"}, nil
+	case "sales.example.com":
+		return []string{"192.0.2.2"}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+func (r *parentMXResolver) LookupMX(domain string) ([]*net.MX, error) {
+	if domain == "example.com" {
+		return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+func TestMXWalkUp(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&parentMXResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := svc.ValidateEmail("user@sales.example.com")
+	assert.False(t, result.Validations.MXRecords)
+	assert.Empty(t, result.MXDomain)
+
+	emailValidator.SetMXWalkUp(1)
+	result = svc.ValidateEmail("user@sales.example.com")
+	assert.True(t, result.Validations.DomainExists)
+	assert.True(t, result.Validations.MXRecords)
+	assert.Equal(t, "example.com", result.MXDomain)
+	assert.NotEqual(t, model.ValidationStatusInvalidDomain, result.Status)
+
+	domain, err := svc.ValidateDomain(context.Background(), "sales.example.com", service.ValidationOptions{})
+	require.NoError(t, err)
+	assert.True(t, domain.Validations.MXRecords)
+	assert.Equal(t, "example.com", domain.MXDomain)
+
+	result = svc.ValidateEmail("user@random.example.com")
+	assert.Equal(t, model.ValidationStatusInvalidDomain, result.Status, "a made-up subdomain does not inherit its parent's mail servers")
+	assert.False(t, result.Validations.DomainExists)
+	assert.Empty(t, result.MXDomain)
+}
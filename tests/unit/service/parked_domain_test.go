@@ -0,0 +1,88 @@
+package servicetest
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parkedResolver delegates every domain to a parking service, optionally without MX records
+type parkedResolver struct {
+	noMX bool
+}
+
+func (r *parkedResolver) LookupHost(domain string) ([]string, error) {
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *parkedResolver) LookupMX(domain string) ([]*net.MX, error) {
+	if r.noMX {
+		return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+	}
+	return []*net.MX{{Host: "mail." + domain, Pref: 10}}, nil
+}
+
+func (r *parkedResolver) LookupNS(domain string) ([]*net.NS, error) {
+	return []*net.NS{{Host: "ns1.sedoparking.com."}, {Host: "ns2.sedoparking.com."}}, nil
+}
+
+func TestParkedDomain(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolver   *parkedResolver
+		wantStatus model.ValidationStatus
+		wantScore  int
+	}{
+		{name: "With mail servers", resolver: &parkedResolver{}, wantStatus: model.ValidationStatusProbablyValid, wantScore: 70},
+		{name: "Without mail servers", resolver: &parkedResolver{noMX: true}, wantStatus: model.ValidationStatusNoMXRecords, wantScore: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(tt.resolver)
+			require.NoError(t, err)
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+
+			result := svc.ValidateEmail("user@expired.com")
+			assert.True(t, result.Validations.IsParked)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantScore, result.Score)
+			assert.Equal(t, model.ReasonParkedDomain, result.ReasonCode)
+
+			batch := svc.ValidateEmails([]string{"user@expired.com"})
+			require.Len(t, batch.Results, 1)
+			assert.True(t, batch.Results[0].Validations.IsParked)
+			assert.Equal(t, tt.wantStatus, batch.Results[0].Status)
+			assert.Equal(t, tt.wantScore, batch.Results[0].Score)
+		})
+	}
+}
+
+func TestParkedDomainValidateDomain(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&parkedResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result, err := svc.ValidateDomain(context.Background(), "expired.com", service.ValidationOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.Validations.IsParked)
+	assert.Equal(t, model.ValidationStatusProbablyValid, result.Status)
+	assert.Equal(t, model.ReasonParkedDomain, result.ReasonCode)
+}
+
+func TestParkedDomainDetectionDisabled(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&parkedResolver{})
+	require.NoError(t, err)
+	emailValidator.SetParkedDomainDetector(nil)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := svc.ValidateEmail("user@expired.com")
+	assert.False(t, result.Validations.IsParked)
+	assert.Equal(t, model.ValidationStatusValid, result.Status)
+}
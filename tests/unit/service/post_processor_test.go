@@ -0,0 +1,165 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/cache"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPostProcessorTestService(t *testing.T) *service.EmailService {
+	t.Helper()
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	return service.NewEmailServiceWithDeps(emailValidator)
+}
+
+func TestResultPostProcessorsRunInOrder(t *testing.T) {
+	svc := newPostProcessorTestService(t)
+	var seen []int
+	svc.SetResultPostProcessors(
+		service.ResultPostProcessorFunc(func(response *model.EmailValidationResponse) {
+			seen = append(seen, response.Score)
+			response.Score = 50
+		}),
+		service.ResultPostProcessorFunc(func(response *model.EmailValidationResponse) {
+			seen = append(seen, response.Score)
+			response.Status = model.ValidationStatusInvalid
+		}),
+	)
+
+	response := svc.ValidateEmail("user@example.com")
+	require.Len(t, seen, 2)
+	assert.Equal(t, 50, seen[1], "each processor sees the changes of the ones before it")
+	assert.Equal(t, 50, response.Score)
+	assert.Equal(t, model.ValidationStatusInvalid, response.Status)
+	assert.Equal(t, service.Fingerprint(response), response.Fingerprint, "the fingerprint is taken after processing")
+}
+
+func TestResultPostProcessorsApplyToBatchAndExplain(t *testing.T) {
+	svc := newPostProcessorTestService(t)
+	svc.SetResultPostProcessors(service.NewDomainRules([]string{"competitor.test"}, nil))
+
+	batch := svc.ValidateEmails([]string{"user@example.com", "user@mail.competitor.test"})
+	require.Len(t, batch.Results, 2)
+	assert.NotEqual(t, model.ValidationStatusInvalid, batch.Results[0].Status)
+	assert.Equal(t, model.ValidationStatusInvalid, batch.Results[1].Status)
+	assert.Equal(t, model.ReasonBlockedDomain, batch.Results[1].ReasonCode)
+	assert.Equal(t, service.Fingerprint(batch.Results[1]), batch.Results[1].Fingerprint)
+
+	explained := svc.ExplainEmail("user@competitor.test", service.ValidationOptions{})
+	assert.Equal(t, model.ValidationStatusInvalid, explained.Status)
+	assert.Equal(t, 0, explained.Score)
+	assert.Equal(t, 0, explained.Trace.Score.Final, "the trace ends with the processed score")
+}
+
+func TestResultPostProcessorsRunOnCachedResults(t *testing.T) {
+	svc := newPostProcessorTestService(t)
+	svc.SetResultCache(service.NewResultCache(cache.NewMockCache(), service.DefaultResultCacheTTL))
+	svc.SetResultPostProcessors(service.NewDomainRules([]string{"example.com"}, nil))
+
+	assert.Equal(t, model.ReasonBlockedDomain, svc.ValidateEmail("user@example.com").ReasonCode)
+	assert.Equal(t, model.ReasonBlockedDomain, svc.ValidateEmail("user@example.com").ReasonCode, "cached results are processed too")
+
+	// The cache holds the unprocessed result, so removing a rule takes effect at once
+	svc.SetResultPostProcessors()
+	assert.NotEqual(t, model.ReasonBlockedDomain, svc.ValidateEmail("user@example.com").ReasonCode)
+}
+
+func TestResultPostProcessorsRunAfterMarkDisposable(t *testing.T) {
+	svc := newPostProcessorTestService(t)
+	svc.SetResultPostProcessors(service.NewDomainRules([]string{"competitor.test"}, map[string]int{"example.com": 5}))
+	opts := service.ValidationOptions{DisposablePolicy: service.DisposablePolicyScore}
+
+	boosted := svc.ValidateEmailWithOptions("user@example.com", opts)
+	svc.MarkDisposable(&boosted, opts)
+	assert.Equal(t, 95, boosted.Score, "the boost is applied to the rescored result")
+
+	blocked := svc.ValidateEmailWithOptions("user@competitor.test", opts)
+	svc.MarkDisposable(&blocked, opts)
+	assert.Equal(t, model.ValidationStatusInvalid, blocked.Status)
+	assert.Equal(t, model.ReasonBlockedDomain, blocked.ReasonCode)
+	assert.Equal(t, service.Fingerprint(blocked), blocked.Fingerprint)
+}
+
+func TestDomainRulesBoost(t *testing.T) {
+	rules := service.NewDomainRules(nil, map[string]int{"partner.test": 15, "eu.partner.test": -30})
+
+	tests := []struct {
+		name       string
+		response   model.EmailValidationResponse
+		wantScore  int
+		wantStatus model.ValidationStatus
+	}{
+		{
+			name:       "probably valid promoted to valid",
+			response:   boostTestResponse("user@partner.test", model.ValidationStatusProbablyValid, 80),
+			wantScore:  95,
+			wantStatus: model.ValidationStatusValid,
+		},
+		{
+			name:       "score capped at 100",
+			response:   boostTestResponse("user@mail.partner.test", model.ValidationStatusValid, 95),
+			wantScore:  100,
+			wantStatus: model.ValidationStatusValid,
+		},
+		{
+			name:       "most specific domain wins",
+			response:   boostTestResponse("user@eu.partner.test", model.ValidationStatusValid, 100),
+			wantScore:  70,
+			wantStatus: model.ValidationStatusValid,
+		},
+		{
+			name:       "rejected results are not boosted",
+			response:   boostTestResponse("user@partner.test", model.ValidationStatusInvalid, 40),
+			wantScore:  40,
+			wantStatus: model.ValidationStatusInvalid,
+		},
+		{
+			name:       "other domains are untouched",
+			response:   boostTestResponse("user@example.com", model.ValidationStatusProbablyValid, 80),
+			wantScore:  80,
+			wantStatus: model.ValidationStatusProbablyValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := tt.response
+			rules.Process(&response)
+			assert.Equal(t, tt.wantScore, response.Score)
+			assert.Equal(t, tt.wantStatus, response.Status)
+		})
+	}
+
+	inconclusive := boostTestResponse("user@partner.test", model.ValidationStatusProbablyValid, 80)
+	inconclusive.Inconclusive = []string{service.CheckMailboxExists}
+	rules.Process(&inconclusive)
+	assert.Equal(t, model.ValidationStatusProbablyValid, inconclusive.Status, "an inconclusive result is not promoted")
+}
+
+func boostTestResponse(email string, status model.ValidationStatus, score int) model.EmailValidationResponse {
+	return model.EmailValidationResponse{
+		Email:            email,
+		DomainNormalized: email[len("user@"):],
+		Status:           status,
+		Score:            score,
+		Validations:      model.ValidationResults{Syntax: true},
+	}
+}
+
+func TestParseDomainScoreBoosts(t *testing.T) {
+	boosts, err := service.ParseDomainScoreBoosts("Partner.test=10, reseller.test=-5,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"partner.test": 10, "reseller.test": -5}, boosts)
+
+	for _, invalid := range []string{"partner.test", "partner.test=lots", "=10", "partner.test=101"} {
+		_, err := service.ParseDomainScoreBoosts(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,143 @@
+package servicetest
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nxdomainResolver implements validator.DNSResolver and answers that no domain exists
+type nxdomainResolver struct{}
+
+func (r *nxdomainResolver) LookupHost(domain string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+func (r *nxdomainResolver) LookupMX(domain string) ([]*net.MX, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+// nullMXResolver implements validator.DNSResolver for domains that resolve but publish a null MX
+type nullMXResolver struct{}
+
+func (r *nullMXResolver) LookupHost(domain string) ([]string, error) {
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *nullMXResolver) LookupMX(domain string) ([]*net.MX, error) {
+	return []*net.MX{{Host: ".", Pref: 0}}, nil
+}
+
+// countingMailboxVerifier counts mailbox probes and answers them as deliverable
+type countingMailboxVerifier struct {
+	calls int64
+}
+
+func (v *countingMailboxVerifier) Verify(email string) validator.SMTPResult {
+	atomic.AddInt64(&v.calls, 1)
+	return deliverable
+}
+
+func TestReasonCodes(t *testing.T) {
+	unverified := validator.SMTPResult{Status: validator.SMTPStatusAllMXUnreachable}
+	circuitOpen := validator.SMTPResult{Status: validator.SMTPStatusCircuitOpen}
+
+	tests := []struct {
+		name     string
+		email    string
+		resolver validator.DNSResolver
+		smtp     *validator.SMTPResult
+		policy   service.UnknownPolicy
+		want     model.ReasonCode
+	}{
+		{name: "Valid address has no code", email: "user@example.com", smtp: &deliverable, want: ""},
+		{name: "Missing email", email: "", want: model.ReasonMissingEmail},
+		{name: "Invalid syntax", email: "not-an-email", want: model.ReasonSyntaxInvalid},
+		{name: "Unknown TLD", email: "user@example.notatld", want: model.ReasonUnknownTLD},
+		{name: "DNS timeout under strict policy", email: "user@example.com", resolver: &timeoutDNSResolver{}, want: model.ReasonDNSTimeout},
+		{name: "DNS timeout under lenient policy", email: "user@example.com", resolver: &timeoutDNSResolver{}, policy: service.UnknownPolicyLenient, want: model.ReasonDNSTimeout},
+		{name: "Nonexistent domain", email: "user@example.com", resolver: &nxdomainResolver{}, want: model.ReasonDomainNotFound},
+		{name: "Null MX", email: "user@example.com", resolver: &nullMXResolver{}, smtp: &deliverable, want: model.ReasonNullMX},
+		{name: "No MX records", email: "user@example.com", resolver: &txtResolver{noMX: true}, want: model.ReasonNoMX},
+		{name: "Disposable domain", email: "user@mailinator.com", want: model.ReasonDisposable},
+		{name: "Rejected mailbox", email: "user@example.com", smtp: &rejected, want: model.ReasonMailboxNotFound},
+		{name: "Greylisted", email: "user@example.com", smtp: &greylisted, want: model.ReasonGreylisted},
+		{name: "Greylisted role account", email: "admin@example.com", smtp: &greylisted, want: model.ReasonGreylisted},
+		{name: "Unreachable mail servers", email: "user@example.com", smtp: &unverified, want: model.ReasonMailboxUnverified},
+		{name: "SMTP circuit open", email: "user@example.com", smtp: &circuitOpen, want: model.ReasonMailboxUnverified},
+		{name: "Catch-all", email: "user@example.com", smtp: &catchAll, want: model.ReasonCatchAll},
+		{name: "Catch-all role account", email: "admin@example.com", smtp: &catchAll, want: model.ReasonRoleUnverified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := tt.resolver
+			if resolver == nil {
+				resolver = &mockDNSResolver{}
+			}
+			emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			if tt.smtp != nil {
+				svc.SetMailboxVerifier(&stubMailboxVerifier{result: *tt.smtp})
+			}
+			if tt.policy != "" {
+				svc.SetUnknownPolicy(tt.policy)
+			}
+
+			result := svc.ValidateEmail(tt.email)
+			assert.Equal(t, tt.want, result.ReasonCode, "status %s", result.Status)
+			assert.Equal(t, tt.want == "", result.Status == model.ValidationStatusValid)
+
+			batch := svc.ValidateEmails([]string{tt.email})
+			if assert.Len(t, batch.Results, 1) {
+				assert.Equal(t, tt.want, batch.Results[0].ReasonCode)
+			}
+		})
+	}
+}
+
+func TestReasonCodeAfterMarkDisposable(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := svc.ValidateEmail("user@example.com")
+	assert.Empty(t, result.ReasonCode)
+
+	svc.MarkDisposable(&result, service.ValidationOptions{})
+	assert.Equal(t, model.ReasonDisposable, result.ReasonCode)
+}
+
+func TestNullMXIsExplainedAndNotProbed(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&nullMXResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	verifier := &countingMailboxVerifier{}
+	svc.SetMailboxVerifier(verifier)
+
+	result := svc.ValidateEmail("user@example.com")
+	assert.Equal(t, model.ValidationStatusNoMXRecords, result.Status)
+	assert.True(t, result.Validations.NullMX)
+	assert.False(t, result.Validations.MXRecords)
+	assert.Contains(t, result.Reason, "null MX")
+
+	batch := svc.ValidateEmails([]string{"other@example.com"})
+	if assert.Len(t, batch.Results, 1) {
+		assert.True(t, batch.Results[0].Validations.NullMX)
+		assert.Equal(t, model.ReasonNullMX, batch.Results[0].ReasonCode)
+	}
+	assert.Zero(t, atomic.LoadInt64(&verifier.calls), "a null MX domain is never probed")
+}
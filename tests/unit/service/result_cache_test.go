@@ -0,0 +1,102 @@
+package servicetest
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/cache"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultCacheKeyIsolatesCheckSets(t *testing.T) {
+	lenient := 0.5
+	sameLenient := 0.5
+	base := service.CheckSet{
+		UnknownPolicy:    service.UnknownPolicyStrict,
+		DisposablePolicy: service.DisposablePolicyReject,
+		Penalties:        service.DefaultConfidencePenalties(),
+		ScoringVersion:   service.ScoringVersion,
+	}
+
+	variants := map[string]func(*service.CheckSet){
+		"mailbox":           func(c *service.CheckSet) { c.Mailbox = true },
+		"domain age":        func(c *service.CheckSet) { c.DomainAge = true },
+		"unknown policy":    func(c *service.CheckSet) { c.UnknownPolicy = service.UnknownPolicyLenient },
+		"disposable policy": func(c *service.CheckSet) { c.DisposablePolicy = service.DisposablePolicyFlag },
+		"confidence":        func(c *service.CheckSet) { c.MinSuggestionConfidence = &lenient },
+		"penalties":         func(c *service.CheckSet) { c.Penalties.CatchAllCeiling = 50 },
+		"scoring version":   func(c *service.CheckSet) { c.ScoringVersion++ },
+	}
+	baseKey := service.ResultCacheKey("user@example.com", base)
+	assert.NotEqual(t, baseKey, service.ResultCacheKey("other@example.com", base))
+	for name, change := range variants {
+		checks := base
+		change(&checks)
+		assert.NotEqual(t, baseKey, service.ResultCacheKey("user@example.com", checks), name)
+	}
+
+	withConfidence, sameConfidence := base, base
+	withConfidence.MinSuggestionConfidence = &lenient
+	sameConfidence.MinSuggestionConfidence = &sameLenient
+	assert.Equal(t, service.ResultCacheKey("user@example.com", withConfidence), service.ResultCacheKey("user@example.com", sameConfidence),
+		"keys depend on the confidence value, not its address")
+}
+
+func TestResultCacheOnlyServesMatchingCheckSet(t *testing.T) {
+	resolver := &lookupCountingResolver{}
+	emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	svc.SetResultCache(service.NewResultCache(cache.NewMockCache(), service.DefaultResultCacheTTL))
+
+	// A cheap result without the mailbox probe
+	first := svc.ValidateEmail("user@example.com")
+	assert.Equal(t, model.ValidationStatusValid, first.Status)
+	lookups := atomic.LoadInt64(&resolver.lookups)
+
+	assert.Equal(t, first, svc.ValidateEmail("user@example.com"))
+	assert.Equal(t, lookups, atomic.LoadInt64(&resolver.lookups), "a repeated request is answered from the cache")
+
+	// The full check set must not reuse the cheap result
+	svc.SetMailboxVerifier(&stubMailboxVerifier{result: rejected})
+	full := svc.ValidateEmail("user@example.com")
+	assert.False(t, full.Validations.MailboxExists)
+	assert.Equal(t, model.ReasonMailboxNotFound, full.ReasonCode)
+
+	// Nor may a request with a different disposable policy reuse either one
+	flagged := svc.ValidateEmailWithOptions("user@mailinator.com", service.ValidationOptions{DisposablePolicy: service.DisposablePolicyFlag})
+	rejectedDisposable := svc.ValidateEmail("user@mailinator.com")
+	assert.NotEqual(t, flagged.Status, rejectedDisposable.Status)
+
+	svc.SetMailboxVerifier(nil)
+	assert.Equal(t, first, svc.ValidateEmail("user@example.com"))
+}
+
+func TestResultCacheSkipsInconclusiveResults(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&timeoutDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	svc.SetUnknownPolicy(service.UnknownPolicyLenient)
+	resultCache := service.NewResultCache(cache.NewMockCache(), service.DefaultResultCacheTTL)
+	svc.SetResultCache(resultCache)
+
+	result := svc.ValidateEmail("user@example.com")
+	assert.NotEmpty(t, result.Inconclusive)
+
+	checks := service.CheckSet{
+		UnknownPolicy:    service.UnknownPolicyLenient,
+		DisposablePolicy: service.DisposablePolicyReject,
+		Penalties:        service.DefaultConfidencePenalties(),
+		ScoringVersion:   service.ScoringVersion,
+	}
+	_, ok := resultCache.Get("user@example.com", checks)
+	assert.False(t, ok)
+}
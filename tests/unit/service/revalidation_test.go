@@ -0,0 +1,84 @@
+package servicetest
+
+import (
+	"testing"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/cache"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevalidationPolicyFor(t *testing.T) {
+	policy := service.RevalidationPolicy{
+		"VALID":                        90 * 24 * time.Hour,
+		"INVALID_FORMAT":               365 * 24 * time.Hour,
+		service.RevalidateCatchAll:     14 * 24 * time.Hour,
+		service.RevalidateInconclusive: 24 * time.Hour,
+	}
+
+	tests := []struct {
+		name     string
+		response model.EmailValidationResponse
+		want     time.Duration
+	}{
+		{name: "By status", response: model.EmailValidationResponse{Status: model.ValidationStatusValid}, want: 90 * 24 * time.Hour},
+		{name: "Catch-all shortens it", response: model.EmailValidationResponse{Status: model.ValidationStatusValid, Validations: model.ValidationResults{IsCatchAll: true}}, want: 14 * 24 * time.Hour},
+		{name: "Inconclusive shortens it further", response: model.EmailValidationResponse{Status: model.ValidationStatusValid, Validations: model.ValidationResults{IsCatchAll: true}, Inconclusive: []string{service.CheckMailboxExists}}, want: 24 * time.Hour},
+		{name: "Outcome without an interval", response: model.EmailValidationResponse{Status: model.ValidationStatusDisposable}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.For(tt.response))
+		})
+	}
+}
+
+func TestParseRevalidationPolicy(t *testing.T) {
+	policy, err := service.ParseRevalidationPolicy("valid=2160h, CATCH_ALL=72h")
+	require.NoError(t, err)
+	assert.Equal(t, 2160*time.Hour, policy["VALID"])
+	assert.Equal(t, 72*time.Hour, policy[service.RevalidateCatchAll])
+	assert.Equal(t, service.DefaultRevalidationPolicy()["NO_MX_RECORDS"], policy["NO_MX_RECORDS"], "unset outcomes keep their default")
+
+	for _, value := range []string{"VALID", "VALID=soon", "VALID=-1h", "BOUNCED=24h"} {
+		_, err := service.ParseRevalidationPolicy(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestValidationResultTimestamps(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	svc.SetMailboxVerifier(&stubMailboxVerifier{result: catchAll})
+	svc.SetRevalidationPolicy(service.RevalidationPolicy{"VALID": 90 * 24 * time.Hour, "PROBABLY_VALID": 30 * 24 * time.Hour, service.RevalidateCatchAll: time.Hour})
+
+	before := time.Now()
+	result := svc.ValidateEmail("user@example.com")
+	assert.WithinRange(t, result.ValidatedAt, before, time.Now())
+	assert.Equal(t, 3600, result.RevalidateAfter, "a catch-all mailbox is trusted for the catch-all interval")
+
+	batch := svc.ValidateEmails([]string{"user@example.com", "not-an-email"})
+	for _, result := range batch.Results {
+		assert.WithinRange(t, result.ValidatedAt, before, time.Now())
+	}
+	assert.Equal(t, 3600, batch.Results[0].RevalidateAfter)
+	assert.Zero(t, batch.Results[1].RevalidateAfter, "INVALID_FORMAT has no interval in this policy")
+}
+
+func TestCachedResultKeepsValidationTime(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	svc.SetResultCache(service.NewResultCache(cache.NewMockCache(), service.DefaultResultCacheTTL))
+
+	first := svc.ValidateEmail("user@example.com")
+	time.Sleep(10 * time.Millisecond)
+	second := svc.ValidateEmail("user@example.com")
+	assert.True(t, first.ValidatedAt.Equal(second.ValidatedAt), "a cached result reports when it was validated")
+}
@@ -0,0 +1,123 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		email          string
+		resolver       validator.DNSResolver
+		smtp           *validator.SMTPResult
+		wantRoleStatus model.RoleStatus
+		wantStatus     model.ValidationStatus
+		wantReason     model.ReasonCode
+		wantScore      int
+	}{
+		{
+			name:           "Deliverable role account",
+			email:          "support@example.com",
+			smtp:           &deliverable,
+			wantRoleStatus: model.RoleStatusDeliverable,
+			wantStatus:     model.ValidationStatusValid,
+			wantScore:      90,
+		},
+		{
+			name:           "Role account without a mailbox probe",
+			email:          "support@example.com",
+			wantRoleStatus: model.RoleStatusDeliverable,
+			wantStatus:     model.ValidationStatusValid,
+			wantScore:      90,
+		},
+		{
+			name:           "Role account with a rejected mailbox",
+			email:          "support@example.com",
+			smtp:           &rejected,
+			wantRoleStatus: model.RoleStatusUndeliverable,
+			wantStatus:     model.ValidationStatusInvalid,
+			wantReason:     model.ReasonRoleUndeliverable,
+			wantScore:      60,
+		},
+		{
+			name:           "Role account at a nonexistent domain",
+			email:          "support@example.com",
+			resolver:       &nxdomainResolver{},
+			wantRoleStatus: model.RoleStatusUndeliverable,
+			wantStatus:     model.ValidationStatusInvalidDomain,
+			wantReason:     model.ReasonRoleUndeliverable,
+			wantScore:      20,
+		},
+		{
+			name:           "Role account at a domain without MX records",
+			email:          "support@example.com",
+			resolver:       &txtResolver{noMX: true},
+			wantRoleStatus: model.RoleStatusUndeliverable,
+			wantStatus:     model.ValidationStatusNoMXRecords,
+			wantReason:     model.ReasonRoleUndeliverable,
+			wantScore:      40,
+		},
+		{
+			name:           "Role account on a catch-all server",
+			email:          "support@example.com",
+			smtp:           &catchAll,
+			wantRoleStatus: model.RoleStatusUnverified,
+			wantStatus:     model.ValidationStatusInvalid,
+			wantReason:     model.ReasonRoleUnverified,
+			wantScore:      62,
+		},
+		{
+			name:           "Greylisted role account",
+			email:          "support@example.com",
+			smtp:           &greylisted,
+			wantRoleStatus: model.RoleStatusUnverified,
+			wantStatus:     model.ValidationStatusInvalid,
+			wantReason:     model.ReasonGreylisted,
+			wantScore:      53,
+		},
+		{
+			name:       "Personal address has no role status",
+			email:      "user@example.com",
+			smtp:       &rejected,
+			wantStatus: model.ValidationStatusInvalid,
+			wantReason: model.ReasonMailboxNotFound,
+			wantScore:  80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := tt.resolver
+			if resolver == nil {
+				resolver = &mockDNSResolver{}
+			}
+			emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			if tt.smtp != nil {
+				svc.SetMailboxVerifier(&stubMailboxVerifier{result: *tt.smtp})
+			}
+
+			result := svc.ValidateEmail(tt.email)
+			assert.Equal(t, tt.wantRoleStatus, result.RoleStatus)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantReason, result.ReasonCode)
+			assert.Equal(t, tt.wantScore, result.Score)
+
+			batch := svc.ValidateEmails([]string{tt.email})
+			if assert.Len(t, batch.Results, 1) {
+				assert.Equal(t, tt.wantRoleStatus, batch.Results[0].RoleStatus)
+				assert.Equal(t, tt.wantReason, batch.Results[0].ReasonCode)
+				assert.Equal(t, tt.wantScore, batch.Results[0].Score)
+			}
+		})
+	}
+}
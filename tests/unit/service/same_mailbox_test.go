@@ -0,0 +1,67 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameMailbox(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	tests := []struct {
+		name          string
+		first, second string
+		want          bool
+	}{
+		{"dots and tag at Gmail", "J.Smith+news@gmail.com", "jsmith@gmail.com", true},
+		{"Gmail domain alias", "jsmith@googlemail.com", "j.smith@gmail.com", true},
+		{"case differs", "John@Example.com", "john@example.com", true},
+		{"trailing dot", "john@example.com.", "john@example.com", true},
+		{"plus tag at Outlook", "jsmith+shop@outlook.com", "jsmith@outlook.com", true},
+		{"dots matter at Outlook", "j.smith@outlook.com", "jsmith@outlook.com", false},
+		{"plus is part of the mailbox elsewhere", "john+x@example.com", "john@example.com", false},
+		{"different domains", "jsmith@gmail.com", "jsmith@yahoo.com", false},
+		{"Unicode and punycode domain", "user@例え.jp", "user@xn--r8jz45g.jp", true},
+		{"mixed-case punycode domain", "user@XN--BCHER-KVA.de", "user@Bücher.de", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.SameMailbox(tt.first, tt.second, service.ValidationOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.SameMailbox)
+			assert.Equal(t, tt.first, result.First)
+			assert.Equal(t, tt.second, result.Second)
+		})
+	}
+
+	result, err := svc.SameMailbox("J.Smith+news@GMail.com", "jsmith@googlemail.com", service.ValidationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "jsmith@gmail.com", result.FirstCanonical)
+	assert.Equal(t, "jsmith@gmail.com", result.SecondCanonical)
+
+	// With local-part lowercasing turned off, case is still ignored in the comparison
+	emailValidator.SetCanonicalizationRules(validator.CanonicalizationRules{})
+	result, err = svc.SameMailbox("John@example.com", "john@example.com", service.ValidationOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.SameMailbox)
+}
+
+func TestSameMailboxInvalidEmail(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	_, err = svc.SameMailbox("jsmith@gmail.com", "not-an-email", service.ValidationOptions{})
+	assert.ErrorContains(t, err, `"not-an-email"`)
+
+	_, err = svc.SameMailbox("", "jsmith@gmail.com", service.ValidationOptions{})
+	assert.Error(t, err)
+}
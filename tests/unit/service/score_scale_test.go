@@ -0,0 +1,71 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScoreScale(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    service.ScoreScale
+		wantErr bool
+	}{
+		{"", service.ScoreScalePercent, false},
+		{"percent", service.ScoreScalePercent, false},
+		{"probability", service.ScoreScaleProbability, false},
+		{"grade", service.ScoreScaleGrade, false},
+		{"stars", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := service.ParseScoreScale(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScoreScaleFormat(t *testing.T) {
+	tests := []struct {
+		score           int
+		wantPercent     int
+		wantProbability float64
+		wantGrade       string
+	}{
+		{100, 100, 1.0, "A"},
+		{90, 90, 0.9, "A"},
+		{89, 89, 0.89, "B"},
+		{80, 80, 0.8, "B"},
+		{75, 75, 0.75, "C"},
+		{60, 60, 0.6, "D"},
+		{59, 59, 0.59, "F"},
+		{0, 0, 0.0, "F"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantPercent, service.ScoreScalePercent.Format(tt.score), "percent(%d)", tt.score)
+		assert.Equal(t, tt.wantProbability, service.ScoreScaleProbability.Format(tt.score), "probability(%d)", tt.score)
+		assert.Equal(t, tt.wantGrade, service.ScoreScaleGrade.Format(tt.score), "grade(%d)", tt.score)
+	}
+}
+
+func TestEmailService_ScoreScaleFor(t *testing.T) {
+	emailService := service.NewEmailServiceWithDeps(nil)
+	assert.Equal(t, service.ScoreScalePercent, emailService.ScoreScaleFor(service.ValidationOptions{}))
+
+	emailService.SetScoreScale(service.ScoreScaleGrade)
+	assert.Equal(t, service.ScoreScaleGrade, emailService.ScoreScaleFor(service.ValidationOptions{}))
+
+	// A per-request scale overrides the configured one
+	opts := service.ValidationOptions{ScoreScale: service.ScoreScaleProbability}
+	assert.Equal(t, service.ScoreScaleProbability, emailService.ScoreScaleFor(opts))
+}
@@ -0,0 +1,129 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatusPrecedence(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    service.StatusPrecedence
+		wantErr bool
+	}{
+		{"", service.DefaultStatusPrecedence(), false},
+		{"invalid_mailbox>catch_all>disposable", service.StatusPrecedence{service.SignalInvalidMailbox, service.SignalCatchAll, service.SignalDisposable}, false},
+		{"Catch-All, Disposable, Invalid-Mailbox", service.StatusPrecedence{service.SignalCatchAll, service.SignalDisposable, service.SignalInvalidMailbox}, false},
+		{"disposable>invalid_mailbox", nil, true},
+		{"disposable>disposable>catch_all", nil, true},
+		{"disposable>invalid_mailbox>catch_all>valid", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := service.ParseStatusPrecedence(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	assert.Equal(t, "disposable>invalid_mailbox>catch_all", service.DefaultStatusPrecedence().String())
+}
+
+func TestStatusPrecedenceResolvesConflicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		precedence string
+		email      string
+		smtp       validator.SMTPResult
+		wantStatus model.ValidationStatus
+		wantReason model.ReasonCode
+	}{
+		{
+			name:       "Disposable beats a rejected mailbox by default",
+			email:      "user@mailinator.com",
+			smtp:       rejected,
+			wantStatus: model.ValidationStatusDisposable,
+			wantReason: model.ReasonDisposable,
+		},
+		{
+			name:       "A rejected mailbox can beat disposable",
+			precedence: "invalid_mailbox>disposable>catch_all",
+			email:      "user@mailinator.com",
+			smtp:       rejected,
+			wantStatus: model.ValidationStatusInvalid,
+			wantReason: model.ReasonMailboxNotFound,
+		},
+		{
+			name:       "Disposable beats catch-all by default",
+			email:      "user@mailinator.com",
+			smtp:       catchAll,
+			wantStatus: model.ValidationStatusDisposable,
+			wantReason: model.ReasonDisposable,
+		},
+		{
+			name:       "Catch-all ahead of disposable leaves the status to the score",
+			precedence: "catch_all>invalid_mailbox>disposable",
+			email:      "user@mailinator.com",
+			smtp:       catchAll,
+			wantStatus: model.ValidationStatusProbablyValid,
+			wantReason: model.ReasonCatchAll,
+		},
+		{
+			name:       "A signal that does not hold is skipped",
+			precedence: "catch_all>invalid_mailbox>disposable",
+			email:      "user@mailinator.com",
+			smtp:       deliverable,
+			wantStatus: model.ValidationStatusDisposable,
+			wantReason: model.ReasonDisposable,
+		},
+		{
+			name:       "Without conflicting signals the order does not matter",
+			precedence: "catch_all>invalid_mailbox>disposable",
+			email:      "user@example.com",
+			smtp:       rejected,
+			wantStatus: model.ValidationStatusInvalid,
+			wantReason: model.ReasonMailboxNotFound,
+		},
+	}
+
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			precedence, err := service.ParseStatusPrecedence(tt.precedence)
+			require.NoError(t, err)
+			emailService := service.NewEmailServiceWithDeps(emailValidator)
+			emailService.SetMailboxVerifier(&stubMailboxVerifier{result: tt.smtp})
+			emailService.SetStatusPrecedence(precedence)
+
+			result := emailService.ValidateEmail(tt.email)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantReason, result.ReasonCode)
+
+			batch := emailService.ValidateEmails([]string{tt.email})
+			assert.Equal(t, tt.wantStatus, batch.Results[0].Status)
+			assert.Equal(t, tt.wantReason, batch.Results[0].ReasonCode)
+		})
+	}
+}
+
+func TestStatusPrecedenceKeepsDomainChecksFirst(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+	emailService.SetStatusPrecedence(service.StatusPrecedence{service.SignalInvalidMailbox, service.SignalCatchAll, service.SignalDisposable})
+
+	result := emailService.ValidateEmail("user@mailinator.unknowntld")
+	assert.Equal(t, model.ValidationStatusUnknownTLD, result.Status)
+}
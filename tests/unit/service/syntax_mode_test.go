@@ -0,0 +1,55 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxMode(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+
+	// Lenient by default: comments are rejected with an explanation
+	result := emailService.ValidateEmail("jane(work)@example.com")
+	assert.Equal(t, model.ValidationStatusInvalidFormat, result.Status)
+	assert.Equal(t, validator.ViolationComment.Description(), result.Reason)
+
+	emailService.SetSyntaxMode(validator.SyntaxModeRFC5322)
+
+	// The domain is read past the comment
+	result = emailService.ValidateEmail("jane(work)@example.com")
+	assert.True(t, result.Validations.Syntax)
+	assert.Equal(t, model.ValidationStatusValid, result.Status)
+
+	batch := emailService.ValidateEmails([]string{"jane(work)@example.com", `"jane@home"@example.com`})
+	assert.Equal(t, model.ValidationStatusValid, batch.Results[0].Status)
+	assert.Equal(t, model.ValidationStatusValid, batch.Results[1].Status)
+}
+
+func TestSyntaxMode_PerRequestOverride(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+
+	opts := service.ValidationOptions{SyntaxMode: validator.SyntaxModeRFC5321Strict}
+
+	result := emailService.ValidateEmailWithOptions(`"jane doe"@example.com`, opts)
+	assert.True(t, result.Validations.Syntax)
+
+	batch := emailService.ValidateEmailsWithOptions([]string{`"jane doe"@example.com`}, opts)
+	assert.True(t, batch.Results[0].Validations.Syntax)
+
+	// The service default still applies to requests without an override
+	result = emailService.ValidateEmail(`"jane doe"@example.com`)
+	assert.Equal(t, model.ValidationStatusInvalidFormat, result.Status)
+}
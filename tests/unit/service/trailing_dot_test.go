@@ -0,0 +1,59 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrailingDotDomain(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	emailService := service.NewEmailServiceWithDeps(emailValidator)
+
+	tests := []struct {
+		name          string
+		email         string
+		wantStatus    model.ValidationStatus
+		wantCanonical string
+	}{
+		{
+			name:          "Fully-qualified domain is valid",
+			email:         "John.Doe@Example.com.",
+			wantStatus:    model.ValidationStatusValid,
+			wantCanonical: "John.Doe@example.com",
+		},
+		{
+			name:          "Disposable lookups ignore the trailing dot",
+			email:         "user@mailinator.com.",
+			wantStatus:    model.ValidationStatusDisposable,
+			wantCanonical: "user@mailinator.com",
+		},
+		{
+			name:          "Aliases are detected on fully-qualified domains",
+			email:         "john.doe+news@gmail.com.",
+			wantStatus:    model.ValidationStatusValid,
+			wantCanonical: "johndoe@gmail.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := emailService.ValidateEmail(tt.email)
+			assert.Equal(t, tt.email, result.Email, "the response echoes the input")
+			assert.True(t, result.Validations.Syntax)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantCanonical, result.Canonical)
+
+			batch := emailService.ValidateEmails([]string{tt.email})
+			assert.Equal(t, tt.wantStatus, batch.Results[0].Status)
+			assert.Equal(t, tt.wantCanonical, batch.Results[0].Canonical)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package servicetest
+
+import (
+	"sync"
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLearner records every domain it is told about
+type recordingLearner struct {
+	mu      sync.Mutex
+	domains []string
+}
+
+func (l *recordingLearner) Learn(domain string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.domains = append(l.domains, domain)
+}
+
+func TestDomainLearnerSeesOnlyValidDomains(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&lookupCountingResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	learner := &recordingLearner{}
+	svc.SetDomainLearner(learner)
+
+	svc.ValidateEmail("user@example.com")
+	svc.ValidateEmail("user@gmial.com")      // typo suggestion lowers the score
+	svc.ValidateEmail("user@example.qwerty") // unknown TLD
+	svc.ValidateEmail("user@mailinator.com") // disposable
+	svc.ValidateEmails([]string{"a@batch-example.com", "not-an-email"})
+
+	assert.ElementsMatch(t, []string{"example.com", "batch-example.com"}, learner.domains)
+
+	svc.SetDomainLearner(nil)
+	svc.ValidateEmail("user@example.com")
+	assert.Len(t, learner.domains, 2)
+}
+
+func TestAcceptTypoSuggestionTeachesLearner(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&lookupCountingResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	learner := &recordingLearner{}
+	svc.SetDomainLearner(learner)
+
+	assert.NoError(t, svc.AcceptTypoSuggestion("user@gmial.com", "user@Gmail.com"))
+	assert.Equal(t, []string{"gmail.com"}, learner.domains)
+
+	// A suggestion the service would not make is refused and teaches nothing
+	assert.Error(t, svc.AcceptTypoSuggestion("user@gmial.com", "user@attacker.example"))
+	assert.Error(t, svc.AcceptTypoSuggestion("user@example.com", "user@gmail.com"))
+	assert.Len(t, learner.domains, 1)
+}
@@ -0,0 +1,33 @@
+package servicetest
+
+import (
+	"testing"
+
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/cache"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypoRegionsInValidation(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	svc.SetResultCache(service.NewResultCache(cache.NewMockCache(), service.DefaultResultCacheTTL))
+
+	korean := service.ValidationOptions{TypoRegions: []string{"en", "kr"}}
+	assert.Equal(t, "minsu@naver.com", svc.ValidateEmailWithOptions("minsu@navr.com", korean).TypoSuggestion)
+	// A cached result for one region is not served to another
+	assert.Empty(t, svc.ValidateEmail("minsu@navr.com").TypoSuggestion)
+
+	batch := svc.ValidateEmailsWithOptions([]string{"ivan@mai.ru"}, service.ValidationOptions{TypoRegions: []string{"ru"}})
+	if assert.Len(t, batch.Results, 1) {
+		assert.Equal(t, "ivan@mail.ru", batch.Results[0].TypoSuggestion)
+	}
+
+	suggestion := svc.GetTypoSuggestionsWithOptions("hans@gmx.dee", service.ValidationOptions{TypoRegions: []string{"de"}})
+	assert.Equal(t, "hans@gmx.de", suggestion.TypoSuggestion)
+	assert.Greater(t, suggestion.Confidence, 0.8)
+}
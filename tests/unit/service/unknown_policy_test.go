@@ -0,0 +1,142 @@
+package servicetest
+
+import (
+	"net"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// timeoutDNSResolver implements validator.DNSResolver and times out on every lookup
+type timeoutDNSResolver struct{}
+
+func (r *timeoutDNSResolver) LookupHost(domain string) ([]string, error) {
+	return nil, validator.ErrDNSTimeout
+}
+
+func (r *timeoutDNSResolver) LookupMX(domain string) ([]*net.MX, error) {
+	return nil, validator.ErrDNSTimeout
+}
+
+func TestParseUnknownPolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    service.UnknownPolicy
+		wantErr bool
+	}{
+		{"", service.UnknownPolicyStrict, false},
+		{"strict", service.UnknownPolicyStrict, false},
+		{"lenient", service.UnknownPolicyLenient, false},
+		{"optimistic", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := service.ParseUnknownPolicy(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnknownPolicy_InconclusiveDNS(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     service.UnknownPolicy
+		wantStatus model.ValidationStatus
+		wantScore  int
+		wantExists bool
+		wantMX     bool
+	}{
+		{
+			name:       "Strict treats timeouts as failures",
+			policy:     service.UnknownPolicyStrict,
+			wantStatus: model.ValidationStatusInvalidDomain,
+			wantScore:  40,
+			wantExists: false,
+			wantMX:     false,
+		},
+		{
+			name:       "Lenient treats timeouts as passes but caps the status",
+			policy:     service.UnknownPolicyLenient,
+			wantStatus: model.ValidationStatusProbablyValid,
+			wantScore:  100,
+			wantExists: true,
+			wantMX:     true,
+		},
+	}
+
+	wantInconclusive := []string{service.CheckDomainExists, service.CheckMXRecords}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(&timeoutDNSResolver{})
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			svc.SetUnknownPolicy(tt.policy)
+
+			result := svc.ValidateEmail("user@slow-dns.com")
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantScore, result.Score)
+			assert.Equal(t, tt.wantExists, result.Validations.DomainExists)
+			assert.Equal(t, tt.wantMX, result.Validations.MXRecords)
+			assert.ElementsMatch(t, wantInconclusive, result.Inconclusive)
+
+			batch := svc.ValidateEmails([]string{"user@slow-dns.com"})
+			if assert.Len(t, batch.Results, 1) {
+				assert.Equal(t, tt.wantStatus, batch.Results[0].Status)
+				assert.Equal(t, tt.wantScore, batch.Results[0].Score)
+				assert.ElementsMatch(t, wantInconclusive, batch.Results[0].Inconclusive)
+			}
+		})
+	}
+}
+
+func TestUnknownPolicy_PerRequestOverride(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&timeoutDNSResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	opts := service.ValidationOptions{UnknownPolicy: service.UnknownPolicyLenient}
+
+	result := svc.ValidateEmailWithOptions("user@slow-dns.com", opts)
+	assert.Equal(t, model.ValidationStatusProbablyValid, result.Status)
+	assert.Equal(t, 100, result.Score)
+
+	batch := svc.ValidateEmailsWithOptions([]string{"user@slow-dns.com"}, opts)
+	if assert.Len(t, batch.Results, 1) {
+		assert.Equal(t, model.ValidationStatusProbablyValid, batch.Results[0].Status)
+	}
+
+	// Without the override the service's strict default applies
+	assert.Equal(t, model.ValidationStatusInvalidDomain, svc.ValidateEmail("user@slow-dns.com").Status)
+}
+
+func TestUnknownPolicy_ConclusiveResultsUnaffected(t *testing.T) {
+	for _, policy := range []service.UnknownPolicy{service.UnknownPolicyStrict, service.UnknownPolicyLenient} {
+		t.Run(string(policy), func(t *testing.T) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			svc.SetUnknownPolicy(policy)
+
+			result := svc.ValidateEmail("user@example.com")
+			assert.Equal(t, model.ValidationStatusValid, result.Status)
+			assert.Equal(t, 100, result.Score)
+			assert.Empty(t, result.Inconclusive)
+		})
+	}
+}
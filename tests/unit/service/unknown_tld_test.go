@@ -0,0 +1,54 @@
+package servicetest
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lookupCountingResolver resolves every domain and counts the lookups
+type lookupCountingResolver struct {
+	lookups int64
+}
+
+func (r *lookupCountingResolver) LookupHost(domain string) ([]string, error) {
+	atomic.AddInt64(&r.lookups, 1)
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *lookupCountingResolver) LookupMX(domain string) ([]*net.MX, error) {
+	atomic.AddInt64(&r.lookups, 1)
+	return []*net.MX{{Host: "mail." + domain, Pref: 10}}, nil
+}
+
+func TestUnknownTLD(t *testing.T) {
+	resolver := &lookupCountingResolver{}
+	emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := svc.ValidateEmail("user@example.qwerty")
+	assert.Equal(t, model.ValidationStatusUnknownTLD, result.Status)
+	assert.True(t, result.Validations.Syntax)
+	assert.True(t, result.Validations.UnknownTLD)
+	assert.False(t, result.Validations.DomainExists)
+	assert.Empty(t, result.Inconclusive)
+
+	batch := svc.ValidateEmails([]string{"user@example.qwerty", "user@example.com"})
+	if assert.Len(t, batch.Results, 2) {
+		assert.Equal(t, model.ValidationStatusUnknownTLD, batch.Results[0].Status)
+		assert.Equal(t, model.ValidationStatusValid, batch.Results[1].Status)
+		assert.False(t, batch.Results[1].Validations.UnknownTLD)
+	}
+
+	// Only example.com reached DNS: one host and one MX lookup
+	assert.Equal(t, int64(2), atomic.LoadInt64(&resolver.lookups))
+}
@@ -0,0 +1,90 @@
+package servicetest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDomain(t *testing.T) {
+	tests := []struct {
+		name       string
+		domain     string
+		resolver   validator.DNSResolver
+		opts       service.ValidationOptions
+		wantStatus model.ValidationStatus
+		wantReason model.ReasonCode
+	}{
+		{name: "Accepts mail", domain: "example.com", resolver: &mockDNSResolver{}, wantStatus: model.ValidationStatusValid},
+		{name: "Normalized", domain: "Example.COM.", resolver: &mockDNSResolver{}, wantStatus: model.ValidationStatusValid},
+		{name: "Does not exist", domain: "example.com", resolver: &nxdomainResolver{}, wantStatus: model.ValidationStatusInvalidDomain, wantReason: model.ReasonDomainNotFound},
+		{name: "Null MX", domain: "example.com", resolver: &nullMXResolver{}, wantStatus: model.ValidationStatusNoMXRecords, wantReason: model.ReasonNullMX},
+		{name: "DNS timeout", domain: "example.com", resolver: &timeoutDNSResolver{}, wantStatus: model.ValidationStatusInvalidDomain, wantReason: model.ReasonDNSTimeout},
+		{name: "Disposable", domain: "mailinator.com", resolver: &mockDNSResolver{}, wantStatus: model.ValidationStatusDisposable, wantReason: model.ReasonDisposable},
+		{
+			name:       "Disposable under the flag policy",
+			domain:     "mailinator.com",
+			resolver:   &mockDNSResolver{},
+			opts:       service.ValidationOptions{DisposablePolicy: service.DisposablePolicyFlag},
+			wantStatus: model.ValidationStatusProbablyValid,
+			wantReason: model.ReasonDisposable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailValidator, err := validator.NewEmailValidatorWithResolver(tt.resolver)
+			require.NoError(t, err)
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+
+			result, err := svc.ValidateDomain(context.Background(), tt.domain, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, strings.ToLower(validator.TrimTrailingDot(tt.domain)), result.Domain)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantReason, result.ReasonCode)
+		})
+	}
+}
+
+func TestValidateDomainUnknownTLD(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result, err := svc.ValidateDomain(context.Background(), "example.qwerty", service.ValidationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, model.ValidationStatusUnknownTLD, result.Status)
+	assert.True(t, result.Validations.UnknownTLD)
+}
+
+func TestValidateDomainAge(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	svc.SetDomainAgeChecker(&stubDomainAgeChecker{days: map[string]int{"example.com": 10}})
+
+	result, err := svc.ValidateDomain(context.Background(), "example.com", service.ValidationOptions{})
+	require.NoError(t, err)
+	if assert.NotNil(t, result.DomainAgeDays) {
+		assert.Equal(t, 10, *result.DomainAgeDays)
+	}
+	assert.True(t, result.IsNewDomain)
+}
+
+func TestValidateDomainInvalidName(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&mockDNSResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	for _, domain := range []string{"", "localhost", "user@example.com", "exa mple.com", "-example.com"} {
+		_, err := svc.ValidateDomain(context.Background(), domain, service.ValidationOptions{})
+		assert.Error(t, err, domain)
+	}
+}
@@ -0,0 +1,84 @@
+package servicetest
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEmailLocalOnly(t *testing.T) {
+	tests := []struct {
+		name       string
+		email      string
+		opts       service.ValidationOptions
+		wantStatus model.ValidationStatus
+		wantReason model.ReasonCode
+	}{
+		{name: "Passes every local check", email: "user@example.com", wantStatus: model.ValidationStatusProbablyValid},
+		{name: "Missing email", email: "", wantStatus: model.ValidationStatusMissingEmail, wantReason: model.ReasonMissingEmail},
+		{name: "Invalid syntax", email: "not-an-email", wantStatus: model.ValidationStatusInvalidFormat, wantReason: model.ReasonSyntaxInvalid},
+		{name: "Unknown TLD", email: "user@example.qwerty", wantStatus: model.ValidationStatusUnknownTLD, wantReason: model.ReasonUnknownTLD},
+		{name: "Disposable", email: "user@mailinator.com", wantStatus: model.ValidationStatusDisposable, wantReason: model.ReasonDisposable},
+		{
+			name:       "Disposable under the flag policy",
+			email:      "user@mailinator.com",
+			opts:       service.ValidationOptions{DisposablePolicy: service.DisposablePolicyFlag},
+			wantStatus: model.ValidationStatusProbablyValid,
+		},
+		{name: "Possible typo", email: "user@gmai.com", wantStatus: model.ValidationStatusProbablyValid, wantReason: model.ReasonPossibleTypo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &lookupCountingResolver{}
+			emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+			require.NoError(t, err)
+			svc := service.NewEmailServiceWithDeps(emailValidator)
+			verifier := &countingMailboxVerifier{}
+			svc.SetMailboxVerifier(verifier)
+
+			result := svc.ValidateEmailLocalOnly(tt.email, tt.opts)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantReason, result.ReasonCode)
+			assert.Zero(t, atomic.LoadInt64(&resolver.lookups), "no DNS lookups")
+			assert.Zero(t, atomic.LoadInt64(&verifier.calls), "no mailbox probes")
+		})
+	}
+}
+
+func TestValidateEmailLocalOnlyFields(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&lookupCountingResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+
+	result := svc.ValidateEmailLocalOnly("Jane.Doe+News@Gmail.com.", service.ValidationOptions{})
+	assert.True(t, result.Validations.Syntax)
+	assert.False(t, result.Validations.IsRoleBased)
+	assert.True(t, result.Validations.IsFreeProvider)
+	assert.False(t, result.Validations.IsDisposable)
+	assert.Equal(t, "janedoe@gmail.com", result.Canonical)
+	assert.True(t, result.HasSubaddress)
+	assert.Equal(t, "News", result.SubaddressTag)
+	require.NotNil(t, result.Addressing)
+	assert.True(t, result.Addressing.PlusAddressing)
+
+	assert.True(t, svc.ValidateEmailLocalOnly("admin@example.com", service.ValidationOptions{}).Validations.IsRoleBased)
+}
+
+func TestValidateEmailLocalOnlyHeuristics(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&lookupCountingResolver{})
+	require.NoError(t, err)
+	svc := service.NewEmailServiceWithDeps(emailValidator)
+	svc.SetDisposableHeuristicThreshold(0.5)
+
+	result := svc.ValidateEmailLocalOnly("user@temp-mail-inbox-42.com", service.ValidationOptions{})
+	require.NotNil(t, result.DisposableHeuristic)
+	assert.True(t, result.Validations.IsDisposable)
+	assert.Equal(t, model.ValidationStatusDisposable, result.Status)
+}
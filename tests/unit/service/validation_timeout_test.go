@@ -0,0 +1,98 @@
+package servicetest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"emailvalidator/internal/model"
+	"emailvalidator/internal/service"
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingMailboxVerifier never answers until release is closed
+type blockingMailboxVerifier struct {
+	release chan struct{}
+}
+
+func (v *blockingMailboxVerifier) Verify(email string) validator.SMTPResult {
+	<-v.release
+	return deliverable
+}
+
+// blockingDNSResolver never answers until release is closed
+type blockingDNSResolver struct {
+	release chan struct{}
+}
+
+func (r *blockingDNSResolver) LookupHost(domain string) ([]string, error) {
+	<-r.release
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *blockingDNSResolver) LookupMX(domain string) ([]*net.MX, error) {
+	<-r.release
+	return []*net.MX{{Host: "mail." + domain, Pref: 10}}, nil
+}
+
+func TestValidationTimeoutKeepsCompletedChecks(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&lookupCountingResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	verifier := &blockingMailboxVerifier{release: make(chan struct{})}
+	defer close(verifier.release)
+
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	svc.SetMailboxVerifier(verifier)
+	svc.SetValidationTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	result := svc.ValidateEmail("user@example.com")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// The domain checks finished and are reported as usual
+	assert.True(t, result.Validations.Syntax)
+	assert.True(t, result.Validations.DomainExists)
+	assert.True(t, result.Validations.MXRecords)
+	// The mailbox probe did not, and is resolved by the strict unknown policy
+	assert.False(t, result.Validations.MailboxExists)
+	assert.Equal(t, []string{service.CheckMailboxExists}, result.TimedOut)
+	assert.Contains(t, result.Inconclusive, service.CheckMailboxExists)
+	assert.Equal(t, model.ReasonTimeout, result.ReasonCode)
+	assert.Equal(t, model.ValidationStatusProbablyValid, result.Status)
+}
+
+func TestValidationTimeoutDuringDomainChecks(t *testing.T) {
+	resolver := &blockingDNSResolver{release: make(chan struct{})}
+	defer close(resolver.release)
+	emailValidator, err := validator.NewEmailValidatorWithResolver(resolver)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	svc.SetValidationTimeout(50 * time.Millisecond)
+
+	result := svc.ValidateEmail("user@example.com")
+	assert.True(t, result.Validations.Syntax)
+	assert.False(t, result.Validations.DomainExists)
+	assert.ElementsMatch(t, []string{service.CheckDomainExists, service.CheckMXRecords, service.CheckIsDisposable}, result.TimedOut)
+	assert.Equal(t, model.ReasonTimeout, result.ReasonCode)
+}
+
+func TestValidationWithoutTimeoutWaitsForEveryCheck(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&lookupCountingResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	svc := service.NewEmailServiceWithValidator(emailValidator)
+	svc.SetMailboxVerifier(&stubMailboxVerifier{result: deliverable})
+
+	result := svc.ValidateEmail("user@example.com")
+	assert.Empty(t, result.TimedOut)
+	assert.True(t, result.Validations.MailboxExists)
+	assert.Equal(t, model.ValidationStatusValid, result.Status)
+}
@@ -0,0 +1,16 @@
+// Package testutil contains helpers shared by the unit test packages
+package testutil
+
+import "net"
+
+// LoopbackMXResolver points every domain's MX at the loopback interface, so SMTP probes
+// reach a test server listening there
+type LoopbackMXResolver struct{}
+
+func (r *LoopbackMXResolver) LookupHost(domain string) ([]string, error) {
+	return []string{"127.0.0.1"}, nil
+}
+
+func (r *LoopbackMXResolver) LookupMX(domain string) ([]*net.MX, error) {
+	return []*net.MX{{Host: "127.0.0.1.", Pref: 10}}, nil
+}
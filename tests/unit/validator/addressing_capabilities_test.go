@@ -0,0 +1,92 @@
+package validatortest
+
+import (
+	"strings"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressingCapabilities(t *testing.T) {
+	detector := validator.NewAliasDetector()
+
+	tests := []struct {
+		domain string
+		want   validator.Capabilities
+	}{
+		{"gmail.com", validator.Capabilities{DotInsensitive: true, PlusAddressing: true, CaseInsensitive: true}},
+		{"GoogleMail.com", validator.Capabilities{DotInsensitive: true, PlusAddressing: true, CaseInsensitive: true}},
+		{"outlook.com", validator.Capabilities{PlusAddressing: true, CaseInsensitive: true}},
+		{"icloud.com", validator.Capabilities{PlusAddressing: true, CaseInsensitive: true}},
+		{"fastmail.com", validator.Capabilities{PlusAddressing: true, SubdomainAddressing: true, CaseInsensitive: true}},
+		{"jsmith.fastmail.com", validator.Capabilities{PlusAddressing: true, SubdomainAddressing: true, CaseInsensitive: true}},
+		{"yahoo.com", validator.Capabilities{CaseInsensitive: true}},
+		{"mail.gmail.com", validator.Capabilities{}},
+		{"example.com", validator.Capabilities{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			assert.Equal(t, tt.want, detector.AddressingCapabilities(tt.domain))
+		})
+	}
+}
+
+func TestSetAddressingCapabilities(t *testing.T) {
+	detector := validator.NewAliasDetector()
+
+	detector.SetAddressingCapabilities("Example.com", validator.Capabilities{PlusAddressing: true})
+	assert.Equal(t, validator.Capabilities{PlusAddressing: true}, detector.AddressingCapabilities("example.com"))
+
+	// Entries override the built-in table
+	detector.SetAddressingCapabilities("gmail.com", validator.Capabilities{})
+	assert.Equal(t, validator.Capabilities{}, detector.AddressingCapabilities("gmail.com"))
+}
+
+func TestParseAddressingCapabilities(t *testing.T) {
+	input := `domain,dot_insensitive,plus_addressing,subdomain_addressing
+# corporate domains
+Example.com,false,true,false
+mail.example.org, 1, 1, 1
+`
+	table, err := validator.ParseAddressingCapabilities(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]validator.Capabilities{
+		"example.com":      {PlusAddressing: true},
+		"mail.example.org": {DotInsensitive: true, PlusAddressing: true, SubdomainAddressing: true},
+	}, table)
+}
+
+func TestParseAddressingCapabilitiesCaseColumn(t *testing.T) {
+	input := `domain,dot_insensitive,plus_addressing,subdomain_addressing,case_insensitive
+example.com,false,true,false,true
+legacy.example.org,false,false,false,false
+`
+	table, err := validator.ParseAddressingCapabilities(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]validator.Capabilities{
+		"example.com":        {PlusAddressing: true, CaseInsensitive: true},
+		"legacy.example.org": {},
+	}, table)
+}
+
+func TestParseAddressingCapabilitiesErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty input":     "",
+		"wrong header":    "domain,dots,plus,subdomain\n",
+		"missing column":  "domain,dot_insensitive,plus_addressing,subdomain_addressing\nexample.com,true,true\n",
+		"invalid boolean": "domain,dot_insensitive,plus_addressing,subdomain_addressing\nexample.com,yes,true,false\n",
+		"extra column":    "domain,dot_insensitive,plus_addressing,subdomain_addressing,case_insensitive,extra\n",
+		"missing case":    "domain,dot_insensitive,plus_addressing,subdomain_addressing,case_insensitive\nexample.com,true,true,false\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := validator.ParseAddressingCapabilities(strings.NewReader(input))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package validatortest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+)
+
+// benchmarkAddresses mixes the address shapes a typical list contains
+var benchmarkAddresses = []string{
+	"user@example.com",
+	"first.last+tag@gmail.com",
+	"o'brien@example.co.uk",
+	"user@sub.domain.example.org",
+	"not-an-email",
+	"user@@example.com",
+	"very.long.local.part.with.many.dots@example.com",
+	"用户@例子.广告",
+}
+
+func BenchmarkValidateSyntax(b *testing.B) {
+	v, err := validator.NewEmailValidatorWithResolver(NewMockResolver())
+	if err != nil {
+		b.Fatalf("Failed to create validator: %v", err)
+	}
+
+	for _, mode := range []validator.SyntaxMode{validator.SyntaxModeLenient, validator.SyntaxModeRFC5322, validator.SyntaxModeRFC5321Strict} {
+		b.Run(string(mode), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				v.ValidateSyntaxMode(benchmarkAddresses[i%len(benchmarkAddresses)], mode)
+			}
+		})
+	}
+}
+
+// benchmarkDisposableList returns size distinct disposable domains
+func benchmarkDisposableList(size int) []string {
+	domains := make([]string, size)
+	for i := range domains {
+		domains[i] = fmt.Sprintf("temp%d.example", i)
+	}
+	return domains
+}
+
+func BenchmarkDisposableLookup(b *testing.B) {
+	domains := benchmarkDisposableList(100_000)
+	lookups := map[string]string{
+		"hit":       "temp4242.example",
+		"miss":      "gmail.com",
+		"subdomain": "mx.mail.temp4242.example",
+	}
+
+	for _, strategy := range []validator.MatchStrategy{validator.MatchStrategyExact, validator.MatchStrategySuffix} {
+		v := validator.NewDisposableValidatorWithDomains(domains)
+		if err := v.SetMatchStrategy(strategy); err != nil {
+			b.Fatalf("Failed to set match strategy: %v", err)
+		}
+		for name, domain := range lookups {
+			b.Run(string(strategy)+"/"+name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					v.Validate(domain)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDomainValidation(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		v := validator.NewDomainValidator(NewMockResolver(), validator.NewDomainCacheManager(time.Hour))
+		v.Validate("example.com")
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			v.Validate("example.com")
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		v := validator.NewDomainValidator(NewMockResolver(), validator.NewDomainCacheManager(time.Hour))
+		domains := make([]string, b.N)
+		for i := range domains {
+			domains[i] = fmt.Sprintf("domain%d.example", i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			v.Validate(domains[i])
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		v := validator.NewDomainValidator(NewMockResolver(), validator.NewDomainCacheManager(time.Hour))
+		v.Validate("example.com")
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				v.Validate("example.com")
+			}
+		})
+	})
+}
@@ -0,0 +1,210 @@
+package validatortest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+)
+
+// diffListServer serves a full list at /list and diffs at /diff. The diff handler answers
+// with the queued diff, or with diffStatus when it is set.
+type diffListServer struct {
+	*httptest.Server
+	mu         sync.Mutex
+	list       string
+	diff       string
+	diffStatus int
+	fullHits   atomic.Int32
+	diffHits   atomic.Int32
+	lastSince  atomic.Value
+}
+
+func newDiffListServer(t *testing.T, list string) *diffListServer {
+	t.Helper()
+	s := &diffListServer{list: list}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/list", func(w http.ResponseWriter, r *http.Request) {
+		s.fullHits.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		fmt.Fprint(w, s.list)
+	})
+	mux.HandleFunc("/diff", func(w http.ResponseWriter, r *http.Request) {
+		s.diffHits.Add(1)
+		s.lastSince.Store(r.URL.Query().Get("since"))
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case s.diffStatus != 0:
+			w.WriteHeader(s.diffStatus)
+		case s.diff == "":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, s.diff)
+		}
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *diffListServer) set(list, diff string, diffStatus int) {
+	s.mu.Lock()
+	s.list, s.diff, s.diffStatus = list, diff, diffStatus
+	s.mu.Unlock()
+}
+
+func (s *diffListServer) source() validator.BlocklistSource {
+	return validator.BlocklistSource{URL: s.URL + "/list", DiffURL: s.URL + "/diff"}
+}
+
+func refreshBlocklist(t *testing.T, blocklist *validator.DisposableBlocklist) validator.BlocklistStats {
+	t.Helper()
+	stats, err := blocklist.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	return stats
+}
+
+func TestDisposableBlocklistAppliesDiffs(t *testing.T) {
+	server := newDiffListServer(t, "tempmail.com\nmailinator.com\n")
+	blocklist := validator.NewDisposableBlocklistWithSources(server.source())
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := server.diffHits.Load(); got != 0 {
+		t.Errorf("diff fetched %d times on the first load, want 0", got)
+	}
+
+	server.set("", "# changes\n+Throwaway.com\n-mailinator.com\n\n", 0)
+	stats := refreshBlocklist(t, blocklist)
+	if got := server.fullHits.Load(); got != 1 {
+		t.Errorf("whole list fetched %d times, want 1", got)
+	}
+	if since, _ := server.lastSince.Load().(string); since == "" {
+		t.Error("diff requested without a since parameter")
+	}
+	if stats.Domains != 2 {
+		t.Errorf("Stats().Domains = %d after the diff, want 2", stats.Domains)
+	}
+	for domain, want := range map[string]bool{"tempmail.com": true, "throwaway.com": true, "mailinator.com": false} {
+		if got := blocklist.IsDisposable(domain); got != want {
+			t.Errorf("IsDisposable(%s) = %v after the diff, want %v", domain, got, want)
+		}
+	}
+
+	// No changes
+	server.set("", "", 0)
+	if stats := refreshBlocklist(t, blocklist); stats.Domains != 2 {
+		t.Errorf("Stats().Domains = %d after an empty diff, want 2", stats.Domains)
+	}
+	if got := server.fullHits.Load(); got != 1 {
+		t.Errorf("whole list fetched %d times, want 1", got)
+	}
+}
+
+func TestDisposableBlocklistFallsBackToFullFetch(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		diff       string
+		diffStatus int
+	}{
+		{name: "diff not available", diffStatus: http.StatusNotFound},
+		{name: "malformed diff", diff: "+throwaway.com\nmailinator.com\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newDiffListServer(t, "tempmail.com\nmailinator.com\n")
+			blocklist := validator.NewDisposableBlocklistWithSources(server.source())
+			if err := blocklist.Load(); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			server.set("tempmail.com\n", tt.diff, tt.diffStatus)
+			refreshBlocklist(t, blocklist)
+			if got := server.fullHits.Load(); got != 2 {
+				t.Errorf("whole list fetched %d times, want 2", got)
+			}
+			if blocklist.IsDisposable("mailinator.com") || blocklist.IsDisposable("throwaway.com") {
+				t.Error("the list does not match the whole list fetched after the diff failed")
+			}
+
+			// The next refresh tries the diff again
+			server.set("", "+throwaway.com\n", 0)
+			refreshBlocklist(t, blocklist)
+			if !blocklist.IsDisposable("throwaway.com") || !blocklist.IsDisposable("tempmail.com") {
+				t.Error("the diff was not applied on top of the whole list")
+			}
+		})
+	}
+}
+
+func TestDisposableBlocklistDiffKeepsEntriesListedByOtherSources(t *testing.T) {
+	first := newDiffListServer(t, "tempmail.com\nshared.com\n")
+	second := newDiffListServer(t, "shared.com\n")
+	blocklist := validator.NewDisposableBlocklistWithSources(first.source(), second.source())
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	first.set("", "-shared.com\n-tempmail.com\n", 0)
+	refreshBlocklist(t, blocklist)
+	if !blocklist.IsDisposable("shared.com") {
+		t.Error("IsDisposable(shared.com) = false, want true while the second source lists it")
+	}
+	if blocklist.IsDisposable("tempmail.com") {
+		t.Error("IsDisposable(tempmail.com) = true, want false after its only source removed it")
+	}
+
+	second.set("", "-shared.com\n", 0)
+	first.set("", "", 0)
+	refreshBlocklist(t, blocklist)
+	if blocklist.IsDisposable("shared.com") {
+		t.Error("IsDisposable(shared.com) = true, want false after every source removed it")
+	}
+}
+
+func TestDisposableBlocklistDiffWithSourceWithoutDiffs(t *testing.T) {
+	withDiffs := newDiffListServer(t, "tempmail.com\n")
+	plain := newDiffListServer(t, "mailinator.com\n")
+	blocklist := validator.NewDisposableBlocklistWithSources(withDiffs.source(), validator.BlocklistSource{URL: plain.URL + "/list"})
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	withDiffs.set("", "+throwaway.com\n", 0)
+	plain.set("mailinator.com\nguerrillamail.com\n", "", 0)
+	refreshBlocklist(t, blocklist)
+	if got := withDiffs.fullHits.Load(); got != 1 {
+		t.Errorf("source with diffs fetched whole %d times, want 1", got)
+	}
+	for _, domain := range []string{"tempmail.com", "throwaway.com", "mailinator.com", "guerrillamail.com"} {
+		if !blocklist.IsDisposable(domain) {
+			t.Errorf("IsDisposable(%s) = false, want true", domain)
+		}
+	}
+}
+
+func TestDisposableBlocklistDiffHonorsMatchStrategy(t *testing.T) {
+	server := newDiffListServer(t, "tempmail.com\n")
+	blocklist := validator.NewDisposableBlocklistWithSources(server.source())
+	blocklist.SetMatchStrategy(validator.MatchStrategySuffix)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	server.set("", "+throwaway.com\n-tempmail.com\n", 0)
+	refreshBlocklist(t, blocklist)
+	if !blocklist.IsDisposable("inbox.throwaway.com") {
+		t.Error("IsDisposable(inbox.throwaway.com) = false, want an added entry to cover subdomains")
+	}
+	if blocklist.IsDisposable("inbox.tempmail.com") {
+		t.Error("IsDisposable(inbox.tempmail.com) = true, want a removed entry gone")
+	}
+}
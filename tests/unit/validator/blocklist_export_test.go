@@ -0,0 +1,46 @@
+package validatortest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportDisposableAppliesAllowlist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "*.remote-wild.com\nremote.com\n")
+	}))
+	defer server.Close()
+
+	allowlist := []string{"*.wild.com", "ok.part.com", "*.remote-wild.com"}
+	v, err := validator.NewEmailValidator()
+	require.NoError(t, err)
+	v.SetDisposableValidator(validator.NewDisposableValidatorWithDomains([]string{"*.wild.com", "*.part.com", "local.com"}))
+	v.SetDisposableAllowlist(allowlist)
+	db := validator.NewDisposableBlocklistWithURL(server.URL)
+	db.SetAllowlist(allowlist)
+
+	export := func() []string {
+		var entries []string
+		ready, err := v.ExportDisposable(db, false, func(entry validator.BlocklistEntry) error {
+			entries = append(entries, entry.Entry)
+			return nil
+		})
+		require.NoError(t, err)
+		if !ready {
+			return nil
+		}
+		return entries
+	}
+	assert.Nil(t, export(), "nothing is exported until the blocklist loads")
+
+	require.NoError(t, db.Load())
+	// An allowlist entry covering part of a wildcard entry leaves the entry in effect
+	assert.Equal(t, []string{"*.part.com", "local.com", "remote.com"}, export())
+}
@@ -0,0 +1,150 @@
+package validatortest
+
+import (
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	detector := validator.NewAliasDetector()
+	rules := validator.DefaultCanonicalizationRules()
+
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"J.Smith+News@GMail.com", "jsmith@gmail.com"},
+		{"j.smith@googlemail.com", "jsmith@gmail.com"},
+		{"John.Doe+work@Outlook.com", "john.doe@outlook.com"},
+		{"anything@JSmith.fastmail.com", "jsmith@fastmail.com"},
+		{"jsmith+tag@fastmail.com", "jsmith@fastmail.com"},
+		{"John.Doe+tag@Example.COM", "John.Doe+tag@example.com"},
+		{"John.Doe@Yahoo.com", "john.doe@yahoo.com"},
+		{"+tag@gmail.com", "+tag@gmail.com"},
+		{"not-an-email", "not-an-email"},
+		{"user@例え.jp", "user@xn--r8jz45g.jp"},
+		{"user@XN--R8JZ45G.JP", "user@xn--r8jz45g.jp"},
+		{"User@Bücher.DE.", "User@xn--bcher-kva.de"},
+		{"user@BÜCHER.de", "user@xn--bcher-kva.de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.want, detector.Canonicalize(tt.email, rules))
+		})
+	}
+}
+
+func TestCanonicalizeRules(t *testing.T) {
+	detector := validator.NewAliasDetector()
+	email := "J.Smith+News@GoogleMail.com"
+
+	tests := []struct {
+		name  string
+		rules validator.CanonicalizationRules
+		want  string
+	}{
+		{"none", validator.CanonicalizationRules{}, "J.Smith+News@googlemail.com"},
+		{"keep dots", validator.CanonicalizationRules{LocalPartCase: validator.LocalPartCaseProvider, StripSubaddress: true, UnifyDomains: true}, "j.smith@gmail.com"},
+		{"keep subaddress", validator.CanonicalizationRules{LocalPartCase: validator.LocalPartCaseProvider, StripDots: true, UnifyDomains: true}, "jsmith+news@gmail.com"},
+		{"keep case", validator.CanonicalizationRules{StripDots: true, StripSubaddress: true, UnifyDomains: true}, "JSmith@gmail.com"},
+		{"keep domain", validator.CanonicalizationRules{LocalPartCase: validator.LocalPartCaseProvider, StripDots: true, StripSubaddress: true}, "jsmith@googlemail.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detector.Canonicalize(email, tt.rules))
+		})
+	}
+}
+
+func TestCanonicalizeLocalPartCase(t *testing.T) {
+	detector := validator.NewAliasDetector()
+
+	tests := []struct {
+		mode  validator.LocalPartCase
+		email string
+		want  string
+	}{
+		{validator.LocalPartCaseProvider, "JSmith@Outlook.com", "jsmith@outlook.com"},
+		{validator.LocalPartCaseProvider, "Shop@JSmith.fastmail.com", "shop@jsmith.fastmail.com"},
+		{validator.LocalPartCaseProvider, "JSmith@Example.com", "JSmith@example.com"},
+		{validator.LocalPartCaseLower, "JSmith@Outlook.com", "jsmith@outlook.com"},
+		{validator.LocalPartCaseLower, "JSmith@Example.com", "jsmith@example.com"},
+		{validator.LocalPartCasePreserve, "JSmith@Outlook.com", "JSmith@outlook.com"},
+		{validator.LocalPartCasePreserve, "JSmith@Example.com", "JSmith@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode)+"/"+tt.email, func(t *testing.T) {
+			rules := validator.CanonicalizationRules{LocalPartCase: tt.mode}
+			assert.Equal(t, tt.want, detector.Canonicalize(tt.email, rules))
+		})
+	}
+
+	// The table drives the provider mode, including entries added at runtime
+	detector.SetAddressingCapabilities("example.com", validator.Capabilities{CaseInsensitive: true})
+	assert.Equal(t, "jsmith@example.com", detector.Canonicalize("JSmith@Example.com", validator.DefaultCanonicalizationRules()))
+}
+
+func TestParseLocalPartCase(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    validator.LocalPartCase
+		wantErr bool
+	}{
+		{"", validator.LocalPartCaseProvider, false},
+		{"provider", validator.LocalPartCaseProvider, false},
+		{"Preserve", validator.LocalPartCasePreserve, false},
+		{"lower", validator.LocalPartCaseLower, false},
+		{"upper", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := validator.ParseLocalPartCase(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDetectSubaddress(t *testing.T) {
+	detector := validator.NewAliasDetector()
+
+	tests := []struct {
+		email   string
+		wantTag string
+		wantHas bool
+	}{
+		{"jsmith+news@gmail.com", "news", true},
+		{"j.smith+News@GoogleMail.com", "News", true},
+		{"jsmith+@gmail.com", "", true},
+		{"jsmith@gmail.com", "", false},
+		{"+news@gmail.com", "", false},
+		{"jsmith-shopping@yahoo.com", "shopping", true},
+		{"jsmith+shopping@yahoo.com", "", false},
+		{"jsmith@yahoo.com", "", false},
+		{"anything@jsmith.fastmail.com", "anything", true},
+		{"jsmith+tag@fastmail.com", "tag", true},
+		{"jsmith@fastmail.com", "", false},
+		{"john-doe+tag@example.com", "", false},
+		{"jsmith+news@gmail.com.", "news", true},
+		{"not-an-email", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			tag, has := detector.DetectSubaddress(tt.email)
+			assert.Equal(t, tt.wantHas, has)
+			assert.Equal(t, tt.wantTag, tag)
+		})
+	}
+}
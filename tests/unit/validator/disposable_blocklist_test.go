@@ -0,0 +1,506 @@
+package validatortest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+)
+
+func TestDisposableBlocklistNotReadyBeforeLoad(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		fmt.Fprintln(w, "tempmail.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+
+	if blocklist.IsReady() {
+		t.Error("IsReady() = true before Load, want false")
+	}
+
+	disposable, ready := blocklist.Lookup("tempmail.com")
+	if disposable || ready {
+		t.Errorf("Lookup() = (%v, %v) before Load, want (false, false)", disposable, ready)
+	}
+	if blocklist.IsDisposable("tempmail.com") {
+		t.Error("IsDisposable() = true before Load, want false")
+	}
+	if got := atomic.LoadInt32(&hits); got != 0 {
+		t.Errorf("blocklist was fetched %d times during lookups, want 0", got)
+	}
+}
+
+func TestDisposableBlocklistLoad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "# comment")
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "TempMail.com")
+		fmt.Fprintln(w, "mailinator.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !blocklist.IsReady() {
+		t.Fatal("IsReady() = false after Load, want true")
+	}
+
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{"tempmail.com", true},
+		{"MAILINATOR.COM", true},
+		{"gmail.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			disposable, ready := blocklist.Lookup(tt.domain)
+			if !ready {
+				t.Errorf("Lookup(%q) ready = false, want true", tt.domain)
+			}
+			if disposable != tt.want {
+				t.Errorf("Lookup(%q) = %v, want %v", tt.domain, disposable, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisposableBlocklistLoadInternationalizedDomains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "dé.net")
+		fmt.Fprintln(w, "xn--9kq967o.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for _, domain := range []string{"dé.net", "xn--d-bga.net", "雨云.com", "xn--9kq967o.com"} {
+		if !blocklist.IsDisposable(domain) {
+			t.Errorf("IsDisposable(%q) = false, want true", domain)
+		}
+	}
+}
+
+func TestDisposableBlocklistMatchStrategy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "mailinator.com")
+		fmt.Fprintln(w, ".tempmail.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	blocklist.SetMatchStrategy(validator.MatchStrategyAnnotated)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for domain, want := range map[string]bool{
+		"mailinator.com":     true,
+		"sub.mailinator.com": false,
+		"tempmail.com":       true,
+		"sub.tempmail.com":   true,
+	} {
+		if got := blocklist.IsDisposable(domain); got != want {
+			t.Errorf("IsDisposable(%q) = %v, want %v", domain, got, want)
+		}
+	}
+}
+
+func TestDisposableBlocklistLoadFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	if err := blocklist.Load(); err == nil {
+		t.Fatal("Load() error = nil, want error for non-200 response")
+	}
+
+	if blocklist.IsReady() {
+		t.Error("IsReady() = true after failed Load, want false")
+	}
+}
+
+func TestDisposableBlocklistMergesSourcesWithParsers(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/list.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "# plaintext")
+		fmt.Fprintln(w, "tempmail.com")
+	})
+	mux.HandleFunc("/list.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `["mailinator.com"]`)
+	})
+	mux.HandleFunc("/list.csv", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "domain,source")
+		fmt.Fprintln(w, "*.throwaway.test,community")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithSources(
+		validator.BlocklistSource{URL: server.URL + "/list.txt"},
+		validator.BlocklistSource{URL: server.URL + "/list.json", Parser: validator.JSONParser{}},
+		validator.BlocklistSource{URL: server.URL + "/list.csv", Parser: validator.CSVParser{HasHeader: true}},
+	)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for domain, want := range map[string]bool{
+		"tempmail.com":     true,
+		"mailinator.com":   true,
+		"a.throwaway.test": true,
+		"domain":           false,
+		"gmail.com":        false,
+	} {
+		if got := blocklist.IsDisposable(domain); got != want {
+			t.Errorf("IsDisposable(%q) = %v, want %v", domain, got, want)
+		}
+	}
+}
+
+func TestDisposableBlocklistFailsWhenASourceCannotBeParsed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "not json")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithSources(
+		validator.BlocklistSource{URL: server.URL, Parser: validator.JSONParser{}},
+	)
+	if err := blocklist.Load(); err == nil {
+		t.Fatal("Load() error = nil, want parse error")
+	}
+	if blocklist.IsReady() {
+		t.Error("IsReady() = true after failed Load, want false")
+	}
+}
+
+func TestDisposableBlocklistLoadsPartialSuccesses(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/good.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "tempmail.com")
+	})
+	mux.HandleFunc("/broken.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithSources(
+		validator.BlocklistSource{URL: server.URL + "/broken.txt"},
+		validator.BlocklistSource{URL: server.URL + "/good.txt"},
+	)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v, want nil when a source succeeds", err)
+	}
+	if !blocklist.IsDisposable("tempmail.com") {
+		t.Error("IsDisposable(tempmail.com) = false, want true from the working source")
+	}
+}
+
+func TestDisposableBlocklistSlowSourceCannotBlockPastDeadline(t *testing.T) {
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fast.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "tempmail.com")
+	})
+	mux.HandleFunc("/slow.txt", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	defer close(release)
+
+	blocklist := validator.NewDisposableBlocklistWithSources(
+		validator.BlocklistSource{URL: server.URL + "/slow.txt"},
+		validator.BlocklistSource{URL: server.URL + "/fast.txt"},
+	)
+	blocklist.SetLoadTimeout(200 * time.Millisecond)
+
+	start := time.Now()
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Load() took %v, want it to give up on the slow source at the deadline", elapsed)
+	}
+	if !blocklist.IsDisposable("tempmail.com") {
+		t.Error("IsDisposable(tempmail.com) = false, want true from the fast source")
+	}
+}
+
+func TestDisposableBlocklistZeroLoadTimeoutSetsNoDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "tempmail.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	blocklist.SetLoadTimeout(0)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !blocklist.IsDisposable("tempmail.com") {
+		t.Error("IsDisposable(tempmail.com) = false, want true")
+	}
+}
+
+func TestDisposableBlocklistBoundsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			peak := atomic.LoadInt32(&maxInFlight)
+			if n <= peak || atomic.CompareAndSwapInt32(&maxInFlight, peak, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprintln(w, strings.TrimPrefix(r.URL.Path, "/"))
+	}))
+	defer server.Close()
+
+	var sources []validator.BlocklistSource
+	for i := 0; i < 6; i++ {
+		sources = append(sources, validator.BlocklistSource{URL: fmt.Sprintf("%s/source%d.test", server.URL, i)})
+	}
+	blocklist := validator.NewDisposableBlocklistWithSources(sources...)
+	blocklist.SetConcurrency(2)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if peak := atomic.LoadInt32(&maxInFlight); peak != 2 {
+		t.Errorf("peak concurrent fetches = %d, want 2", peak)
+	}
+	for i := 0; i < 6; i++ {
+		if domain := fmt.Sprintf("source%d.test", i); !blocklist.IsDisposable(domain) {
+			t.Errorf("IsDisposable(%s) = false, want true", domain)
+		}
+	}
+}
+
+func TestDisposableBlocklistLookupsDoNotWaitForBackgroundLoad(t *testing.T) {
+	release := make(chan struct{})
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		fmt.Fprintln(w, "tempmail.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := blocklist.LoadInBackground(ctx, time.Millisecond)
+
+	// 100 concurrent checks while the fetch is blocked must all answer "not ready" at once
+	var wg sync.WaitGroup
+	var notReady int32
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if disposable, ready := blocklist.Lookup("tempmail.com"); !disposable && !ready {
+				atomic.AddInt32(&notReady, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("lookups took %v during the initial load, want them not to wait for it", elapsed)
+	}
+	if notReady != 100 {
+		t.Errorf("%d of 100 lookups reported (false, false) during the initial load, want all", notReady)
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background load did not finish after the source responded")
+	}
+	if disposable, ready := blocklist.Lookup("tempmail.com"); !disposable || !ready {
+		t.Errorf("Lookup() = (%v, %v) after the background load, want (true, true)", disposable, ready)
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("blocklist was fetched %d times, want 1", got)
+	}
+}
+
+func TestDisposableBlocklistBackgroundLoadRetriesFailures(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "tempmail.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	select {
+	case <-blocklist.LoadInBackground(ctx, 10*time.Millisecond):
+	case <-time.After(5 * time.Second):
+		t.Fatal("background load did not finish")
+	}
+	if !blocklist.IsDisposable("tempmail.com") {
+		t.Error("IsDisposable(tempmail.com) = false, want true once a retry succeeded")
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Errorf("blocklist was fetched %d times, want 3", got)
+	}
+}
+
+func TestDisposableBlocklistLoadCanBeRetriedAfterFailure(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, "tempmail.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	if err := blocklist.Load(); err == nil {
+		t.Fatal("Load() error = nil, want error for non-200 response")
+	}
+	fail.Store(false)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("second Load() error = %v, want the retry to succeed", err)
+	}
+	if !blocklist.IsDisposable("tempmail.com") {
+		t.Error("IsDisposable(tempmail.com) = false after the retried Load, want true")
+	}
+}
+
+func TestDisposableBlocklistBackgroundLoadStopsWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := blocklist.LoadInBackground(ctx, time.Hour)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background load kept retrying after its context was cancelled")
+	}
+	if blocklist.IsReady() {
+		t.Error("IsReady() = true, want false when every load failed")
+	}
+}
+
+func TestDisposableBlocklistRefreshSharesInFlightRefresh(t *testing.T) {
+	release := make(chan struct{})
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		fmt.Fprintln(w, "tempmail.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+
+	var wg sync.WaitGroup
+	stats := make([]validator.BlocklistStats, 10)
+	errs := make([]error, 10)
+	for i := range stats {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stats[i], errs[i] = blocklist.Refresh(context.Background())
+		}(i)
+	}
+	// Let every caller join the refresh before the source answers
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range stats {
+		if errs[i] != nil {
+			t.Fatalf("Refresh() error = %v", errs[i])
+		}
+		if stats[i].Domains != 1 || stats[i].LoadedAt.IsZero() {
+			t.Errorf("Refresh() = %+v, want 1 domain and a load time", stats[i])
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("blocklist was fetched %d times by concurrent refreshes, want 1", got)
+	}
+
+	// A refresh after the previous one finished fetches again
+	if _, err := blocklist.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("blocklist was fetched %d times, want 2", got)
+	}
+}
+
+func TestDisposableBlocklistRefreshKeepsListOnFailure(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, "tempmail.com")
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithURL(server.URL)
+	if err := blocklist.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	loadedAt := blocklist.Stats().LoadedAt
+
+	fail.Store(true)
+	if _, err := blocklist.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() error = nil, want error for non-200 response")
+	}
+	if !blocklist.IsDisposable("tempmail.com") {
+		t.Error("IsDisposable(tempmail.com) = false after a failed refresh, want the old list kept")
+	}
+	if got := blocklist.Stats().LoadedAt; !got.Equal(loadedAt) {
+		t.Errorf("Stats().LoadedAt = %v after a failed refresh, want %v", got, loadedAt)
+	}
+}
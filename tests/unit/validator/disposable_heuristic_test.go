@@ -0,0 +1,101 @@
+package validatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisposableHeuristicDetectorScore(t *testing.T) {
+	tests := []struct {
+		domain    string
+		wantScore float64
+		wantRules []string
+	}{
+		{"example.com", 0, nil},
+		{"gmail.com", 0.2, []string{"mailbox_word"}},
+		{"newtempmail.io", 0.95, []string{"disposable_keyword", "throwaway_word", "mailbox_word"}},
+		{"30minutemail.net", 0.84, []string{"minute_mail", "mailbox_word"}},
+		{"junkbox.org", 0.52, []string{"throwaway_word", "mailbox_word"}},
+		{"x7k2m9qa.example.com", 0.5, []string{validator.RandomLabelRule}},
+		{"qzkwrtxp.net", 0.5, []string{validator.RandomLabelRule}},
+		// The public suffix is not a label the heuristics look at
+		{"shop.co.uk", 0, nil},
+		{"TempMail.example.", 0.95, []string{"disposable_keyword", "throwaway_word", "mailbox_word"}},
+	}
+
+	detector := validator.NewDisposableHeuristicDetector()
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			score, rules := detector.Score(tt.domain)
+			assert.Equal(t, tt.wantScore, score)
+			assert.Equal(t, tt.wantRules, rules)
+		})
+	}
+}
+
+func TestParseHeuristicRules(t *testing.T) {
+	rules, err := validator.ParseHeuristicRules([]string{`burner 0.7 burn(er)?`, validator.RandomLabelRule + " 0.3"})
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "burner", rules[0].Name)
+	assert.Equal(t, 0.7, rules[0].Weight)
+	assert.True(t, rules[0].Pattern.MatchString("burnermail.com"))
+	assert.Nil(t, rules[1].Pattern)
+
+	for _, line := range []string{
+		"burner",
+		"burner 0.7",
+		"burner 1.5 burn",
+		"burner high burn",
+		"burner 0.7 burn(",
+		"burner 0.7 burn extra",
+		validator.RandomLabelRule + " 0.3 [a-z]+",
+	} {
+		t.Run(line, func(t *testing.T) {
+			_, err := validator.ParseHeuristicRules([]string{line})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDisposableHeuristicDetectorFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heuristics.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# Custom rules\nburner 0.7 burner\n"), 0o600))
+
+	detector, err := validator.NewDisposableHeuristicDetectorFromFile(path)
+	require.NoError(t, err)
+	score, rules := detector.Score("burnerbox.com")
+	assert.Equal(t, 0.7, score)
+	assert.Equal(t, []string{"burner"}, rules)
+
+	// An invalid file keeps the current rules
+	require.NoError(t, os.WriteFile(path, []byte("burner 2 burner\n"), 0o600))
+	assert.Error(t, detector.Reload())
+	score, _ = detector.Score("burnerbox.com")
+	assert.Equal(t, 0.7, score)
+
+	require.NoError(t, os.WriteFile(path, []byte("box 0.4 box\n"), 0o600))
+	require.NoError(t, detector.Reload())
+	score, rules = detector.Score("burnerbox.com")
+	assert.Equal(t, 0.4, score)
+	assert.Equal(t, []string{"box"}, rules)
+}
+
+func TestEmailValidatorDisposableHeuristicAllowlist(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&MockResolver{})
+	require.NoError(t, err)
+
+	score, _ := emailValidator.DisposableHeuristic("newtempmail.io")
+	assert.Equal(t, 0.95, score)
+
+	emailValidator.SetDisposableAllowlist([]string{"newtempmail.io"})
+	score, rules := emailValidator.DisposableHeuristic("newtempmail.io")
+	assert.Zero(t, score)
+	assert.Empty(t, rules)
+}
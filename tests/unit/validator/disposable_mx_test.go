@@ -0,0 +1,142 @@
+package validatortest
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mxHostResolver answers every domain with fixed MX hosts, or fails MX lookups with err
+type mxHostResolver struct {
+	hosts   []string
+	err     error
+	lookups int
+}
+
+func (r *mxHostResolver) LookupHost(domain string) ([]string, error) {
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *mxHostResolver) LookupMX(domain string) ([]*net.MX, error) {
+	r.lookups++
+	if r.err != nil {
+		return nil, r.err
+	}
+	records := make([]*net.MX, 0, len(r.hosts))
+	for _, host := range r.hosts {
+		records = append(records, &net.MX{Host: host, Pref: 10})
+	}
+	return records, nil
+}
+
+func newDisposableMXTestValidator(t *testing.T, resolver *mxHostResolver) *validator.EmailValidator {
+	t.Helper()
+	v, err := validator.NewEmailValidatorWithResolver(resolver)
+	require.NoError(t, err)
+	v.SetDisposableMXDetector(validator.NewDisposableMXDetector())
+	return v
+}
+
+func TestDisposableMXDetectorProvider(t *testing.T) {
+	detector := validator.NewDisposableMXDetector()
+	assert.Equal(t, "mailinator.com", detector.Provider([]string{"example.net", "mailinator.com"}))
+	assert.Empty(t, detector.Provider([]string{"google.com"}))
+	assert.Empty(t, detector.Provider(nil))
+}
+
+func TestEmailValidatorDisposableMXProvider(t *testing.T) {
+	tests := []struct {
+		name  string
+		hosts []string
+		want  string
+	}{
+		{name: "Provider mail server", hosts: []string{"mail2.mailinator.com."}, want: "mailinator.com"},
+		{name: "Provider among several", hosts: []string{"mx.example.net.", "MX.YOPMAIL.COM."}, want: "yopmail.com"},
+		{name: "Regular mail servers", hosts: []string{"aspmx.l.google.com."}},
+		{name: "Look-alike host", hosts: []string{"mailinator.com.example.net."}},
+		{name: "Null MX", hosts: []string{"."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newDisposableMXTestValidator(t, &mxHostResolver{hosts: tt.hosts})
+			provider, inconclusive := v.DisposableMXProvider("vanity-domain.com")
+			assert.Equal(t, tt.want, provider)
+			assert.False(t, inconclusive)
+		})
+	}
+}
+
+func TestEmailValidatorDisposableMXProviderCache(t *testing.T) {
+	resolver := &mxHostResolver{hosts: []string{"mx.mailinator.com."}}
+	v := newDisposableMXTestValidator(t, resolver)
+
+	for i := 0; i < 3; i++ {
+		provider, _ := v.DisposableMXProvider("vanity-domain.com")
+		assert.Equal(t, "mailinator.com", provider)
+	}
+	assert.Equal(t, 1, resolver.lookups, "the MX records are cached")
+
+	// Inconclusive lookups are retried
+	resolver = &mxHostResolver{err: &net.DNSError{Err: "timeout", IsTimeout: true}}
+	v = newDisposableMXTestValidator(t, resolver)
+	for i := 0; i < 2; i++ {
+		provider, inconclusive := v.DisposableMXProvider("vanity-domain.com")
+		assert.Empty(t, provider)
+		assert.True(t, inconclusive)
+	}
+	assert.Equal(t, 2, resolver.lookups)
+}
+
+func TestEmailValidatorDisposableMXProviderDisabledOrAllowlisted(t *testing.T) {
+	resolver := &mxHostResolver{hosts: []string{"mx.mailinator.com."}}
+	v, err := validator.NewEmailValidatorWithResolver(resolver)
+	require.NoError(t, err)
+
+	provider, _ := v.DisposableMXProvider("vanity-domain.com")
+	assert.Empty(t, provider, "the check is disabled by default")
+	assert.Zero(t, resolver.lookups)
+
+	v.SetDisposableMXDetector(validator.NewDisposableMXDetector())
+	v.SetDisposableAllowlist([]string{"vanity-domain.com"})
+	provider, _ = v.DisposableMXProvider("vanity-domain.com")
+	assert.Empty(t, provider, "allowlisted domains are never disposable")
+	assert.Zero(t, resolver.lookups)
+}
+
+func TestDisposableMXDetectorFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disposable_mx.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# disposable providers\nthrowaway.com\n"), 0o644))
+
+	detector, err := validator.NewDisposableMXDetectorFromFile(path)
+	require.NoError(t, err)
+	resolver := &mxHostResolver{hosts: []string{"in.throwaway.com."}}
+	v, err := validator.NewEmailValidatorWithResolver(resolver)
+	require.NoError(t, err)
+	v.SetDisposableMXDetector(detector)
+
+	provider, _ := v.DisposableMXProvider("vanity-domain.com")
+	assert.Equal(t, "throwaway.com", provider)
+	assert.Empty(t, detector.Provider([]string{"mailinator.com"}), "the file replaces the built-in list")
+
+	// A reloaded list applies to cached MX records at once
+	require.NoError(t, os.WriteFile(path, []byte("other-provider.com\n"), 0o644))
+	require.NoError(t, v.Reload())
+	provider, _ = v.DisposableMXProvider("vanity-domain.com")
+	assert.Empty(t, provider)
+	assert.Equal(t, 1, resolver.lookups)
+
+	// An invalid list is rejected on reload and the current one is kept
+	require.NoError(t, os.WriteFile(path, []byte("not a domain!\n"), 0o644))
+	assert.Error(t, detector.Reload())
+	assert.Equal(t, "other-provider.com", detector.Provider([]string{"other-provider.com"}))
+
+	_, err = validator.NewDisposableMXDetectorFromFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package validatortest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisposableValidatorPatterns(t *testing.T) {
+	patterns, err := validator.NewDisposablePatterns([]string{`^\d+min(ute)?mail\.`, `^temp-?mail\d*\.`})
+	require.NoError(t, err)
+
+	v := validator.NewDisposableValidatorWithDomains([]string{"mailinator.com"})
+	v.SetPatterns(patterns)
+	v.SetAllowlist([]string{"temp-mail.org"})
+
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{"mailinator.com", true},
+		{"10minutemail.com", true},
+		{"20MINMAIL.it", true},
+		{"tempmail7.net", true},
+		{"temp-mail.org", false},
+		{"minutemail.com", false},
+		{"my10minutemail.com", false},
+		{"gmail.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			assert.Equal(t, tt.want, v.Validate(tt.domain))
+		})
+	}
+
+	assert.Equal(t, []validator.DisposableSource{
+		{Source: validator.DisposableSourceLocal, Entry: `^\d+min(ute)?mail\.`, Match: validator.MatchPattern},
+	}, v.Sources("10minutemail.com"))
+}
+
+func TestParseDisposablePatterns(t *testing.T) {
+	_, err := validator.ParseDisposablePatterns([]string{`^\d+min(ute)?mail\.`, `(^|\.)trash-?mail\.`})
+	require.NoError(t, err)
+
+	for _, pattern := range []string{
+		`(tempmail`,
+		`.*`,
+		`(mail)?`,
+		`(a{100}){100}`,
+		strings.Repeat("a", validator.MaxDisposablePatternLength+1),
+	} {
+		_, err := validator.ParseDisposablePatterns([]string{pattern})
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestDisposablePatternsReloadKeepsPatternsOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "patterns.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# timed inboxes\n^\\d+minutemail\\.\n"), 0o644))
+	patterns, err := validator.NewDisposablePatternsFromFile(path)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("^\\d+minutemail\\.\n.*\n"), 0o644))
+	assert.Error(t, patterns.Reload())
+	assert.Equal(t, `^\d+minutemail\.`, patterns.Match("10minutemail.com"))
+	assert.Empty(t, patterns.Match("gmail.com"))
+}
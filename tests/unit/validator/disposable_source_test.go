@@ -0,0 +1,83 @@
+package validatortest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainMatcherEntries(t *testing.T) {
+	matcher := validator.NewDomainMatcherWithStrategy([]string{"mail.temp.com", "*.temp.com", ".com.example", "temp.com"}, validator.MatchStrategyAnnotated)
+
+	assert.Equal(t, []string{"mail.temp.com", "*.temp.com"}, matcher.Entries("MAIL.temp.com."))
+	assert.Equal(t, []string{"temp.com"}, matcher.Entries("temp.com"))
+	assert.Equal(t, []string{".com.example"}, matcher.Entries("x.com.example"))
+	assert.Empty(t, matcher.Entries("gmail.com"))
+}
+
+func TestDisposableBlocklistSources(t *testing.T) {
+	lists := map[string]string{
+		"/a": "tempmail.com\n*.throwaway.com\n",
+		"/b": "TempMail.com\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, lists[r.URL.Path])
+	}))
+	defer server.Close()
+
+	blocklist := validator.NewDisposableBlocklistWithSources(
+		validator.BlocklistSource{URL: server.URL + "/a"},
+		validator.BlocklistSource{URL: server.URL + "/b"},
+	)
+	_, ready := blocklist.Sources("tempmail.com")
+	assert.False(t, ready)
+	require.NoError(t, blocklist.Load())
+
+	sources, ready := blocklist.Sources("tempmail.com")
+	assert.True(t, ready)
+	assert.Equal(t, []validator.DisposableSource{
+		{Source: server.URL + "/a", Entry: "tempmail.com", Match: validator.MatchExact},
+		{Source: server.URL + "/b", Entry: "tempmail.com", Match: validator.MatchExact},
+	}, sources)
+
+	sources, _ = blocklist.Sources("inbox.throwaway.com")
+	assert.Equal(t, []validator.DisposableSource{
+		{Source: server.URL + "/a", Entry: "*.throwaway.com", Match: validator.MatchWildcard},
+	}, sources)
+
+	// Allowlisting does not hide the entries it overrides
+	blocklist.SetAllowlist([]string{"tempmail.com"})
+	sources, _ = blocklist.Sources("tempmail.com")
+	assert.Len(t, sources, 2)
+	assert.False(t, blocklist.IsDisposable("tempmail.com"))
+}
+
+func TestDisposableBlocklistSourcesFollowDiffs(t *testing.T) {
+	server := newDiffListServer(t, "tempmail.com\n")
+	blocklist := validator.NewDisposableBlocklistWithSources(server.source())
+	require.NoError(t, blocklist.Load())
+
+	server.set("", "+throwaway.com\n", 0)
+	refreshBlocklist(t, blocklist)
+	sources, _ := blocklist.Sources("throwaway.com")
+	assert.Equal(t, []validator.DisposableSource{{Source: server.URL + "/list", Entry: "throwaway.com", Match: validator.MatchExact}}, sources)
+}
+
+func TestDisposableValidatorSources(t *testing.T) {
+	v := validator.NewDisposableValidatorWithDomains([]string{"tempmail.com", "*.tempmail.com"})
+	v.SetAllowlist([]string{"*.tempmail.com", "safe.tempmail.com"})
+
+	assert.Equal(t, []validator.DisposableSource{
+		{Source: validator.DisposableSourceLocal, Entry: "*.tempmail.com", Match: validator.MatchWildcard},
+	}, v.Sources("safe.tempmail.com"))
+	assert.Equal(t, "safe.tempmail.com", v.AllowlistEntry("safe.tempmail.com"), "the most specific allowlist entry")
+	assert.Equal(t, "*.tempmail.com", v.AllowlistEntry("other.tempmail.com"))
+	assert.Empty(t, v.AllowlistEntry("tempmail.com"))
+	assert.Empty(t, v.Sources("gmail.com"))
+}
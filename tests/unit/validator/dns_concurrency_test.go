@@ -0,0 +1,85 @@
+package validatortest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gatedDNS counts DNS queries in flight; each query blocks until the gate is opened and then fails
+type gatedDNS struct {
+	gate              chan struct{}
+	active, maxActive atomic.Int32
+}
+
+func (g *gatedDNS) resolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			active := g.active.Add(1)
+			defer g.active.Add(-1)
+			for {
+				peak := g.maxActive.Load()
+				if active <= peak || g.maxActive.CompareAndSwap(peak, active) {
+					break
+				}
+			}
+			select {
+			case <-g.gate:
+			case <-ctx.Done():
+			}
+			return nil, errors.New("connection refused")
+		},
+	}
+}
+
+func TestDNSConcurrencyLimit(t *testing.T) {
+	dns := &gatedDNS{gate: make(chan struct{})}
+	resolver := validator.NewDefaultResolver(5 * time.Second)
+	resolver.SetNetResolver(dns.resolver())
+	resolver.SetConcurrencyLimit(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = resolver.LookupMX("example.test.")
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(2), dns.active.Load(), "only the first two lookups run")
+	close(dns.gate)
+	wg.Wait()
+	assert.Equal(t, int32(2), dns.maxActive.Load())
+}
+
+func TestDNSConcurrencyLimitQueueTimeout(t *testing.T) {
+	dns := &gatedDNS{gate: make(chan struct{})}
+	defer close(dns.gate)
+	resolver := validator.NewDefaultResolver(100 * time.Millisecond)
+	resolver.SetNetResolver(dns.resolver())
+	resolver.SetConcurrencyLimit(1)
+
+	// The first lookup holds the only slot until it times out; the second waits for it in vain
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := resolver.LookupMX("example.test.")
+			errs <- err
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, <-errs, validator.ErrDNSTimeout)
+	}
+	assert.Equal(t, int32(1), dns.maxActive.Load())
+}
@@ -0,0 +1,184 @@
+package validatortest
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// truncatingDNS is a DNS server that answers every MX query with mxCount records over TCP,
+// and over UDP with an empty answer marked truncated, as a server does when the records
+// do not fit in a UDP packet
+type truncatingDNS struct {
+	udp                    net.PacketConn
+	tcp                    net.Listener
+	mxCount                int
+	udpQueries, tcpQueries atomic.Int32
+}
+
+func newTruncatingDNS(t *testing.T, mxCount int) *truncatingDNS {
+	t.Helper()
+	udp, tcp := listenUDPAndTCP(t)
+	s := &truncatingDNS{udp: udp, tcp: tcp, mxCount: mxCount}
+	t.Cleanup(func() {
+		udp.Close()
+		tcp.Close()
+	})
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			s.udpQueries.Add(1)
+			if answer, err := s.answer(buf[:n], true); err == nil {
+				udp.WriteTo(answer, addr)
+			}
+		}
+	}()
+	go func() {
+		for {
+			conn, err := tcp.Accept()
+			if err != nil {
+				return
+			}
+			go s.serveTCP(conn)
+		}
+	}()
+	return s
+}
+
+// listenUDPAndTCP listens for UDP and TCP on the same loopback port. The port is chosen
+// for TCP first, and the pair retried if another process took it for UDP in the meantime.
+func listenUDPAndTCP(t *testing.T) (net.PacketConn, net.Listener) {
+	t.Helper()
+	for attempt := 0; ; attempt++ {
+		tcp, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		udp, err := net.ListenPacket("udp", tcp.Addr().String())
+		if err == nil {
+			return udp, tcp
+		}
+		tcp.Close()
+		if !errors.Is(err, syscall.EADDRINUSE) || attempt == 10 {
+			require.NoError(t, err)
+		}
+	}
+}
+
+func (s *truncatingDNS) serveTCP(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var length uint16
+		if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+			return
+		}
+		query := make([]byte, length)
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		s.tcpQueries.Add(1)
+		answer, err := s.answer(query, false)
+		if err != nil {
+			return
+		}
+		conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(answer))))
+		conn.Write(answer)
+	}
+}
+
+func (s *truncatingDNS) answer(query []byte, truncated bool) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true, Truncated: truncated})
+	builder.EnableCompression()
+	builder.StartQuestions()
+	builder.Question(question)
+	builder.StartAnswers()
+	if !truncated && question.Type == dnsmessage.TypeMX {
+		for i := 0; i < s.mxCount; i++ {
+			host := dnsmessage.MustNewName(fmt.Sprintf("mx%d.example.com.", i))
+			builder.MXResource(
+				dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60},
+				dnsmessage.MXResource{Pref: uint16(10 * (s.mxCount - i)), MX: host},
+			)
+		}
+	}
+	return builder.Finish()
+}
+
+// resolver returns a resolver whose queries all go to the server
+func (s *truncatingDNS) resolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, s.udp.LocalAddr().String())
+		},
+	}
+}
+
+func TestDNSTruncatedAnswerRetriedOverTCP(t *testing.T) {
+	dns := newTruncatingDNS(t, 3)
+	resolver := validator.NewDefaultResolver(5 * time.Second)
+	resolver.SetNetResolver(dns.resolver())
+	resolver.SetTCPFallback(true)
+
+	records, err := resolver.LookupMX("example.com")
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "mx2.example.com.", records[0].Host, "records are in order of preference")
+	assert.Positive(t, dns.udpQueries.Load())
+	assert.Positive(t, dns.tcpQueries.Load())
+}
+
+func TestDNSTruncatedAnswerWithoutTCPFallback(t *testing.T) {
+	dns := newTruncatingDNS(t, 3)
+	resolver := validator.NewDefaultResolver(5 * time.Second)
+	resolver.SetNetResolver(dns.resolver())
+	resolver.SetTCPFallback(false)
+
+	_, err := resolver.LookupMX("example.com")
+	require.Error(t, err)
+	assert.True(t, validator.IsTruncatedDNSError(err))
+	assert.True(t, validator.IsInconclusiveDNSError(err), "a truncated answer says nothing about the domain")
+	assert.Zero(t, dns.tcpQueries.Load())
+}
+
+func TestDNSMaxMXRecords(t *testing.T) {
+	dns := newTruncatingDNS(t, 20)
+	resolver := validator.NewDefaultResolver(5 * time.Second)
+	resolver.SetNetResolver(dns.resolver())
+	resolver.SetTCPFallback(true)
+	resolver.SetMaxMXRecords(5)
+
+	records, err := resolver.LookupMX("example.com")
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	for i, record := range records {
+		assert.Equal(t, uint16(10*(i+1)), record.Pref, "the most preferred records are kept")
+	}
+}
@@ -0,0 +1,115 @@
+package validatortest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRDAPServer serves an RDAP bootstrap registry for "com" and "uk" and domain records
+// with the given registration dates. Domains without a date have no registration event.
+func newRDAPServer(t *testing.T, registered map[string]time.Time, lookups *int64) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/bootstrap.json":
+			fmt.Fprintf(w, `{"services": [[["com", "uk"], ["%s/rdap/"]]]}`, server.URL)
+		case strings.HasPrefix(r.URL.Path, "/rdap/domain/"):
+			atomic.AddInt64(lookups, 1)
+			domain := strings.TrimPrefix(r.URL.Path, "/rdap/domain/")
+			date, ok := registered[domain]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			events := []map[string]string{{"eventAction": "last changed", "eventDate": time.Now().UTC().Format(time.RFC3339)}}
+			if !date.IsZero() {
+				events = append(events, map[string]string{"eventAction": "registration", "eventDate": date.UTC().Format(time.RFC3339)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ldhName": domain, "events": events})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDomainAgeChecker(t *testing.T) {
+	var lookups int64
+	server := newRDAPServer(t, map[string]time.Time{
+		"established.com": time.Now().AddDate(-5, 0, 0),
+		"fresh.com":       time.Now().Add(-3 * 24 * time.Hour),
+		"example.co.uk":   time.Now().Add(-100 * 24 * time.Hour),
+		"undated.com":     {},
+	}, &lookups)
+
+	checker := validator.NewDomainAgeChecker(30)
+	checker.SetBootstrapURL(server.URL + "/bootstrap.json")
+
+	age, err := checker.Check("established.com")
+	require.NoError(t, err)
+	assert.False(t, age.IsNew)
+	assert.InDelta(t, 5*365, age.Days, 2)
+
+	age, err = checker.Check("fresh.com")
+	require.NoError(t, err)
+	assert.True(t, age.IsNew)
+	assert.Equal(t, 3, age.Days)
+
+	// Subdomains are looked up by their registrable domain
+	age, err = checker.Check("mail.example.co.uk")
+	require.NoError(t, err)
+	assert.Equal(t, 100, age.Days)
+
+	_, err = checker.Check("undated.com")
+	assert.ErrorIs(t, err, validator.ErrNoRegistrationDate)
+
+	_, err = checker.Check("example.org")
+	assert.ErrorIs(t, err, validator.ErrNoRDAPServer)
+
+	_, err = checker.Check("missing.com")
+	assert.Error(t, err)
+}
+
+func TestDomainAgeCheckerCachesLookups(t *testing.T) {
+	var lookups int64
+	server := newRDAPServer(t, map[string]time.Time{
+		"example.com": time.Now().AddDate(-1, 0, 0),
+		"undated.com": {},
+	}, &lookups)
+
+	checker := validator.NewDomainAgeChecker(30)
+	checker.SetBootstrapURL(server.URL + "/bootstrap.json")
+
+	for i := 0; i < 3; i++ {
+		_, err := checker.Check("example.com")
+		require.NoError(t, err)
+		_, err = checker.Check("undated.com")
+		require.Error(t, err)
+	}
+	assert.Equal(t, int64(2), atomic.LoadInt64(&lookups), "lookups and definitive misses are cached")
+
+	// Transient failures are retried
+	for i := 0; i < 2; i++ {
+		_, err := checker.Check("missing.com")
+		require.Error(t, err)
+	}
+	assert.Equal(t, int64(4), atomic.LoadInt64(&lookups))
+
+	checker.SetCacheDuration(0)
+	_, err := checker.Check("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), atomic.LoadInt64(&lookups))
+}
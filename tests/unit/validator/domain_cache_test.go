@@ -0,0 +1,43 @@
+package validatortest
+
+import (
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainCacheManagerGetWithAge(t *testing.T) {
+	m := validator.NewDomainCacheManager(time.Hour)
+
+	_, _, ok := m.GetWithAge("example.com")
+	assert.False(t, ok)
+
+	m.Set("example.com", true)
+	exists, age, ok := m.GetWithAge("example.com")
+	assert.True(t, ok)
+	assert.True(t, exists)
+	assert.GreaterOrEqual(t, age, time.Duration(0))
+	assert.Less(t, age, time.Minute)
+
+	// An expired entry is a miss, with no age
+	m.SetDuration(0)
+	time.Sleep(time.Millisecond)
+	_, age, ok = m.GetWithAge("example.com")
+	assert.False(t, ok)
+	assert.Zero(t, age)
+}
+
+func TestDomainValidatorCacheAge(t *testing.T) {
+	v := validator.NewDomainValidator(NewMockResolver(), validator.NewDomainCacheManager(time.Hour))
+
+	_, cached := v.CacheAge("example.com")
+	assert.False(t, cached, "nothing is cached before the first lookup")
+
+	v.Validate("example.com")
+	age, cached := v.CacheAge("example.com.")
+	assert.True(t, cached, "the trailing dot shares the cache entry")
+	assert.Less(t, age, time.Minute)
+}
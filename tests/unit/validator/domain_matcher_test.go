@@ -0,0 +1,204 @@
+package validatortest
+
+import (
+	"testing"
+
+	"emailvalidator/pkg/validator"
+)
+
+func TestDomainMatcherMatch(t *testing.T) {
+	matcher := validator.NewDomainMatcher([]string{
+		"tempmail.com",
+		"*.example.com",
+		"*.Mail.Corp.org",
+	})
+
+	tests := []struct {
+		domain string
+		want   validator.MatchKind
+	}{
+		{"tempmail.com", validator.MatchExact},
+		{"TEMPMAIL.COM", validator.MatchExact},
+		{"sub.tempmail.com", validator.MatchNone},
+		{"example.com", validator.MatchNone},
+		{"a.example.com", validator.MatchWildcard},
+		{"a.b.c.example.com", validator.MatchWildcard},
+		{"notexample.com", validator.MatchNone},
+		{"x.mail.corp.org", validator.MatchWildcard},
+		{"corp.org", validator.MatchNone},
+		{"gmail.com", validator.MatchNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := matcher.Match(tt.domain); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+
+	if got := matcher.Len(); got != 3 {
+		t.Errorf("Len() = %d, want 3", got)
+	}
+}
+
+func TestDisposableValidatorWildcardAndAllowlist(t *testing.T) {
+	v := validator.NewDisposableValidatorWithDomains([]string{
+		"*.throwaway.io",
+		"blocked.partner.com",
+		"tempmail.com",
+	})
+	v.SetAllowlist([]string{
+		"keep.throwaway.io",
+		"*.partner.com",
+		"*.tempmail.com",
+	})
+
+	tests := []struct {
+		name   string
+		domain string
+		want   bool
+	}{
+		{"Wildcard blocks nested subdomain", "x.y.throwaway.io", true},
+		{"Exact allow beats wildcard block", "keep.throwaway.io", false},
+		{"Wildcard block covers sibling of allowed subdomain", "other.throwaway.io", true},
+		{"Exact block beats wildcard allow", "blocked.partner.com", true},
+		{"Wildcard allow on unlisted subdomain", "ok.partner.com", false},
+		{"Exact block unaffected by allow of subdomains", "tempmail.com", true},
+		{"Unlisted domain", "gmail.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.Validate(tt.domain); got != tt.want {
+				t.Errorf("Validate(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainMatcherInternationalizedDomains(t *testing.T) {
+	// Entries in either form match submissions in either form
+	matcher := validator.NewDomainMatcher([]string{
+		"xn--d-bga.net",      // dé.net
+		"雨云.com",             // xn--9kq967o.com
+		"*.xn--bcher-kva.ch", // *.bücher.ch
+	})
+
+	tests := []struct {
+		domain string
+		want   validator.MatchKind
+	}{
+		{"dé.net", validator.MatchExact},
+		{"DÉ.NET", validator.MatchExact},
+		{"xn--d-bga.net", validator.MatchExact},
+		{"XN--D-BGA.NET", validator.MatchExact},
+		{"雨云.com", validator.MatchExact},
+		{"xn--9kq967o.com", validator.MatchExact},
+		{"mail.bücher.ch", validator.MatchWildcard},
+		{"mail.xn--bcher-kva.ch", validator.MatchWildcard},
+		{"bücher.ch", validator.MatchNone},
+		{"de.net", validator.MatchNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := matcher.Match(tt.domain); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisposableValidatorBundledInternationalizedDomain(t *testing.T) {
+	// config/disposable_domains.txt lists dé.net in punycode form
+	v, err := validator.NewDisposableValidator()
+	if err != nil {
+		t.Fatalf("NewDisposableValidator() error = %v", err)
+	}
+
+	for _, domain := range []string{"xn--d-bga.net", "dé.net"} {
+		if !v.Validate(domain) {
+			t.Errorf("Validate(%q) = false, want true", domain)
+		}
+	}
+
+	v.SetAllowlist([]string{"dé.net"})
+	if v.Validate("xn--d-bga.net") {
+		t.Error("Validate(\"xn--d-bga.net\") = true with dé.net allowlisted, want false")
+	}
+}
+
+func TestDomainMatcherStrategies(t *testing.T) {
+	entries := []string{"mailinator.com", ".tempmail.com", "*.example.com"}
+
+	tests := []struct {
+		strategy validator.MatchStrategy
+		domain   string
+		want     validator.MatchKind
+	}{
+		{validator.MatchStrategyExact, "mailinator.com", validator.MatchExact},
+		{validator.MatchStrategyExact, "sub.mailinator.com", validator.MatchNone},
+		{validator.MatchStrategyExact, "sub.tempmail.com", validator.MatchNone},
+		{validator.MatchStrategyExact, "tempmail.com", validator.MatchExact},
+		{validator.MatchStrategyExact, "a.example.com", validator.MatchWildcard},
+
+		{validator.MatchStrategySuffix, "mailinator.com", validator.MatchExact},
+		{validator.MatchStrategySuffix, "sub.mailinator.com", validator.MatchWildcard},
+		{validator.MatchStrategySuffix, "a.b.sub.mailinator.com", validator.MatchWildcard},
+		{validator.MatchStrategySuffix, "notmailinator.com", validator.MatchNone},
+		{validator.MatchStrategySuffix, "sub.tempmail.com", validator.MatchWildcard},
+		{validator.MatchStrategySuffix, "example.com", validator.MatchNone},
+
+		{validator.MatchStrategyAnnotated, "mailinator.com", validator.MatchExact},
+		{validator.MatchStrategyAnnotated, "sub.mailinator.com", validator.MatchNone},
+		{validator.MatchStrategyAnnotated, "tempmail.com", validator.MatchExact},
+		{validator.MatchStrategyAnnotated, "sub.tempmail.com", validator.MatchWildcard},
+		{validator.MatchStrategyAnnotated, "a.example.com", validator.MatchWildcard},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy)+"/"+tt.domain, func(t *testing.T) {
+			matcher := validator.NewDomainMatcherWithStrategy(entries, tt.strategy)
+			if got := matcher.Match(tt.domain); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMatchStrategy(t *testing.T) {
+	for value, want := range map[string]validator.MatchStrategy{
+		"":          validator.MatchStrategyExact,
+		"exact":     validator.MatchStrategyExact,
+		"Suffix":    validator.MatchStrategySuffix,
+		"annotated": validator.MatchStrategyAnnotated,
+	} {
+		got, err := validator.ParseMatchStrategy(value)
+		if err != nil || got != want {
+			t.Errorf("ParseMatchStrategy(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := validator.ParseMatchStrategy("prefix"); err == nil {
+		t.Error("ParseMatchStrategy(\"prefix\") succeeded, want an error")
+	}
+}
+
+func TestDisposableValidatorMatchStrategy(t *testing.T) {
+	v := validator.NewDisposableValidatorWithDomains([]string{"mailinator.com"})
+	v.SetAllowlist([]string{"ok.mailinator.com"})
+
+	if v.Validate("sub.mailinator.com") {
+		t.Error("sub.mailinator.com is disposable under the default exact strategy")
+	}
+
+	if err := v.SetMatchStrategy(validator.MatchStrategySuffix); err != nil {
+		t.Fatalf("SetMatchStrategy() error = %v", err)
+	}
+	if !v.Validate("sub.mailinator.com") {
+		t.Error("sub.mailinator.com is not disposable under the suffix strategy")
+	}
+	if v.Validate("ok.mailinator.com") {
+		t.Error("an exact allowlist entry should beat a suffix match")
+	}
+}
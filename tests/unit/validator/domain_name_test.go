@@ -0,0 +1,42 @@
+package validatortest
+
+import (
+	"strings"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDomainName(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		wantErr bool
+	}{
+		{name: "Domain", domain: "example.com"},
+		{name: "Trailing dot", domain: "example.com."},
+		{name: "Mixed case", domain: "Mail-01.Example.COM"},
+		{name: "Internationalized", domain: "bücher.de"},
+		{name: "Empty", domain: "", wantErr: true},
+		{name: "Single label", domain: "localhost", wantErr: true},
+		{name: "Address", domain: "user@example.com", wantErr: true},
+		{name: "IP literal", domain: "[192.0.2.1]", wantErr: true},
+		{name: "Space", domain: "exa mple.com", wantErr: true},
+		{name: "Leading hyphen", domain: "-example.com", wantErr: true},
+		{name: "Empty label", domain: "example..com", wantErr: true},
+		{name: "Label too long", domain: strings.Repeat("a", 64) + ".com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateDomainName(tt.domain)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package validatortest
+
+import (
+	"net"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// intranetResolver resolves "intranet" as an internal mail host; no other name exists
+type intranetResolver struct{}
+
+func (intranetResolver) LookupHost(domain string) ([]string, error) {
+	if domain == "intranet" {
+		return []string{"10.0.0.25"}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+func (intranetResolver) LookupMX(domain string) ([]*net.MX, error) {
+	if domain == "intranet" {
+		return []*net.MX{{Host: "mail.intranet.", Pref: 10}}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+func TestDotlessDomains(t *testing.T) {
+	tests := []struct {
+		name       string
+		allow      bool
+		domain     string
+		wantKnown  bool
+		wantExists bool
+		wantMX     bool
+		wantNameOK bool
+	}{
+		{name: "Disabled", allow: false, domain: "intranet"},
+		{name: "Disabled, fully qualified", allow: false, domain: "intranet."},
+		{name: "Enabled", allow: true, domain: "intranet", wantKnown: true, wantExists: true, wantMX: true, wantNameOK: true},
+		{name: "Enabled, fully qualified", allow: true, domain: "intranet.", wantKnown: true, wantExists: true, wantMX: true, wantNameOK: true},
+		{name: "Enabled, missing host", allow: true, domain: "nosuchhost", wantKnown: true, wantNameOK: true},
+		{name: "Enabled, unknown TLD still rejected", allow: true, domain: "example.notatld", wantNameOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := validator.NewEmailValidatorWithResolver(intranetResolver{})
+			require.NoError(t, err)
+			v.SetAllowDotlessDomains(tt.allow)
+
+			assert.Equal(t, tt.wantKnown, v.HasKnownTLD(tt.domain))
+			assert.Equal(t, tt.wantExists, v.ValidateDomain(tt.domain))
+			assert.Equal(t, tt.wantMX, v.ValidateMXRecords(tt.domain))
+			if tt.wantNameOK {
+				assert.NoError(t, v.ValidateDomainName(tt.domain))
+			} else {
+				assert.Error(t, v.ValidateDomainName(tt.domain))
+			}
+		})
+	}
+}
+
+func TestDotlessDomainsSurviveResolverChange(t *testing.T) {
+	v, err := validator.NewEmailValidatorWithResolver(&MockResolver{})
+	require.NoError(t, err)
+	v.SetAllowDotlessDomains(true)
+	v.SetResolver(intranetResolver{})
+
+	assert.True(t, v.HasKnownTLD("intranet"))
+	assert.True(t, v.ValidateDomain("intranet"))
+}
+
+func TestValidateDomainNameRejectsDotless(t *testing.T) {
+	// The package-level check keeps requiring a fully qualified name
+	assert.Error(t, validator.ValidateDomainName("intranet"))
+	assert.NoError(t, validator.ValidateDomainName("example.com"))
+}
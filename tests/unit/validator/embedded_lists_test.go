@@ -0,0 +1,65 @@
+package validatortest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chdir changes the working directory to dir for the rest of the test
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+func TestEmbeddedDomainReader(t *testing.T) {
+	domains, err := validator.NewEmbeddedDomainReader(validator.EmbeddedDisposableList).ReadDomains()
+	require.NoError(t, err)
+	assert.Contains(t, domains, "mailinator.com")
+	for _, domain := range domains {
+		assert.NotContains(t, domain, "#")
+	}
+
+	tlds, err := validator.NewEmbeddedDomainReader(validator.EmbeddedTLDList).ReadDomains()
+	require.NoError(t, err)
+	assert.Contains(t, tlds, "COM")
+
+	_, err = validator.NewEmbeddedDomainReader("missing.txt").ReadDomains()
+	assert.Error(t, err)
+}
+
+func TestDefaultListReaderWithoutConfigDirectory(t *testing.T) {
+	chdir(t, t.TempDir())
+
+	reader := validator.DefaultListReader(validator.EmbeddedDisposableList)
+	assert.IsType(t, &validator.EmbeddedDomainReader{}, reader)
+
+	// A binary run away from the source tree still has working default lists
+	v, err := validator.NewEmailValidator()
+	require.NoError(t, err)
+	assert.True(t, v.IsDisposable("mailinator.com"))
+	assert.False(t, v.HasKnownTLD("example.qwerty"))
+	assert.True(t, v.HasKnownTLD("example.com"))
+}
+
+func TestDefaultListReaderPrefersConfigDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", validator.EmbeddedDisposableList), []byte("# local copy\nlocal-disposable.example\n"), 0o644))
+	chdir(t, dir)
+
+	domains, err := validator.DefaultListReader(validator.EmbeddedDisposableList).ReadDomains()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"local-disposable.example"}, domains)
+
+	// Lists missing from the config directory still come from the binary
+	assert.IsType(t, &validator.EmbeddedDomainReader{}, validator.DefaultListReader(validator.EmbeddedTLDList))
+}
@@ -0,0 +1,110 @@
+package validatortest
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+	"emailvalidator/tests/unit/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHELOName(t *testing.T) {
+	tests := []struct {
+		name    string
+		helo    string
+		wantErr bool
+	}{
+		{name: "FQDN", helo: "verifier.example.com"},
+		{name: "FQDN with trailing dot", helo: "verifier.example.com."},
+		{name: "Hyphenated labels", helo: "mail-01.example-corp.net"},
+		{name: "Empty", helo: "", wantErr: true},
+		{name: "Bare hostname", helo: "verifier", wantErr: true},
+		{name: "Localhost", helo: "localhost", wantErr: true},
+		{name: "Local domain", helo: "box.localdomain", wantErr: true},
+		{name: "mDNS name", helo: "laptop.local", wantErr: true},
+		{name: "IPv4 address", helo: "192.0.2.10", wantErr: true},
+		{name: "IPv6 address literal", helo: "[2001:db8::1]", wantErr: true},
+		{name: "Underscore", helo: "mail_server.example.com", wantErr: true},
+		{name: "Leading hyphen", helo: "-mail.example.com", wantErr: true},
+		{name: "Empty label", helo: "mail..example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateHELOName(tt.helo)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// heloResolver resolves names from a fixed table
+type heloResolver struct {
+	hosts map[string][]string
+}
+
+func (r *heloResolver) LookupHost(domain string) ([]string, error) {
+	if addrs, ok := r.hosts[domain]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+func (r *heloResolver) LookupMX(domain string) ([]*net.MX, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestPreflightHELO(t *testing.T) {
+	resolver := &heloResolver{hosts: map[string][]string{
+		"verifier.example.com": {"2001:db8::25", "192.0.2.25"},
+		"other.example.com":    {"192.0.2.99"},
+	}}
+	sendingIP := net.ParseIP("192.0.2.25")
+
+	tests := []struct {
+		name      string
+		helo      string
+		sendingIP net.IP
+		wantErr   string
+	}{
+		{name: "Resolves to the sending IP", helo: "verifier.example.com", sendingIP: sendingIP},
+		{name: "Trailing dot", helo: "verifier.example.com.", sendingIP: sendingIP},
+		{name: "Unknown sending IP skips the address check", helo: "other.example.com"},
+		{name: "Resolves elsewhere", helo: "other.example.com", sendingIP: sendingIP, wantErr: "not to the sending IP 192.0.2.25"},
+		{name: "Does not resolve", helo: "missing.example.com", sendingIP: sendingIP, wantErr: "does not resolve"},
+		{name: "Not an FQDN", helo: "verifier", sendingIP: sendingIP, wantErr: "not a fully qualified domain name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.PreflightHELO(tt.helo, resolver, tt.sendingIP)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSMTPValidatorDefaultHELOName(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skipf("host name unavailable: %v", err)
+	}
+
+	v := validator.NewSMTPValidator(&testutil.LoopbackMXResolver{}, "", validator.NullSender)
+	assert.Equal(t, hostname, v.HELOName())
+
+	v = validator.NewSMTPValidator(&testutil.LoopbackMXResolver{}, "verifier.example.com", validator.NullSender)
+	assert.Equal(t, "verifier.example.com", v.HELOName())
+}
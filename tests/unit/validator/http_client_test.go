@@ -0,0 +1,165 @@
+package validatortest
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockProxy starts an HTTP proxy that answers every request itself with body and
+// records the absolute URLs it was asked for
+func newMockProxy(t *testing.T, body string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.String())
+		mu.Unlock()
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	proxy, requested := newMockProxy(t, "tempmail.example\n")
+
+	client, err := validator.NewHTTPClient(validator.HTTPClientConfig{ProxyURL: proxy.URL})
+	require.NoError(t, err)
+	assert.Equal(t, validator.DefaultHTTPTimeout, client.Timeout)
+
+	blocklist := validator.NewDisposableBlocklistWithClient(client, validator.BlocklistSource{URL: "http://lists.invalid/disposable.txt"})
+	require.NoError(t, blocklist.Load())
+
+	assert.True(t, blocklist.IsDisposable("tempmail.example"))
+	assert.Equal(t, []string{"http://lists.invalid/disposable.txt"}, requested())
+}
+
+func TestNewHTTPClientTimeout(t *testing.T) {
+	client, err := validator.NewHTTPClient(validator.HTTPClientConfig{Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, client.Timeout)
+}
+
+func TestNewHTTPClientInvalidProxy(t *testing.T) {
+	for _, proxyURL := range []string{"ftp://proxy.example:21", "http://", "://proxy"} {
+		t.Run(proxyURL, func(t *testing.T) {
+			_, err := validator.NewHTTPClient(validator.HTTPClientConfig{ProxyURL: proxyURL})
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := validator.NewHTTPClient(validator.HTTPClientConfig{ProxyURL: "socks5://127.0.0.1:1080"})
+	assert.NoError(t, err)
+}
+
+func TestNewHTTPClientCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	untrusted, err := validator.NewHTTPClient(validator.HTTPClientConfig{})
+	require.NoError(t, err)
+	_, err = untrusted.Get(server.URL)
+	assert.Error(t, err, "the test server's certificate is not signed by a system root")
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(bundle, certPEM, 0o600))
+
+	trusted, err := validator.NewHTTPClient(validator.HTTPClientConfig{CABundle: bundle})
+	require.NoError(t, err)
+	resp, err := trusted.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewHTTPClientInvalidCABundle(t *testing.T) {
+	_, err := validator.NewHTTPClient(validator.HTTPClientConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("not a certificate"), 0o600))
+	_, err = validator.NewHTTPClient(validator.HTTPClientConfig{CABundle: empty})
+	assert.Error(t, err)
+}
+
+func TestNewHTTPClientConnectionPool(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client, err := validator.NewHTTPClient(validator.HTTPClientConfig{})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	assert.Equal(t, int32(1), connections.Load(), "requests should reuse the pooled connection")
+}
+
+func TestNewHTTPClientUserAgent(t *testing.T) {
+	var mu sync.Mutex
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents = append(userAgents, r.UserAgent())
+		mu.Unlock()
+		fmt.Fprintln(w, "tempmail.example")
+	}))
+	defer server.Close()
+
+	client, err := validator.NewHTTPClient(validator.HTTPClientConfig{})
+	require.NoError(t, err)
+	require.NoError(t, validator.NewDisposableBlocklistWithClient(client, validator.BlocklistSource{URL: server.URL}).Load())
+
+	custom, err := validator.NewHTTPClient(validator.HTTPClientConfig{UserAgent: "acme-signup/2.0"})
+	require.NoError(t, err)
+	require.NoError(t, validator.NewDisposableBlocklistWithClient(custom, validator.BlocklistSource{URL: server.URL}).Load())
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "explicit/1.0")
+	resp, err := custom.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "explicit/1.0", req.Header.Get("User-Agent"), "the caller's request must not be modified")
+
+	assert.Equal(t, []string{validator.DefaultUserAgent(), "acme-signup/2.0", "explicit/1.0"}, userAgents)
+	assert.Regexp(t, `^email-verifier/\S+ \(\+https://github\.com/SaiAkashNeela/email-verifier\)$`, validator.DefaultUserAgent())
+}
+
+func TestDefaultHTTPClientIsShared(t *testing.T) {
+	assert.Same(t, validator.DefaultHTTPClient(), validator.DefaultHTTPClient())
+}
@@ -0,0 +1,118 @@
+package validatortest
+
+import (
+	"strings"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		parser  validator.ListParser
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "Plaintext with hash comments",
+			parser: validator.PlaintextParser{},
+			input:  "# header\ntempmail.com\n\n  mailinator.com  \n",
+			want:   []string{"tempmail.com", "mailinator.com"},
+		},
+		{
+			name:   "Plaintext with custom comment prefixes",
+			parser: validator.PlaintextParser{CommentPrefixes: []string{"//", ";"}},
+			input:  "// generated\n; note\ntempmail.com\n",
+			want:   []string{"tempmail.com"},
+		},
+		{
+			name:   "JSON array of strings",
+			parser: validator.JSONParser{},
+			input:  `["tempmail.com", " ", "mailinator.com"]`,
+			want:   []string{"tempmail.com", "mailinator.com"},
+		},
+		{
+			name:   "JSON array of objects",
+			parser: validator.JSONParser{Field: "domain"},
+			input:  `[{"domain": "tempmail.com", "added": "2024-01-01"}, {"name": "ignored"}, {"domain": "mailinator.com"}]`,
+			want:   []string{"tempmail.com", "mailinator.com"},
+		},
+		{
+			name:    "Invalid JSON",
+			parser:  validator.JSONParser{},
+			input:   `{"domains": []}`,
+			wantErr: true,
+		},
+		{
+			name:   "CSV first column",
+			parser: validator.CSVParser{},
+			input:  "tempmail.com,temporary\n# comment\nmailinator.com\n",
+			want:   []string{"tempmail.com", "mailinator.com"},
+		},
+		{
+			name:   "CSV with header and column",
+			parser: validator.CSVParser{Column: 1, HasHeader: true},
+			input:  "id,domain\n1,tempmail.com\n2, mailinator.com\n3\n",
+			want:   []string{"tempmail.com", "mailinator.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parser.Parse(strings.NewReader(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParserByName(t *testing.T) {
+	for _, name := range []string{"", "plaintext", "json", "csv", "JSON"} {
+		parser, err := validator.ParserByName(name)
+		assert.NoError(t, err, name)
+		assert.NotNil(t, parser, name)
+	}
+
+	_, err := validator.ParserByName("yaml")
+	assert.Error(t, err)
+}
+
+func TestParseBlocklistSources(t *testing.T) {
+	sources, err := validator.ParseBlocklistSources("https://a.test/list.txt, json=https://b.test/list.json?x=1,csv=https://c.test/list.csv")
+	assert.NoError(t, err)
+	if assert.Len(t, sources, 3) {
+		assert.Equal(t, "https://a.test/list.txt", sources[0].URL)
+		assert.IsType(t, validator.PlaintextParser{}, sources[0].Parser)
+		assert.Equal(t, "https://b.test/list.json?x=1", sources[1].URL)
+		assert.IsType(t, validator.JSONParser{}, sources[1].Parser)
+		assert.Equal(t, "https://c.test/list.csv", sources[2].URL)
+		assert.IsType(t, validator.CSVParser{}, sources[2].Parser)
+	}
+
+	sources, err = validator.ParseBlocklistSources("")
+	assert.NoError(t, err)
+	assert.Empty(t, sources)
+
+	_, err = validator.ParseBlocklistSources("yaml=https://a.test/list.yaml")
+	assert.Error(t, err)
+
+	sources, err = validator.ParseBlocklistSources("json=https://a.test/list.json;diff=https://a.test/list.diff, https://b.test/list.txt")
+	assert.NoError(t, err)
+	if assert.Len(t, sources, 2) {
+		assert.Equal(t, "https://a.test/list.json", sources[0].URL)
+		assert.IsType(t, validator.JSONParser{}, sources[0].Parser)
+		assert.Equal(t, "https://a.test/list.diff", sources[0].DiffURL)
+		assert.Empty(t, sources[1].DiffURL)
+	}
+
+	_, err = validator.ParseBlocklistSources("https://a.test/list.txt;diff=")
+	assert.Error(t, err)
+}
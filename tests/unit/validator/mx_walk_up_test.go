@@ -0,0 +1,128 @@
+package validatortest
+
+import (
+	"net"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zoneResolver answers lookups from a fixed zone; names missing from it do not exist.
+// Like the Go resolver, it reports a name without MX records as not found.
+type zoneResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	errs    map[string]error
+	queried []string
+}
+
+func (r *zoneResolver) LookupHost(domain string) ([]string, error) {
+	if addrs, ok := r.hosts[domain]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+func (r *zoneResolver) LookupMX(domain string) ([]*net.MX, error) {
+	r.queried = append(r.queried, domain)
+	if err := r.errs[domain]; err != nil {
+		return nil, err
+	}
+	if records, ok := r.mx[domain]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+}
+
+func newZoneResolver() *zoneResolver {
+	return &zoneResolver{
+		mx: map[string][]*net.MX{
+			"example.com":      {{Host: "mx.example.com.", Pref: 10}},
+			"null.example.com": {{Host: ".", Pref: 0}},
+			"co.uk":            {{Host: "mx.co.uk.", Pref: 10}},
+		},
+		hosts: map[string][]string{
+			"eu.example.com":       {"192.0.2.1"},
+			"mail.eu.example.com":  {"192.0.2.2"},
+			"sub.null.example.com": {"192.0.2.3"},
+			"a.b.missing.com":      {"192.0.2.4"},
+			"b.missing.com":        {"192.0.2.5"},
+			"missing.com":          {"192.0.2.6"},
+			"shop.acme.co.uk":      {"192.0.2.7"},
+			"acme.co.uk":           {"192.0.2.8"},
+		},
+		errs: map[string]error{
+			"slow.example.com": &net.DNSError{Err: "i/o timeout", Name: "slow.example.com", IsTimeout: true},
+		},
+	}
+}
+
+func TestLookupMXWalkUp(t *testing.T) {
+	tests := []struct {
+		name         string
+		domain       string
+		levels       int
+		wantHosts    int
+		wantMXDomain string
+		wantErr      bool
+	}{
+		{name: "Own records", domain: "example.com", levels: 2, wantHosts: 1},
+		{name: "Parent records", domain: "eu.example.com", levels: 1, wantHosts: 1, wantMXDomain: "example.com"},
+		{name: "Grandparent records", domain: "mail.eu.example.com", levels: 2, wantHosts: 1, wantMXDomain: "example.com"},
+		{name: "Beyond the bound", domain: "mail.eu.example.com", levels: 1, wantErr: true},
+		{name: "Disabled", domain: "eu.example.com", levels: 0, wantErr: true},
+		{name: "Inconclusive lookup stops the walk", domain: "slow.example.com", levels: 2, wantErr: true},
+		{name: "Made-up subdomain is not routed", domain: "random.example.com", levels: 2, wantErr: true},
+		{name: "Parent null MX is not the subdomain's", domain: "sub.null.example.com", levels: 1, wantErr: true},
+		{name: "Public suffix is not a parent", domain: "shop.acme.co.uk", levels: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, mxDomain, err := validator.LookupMXWalkUp(newZoneResolver(), tt.domain, tt.levels)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Len(t, records, tt.wantHosts)
+			assert.Equal(t, tt.wantMXDomain, mxDomain)
+		})
+	}
+}
+
+func TestLookupMXWalkUpStopsBelowTLD(t *testing.T) {
+	resolver := newZoneResolver()
+	_, _, err := validator.LookupMXWalkUp(resolver, "a.b.missing.com", 10)
+	require.Error(t, err)
+	assert.Equal(t, []string{"a.b.missing.com", "b.missing.com", "missing.com"}, resolver.queried)
+
+	resolver = newZoneResolver()
+	_, _, err = validator.LookupMXWalkUp(resolver, "shop.acme.co.uk", 10)
+	require.Error(t, err)
+	assert.Equal(t, []string{"shop.acme.co.uk", "acme.co.uk"}, resolver.queried, "co.uk is a public suffix")
+}
+
+func TestCheckMXWalkUp(t *testing.T) {
+	v := validator.NewDomainValidator(newZoneResolver(), validator.NewDomainCacheManager(0))
+
+	check := v.CheckMX("eu.example.com")
+	assert.False(t, check.HasMX, "walk-up is opt-in")
+
+	v.SetMXWalkUp(1)
+	check = v.CheckMX("eu.example.com")
+	assert.True(t, check.HasMX)
+	assert.Equal(t, "example.com", check.MXDomain)
+
+	check = v.CheckMX("random.example.com")
+	assert.False(t, check.HasMX, "a subdomain that does not exist has no mail servers")
+	assert.Empty(t, check.MXDomain)
+
+	check = v.CheckMX("sub.null.example.com")
+	assert.False(t, check.HasMX)
+	assert.False(t, check.NullMX, "a parent's null MX is not the subdomain's")
+
+	// The subdomain's own null MX is final
+	check = v.CheckMX("null.example.com")
+	assert.True(t, check.NullMX)
+	assert.False(t, check.HasMX)
+	assert.Empty(t, check.MXDomain)
+}
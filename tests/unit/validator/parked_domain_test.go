@@ -0,0 +1,111 @@
+package validatortest
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+	"emailvalidator/tests/unit/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nsResolver answers every domain with fixed nameservers, or fails NS lookups with err
+type nsResolver struct {
+	nameservers []string
+	err         error
+	lookups     int
+}
+
+func (r *nsResolver) LookupHost(domain string) ([]string, error) {
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *nsResolver) LookupMX(domain string) ([]*net.MX, error) {
+	return []*net.MX{{Host: "mail." + domain + ".", Pref: 10}}, nil
+}
+
+func (r *nsResolver) LookupNS(domain string) ([]*net.NS, error) {
+	r.lookups++
+	if r.err != nil {
+		return nil, r.err
+	}
+	records := make([]*net.NS, 0, len(r.nameservers))
+	for _, host := range r.nameservers {
+		records = append(records, &net.NS{Host: host})
+	}
+	return records, nil
+}
+
+func TestParkedDomainDetector(t *testing.T) {
+	detector := validator.NewParkedDomainDetector()
+	assert.True(t, detector.IsParkingNameserver("ns1.sedoparking.com."))
+	assert.True(t, detector.IsParkingNameserver("NS2.PARKINGCREW.NET"))
+	assert.False(t, detector.IsParkingNameserver("sedoparking.com"))
+	assert.False(t, detector.IsParkingNameserver("ns1.example.com."))
+}
+
+func TestParkedDomainDetectorFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parking.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# parking services\nns1.parked.test\n*.parking.test\n"), 0o644))
+
+	detector, err := validator.NewParkedDomainDetectorFromFile(path)
+	require.NoError(t, err)
+	assert.True(t, detector.IsParkingNameserver("ns1.parked.test."))
+	assert.False(t, detector.IsParkingNameserver("ns2.parked.test."))
+	assert.True(t, detector.IsParkingNameserver("a.ns.parking.test"))
+	assert.False(t, detector.IsParkingNameserver("ns1.sedoparking.com"))
+
+	// An invalid list is rejected on reload and the current one is kept
+	require.NoError(t, os.WriteFile(path, []byte("not a domain!\n"), 0o644))
+	assert.Error(t, detector.Reload())
+	assert.True(t, detector.IsParkingNameserver("ns1.parked.test."))
+
+	_, err = validator.NewParkedDomainDetectorFromFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestEmailValidatorIsParked(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver *nsResolver
+		domain   string
+		want     bool
+	}{
+		{name: "Parking nameservers", resolver: &nsResolver{nameservers: []string{"ns1.bodis.com.", "ns2.bodis.com."}}, domain: "expired.com", want: true},
+		{name: "Trailing dot", resolver: &nsResolver{nameservers: []string{"ns1.bodis.com."}}, domain: "expired.com.", want: true},
+		{name: "Regular nameservers", resolver: &nsResolver{nameservers: []string{"ns1.example.net."}}, domain: "example.com"},
+		{name: "Lookup failure", resolver: &nsResolver{err: errors.New("no such host")}, domain: "example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := validator.NewEmailValidatorWithResolver(tt.resolver)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v.IsParked(tt.domain))
+		})
+	}
+}
+
+func TestEmailValidatorIsParkedSkipsLookups(t *testing.T) {
+	resolver := &nsResolver{nameservers: []string{"ns1.bodis.com."}}
+	v, err := validator.NewEmailValidatorWithResolver(resolver)
+	require.NoError(t, err)
+
+	// No lookup for a TLD that does not exist
+	assert.False(t, v.IsParked("expired.qwerty"))
+	assert.Zero(t, resolver.lookups)
+
+	// Nor with detection disabled
+	v.SetParkedDomainDetector(nil)
+	assert.False(t, v.IsParked("expired.com"))
+	assert.Zero(t, resolver.lookups)
+
+	// Resolvers without NS lookups never report a parked domain
+	plain, err := validator.NewEmailValidatorWithResolver(&testutil.LoopbackMXResolver{})
+	require.NoError(t, err)
+	assert.False(t, plain.IsParked("expired.com"))
+}
@@ -0,0 +1,136 @@
+package validatortest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestRoleValidatorReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roles.txt")
+	writeFile(t, path, "# roles\nadmin\nsupport\n")
+
+	v, err := validator.NewRoleValidatorFromFile(path)
+	require.NoError(t, err)
+	assert.True(t, v.Validate("admin@example.com"))
+	assert.False(t, v.Validate("noreply@example.com"))
+
+	writeFile(t, path, "admin\nNoReply\n")
+	require.NoError(t, v.Reload())
+	assert.True(t, v.Validate("noreply@example.com"))
+	assert.False(t, v.Validate("support@example.com"))
+}
+
+func TestRoleValidatorReloadKeepsListOnInvalidContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roles.txt")
+	writeFile(t, path, "admin\n")
+
+	v, err := validator.NewRoleValidatorFromFile(path)
+	require.NoError(t, err)
+
+	for name, content := range map[string]string{
+		"empty":          "",
+		"only comments":  "# truncated\n",
+		"address entry":  "admin\nbilling@example.com\n",
+		"csv formatting": "admin,1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			writeFile(t, path, content)
+			assert.Error(t, v.Reload())
+			assert.True(t, v.Validate("admin@example.com"), "previous list must stay in effect")
+		})
+	}
+
+	require.NoError(t, os.Remove(path))
+	assert.Error(t, v.Reload())
+	assert.True(t, v.Validate("admin@example.com"))
+}
+
+func TestDisposableValidatorReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disposable.txt")
+	writeFile(t, path, "tempmail.com\n")
+
+	v, err := validator.NewDisposableValidatorFromFile(path)
+	require.NoError(t, err)
+	assert.True(t, v.Validate("tempmail.com"))
+
+	writeFile(t, path, "tempmail.com\n*.throwaway.test\n")
+	require.NoError(t, v.Reload())
+	assert.True(t, v.Validate("a.throwaway.test"))
+
+	writeFile(t, path, "tempmail.com\nthis is not a domain\n")
+	assert.Error(t, v.Reload())
+	assert.True(t, v.Validate("a.throwaway.test"), "previous list must stay in effect")
+
+	writeFile(t, path, "")
+	assert.Error(t, v.Reload())
+	assert.True(t, v.Validate("tempmail.com"))
+}
+
+func TestEmailValidatorReloadContinuesPastFailures(t *testing.T) {
+	dir := t.TempDir()
+	rolesPath := filepath.Join(dir, "roles.txt")
+	disposablePath := filepath.Join(dir, "disposable.txt")
+	writeFile(t, rolesPath, "admin\n")
+	writeFile(t, disposablePath, "tempmail.com\n")
+
+	roles, err := validator.NewRoleValidatorFromFile(rolesPath)
+	require.NoError(t, err)
+	disposable, err := validator.NewDisposableValidatorFromFile(disposablePath)
+	require.NoError(t, err)
+	v, err := validator.NewEmailValidator()
+	require.NoError(t, err)
+	v.SetRoleValidator(roles)
+	v.SetDisposableValidator(disposable)
+
+	// The role list fails to reload, but the disposable list still picks up its change
+	writeFile(t, rolesPath, "")
+	writeFile(t, disposablePath, "tempmail.com\nthrowaway.test\n")
+	assert.Error(t, v.Reload())
+	assert.True(t, v.IsRoleBased("admin@example.com"), "previous role list must stay in effect")
+	assert.True(t, v.IsDisposable("throwaway.test"))
+}
+
+func TestWatchFileReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "roles.txt")
+	writeFile(t, path, "admin\n")
+
+	v, err := validator.NewRoleValidatorFromFile(path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, validator.WatchFile(ctx, path, v.Reload))
+
+	// In-place write
+	writeFile(t, path, "admin\nnoreply\n")
+	assert.Eventually(t, func() bool {
+		return v.Validate("noreply@example.com")
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// Atomic replace via rename
+	tmp := filepath.Join(dir, "roles.txt.tmp")
+	writeFile(t, tmp, "postmaster\n")
+	require.NoError(t, os.Rename(tmp, path))
+	assert.Eventually(t, func() bool {
+		return v.Validate("postmaster@example.com") && !v.Validate("admin@example.com")
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// A bad write is rejected and the last good list stays in effect
+	writeFile(t, path, "")
+	time.Sleep(600 * time.Millisecond)
+	assert.True(t, v.Validate("postmaster@example.com"))
+}
@@ -0,0 +1,130 @@
+package validatortest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteListReaderMergesSources(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/roles.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "# roles")
+		fmt.Fprintln(w, "admin")
+	})
+	mux.HandleFunc("/roles.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `["noreply"]`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	reader := validator.NewRemoteListReader(server.Client(), "",
+		validator.BlocklistSource{URL: server.URL + "/roles.txt"},
+		validator.BlocklistSource{URL: server.URL + "/roles.json", Parser: validator.JSONParser{}},
+	)
+	entries, err := reader.ReadDomains()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "noreply"}, entries)
+}
+
+func TestRemoteListReaderFallsBackToLocalFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	source := validator.BlocklistSource{URL: server.URL}
+
+	path := filepath.Join(t.TempDir(), "roles.txt")
+	writeFile(t, path, "# local copy\nsupport\n")
+
+	entries, err := validator.NewRemoteListReader(server.Client(), path, source).ReadDomains()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"support"}, entries)
+
+	_, err = validator.NewRemoteListReader(server.Client(), "", source).ReadDomains()
+	assert.Error(t, err, "without a fallback the fetch error is returned")
+
+	_, err = validator.NewRemoteListReader(server.Client(), filepath.Join(t.TempDir(), "missing.txt"), source).ReadDomains()
+	assert.Error(t, err, "a missing fallback file is an error")
+}
+
+func TestRemoteListReaderFallsBackOnlyOnFirstRead(t *testing.T) {
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "admin")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "roles.txt")
+	writeFile(t, path, "support\n")
+	v, err := validator.NewRoleValidatorWithReader(
+		validator.NewRemoteListReader(server.Client(), path, validator.BlocklistSource{URL: server.URL}))
+	require.NoError(t, err)
+	assert.True(t, v.Validate("admin@example.com"))
+
+	failing.Store(true)
+	assert.Error(t, v.Reload(), "a failed refresh is reported, not replaced by the fallback")
+	assert.True(t, v.Validate("admin@example.com"), "the fetched list stays in effect")
+	assert.False(t, v.Validate("support@example.com"))
+}
+
+func TestRoleValidatorRefreshesFromRemoteList(t *testing.T) {
+	var role atomic.Value
+	role.Store("admin")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, role.Load())
+	}))
+	defer server.Close()
+
+	v, err := validator.NewRoleValidatorWithReader(
+		validator.NewRemoteListReader(server.Client(), "", validator.BlocklistSource{URL: server.URL}))
+	require.NoError(t, err)
+	assert.True(t, v.Validate("admin@example.com"))
+
+	role.Store("noreply")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	validator.StartAutoReload(ctx, v, "role list", 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return v.Validate("noreply@example.com")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, v.Validate("admin@example.com"))
+}
+
+func TestFreeProviderValidator(t *testing.T) {
+	v := validator.NewFreeProviderValidator()
+	assert.True(t, v.Validate("jane@gmail.com"))
+	assert.True(t, v.Validate("jane@GMAIL.com."))
+	assert.False(t, v.Validate("jane@example.com"))
+	assert.False(t, v.Validate("not-an-email"))
+}
+
+func TestFreeProviderValidatorReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "free.txt")
+	writeFile(t, path, "# providers\nexample-mail.com\n*.webmail.test\n")
+
+	v, err := validator.NewFreeProviderValidatorFromFile(path)
+	require.NoError(t, err)
+	assert.True(t, v.Validate("jane@example-mail.com"))
+	assert.True(t, v.Validate("jane@eu.webmail.test"))
+	assert.False(t, v.Validate("jane@gmail.com"), "a configured list replaces the built-in one")
+
+	writeFile(t, path, "example-mail.com\nnot a domain\n")
+	assert.Error(t, v.Reload())
+	assert.True(t, v.Validate("jane@eu.webmail.test"), "previous list must stay in effect")
+}
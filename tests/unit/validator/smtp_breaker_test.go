@@ -0,0 +1,134 @@
+package validatortest
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// switchableDialer fails every connection with a timeout while down is set and otherwise
+// connects normally, counting the attempts
+type switchableDialer struct {
+	down     atomic.Bool
+	attempts atomic.Int64
+}
+
+func (d *switchableDialer) dial(network, address string, timeout time.Duration) (net.Conn, error) {
+	d.attempts.Add(1)
+	if d.down.Load() {
+		return nil, errors.New("dial tcp " + address + ": i/o timeout")
+	}
+	return net.DialTimeout(network, address, timeout)
+}
+
+func newBreakerTestValidator(server *mockSMTPServer, dialer *switchableDialer, threshold int, cooldown time.Duration) *validator.SMTPValidator {
+	v := newTestSMTPValidator(server, "bounce@verified.test")
+	v.SetDialFunc(dialer.dial)
+	v.SetCircuitBreaker(threshold, cooldown)
+	return v
+}
+
+func TestSMTPCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	dialer := &switchableDialer{}
+	dialer.down.Store(true)
+	v := newBreakerTestValidator(server, dialer, 3, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, validator.SMTPStatusAllMXUnreachable, v.Verify("user@tarpit.test").Status)
+	}
+
+	result := v.Verify("other@tarpit.test")
+	assert.Equal(t, validator.SMTPStatusCircuitOpen, result.Status)
+	assert.Equal(t, "bounce@verified.test", result.Sender)
+	assert.Contains(t, result.Message, "circuit open")
+	assert.Equal(t, int64(3), dialer.attempts.Load())
+
+	// Domain names are matched regardless of case and trailing dot
+	assert.Equal(t, validator.SMTPStatusCircuitOpen, v.Verify("user@TARPIT.test.").Status)
+
+	// Other domains are still probed
+	dialer.down.Store(false)
+	assert.Equal(t, validator.SMTPStatusDeliverable, v.Verify("user@healthy.test").Status)
+}
+
+func TestSMTPCircuitBreakerRepliesAreNotFailures(t *testing.T) {
+	server := newMockSMTPServer(t, func(command string) string {
+		if strings.HasPrefix(strings.ToUpper(command), "RCPT TO:") {
+			return "450 4.2.0 Greylisted, please try again later"
+		}
+		return ""
+	})
+	v := newBreakerTestValidator(server, &switchableDialer{}, 1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, validator.SMTPStatusGreylisted, v.Verify("user@example.com").Status)
+	}
+}
+
+func TestSMTPCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	dialer := &switchableDialer{}
+	v := newBreakerTestValidator(server, dialer, 2, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		dialer.down.Store(true)
+		assert.Equal(t, validator.SMTPStatusAllMXUnreachable, v.Verify("user@flaky.test").Status)
+		dialer.down.Store(false)
+		assert.Equal(t, validator.SMTPStatusDeliverable, v.Verify("user@flaky.test").Status)
+	}
+}
+
+func TestSMTPCircuitBreakerRecovers(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	dialer := &switchableDialer{}
+	dialer.down.Store(true)
+	v := newBreakerTestValidator(server, dialer, 1, 20*time.Millisecond)
+
+	assert.Equal(t, validator.SMTPStatusAllMXUnreachable, v.Verify("user@slow.test").Status)
+	assert.Equal(t, validator.SMTPStatusCircuitOpen, v.Verify("user@slow.test").Status)
+
+	// A trial probe that fails keeps the circuit open for another cooldown
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, validator.SMTPStatusAllMXUnreachable, v.Verify("user@slow.test").Status)
+	assert.Equal(t, validator.SMTPStatusCircuitOpen, v.Verify("user@slow.test").Status)
+
+	// A trial probe that gets an answer closes it
+	dialer.down.Store(false)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, validator.SMTPStatusDeliverable, v.Verify("user@slow.test").Status)
+	assert.Equal(t, validator.SMTPStatusDeliverable, v.Verify("user@slow.test").Status)
+}
+
+func TestSMTPCircuitBreakerForgetsStaleFailures(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	dialer := &switchableDialer{}
+	dialer.down.Store(true)
+	v := newBreakerTestValidator(server, dialer, 2, 20*time.Millisecond)
+
+	// Failures further apart than the cooldown are not consecutive enough to open the circuit
+	assert.Equal(t, validator.SMTPStatusAllMXUnreachable, v.Verify("user@rare.test").Status)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, validator.SMTPStatusAllMXUnreachable, v.Verify("user@rare.test").Status)
+	assert.Equal(t, validator.SMTPStatusAllMXUnreachable, v.Verify("user@rare.test").Status)
+	assert.Equal(t, validator.SMTPStatusCircuitOpen, v.Verify("user@rare.test").Status)
+}
+
+func TestSMTPCircuitBreakerDisabled(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	dialer := &switchableDialer{}
+	dialer.down.Store(true)
+	v := newBreakerTestValidator(server, dialer, 0, time.Hour)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, validator.SMTPStatusAllMXUnreachable, v.Verify("user@tarpit.test").Status)
+	}
+	assert.Equal(t, int64(10), dialer.attempts.Load())
+}
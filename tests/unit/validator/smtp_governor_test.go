@@ -0,0 +1,85 @@
+package validatortest
+
+import (
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPGovernorGlobalLimit(t *testing.T) {
+	// 60 a minute allows a burst of ten seconds' worth
+	governor := validator.NewSMTPGovernor(60, nil, 0)
+	for i := 0; i < 10; i++ {
+		assert.True(t, governor.Acquire("example.com", "mx.example.com"), "probe %d", i)
+	}
+	assert.False(t, governor.Acquire("example.com", "mx.example.com"))
+	assert.False(t, governor.Acquire("other.test", "mx.other.test"), "the limit is shared by every domain")
+}
+
+func TestSMTPGovernorProviderLimit(t *testing.T) {
+	governor := validator.NewSMTPGovernor(0, map[string]int{"google.com": 6, "gmail.com": 600}, 0)
+
+	// A Workspace domain is matched by its mail server
+	assert.True(t, governor.Acquire("acme.test", "aspmx.l.google.com."))
+	assert.False(t, governor.Acquire("other.test", "ASPMX.L.GOOGLE.COM"))
+
+	// The most specific limit applies
+	for i := 0; i < 100; i++ {
+		require.True(t, governor.Acquire("gmail.com", "gmail-smtp-in.l.google.com"), "probe %d", i)
+	}
+
+	// Domains without a limit are not limited
+	for i := 0; i < 100; i++ {
+		require.True(t, governor.Acquire("example.com", "mx.example.com"), "probe %d", i)
+	}
+}
+
+func TestSMTPGovernorWaitsForBudget(t *testing.T) {
+	// Ten probes a second with a burst of 100
+	governor := validator.NewSMTPGovernor(600, nil, time.Second)
+	for i := 0; i < 100; i++ {
+		require.True(t, governor.Acquire("example.com", "mx.example.com"))
+	}
+
+	start := time.Now()
+	assert.True(t, governor.Acquire("example.com", "mx.example.com"), "the next token arrives within the wait")
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestSMTPValidatorRateLimited(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	dialer := &switchableDialer{}
+	v := newBreakerTestValidator(server, dialer, 1, time.Hour)
+	v.SetGovernor(validator.NewSMTPGovernor(0, map[string]int{"limited.test": 6}, 0))
+
+	assert.Equal(t, validator.SMTPStatusDeliverable, v.Verify("user@limited.test").Status)
+
+	result := v.Verify("other@limited.test")
+	assert.Equal(t, validator.SMTPStatusRateLimited, result.Status)
+	assert.Equal(t, "bounce@verified.test", result.Sender)
+	assert.Equal(t, int64(1), dialer.attempts.Load(), "a rate-limited probe makes no connection")
+
+	// A skipped probe does not count against the domain's circuit breaker
+	assert.Equal(t, validator.SMTPStatusDeliverable, v.Verify("user@unlimited.test").Status)
+	assert.Equal(t, validator.SMTPStatusRateLimited, v.Verify("user@limited.test").Status)
+	assert.NotEqual(t, validator.SMTPStatusCircuitOpen, v.Verify("user@limited.test").Status)
+}
+
+func TestParseSMTPProviderRates(t *testing.T) {
+	rates, err := validator.ParseSMTPProviderRates(" Google.com=60, outlook.com = 120 ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"google.com": 60, "outlook.com": 120}, rates)
+
+	rates, err = validator.ParseSMTPProviderRates("")
+	require.NoError(t, err)
+	assert.Empty(t, rates)
+
+	for _, invalid := range []string{"google.com", "google.com=0", "google.com=fast", "=60"} {
+		_, err := validator.ParseSMTPProviderRates(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,521 @@
+package validatortest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+	"emailvalidator/tests/unit/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSMTPServer is a minimal SMTP server for tests. The reply function decides the
+// response to each command; an empty reply means "250 OK".
+type mockSMTPServer struct {
+	listener net.Listener
+	reply    func(command string) string
+
+	mu       sync.Mutex
+	commands []string
+	clients  []string
+}
+
+func newMockSMTPServer(t *testing.T, reply func(command string) string) *mockSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start mock SMTP server: %v", err)
+	}
+	server := &mockSMTPServer{listener: listener, reply: reply}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *mockSMTPServer) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *mockSMTPServer) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// Clients returns the IP address of every client that connected
+func (s *mockSMTPServer) Clients() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.clients...)
+}
+
+func (s *mockSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	s.mu.Lock()
+	s.clients = append(s.clients, conn.RemoteAddr().(*net.TCPAddr).IP.String())
+	s.mu.Unlock()
+	reader := bufio.NewReader(conn)
+	_, _ = conn.Write([]byte("220 mock.test ESMTP\r\n"))
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+
+		s.mu.Lock()
+		s.commands = append(s.commands, command)
+		s.mu.Unlock()
+
+		if strings.EqualFold(command, "QUIT") {
+			_, _ = conn.Write([]byte("221 bye\r\n"))
+			return
+		}
+
+		response := ""
+		if s.reply != nil {
+			response = s.reply(command)
+		}
+		if response == "" {
+			response = "250 OK"
+		}
+		_, _ = conn.Write([]byte(response + "\r\n"))
+	}
+}
+
+func newTestSMTPValidator(server *mockSMTPServer, sender string) *validator.SMTPValidator {
+	v := validator.NewSMTPValidator(&testutil.LoopbackMXResolver{}, "verifier.test", sender)
+	v.SetPort(server.Port())
+	v.SetTimeout(2 * time.Second)
+	return v
+}
+
+// rejectNullSender mimics servers that refuse the null reverse-path
+func rejectNullSender(command string) string {
+	if strings.HasPrefix(strings.ToUpper(command), "MAIL FROM:<>") {
+		return "550 5.7.1 Null sender not accepted"
+	}
+	return ""
+}
+
+// requireSenderDomain mimics servers that only accept senders from a verified domain
+func requireSenderDomain(domain string) func(string) string {
+	return func(command string) string {
+		upper := strings.ToUpper(command)
+		if strings.HasPrefix(upper, "MAIL FROM:") && !strings.Contains(upper, "@"+strings.ToUpper(domain)+">") {
+			return "550 5.7.1 Sender domain not verified"
+		}
+		if strings.HasPrefix(upper, "RCPT TO:") && strings.Contains(upper, "<NOBODY@") {
+			return "550 5.1.1 No such user"
+		}
+		return ""
+	}
+}
+
+func TestSMTPValidatorSender(t *testing.T) {
+	server := newMockSMTPServer(t, requireSenderDomain("verified.test"))
+
+	tests := []struct {
+		name       string
+		sender     string
+		email      string
+		wantStatus validator.SMTPStatus
+		wantSender string
+	}{
+		{
+			name:       "Verified sender address",
+			sender:     "bounce@verified.test",
+			email:      "user@example.com",
+			wantStatus: validator.SMTPStatusDeliverable,
+			wantSender: "bounce@verified.test",
+		},
+		{
+			name:       "Bare domain expands to an address",
+			sender:     "verified.test",
+			email:      "user@example.com",
+			wantStatus: validator.SMTPStatusDeliverable,
+			wantSender: "verify@verified.test",
+		},
+		{
+			name:       "Unverified sender domain is rejected",
+			sender:     "bounce@other.test",
+			email:      "user@example.com",
+			wantStatus: validator.SMTPStatusSenderRejected,
+			wantSender: "bounce@other.test",
+		},
+		{
+			name:       "Null sender is rejected",
+			sender:     validator.NullSender,
+			email:      "user@example.com",
+			wantStatus: validator.SMTPStatusSenderRejected,
+			wantSender: "",
+		},
+		{
+			name:       "Unknown mailbox with accepted sender",
+			sender:     "bounce@verified.test",
+			email:      "nobody@example.com",
+			wantStatus: validator.SMTPStatusUndeliverable,
+			wantSender: "bounce@verified.test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestSMTPValidator(server, tt.sender).Verify(tt.email)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Equal(t, tt.wantSender, result.Sender)
+			assert.Equal(t, "127.0.0.1", result.Host)
+		})
+	}
+}
+
+func TestSMTPValidatorNullSender(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+
+	result := newTestSMTPValidator(server, validator.NullSender).Verify("user@example.com")
+	assert.Equal(t, validator.SMTPStatusDeliverable, result.Status)
+	assert.Contains(t, server.Commands(), "MAIL FROM:<>")
+}
+
+func TestSMTPValidatorPerCallSenderOverride(t *testing.T) {
+	server := newMockSMTPServer(t, rejectNullSender)
+	v := newTestSMTPValidator(server, validator.NullSender)
+
+	result := v.Verify("user@example.com")
+	assert.Equal(t, validator.SMTPStatusSenderRejected, result.Status)
+	assert.Equal(t, 550, result.Code)
+
+	result = v.VerifyWithOptions("user@example.com", validator.SMTPOptions{Sender: "bounce@verified.test"})
+	assert.Equal(t, validator.SMTPStatusDeliverable, result.Status)
+	assert.Equal(t, "bounce@verified.test", result.Sender)
+
+	// The override does not change the configured default
+	result = v.Verify("user@example.com")
+	assert.Equal(t, validator.SMTPStatusSenderRejected, result.Status)
+}
+
+func TestSMTPValidatorTransientSenderRejection(t *testing.T) {
+	server := newMockSMTPServer(t, func(command string) string {
+		if strings.HasPrefix(strings.ToUpper(command), "MAIL FROM:") {
+			return "451 4.7.1 Try again later"
+		}
+		return ""
+	})
+
+	result := newTestSMTPValidator(server, "bounce@verified.test").Verify("user@example.com")
+	assert.Equal(t, validator.SMTPStatusUnknown, result.Status)
+	assert.Equal(t, 451, result.Code)
+}
+
+func TestSMTPValidatorConnectionRefused(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	port := server.Port()
+	server.listener.Close()
+
+	v := validator.NewSMTPValidator(&testutil.LoopbackMXResolver{}, "verifier.test", "bounce@verified.test")
+	v.SetPort(port)
+	v.SetTimeout(time.Second)
+
+	result := v.Verify("user@example.com")
+	assert.Equal(t, validator.SMTPStatusAllMXUnreachable, result.Status)
+	assert.Equal(t, []string{"127.0.0.1"}, result.Unreachable)
+	assert.Empty(t, result.Host)
+	assert.Zero(t, result.Code)
+	assert.NotEmpty(t, result.Message)
+}
+
+// multiMXResolver returns a fixed set of MX records for every domain
+type multiMXResolver struct {
+	records []*net.MX
+}
+
+func (r *multiMXResolver) LookupHost(domain string) ([]string, error) {
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *multiMXResolver) LookupMX(domain string) ([]*net.MX, error) {
+	return r.records, nil
+}
+
+// routingDialer connects hosts listed in up to the mock server and refuses all others
+func routingDialer(server *mockSMTPServer, up ...string) validator.DialFunc {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range up {
+			if h == host {
+				return net.DialTimeout(network, server.listener.Addr().String(), timeout)
+			}
+		}
+		return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+	}
+}
+
+func TestSMTPValidatorMXFailover(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	resolver := &multiMXResolver{records: []*net.MX{
+		{Host: "mx3.example.com.", Pref: 30},
+		{Host: "mx1.example.com.", Pref: 10},
+		{Host: "mx2.example.com.", Pref: 20},
+	}}
+
+	tests := []struct {
+		name            string
+		up              []string
+		wantStatus      validator.SMTPStatus
+		wantHost        string
+		wantUnreachable []string
+	}{
+		{
+			name:       "Primary answers",
+			up:         []string{"mx1.example.com", "mx2.example.com", "mx3.example.com"},
+			wantStatus: validator.SMTPStatusDeliverable,
+			wantHost:   "mx1.example.com",
+		},
+		{
+			name:            "Primary refuses, secondary answers",
+			up:              []string{"mx2.example.com", "mx3.example.com"},
+			wantStatus:      validator.SMTPStatusDeliverable,
+			wantHost:        "mx2.example.com",
+			wantUnreachable: []string{"mx1.example.com"},
+		},
+		{
+			name:            "Only the lowest priority host answers",
+			up:              []string{"mx3.example.com"},
+			wantStatus:      validator.SMTPStatusDeliverable,
+			wantHost:        "mx3.example.com",
+			wantUnreachable: []string{"mx1.example.com", "mx2.example.com"},
+		},
+		{
+			name:            "All hosts refuse",
+			up:              nil,
+			wantStatus:      validator.SMTPStatusAllMXUnreachable,
+			wantHost:        "",
+			wantUnreachable: []string{"mx1.example.com", "mx2.example.com", "mx3.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.NewSMTPValidator(resolver, "verifier.test", "bounce@verified.test")
+			v.SetTimeout(2 * time.Second)
+			v.SetDialFunc(routingDialer(server, tt.up...))
+
+			result := v.Verify("user@example.com")
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Equal(t, tt.wantHost, result.Host)
+			assert.Equal(t, tt.wantUnreachable, result.Unreachable)
+		})
+	}
+}
+
+func TestSMTPValidatorFailoverOnServiceUnavailableGreeting(t *testing.T) {
+	busyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer busyListener.Close()
+	go func() {
+		for {
+			conn, err := busyListener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("421 4.3.2 Service not available\r\n"))
+			conn.Close()
+		}
+	}()
+
+	healthy := newMockSMTPServer(t, nil)
+	resolver := &multiMXResolver{records: []*net.MX{
+		{Host: "busy.example.com.", Pref: 10},
+		{Host: "healthy.example.com.", Pref: 20},
+	}}
+
+	v := validator.NewSMTPValidator(resolver, "verifier.test", "bounce@verified.test")
+	v.SetTimeout(2 * time.Second)
+	v.SetDialFunc(func(network, address string, timeout time.Duration) (net.Conn, error) {
+		if strings.HasPrefix(address, "busy.") {
+			return net.DialTimeout(network, busyListener.Addr().String(), timeout)
+		}
+		return net.DialTimeout(network, healthy.listener.Addr().String(), timeout)
+	})
+
+	result := v.Verify("user@example.com")
+	assert.Equal(t, validator.SMTPStatusDeliverable, result.Status)
+	assert.Equal(t, "healthy.example.com", result.Host)
+	assert.Equal(t, []string{"busy.example.com"}, result.Unreachable)
+}
+
+func TestSMTPValidatorGreylisting(t *testing.T) {
+	server := newMockSMTPServer(t, func(command string) string {
+		if strings.HasPrefix(strings.ToUpper(command), "RCPT TO:") {
+			return "450 4.2.0 Greylisted, please try again later"
+		}
+		return ""
+	})
+
+	result := newTestSMTPValidator(server, "bounce@verified.test").Verify("user@example.com")
+	assert.Equal(t, validator.SMTPStatusGreylisted, result.Status)
+	assert.Equal(t, 450, result.Code)
+	assert.Equal(t, "127.0.0.1", result.Host)
+}
+
+func TestSMTPValidatorCatchAll(t *testing.T) {
+	acceptOnly := func(mailbox string) func(string) string {
+		return func(command string) string {
+			upper := strings.ToUpper(command)
+			if strings.HasPrefix(upper, "RCPT TO:") && !strings.Contains(upper, "<"+strings.ToUpper(mailbox)+">") {
+				return "550 5.1.1 No such user"
+			}
+			return ""
+		}
+	}
+
+	tests := []struct {
+		name         string
+		reply        func(string) string
+		wantCatchAll bool
+	}{
+		{name: "Server accepts every recipient", reply: nil, wantCatchAll: true},
+		{name: "Server rejects unknown recipients", reply: acceptOnly("user@example.com"), wantCatchAll: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockSMTPServer(t, tt.reply)
+
+			result := newTestSMTPValidator(server, "bounce@verified.test").Verify("user@example.com")
+			assert.Equal(t, validator.SMTPStatusDeliverable, result.Status)
+			assert.Equal(t, tt.wantCatchAll, result.CatchAll)
+
+			var rcpts int
+			for _, command := range server.Commands() {
+				if strings.HasPrefix(command, "RCPT TO:") {
+					rcpts++
+				}
+			}
+			assert.Equal(t, 2, rcpts, "the catch-all probe reuses the connection")
+		})
+	}
+}
+
+func TestSMTPValidatorTranscript(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	v := newTestSMTPValidator(server, "bounce@verified.test")
+
+	result := v.Verify("user@example.com")
+	assert.Empty(t, result.Transcript, "transcripts are only recorded on request")
+
+	result = v.VerifyWithOptions("user@example.com", validator.SMTPOptions{Transcript: true})
+	assert.Equal(t, validator.SMTPStatusDeliverable, result.Status)
+	if assert.NotEmpty(t, result.Transcript) {
+		assert.True(t, strings.HasPrefix(result.Transcript[0], "* connecting to 127.0.0.1:"))
+	}
+	assert.Contains(t, result.Transcript, "S: 220 mock.test ESMTP")
+	assert.Contains(t, result.Transcript, "C: EHLO verifier.test")
+	assert.Contains(t, result.Transcript, "C: MAIL FROM:<***@verified.test>")
+	assert.Contains(t, result.Transcript, "C: RCPT TO:<user@example.com>")
+	assert.Contains(t, result.Transcript, "S: 221 bye")
+	for _, line := range result.Transcript {
+		assert.NotContains(t, line, "bounce@", "the sender is redacted")
+	}
+	assert.Equal(t, "bounce@verified.test", result.Sender, "only the transcript is redacted")
+}
+
+func TestSMTPValidatorTranscriptRedactsEchoedSender(t *testing.T) {
+	server := newMockSMTPServer(t, func(command string) string {
+		if strings.HasPrefix(command, "MAIL FROM:") {
+			return "250 2.1.0 Sender <BOUNCE@verified.test> OK"
+		}
+		return ""
+	})
+	v := newTestSMTPValidator(server, "bounce@verified.test")
+
+	result := v.VerifyWithOptions("user@example.com", validator.SMTPOptions{Transcript: true})
+	assert.Contains(t, result.Transcript, "S: 250 2.1.0 Sender <***@verified.test> OK")
+}
+
+func TestRedactSender(t *testing.T) {
+	assert.Equal(t, "***@verified.test", validator.RedactSender("bounce@verified.test"))
+	assert.Equal(t, "***@verified.test", validator.RedactSender("first.last@verified.test"))
+	assert.Equal(t, "", validator.RedactSender(""), "the null sender has nothing to redact")
+}
+
+// advertiseSMTPUTF8 mimics servers that support internationalized addresses
+func advertiseSMTPUTF8(command string) string {
+	if strings.HasPrefix(strings.ToUpper(command), "EHLO") {
+		return "250-mock.test\r\n250-8BITMIME\r\n250 SMTPUTF8"
+	}
+	return ""
+}
+
+func TestSMTPValidatorSMTPUTF8(t *testing.T) {
+	server := newMockSMTPServer(t, advertiseSMTPUTF8)
+	v := newTestSMTPValidator(server, "bounce@verified.test")
+
+	result := v.Verify("用户@例子.公司")
+	assert.Equal(t, validator.SMTPStatusDeliverable, result.Status)
+	assert.Contains(t, server.Commands(), "MAIL FROM:<bounce@verified.test> BODY=8BITMIME SMTPUTF8")
+	assert.Contains(t, server.Commands(), "RCPT TO:<用户@例子.公司>")
+}
+
+func TestSMTPValidatorWithoutSMTPUTF8(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	v := newTestSMTPValidator(server, "bounce@verified.test")
+
+	t.Run("internationalized local part is inconclusive", func(t *testing.T) {
+		result := v.Verify("用户@example.com")
+		assert.Equal(t, validator.SMTPStatusSMTPUTF8Unsupported, result.Status)
+		for _, command := range server.Commands() {
+			assert.False(t, strings.HasPrefix(command, "RCPT TO:<用户"), "the address cannot be sent without SMTPUTF8")
+		}
+	})
+
+	t.Run("internationalized domain is sent in punycode", func(t *testing.T) {
+		result := v.Verify("user@bücher.example")
+		assert.Equal(t, validator.SMTPStatusDeliverable, result.Status)
+		assert.Contains(t, server.Commands(), "RCPT TO:<user@xn--bcher-kva.example>")
+	})
+}
+
+func TestSMTPValidatorSourceIP(t *testing.T) {
+	server := newMockSMTPServer(t, nil)
+	sourceIP := net.ParseIP("127.0.0.2")
+	if err := validator.CheckSourceIP(sourceIP); err != nil {
+		t.Skipf("Loopback alias not available: %v", err)
+	}
+
+	v := validator.NewSMTPValidator(&testutil.LoopbackMXResolver{}, "verifier.test", "bounce@verified.test", validator.WithSourceIP(sourceIP))
+	v.SetPort(server.Port())
+	v.SetTimeout(2 * time.Second)
+	assert.True(t, sourceIP.Equal(v.SourceIP()))
+
+	result := v.Verify("user@example.com")
+	if assert.Equal(t, validator.SMTPStatusDeliverable, result.Status, result.Message) {
+		assert.Equal(t, []string{"127.0.0.2"}, server.Clients())
+	}
+}
+
+func TestCheckSourceIP(t *testing.T) {
+	assert.NoError(t, validator.CheckSourceIP(net.ParseIP("127.0.0.1")))
+	assert.Error(t, validator.CheckSourceIP(net.ParseIP("192.0.2.1")), "documentation addresses are never assigned")
+}
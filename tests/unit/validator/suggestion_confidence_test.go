@@ -0,0 +1,38 @@
+package validatortest
+
+import (
+	"context"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestionConfidence(t *testing.T) {
+	assert.Equal(t, 1.0, validator.SuggestionConfidence("gmail.com", "GMAIL.COM"))
+	assert.InDelta(t, 8.0/9, validator.SuggestionConfidence("gmial.com", "gmail.com"), 1e-9)
+	assert.InDelta(t, 5.0/7, validator.SuggestionConfidence("axne.io", "acme.io"), 1e-9)
+	assert.Equal(t, 0.0, validator.SuggestionConfidence("", ""))
+}
+
+func TestTypoSuggestionsBelowConfidenceAreSuppressed(t *testing.T) {
+	learner := validator.NewTypoLearner(validator.NewMemoryDomainFrequencyStore(), validator.DefaultTypoLearningHalfLife)
+	learner.SetMaxDistance(2)
+	learnTimes(learner, "acme.io", 10)
+	assert.NoError(t, learner.Sync(context.Background()))
+
+	v, err := validator.NewEmailValidator()
+	assert.NoError(t, err)
+	v.SetTypoLearner(learner)
+
+	assert.Equal(t, []string{"user@acme.io"}, v.GetTypoSuggestions("user@acxe.io"), "one edit passes the default")
+	assert.Empty(t, v.GetTypoSuggestions("user@axne.io"), "two edits in seven characters is a wild guess")
+	assert.Equal(t, []string{"user@acme.io"}, v.GetTypoSuggestionsWithConfidence("user@axne.io", 0.7))
+
+	v.SetMinSuggestionConfidence(0.7)
+	assert.Equal(t, []string{"user@acme.io"}, v.GetTypoSuggestions("user@axne.io"))
+
+	v.SetMinSuggestionConfidence(0.9)
+	assert.Empty(t, v.GetTypoSuggestions("user@gmial.com"), "static corrections are filtered too")
+}
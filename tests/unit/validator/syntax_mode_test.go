@@ -0,0 +1,178 @@
+package validatortest
+
+import (
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSyntaxMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    validator.SyntaxMode
+		wantErr bool
+	}{
+		{"", validator.SyntaxModeLenient, false},
+		{"lenient", validator.SyntaxModeLenient, false},
+		{"RFC5322", validator.SyntaxModeRFC5322, false},
+		{"rfc5321", validator.SyntaxModeRFC5321Strict, false},
+		{"strict", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := validator.ParseSyntaxMode(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSyntaxValidatorCheckMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		lenient validator.SyntaxViolation
+		rfc5322 validator.SyntaxViolation
+		rfc5321 validator.SyntaxViolation
+	}{
+		{
+			name:  "Dot-atom address",
+			email: "jane.doe+news@example.com",
+		},
+		{
+			name:    "Quoted local part",
+			email:   `"jane doe"@example.com`,
+			lenient: validator.ViolationQuotedString,
+		},
+		{
+			name:    "Quoted local part with escaped quote and @",
+			email:   `"jane\"@home"@example.com`,
+			lenient: validator.ViolationQuotedString,
+		},
+		{
+			name:    "Quoted local part with a tab",
+			email:   "\"jane\tdoe\"@example.com",
+			lenient: validator.ViolationQuotedString,
+			rfc5321: validator.ViolationMalformed,
+		},
+		{
+			name:    "IPv4 address literal",
+			email:   "jane@[192.0.2.1]",
+			lenient: validator.ViolationAddressLiteral,
+		},
+		{
+			name:    "IPv6 address literal",
+			email:   "jane@[IPv6:2001:db8::1]",
+			lenient: validator.ViolationAddressLiteral,
+		},
+		{
+			name:    "IPv6 literal without its tag is only a general literal",
+			email:   "jane@[2001:db8::1]",
+			lenient: validator.ViolationAddressLiteral,
+			rfc5321: validator.ViolationMalformed,
+		},
+		{
+			name:    "General domain literal",
+			email:   "jane@[example]",
+			lenient: validator.ViolationAddressLiteral,
+			rfc5321: validator.ViolationMalformed,
+		},
+		{
+			name:    "Comment in local part",
+			email:   "jane(work)@example.com",
+			lenient: validator.ViolationComment,
+			rfc5321: validator.ViolationComment,
+		},
+		{
+			name:    "Comments and folding whitespace around the @",
+			email:   "jane (home) @ (primary) example.com",
+			lenient: validator.ViolationComment,
+			rfc5321: validator.ViolationComment,
+		},
+		{
+			name:    "Unbalanced comment",
+			email:   "jane(work@example.com",
+			lenient: validator.ViolationComment,
+			rfc5322: validator.ViolationMalformed,
+			rfc5321: validator.ViolationComment,
+		},
+		{
+			name:    "Whitespace inside an atom",
+			email:   "ja ne@example.com",
+			lenient: validator.ViolationMalformed,
+			rfc5322: validator.ViolationMalformed,
+			rfc5321: validator.ViolationMalformed,
+		},
+		{
+			name:    "Internationalized address",
+			email:   "用户@例子.广告",
+			rfc5322: validator.ViolationNonASCII,
+			rfc5321: validator.ViolationNonASCII,
+		},
+		{
+			name:    "Atext that is not a hostname character",
+			email:   "jane@exa_mple.com",
+			lenient: validator.ViolationNone,
+			rfc5321: validator.ViolationMalformed,
+		},
+		{
+			name:    "Hostname label ending with a hyphen",
+			email:   "jane@example-.com",
+			rfc5321: validator.ViolationMalformed,
+		},
+		{
+			name:    "Display name",
+			email:   "Jane <jane@example.com>",
+			lenient: validator.ViolationMalformed,
+			rfc5322: validator.ViolationMalformed,
+			rfc5321: validator.ViolationMalformed,
+		},
+		{
+			name:    "Consecutive dots",
+			email:   "jane..doe@example.com",
+			lenient: validator.ViolationConsecutiveDots,
+			rfc5322: validator.ViolationConsecutiveDots,
+			rfc5321: validator.ViolationConsecutiveDots,
+		},
+		{
+			// Stripping the comment leaves no "@" at all
+			name:    "Only @ inside a comment",
+			email:   "user.(@)example.com",
+			lenient: validator.ViolationComment,
+			rfc5322: validator.ViolationMalformed,
+			rfc5321: validator.ViolationComment,
+		},
+	}
+
+	syntaxValidator := validator.NewSyntaxValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.lenient, syntaxValidator.CheckMode(tt.email, validator.SyntaxModeLenient), "lenient")
+			assert.Equal(t, tt.rfc5322, syntaxValidator.CheckMode(tt.email, validator.SyntaxModeRFC5322), "rfc5322")
+			assert.Equal(t, tt.rfc5321, syntaxValidator.CheckMode(tt.email, validator.SyntaxModeRFC5321Strict), "rfc5321")
+		})
+	}
+}
+
+func TestSyntaxValidatorSetMode(t *testing.T) {
+	syntaxValidator := validator.NewSyntaxValidator()
+	assert.False(t, syntaxValidator.Validate("jane@[192.0.2.1]"))
+
+	syntaxValidator.SetMode(validator.SyntaxModeRFC5321Strict)
+	assert.True(t, syntaxValidator.Validate("jane@[192.0.2.1]"))
+	// An empty mode falls back to the configured one
+	assert.Equal(t, validator.ViolationNone, syntaxValidator.CheckMode("jane@[192.0.2.1]", ""))
+}
+
+func TestStripCFWS(t *testing.T) {
+	assert.Equal(t, "jane@example.com", validator.StripCFWS("jane (home) @ (primary) example.com"))
+	assert.Equal(t, `"jane (not a comment)"@example.com`, validator.StripCFWS(`"jane (not a comment)"@example.com`))
+	assert.Equal(t, "jane(work@example.com", validator.StripCFWS("jane(work@example.com"))
+}
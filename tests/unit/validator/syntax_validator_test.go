@@ -2,6 +2,7 @@ package validatortest
 
 import (
 	"emailvalidator/pkg/validator"
+	"strings"
 	"testing"
 )
 
@@ -94,3 +95,167 @@ func TestSyntaxValidatorValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestSyntaxValidatorCheckLengthLimits(t *testing.T) {
+	label := func(n int) string { return strings.Repeat("a", n) }
+
+	// 64 + 1 + 189 = 254 octets, with every domain label within 63 octets
+	longestDomain := label(63) + "." + label(63) + "." + label(57) + ".com"
+	// The longest domain allowed on its own, which no address can fit in
+	maxDomain := label(63) + "." + label(63) + "." + label(63) + "." + label(63)
+
+	tests := []struct {
+		name  string
+		email string
+		want  validator.SyntaxViolation
+	}{
+		{
+			name:  "Local part of exactly 64 octets",
+			email: label(64) + "@example.com",
+			want:  validator.ViolationNone,
+		},
+		{
+			name:  "Local part of 65 octets",
+			email: label(65) + "@example.com",
+			want:  validator.ViolationLocalPartTooLong,
+		},
+		{
+			name:  "Domain label of exactly 63 octets",
+			email: "user@" + label(63) + ".com",
+			want:  validator.ViolationNone,
+		},
+		{
+			name:  "Domain label of 64 octets",
+			email: "user@" + label(64) + ".com",
+			want:  validator.ViolationLabelTooLong,
+		},
+		{
+			name:  "Address of exactly 254 octets",
+			email: label(64) + "@" + longestDomain,
+			want:  validator.ViolationNone,
+		},
+		{
+			name:  "Address of 255 octets",
+			email: label(64) + "@a" + longestDomain,
+			want:  validator.ViolationAddressTooLong,
+		},
+		{
+			name:  "Domain of exactly 255 octets",
+			email: "a@" + maxDomain,
+			want:  validator.ViolationAddressTooLong,
+		},
+		{
+			name:  "Domain of 256 octets",
+			email: "a@" + maxDomain[:254] + ".a",
+			want:  validator.ViolationDomainTooLong,
+		},
+		{
+			name:  "Multibyte local part counted in octets",
+			email: strings.Repeat("é", 33) + "@example.com",
+			want:  validator.ViolationLocalPartTooLong,
+		},
+		{
+			name:  "Empty address",
+			email: "",
+			want:  validator.ViolationEmpty,
+		},
+		{
+			name:  "Missing @",
+			email: "invalid-email",
+			want:  validator.ViolationMalformed,
+		},
+	}
+
+	v := validator.NewSyntaxValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v.Check(tt.email)
+			if got != tt.want {
+				t.Errorf("SyntaxValidator.Check(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+			if got != validator.ViolationNone && got.Description() == "" {
+				t.Errorf("SyntaxViolation %q has no description", got)
+			}
+		})
+	}
+}
+
+func TestSyntaxValidatorCheckLocalPartDots(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  validator.SyntaxViolation
+	}{
+		{
+			name:  "Single dots between atoms",
+			email: "john.q.doe@x.com",
+			want:  validator.ViolationNone,
+		},
+		{
+			name:  "Leading dot",
+			email: ".john@x.com",
+			want:  validator.ViolationLeadingDot,
+		},
+		{
+			name:  "Leading consecutive dots",
+			email: "..john@x.com",
+			want:  validator.ViolationLeadingDot,
+		},
+		{
+			name:  "Trailing dot",
+			email: "john.@x.com",
+			want:  validator.ViolationTrailingDot,
+		},
+		{
+			name:  "Only a dot",
+			email: ".@x.com",
+			want:  validator.ViolationLeadingDot,
+		},
+		{
+			name:  "Consecutive dots in the middle",
+			email: "john..doe@x.com",
+			want:  validator.ViolationConsecutiveDots,
+		},
+		{
+			name:  "Consecutive dots at the end",
+			email: "john..@x.com",
+			want:  validator.ViolationConsecutiveDots,
+		},
+		{
+			name:  "Consecutive dots inside quotes are not a dot violation",
+			email: `"john..doe"@x.com`,
+			want:  validator.ViolationQuotedString,
+		},
+		{
+			name:  "Leading and trailing dots inside quotes are not a dot violation",
+			email: `".john."@x.com`,
+			want:  validator.ViolationQuotedString,
+		},
+		{
+			name:  "Escaped quote does not end the quoted string",
+			email: `"a\"..b"@x.com`,
+			want:  validator.ViolationQuotedString,
+		},
+		{
+			name:  "Consecutive dots after a quoted string",
+			email: `"john"..doe@x.com`,
+			want:  validator.ViolationConsecutiveDots,
+		},
+		{
+			name:  "Dots in the domain are not checked as local part dots",
+			email: "john@x..com",
+			want:  validator.ViolationMalformed,
+		},
+	}
+
+	v := validator.NewSyntaxValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.Check(tt.email); got != tt.want {
+				t.Errorf("SyntaxValidator.Check(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
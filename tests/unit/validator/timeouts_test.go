@@ -0,0 +1,101 @@
+package validatortest
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+	"emailvalidator/tests/unit/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// silentDNSResolver returns a net.Resolver whose queries go to a UDP socket that never answers
+func silentDNSResolver(t *testing.T) *net.Resolver {
+	t.Helper()
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", server.LocalAddr().String())
+		},
+	}
+}
+
+func TestDNSTimeout(t *testing.T) {
+	resolver := validator.NewDefaultResolver(100 * time.Millisecond)
+	resolver.SetNetResolver(silentDNSResolver(t))
+
+	start := time.Now()
+	_, err := resolver.LookupMX("example.test")
+	assert.ErrorIs(t, err, validator.ErrDNSTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, validator.IsInconclusiveDNSError(err))
+}
+
+func TestSMTPConnectTimeout(t *testing.T) {
+	var dialTimeouts []time.Duration
+	v := validator.NewSMTPValidator(&testutil.LoopbackMXResolver{}, "verifier.test", "bounce@verified.test")
+	v.SetConnectTimeout(300 * time.Millisecond)
+	v.SetCommandTimeout(5 * time.Second)
+	v.SetDialFunc(func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dialTimeouts = append(dialTimeouts, timeout)
+		time.Sleep(timeout)
+		return nil, &net.OpError{Op: "dial", Net: network, Err: os.ErrDeadlineExceeded}
+	})
+
+	result := v.Verify("user@example.com")
+	assert.Equal(t, validator.SMTPStatusAllMXUnreachable, result.Status)
+
+	result = v.VerifyWithOptions("user@example.com", validator.SMTPOptions{ConnectTimeout: 50 * time.Millisecond})
+	assert.Equal(t, validator.SMTPStatusAllMXUnreachable, result.Status)
+	assert.Equal(t, []time.Duration{300 * time.Millisecond, 50 * time.Millisecond}, dialTimeouts)
+}
+
+// slowRcpt delays the reply to RCPT TO by delay
+func slowRcpt(delay time.Duration) func(string) string {
+	return func(command string) string {
+		if strings.HasPrefix(strings.ToUpper(command), "RCPT TO:") {
+			time.Sleep(delay)
+		}
+		return ""
+	}
+}
+
+func TestSMTPCommandTimeout(t *testing.T) {
+	server := newMockSMTPServer(t, slowRcpt(2*time.Second))
+	v := newTestSMTPValidator(server, "bounce@verified.test")
+	v.SetConnectTimeout(5 * time.Second)
+	v.SetCommandTimeout(200 * time.Millisecond)
+
+	start := time.Now()
+	result := v.Verify("user@example.com")
+	assert.Less(t, time.Since(start), time.Second)
+	// A recipient the server never answered for is unknown, not rejected
+	assert.Equal(t, validator.SMTPStatusUnknown, result.Status)
+	assert.Zero(t, result.Code)
+	assert.Contains(t, result.Message, "timeout")
+}
+
+func TestSMTPCommandTimeoutPerCommand(t *testing.T) {
+	// Each reply takes 150ms, longer in total than the command timeout but not per command
+	server := newMockSMTPServer(t, func(command string) string {
+		time.Sleep(150 * time.Millisecond)
+		return ""
+	})
+	v := newTestSMTPValidator(server, "bounce@verified.test")
+	v.SetCommandTimeout(400 * time.Millisecond)
+	assert.Equal(t, validator.SMTPStatusDeliverable, v.Verify("user@example.com").Status)
+
+	result := v.VerifyWithOptions("user@example.com", validator.SMTPOptions{CommandTimeout: 50 * time.Millisecond})
+	assert.NotEqual(t, validator.SMTPStatusDeliverable, result.Status)
+}
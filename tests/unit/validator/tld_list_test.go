@@ -0,0 +1,128 @@
+package validatortest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundledTLDList(t *testing.T) {
+	path, err := validator.DefaultTLDFile()
+	require.NoError(t, err)
+	tlds, err := validator.NewTLDListFromFile(path)
+	require.NoError(t, err)
+	assert.Greater(t, tlds.Len(), 1000)
+
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{"example.com", true},
+		{"EXAMPLE.COM", true},
+		{"example.co.uk", true},
+		{"example.com.", true},
+		{"example.xn--p1ai", true},
+		{"example.рф", true},
+		{"example.qwerty", false},
+		{"example.invalid", false},
+		{"localhost", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			assert.Equal(t, tt.want, tlds.HasKnownTLD(tt.domain))
+		})
+	}
+}
+
+// countingResolver records how many lookups it served
+type countingResolver struct {
+	lookups int64
+}
+
+func (r *countingResolver) LookupHost(domain string) ([]string, error) {
+	atomic.AddInt64(&r.lookups, 1)
+	return []string{"192.0.2.1"}, nil
+}
+
+func (r *countingResolver) LookupMX(domain string) ([]*net.MX, error) {
+	atomic.AddInt64(&r.lookups, 1)
+	return []*net.MX{{Host: "mx." + domain, Pref: 10}}, nil
+}
+
+func TestDomainValidatorUnknownTLD(t *testing.T) {
+	resolver := &countingResolver{}
+	v := validator.NewDomainValidator(resolver, validator.NewDomainCacheManager(time.Hour))
+
+	// Without a list every TLD is accepted
+	assert.True(t, v.HasKnownTLD("example.qwerty"))
+
+	v.SetTLDList(validator.NewTLDList([]string{"com", "NET"}))
+	assert.False(t, v.Validate("example.qwerty"))
+	assert.False(t, v.ValidateMX("example.qwerty"))
+	assert.Zero(t, atomic.LoadInt64(&resolver.lookups), "unknown TLDs must not reach DNS")
+
+	assert.True(t, v.Validate("example.net"))
+	assert.True(t, v.ValidateMX("example.com"))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&resolver.lookups))
+}
+
+// ianaTLDList renders n TLDs in the IANA file format
+func ianaTLDList(n int, extra ...string) string {
+	var b strings.Builder
+	b.WriteString("# Version 2026101500, Last Updated Thu Oct 15 07:07:01 2026 UTC\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "TLD%d\n", i)
+	}
+	for _, tld := range extra {
+		b.WriteString(tld + "\n")
+	}
+	return b.String()
+}
+
+func TestTLDListUpdate(t *testing.T) {
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body.Load().(string))
+	}))
+	defer server.Close()
+
+	tlds := validator.NewTLDList([]string{"com"})
+
+	body.Store(ianaTLDList(300, "COM", "NEWTLD"))
+	require.NoError(t, tlds.Update(server.Client(), server.URL))
+	assert.True(t, tlds.Contains("newtld"))
+	assert.Equal(t, 302, tlds.Len())
+
+	// A truncated download is rejected and the current list kept
+	body.Store(ianaTLDList(10))
+	assert.Error(t, tlds.Update(server.Client(), server.URL))
+	assert.True(t, tlds.Contains("newtld"))
+}
+
+func TestTLDListAutoUpdate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, ianaTLDList(300, "FRESH"))
+	}))
+	defer server.Close()
+
+	tlds := validator.NewTLDList([]string{"com"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tlds.StartAutoUpdate(ctx, server.Client(), server.URL, 20*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return tlds.Contains("fresh")
+	}, 2*time.Second, 10*time.Millisecond)
+}
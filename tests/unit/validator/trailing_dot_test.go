@@ -0,0 +1,66 @@
+package validatortest
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimTrailingDot(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"example.com.", "example.com"},
+		{"example.com", "example.com"},
+		{"user@example.com.", "user@example.com"},
+		{"example.com..", "example.com.."},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, validator.TrimTrailingDot(tt.input))
+		})
+	}
+}
+
+func TestSyntaxValidatorTrailingDot(t *testing.T) {
+	syntaxValidator := validator.NewSyntaxValidator()
+	for _, mode := range []validator.SyntaxMode{validator.SyntaxModeLenient, validator.SyntaxModeRFC5322, validator.SyntaxModeRFC5321Strict} {
+		t.Run(string(mode), func(t *testing.T) {
+			assert.Equal(t, validator.ViolationNone, syntaxValidator.CheckMode("user@example.com.", mode))
+			assert.NotEqual(t, validator.ViolationNone, syntaxValidator.CheckMode("user@example.com..", mode))
+			assert.NotEqual(t, validator.ViolationNone, syntaxValidator.CheckMode("user@.", mode))
+		})
+	}
+}
+
+func TestDomainValidatorTrailingDotSharesCache(t *testing.T) {
+	resolver := &countingResolver{}
+	v := validator.NewDomainValidator(resolver, validator.NewDomainCacheManager(time.Hour))
+
+	assert.True(t, v.Validate("example.com."))
+	assert.True(t, v.Validate("example.com"))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&resolver.lookups))
+}
+
+func TestDisposableTrailingDot(t *testing.T) {
+	emailValidator, err := validator.NewEmailValidatorWithResolver(&MockResolver{})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	assert.True(t, emailValidator.IsDisposable("mailinator.com."))
+
+	emailValidator.SetDisposableAllowlist([]string{"mailinator.com"})
+	assert.False(t, emailValidator.IsDisposable("mailinator.com."))
+}
+
+func TestCanonicalizeTrailingDot(t *testing.T) {
+	detector := validator.NewAliasDetector()
+	assert.Equal(t, "johndoe@gmail.com", detector.Canonicalize("John.Doe+news@GMail.com.", validator.DefaultCanonicalizationRules()))
+}
@@ -0,0 +1,127 @@
+package validatortest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionalTypoSuggestions(t *testing.T) {
+	v, err := validator.NewEmailValidatorWithResolver(NewMockResolver())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		email  string
+		region string
+		want   []string
+	}{
+		{"Russian provider in ru", "ivan@mai.ru", "ru", []string{"ivan@mail.ru"}},
+		{"Russian provider without a region", "ivan@mai.ru", "", nil},
+		{"Korean provider in kr", "minsu@navr.com", "kr", []string{"minsu@naver.com"}},
+		{"Korean provider in another region", "minsu@navr.com", "ru", nil},
+		{"Region is case-insensitive", "hans@gmx.dee", "DE", []string{"hans@gmx.de"}},
+		{"Global typo in a region", "user@gmial.com", "kr", []string{"user@gmail.com"}},
+		{"Unknown region falls back to global", "user@gmial.com", "zz", []string{"user@gmail.com"}},
+		{"Unknown region has no regional typos", "ivan@mai.ru", "zz", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, v.GetTypoSuggestionsForRegion(tt.email, tt.region))
+		})
+	}
+}
+
+func TestRegionalTypoDictionaryTakesPrecedence(t *testing.T) {
+	v, err := validator.NewEmailValidatorWithResolver(NewMockResolver())
+	require.NoError(t, err)
+
+	// In Germany, gmx.co is far more likely a mistyped gmx.de than anything global
+	v.SetTypoDictionaries(map[string]map[string]string{
+		"":   {"gmx.co": "gmx.com"},
+		"de": {"gmx.co": "gmx.de"},
+	})
+	assert.Equal(t, []string{"hans@gmx.de"}, v.GetTypoSuggestionsForRegionWithConfidence("hans@gmx.co", "de", 0))
+	assert.Equal(t, []string{"hans@gmx.com"}, v.GetTypoSuggestionsForRegionWithConfidence("hans@gmx.co", "", 0))
+
+	// Added dictionaries extend the built-in ones rather than replace them
+	assert.Equal(t, []string{"hans@web.de"}, v.GetTypoSuggestionsForRegion("hans@wbe.de", "de"))
+}
+
+func TestTypoRegion(t *testing.T) {
+	v, err := validator.NewEmailValidatorWithResolver(NewMockResolver())
+	require.NoError(t, err)
+
+	assert.Equal(t, "kr", v.TypoRegion("ko"))
+	assert.Equal(t, "cn", v.TypoRegion("fr", "zh"))
+	assert.Equal(t, "de", v.TypoRegion("at", "DE"))
+	assert.Equal(t, "", v.TypoRegion("en", "us"))
+	assert.Equal(t, "", v.TypoRegion())
+}
+
+func TestTypoRegionsFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"ru-RU,ru;q=0.9,en;q=0.8", []string{"ru", "en"}},
+		{"en;q=0.5, ko-KR", []string{"kr", "ko", "en"}},
+		{"de-AT;q=0.9, zh", []string{"cn", "zh", "at", "de"}},
+		{"zh-Hant-TW", []string{"tw", "cn", "zh"}},
+		{"fr;q=0, *", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, validator.TypoRegionsFromAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestParseTypoDictionary(t *testing.T) {
+	input := `# Common misspellings in Poland
+wp.p,wp.pl
+ Onet.p , onet.pl
+
+`
+	corrections, err := validator.ParseTypoDictionary(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"wp.p": "wp.pl", "onet.p": "onet.pl"}, corrections)
+
+	for _, invalid := range []string{"wp.p", "wp.p,", "wp.p,not a domain", "*.wp.p,wp.pl"} {
+		_, err := validator.ParseTypoDictionary(strings.NewReader(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLoadTypoDictionaries(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "PL.csv"), []byte("wp.p,wp.pl\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "global.txt"), []byte("icloud.co,icloud.com\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a dictionary"), 0o644))
+
+	dictionaries, err := validator.LoadTypoDictionaries(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"pl": {"wp.p": "wp.pl"},
+		"":   {"icloud.co": "icloud.com"},
+	}, dictionaries)
+
+	v, err := validator.NewEmailValidatorWithResolver(NewMockResolver())
+	require.NoError(t, err)
+	v.SetTypoDictionaries(dictionaries)
+	assert.Equal(t, []string{"jan@wp.pl"}, v.GetTypoSuggestionsForRegion("jan@wp.p", v.TypoRegion("PL")))
+	assert.Equal(t, []string{"jan@icloud.com"}, v.GetTypoSuggestions("jan@icloud.co"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "de.csv"), []byte("gmx.d\n"), 0o644))
+	_, err = validator.LoadTypoDictionaries(dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package validatortest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func learnTimes(learner *validator.TypoLearner, domain string, n int) {
+	for i := 0; i < n; i++ {
+		learner.Learn(domain)
+	}
+}
+
+func TestTypoLearnerSuggest(t *testing.T) {
+	learner := validator.NewTypoLearner(validator.NewMemoryDomainFrequencyStore(), validator.DefaultTypoLearningHalfLife)
+	learnTimes(learner, "company-mail.de", 4)
+	learnTimes(learner, "acme.io", 10)
+	learnTimes(learner, "acne.io", 6)
+
+	assert.NoError(t, learner.Sync(context.Background()))
+	assert.Equal(t, "", learner.Suggest("company-mial.de"), "4 observations are below the threshold")
+
+	learner.Learn("Company-Mail.de.")
+	assert.Equal(t, "", learner.Suggest("company-mial.de"), "observations only count once synced")
+	assert.NoError(t, learner.Sync(context.Background()))
+
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{"company-mial.de", "company-mail.de"},
+		{"COMPANY-MAL.DE", "company-mail.de"},
+		{"company-mail.de", ""},
+		{"acxe.io", "acme.io"},
+		{"acne.io", ""},
+		{"acmeee.io", ""},
+		{"unrelated.org", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			assert.Equal(t, tt.want, learner.Suggest(tt.domain))
+		})
+	}
+}
+
+func TestTypoLearnerDecay(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	halfLife := 30 * 24 * time.Hour
+	learner := validator.NewTypoLearner(validator.NewMemoryDomainFrequencyStore(), halfLife)
+	learner.SetClock(func() time.Time { return now })
+
+	learnTimes(learner, "stale-mail.com", 8)
+	assert.NoError(t, learner.Sync(context.Background()))
+	assert.Equal(t, "stale-mail.com", learner.Suggest("stale-mial.com"))
+
+	// Two half-lives later the 8 observations count as 2
+	now = now.Add(2 * halfLife)
+	learnTimes(learner, "fresh-mail.com", 5)
+	assert.NoError(t, learner.Sync(context.Background()))
+	assert.Equal(t, "", learner.Suggest("stale-mial.com"))
+	assert.Equal(t, "fresh-mail.com", learner.Suggest("fresh-mial.com"))
+
+	// New observations bring the domain back
+	learnTimes(learner, "stale-mail.com", 3)
+	assert.NoError(t, learner.Sync(context.Background()))
+	assert.Equal(t, "stale-mail.com", learner.Suggest("stale-mial.com"))
+}
+
+// flakyFrequencyStore fails writes until healed
+type flakyFrequencyStore struct {
+	*validator.MemoryDomainFrequencyStore
+	broken bool
+}
+
+func (s *flakyFrequencyStore) IncrementDomains(ctx context.Context, increments map[string]float64) error {
+	if s.broken {
+		return errors.New("connection refused")
+	}
+	return s.MemoryDomainFrequencyStore.IncrementDomains(ctx, increments)
+}
+
+func TestTypoLearnerKeepsObservationsWhenStoreFails(t *testing.T) {
+	store := &flakyFrequencyStore{MemoryDomainFrequencyStore: validator.NewMemoryDomainFrequencyStore(), broken: true}
+	learner := validator.NewTypoLearner(store, validator.DefaultTypoLearningHalfLife)
+
+	learnTimes(learner, "company-mail.de", 5)
+	assert.Error(t, learner.Sync(context.Background()))
+
+	store.broken = false
+	assert.NoError(t, learner.Sync(context.Background()))
+	assert.Equal(t, "company-mail.de", learner.Suggest("company-mial.de"))
+}
+
+func TestEmailValidatorPrefersLearnedSuggestions(t *testing.T) {
+	v, err := validator.NewEmailValidator()
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	learner := validator.NewTypoLearner(validator.NewMemoryDomainFrequencyStore(), validator.DefaultTypoLearningHalfLife)
+	learnTimes(learner, "gmail.com", 5)
+	learnTimes(learner, "hotmail.de", 5)
+	assert.NoError(t, learner.Sync(context.Background()))
+
+	// Without the learner only the built-in corrections apply
+	assert.Empty(t, v.GetTypoSuggestions("user@hotmial.de"))
+
+	v.SetTypoLearner(learner)
+	assert.Equal(t, []string{"user@hotmail.de"}, v.GetTypoSuggestions("user@hotmial.de"))
+	assert.Equal(t, []string{"user@gmail.com"}, v.GetTypoSuggestions("user@gmial.com"), "a matching built-in correction is not repeated")
+	assert.Empty(t, v.GetTypoSuggestions("user@hotmail.de"))
+}
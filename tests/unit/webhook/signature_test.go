@@ -0,0 +1,64 @@
+package webhooktest
+
+import (
+	"testing"
+	"time"
+
+	"emailvalidator/pkg/webhook"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	secret   = []byte("shared-secret")
+	payload  = []byte(`{"results":[{"email":"user@example.com","status":"VALID"}]}`)
+	signedAt = time.Unix(1700000000, 0)
+)
+
+func TestSignKnownValue(t *testing.T) {
+	// hex(HMAC-SHA256("key", "1700000000.body")), computed independently
+	header := webhook.Sign([]byte("key"), signedAt, []byte("body"))
+	assert.Equal(t, "t=1700000000,v1=47b6ce0fca59474308e2921c247cb2493dce6b8101d90ac05bd0c6a37d0e046e", header)
+}
+
+func TestVerifyAcceptsSignedPayload(t *testing.T) {
+	header := webhook.Sign(secret, signedAt, payload)
+	assert.NoError(t, webhook.Verify(secret, header, payload, signedAt.Add(time.Minute), webhook.DefaultTolerance))
+}
+
+func TestVerifyRejects(t *testing.T) {
+	header := webhook.Sign(secret, signedAt, payload)
+
+	tests := []struct {
+		name    string
+		secret  []byte
+		header  string
+		payload []byte
+		now     time.Time
+		want    error
+	}{
+		{name: "Missing header", secret: secret, header: "", payload: payload, now: signedAt, want: webhook.ErrMissingSignature},
+		{name: "Wrong secret", secret: []byte("other"), header: header, payload: payload, now: signedAt, want: webhook.ErrInvalidSignature},
+		{name: "Tampered payload", secret: secret, header: header, payload: []byte(`{"results":[]}`), now: signedAt, want: webhook.ErrInvalidSignature},
+		{name: "Expired", secret: secret, header: header, payload: payload, now: signedAt.Add(time.Hour), want: webhook.ErrSignatureExpired},
+		{name: "From the future", secret: secret, header: header, payload: payload, now: signedAt.Add(-time.Hour), want: webhook.ErrSignatureExpired},
+		{name: "No timestamp", secret: secret, header: "v1=abcd", payload: payload, now: signedAt, want: webhook.ErrInvalidSignature},
+		{name: "Malformed", secret: secret, header: "garbage", payload: payload, now: signedAt, want: webhook.ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webhook.Verify(tt.secret, tt.header, tt.payload, tt.now, webhook.DefaultTolerance)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestVerifyAcceptsAnyOfSeveralSignatures(t *testing.T) {
+	old := webhook.Sign([]byte("old-secret"), signedAt, payload)
+	current := webhook.Sign(secret, signedAt, payload)
+	// During a rotation the sender signs with both secrets
+	header := old + ",v1=" + current[len("t=1700000000,v1="):]
+
+	assert.NoError(t, webhook.Verify(secret, header, payload, signedAt, webhook.DefaultTolerance))
+	assert.NoError(t, webhook.Verify([]byte("old-secret"), header, payload, signedAt, webhook.DefaultTolerance))
+}